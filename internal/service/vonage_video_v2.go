@@ -1,6 +1,9 @@
 package service
 
 import (
+	"context"
+	"errors"
+
 	"github.com/rs/zerolog/log"
 
 	"github.com/vonatrigger/poc/internal/config"
@@ -18,6 +21,9 @@ type VonageVideoServiceV2 struct {
 
 // NewVonageVideoServiceV2 creates a new video service using the SDK
 func NewVonageVideoServiceV2(cfg *config.Config, secrets VonageVideoSecrets) (*VonageVideoServiceV2, error) {
+	// Route SDK logs to the global zerolog logger
+	sdkLogger := vonage.NewZerologLogger(log.Logger)
+
 	// Create credentials using the SDK
 	creds, err := vonage.NewCredentials(
 		vonage.WithApplication(secrets.AppID, secrets.PrivateKey),
@@ -26,26 +32,26 @@ func NewVonageVideoServiceV2(cfg *config.Config, secrets VonageVideoSecrets) (*V
 		log.Warn().Err(err).Msg("Failed to create Vonage credentials, will use mock mode")
 		// Return service in mock mode
 		return &VonageVideoServiceV2{
-			client:   video.NewClient(secrets.AppID, nil),
-			tokenGen: video.NewTokenGenerator(secrets.AppID, nil),
+			client:   video.NewClient(secrets.AppID, nil, video.WithMockFallback(), video.WithLogger(sdkLogger)),
+			tokenGen: video.NewTokenGenerator(secrets.AppID, nil, video.WithTokenLogger(sdkLogger)),
 			appID:    secrets.AppID,
 		}, nil
 	}
 
 	// Create the SDK client
-	client, err := video.NewClientFromCredentials(creds)
+	client, err := video.NewClientFromCredentials(creds, video.WithMockFallback(), video.WithLogger(sdkLogger))
 	if err != nil {
 		log.Warn().Err(err).Msg("Failed to create Video client, will use mock mode")
 		return &VonageVideoServiceV2{
-			client:   video.NewClient(secrets.AppID, nil),
-			tokenGen: video.NewTokenGenerator(secrets.AppID, nil),
+			client:   video.NewClient(secrets.AppID, nil, video.WithMockFallback(), video.WithLogger(sdkLogger)),
+			tokenGen: video.NewTokenGenerator(secrets.AppID, nil, video.WithTokenLogger(sdkLogger)),
 			appID:    secrets.AppID,
 		}, nil
 	}
 
 	// Create token generator
-	jwtGen := vonage.NewJWTGenerator(creds.AppID, creds.PrivateKey)
-	tokenGen := video.NewTokenGenerator(creds.AppID, jwtGen)
+	jwtGen := vonage.NewJWTGeneratorFromCredentials(creds)
+	tokenGen := video.NewTokenGenerator(creds.AppID, jwtGen, video.WithTokenLogger(sdkLogger))
 
 	log.Info().
 		Str("appID", secrets.AppID).
@@ -66,8 +72,8 @@ func (s *VonageVideoServiceV2) IsConfigured() bool {
 // CreateSession creates a new video session via Vonage Video API
 // Backward compatible with the old interface
 func (s *VonageVideoServiceV2) CreateSession(spotID string) (*VideoSession, error) {
-	session, err := s.client.CreateSessionForSpot(spotID, nil)
-	if err != nil {
+	session, err := s.client.CreateSessionForSpot(context.Background(), spotID, nil)
+	if err != nil && !errors.Is(err, video.ErrMockSession) {
 		return nil, err
 	}
 
@@ -82,8 +88,8 @@ func (s *VonageVideoServiceV2) CreateSession(spotID string) (*VideoSession, erro
 
 // GetSession retrieves an existing session
 func (s *VonageVideoServiceV2) GetSession(sessionID string) (*VideoSession, error) {
-	session, err := s.client.GetSession(sessionID)
-	if err != nil {
+	session, err := s.client.GetSession(context.Background(), sessionID)
+	if err != nil && !errors.Is(err, video.ErrMockSession) {
 		return nil, err
 	}
 
@@ -97,8 +103,8 @@ func (s *VonageVideoServiceV2) GetSession(sessionID string) (*VideoSession, erro
 
 // GetOrCreateSessionForSpot gets existing session or creates a new one for a spot
 func (s *VonageVideoServiceV2) GetOrCreateSessionForSpot(spotID string) (*VideoSession, error) {
-	session, err := s.client.GetOrCreateSession(spotID, nil)
-	if err != nil {
+	session, err := s.client.GetOrCreateSession(context.Background(), spotID, nil)
+	if err != nil && !errors.Is(err, video.ErrMockSession) {
 		return nil, err
 	}
 
@@ -142,7 +148,7 @@ func (s *VonageVideoServiceV2) GenerateToken(sessionID, userID, role string) (*V
 
 // CleanupExpiredSessions removes expired sessions
 func (s *VonageVideoServiceV2) CleanupExpiredSessions() int {
-	return s.client.CleanupExpiredSessions()
+	return s.client.CleanupExpiredSessions(context.Background())
 }
 
 // Client returns the underlying SDK client for advanced usage
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/rs/zerolog/log"
 
@@ -17,7 +18,8 @@ import (
 type VonageServiceV2 struct {
 	voiceClient *voice.Client
 	phoneNumber string
-	webhookBase string
+	webhookBase vonage.WebhookBaseResolver
+	urls        *voice.URLBuilder
 }
 
 // NewVonageServiceV2 creates a new Vonage voice service using the SDK
@@ -31,7 +33,9 @@ func NewVonageServiceV2(cfg *config.Config, secrets VonageSecrets) (*VonageServi
 		return nil, fmt.Errorf("failed to create credentials: %w", err)
 	}
 
-	voiceClient, err := voice.NewClientFromCredentials(creds)
+	voiceClient, err := voice.NewClientFromCredentials(creds,
+		voice.WithLogger(vonage.NewZerologLogger(log.Logger)),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create voice client: %w", err)
 	}
@@ -44,21 +48,52 @@ func NewVonageServiceV2(cfg *config.Config, secrets VonageSecrets) (*VonageServi
 	return &VonageServiceV2{
 		voiceClient: voiceClient,
 		phoneNumber: secrets.PhoneNumber,
-		webhookBase: cfg.WebhookBaseURL,
+		webhookBase: vonage.StaticWebhookBase(cfg.WebhookBaseURL),
+		urls:        voice.NewURLBuilder(""),
 	}, nil
 }
 
+// SetWebhookBaseResolver replaces the configured webhook base URL, e.g.
+// with vonage.NgrokWebhookBase during local development
+func (s *VonageServiceV2) SetWebhookBaseResolver(r vonage.WebhookBaseResolver) {
+	s.webhookBase = r
+}
+
+// SetURLBuilder replaces the builder used for webhook URLs, e.g. with one
+// that signs parameters. Its base URL is replaced by the resolved one.
+func (s *VonageServiceV2) SetURLBuilder(b *voice.URLBuilder) {
+	s.urls = b
+}
+
+// InputEventURL returns the input webhook URL of a conversation
+func (s *VonageServiceV2) InputEventURL(ctx context.Context, conversationID string) (string, error) {
+	base, err := s.webhookBase.WebhookBase(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve input URL: %w", err)
+	}
+	return s.urls.WithBase(base).InputURL(conversationID), nil
+}
+
 // ========================================
 // Voice API (backward compatible)
 // ========================================
 
 // CreateCall initiates a call (backward compatible with existing VonageService)
 func (s *VonageServiceV2) CreateCall(ctx context.Context, toNumber, answerPath, eventPath string) (*CreateCallResponse, error) {
+	answerURL, err := vonage.WebhookURL(ctx, s.webhookBase, answerPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve answer URL: %w", err)
+	}
+	eventURL, err := vonage.WebhookURL(ctx, s.webhookBase, eventPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve event URL: %w", err)
+	}
+
 	resp, err := s.voiceClient.CreateCall(ctx, voice.CreateCallOptions{
 		To:           voice.PhoneEndpoint(toNumber),
-		AnswerURL:    s.webhookBase + answerPath,
+		AnswerURL:    answerURL,
 		AnswerMethod: "POST",
-		EventURL:     s.webhookBase + eventPath,
+		EventURL:     eventURL,
 		EventMethod:  "POST",
 	})
 	if err != nil {
@@ -108,9 +143,9 @@ func (s *VonageServiceV2) GetCallInfo(ctx context.Context, callUUID string) (*Ca
 		UUID:             info.UUID,
 		Status:           string(info.Status),
 		Direction:        string(info.Direction),
-		Rate:             info.Rate,
-		Price:            info.Price,
-		Duration:         info.Duration,
+		Rate:             info.Rate.String(),
+		Price:            info.Price.String(),
+		Duration:         strconv.Itoa(int(info.Duration.Seconds())),
 		StartTime:        info.StartTime,
 		EndTime:          info.EndTime,
 		ConversationUUID: info.ConversationUUID,
@@ -145,15 +180,17 @@ func sdkNCCOToModel(sdkNCCO voice.NCCO) model.NCCO {
 			Text:         action.Text,
 			VoiceName:    action.VoiceName,
 			Language:     action.Language,
-			Style:        action.Style,
 			EventURL:     action.EventURL,
-			EventMethod:  action.EventMethod,
+			EventMethod:  string(action.EventMethod),
 			Type:         action.Type,
 			EndOnSilence: action.EndOnSilence,
 			StartTimeout: action.StartTimeout,
 			MaxDuration:  action.MaxDuration,
 			StreamURL:    action.StreamURL,
 		}
+		if action.Style != nil {
+			modelAction.Style = *action.Style
+		}
 		ncco = append(ncco, modelAction)
 	}
 
@@ -171,15 +208,19 @@ func ModelNCCOToSDK(modelNCCO model.NCCO) voice.NCCO {
 			Text:         action.Text,
 			VoiceName:    action.VoiceName,
 			Language:     action.Language,
-			Style:        action.Style,
 			EventURL:     action.EventURL,
-			EventMethod:  action.EventMethod,
+			EventMethod:  voice.HTTPMethod(action.EventMethod),
 			Type:         action.Type,
 			EndOnSilence: action.EndOnSilence,
 			StartTimeout: action.StartTimeout,
 			MaxDuration:  action.MaxDuration,
 			StreamURL:    action.StreamURL,
 		}
+		// The model cannot tell style 0 from unset; keep the voice default
+		if action.Style != 0 {
+			style := action.Style
+			sdkAction.Style = &style
+		}
 		ncco = append(ncco, sdkAction)
 	}
 
@@ -0,0 +1,24 @@
+package account
+
+import (
+	"context"
+)
+
+// ========================================
+// API Interface
+// ========================================
+
+// API is the Account API implemented by *Client. Application code can
+// depend on it and substitute vonagemock.Account in tests.
+type API interface {
+	GetBalance(ctx context.Context) (*Balance, error)
+	TopUp(ctx context.Context, transactionID string) error
+	GetSettings(ctx context.Context) (*Settings, error)
+	UpdateSettings(ctx context.Context, update *SettingsUpdate) (*Settings, error)
+	ListSecrets(ctx context.Context) ([]Secret, error)
+	GetSecret(ctx context.Context, secretID string) (*Secret, error)
+	CreateSecret(ctx context.Context, secret string) (*Secret, error)
+	RevokeSecret(ctx context.Context, secretID string) error
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,312 @@
+// Package account reads the Vonage account balance and settings, triggers
+// auto-reload top-ups and manages API secrets.
+package account
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+const (
+	// BaseURL is the base URL of the balance, top-up and settings endpoints
+	BaseURL = vonage.BaseURLLegacy
+
+	// APIBaseURL is the base URL of the secrets endpoints
+	APIBaseURL = vonage.BaseURLREST
+)
+
+// ErrNotConfigured is returned when the client has no API key and secret
+var ErrNotConfigured = errors.New("account: API key and secret required")
+
+// Client handles Vonage Account API operations. The API authenticates with
+// the account API key and secret.
+type Client struct {
+	apiKey    string
+	apiSecret string
+	provider  vonage.CredentialsProvider
+
+	baseURL    string
+	apiBaseURL string
+	httpClient *http.Client
+	middleware []vonage.Middleware
+	logger     vonage.Logger
+	uaSuffix   string
+	mode       vonage.Mode
+
+	// rest carries the key and secret in the query; api uses Basic
+	// authentication
+	rest *vonage.Transport
+	api  *vonage.Transport
+}
+
+// ClientOption is a functional option for configuring the account client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the balance and settings base URL (useful for
+// testing)
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithAPIBaseURL overrides the secrets base URL (useful for testing)
+func WithAPIBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.apiBaseURL = url
+	}
+}
+
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l vonage.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier such as
+// "checkin-service/1.4" to the User-Agent
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.uaSuffix = suffix
+	}
+}
+
+// WithMiddleware adds transport middleware (retries, tracing, metrics)
+func WithMiddleware(mw ...vonage.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithMode sets the client's mode (default vonage.ModeLive). In
+// vonage.ModeMock no request is sent, and in vonage.ModeDryRun only
+// reads are.
+func WithMode(m vonage.Mode) ClientOption {
+	return func(c *Client) {
+		c.mode = m
+	}
+}
+
+// WithSettings applies the HTTP client, logger, middleware, User-Agent
+// suffix and mode shared by a root vonage.Client, see
+// vonage.Client.Settings
+func WithSettings(s vonage.Settings) ClientOption {
+	return func(c *Client) {
+		if s.HTTPClient != nil {
+			c.httpClient = s.HTTPClient
+		}
+		if s.Logger != nil {
+			c.logger = s.Logger
+		}
+		c.middleware = append(c.middleware, s.Middleware...)
+		c.uaSuffix = s.UserAgentSuffix
+		c.mode = s.Mode
+	}
+}
+
+// WithCredentialsProvider fetches the API key and secret from p for every
+// request instead of using those passed to NewClient, so rotated secrets
+// are picked up. The secrets paths keep the API key passed to NewClient.
+func WithCredentialsProvider(p vonage.CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.provider = p
+	}
+}
+
+// NewClient creates a new Vonage Account API client
+func NewClient(apiKey, apiSecret string, opts ...ClientOption) *Client {
+	c := &Client{
+		apiKey:     apiKey,
+		apiSecret:  apiSecret,
+		baseURL:    BaseURL,
+		apiBaseURL: APIBaseURL,
+		httpClient: &http.Client{Timeout: vonage.DefaultTimeout},
+		logger:     vonage.NopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	transportOpts := []vonage.TransportOption{
+		vonage.WithTransportHTTPClient(c.httpClient),
+		vonage.WithMiddleware(c.middleware...),
+		vonage.WithTransportLogger(c.logger),
+		vonage.WithTransportUserAgentSuffix(c.uaSuffix),
+		vonage.WithTransportMode(c.mode),
+	}
+	provider := c.provider
+	if provider == nil {
+		provider = &vonage.Credentials{APIKey: apiKey, APISecret: apiSecret}
+	}
+	c.rest = vonage.NewTransport(c.baseURL, vonage.NewAuthChain(provider, vonage.AuthQuery), transportOpts...)
+	c.api = vonage.NewTransport(c.apiBaseURL, vonage.NewAuthChain(provider, vonage.AuthBasic), transportOpts...)
+
+	return c
+}
+
+// NewClientFromCredentials creates a new client from Vonage credentials
+func NewClientFromCredentials(creds *vonage.Credentials, opts ...ClientOption) (*Client, error) {
+	if !creds.HasAPIKey() {
+		return nil, ErrNotConfigured
+	}
+	return NewClient(creds.APIKey, creds.APISecret, append([]ClientOption{WithMode(creds.Mode)}, opts...)...), nil
+}
+
+// IsConfigured returns true if the client has an API key and secret or a
+// credentials provider
+func (c *Client) IsConfigured() bool {
+	return c.provider != nil || (c.apiKey != "" && c.apiSecret != "")
+}
+
+// ========================================
+// Balance and Settings
+// ========================================
+
+// GetBalance returns the remaining credit and auto-reload status
+func (c *Client) GetBalance(ctx context.Context) (*Balance, error) {
+	if !c.IsConfigured() {
+		return nil, ErrNotConfigured
+	}
+
+	var balance Balance
+	if err := c.rest.Do(ctx, http.MethodGet, "/account/get-balance", nil, &balance); err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// TopUp reloads the balance with the amount of the auto-reload payment
+// identified by transactionID. Auto-reload must be enabled on the account.
+func (c *Client) TopUp(ctx context.Context, transactionID string) error {
+	if !c.IsConfigured() {
+		return ErrNotConfigured
+	}
+
+	form := url.Values{}
+	form.Set("trx", transactionID)
+
+	var resp response
+	if err := c.rest.Do(ctx, http.MethodPost, "/account/top-up", form, &resp); err != nil {
+		return err
+	}
+	if err := resp.err(); err != nil {
+		return err
+	}
+
+	c.logger.Info("Topped up Vonage account", "transactionID", transactionID)
+	return nil
+}
+
+// GetSettings returns the current account settings
+func (c *Client) GetSettings(ctx context.Context) (*Settings, error) {
+	return c.UpdateSettings(ctx, nil)
+}
+
+// UpdateSettings changes the account webhook URLs and returns the
+// resulting settings. A nil update only reads them.
+func (c *Client) UpdateSettings(ctx context.Context, update *SettingsUpdate) (*Settings, error) {
+	if !c.IsConfigured() {
+		return nil, ErrNotConfigured
+	}
+
+	form := url.Values{}
+	if update != nil {
+		if update.MOCallbackURL != "" {
+			form.Set("moCallBackUrl", update.MOCallbackURL)
+		}
+		if update.DRCallbackURL != "" {
+			form.Set("drCallBackUrl", update.DRCallbackURL)
+		}
+	}
+
+	var settings Settings
+	if err := c.rest.Do(ctx, http.MethodPost, "/account/settings", form, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// ========================================
+// Secrets
+// ========================================
+
+// ListSecrets returns the API secrets of the account (at most two)
+func (c *Client) ListSecrets(ctx context.Context) ([]Secret, error) {
+	var list secretList
+	if err := c.api.Do(ctx, http.MethodGet, c.secretsPath(""), nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Embedded.Secrets, nil
+}
+
+// GetSecret returns a single API secret's metadata
+func (c *Client) GetSecret(ctx context.Context, secretID string) (*Secret, error) {
+	var secret Secret
+	if err := c.api.Do(ctx, http.MethodGet, c.secretsPath(secretID), nil, &secret); err != nil {
+		return nil, err
+	}
+	return &secret, nil
+}
+
+// CreateSecret adds a new API secret. An account has at most two, so
+// rotation is: create the new secret, deploy it, then RevokeSecret the old.
+func (c *Client) CreateSecret(ctx context.Context, secret string) (*Secret, error) {
+	req := struct {
+		Secret string `json:"secret"`
+	}{Secret: secret}
+
+	var created Secret
+	if err := c.api.Do(ctx, http.MethodPost, c.secretsPath(""), req, &created); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Created Vonage API secret", "secretID", created.ID)
+	return &created, nil
+}
+
+// RevokeSecret deletes an API secret. The secret used by this client
+// cannot revoke itself.
+func (c *Client) RevokeSecret(ctx context.Context, secretID string) error {
+	if err := c.api.Do(ctx, http.MethodDelete, c.secretsPath(secretID), nil, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Revoked Vonage API secret", "secretID", secretID)
+	return nil
+}
+
+// secretsPath returns the path of the secrets collection or one secret
+func (c *Client) secretsPath(secretID string) string {
+	path := "/accounts/" + url.PathEscape(c.apiKey) + "/secrets"
+	if secretID != "" {
+		path += "/" + url.PathEscape(secretID)
+	}
+	return path
+}
+
+// ========================================
+// Health
+// ========================================
+
+// Ping checks the credentials and connectivity for readiness probes; it
+// reads the balance
+func (c *Client) Ping(ctx context.Context) *vonage.Health {
+	if !c.IsConfigured() {
+		return &vonage.Health{Service: "account", CheckedAt: time.Now(), Err: ErrNotConfigured}
+	}
+	return c.rest.Ping(ctx, "account", "/account/get-balance")
+}
@@ -0,0 +1,52 @@
+package account_test
+
+import (
+	"context"
+	"fmt"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/account"
+)
+
+func ExampleClient_GetBalance() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithAPIKey("api-key", "api-secret"),
+	)
+	client, _ := account.NewClientFromCredentials(creds)
+
+	balance, err := client.GetBalance(context.Background())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("Remaining credit: EUR %.2f (auto-reload: %v)\n", balance.Value, balance.AutoReload)
+}
+
+func ExampleClient_CreateSecret() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithAPIKey("api-key", "old-secret"),
+	)
+	client, _ := account.NewClientFromCredentials(creds)
+
+	ctx := context.Background()
+	existing, err := client.ListSecrets(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// 1. Add the new secret alongside the current one
+	if _, err := client.CreateSecret(ctx, "N3w-Secret-Value"); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// 2. Deploy the new secret to every service, then 3. revoke the old
+	// one with a client that authenticates with the new secret
+	rotated := account.NewClient("api-key", "N3w-Secret-Value")
+	for _, s := range existing {
+		if err := rotated.RevokeSecret(ctx, s.ID); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
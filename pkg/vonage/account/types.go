@@ -0,0 +1,69 @@
+package account
+
+import (
+	"fmt"
+	"time"
+)
+
+// Balance is the account's remaining credit
+type Balance struct {
+	// Value is the balance in EUR
+	Value float64 `json:"value"`
+	// AutoReload is true if the account tops up automatically
+	AutoReload bool `json:"autoReload"`
+}
+
+// Settings are the account-level webhook URLs and rate limits
+type Settings struct {
+	MOCallbackURL      string  `json:"mo-callback-url"`
+	DRCallbackURL      string  `json:"dr-callback-url"`
+	MaxOutboundRequest float64 `json:"max-outbound-request"`
+	MaxInboundRequest  float64 `json:"max-inbound-request"`
+	MaxCallsPerSecond  float64 `json:"max-calls-per-second"`
+}
+
+// SettingsUpdate changes the account webhook URLs. Empty fields are left
+// unchanged.
+type SettingsUpdate struct {
+	// MOCallbackURL receives inbound SMS
+	MOCallbackURL string
+	// DRCallbackURL receives SMS delivery receipts
+	DRCallbackURL string
+}
+
+// Secret is an API secret. The secret value itself is never returned.
+type Secret struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// secretList is the HAL body of the list secrets endpoint
+type secretList struct {
+	Embedded struct {
+		Secrets []Secret `json:"secrets"`
+	} `json:"_embedded"`
+}
+
+// Error is an Account API error reported in the response body
+type Error struct {
+	Code  string
+	Label string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("account: error %s - %s", e.Code, e.Label)
+}
+
+// response is the body returned by top-up
+type response struct {
+	ErrorCode      string `json:"error-code"`
+	ErrorCodeLabel string `json:"error-code-label"`
+}
+
+// err converts a non-200 error-code into an *Error
+func (r *response) err() error {
+	if r.ErrorCode == "" || r.ErrorCode == "200" {
+		return nil
+	}
+	return &Error{Code: r.ErrorCode, Label: r.ErrorCodeLabel}
+}
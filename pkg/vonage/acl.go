@@ -0,0 +1,97 @@
+package vonage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ========================================
+// Client SDK JWTs
+// ========================================
+
+const (
+	// DefaultUserJWTTTL is used by GenerateUserJWT when ttl is zero
+	DefaultUserJWTTTL = time.Hour
+
+	// MaxUserJWTTTL is the longest lifetime the Client SDKs accept
+	MaxUserJWTTTL = 24 * time.Hour
+)
+
+// ErrUsernameRequired is returned by GenerateUserJWT without a username
+var ErrUsernameRequired = errors.New("vonage: username is required for a user JWT")
+
+// ACLPath restricts access to one ACL path. The zero value allows all
+// methods.
+type ACLPath struct {
+	Methods []string               `json:"methods,omitempty"`
+	Filters map[string]interface{} `json:"filters,omitempty"`
+}
+
+// ACL is the "acl" claim of a Client SDK JWT, mapping API paths such as
+// "/*/conversations/**" to their restrictions
+type ACL map[string]ACLPath
+
+// MarshalJSON encodes the ACL as {"paths": {...}}
+func (a ACL) MarshalJSON() ([]byte, error) {
+	paths := map[string]ACLPath(a)
+	if paths == nil {
+		paths = map[string]ACLPath{}
+	}
+	return json.Marshal(struct {
+		Paths map[string]ACLPath `json:"paths"`
+	}{Paths: paths})
+}
+
+// NewACL returns an ACL allowing all methods on the given paths
+func NewACL(paths ...string) ACL {
+	acl := make(ACL, len(paths))
+	for _, p := range paths {
+		acl[p] = ACLPath{}
+	}
+	return acl
+}
+
+// VoiceOnlyACL returns the paths needed for in-app voice calls
+func VoiceOnlyACL() ACL {
+	return NewACL(
+		"/*/users/**",
+		"/*/conversations/**",
+		"/*/sessions/**",
+		"/*/devices/**",
+		"/*/applications/**",
+		"/*/push/**",
+		"/*/knocking/**",
+		"/*/legs/**",
+	)
+}
+
+// FullACL returns the standard paths for in-app voice and chat, including
+// image and media uploads
+func FullACL() ACL {
+	acl := VoiceOnlyACL()
+	acl["/*/image/**"] = ACLPath{}
+	acl["/*/media/**"] = ACLPath{}
+	return acl
+}
+
+// GenerateUserJWT generates a Client SDK JWT for username (the "sub"
+// claim, which must match a provisioned user) with the given ACL. A zero
+// ttl uses DefaultUserJWTTTL.
+func (g *JWTGenerator) GenerateUserJWT(username string, acl ACL, ttl time.Duration) (string, error) {
+	if username == "" {
+		return "", ErrUsernameRequired
+	}
+	if ttl == 0 {
+		ttl = DefaultUserJWTTTL
+	}
+	if ttl < 0 || ttl > MaxUserJWTTTL {
+		return "", fmt.Errorf("vonage: user JWT ttl %s must be between 0 and %s", ttl, MaxUserJWTTTL)
+	}
+
+	return g.GenerateJWT(ttl, JWTClaims{
+		"sub": username,
+		"acl": acl,
+	})
+}
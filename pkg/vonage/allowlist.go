@@ -0,0 +1,84 @@
+package vonage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ========================================
+// Destination Allowlist
+// ========================================
+
+// ErrDestinationBlocked is matched by errors for sends and calls to
+// destinations outside an allowlist
+var ErrDestinationBlocked = errors.New("vonage: destination not in allowlist")
+
+// DestinationAllowlist restricts the numbers clients may send to or call
+// outside production (see Mode.IsProduction). Configure it with the
+// clients' WithAllowedDestinations option.
+type DestinationAllowlist struct {
+	patterns []string
+}
+
+// NewDestinationAllowlist creates an allowlist. A pattern is a number,
+// matched exactly, or a prefix ending in "*", e.g. "+1 555 01*". Phone
+// numbers are compared by their digits only, so "+81 90-1234-5678"
+// matches "819012345678". Other destinations, such as SIP URIs, are
+// compared as given.
+func NewDestinationAllowlist(patterns ...string) *DestinationAllowlist {
+	a := &DestinationAllowlist{}
+	for _, p := range patterns {
+		prefix, wildcard := strings.CutSuffix(strings.TrimSpace(p), "*")
+		if prefix = normalizeDestination(prefix); prefix == "" && !wildcard {
+			continue
+		}
+		if wildcard {
+			prefix += "*"
+		}
+		a.patterns = append(a.patterns, prefix)
+	}
+	return a
+}
+
+// Allows reports whether destination matches a pattern
+func (a *DestinationAllowlist) Allows(destination string) bool {
+	d := normalizeDestination(destination)
+	for _, p := range a.patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(d, prefix) {
+				return true
+			}
+		} else if d == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns an error wrapping ErrDestinationBlocked if mode is not
+// production and destination is not allowed. A nil allowlist allows
+// everything.
+func (a *DestinationAllowlist) Check(mode Mode, destination string) error {
+	if a == nil || mode.IsProduction() || a.Allows(destination) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s (mode %s)", ErrDestinationBlocked, destination, mode)
+}
+
+// normalizeDestination reduces phone numbers to their digits and leaves
+// other destinations unchanged
+func normalizeDestination(s string) string {
+	s = strings.TrimSpace(s)
+	digits := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c >= '0' && c <= '9':
+			digits = append(digits, c)
+		case strings.IndexByte("+-. ()", c) >= 0:
+		default:
+			return s
+		}
+	}
+	return string(digits)
+}
@@ -1,11 +1,15 @@
 package vonage
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rsa"
-	"crypto/x509"
-	"encoding/pem"
 	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -17,8 +21,30 @@ type Credentials struct {
 	APIKey      string
 	APISecret   string
 	AppID       string
-	PrivateKey  *rsa.PrivateKey
 	PhoneNumber string
+
+	// PrivateKey signs application JWTs: an *rsa.PrivateKey or, for
+	// ES256, an *ecdsa.PrivateKey on P-256
+	PrivateKey crypto.Signer
+	// Algorithm is the JWT signing algorithm; empty selects it from the key
+	Algorithm JWTAlgorithm
+
+	// ClockSkew is subtracted from iat/nbf of generated JWTs to tolerate
+	// clocks running ahead of Vonage's
+	ClockSkew time.Duration
+
+	// SignatureSecret and SignatureMethod verify signed webhooks and sign
+	// legacy SMS requests
+	SignatureSecret string
+	SignatureMethod SignatureMethod
+
+	// Mode is the mode of clients created from the credentials
+	Mode Mode
+
+	// passphrase decrypts encrypted private keys; pendingKey holds an
+	// encrypted key read before the passphrase was set
+	passphrase []byte
+	pendingKey string
 }
 
 // CredentialsOption is a functional option for configuring credentials
@@ -38,24 +64,96 @@ func WithApplication(appID, privateKeyPEM string) CredentialsOption {
 	return func(c *Credentials) error {
 		c.AppID = appID
 		if privateKeyPEM != "" {
-			key, err := ParseRSAPrivateKey(privateKeyPEM)
-			if err != nil {
+			if err := c.setKeyPEM(privateKeyPEM); err != nil {
 				return fmt.Errorf("failed to parse private key: %w", err)
 			}
-			c.PrivateKey = key
 		}
 		return nil
 	}
 }
 
-// WithPrivateKey sets the private key directly
-func WithPrivateKey(key *rsa.PrivateKey) CredentialsOption {
+// WithPrivateKeyFile reads a PEM encoded private key from path
+func WithPrivateKeyFile(path string) CredentialsOption {
+	return func(c *Credentials) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read private key file: %w", err)
+		}
+		if err := c.setKeyPEM(string(data)); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	}
+}
+
+// WithPrivateKeyPassphrase decrypts an encrypted private key given with
+// WithApplication or WithPrivateKeyFile, before or after this option. Keys
+// reloaded by RotatingCredentials.WatchKeyFile use it too.
+func WithPrivateKeyPassphrase(passphrase string) CredentialsOption {
+	return func(c *Credentials) error {
+		c.passphrase = []byte(passphrase)
+		if c.pendingKey == "" {
+			return nil
+		}
+		pending := c.pendingKey
+		c.pendingKey = ""
+		if err := c.setKeyPEM(pending); err != nil {
+			return fmt.Errorf("failed to parse private key: %w", err)
+		}
+		return nil
+	}
+}
+
+// setKeyPEM parses and sets the private key. An encrypted key read
+// without a passphrase is kept until WithPrivateKeyPassphrase.
+func (c *Credentials) setKeyPEM(pemStr string) error {
+	key, err := ParseEncryptedPrivateKey(pemStr, c.passphrase)
+	if errors.Is(err, ErrEncryptedKey) && len(c.passphrase) == 0 {
+		c.pendingKey = pemStr
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.PrivateKey = key
+	c.pendingKey = ""
+	return nil
+}
+
+// WithPrivateKey sets the private key directly: an *rsa.PrivateKey or an
+// *ecdsa.PrivateKey
+func WithPrivateKey(key crypto.Signer) CredentialsOption {
 	return func(c *Credentials) error {
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			if k == nil {
+				key = nil
+			}
+		case *ecdsa.PrivateKey:
+			if k == nil {
+				key = nil
+			}
+		case nil:
+		default:
+			return fmt.Errorf("%w: unsupported key type %T", ErrInvalidPrivateKey, key)
+		}
 		c.PrivateKey = key
 		return nil
 	}
 }
 
+// WithJWTAlgorithm sets the JWT signing algorithm (default: RS256 for RSA
+// keys, ES256 for EC keys)
+func WithJWTAlgorithm(alg JWTAlgorithm) CredentialsOption {
+	return func(c *Credentials) error {
+		if err := alg.validate(); err != nil {
+			return err
+		}
+		c.Algorithm = alg
+		return nil
+	}
+}
+
 // WithPhoneNumber sets the phone number for outbound calls/SMS
 func WithPhoneNumber(number string) CredentialsOption {
 	return func(c *Credentials) error {
@@ -64,6 +162,15 @@ func WithPhoneNumber(number string) CredentialsOption {
 	}
 }
 
+// WithClockSkew backdates the iat and nbf claims of generated JWTs by the
+// given duration, avoiding "token not yet valid" errors on fast clocks
+func WithClockSkew(skew time.Duration) CredentialsOption {
+	return func(c *Credentials) error {
+		c.ClockSkew = skew
+		return nil
+	}
+}
+
 // NewCredentials creates new credentials with the given options
 func NewCredentials(opts ...CredentialsOption) (*Credentials, error) {
 	c := &Credentials{}
@@ -72,9 +179,76 @@ func NewCredentials(opts ...CredentialsOption) (*Credentials, error) {
 			return nil, err
 		}
 	}
+	if c.pendingKey != "" {
+		return nil, fmt.Errorf("failed to parse private key: %w, a passphrase is required (WithPrivateKeyPassphrase)", ErrEncryptedKey)
+	}
 	return c, nil
 }
 
+// Environment variables read by NewCredentialsFromEnv
+const (
+	EnvApplicationID  = "VONAGE_APPLICATION_ID"
+	EnvPrivateKey     = "VONAGE_PRIVATE_KEY"
+	EnvPrivateKeyPath = "VONAGE_PRIVATE_KEY_PATH"
+	EnvKeyPassphrase  = "VONAGE_PRIVATE_KEY_PASSPHRASE"
+	EnvAPIKey         = "VONAGE_API_KEY"
+	EnvAPISecret      = "VONAGE_API_SECRET"
+	EnvNumber         = "VONAGE_NUMBER"
+	EnvJWTAlgorithm   = "VONAGE_JWT_ALGORITHM"
+
+	EnvSignatureSecret = "VONAGE_SIGNATURE_SECRET"
+	EnvSignatureMethod = "VONAGE_SIGNATURE_METHOD"
+)
+
+// NewCredentialsFromEnv creates credentials from the VONAGE_* environment
+// variables, then applies opts. VONAGE_PRIVATE_KEY_PATH takes precedence
+// over an inline VONAGE_PRIVATE_KEY, in which literal \n sequences are
+// treated as newlines.
+func NewCredentialsFromEnv(opts ...CredentialsOption) (*Credentials, error) {
+	var envOpts []CredentialsOption
+
+	if appID := os.Getenv(EnvApplicationID); appID != "" {
+		pemStr := strings.ReplaceAll(os.Getenv(EnvPrivateKey), `\n`, "\n")
+		if path := os.Getenv(EnvPrivateKeyPath); path != "" {
+			envOpts = append(envOpts, WithApplication(appID, ""), WithPrivateKeyFile(path))
+		} else {
+			envOpts = append(envOpts, WithApplication(appID, pemStr))
+		}
+	}
+	if passphrase := os.Getenv(EnvKeyPassphrase); passphrase != "" {
+		envOpts = append(envOpts, WithPrivateKeyPassphrase(passphrase))
+	}
+	if alg := os.Getenv(EnvJWTAlgorithm); alg != "" {
+		envOpts = append(envOpts, WithJWTAlgorithm(JWTAlgorithm(strings.ToUpper(alg))))
+	}
+	if key := os.Getenv(EnvAPIKey); key != "" {
+		envOpts = append(envOpts, WithAPIKey(key, os.Getenv(EnvAPISecret)))
+	}
+	if number := os.Getenv(EnvNumber); number != "" {
+		envOpts = append(envOpts, WithPhoneNumber(number))
+	}
+	if secret := os.Getenv(EnvSignatureSecret); secret != "" {
+		envOpts = append(envOpts, WithSignatureSecret(secret, SignatureMethod(os.Getenv(EnvSignatureMethod))))
+	}
+	if env := os.Getenv(EnvMode); env != "" {
+		mode, err := ParseMode(env)
+		if err != nil {
+			return nil, err
+		}
+		envOpts = append(envOpts, WithMode(mode))
+	}
+
+	creds, err := NewCredentials(append(envOpts, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	if !creds.HasApplication() && !creds.HasAPIKey() {
+		return nil, fmt.Errorf("%w: set %s and %s, or %s and %s",
+			ErrNotConfigured, EnvApplicationID, EnvPrivateKeyPath, EnvAPIKey, EnvAPISecret)
+	}
+	return creds, nil
+}
+
 // HasApplication returns true if application credentials are configured
 func (c *Credentials) HasApplication() bool {
 	return c.AppID != "" && c.PrivateKey != nil
@@ -85,45 +259,122 @@ func (c *Credentials) HasAPIKey() bool {
 	return c.APIKey != "" && c.APISecret != ""
 }
 
-// ParseRSAPrivateKey parses a PEM encoded RSA private key
-func ParseRSAPrivateKey(pemStr string) (*rsa.PrivateKey, error) {
-	block, _ := pem.Decode([]byte(pemStr))
-	if block == nil {
-		return nil, errors.New("failed to decode PEM block")
-	}
+// JWTAlgorithm is a JWT signing algorithm
+type JWTAlgorithm string
 
-	// Try PKCS#1 format first
-	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
-	if err == nil {
-		return key, nil
-	}
+// Supported JWT signing algorithms
+const (
+	// JWTAlgorithmRS256 signs with an RSA key (the default)
+	JWTAlgorithmRS256 JWTAlgorithm = "RS256"
+	// JWTAlgorithmES256 signs with an EC P-256 key
+	JWTAlgorithmES256 JWTAlgorithm = "ES256"
+)
 
-	// Try PKCS#8 format
-	keyInterface, err := x509.ParsePKCS8PrivateKey(block.Bytes)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse private key: %w", err)
+// ErrUnsupportedAlgorithm is returned (wrapped) for JWT algorithms other
+// than RS256 and ES256, or ones that do not match the key
+var ErrUnsupportedAlgorithm = errors.New("vonage: unsupported JWT algorithm")
+
+func (a JWTAlgorithm) validate() error {
+	switch a {
+	case JWTAlgorithmRS256, JWTAlgorithmES256:
+		return nil
 	}
+	return fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, string(a))
+}
 
-	rsaKey, ok := keyInterface.(*rsa.PrivateKey)
-	if !ok {
-		return nil, errors.New("not an RSA private key")
+// algorithmFor returns alg, or the default algorithm for key if alg is
+// empty
+func algorithmFor(alg JWTAlgorithm, key crypto.Signer) JWTAlgorithm {
+	if alg != "" {
+		return alg
 	}
+	if _, ok := key.(*ecdsa.PrivateKey); ok {
+		return JWTAlgorithmES256
+	}
+	return JWTAlgorithmRS256
+}
 
-	return rsaKey, nil
+// signingMethod returns the jwt signing method for alg, checking that key
+// can sign with it
+func signingMethod(alg JWTAlgorithm, key crypto.Signer) (jwt.SigningMethod, error) {
+	switch alg {
+	case JWTAlgorithmRS256:
+		if _, ok := key.(*rsa.PrivateKey); ok {
+			return jwt.SigningMethodRS256, nil
+		}
+	case JWTAlgorithmES256:
+		if k, ok := key.(*ecdsa.PrivateKey); ok && k.Curve == elliptic.P256() {
+			return jwt.SigningMethodES256, nil
+		}
+	default:
+		return nil, alg.validate()
+	}
+	return nil, fmt.Errorf("%w: %s cannot sign with a %T", ErrUnsupportedAlgorithm, alg, key)
 }
 
 // JWTGenerator generates JWT tokens for Vonage API authentication
 type JWTGenerator struct {
 	appID      string
-	privateKey *rsa.PrivateKey
+	privateKey crypto.Signer
+	algorithm  JWTAlgorithm
+
+	// issuedAtSkew is subtracted from iat
+	issuedAtSkew time.Duration
+	// notBeforeSkew, if set, adds an nbf claim of now minus the skew
+	notBeforeSkew *time.Duration
+}
+
+// JWTOption is a functional option for configuring the JWT generator
+type JWTOption func(*JWTGenerator)
+
+// WithIssuedAtSkew backdates the iat claim by the given duration
+func WithIssuedAtSkew(skew time.Duration) JWTOption {
+	return func(g *JWTGenerator) {
+		g.issuedAtSkew = skew
+	}
+}
+
+// WithNotBefore adds an nbf claim backdated by the given duration
+func WithNotBefore(skew time.Duration) JWTOption {
+	return func(g *JWTGenerator) {
+		g.notBeforeSkew = &skew
+	}
 }
 
-// NewJWTGenerator creates a new JWT generator
-func NewJWTGenerator(appID string, privateKey *rsa.PrivateKey) *JWTGenerator {
-	return &JWTGenerator{
+// WithAlgorithm sets the signing algorithm (default: RS256 for RSA keys,
+// ES256 for EC keys)
+func WithAlgorithm(alg JWTAlgorithm) JWTOption {
+	return func(g *JWTGenerator) {
+		g.algorithm = alg
+	}
+}
+
+// NewJWTGenerator creates a new JWT generator for an *rsa.PrivateKey or
+// *ecdsa.PrivateKey
+func NewJWTGenerator(appID string, privateKey crypto.Signer, opts ...JWTOption) *JWTGenerator {
+	g := &JWTGenerator{
 		appID:      appID,
 		privateKey: privateKey,
 	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// NewJWTGeneratorFromCredentials creates a JWT generator from application
+// credentials, applying the configured clock skew to iat and nbf
+func NewJWTGeneratorFromCredentials(creds *Credentials) *JWTGenerator {
+	var opts []JWTOption
+	if creds.Algorithm != "" {
+		opts = append(opts, WithAlgorithm(creds.Algorithm))
+	}
+	if creds.ClockSkew > 0 {
+		opts = append(opts, WithIssuedAtSkew(creds.ClockSkew), WithNotBefore(creds.ClockSkew))
+	}
+	return NewJWTGenerator(creds.AppID, creds.PrivateKey, opts...)
 }
 
 // JWTClaims represents additional claims for JWT generation
@@ -137,18 +388,25 @@ func (g *JWTGenerator) GenerateJWT(ttl time.Duration, additionalClaims JWTClaims
 
 	now := time.Now()
 	claims := jwt.MapClaims{
-		"iat":            now.Unix(),
+		"iat":            now.Add(-g.issuedAtSkew).Unix(),
 		"exp":            now.Add(ttl).Unix(),
 		"jti":            uuid.New().String(),
 		"application_id": g.appID,
 	}
+	if g.notBeforeSkew != nil {
+		claims["nbf"] = now.Add(-*g.notBeforeSkew).Unix()
+	}
 
 	// Merge additional claims
 	for k, v := range additionalClaims {
 		claims[k] = v
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	method, err := signingMethod(g.Algorithm(), g.privateKey)
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(method, claims)
 	signedToken, err := token.SignedString(g.privateKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign JWT: %w", err)
@@ -157,7 +415,159 @@ func (g *JWTGenerator) GenerateJWT(ttl time.Duration, additionalClaims JWTClaims
 	return signedToken, nil
 }
 
+// Algorithm returns the signing algorithm
+func (g *JWTGenerator) Algorithm() JWTAlgorithm {
+	return algorithmFor(g.algorithm, g.privateKey)
+}
+
 // GenerateAPIJWT generates a short-lived JWT for API calls (5 minutes)
 func (g *JWTGenerator) GenerateAPIJWT() (string, error) {
 	return g.GenerateJWT(5*time.Minute, nil)
 }
+
+// Authenticate sets a Bearer API JWT on the request. It implements
+// Authenticator.
+func (g *JWTGenerator) Authenticate(req *http.Request) error {
+	if g == nil {
+		return ErrNotConfigured
+	}
+	token, err := g.GenerateAPIJWT()
+	if err != nil {
+		return fmt.Errorf("failed to generate JWT: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// BasicAuth authenticates with the account API key and secret using HTTP
+// Basic authentication. It implements Authenticator.
+type BasicAuth struct {
+	APIKey    string
+	APISecret string
+}
+
+// Authenticate sets the Basic Authorization header on the request
+func (a BasicAuth) Authenticate(req *http.Request) error {
+	if a.APIKey == "" || a.APISecret == "" {
+		return ErrNotConfigured
+	}
+	req.SetBasicAuth(a.APIKey, a.APISecret)
+	return nil
+}
+
+// QueryAuth authenticates with api_key and api_secret query parameters, as
+// the legacy REST APIs (Numbers, Account, SMS) accept. It implements
+// Authenticator.
+type QueryAuth struct {
+	APIKey    string
+	APISecret string
+}
+
+// Authenticate adds the api_key and api_secret query parameters
+func (a QueryAuth) Authenticate(req *http.Request) error {
+	if a.APIKey == "" || a.APISecret == "" {
+		return ErrNotConfigured
+	}
+	q := req.URL.Query()
+	q.Set("api_key", a.APIKey)
+	q.Set("api_secret", a.APISecret)
+	req.URL.RawQuery = q.Encode()
+	return nil
+}
+
+// ========================================
+// Auth Strategies
+// ========================================
+
+// AuthStrategy returns the Authenticator for a way of authenticating, or
+// nil if creds lack what it needs
+type AuthStrategy func(creds *Credentials) Authenticator
+
+// Auth strategies for NewAuthChain
+var (
+	// AuthJWT signs with an application JWT
+	AuthJWT AuthStrategy = func(creds *Credentials) Authenticator {
+		if !creds.HasApplication() {
+			return nil
+		}
+		return NewJWTGeneratorFromCredentials(creds)
+	}
+	// AuthBasic uses the API key and secret with HTTP Basic authentication
+	AuthBasic AuthStrategy = func(creds *Credentials) Authenticator {
+		if !creds.HasAPIKey() {
+			return nil
+		}
+		return BasicAuth{APIKey: creds.APIKey, APISecret: creds.APISecret}
+	}
+	// AuthQuery uses the API key and secret as query parameters
+	AuthQuery AuthStrategy = func(creds *Credentials) Authenticator {
+		if !creds.HasAPIKey() {
+			return nil
+		}
+		return QueryAuth{APIKey: creds.APIKey, APISecret: creds.APISecret}
+	}
+)
+
+// DefaultAuthStrategies prefer an application JWT and fall back to Basic
+// authentication, which APIs such as Messages and Verify v2 also accept
+var DefaultAuthStrategies = []AuthStrategy{AuthJWT, AuthBasic}
+
+// authChain authenticates with the first strategy the current
+// credentials support
+type authChain struct {
+	provider   CredentialsProvider
+	strategies []AuthStrategy
+}
+
+// NewAuthChain returns an Authenticator that fetches credentials from p
+// for every request and authenticates with the first of strategies
+// (DefaultAuthStrategies if none) they support. Requests fail with
+// ErrNotConfigured if they support none. *Credentials is itself a
+// provider of static credentials.
+func NewAuthChain(p CredentialsProvider, strategies ...AuthStrategy) Authenticator {
+	if len(strategies) == 0 {
+		strategies = DefaultAuthStrategies
+	}
+	return &authChain{provider: p, strategies: strategies}
+}
+
+// Authenticate implements Authenticator
+func (a *authChain) Authenticate(req *http.Request) error {
+	creds, err := a.provider.Credentials(req.Context())
+	if err != nil {
+		return err
+	}
+	auth := selectAuth(creds, a.strategies)
+	if auth == nil {
+		return ErrNotConfigured
+	}
+	return auth.Authenticate(req)
+}
+
+// AuthenticatorFromCredentials is like NewAuthChain for static
+// credentials, but fails with ErrNotConfigured at once if they support
+// none of the strategies
+func AuthenticatorFromCredentials(creds *Credentials, strategies ...AuthStrategy) (Authenticator, error) {
+	if len(strategies) == 0 {
+		strategies = DefaultAuthStrategies
+	}
+	auth := selectAuth(creds, strategies)
+	if auth == nil {
+		return nil, ErrNotConfigured
+	}
+	return auth, nil
+}
+
+// selectAuth returns the authenticator of the first strategy creds
+// support, or nil
+func selectAuth(creds *Credentials, strategies []AuthStrategy) Authenticator {
+	if creds == nil {
+		return nil
+	}
+	for _, strategy := range strategies {
+		if auth := strategy(creds); auth != nil {
+			return auth
+		}
+	}
+	return nil
+}
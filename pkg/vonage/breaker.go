@@ -0,0 +1,199 @@
+package vonage
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ========================================
+// Circuit Breaker
+// ========================================
+
+// ErrCircuitOpen is returned without contacting the API while the circuit
+// breaker is open
+var ErrCircuitOpen = errors.New("vonage: circuit breaker open")
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets all requests through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects all requests with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreakerConfig configures a CircuitBreaker
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens
+	// the circuit (default 5)
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before probing
+	// (default 30s)
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of concurrent probe requests allowed
+	// while half-open (default 1)
+	HalfOpenProbes int
+}
+
+// CircuitBreaker fails requests fast after repeated transport errors or
+// 5xx responses. Requests whose context is canceled or past its deadline
+// are not counted. Install it with WithCircuitBreaker.
+type CircuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probes   int
+	// period numbers the half-open periods, so that only probes admitted
+	// in the current one move the state
+	period uint64
+}
+
+// NewCircuitBreaker creates a circuit breaker, applying defaults to unset
+// config fields
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenProbes <= 0 {
+		cfg.HalfOpenProbes = 1
+	}
+	return &CircuitBreaker{cfg: cfg}
+}
+
+// WithCircuitBreaker installs the circuit breaker as transport middleware
+func WithCircuitBreaker(b *CircuitBreaker) TransportOption {
+	return WithMiddleware(b.Middleware())
+}
+
+// State returns the current state of the breaker
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.advance()
+	return b.state
+}
+
+// Middleware returns the breaker as transport middleware
+func (b *CircuitBreaker) Middleware() Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			probe, err := b.allow()
+			if err != nil {
+				return nil, err
+			}
+			resp, err := next(req)
+			if req.Context().Err() != nil {
+				// The caller gave up, which says nothing about the API
+				b.release(probe)
+				return resp, err
+			}
+			b.record(probe, err != nil || resp.StatusCode >= 500)
+			return resp, err
+		}
+	}
+}
+
+// allow reports whether a request may proceed. When half-open it reserves
+// a probe slot and returns the period the probe belongs to, which is 0 for
+// requests that are not probes.
+func (b *CircuitBreaker) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance()
+	switch b.state {
+	case CircuitOpen:
+		return 0, ErrCircuitOpen
+	case CircuitHalfOpen:
+		if b.probes >= b.cfg.HalfOpenProbes {
+			return 0, ErrCircuitOpen
+		}
+		b.probes++
+		return b.period, nil
+	}
+	return 0, nil
+}
+
+// isProbe reports whether probe was admitted in the current half-open
+// period
+func (b *CircuitBreaker) isProbe(probe uint64) bool {
+	return probe != 0 && b.state == CircuitHalfOpen && probe == b.period
+}
+
+// release frees the slot of a probe without recording an outcome
+func (b *CircuitBreaker) release(probe uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.isProbe(probe) {
+		b.probes--
+	}
+}
+
+// record updates the breaker with the outcome of a request. While
+// half-open only the admitted probes move the state.
+func (b *CircuitBreaker) record(probe uint64, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen {
+		if !b.isProbe(probe) {
+			return
+		}
+		b.probes--
+		if failed {
+			b.trip()
+		} else {
+			b.state = CircuitClosed
+			b.failures = 0
+		}
+		return
+	}
+
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == CircuitClosed && b.failures >= b.cfg.FailureThreshold {
+		b.trip()
+	}
+}
+
+// trip opens the circuit
+func (b *CircuitBreaker) trip() {
+	b.state = CircuitOpen
+	b.openedAt = time.Now()
+	b.probes = 0
+}
+
+// advance moves an open circuit to half-open once the timeout has elapsed
+func (b *CircuitBreaker) advance() {
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.cfg.OpenTimeout {
+		b.state = CircuitHalfOpen
+		b.probes = 0
+		b.period++
+	}
+}
@@ -0,0 +1,203 @@
+package vonage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var errConnReset = errors.New("connection reset")
+
+// statusTrip returns a round trip answering with status, or failing with
+// errConnReset for status 0, and counts its calls
+func statusTrip(status int, calls *int) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		*calls++
+		if status == 0 {
+			return nil, errConnReset
+		}
+		return &http.Response{StatusCode: status, Body: http.NoBody}, nil
+	}
+}
+
+// viaBreaker passes a request with the given outcome through the breaker
+func viaBreaker(b *CircuitBreaker, status int) error {
+	var calls int
+	_, err := b.Middleware()(statusTrip(status, &calls))(httptest.NewRequest(http.MethodGet, "/v1/calls", nil))
+	return err
+}
+
+// expireOpen moves the breaker's open period into the past
+func expireOpen(b *CircuitBreaker) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.openedAt = b.openedAt.Add(-b.cfg.OpenTimeout)
+}
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		outcomes []int
+		want     CircuitState
+	}{
+		{"below threshold", []int{500, 500}, CircuitClosed},
+		{"at threshold", []int{500, 502, 503}, CircuitOpen},
+		{"transport errors count", []int{0, 0, 500}, CircuitOpen},
+		{"success resets the count", []int{500, 500, 200, 500, 500}, CircuitClosed},
+		{"client errors are not failures", []int{500, 500, 404, 429}, CircuitClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 3, OpenTimeout: time.Hour})
+			for _, status := range tt.outcomes {
+				viaBreaker(b, status)
+			}
+			if got := b.State(); got != tt.want {
+				t.Errorf("State() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerOpenRejects(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour})
+	viaBreaker(b, 500)
+
+	var calls int
+	_, err := b.Middleware()(statusTrip(200, &calls))(httptest.NewRequest(http.MethodGet, "/v1/calls", nil))
+	if !errors.Is(err, ErrCircuitOpen) || calls != 0 {
+		t.Errorf("request while open = %v after %d calls, want ErrCircuitOpen without a call", err, calls)
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name  string
+		probe int
+		want  CircuitState
+	}{
+		{"probe succeeds", 200, CircuitClosed},
+		{"probe fails", 500, CircuitOpen},
+		{"probe transport error", 0, CircuitOpen},
+		{"probe client error", 400, CircuitClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Hour})
+			viaBreaker(b, 500)
+			viaBreaker(b, 500)
+			if got := b.State(); got != CircuitOpen {
+				t.Fatalf("State() = %s, want open", got)
+			}
+
+			expireOpen(b)
+			if got := b.State(); got != CircuitHalfOpen {
+				t.Fatalf("State() after the timeout = %s, want half-open", got)
+			}
+
+			viaBreaker(b, tt.probe)
+			if got := b.State(); got != tt.want {
+				t.Errorf("State() after the probe = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerHalfOpenProbeLimit(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour, HalfOpenProbes: 1})
+	viaBreaker(b, 500)
+	expireOpen(b)
+
+	// While the probe is in flight, other requests fail fast
+	var calls int
+	probe := func(req *http.Request) (*http.Response, error) {
+		if err := viaBreaker(b, 200); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("request during the probe = %v, want ErrCircuitOpen", err)
+		}
+		return statusTrip(200, &calls)(req)
+	}
+	if _, err := b.Middleware()(probe)(httptest.NewRequest(http.MethodGet, "/v1/calls", nil)); err != nil {
+		t.Fatalf("probe = %v", err)
+	}
+	if got := b.State(); got != CircuitClosed || calls != 1 {
+		t.Errorf("State() = %s after %d calls, want closed after the probe", got, calls)
+	}
+}
+
+func TestCircuitBreakerCallerCancel(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	viaCanceled := func(b *CircuitBreaker, status int) error {
+		var calls int
+		req := httptest.NewRequest(http.MethodGet, "/v1/calls", nil).WithContext(canceled)
+		_, err := b.Middleware()(statusTrip(status, &calls))(req)
+		return err
+	}
+
+	// Requests the caller gave up on are not failures
+	b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour})
+	viaCanceled(b, 0)
+	viaCanceled(b, 500)
+	if got := b.State(); got != CircuitClosed {
+		t.Errorf("State() after canceled requests = %s, want closed", got)
+	}
+
+	// A canceled probe frees its slot and leaves the circuit half-open
+	viaBreaker(b, 500)
+	expireOpen(b)
+	viaCanceled(b, 0)
+	if got := b.State(); got != CircuitHalfOpen {
+		t.Fatalf("State() after a canceled probe = %s, want half-open", got)
+	}
+	if err := viaBreaker(b, 200); err != nil {
+		t.Fatalf("probe after a canceled probe = %v", err)
+	}
+	if got := b.State(); got != CircuitClosed {
+		t.Errorf("State() after the probe = %s, want closed", got)
+	}
+}
+
+func TestCircuitBreakerHalfOpenOnlyProbes(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{"late success", 200},
+		{"late failure", 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour})
+
+			// A request admitted while closed completes after the circuit
+			// has opened and gone half-open
+			var calls int
+			slow := func(req *http.Request) (*http.Response, error) {
+				viaBreaker(b, 500)
+				expireOpen(b)
+				if got := b.State(); got != CircuitHalfOpen {
+					t.Fatalf("State() before the late request completes = %s, want half-open", got)
+				}
+				return statusTrip(tt.status, &calls)(req)
+			}
+			b.Middleware()(slow)(httptest.NewRequest(http.MethodGet, "/v1/calls", nil))
+			if got := b.State(); got != CircuitHalfOpen {
+				t.Fatalf("State() after the late request = %s, want half-open", got)
+			}
+
+			// The probe slot is still free
+			if err := viaBreaker(b, 200); err != nil {
+				t.Fatalf("probe = %v", err)
+			}
+			if got := b.State(); got != CircuitClosed {
+				t.Errorf("State() after the probe = %s, want closed", got)
+			}
+		})
+	}
+}
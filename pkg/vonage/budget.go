@@ -0,0 +1,312 @@
+package vonage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ========================================
+// Budget Guard
+// ========================================
+
+// ErrBudgetExceeded is matched by *BudgetError
+var ErrBudgetExceeded = errors.New("vonage: budget exceeded")
+
+// BudgetError is returned when a send or call would exceed a daily limit
+type BudgetError struct {
+	// Limit names the limit, e.g. "messages", "messages:sms", "calls",
+	// "call_minutes" or "spend"
+	Limit string
+	Used  float64
+	Max   float64
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("vonage: daily %s budget exceeded (%g of %g)", e.Limit, e.Used, e.Max)
+}
+
+// Is makes errors.Is(err, ErrBudgetExceeded) true
+func (e *BudgetError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+// BudgetLimits are daily limits. Zero values are unlimited.
+type BudgetLimits struct {
+	MessagesPerDay int
+	// ChannelMessagesPerDay limits channels separately, e.g. {"sms": 500}
+	ChannelMessagesPerDay map[string]int
+	CallsPerDay           int
+	CallMinutesPerDay     float64
+	// SpendPerDay limits the estimated spend, in the account currency
+	SpendPerDay float64
+}
+
+// Usage is an amount of sending, charged against a budget
+type Usage struct {
+	// Channel is the messaging channel, e.g. "sms"
+	Channel     string
+	Messages    int
+	Calls       int
+	CallMinutes float64
+	// Spend is the known price; if zero it is estimated from the budget's
+	// prices
+	Spend float64
+}
+
+// CounterStore keeps the budget's daily counters. Deployments with several
+// instances need a shared implementation, e.g. Redis INCRBYFLOAT with
+// EXPIRE.
+type CounterStore interface {
+	// Add adds delta to the counter, creating it with the given TTL, and
+	// returns the new total. It must be atomic.
+	Add(ctx context.Context, key string, delta float64, ttl time.Duration) (float64, error)
+	// Get returns the counter's value, 0 if it does not exist
+	Get(ctx context.Context, key string) (float64, error)
+}
+
+// MemoryCounterStore is an in-process CounterStore
+type MemoryCounterStore struct {
+	mu       sync.Mutex
+	counters map[string]memoryCounter
+}
+
+// memoryCounter is a counter value with its expiry
+type memoryCounter struct {
+	value     float64
+	expiresAt time.Time
+}
+
+// NewMemoryCounterStore creates an empty in-process counter store
+func NewMemoryCounterStore() *MemoryCounterStore {
+	return &MemoryCounterStore{counters: make(map[string]memoryCounter)}
+}
+
+// Add implements CounterStore
+func (m *MemoryCounterStore) Add(ctx context.Context, key string, delta float64, ttl time.Duration) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	c, ok := m.counters[key]
+	if !ok || now.After(c.expiresAt) {
+		c = memoryCounter{expiresAt: now.Add(ttl)}
+	}
+	c.value += delta
+	m.counters[key] = c
+	return c.value, nil
+}
+
+// Get implements CounterStore
+func (m *MemoryCounterStore) Get(ctx context.Context, key string) (float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.counters[key]
+	if !ok || time.Now().After(c.expiresAt) {
+		return 0, nil
+	}
+	return c.value, nil
+}
+
+// Budget guards against runaway sending, such as a loop dialing real
+// phones, by enforcing daily limits on messages, calls, call minutes and
+// estimated spend. Configure it on clients with their WithBudget option.
+type Budget struct {
+	limits       BudgetLimits
+	store        CounterStore
+	logger       Logger
+	warnOnly     bool
+	location     *time.Location
+	prefix       string
+	messagePrice map[string]float64
+	minutePrice  float64
+	now          func() time.Time
+}
+
+// BudgetOption is a functional option for configuring a Budget
+type BudgetOption func(*Budget)
+
+// WithCounterStore replaces the default in-memory counter store
+func WithCounterStore(s CounterStore) BudgetOption {
+	return func(b *Budget) {
+		b.store = s
+	}
+}
+
+// WithBudgetWarnOnly logs exceeded limits as warnings instead of blocking
+func WithBudgetWarnOnly() BudgetOption {
+	return func(b *Budget) {
+		b.warnOnly = true
+	}
+}
+
+// WithBudgetLogger sets the logger (default: no logging)
+func WithBudgetLogger(l Logger) BudgetOption {
+	return func(b *Budget) {
+		b.logger = l
+	}
+}
+
+// WithBudgetLocation sets the time zone whose midnight resets the daily
+// counters (default UTC)
+func WithBudgetLocation(loc *time.Location) BudgetOption {
+	return func(b *Budget) {
+		b.location = loc
+	}
+}
+
+// WithBudgetKeyPrefix namespaces the counters, e.g. per environment, when
+// budgets share a CounterStore (default "vonage:budget")
+func WithBudgetKeyPrefix(prefix string) BudgetOption {
+	return func(b *Budget) {
+		b.prefix = prefix
+	}
+}
+
+// WithEstimatedPrices sets the prices used to estimate spend: per message
+// by channel, and per call minute
+func WithEstimatedPrices(perMessage map[string]float64, perCallMinute float64) BudgetOption {
+	return func(b *Budget) {
+		b.messagePrice = perMessage
+		b.minutePrice = perCallMinute
+	}
+}
+
+// NewBudget creates a budget enforcing limits
+func NewBudget(limits BudgetLimits, opts ...BudgetOption) *Budget {
+	b := &Budget{
+		limits:   limits,
+		store:    NewMemoryCounterStore(),
+		logger:   NopLogger(),
+		location: time.UTC,
+		prefix:   "vonage:budget",
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// budgetCounter is one of the counters a usage charges
+type budgetCounter struct {
+	name  string
+	delta float64
+	max   float64
+}
+
+// Reserve charges u against the budget before sending. If a limit would
+// be exceeded it returns a *BudgetError and charges nothing, unless the
+// budget only warns. Calls are also refused once the day's call minutes
+// or spend are used up. Release the usage if the send then fails.
+func (b *Budget) Reserve(ctx context.Context, u Usage) error {
+	counters := b.counters(u)
+
+	var exceeded *BudgetError
+	for i, c := range counters {
+		key, ttl := b.key(c.name)
+		total, err := b.store.Add(ctx, key, c.delta, ttl)
+		if err != nil {
+			b.rollback(ctx, counters[:i])
+			return fmt.Errorf("vonage: failed to update budget counter: %w", err)
+		}
+
+		over := c.max > 0 && total > c.max
+		// Zero-delta checks refuse calls once minutes or spend are used up
+		if c.max > 0 && c.delta == 0 && total >= c.max {
+			over = true
+		}
+		if over && exceeded == nil {
+			exceeded = &BudgetError{Limit: c.name, Used: total - c.delta, Max: c.max}
+		}
+	}
+
+	if exceeded == nil {
+		return nil
+	}
+	if b.warnOnly {
+		LoggerFromContext(ctx, b.logger).Warn("Vonage budget exceeded",
+			"limit", exceeded.Limit,
+			"used", exceeded.Used,
+			"max", exceeded.Max,
+		)
+		return nil
+	}
+	b.rollback(ctx, counters)
+	return exceeded
+}
+
+// Release returns a reservation after a failed send
+func (b *Budget) Release(ctx context.Context, u Usage) {
+	b.rollback(ctx, b.counters(u))
+}
+
+// Record charges actual usage that was not reserved, such as call minutes
+// and prices from completed call events
+func (b *Budget) Record(ctx context.Context, u Usage) error {
+	for _, c := range b.counters(u) {
+		if c.delta == 0 {
+			continue
+		}
+		key, ttl := b.key(c.name)
+		if _, err := b.store.Add(ctx, key, c.delta, ttl); err != nil {
+			return fmt.Errorf("vonage: failed to update budget counter: %w", err)
+		}
+	}
+	return nil
+}
+
+// Used returns today's count for a limit name, e.g. "messages:sms"
+func (b *Budget) Used(ctx context.Context, limit string) (float64, error) {
+	key, _ := b.key(limit)
+	return b.store.Get(ctx, key)
+}
+
+// counters returns the counters u charges
+func (b *Budget) counters(u Usage) []budgetCounter {
+	spend := u.Spend
+	if spend == 0 {
+		spend = float64(u.Messages)*b.messagePrice[u.Channel] + u.CallMinutes*b.minutePrice
+	}
+
+	var counters []budgetCounter
+	if u.Messages > 0 {
+		counters = append(counters, budgetCounter{"messages", float64(u.Messages), float64(b.limits.MessagesPerDay)})
+		if u.Channel != "" {
+			counters = append(counters, budgetCounter{"messages:" + u.Channel, float64(u.Messages), float64(b.limits.ChannelMessagesPerDay[u.Channel])})
+		}
+	}
+	if u.Calls > 0 || u.CallMinutes > 0 {
+		counters = append(counters,
+			budgetCounter{"calls", float64(u.Calls), float64(b.limits.CallsPerDay)},
+			budgetCounter{"call_minutes", u.CallMinutes, b.limits.CallMinutesPerDay},
+		)
+	}
+	return append(counters, budgetCounter{"spend", spend, b.limits.SpendPerDay})
+}
+
+// rollback subtracts charged counters
+func (b *Budget) rollback(ctx context.Context, counters []budgetCounter) {
+	for _, c := range counters {
+		if c.delta == 0 {
+			continue
+		}
+		key, ttl := b.key(c.name)
+		if _, err := b.store.Add(ctx, key, -c.delta, ttl); err != nil {
+			LoggerFromContext(ctx, b.logger).Error("Failed to roll back budget counter", "counter", c.name, "error", err)
+		}
+	}
+}
+
+// key returns today's key for a counter and the time until it resets
+func (b *Budget) key(name string) (string, time.Duration) {
+	now := b.now().In(b.location)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, b.location).AddDate(0, 0, 1)
+	// Keep counters a little past midnight for late rollbacks
+	return b.prefix + ":" + now.Format("2006-01-02") + ":" + name, midnight.Sub(now) + time.Hour
+}
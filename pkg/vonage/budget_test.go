@@ -0,0 +1,148 @@
+package vonage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBudgetLimitBoundary(t *testing.T) {
+	sms := Usage{Channel: "sms", Messages: 1}
+	call := Usage{Calls: 1}
+
+	tests := []struct {
+		name      string
+		limits    BudgetLimits
+		used      []Usage
+		next      Usage
+		wantLimit string
+	}{
+		{"last message", BudgetLimits{MessagesPerDay: 3}, []Usage{sms, sms}, sms, ""},
+		{"one over", BudgetLimits{MessagesPerDay: 3}, []Usage{sms, sms, sms}, sms, "messages"},
+		{"batch over", BudgetLimits{MessagesPerDay: 3}, []Usage{sms, sms}, Usage{Channel: "sms", Messages: 2}, "messages"},
+		{"channel limit", BudgetLimits{ChannelMessagesPerDay: map[string]int{"sms": 1}}, []Usage{sms}, sms, "messages:sms"},
+		{"other channel", BudgetLimits{ChannelMessagesPerDay: map[string]int{"sms": 1}}, []Usage{sms}, Usage{Channel: "whatsapp", Messages: 1}, ""},
+		{"spend reaches limit", BudgetLimits{SpendPerDay: 1}, []Usage{{Messages: 1, Spend: 0.5}}, Usage{Messages: 1, Spend: 0.5}, ""},
+		{"spend over limit", BudgetLimits{SpendPerDay: 1}, []Usage{{Messages: 1, Spend: 0.75}}, Usage{Messages: 1, Spend: 0.5}, "spend"},
+		{"calls", BudgetLimits{CallsPerDay: 1}, []Usage{call}, call, "calls"},
+		{"call minutes used up", BudgetLimits{CallMinutesPerDay: 10}, []Usage{{CallMinutes: 10}}, call, "call_minutes"},
+		{"call minutes left", BudgetLimits{CallMinutesPerDay: 10}, []Usage{{CallMinutes: 9.5}}, call, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			b := NewBudget(tt.limits)
+			for _, u := range tt.used {
+				if err := b.Record(ctx, u); err != nil {
+					t.Fatal(err)
+				}
+			}
+			before, _ := b.Used(ctx, "messages")
+
+			err := b.Reserve(ctx, tt.next)
+			if tt.wantLimit == "" {
+				if err != nil {
+					t.Fatalf("Reserve() = %v, want nil", err)
+				}
+				return
+			}
+			var budgetErr *BudgetError
+			if !errors.As(err, &budgetErr) || !errors.Is(err, ErrBudgetExceeded) || budgetErr.Limit != tt.wantLimit {
+				t.Fatalf("Reserve() = %v, want the %s limit exceeded", err, tt.wantLimit)
+			}
+			// A refused reservation charges nothing
+			if after, _ := b.Used(ctx, "messages"); after != before {
+				t.Errorf("messages used = %g after a refused reservation, want %g", after, before)
+			}
+		})
+	}
+}
+
+func TestBudgetWarnOnly(t *testing.T) {
+	ctx := context.Background()
+	b := NewBudget(BudgetLimits{MessagesPerDay: 1}, WithBudgetWarnOnly())
+	for i := 0; i < 2; i++ {
+		if err := b.Reserve(ctx, Usage{Messages: 1}); err != nil {
+			t.Fatalf("Reserve() = %v, want nil in warn-only mode", err)
+		}
+	}
+	if used, _ := b.Used(ctx, "messages"); used != 2 {
+		t.Errorf("messages used = %g, want 2", used)
+	}
+}
+
+func TestBudgetWindowReset(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		wantErr bool
+	}{
+		{"same day", 59 * time.Second, true},
+		{"after local midnight", time.Minute, false},
+		{"next day", 24 * time.Hour, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 23:59 in Tokyo is mid-afternoon UTC, so only the local day
+			// boundary can reset the counter
+			now := time.Date(2024, 4, 1, 23, 59, 0, 0, tokyo)
+			b := NewBudget(BudgetLimits{MessagesPerDay: 1}, WithBudgetLocation(tokyo))
+			b.now = func() time.Time { return now }
+			if err := b.Reserve(ctx, Usage{Messages: 1}); err != nil {
+				t.Fatal(err)
+			}
+
+			now = now.Add(tt.advance)
+			if err := b.Reserve(ctx, Usage{Messages: 1}); (err != nil) != tt.wantErr {
+				t.Errorf("Reserve() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBudgetConcurrentReservations(t *testing.T) {
+	ctx := context.Background()
+	b := NewBudget(BudgetLimits{MessagesPerDay: 50, ChannelMessagesPerDay: map[string]int{"sms": 40}})
+
+	var wg sync.WaitGroup
+	var reserved atomic.Int32
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			channel := "sms"
+			if i%2 == 1 {
+				channel = "whatsapp"
+			}
+			if b.Reserve(ctx, Usage{Channel: channel, Messages: 1}) == nil {
+				reserved.Add(1)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Refused reservations roll back, so the limits are never overshot
+	// and the counters match what was reserved. A reservation may be
+	// refused while others are in flight, so fewer than 50 can succeed.
+	used, _ := b.Used(ctx, "messages")
+	smsUsed, _ := b.Used(ctx, "messages:sms")
+	if n := reserved.Load(); n == 0 || n > 50 || float64(n) != used {
+		t.Errorf("reserved %d, messages used %g, want the same, at most 50", n, used)
+	}
+	if smsUsed > 40 {
+		t.Errorf("sms used %g, want at most 40", smsUsed)
+	}
+
+	b.Release(ctx, Usage{Channel: "sms", Messages: 1})
+	if err := b.Reserve(ctx, Usage{Channel: "whatsapp", Messages: 1}); err != nil {
+		t.Errorf("Reserve() after Release = %v, want nil", err)
+	}
+}
@@ -19,11 +19,45 @@ type Client struct {
 	credentials  *Credentials
 	httpClient   *http.Client
 	jwtGenerator *JWTGenerator
+	logger       Logger
+	middleware   []Middleware
+	provider     CredentialsProvider
+	uaSuffix     string
+	endpoints    Endpoints
+	timeout      time.Duration
+	retry        *RetryPolicy
 
 	// Sub-clients (lazy initialized)
 	video *VideoClient
 }
 
+// Settings is the configuration a root Client shares with its
+// sub-clients: each sub-package has a WithSettings option that applies it
+type Settings struct {
+	HTTPClient *http.Client
+	Logger     Logger
+	// Middleware includes the interceptors and retry policy
+	Middleware      []Middleware
+	UserAgentSuffix string
+	Mode            Mode
+}
+
+// TransportOptions returns s as options for NewTransport
+func (s Settings) TransportOptions() []TransportOption {
+	opts := []TransportOption{
+		WithMiddleware(s.Middleware...),
+		WithTransportUserAgentSuffix(s.UserAgentSuffix),
+		WithTransportMode(s.Mode),
+	}
+	if s.HTTPClient != nil {
+		opts = append(opts, WithTransportHTTPClient(s.HTTPClient))
+	}
+	if s.Logger != nil {
+		opts = append(opts, WithTransportLogger(s.Logger))
+	}
+	return opts
+}
+
 // ClientOption is a functional option for configuring the client
 type ClientOption func(*Client)
 
@@ -34,10 +68,72 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
-// WithTimeout sets the HTTP client timeout
+// WithTimeout sets the HTTP client timeout. It applies to a client set
+// with WithHTTPClient too, whatever the order of the options, without
+// modifying that client.
 func WithTimeout(timeout time.Duration) ClientOption {
 	return func(c *Client) {
-		c.httpClient.Timeout = timeout
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithTransportMiddleware adds middleware to every transport created by
+// the client
+func WithTransportMiddleware(mw ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithTransportRequestInterceptor runs fn on every outgoing request of
+// every transport created by the client, after authentication
+func WithTransportRequestInterceptor(fn RequestInterceptor) ClientOption {
+	return WithTransportMiddleware(InterceptRequest(fn))
+}
+
+// WithTransportResponseInterceptor runs fn on every response received by
+// every transport created by the client
+func WithTransportResponseInterceptor(fn ResponseInterceptor) ClientOption {
+	return WithTransportMiddleware(InterceptResponse(fn))
+}
+
+// WithRetryPolicy retries failed requests of every transport created by
+// the client with p. Retries run inside the other middleware, so
+// interceptors and metrics see one call per request.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = &p
+	}
+}
+
+// WithCredentialsProvider makes transports created by the client fetch
+// credentials from p on every request, enabling key rotation
+func WithCredentialsProvider(p CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.provider = p
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier to the
+// User-Agent of every transport created by the client
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.uaSuffix = suffix
+	}
+}
+
+// WithEndpoints sets the base URLs of the sub-clients created from the
+// client (default DefaultEndpoints). Empty fields keep their defaults.
+func WithEndpoints(e Endpoints) ClientOption {
+	return func(c *Client) {
+		c.endpoints = e.Resolve()
 	}
 }
 
@@ -48,16 +144,24 @@ func NewClient(credentials *Credentials, opts ...ClientOption) *Client {
 		httpClient: &http.Client{
 			Timeout: DefaultTimeout,
 		},
+		logger:    NopLogger(),
+		endpoints: DefaultEndpoints(),
 	}
 
 	if credentials.HasApplication() {
-		c.jwtGenerator = NewJWTGenerator(credentials.AppID, credentials.PrivateKey)
+		c.jwtGenerator = NewJWTGeneratorFromCredentials(credentials)
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	if c.timeout > 0 {
+		httpClient := *c.httpClient
+		httpClient.Timeout = c.timeout
+		c.httpClient = &httpClient
+	}
+
 	return c
 }
 
@@ -66,23 +170,98 @@ func (c *Client) Credentials() *Credentials {
 	return c.credentials
 }
 
+// CredentialsProvider returns the provider set with
+// WithCredentialsProvider, or nil if the credentials are static
+func (c *Client) CredentialsProvider() CredentialsProvider {
+	return c.provider
+}
+
 // HTTPClient returns the HTTP client
 func (c *Client) HTTPClient() *http.Client {
 	return c.httpClient
 }
 
+// Logger returns the logger
+func (c *Client) Logger() Logger {
+	return c.logger
+}
+
 // JWTGenerator returns the JWT generator
 func (c *Client) JWTGenerator() *JWTGenerator {
 	return c.jwtGenerator
 }
 
+// Middleware returns the transport middleware, ending with the retry
+// policy if one is set
+func (c *Client) Middleware() []Middleware {
+	if c.retry == nil {
+		return c.middleware
+	}
+	mw := append([]Middleware(nil), c.middleware...)
+	return append(mw, c.retry.Middleware())
+}
+
+// RetryPolicy returns the retry policy, if one is set
+func (c *Client) RetryPolicy() (RetryPolicy, bool) {
+	if c.retry == nil {
+		return RetryPolicy{}, false
+	}
+	return *c.retry, true
+}
+
+// UserAgentSuffix returns the application identifier appended to the
+// User-Agent
+func (c *Client) UserAgentSuffix() string {
+	return c.uaSuffix
+}
+
+// Endpoints returns the base URLs of the client's sub-clients
+func (c *Client) Endpoints() Endpoints {
+	return c.endpoints
+}
+
+// NewTransport creates a transport for baseURL that shares the client's
+// HTTP client, authentication, middleware, logger and mode. Requests are
+// signed with an application JWT, or use Basic authentication with the
+// API key and secret if no application is configured.
+func (c *Client) NewTransport(baseURL string) *Transport {
+	var auth Authenticator
+	switch {
+	case c.provider != nil:
+		auth = NewAuthChain(c.provider)
+	case c.jwtGenerator != nil:
+		auth = c.jwtGenerator
+	default:
+		// Without an application, fall back to the API key and secret
+		auth, _ = AuthenticatorFromCredentials(c.credentials, AuthBasic)
+	}
+	return NewTransport(baseURL, auth, c.Settings().TransportOptions()...)
+}
+
+// Settings returns the configuration the client shares with its
+// sub-clients. Pass it to a sub-client's WithSettings option to build one
+// by hand with the same configuration as the sdk package would.
+func (c *Client) Settings() Settings {
+	return Settings{
+		HTTPClient:      c.httpClient,
+		Logger:          c.logger,
+		Middleware:      c.Middleware(),
+		UserAgentSuffix: c.uaSuffix,
+		Mode:            c.credentials.Mode,
+	}
+}
+
 // VideoClient is a placeholder for the video sub-client
-// The actual implementation is in the video package
+//
+// Deprecated: Use the sdk package, whose Client.Video returns a configured
+// *video.Client.
 type VideoClient struct{}
 
-// Video returns the Video API client
-// Note: This is a convenience method. For full Video API functionality,
-// use the video package directly.
+// Video returns the placeholder Video API client
+//
+// Deprecated: Use the sdk package, whose Client.Video returns a configured
+// *video.Client. The sub-clients import this package, so it cannot
+// construct them itself.
 func (c *Client) Video() *VideoClient {
 	if c.video == nil {
 		c.video = &VideoClient{}
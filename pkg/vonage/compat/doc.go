@@ -0,0 +1,8 @@
+// Package compat keeps older SDK call signatures available as thin wrappers
+// around the current API, so services can upgrade incrementally.
+//
+// Every exported identifier in this package carries a "Deprecated:" notice
+// pointing at its replacement, which go vet-aware tooling (gopls,
+// staticcheck) reports at compile time. Wrappers are removed one minor
+// version after the signature they preserve was changed.
+package compat
@@ -0,0 +1,19 @@
+package compat_test
+
+import (
+	"context"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/compat"
+	"github.com/vonatrigger/poc/pkg/vonage/voice"
+)
+
+func ExampleTalkIntoCall() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+	)
+	client, _ := voice.NewClientFromCredentials(creds)
+
+	// Existing call sites keep compiling while they migrate
+	_ = compat.TalkIntoCall(context.Background(), client, "some-call-uuid", "新しいメッセージです", "Mizuki", 1)
+}
@@ -0,0 +1,70 @@
+package compat
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vonatrigger/poc/pkg/vonage/video"
+)
+
+// ========================================
+// Video sessions (pre-context signatures)
+// ========================================
+
+// CreateSession creates a video session using the original signature
+// without a context. Like the original, it returns mock sessions without
+// an error.
+//
+// Deprecated: Use video.Client.CreateSession.
+func CreateSession(c *video.Client, opts *video.CreateSessionOptions) (*video.Session, error) {
+	return acceptMock(c.CreateSession(context.Background(), opts))
+}
+
+// CreateSessionForSpot creates a spot session using the original signature
+// without a context.
+//
+// Deprecated: Use video.Client.CreateSessionForSpot.
+func CreateSessionForSpot(c *video.Client, spotID string, opts *video.CreateSessionOptions) (*video.Session, error) {
+	return acceptMock(c.CreateSessionForSpot(context.Background(), spotID, opts))
+}
+
+// GetSession retrieves a cached session using the original signature
+// without a context.
+//
+// Deprecated: Use video.Client.GetSession.
+func GetSession(c *video.Client, sessionID string) (*video.Session, error) {
+	return acceptMock(c.GetSession(context.Background(), sessionID))
+}
+
+// GetOrCreateSession gets or creates a spot session using the original
+// signature without a context.
+//
+// Deprecated: Use video.Client.GetOrCreateSession.
+func GetOrCreateSession(c *video.Client, spotID string, opts *video.CreateSessionOptions) (*video.Session, error) {
+	return acceptMock(c.GetOrCreateSession(context.Background(), spotID, opts))
+}
+
+// CleanupExpiredSessions removes expired sessions using the original
+// signature without a context.
+//
+// Deprecated: Use video.Client.CleanupExpiredSessions.
+func CleanupExpiredSessions(c *video.Client) int {
+	return c.CleanupExpiredSessions(context.Background())
+}
+
+// CachedSessionCount returns the number of cached sessions using the
+// original signature without a context.
+//
+// Deprecated: Use video.Client.CachedSessionCount.
+func CachedSessionCount(c *video.Client) int {
+	return c.CachedSessionCount(context.Background())
+}
+
+// acceptMock drops the *video.MockSessionError that comes with mock
+// sessions, which the original signatures returned as if they were real
+func acceptMock(session *video.Session, err error) (*video.Session, error) {
+	if errors.Is(err, video.ErrMockSession) {
+		return session, nil
+	}
+	return session, err
+}
@@ -0,0 +1,52 @@
+package compat
+
+import (
+	"context"
+
+	"github.com/vonatrigger/poc/pkg/vonage/voice"
+)
+
+// ========================================
+// In-call operations
+// ========================================
+
+// TalkIntoCall sends a TTS message into an active call using the original
+// positional signature.
+//
+// Deprecated: Use voice.Client.TalkIntoCall.
+func TalkIntoCall(ctx context.Context, c *voice.Client, callUUID, text, voiceName string, loop int) error {
+	return c.TalkIntoCall(ctx, callUUID, voice.TalkIntoCallOptions{
+		Text:      text,
+		VoiceName: voiceName,
+		Loop:      voice.LoopTimes(loop),
+	})
+}
+
+// StreamIntoCall streams audio into an active call using the original
+// positional signature.
+//
+// Deprecated: Use voice.Client.StreamIntoCall.
+func StreamIntoCall(ctx context.Context, c *voice.Client, callUUID, streamURL string, loop int) error {
+	return c.StreamIntoCall(ctx, callUUID, voice.StreamIntoCallOptions{
+		StreamURL: streamURL,
+		Loop:      voice.LoopTimes(loop),
+	})
+}
+
+// ========================================
+// NCCO builder setters
+// ========================================
+
+// TalkLevel sets the talk volume level using the original int signature.
+//
+// Deprecated: Use voice.TalkBuilder.Level.
+func TalkLevel(t *voice.TalkBuilder, level int) *voice.TalkBuilder {
+	return t.Level(float64(level))
+}
+
+// StreamLevel sets the stream volume level using the original int signature.
+//
+// Deprecated: Use voice.StreamBuilder.Level.
+func StreamLevel(s *voice.StreamBuilder, level int) *voice.StreamBuilder {
+	return s.Level(float64(level))
+}
@@ -0,0 +1,190 @@
+// Package devtools helps debug webhook-driven flows locally. Capture
+// records the webhooks Vonage sends to disk, and Replayer posts them again
+// to a local handler, so a call or message flow can be stepped through
+// offline, as often as needed, without placing new calls.
+//
+// Captured files hold customer data such as phone numbers and message
+// text; keep them out of version control.
+package devtools
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// Capture
+// ========================================
+
+// DefaultCapturedHeaders are the request headers kept with each webhook.
+// Authorization is left out: signed webhook JWTs expire, so replays skip
+// signature verification anyway.
+var DefaultCapturedHeaders = []string{"Content-Type", "User-Agent"}
+
+// CapturedWebhook is one webhook request as received
+type CapturedWebhook struct {
+	// Seq numbers the webhooks of a capture file from 1
+	Seq        int               `json:"seq"`
+	ReceivedAt time.Time         `json:"received_at"`
+	Method     string            `json:"method"`
+	Path       string            `json:"path"`
+	Query      string            `json:"query,omitempty"`
+	Header     map[string]string `json:"header,omitempty"`
+	Body       string            `json:"body,omitempty"`
+}
+
+// Capture is an http.Handler that appends every request to a JSON Lines
+// file before passing it to the wrapped handler. It is safe for
+// concurrent use.
+type Capture struct {
+	next    http.Handler
+	headers []string
+	logger  vonage.Logger
+
+	mu   sync.Mutex
+	file *os.File
+	seq  int
+}
+
+// CaptureOption is a functional option for configuring a Capture
+type CaptureOption func(*Capture)
+
+// WithCapturedHeaders sets the request headers kept (default
+// DefaultCapturedHeaders)
+func WithCapturedHeaders(names ...string) CaptureOption {
+	return func(c *Capture) {
+		c.headers = names
+	}
+}
+
+// WithCaptureLogger sets the logger (default: no logging)
+func WithCaptureLogger(l vonage.Logger) CaptureOption {
+	return func(c *Capture) {
+		c.logger = l
+	}
+}
+
+// NewCapture records webhooks to the file at path, appending to it if it
+// exists, and passes them to next. A nil next answers 204 No Content, for
+// capturing without a handler running.
+func NewCapture(path string, next http.Handler, opts ...CaptureOption) (*Capture, error) {
+	c := &Capture{
+		next:    next,
+		headers: DefaultCapturedHeaders,
+		logger:  vonage.NopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	existing, err := Load(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	c.seq = len(existing)
+
+	c.file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("devtools: failed to open capture file: %w", err)
+	}
+	return c, nil
+}
+
+// ServeHTTP records the request and serves it with the wrapped handler.
+// A failure to record is reported but does not stop the webhook.
+func (c *Capture) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	hook := CapturedWebhook{
+		ReceivedAt: time.Now().UTC(),
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Query:      r.URL.RawQuery,
+		Body:       string(body),
+	}
+	for _, name := range c.headers {
+		if v := r.Header.Get(name); v != "" {
+			if hook.Header == nil {
+				hook.Header = make(map[string]string)
+			}
+			hook.Header[name] = v
+		}
+	}
+	if err := c.append(&hook); err != nil {
+		c.logger.Error("Failed to capture webhook", "path", r.URL.Path, "error", err)
+	} else {
+		c.logger.Debug("Captured webhook", "seq", hook.Seq, "path", r.URL.Path)
+	}
+
+	if c.next == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	c.next.ServeHTTP(w, r)
+}
+
+// Close closes the capture file
+func (c *Capture) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.file.Close()
+}
+
+// append numbers and writes one webhook
+func (c *Capture) append(hook *CapturedWebhook) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.seq++
+	hook.Seq = c.seq
+	line, err := json.Marshal(hook)
+	if err != nil {
+		return fmt.Errorf("devtools: failed to encode webhook: %w", err)
+	}
+	if _, err := c.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("devtools: failed to write webhook: %w", err)
+	}
+	return nil
+}
+
+// Load reads the webhooks of a capture file in the order they arrived
+func Load(path string) ([]CapturedWebhook, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var hooks []CapturedWebhook
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var hook CapturedWebhook
+		if err := json.Unmarshal(scanner.Bytes(), &hook); err != nil {
+			return nil, fmt.Errorf("devtools: %s line %d: %w", path, line, err)
+		}
+		hooks = append(hooks, hook)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("devtools: failed to read %s: %w", path, err)
+	}
+	return hooks, nil
+}
@@ -0,0 +1,55 @@
+package devtools_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vonatrigger/poc/pkg/vonage/devtools"
+)
+
+func Example() {
+	dir, _ := os.MkdirTemp("", "webhooks")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "call.jsonl")
+
+	// While developing, wrap the webhook handler: what Vonage sends is
+	// recorded and still served
+	capture, err := devtools.NewCapture(path, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, status := range []string{"ringing", "answered", "completed"} {
+		body := `{"uuid":"CALL-1","status":"` + status + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/event", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		capture.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	capture.Close()
+
+	// Later, offline: replay the call into the handler being debugged
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webhooks/event", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fmt.Println(string(body))
+	})
+	replayer := devtools.NewReplayer("",
+		devtools.WithReplayHandler(mux),
+		devtools.WithSpeed(0),
+		devtools.WithReplayFilter(func(h *devtools.CapturedWebhook) bool {
+			return !strings.Contains(h.Body, `"ringing"`)
+		}),
+	)
+	if err := replayer.ReplayFile(context.Background(), path); err != nil {
+		log.Fatal(err)
+	}
+	// Output:
+	// {"uuid":"CALL-1","status":"answered"}
+	// {"uuid":"CALL-1","status":"completed"}
+}
@@ -0,0 +1,157 @@
+package devtools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// Replay
+// ========================================
+
+// Replayer posts captured webhooks to a local server or handler in their
+// original order, with the original gaps between them scaled by a speed
+// factor
+type Replayer struct {
+	baseURL    string
+	handler    http.Handler
+	httpClient *http.Client
+	speed      float64
+	filter     func(*CapturedWebhook) bool
+	logger     vonage.Logger
+}
+
+// ReplayerOption is a functional option for configuring a Replayer
+type ReplayerOption func(*Replayer)
+
+// WithReplayHandler serves the webhooks with h in-process instead of
+// posting them to the base URL
+func WithReplayHandler(h http.Handler) ReplayerOption {
+	return func(r *Replayer) {
+		r.handler = h
+	}
+}
+
+// WithReplayHTTPClient sets the HTTP client used to post webhooks
+func WithReplayHTTPClient(httpClient *http.Client) ReplayerOption {
+	return func(r *Replayer) {
+		r.httpClient = httpClient
+	}
+}
+
+// WithSpeed scales the time between webhooks: 1 replays in real time (the
+// default), 10 ten times faster and 0 without waiting
+func WithSpeed(factor float64) ReplayerOption {
+	return func(r *Replayer) {
+		r.speed = factor
+	}
+}
+
+// WithReplayFilter replays only the webhooks fn returns true for, e.g.
+// those of one call
+func WithReplayFilter(fn func(*CapturedWebhook) bool) ReplayerOption {
+	return func(r *Replayer) {
+		r.filter = fn
+	}
+}
+
+// WithReplayLogger sets the logger (default: no logging)
+func WithReplayLogger(l vonage.Logger) ReplayerOption {
+	return func(r *Replayer) {
+		r.logger = l
+	}
+}
+
+// NewReplayer creates a replayer posting to baseURL, e.g.
+// "http://localhost:8080", followed by each webhook's path
+func NewReplayer(baseURL string, opts ...ReplayerOption) *Replayer {
+	r := &Replayer{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: vonage.DefaultTimeout},
+		speed:      1,
+		logger:     vonage.NopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Replay posts hooks in order and stops at the first that fails or is
+// answered with a non-2xx status
+func (r *Replayer) Replay(ctx context.Context, hooks []CapturedWebhook) error {
+	var last time.Time
+	for i := range hooks {
+		hook := &hooks[i]
+		if r.filter != nil && !r.filter(hook) {
+			continue
+		}
+
+		if !last.IsZero() && r.speed > 0 {
+			gap := time.Duration(float64(hook.ReceivedAt.Sub(last)) / r.speed)
+			if gap > 0 {
+				select {
+				case <-time.After(gap):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+		last = hook.ReceivedAt
+
+		status, err := r.send(ctx, hook)
+		if err != nil {
+			return fmt.Errorf("devtools: replaying webhook %d %s: %w", hook.Seq, hook.Path, err)
+		}
+		if status < 200 || status >= 300 {
+			return fmt.Errorf("devtools: replaying webhook %d %s: status %d", hook.Seq, hook.Path, status)
+		}
+		r.logger.Debug("Replayed webhook", "seq", hook.Seq, "path", hook.Path, "status", status)
+	}
+	return nil
+}
+
+// ReplayFile loads a capture file and replays it
+func (r *Replayer) ReplayFile(ctx context.Context, path string) error {
+	hooks, err := Load(path)
+	if err != nil {
+		return err
+	}
+	return r.Replay(ctx, hooks)
+}
+
+// send delivers one webhook and returns the response status
+func (r *Replayer) send(ctx context.Context, hook *CapturedWebhook) (int, error) {
+	target := r.baseURL + hook.Path
+	if hook.Query != "" {
+		target += "?" + hook.Query
+	}
+	req, err := http.NewRequestWithContext(ctx, hook.Method, target, strings.NewReader(hook.Body))
+	if err != nil {
+		return 0, err
+	}
+	for name, v := range hook.Header {
+		req.Header.Set(name, v)
+	}
+
+	if r.handler != nil {
+		rec := httptest.NewRecorder()
+		r.handler.ServeHTTP(rec, req)
+		return rec.Code, nil
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,273 @@
+package vonage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ========================================
+// Download
+// ========================================
+
+// downloadBufferSize is the size of the chunks a download is copied in, and
+// so how often progress is reported
+const downloadBufferSize = 32 << 10
+
+// DefaultResumeBackoff is the default delay before resuming an interrupted
+// download
+const DefaultResumeBackoff = time.Second
+
+// ErrChecksumMismatch is returned when a download does not match the
+// checksum set with WithChecksum
+var ErrChecksumMismatch = errors.New("vonage: checksum mismatch")
+
+// DownloadProgress is the state of a download, reported to a ProgressFunc
+type DownloadProgress struct {
+	// Offset is the byte offset the download started at
+	Offset int64
+	// Written is the number of bytes written to the writer so far
+	Written int64
+	// Total is the size of the whole file, or -1 if the server did not
+	// send it
+	Total int64
+	// Resumes is how often the download was resumed after an interruption
+	Resumes int
+}
+
+// Percent returns how much of the file has been downloaded, counting the
+// Offset, or -1 if the total is unknown
+func (p DownloadProgress) Percent() float64 {
+	if p.Total <= 0 {
+		return -1
+	}
+	return float64(p.Offset+p.Written) * 100 / float64(p.Total)
+}
+
+// ProgressFunc is called as a download writes each chunk
+type ProgressFunc func(p DownloadProgress)
+
+// DownloadOption is a functional option for a download
+type DownloadOption func(*downloadSettings)
+
+type downloadSettings struct {
+	progress ProgressFunc
+	resumes  int
+	backoff  time.Duration
+	hash     hash.Hash
+	want     string
+}
+
+// WithProgress calls fn after every chunk written, e.g. to drive a
+// progress bar for a large recording or archive
+func WithProgress(fn ProgressFunc) DownloadOption {
+	return func(s *downloadSettings) {
+		s.progress = fn
+	}
+}
+
+// WithResume resumes a download whose connection breaks mid-body up to
+// attempts times, with a Range request from the last byte written, after
+// waiting backoff (default DefaultResumeBackoff). Failures before the body
+// starts are left to the transport's retry policy.
+func WithResume(attempts int, backoff time.Duration) DownloadOption {
+	return func(s *downloadSettings) {
+		s.resumes = attempts
+		s.backoff = backoff
+	}
+}
+
+// WithChecksum verifies the downloaded bytes against want, the
+// hex-encoded sum of h, and fails with ErrChecksumMismatch if they differ.
+// Only the bytes written by this download are hashed; when resuming a
+// partial file from an offset, write its existing content to h first.
+func WithChecksum(h hash.Hash, want string) DownloadOption {
+	return func(s *downloadSettings) {
+		s.hash = h
+		s.want = strings.ToLower(want)
+	}
+}
+
+// WithSHA256 verifies the download against a hex-encoded SHA-256 sum
+func WithSHA256(want string) DownloadOption {
+	return WithChecksum(sha256.New(), want)
+}
+
+// interruptedError is a download that broke off while reading the body,
+// which can be resumed
+type interruptedError struct {
+	err error
+}
+
+func (e *interruptedError) Error() string {
+	return fmt.Sprintf("download failed: %v", e.err)
+}
+
+func (e *interruptedError) Unwrap() error {
+	return e.err
+}
+
+// Download streams the body of a GET request to w, returning the number of
+// bytes written. It is meant for recordings and media, so the body is not
+// subject to MaxResponseBytes and is never buffered whole; use
+// WithRequestTimeout for large files.
+func (t *Transport) Download(ctx context.Context, path string, w io.Writer, opts ...DownloadOption) (int64, error) {
+	return t.DownloadFrom(ctx, path, 0, w, opts...)
+}
+
+// DownloadFrom is like Download but starts at byte offset, so an
+// interrupted download can be resumed. If the server ignores the Range
+// request, the first offset bytes are skipped locally.
+func (t *Transport) DownloadFrom(ctx context.Context, path string, offset int64, w io.Writer, opts ...DownloadOption) (int64, error) {
+	s := downloadSettings{backoff: DefaultResumeBackoff}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	// Fail before any resume or Range request rather than on each one
+	if err := t.checkTarget(path); err != nil {
+		return 0, err
+	}
+
+	out := w
+	if s.hash != nil {
+		out = io.MultiWriter(w, s.hash)
+	}
+
+	progress := DownloadProgress{Offset: offset, Total: -1}
+	for {
+		err := t.download(ctx, path, out, &progress, s.progress)
+		if err == nil {
+			break
+		}
+		var interrupted *interruptedError
+		if !errors.As(err, &interrupted) || progress.Resumes >= s.resumes || ctx.Err() != nil {
+			return progress.Written, err
+		}
+
+		progress.Resumes++
+		LoggerFromContext(ctx, t.logger).Warn("Vonage download interrupted, resuming",
+			"path", path,
+			"offset", progress.Offset+progress.Written,
+			"attempt", progress.Resumes,
+			"error", interrupted.err,
+		)
+		select {
+		case <-time.After(s.backoff):
+		case <-ctx.Done():
+			return progress.Written, ctx.Err()
+		}
+	}
+
+	if s.hash != nil {
+		if got := hex.EncodeToString(s.hash.Sum(nil)); got != s.want {
+			return progress.Written, fmt.Errorf("%w: got %s, want %s", ErrChecksumMismatch, got, s.want)
+		}
+	}
+	return progress.Written, nil
+}
+
+// checkTarget fails with ErrUntrustedHost if path is an absolute URL the
+// transport would not send its credentials to
+func (t *Transport) checkTarget(path string) error {
+	if t.auth == nil || t.mode == ModeMock || !strings.Contains(path, "://") {
+		return nil
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		return fmt.Errorf("invalid download URL: %w", err)
+	}
+	if !t.trusted(u) {
+		return fmt.Errorf("%w: %s", ErrUntrustedHost, u.Host)
+	}
+	return nil
+}
+
+// download makes one request for the bytes after those already written,
+// copying them to w and updating progress
+func (t *Transport) download(ctx context.Context, path string, w io.Writer, progress *DownloadProgress, fn ProgressFunc) error {
+	ctx, cancel, httpClient := t.withTimeout(ctx)
+	defer cancel()
+
+	req, err := t.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "*/*")
+	offset := progress.Offset + progress.Written
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := t.send(httpClient, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, bodySnippetBytes))
+		LoggerFromContext(ctx, t.logger).Error("Vonage download error",
+			"url", logURL(req.URL),
+			"status", resp.StatusCode,
+			"body", string(respBody),
+		)
+		return NewError(resp.StatusCode, string(respBody))
+	}
+
+	if resp.StatusCode == http.StatusPartialContent {
+		progress.Total = contentRangeTotal(resp.Header.Get("Content-Range"))
+		if progress.Total < 0 && resp.ContentLength >= 0 {
+			progress.Total = offset + resp.ContentLength
+		}
+	} else {
+		progress.Total = resp.ContentLength
+		if offset > 0 {
+			if _, err := io.CopyN(io.Discard, resp.Body, offset); err != nil {
+				return &interruptedError{err: err}
+			}
+		}
+	}
+
+	buf := make([]byte, downloadBufferSize)
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return fmt.Errorf("download failed: %w", err)
+			}
+			progress.Written += int64(n)
+			if fn != nil {
+				fn(*progress)
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return &interruptedError{err: readErr}
+		}
+	}
+}
+
+// contentRangeTotal returns the complete length from a Content-Range
+// header such as "bytes 100-199/2000", or -1 if it is unknown
+func contentRangeTotal(v string) int64 {
+	i := strings.LastIndexByte(v, '/')
+	if i < 0 {
+		return -1
+	}
+	total, err := strconv.ParseInt(v[i+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return total
+}
@@ -0,0 +1,119 @@
+package vonage
+
+import "strings"
+
+// ========================================
+// Endpoints
+// ========================================
+
+// Base URLs of the Vonage APIs besides BaseURLREST and BaseURLVideo
+const (
+	// BaseURLLegacy is the host of the older REST APIs, numbers and account
+	BaseURLLegacy = "https://rest.nexmo.com"
+	// BaseURLRegional is the host of the APIs served from a region, such as
+	// the Network APIs, Proactive Connect and Meetings
+	BaseURLRegional = "https://api-eu.vonage.com"
+	// BaseURLNetworkAuth is the OpenID Connect provider of the Network APIs
+	BaseURLNetworkAuth = "https://oidc.idp.vonage.com"
+	// BaseURLMessagesSandbox is the Messages API sandbox, which delivers
+	// WhatsApp, Viber and Messenger messages to allowlisted test accounts
+	BaseURLMessagesSandbox = "https://messages-sandbox.nexmo.com"
+)
+
+// Region is a Vonage API region
+type Region string
+
+const (
+	RegionEU   Region = "eu"
+	RegionUS   Region = "us"
+	RegionAPAC Region = "ap"
+)
+
+// Endpoints are the base URLs the sub-clients of a Client send to. Set it
+// with WithEndpoints to route every API to a test server, a region or the
+// sandbox in one place. Empty fields use the defaults of DefaultEndpoints.
+type Endpoints struct {
+	// REST is the host of most APIs: voice, messages, verify, users, media,
+	// reports and WhatsApp templates
+	REST string
+	// Messages overrides REST for the Messages API, e.g. for the sandbox
+	Messages string
+	// Legacy is the host of the numbers and account APIs
+	Legacy string
+	// Video is the Video API host
+	Video string
+	// Regional is the host of the Network APIs and Proactive Connect
+	Regional string
+	// Meetings is the Meetings API host
+	Meetings string
+	// NetworkAuth is the OpenID Connect provider of the Network APIs
+	NetworkAuth string
+}
+
+// DefaultEndpoints returns the production base URLs
+func DefaultEndpoints() Endpoints {
+	return Endpoints{
+		REST:        BaseURLREST,
+		Messages:    BaseURLREST,
+		Legacy:      BaseURLLegacy,
+		Video:       BaseURLVideo,
+		Regional:    BaseURLRegional,
+		Meetings:    BaseURLRegional,
+		NetworkAuth: BaseURLNetworkAuth,
+	}
+}
+
+// SandboxEndpoints returns the production base URLs with the Messages API
+// sandbox
+func SandboxEndpoints() Endpoints {
+	e := DefaultEndpoints()
+	e.Messages = BaseURLMessagesSandbox
+	return e
+}
+
+// RegionalEndpoints returns the base URLs that pin the REST and regional
+// APIs to region, e.g. to keep data in the EU
+func RegionalEndpoints(region Region) Endpoints {
+	host := "https://api-" + string(region) + ".vonage.com"
+	e := DefaultEndpoints()
+	e.REST, e.Messages, e.Regional, e.Meetings = host, host, host, host
+	return e
+}
+
+// TestEndpoints returns endpoints that send every API to baseURL, such as
+// an httptest.Server or vonagetest.Server
+func TestEndpoints(baseURL string) Endpoints {
+	return Endpoints{
+		REST:        baseURL,
+		Messages:    baseURL,
+		Legacy:      baseURL,
+		Video:       baseURL,
+		Regional:    baseURL,
+		Meetings:    baseURL,
+		NetworkAuth: baseURL,
+	}
+}
+
+// Resolve returns e with empty fields set to their defaults and trailing
+// slashes removed. An empty Messages follows REST.
+func (e Endpoints) Resolve() Endpoints {
+	def := DefaultEndpoints()
+	if e.Messages == "" && e.REST != "" {
+		def.Messages = e.REST
+	}
+	resolve := func(v, d string) string {
+		if v == "" {
+			return d
+		}
+		return strings.TrimSuffix(v, "/")
+	}
+	return Endpoints{
+		REST:        resolve(e.REST, def.REST),
+		Messages:    resolve(e.Messages, def.Messages),
+		Legacy:      resolve(e.Legacy, def.Legacy),
+		Video:       resolve(e.Video, def.Video),
+		Regional:    resolve(e.Regional, def.Regional),
+		Meetings:    resolve(e.Meetings, def.Meetings),
+		NetworkAuth: resolve(e.NetworkAuth, def.NetworkAuth),
+	}
+}
@@ -1,18 +1,42 @@
 package vonage
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
-// Error represents a Vonage API error
+// Error represents a Vonage API error. Bodies in RFC 7807 problem+json
+// format are parsed into the Type, Title, Detail, Instance and
+// InvalidParameters fields.
 type Error struct {
-	StatusCode int
-	Type       string
-	Title      string
-	Detail     string
-	Instance   string
-	Raw        string
+	StatusCode        int
+	Type              string
+	Title             string
+	Detail            string
+	Instance          string
+	InvalidParameters []FieldError
+	Raw               string
+
+	// RetryAfter is the wait requested by a Retry-After header, if any
+	RetryAfter time.Duration
+}
+
+// FieldError describes a request parameter rejected by the API
+type FieldError struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// problem is the RFC 7807 problem+json body returned by Vonage APIs
+type problem struct {
+	Type              string       `json:"type"`
+	Title             string       `json:"title"`
+	Detail            string       `json:"detail"`
+	Instance          string       `json:"instance"`
+	InvalidParameters []FieldError `json:"invalid_parameters"`
 }
 
 func (e *Error) Error() string {
@@ -20,7 +44,11 @@ func (e *Error) Error() string {
 		return fmt.Sprintf("vonage: %s - %s (status: %d)", e.Title, e.Detail, e.StatusCode)
 	}
 	if e.Raw != "" {
-		return fmt.Sprintf("vonage: status %d - %s", e.StatusCode, e.Raw)
+		raw := e.Raw
+		if len(raw) > bodySnippetBytes {
+			raw = raw[:bodySnippetBytes] + "..."
+		}
+		return fmt.Sprintf("vonage: status %d - %s", e.StatusCode, raw)
 	}
 	return fmt.Sprintf("vonage: status %d", e.StatusCode)
 }
@@ -45,14 +73,55 @@ func (e *Error) IsRateLimited() bool {
 	return e.StatusCode == http.StatusTooManyRequests
 }
 
-// NewError creates a new Vonage error
+// Is reports whether the error belongs to one of the sentinel categories
+// ErrRateLimited, ErrInvalidRequest or ErrAuth, so callers can use
+// errors.Is(err, vonage.ErrRateLimited)
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.IsRateLimited()
+	case ErrInvalidRequest:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrAuth:
+		return e.IsUnauthorized() || e.IsForbidden()
+	}
+	return false
+}
+
+// NewError creates a new Vonage error, parsing an RFC 7807 body if present
 func NewError(statusCode int, body string) *Error {
-	return &Error{
+	e := &Error{
 		StatusCode: statusCode,
 		Raw:        body,
 	}
+
+	var p problem
+	if json.Unmarshal([]byte(body), &p) == nil {
+		e.Type = p.Type
+		e.Title = p.Title
+		e.Detail = p.Detail
+		e.Instance = p.Instance
+		e.InvalidParameters = p.InvalidParameters
+	}
+	return e
+}
+
+// AsError returns the *Error in err's chain, if any
+func AsError(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
 }
 
+// Error categories matched by (*Error).Is
+var (
+	ErrRateLimited    = errors.New("vonage: rate limited")
+	ErrInvalidRequest = errors.New("vonage: invalid request")
+	ErrAuth           = errors.New("vonage: authentication failed")
+)
+
 // Common errors
 var (
 	ErrNotConfigured     = fmt.Errorf("vonage: credentials not configured")
@@ -0,0 +1,158 @@
+package vonage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ========================================
+// Event Sinks
+// ========================================
+
+// Event sources
+const (
+	EventSourceVoice    = "voice"
+	EventSourceMessages = "messages"
+	EventSourceVideo    = "video"
+	EventSourceVerify   = "verify"
+	EventSourceRTC      = "rtc"
+)
+
+// Event is a webhook event published to an EventSink
+type Event struct {
+	// Source is the API the event came from, e.g. EventSourceVoice
+	Source string `json:"source"`
+	// Type identifies the webhook, e.g. "voice.event" or "messages.status"
+	Type string `json:"type"`
+	// Key groups related events, e.g. a conversation or message UUID. Sinks
+	// use it as the partition or subject key to keep related events in
+	// order.
+	Key string `json:"key,omitempty"`
+	// Time is when the webhook was received
+	Time time.Time `json:"time"`
+	// Data is the webhook body
+	Data json.RawMessage `json:"data"`
+	// Payload is the parsed webhook, e.g. *messages.MessageStatus, when
+	// published by a typed handler. It is not serialized.
+	Payload interface{} `json:"-"`
+}
+
+// EventSink receives webhook events, decoupling downstream processing from
+// the HTTP handlers. Publish is called synchronously from the handler, so
+// sinks that forward over the network should be quick or buffer.
+type EventSink interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// EventSinkFunc adapts a function to EventSink
+type EventSinkFunc func(ctx context.Context, event Event) error
+
+// Publish implements EventSink
+func (f EventSinkFunc) Publish(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// FanOut publishes each event to every sink and returns their joined
+// errors
+func FanOut(sinks ...EventSink) EventSink {
+	return EventSinkFunc(func(ctx context.Context, event Event) error {
+		var errs []error
+		for _, s := range sinks {
+			if err := s.Publish(ctx, event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// ChannelSink publishes events to a Go channel for in-process consumers
+type ChannelSink struct {
+	ch       chan<- Event
+	dropping bool
+}
+
+// ErrEventDropped is returned by a dropping ChannelSink whose channel is
+// full
+var ErrEventDropped = errors.New("vonage: event dropped, channel full")
+
+// NewChannelSink creates a sink that sends events to ch, blocking until
+// the channel has room or the request context is done
+func NewChannelSink(ch chan<- Event) *ChannelSink {
+	return &ChannelSink{ch: ch}
+}
+
+// NewDroppingChannelSink is like NewChannelSink but returns ErrEventDropped
+// instead of blocking when ch is full, so a slow consumer cannot delay
+// webhook responses
+func NewDroppingChannelSink(ch chan<- Event) *ChannelSink {
+	return &ChannelSink{ch: ch, dropping: true}
+}
+
+// Publish implements EventSink
+func (s *ChannelSink) Publish(ctx context.Context, event Event) error {
+	if s.dropping {
+		select {
+		case s.ch <- event:
+			return nil
+		default:
+			return ErrEventDropped
+		}
+	}
+
+	select {
+	case s.ch <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// EventKey returns the first of the identifiers Vonage webhooks carry
+// (conversation, message, session or call UUID) found in a JSON body
+func EventKey(body []byte) string {
+	var ids struct {
+		ConversationUUID string `json:"conversation_uuid"`
+		MessageUUID      string `json:"message_uuid"`
+		SessionID        string `json:"sessionId"`
+		UUID             string `json:"uuid"`
+	}
+	if err := json.Unmarshal(body, &ids); err != nil {
+		return ""
+	}
+	for _, id := range []string{ids.ConversationUUID, ids.MessageUUID, ids.SessionID, ids.UUID} {
+		if id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// NewEvent returns an event for a webhook body received now, keyed with
+// EventKey
+func NewEvent(source, typ string, body []byte) Event {
+	event := Event{
+		Source: source,
+		Type:   typ,
+		Key:    EventKey(body),
+		Time:   time.Now(),
+	}
+	if json.Valid(body) {
+		event.Data = json.RawMessage(body)
+	}
+	return event
+}
+
+// PublishEvent publishes event to sink, logging a failure rather than
+// returning it, since webhooks are acknowledged regardless
+func PublishEvent(ctx context.Context, sink EventSink, logger Logger, event Event) {
+	if err := sink.Publish(ctx, event); err != nil {
+		LoggerFromContext(ctx, logger).Error("Failed to publish webhook event",
+			"type", event.Type,
+			"key", event.Key,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,21 @@
+module github.com/vonatrigger/poc/pkg/vonage/eventsink/kafkasink
+
+go 1.22
+
+require (
+	github.com/segmentio/kafka-go v0.4.47
+	github.com/vonatrigger/poc v0.0.0
+)
+
+require (
+	github.com/golang-jwt/jwt/v5 v5.2.1 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/klauspost/compress v1.17.9 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+	github.com/rs/zerolog v1.33.0 // indirect
+	golang.org/x/sys v0.27.0 // indirect
+)
+
+replace github.com/vonatrigger/poc => ../../../..
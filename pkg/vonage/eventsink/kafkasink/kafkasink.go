@@ -0,0 +1,73 @@
+// Package kafkasink publishes Vonage webhook events to Kafka. It is a
+// separate module so the SDK does not depend on the Kafka client.
+package kafkasink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// Sink is a vonage.EventSink that writes each event as a JSON message keyed
+// by the event key, so events of one conversation or message stay in order
+// on one partition
+type Sink struct {
+	writer *kafka.Writer
+	topic  func(event vonage.Event) string
+}
+
+// Option is a functional option for configuring a Sink
+type Option func(*Sink)
+
+// WithTopicFunc routes events to topics, e.g. one per source. The writer
+// must then have no Topic of its own.
+func WithTopicFunc(fn func(event vonage.Event) string) Option {
+	return func(s *Sink) {
+		s.topic = fn
+	}
+}
+
+// New creates a sink writing with w. The caller owns w and closes it.
+// Configure w.Async or w.BatchTimeout so webhook handlers are not held up
+// waiting for batches.
+func New(w *kafka.Writer, opts ...Option) *Sink {
+	s := &Sink{writer: w}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+var _ vonage.EventSink = (*Sink)(nil)
+
+// Publish implements vonage.EventSink. The event source and type are sent
+// as headers.
+func (s *Sink) Publish(ctx context.Context, event vonage.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("kafkasink: failed to encode event: %w", err)
+	}
+
+	msg := kafka.Message{
+		Key:   []byte(event.Key),
+		Value: data,
+		Time:  event.Time,
+		Headers: []kafka.Header{
+			{Key: "vonage-source", Value: []byte(event.Source)},
+			{Key: "vonage-type", Value: []byte(event.Type)},
+		},
+	}
+	if s.topic != nil {
+		msg.Topic = s.topic(event)
+	}
+
+	if err := s.writer.WriteMessages(ctx, msg); err != nil {
+		return fmt.Errorf("kafkasink: failed to write %s event: %w", event.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+module github.com/vonatrigger/poc/pkg/vonage/eventsink/natssink
+
+go 1.22.0
+
+require (
+	github.com/nats-io/nats.go v1.39.1
+	github.com/vonatrigger/poc v0.0.0
+)
+
+require (
+	github.com/golang-jwt/jwt/v5 v5.2.1 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/klauspost/compress v1.17.9 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/nats-io/nkeys v0.4.9 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	github.com/rs/zerolog v1.33.0 // indirect
+	golang.org/x/crypto v0.31.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+)
+
+replace github.com/vonatrigger/poc => ../../../..
@@ -0,0 +1,82 @@
+// Package natssink publishes Vonage webhook events to NATS. It is a
+// separate module so the SDK does not depend on the NATS client.
+package natssink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// DefaultSubjectPrefix is the subject prefix events are published under
+const DefaultSubjectPrefix = "vonage"
+
+// Sink is a vonage.EventSink that publishes each event as JSON to the
+// subject "<prefix>.<type>", e.g. "vonage.messages.status"
+type Sink struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+// Option is a functional option for configuring a Sink
+type Option func(*Sink)
+
+// WithSubjectPrefix sets the subject prefix (default DefaultSubjectPrefix)
+func WithSubjectPrefix(prefix string) Option {
+	return func(s *Sink) {
+		s.prefix = strings.TrimSuffix(prefix, ".")
+	}
+}
+
+// New creates a sink publishing on conn. The caller owns conn.
+func New(conn *nats.Conn, opts ...Option) *Sink {
+	s := &Sink{
+		conn:   conn,
+		prefix: DefaultSubjectPrefix,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+var _ vonage.EventSink = (*Sink)(nil)
+
+// Publish implements vonage.EventSink. The event key is sent in the
+// Vonage-Event-Key header.
+func (s *Sink) Publish(ctx context.Context, event vonage.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("natssink: failed to encode event: %w", err)
+	}
+
+	msg := nats.NewMsg(s.Subject(event))
+	msg.Data = data
+	if event.Key != "" {
+		msg.Header.Set("Vonage-Event-Key", event.Key)
+	}
+
+	if err := s.conn.PublishMsg(msg); err != nil {
+		return fmt.Errorf("natssink: failed to publish %s: %w", msg.Subject, err)
+	}
+	return nil
+}
+
+// Subject returns the subject an event is published to. Characters NATS
+// reserves in subjects are replaced with underscores.
+func (s *Sink) Subject(event vonage.Event) string {
+	typ := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '*', '>', '/':
+			return '_'
+		}
+		return r
+	}, strings.Trim(event.Type, "./"))
+	return s.prefix + "." + typ
+}
@@ -0,0 +1,516 @@
+package vonage_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/messages"
+)
+
+func ExampleNewCredentialsFromEnv() {
+	// Reads VONAGE_APPLICATION_ID, VONAGE_PRIVATE_KEY_PATH (or inline
+	// VONAGE_PRIVATE_KEY), VONAGE_API_KEY/SECRET and VONAGE_NUMBER
+	creds, err := vonage.NewCredentialsFromEnv()
+	switch {
+	case errors.Is(err, vonage.ErrInvalidPrivateKey):
+		fmt.Println("Fix the private key:", err)
+	case errors.Is(err, vonage.ErrNotConfigured):
+		fmt.Println("Vonage is not configured")
+	case err == nil:
+		fmt.Println("Application:", creds.AppID)
+	}
+}
+
+func ExampleWithPrivateKeyFile() {
+	creds, err := vonage.NewCredentials(
+		vonage.WithApplication("app-id", ""),
+		vonage.WithPrivateKeyFile("private.key"),
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	_ = creds
+}
+
+func ExampleWithPrivateKeyPassphrase() {
+	// An encrypted key, e.g. from openssl pkcs8 -topk8 -v2 aes-256-cbc,
+	// possibly preceded by certificates in the same file
+	creds, err := vonage.NewCredentials(
+		vonage.WithApplication("app-id", ""),
+		vonage.WithPrivateKeyFile("private.key"),
+		vonage.WithPrivateKeyPassphrase(os.Getenv("VONAGE_PRIVATE_KEY_PASSPHRASE")),
+	)
+	switch {
+	case errors.Is(err, vonage.ErrEncryptedKey):
+		fmt.Println("Wrong or missing passphrase:", err)
+	case errors.Is(err, vonage.ErrNoKeyBlock):
+		fmt.Println("No private key in the file:", err)
+	case err != nil:
+		fmt.Println(err)
+	default:
+		_ = creds
+	}
+}
+
+func ExampleRotatingCredentials() {
+	creds, _ := vonage.NewCredentialsFromEnv()
+	rotating := vonage.NewRotatingCredentials(creds)
+	rotating.OnError = func(err error) {
+		log.Printf("keeping previous Vonage key: %v", err)
+	}
+
+	// Pick up a rotated private.key without restarting
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go rotating.WatchKeyFile(ctx, "/run/secrets/vonage/private.key", time.Minute)
+
+	// Every request made through the client's transports uses the
+	// current key
+	client := vonage.NewClient(creds, vonage.WithCredentialsProvider(rotating))
+	_ = client
+}
+
+func ExampleWithUserAgentSuffix() {
+	creds, _ := vonage.NewCredentialsFromEnv()
+
+	// Requests go out as "vonage-go-sdk/0.2.0 go/1.22.5 checkin-service/1.4"
+	// so Vonage support can find our traffic
+	client := vonage.NewClient(creds, vonage.WithUserAgentSuffix("checkin-service/1.4"))
+	_ = client
+}
+
+func ExampleJWTGenerator_GenerateUserJWT() {
+	creds, _ := vonage.NewCredentialsFromEnv()
+	jwtGen := vonage.NewJWTGeneratorFromCredentials(creds)
+
+	// Token for the Client SDK in the front desk tablet app. The user
+	// "staff-42" must already exist (see the users package).
+	token, err := jwtGen.GenerateUserJWT("staff-42", vonage.VoiceOnlyACL(), 8*time.Hour)
+	if err != nil {
+		log.Fatal(err)
+	}
+	_ = token
+}
+
+// printLogger prints the message and fields of error events
+type printLogger struct{ vonage.Logger }
+
+func (printLogger) Error(msg string, kv ...interface{}) { fmt.Println(msg, kv[:4]) }
+
+func ExampleContextWithLogFields() {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"title":"Bad Request"}`, http.StatusBadRequest)
+	}))
+	defer api.Close()
+
+	transport := vonage.NewTransport(api.URL, nil,
+		vonage.WithTransportLogger(printLogger{vonage.NopLogger()}),
+	)
+
+	// Tag every SDK log event for this game session
+	ctx := vonage.ContextWithLogFields(context.Background(),
+		"conversationId", "CON-123",
+		"tenantId", "arcade-7",
+	)
+	_ = transport.Do(ctx, http.MethodGet, "/v1/calls", nil, nil)
+	// Output: Vonage API error [conversationId CON-123 tenantId arcade-7]
+}
+
+func ExampleWebhookRouter() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithSignatureSecret("signature-secret", vonage.SignatureSHA256),
+	)
+
+	inbound := messages.NewWebhookHandler().
+		OnInbound(func(msg *messages.InboundMessage) error { return nil })
+
+	router := vonage.NewWebhookRouter(
+		vonage.WithWebhookPrefix("/webhooks"),
+		vonage.WithWebhookSignatureVerification(creds),
+	).
+		VoiceAnswer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[{"action":"talk","text":"Welcome"}]`))
+		})).
+		MessagesInbound(inbound.HandleInbound())
+
+	fmt.Println(router.Paths().VoiceAnswer)
+
+	// Unsigned requests are rejected before reaching the handlers
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhooks/voice/answer", nil))
+	fmt.Println(rec.Code)
+	// Output:
+	// /webhooks/voice/answer
+	// 401
+}
+
+func ExampleNewWebhookVerifier() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithSignatureSecret("signature-secret", vonage.SignatureSHA256),
+	)
+
+	verifier := vonage.NewWebhookVerifier(creds,
+		vonage.WithTimestampTolerance(vonage.DefaultWebhookTolerance),
+		vonage.WithReplayCache(vonage.NewMemoryReplayCache()),
+	)
+
+	// A legacy signed SMS webhook
+	params := map[string]string{
+		"msisdn":    "15551234567",
+		"text":      "JOIN",
+		"nonce":     "6b1b6f62-1b6c-4c9e",
+		"timestamp": strconv.FormatInt(time.Now().Unix(), 10),
+	}
+	params["sig"], _ = vonage.SignParams(params, "signature-secret", vonage.SignatureSHA256)
+	body, _ := json.Marshal(params)
+
+	fmt.Println(verifier.Verify("", body))
+
+	// The same webhook captured and sent again
+	err := verifier.Verify("", body)
+	fmt.Println(errors.Is(err, vonage.ErrWebhookReplayed))
+	// Output:
+	// <nil>
+	// true
+}
+
+// requestLogger prints the method, URL and body of info events
+type requestLogger struct{ vonage.Logger }
+
+func (requestLogger) Info(msg string, kv ...interface{}) { fmt.Println(msg, kv[3], kv[5], kv[9]) }
+
+func ExampleMode() {
+	// Usually set once with vonage.WithMode or VONAGE_MODE=dry-run
+	transport := vonage.NewTransport(vonage.BaseURLREST, nil,
+		vonage.WithTransportMode(vonage.ModeDryRun),
+		vonage.WithTransportLogger(requestLogger{vonage.NopLogger()}),
+	)
+
+	var call struct {
+		UUID string `json:"uuid"`
+	}
+	err := transport.Do(context.Background(), http.MethodPost, "/v1/calls",
+		map[string]string{"to": "15551234567"}, &call)
+	fmt.Println(err, call.UUID == "")
+	// Output:
+	// Vonage API request not sent POST https://api.nexmo.com/v1/calls {"to":"15551234567"}
+	// <nil> true
+}
+
+func ExampleWithWebhookEventSink() {
+	events := make(chan vonage.Event, 16)
+
+	router := vonage.NewWebhookRouter(
+		vonage.WithWebhookEventSink(vonage.NewDroppingChannelSink(events)),
+	).
+		VoiceEvent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	body := `{"uuid":"CALL-1","conversation_uuid":"CON-1","status":"answered"}`
+	router.ServeHTTP(httptest.NewRecorder(),
+		httptest.NewRequest(http.MethodPost, "/voice/event", strings.NewReader(body)))
+
+	// A downstream consumer, decoupled from the HTTP handler
+	event := <-events
+	fmt.Println(event.Source, event.Type, event.Key)
+	// Output: voice voice.event CON-1
+}
+
+func ExampleBudget() {
+	budget := vonage.NewBudget(vonage.BudgetLimits{
+		ChannelMessagesPerDay: map[string]int{"sms": 2},
+		SpendPerDay:           10,
+	}, vonage.WithEstimatedPrices(map[string]float64{"sms": 0.05}, 0.02))
+
+	// Configured on clients with messages.WithBudget and voice.WithBudget;
+	// each send reserves its usage
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		err := budget.Reserve(ctx, vonage.Usage{Channel: "sms", Messages: 1})
+		fmt.Println(err, errors.Is(err, vonage.ErrBudgetExceeded))
+	}
+
+	spend, _ := budget.Used(ctx, "spend")
+	fmt.Printf("%.2f\n", spend)
+	// Output:
+	// <nil> false
+	// <nil> false
+	// vonage: daily messages:sms budget exceeded (2 of 2) true
+	// 0.10
+}
+
+func ExamplePager() {
+	pages := map[string][]string{"": {"a", "b"}, "2": {"c", "d"}, "3": {"e"}}
+	next := map[string]string{"": "2", "2": "3"}
+
+	rateLimited := true
+	fetch := func(ctx context.Context, token string) ([]string, string, error) {
+		// The second page is rate limited once, then retried
+		if token == "2" && rateLimited {
+			rateLimited = false
+			return nil, "", vonage.NewError(http.StatusTooManyRequests, "")
+		}
+		return pages[token], next[token], nil
+	}
+
+	pager := vonage.NewPager(fetch,
+		vonage.WithPrefetch(2),
+		vonage.WithRateLimitRetry(3, time.Millisecond),
+	)
+	items, err := pager.All(context.Background())
+	fmt.Println(items, err)
+
+	_, err = pager.Next(context.Background())
+	fmt.Println(err)
+	// Output:
+	// [a b c d e] <nil>
+	// vonage: no more pages
+}
+
+// probe pings one path, standing in for a sub-client such as voice.Client
+type probe struct {
+	transport *vonage.Transport
+	service   string
+	path      string
+}
+
+func (p probe) Ping(ctx context.Context) *vonage.Health {
+	return p.transport.Ping(ctx, p.service, p.path)
+}
+
+func ExampleHealthHandler() {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/users" {
+			http.Error(w, `{"title":"Unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	}))
+	defer api.Close()
+	transport := vonage.NewTransport(api.URL, nil)
+
+	// sdk.Client.Ping probes every configured sub-client like this
+	check := func(ctx context.Context) []*vonage.Health {
+		return vonage.PingAll(ctx,
+			probe{transport, "voice", "/v1/calls?page_size=1"},
+			probe{transport, "users", "/v1/users?page_size=1"},
+		)
+	}
+
+	rec := httptest.NewRecorder()
+	vonage.HealthHandler(check).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	fmt.Println(rec.Code)
+
+	var results []struct {
+		Service string `json:"service"`
+		Healthy bool   `json:"healthy"`
+		AuthOK  bool   `json:"auth_ok"`
+	}
+	_ = json.NewDecoder(rec.Body).Decode(&results)
+	for _, h := range results {
+		fmt.Println(h.Service, h.Healthy, h.AuthOK)
+	}
+	// Output:
+	// 503
+	// voice true true
+	// users false false
+}
+
+// bodyLogger prints the body of debug events
+type bodyLogger struct{ vonage.Logger }
+
+func (bodyLogger) Debug(msg string, kv ...interface{}) { fmt.Println(msg, kv[5]) }
+
+func ExampleLogRequestBodies() {
+	transport := vonage.NewTransport(vonage.BaseURLREST, nil,
+		vonage.WithTransportMode(vonage.ModeDryRun),
+		vonage.WithMiddleware(vonage.LogRequestBodies(bodyLogger{vonage.NopLogger()}, nil)),
+	)
+	ctx := context.Background()
+
+	_ = transport.Do(ctx, http.MethodPost, "/v1/messages", map[string]string{
+		"message_type": "text",
+		"channel":      "sms",
+		"to":           "81901234567",
+		"from":         "MyGame",
+		"text":         "Your login code is 123456",
+	}, nil)
+	_ = transport.Do(ctx, http.MethodPost, "/sms/json", url.Values{
+		"api_key":    {"key"},
+		"api_secret": {"secret"},
+		"to":         {"81901234567"},
+	}, nil)
+	// Output:
+	// Vonage API request body {"channel":"sms","from":"MyGame","message_type":"text","text":"Your log…(+17 chars)","to":"819******67"}
+	// Vonage API request body api_key=key&api_secret=REDACTED&to=819%2A%2A%2A%2A%2A%2A67
+}
+
+func ExampleNgrokWebhookBase() {
+	// Stands in for the ngrok agent API on 127.0.0.1:4040
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tunnels":[{"public_url":"http://abc123.ngrok-free.app"},{"public_url":"https://abc123.ngrok-free.app"}]}`)
+	}))
+	defer agent.Close()
+
+	// Prefer the tunnel, fall back to configuration; resolve at most
+	// every 30 seconds
+	base := vonage.CachedWebhookBase(vonage.FirstWebhookBase(
+		vonage.NgrokWebhookBase(agent.URL),
+		vonage.StaticWebhookBase("https://game.example.com"),
+	), 30*time.Second)
+
+	answerURL, err := vonage.WebhookURL(context.Background(), base, "/webhooks/answer")
+	fmt.Println(answerURL, err)
+
+	agent.Close()
+	fallback, _ := vonage.FirstWebhookBase(
+		vonage.NgrokWebhookBase(agent.URL),
+		vonage.StaticWebhookBase("https://game.example.com"),
+	).WebhookBase(context.Background())
+	fmt.Println(fallback)
+	// Output:
+	// https://abc123.ngrok-free.app/webhooks/answer <nil>
+	// https://game.example.com
+}
+
+func ExampleWebhookCollector() {
+	metrics := vonage.NewWebhookCollector()
+
+	webhooks := messages.NewWebhookHandler().
+		WithMetrics(metrics).
+		OnInbound(func(msg *messages.InboundMessage) error {
+			if msg.Text == "" {
+				return errors.New("empty message")
+			}
+			return nil
+		})
+
+	mux := http.NewServeMux()
+	mux.Handle("/webhooks/inbound", webhooks.HandleInbound())
+	mux.Handle("/metrics", metrics)
+
+	for _, body := range []string{
+		`{"message_uuid":"m-1","channel":"sms","message_type":"text","text":"hi"}`,
+		`{"message_uuid":"m-2","channel":"sms","message_type":"text"}`,
+		`{"unexpected":true}`,
+	} {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/webhooks/inbound", strings.NewReader(body)))
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, line := range strings.Split(rec.Body.String(), "\n") {
+		// Latency histogram lines vary from run to run
+		if strings.Contains(line, "_total{") {
+			fmt.Println(line)
+		}
+	}
+	// Output:
+	// vonage_webhook_events_total{source="messages",type="messages.inbound"} 2
+	// vonage_webhook_handler_errors_total{source="messages",type="messages.inbound"} 1
+	// vonage_webhook_unknown_total{source="messages"} 1
+}
+
+func ExampleWithJWTAlgorithm() {
+	// Usually read from the application's private.key with
+	// WithApplication or WithPrivateKeyFile, which accept EC keys too
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	creds, err := vonage.NewCredentials(
+		vonage.WithApplication("your-app-id", ""),
+		vonage.WithPrivateKey(key),
+		vonage.WithJWTAlgorithm(vonage.JWTAlgorithmES256),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	gen := vonage.NewJWTGeneratorFromCredentials(creds)
+	token, err := gen.GenerateAPIJWT()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	header, _ := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+	fmt.Println(gen.Algorithm(), string(header))
+	// Output:
+	// ES256 {"alg":"ES256","typ":"JWT"}
+}
+
+func ExampleNewAuthChain() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, _, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		fmt.Printf("%s auth=%q api_key=%q\n", r.URL.Path, scheme, r.URL.Query().Get("api_key"))
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	// An account without a Voice/Messages application
+	creds, _ := vonage.NewCredentials(vonage.WithAPIKey("key", "secret"))
+
+	// JWT if an application is configured, else Basic
+	api := vonage.NewTransport(srv.URL, vonage.NewAuthChain(creds))
+	_ = api.Do(context.Background(), http.MethodGet, "/v1/messages", nil, nil)
+
+	// Legacy REST APIs take the key and secret as query parameters
+	legacy := vonage.NewTransport(srv.URL, vonage.NewAuthChain(creds, vonage.AuthJWT, vonage.AuthQuery))
+	_ = legacy.Do(context.Background(), http.MethodGet, "/account/get-balance", nil, nil)
+	// Output:
+	// /v1/messages auth="Basic" api_key=""
+	// /account/get-balance auth="" api_key="key"
+}
+
+func ExampleTransport_Download() {
+	recording := []byte(strings.Repeat("RIFF....WAVE", 4))
+	sum := sha256.Sum256(recording)
+
+	// The server drops the first connection halfway through the file
+	dropped := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Printf("GET Range: %q\n", r.Header.Get("Range"))
+		if !dropped {
+			dropped = true
+			w.Header().Set("Content-Length", strconv.Itoa(len(recording)))
+			w.Write(recording[:20])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "recording.wav", time.Time{}, bytes.NewReader(recording))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	t := vonage.NewTransport(srv.URL, nil)
+	n, err := t.Download(context.Background(), "/recordings/REC-1", &buf,
+		vonage.WithProgress(func(p vonage.DownloadProgress) {
+			fmt.Printf("%d/%d bytes (%.0f%%), resumes: %d\n", p.Written, p.Total, p.Percent(), p.Resumes)
+		}),
+		vonage.WithResume(3, time.Millisecond),
+		vonage.WithSHA256(hex.EncodeToString(sum[:])),
+	)
+	fmt.Println(n, err)
+	// Output:
+	// GET Range: ""
+	// 20/48 bytes (42%), resumes: 0
+	// GET Range: "bytes=20-"
+	// 48/48 bytes (100%), resumes: 1
+	// 48 <nil>
+}
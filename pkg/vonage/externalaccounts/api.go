@@ -0,0 +1,22 @@
+package externalaccounts
+
+import (
+	"context"
+)
+
+// ========================================
+// API Interface
+// ========================================
+
+// API is the External Accounts API implemented by *Client. Application
+// code can depend on it and substitute vonagemock.ExternalAccounts in
+// tests.
+type API interface {
+	List(ctx context.Context, opts *ListOptions) (*Page, error)
+	Get(ctx context.Context, externalID string) (*Account, error)
+	Link(ctx context.Context, externalID, appID string) error
+	Unlink(ctx context.Context, externalID, appID string) error
+	EnsureLinked(ctx context.Context, externalID, appID string) error
+}
+
+var _ API = (*Client)(nil)
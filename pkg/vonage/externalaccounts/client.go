@@ -0,0 +1,263 @@
+// Package externalaccounts manages external chat channel accounts, such as
+// WhatsApp Business senders and Viber service IDs, and the applications
+// they are linked to. An account must be linked to an application before
+// the Messages API can send from it with that application's JWT.
+package externalaccounts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+const (
+	// BaseURL is the Vonage External Accounts API base URL
+	BaseURL = vonage.BaseURLREST
+
+	// basePath prefixes every External Accounts endpoint
+	basePath = "/beta/chatapp-accounts"
+)
+
+// ErrNotConfigured is returned when the client has no API key and secret
+var ErrNotConfigured = errors.New("externalaccounts: API key and secret required")
+
+// Client handles Vonage External Accounts API operations. The API
+// authenticates with the account API key and secret.
+type Client struct {
+	provider vonage.CredentialsProvider
+
+	baseURL    string
+	httpClient *http.Client
+	middleware []vonage.Middleware
+	transport  *vonage.Transport
+	logger     vonage.Logger
+	uaSuffix   string
+	mode       vonage.Mode
+}
+
+// ClientOption is a functional option for configuring the client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the base URL (useful for testing)
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l vonage.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier such as
+// "checkin-service/1.4" to the User-Agent
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.uaSuffix = suffix
+	}
+}
+
+// WithMiddleware adds transport middleware (retries, tracing, metrics)
+func WithMiddleware(mw ...vonage.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithMode sets the client's mode (default vonage.ModeLive). In
+// vonage.ModeMock no request is sent, and in vonage.ModeDryRun only
+// reads are.
+func WithMode(m vonage.Mode) ClientOption {
+	return func(c *Client) {
+		c.mode = m
+	}
+}
+
+// WithSettings applies the HTTP client, logger, middleware, User-Agent
+// suffix and mode shared by a root vonage.Client, see
+// vonage.Client.Settings
+func WithSettings(s vonage.Settings) ClientOption {
+	return func(c *Client) {
+		if s.HTTPClient != nil {
+			c.httpClient = s.HTTPClient
+		}
+		if s.Logger != nil {
+			c.logger = s.Logger
+		}
+		c.middleware = append(c.middleware, s.Middleware...)
+		c.uaSuffix = s.UserAgentSuffix
+		c.mode = s.Mode
+	}
+}
+
+// WithCredentialsProvider fetches the API key and secret from p for every
+// request instead of using those passed to NewClient, so rotated secrets
+// are picked up
+func WithCredentialsProvider(p vonage.CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.provider = p
+	}
+}
+
+// NewClient creates a new Vonage External Accounts API client
+func NewClient(apiKey, apiSecret string, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:    BaseURL,
+		httpClient: &http.Client{Timeout: vonage.DefaultTimeout},
+		logger:     vonage.NopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	provider := c.provider
+	if provider == nil {
+		provider = &vonage.Credentials{APIKey: apiKey, APISecret: apiSecret}
+	}
+	c.transport = vonage.NewTransport(c.baseURL, vonage.NewAuthChain(provider, vonage.AuthBasic),
+		vonage.WithTransportHTTPClient(c.httpClient),
+		vonage.WithMiddleware(c.middleware...),
+		vonage.WithTransportLogger(c.logger),
+		vonage.WithTransportUserAgentSuffix(c.uaSuffix),
+		vonage.WithTransportMode(c.mode),
+	)
+
+	return c
+}
+
+// NewClientFromCredentials creates a new client from Vonage credentials
+func NewClientFromCredentials(creds *vonage.Credentials, opts ...ClientOption) (*Client, error) {
+	if !creds.HasAPIKey() {
+		return nil, ErrNotConfigured
+	}
+	return NewClient(creds.APIKey, creds.APISecret, append([]ClientOption{WithMode(creds.Mode)}, opts...)...), nil
+}
+
+// ========================================
+// Accounts
+// ========================================
+
+// List returns one page of external accounts
+func (c *Client) List(ctx context.Context, opts *ListOptions) (*Page, error) {
+	var resp listResponse
+	if err := c.transport.Do(ctx, http.MethodGet, basePath+opts.query(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &Page{
+		Accounts:   resp.Embedded.Accounts,
+		Page:       resp.Page,
+		TotalPages: resp.TotalPages,
+		TotalItems: resp.TotalItems,
+	}, nil
+}
+
+// NewPager returns a pager over the external accounts matching opts, from
+// opts.Page on
+func NewPager(c API, opts *ListOptions, pagerOpts ...vonage.PagerOption) *vonage.Pager[Account] {
+	var base ListOptions
+	if opts != nil {
+		base = *opts
+	}
+	return vonage.NewPager(func(ctx context.Context, token string) ([]Account, string, error) {
+		o := base
+		if token != "" {
+			o.Page = vonage.PageNumber(token)
+		}
+		page, err := c.List(ctx, &o)
+		if err != nil {
+			return nil, "", err
+		}
+		if !page.HasNext() {
+			return page.Accounts, "", nil
+		}
+		return page.Accounts, strconv.Itoa(page.Page + 1), nil
+	}, pagerOpts...)
+}
+
+// Get retrieves an external account by its external ID
+func (c *Client) Get(ctx context.Context, externalID string) (*Account, error) {
+	var account Account
+	if err := c.transport.Do(ctx, http.MethodGet, accountPath(externalID), nil, &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// ========================================
+// Application Links
+// ========================================
+
+// Link links an external account to an application, so the application
+// can send and receive messages through it
+func (c *Client) Link(ctx context.Context, externalID, appID string) error {
+	if err := c.transport.Do(ctx, http.MethodPut, linkPath(externalID, appID), nil, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Linked external account to application",
+		"externalID", externalID,
+		"appID", appID,
+	)
+	return nil
+}
+
+// Unlink removes the link between an external account and an application
+func (c *Client) Unlink(ctx context.Context, externalID, appID string) error {
+	if err := c.transport.Do(ctx, http.MethodDelete, linkPath(externalID, appID), nil, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Unlinked external account from application",
+		"externalID", externalID,
+		"appID", appID,
+	)
+	return nil
+}
+
+// EnsureLinked links the account to the application unless it already is,
+// making onboarding scripts safe to re-run
+func (c *Client) EnsureLinked(ctx context.Context, externalID, appID string) error {
+	account, err := c.Get(ctx, externalID)
+	if err != nil {
+		return err
+	}
+	if account.LinkedTo(appID) {
+		return nil
+	}
+	return c.Link(ctx, externalID, appID)
+}
+
+// accountPath returns the path of an external account resource
+func accountPath(externalID string) string {
+	return basePath + "/" + url.PathEscape(externalID)
+}
+
+// linkPath returns the path of an account's link to an application
+func linkPath(externalID, appID string) string {
+	return accountPath(externalID) + "/applications/" + url.PathEscape(appID)
+}
+
+// ========================================
+// Health
+// ========================================
+
+// Ping checks the credentials and connectivity for readiness probes; it
+// lists the external accounts
+func (c *Client) Ping(ctx context.Context) *vonage.Health {
+	return c.transport.Ping(ctx, "externalaccounts", basePath)
+}
@@ -0,0 +1,31 @@
+package externalaccounts_test
+
+import (
+	"context"
+	"fmt"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/externalaccounts"
+)
+
+func ExampleClient_EnsureLinked() {
+	creds, _ := vonage.NewCredentialsFromEnv()
+	client, _ := externalaccounts.NewClientFromCredentials(creds)
+
+	ctx := context.Background()
+	page, err := client.List(ctx, &externalaccounts.ListOptions{
+		Provider: externalaccounts.ProviderWhatsApp,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// Link every WhatsApp sender to the messaging application; already
+	// linked senders are left alone
+	for _, account := range page.Accounts {
+		if err := client.EnsureLinked(ctx, account.ExternalID, creds.AppID); err != nil {
+			fmt.Println(account.Name, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package externalaccounts
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Provider is the chat channel of an external account
+type Provider string
+
+const (
+	ProviderWhatsApp  Provider = "whatsapp"
+	ProviderViber     Provider = "viber_service_msg"
+	ProviderMessenger Provider = "messenger"
+	ProviderInstagram Provider = "instagram"
+)
+
+// Account is an external channel account: a WhatsApp Business sender, a
+// Viber service ID or a Messenger/Instagram page
+type Account struct {
+	// ExternalID is the provider's ID for the account (the WhatsApp
+	// business number ID, the Viber service ID, the Facebook page ID)
+	ExternalID string   `json:"external_id"`
+	Name       string   `json:"name,omitempty"`
+	Provider   Provider `json:"provider"`
+	// Applications are the IDs of the applications the account is linked to
+	Applications []string `json:"applications,omitempty"`
+	// Numbers are the sender numbers of WhatsApp and Viber accounts
+	Numbers []string `json:"numbers,omitempty"`
+}
+
+// LinkedTo returns true if the account is linked to the application
+func (a *Account) LinkedTo(appID string) bool {
+	for _, id := range a.Applications {
+		if id == appID {
+			return true
+		}
+	}
+	return false
+}
+
+// ListOptions filters and pages the account list
+type ListOptions struct {
+	Provider Provider
+	// Page is numbered from 1
+	Page     int
+	PageSize int
+}
+
+// Page is one page of accounts
+type Page struct {
+	Accounts   []Account
+	Page       int
+	TotalPages int
+	TotalItems int
+}
+
+// HasNext returns true if there are more pages after this one
+func (p *Page) HasNext() bool {
+	return p.Page < p.TotalPages
+}
+
+// listResponse is the HAL body of the list endpoint
+type listResponse struct {
+	Page       int `json:"page"`
+	PageSize   int `json:"page_size"`
+	TotalItems int `json:"total_items"`
+	TotalPages int `json:"total_pages"`
+	Embedded   struct {
+		Accounts []Account `json:"accounts"`
+	} `json:"_embedded"`
+}
+
+// query encodes list options
+func (o *ListOptions) query() string {
+	if o == nil {
+		return ""
+	}
+	q := url.Values{}
+	if o.Provider != "" {
+		q.Set("provider", string(o.Provider))
+	}
+	if o.Page > 0 {
+		q.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PageSize > 0 {
+		q.Set("page_size", strconv.Itoa(o.PageSize))
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
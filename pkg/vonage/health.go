@@ -0,0 +1,141 @@
+package vonage
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ========================================
+// Health Checks
+// ========================================
+
+// Health is the result of a connectivity probe, e.g. a sub-client's Ping
+type Health struct {
+	// Service names the API probed, e.g. "voice"
+	Service string `json:"service"`
+	// AuthOK is true if credentials were generated and not rejected
+	AuthOK bool `json:"auth_ok"`
+	// Reachable is true if the API answered
+	Reachable bool `json:"reachable"`
+	// StatusCode is the status of the probe request, 0 if none was received
+	StatusCode int           `json:"status_code,omitempty"`
+	Latency    time.Duration `json:"-"`
+	CheckedAt  time.Time     `json:"checked_at"`
+	// Err is why the probe failed, nil if healthy
+	Err error `json:"-"`
+}
+
+// Healthy returns true if the API answered and accepted the credentials
+func (h *Health) Healthy() bool {
+	return h.Reachable && h.AuthOK && h.Err == nil
+}
+
+// MarshalJSON adds the healthy flag, the latency in milliseconds and the
+// error message
+func (h *Health) MarshalJSON() ([]byte, error) {
+	type health Health
+	out := struct {
+		*health
+		Healthy   bool    `json:"healthy"`
+		LatencyMS float64 `json:"latency_ms"`
+		Error     string  `json:"error,omitempty"`
+	}{
+		health:    (*health)(h),
+		Healthy:   h.Healthy(),
+		LatencyMS: float64(h.Latency.Microseconds()) / 1000,
+	}
+	if h.Err != nil {
+		out.Error = h.Err.Error()
+	}
+	return json.Marshal(out)
+}
+
+// Ping sends a cheap authenticated GET to path and reports whether the
+// credentials could be generated, the API answered, and how long it took.
+// Statuses below 500 other than 401 and 403 count as healthy, so probes
+// may target endpoints that answer 404 or 405 once authenticated. The
+// request goes through the middleware; in mock mode it never leaves the
+// process.
+func (t *Transport) Ping(ctx context.Context, service, path string) *Health {
+	h := &Health{Service: service, CheckedAt: time.Now()}
+
+	ctx, cancel, httpClient := t.withTimeout(ctx)
+	defer cancel()
+
+	req, err := t.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		h.Err = err
+		return h
+	}
+	// Authenticate up front so credential errors are told apart from
+	// network errors
+	if t.auth != nil && t.mode != ModeMock {
+		if err := t.auth.Authenticate(req.Clone(ctx)); err != nil {
+			h.Err = err
+			return h
+		}
+	}
+	h.AuthOK = true
+
+	start := time.Now()
+	resp, err := t.send(httpClient, req)
+	h.Latency = time.Since(start)
+	if err != nil {
+		h.Err = err
+		return h
+	}
+	defer resp.Body.Close()
+
+	h.Reachable = true
+	h.StatusCode = resp.StatusCode
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden || resp.StatusCode >= 500 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, bodySnippetBytes))
+		h.AuthOK = resp.StatusCode >= 500
+		h.Err = NewError(resp.StatusCode, string(body))
+	}
+	return h
+}
+
+// Pinger is implemented by the sub-clients with a health probe
+type Pinger interface {
+	Ping(ctx context.Context) *Health
+}
+
+// PingAll probes pingers concurrently and returns their results in order
+func PingAll(ctx context.Context, pingers ...Pinger) []*Health {
+	results := make([]*Health, len(pingers))
+	var wg sync.WaitGroup
+	for i, p := range pingers {
+		wg.Add(1)
+		go func(i int, p Pinger) {
+			defer wg.Done()
+			results[i] = p.Ping(ctx)
+		}(i, p)
+	}
+	wg.Wait()
+	return results
+}
+
+// HealthHandler serves the results of check as JSON for readiness probes,
+// with 503 Service Unavailable unless every result is healthy
+func HealthHandler(check func(ctx context.Context) []*Health) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results := check(r.Context())
+
+		status := http.StatusOK
+		for _, h := range results {
+			if !h.Healthy() {
+				status = http.StatusServiceUnavailable
+				break
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(results)
+	})
+}
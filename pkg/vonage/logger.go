@@ -0,0 +1,115 @@
+package vonage
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// ========================================
+// Logging
+// ========================================
+
+// Logger receives log output from the SDK. Arguments after msg are
+// alternating key/value pairs. *slog.Logger satisfies this interface.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// nopLogger discards all output
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+
+// NopLogger returns a Logger that discards all output. It is the default
+// for every client.
+func NopLogger() Logger {
+	return nopLogger{}
+}
+
+// zerologLogger adapts a zerolog.Logger to Logger
+type zerologLogger struct {
+	l zerolog.Logger
+}
+
+// NewZerologLogger adapts a zerolog logger, e.g. the global log.Logger
+func NewZerologLogger(l zerolog.Logger) Logger {
+	return zerologLogger{l: l}
+}
+
+func (z zerologLogger) Debug(msg string, kv ...interface{}) { z.l.Debug().Fields(kv).Msg(msg) }
+func (z zerologLogger) Info(msg string, kv ...interface{})  { z.l.Info().Fields(kv).Msg(msg) }
+func (z zerologLogger) Warn(msg string, kv ...interface{})  { z.l.Warn().Fields(kv).Msg(msg) }
+func (z zerologLogger) Error(msg string, kv ...interface{}) { z.l.Error().Fields(kv).Msg(msg) }
+
+// ========================================
+// Context Logging
+// ========================================
+
+// logContextKey carries the per-request logger and fields
+type logContextKey struct{}
+
+// logContext is the logging state carried by a context
+type logContext struct {
+	logger Logger
+	fields []interface{}
+}
+
+// ContextWithLogger makes SDK log events for requests made with ctx go to
+// l instead of the client's logger, e.g. a logger already scoped to the
+// current HTTP request
+func ContextWithLogger(ctx context.Context, l Logger) context.Context {
+	lc := logContextFrom(ctx)
+	lc.logger = l
+	return context.WithValue(ctx, logContextKey{}, lc)
+}
+
+// ContextWithLogFields adds key/value pairs, such as a conversation or
+// tenant ID, to every SDK log event for requests made with ctx. Fields
+// accumulate across calls.
+func ContextWithLogFields(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	lc := logContextFrom(ctx)
+	lc.fields = append(lc.fields[:len(lc.fields):len(lc.fields)], keysAndValues...)
+	return context.WithValue(ctx, logContextKey{}, lc)
+}
+
+// LoggerFromContext returns the logger for events about a request made
+// with ctx: the context's logger, or fallback if it has none, with the
+// context's fields added to every event
+func LoggerFromContext(ctx context.Context, fallback Logger) Logger {
+	lc := logContextFrom(ctx)
+	l := fallback
+	if lc.logger != nil {
+		l = lc.logger
+	}
+	if len(lc.fields) == 0 {
+		return l
+	}
+	return fieldsLogger{l: l, fields: lc.fields}
+}
+
+func logContextFrom(ctx context.Context) logContext {
+	lc, _ := ctx.Value(logContextKey{}).(logContext)
+	return lc
+}
+
+// fieldsLogger prepends fields to every event
+type fieldsLogger struct {
+	l      Logger
+	fields []interface{}
+}
+
+func (f fieldsLogger) with(kv []interface{}) []interface{} {
+	return append(f.fields[:len(f.fields):len(f.fields)], kv...)
+}
+
+func (f fieldsLogger) Debug(msg string, kv ...interface{}) { f.l.Debug(msg, f.with(kv)...) }
+func (f fieldsLogger) Info(msg string, kv ...interface{})  { f.l.Info(msg, f.with(kv)...) }
+func (f fieldsLogger) Warn(msg string, kv ...interface{})  { f.l.Warn(msg, f.with(kv)...) }
+func (f fieldsLogger) Error(msg string, kv ...interface{}) { f.l.Error(msg, f.with(kv)...) }
@@ -0,0 +1,26 @@
+package media
+
+import (
+	"context"
+	"io"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// API Interface
+// ========================================
+
+// API is the Media API implemented by *Client. Application code can
+// depend on it and substitute vonagemock.Media in tests.
+type API interface {
+	List(ctx context.Context, opts *ListOptions) (*Page, error)
+	Get(ctx context.Context, mediaID string) (*Item, error)
+	Update(ctx context.Context, mediaID string, update *Update) error
+	Delete(ctx context.Context, mediaID string) error
+	Upload(ctx context.Context, name, contentType string, r io.Reader, opts *UploadOptions) (string, error)
+	Download(ctx context.Context, mediaID string, w io.Writer, opts ...vonage.DownloadOption) (int64, error)
+	DownloadFrom(ctx context.Context, mediaID string, offset int64, w io.Writer, opts ...vonage.DownloadOption) (int64, error)
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,304 @@
+// Package media manages media files stored by Vonage, such as call
+// recordings and MMS media. voice.Client.DownloadRecording fetches a
+// recording from its URL; this package also uploads, lists, inspects and
+// deletes stored items, for example to archive recordings before they
+// expire or to host media sent with the Messages API.
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+const (
+	// BaseURL is the Vonage Media API base URL
+	BaseURL = vonage.BaseURLREST
+
+	// DefaultRetention is how long Vonage keeps media items that have no
+	// TTL of their own
+	DefaultRetention = 30 * 24 * time.Hour
+)
+
+// Client handles Vonage Media API operations
+type Client struct {
+	baseURL      string
+	jwtGenerator *vonage.JWTGenerator
+	httpClient   *http.Client
+	middleware   []vonage.Middleware
+	transport    *vonage.Transport
+	logger       vonage.Logger
+	uaSuffix     string
+}
+
+// ClientOption is a functional option for configuring the media client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the base URL (useful for testing)
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l vonage.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier such as
+// "checkin-service/1.4" to the User-Agent
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.uaSuffix = suffix
+	}
+}
+
+// WithMiddleware adds transport middleware (retries, tracing, metrics)
+func WithMiddleware(mw ...vonage.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithSettings applies the HTTP client, logger, middleware and User-Agent
+// suffix shared by a root vonage.Client, see
+// vonage.Client.Settings
+func WithSettings(s vonage.Settings) ClientOption {
+	return func(c *Client) {
+		if s.HTTPClient != nil {
+			c.httpClient = s.HTTPClient
+		}
+		if s.Logger != nil {
+			c.logger = s.Logger
+		}
+		c.middleware = append(c.middleware, s.Middleware...)
+		c.uaSuffix = s.UserAgentSuffix
+	}
+}
+
+// WithTransport uses a shared transport instead of building one, pointing
+// at the REST host (BaseURL). Its configuration replaces WithBaseURL,
+// WithHTTPClient, WithMiddleware, WithUserAgentSuffix and the matching
+// parts of WithSettings, and it authenticates instead of the JWT generator
+// passed to NewClient; WithLogger then only applies to the client's own
+// logs.
+func WithTransport(t *vonage.Transport) ClientOption {
+	return func(c *Client) {
+		c.transport = t
+	}
+}
+
+// NewClient creates a new Vonage Media API client
+func NewClient(jwtGenerator *vonage.JWTGenerator, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:      BaseURL,
+		jwtGenerator: jwtGenerator,
+		httpClient:   &http.Client{Timeout: vonage.DefaultTimeout},
+		logger:       vonage.NopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.transport == nil {
+		c.transport = vonage.NewTransport(c.baseURL, jwtGenerator,
+			vonage.WithTransportHTTPClient(c.httpClient),
+			vonage.WithMiddleware(c.middleware...),
+			vonage.WithTransportLogger(c.logger),
+			vonage.WithTransportUserAgentSuffix(c.uaSuffix),
+		)
+	}
+
+	return c
+}
+
+// NewClientFromCredentials creates a new client from Vonage credentials
+func NewClientFromCredentials(creds *vonage.Credentials, opts ...ClientOption) (*Client, error) {
+	if !creds.HasApplication() {
+		return nil, vonage.ErrNotConfigured
+	}
+
+	jwtGen := vonage.NewJWTGeneratorFromCredentials(creds)
+	return NewClient(jwtGen, opts...), nil
+}
+
+// ========================================
+// Media Items
+// ========================================
+
+// List returns one page of media items
+func (c *Client) List(ctx context.Context, opts *ListOptions) (*Page, error) {
+	var resp listResponse
+	if err := c.transport.Do(ctx, http.MethodGet, "/v3/media"+opts.query(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &Page{
+		Items:     resp.Embedded.Media,
+		PageIndex: resp.PageIndex,
+		PageSize:  resp.PageSize,
+		Count:     resp.Count,
+	}, nil
+}
+
+// NewPager returns a pager over the media items matching opts, from
+// opts.PageIndex on
+func NewPager(c API, opts *ListOptions, pagerOpts ...vonage.PagerOption) *vonage.Pager[Item] {
+	var base ListOptions
+	if opts != nil {
+		base = *opts
+	}
+	return vonage.NewPager(func(ctx context.Context, token string) ([]Item, string, error) {
+		o := base
+		if token != "" {
+			o.PageIndex = vonage.PageNumber(token)
+		}
+		page, err := c.List(ctx, &o)
+		if err != nil {
+			return nil, "", err
+		}
+		if !page.HasNext() {
+			return page.Items, "", nil
+		}
+		return page.Items, strconv.Itoa(page.PageIndex + 1), nil
+	}, pagerOpts...)
+}
+
+// Get retrieves a media item's metadata
+func (c *Client) Get(ctx context.Context, mediaID string) (*Item, error) {
+	var item Item
+	if err := c.transport.Do(ctx, http.MethodGet, mediaPath(mediaID)+"/info", nil, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Update changes a media item's visibility and metadata
+func (c *Client) Update(ctx context.Context, mediaID string, update *Update) error {
+	return c.transport.Do(ctx, http.MethodPut, mediaPath(mediaID)+"/info", update, nil)
+}
+
+// Delete deletes a media item
+func (c *Client) Delete(ctx context.Context, mediaID string) error {
+	if err := c.transport.Do(ctx, http.MethodDelete, mediaPath(mediaID), nil, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Deleted Vonage media item", "mediaID", mediaID)
+	return nil
+}
+
+// ========================================
+// Upload
+// ========================================
+
+// Upload stores the content of r as a new media item named name and
+// returns its ID. The content is buffered so that retry middleware can
+// resend it. Use URL for the address of the item, e.g. to send it with
+// the Messages API.
+func (c *Client) Upload(ctx context.Context, name, contentType string, r io.Reader, opts *UploadOptions) (string, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fields := opts.fields()
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := mw.WriteField(k, fields[k]); err != nil {
+			return "", fmt.Errorf("failed to build upload: %w", err)
+		}
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{"name": "filedata", "filename": name}))
+	header.Set("Content-Type", contentType)
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return "", fmt.Errorf("failed to build upload: %w", err)
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return "", fmt.Errorf("failed to read media: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return "", fmt.Errorf("failed to build upload: %w", err)
+	}
+
+	body := vonage.RawBody{ContentType: mw.FormDataContentType(), Body: &buf}
+	respHeader, err := c.transport.DoHeader(ctx, http.MethodPost, "/v3/media", body, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// The new item's ID is the last segment of its Location
+	location := strings.TrimSuffix(respHeader.Get("Location"), "/")
+	mediaID, err := url.PathUnescape(location[strings.LastIndex(location, "/")+1:])
+	if err != nil || mediaID == "" {
+		return "", vonage.NewError(http.StatusCreated, "media upload response has no item location")
+	}
+
+	c.logger.Info("Uploaded Vonage media item", "mediaID", mediaID, "name", name)
+	return mediaID, nil
+}
+
+// URL returns the address a media item is downloaded from. Items not made
+// public need the application's credentials to read.
+func (c *Client) URL(mediaID string) string {
+	return c.transport.BaseURL() + mediaPath(mediaID)
+}
+
+// ========================================
+// Download
+// ========================================
+
+// Download streams a media item's content to w. Large items may need a
+// context from vonage.WithRequestTimeout to allow more than the client's
+// timeout, and opts such as vonage.WithProgress, vonage.WithResume and
+// vonage.WithSHA256.
+func (c *Client) Download(ctx context.Context, mediaID string, w io.Writer, opts ...vonage.DownloadOption) (int64, error) {
+	return c.transport.Download(ctx, mediaPath(mediaID), w, opts...)
+}
+
+// DownloadFrom is like Download but skips the first offset bytes, to
+// resume an interrupted download
+func (c *Client) DownloadFrom(ctx context.Context, mediaID string, offset int64, w io.Writer, opts ...vonage.DownloadOption) (int64, error) {
+	return c.transport.DownloadFrom(ctx, mediaPath(mediaID), offset, w, opts...)
+}
+
+// mediaPath returns the path of a media item
+func mediaPath(mediaID string) string {
+	return "/v3/media/" + url.PathEscape(mediaID)
+}
+
+// ========================================
+// Health
+// ========================================
+
+// Ping checks the credentials and connectivity for readiness probes; it
+// lists one media item
+func (c *Client) Ping(ctx context.Context) *vonage.Health {
+	return c.transport.Ping(ctx, "media", "/v3/media?page_size=1")
+}
@@ -0,0 +1,47 @@
+package media_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/media"
+)
+
+func ExampleClient_List() {
+	creds, _ := vonage.NewCredentialsFromEnv()
+	client, _ := media.NewClientFromCredentials(creds)
+
+	ctx := context.Background()
+
+	// Archive recordings that expire within the next three days
+	opts := &media.ListOptions{PageIndex: 1, PageSize: 100}
+	for {
+		page, err := client.List(ctx, opts)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		for _, item := range page.Items {
+			if time.Until(item.ExpiresAt()) > 72*time.Hour {
+				continue
+			}
+			f, err := os.Create(item.ID + ".mp3")
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			_, err = client.Download(ctx, item.ID, f)
+			f.Close()
+			if err != nil {
+				fmt.Println(item.ID, err)
+			}
+		}
+		if !page.HasNext() {
+			break
+		}
+		opts.PageIndex++
+	}
+}
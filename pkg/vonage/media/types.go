@@ -0,0 +1,174 @@
+package media
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Item is a media file stored by Vonage, such as a call recording or MMS
+// media
+type Item struct {
+	ID               string `json:"id"`
+	OriginalFileName string `json:"original_file_name,omitempty"`
+	MimeType         string `json:"mime_type,omitempty"`
+	AccountID        string `json:"account_id,omitempty"`
+	StoreID          string `json:"store_id,omitempty"`
+	ETag             string `json:"etag,omitempty"`
+	// Size is in bytes
+	Size int64 `json:"media_size,omitempty"`
+	// Timestamp is when the item was stored
+	Timestamp time.Time `json:"timestamp"`
+	Public    bool      `json:"public"`
+
+	// MaxDownloads is the number of downloads allowed (0: unlimited)
+	MaxDownloads    int `json:"max_downloads_allowed,omitempty"`
+	TimesDownloaded int `json:"times_downloaded,omitempty"`
+	// TTLSeconds is the lifetime set at upload, if any
+	TTLSeconds int `json:"ttl,omitempty"`
+
+	MetadataPrimary   string `json:"metadata_primary,omitempty"`
+	MetadataSecondary string `json:"metadata_secondary,omitempty"`
+}
+
+// TTL returns the item's lifetime: its own TTL if one was set, otherwise
+// DefaultRetention
+func (i *Item) TTL() time.Duration {
+	if i.TTLSeconds > 0 {
+		return time.Duration(i.TTLSeconds) * time.Second
+	}
+	return DefaultRetention
+}
+
+// ExpiresAt returns when Vonage will delete the item
+func (i *Item) ExpiresAt() time.Time {
+	return i.Timestamp.Add(i.TTL())
+}
+
+// DownloadsExhausted returns true if the item has reached its download
+// limit
+func (i *Item) DownloadsExhausted() bool {
+	return i.MaxDownloads > 0 && i.TimesDownloaded >= i.MaxDownloads
+}
+
+// Update changes an item's visibility and metadata. Nil fields are left
+// unchanged.
+type Update struct {
+	Public            *bool   `json:"public,omitempty"`
+	MetadataPrimary   *string `json:"metadata_primary,omitempty"`
+	MetadataSecondary *string `json:"metadata_secondary,omitempty"`
+}
+
+// UploadOptions sets the visibility, lifetime and metadata of an uploaded
+// item
+type UploadOptions struct {
+	// Public makes the item readable at its URL without credentials, as
+	// the Messages API needs to fetch it
+	Public bool
+	// TTL is the item's lifetime, rounded down to seconds (0: the account's
+	// DefaultRetention)
+	TTL time.Duration
+	// MaxDownloads is the number of downloads allowed (0: unlimited)
+	MaxDownloads int
+
+	MetadataPrimary   string
+	MetadataSecondary string
+}
+
+// fields returns the upload form fields for o
+func (o *UploadOptions) fields() map[string]string {
+	fields := map[string]string{}
+	if o == nil {
+		return fields
+	}
+	if o.Public {
+		fields["public"] = "true"
+	}
+	if secs := int(o.TTL / time.Second); secs > 0 {
+		fields["ttl"] = strconv.Itoa(secs)
+	}
+	if o.MaxDownloads > 0 {
+		fields["max_downloads_allowed"] = strconv.Itoa(o.MaxDownloads)
+	}
+	if o.MetadataPrimary != "" {
+		fields["metadata_primary"] = o.MetadataPrimary
+	}
+	if o.MetadataSecondary != "" {
+		fields["metadata_secondary"] = o.MetadataSecondary
+	}
+	return fields
+}
+
+// ========================================
+// Listing
+// ========================================
+
+// Order is the sort order of a media list
+type Order string
+
+const (
+	OrderAscending  Order = "ascending"
+	OrderDescending Order = "descending"
+)
+
+// ListOptions filters and pages the media list
+type ListOptions struct {
+	// PageIndex is numbered from 1
+	PageIndex int
+	// PageSize is the number of items per page (max 100)
+	PageSize  int
+	Order     Order
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// Page is one page of media items
+type Page struct {
+	Items     []Item
+	PageIndex int
+	PageSize  int
+	// Count is the total number of matching items
+	Count int
+}
+
+// HasNext returns true if there are more pages after this one
+func (p *Page) HasNext() bool {
+	return p.PageSize > 0 && p.PageIndex*p.PageSize < p.Count
+}
+
+// listResponse is the HAL body of the list endpoint
+type listResponse struct {
+	Count     int `json:"count"`
+	PageSize  int `json:"page_size"`
+	PageIndex int `json:"page_index"`
+	Embedded  struct {
+		Media []Item `json:"media"`
+	} `json:"_embedded"`
+}
+
+// query encodes list options
+func (o *ListOptions) query() string {
+	if o == nil {
+		return ""
+	}
+	q := url.Values{}
+	if o.PageIndex > 0 {
+		q.Set("page_index", strconv.Itoa(o.PageIndex))
+	}
+	if o.PageSize > 0 {
+		q.Set("page_size", strconv.Itoa(o.PageSize))
+	}
+	if o.Order != "" {
+		q.Set("order", string(o.Order))
+	}
+	if !o.StartTime.IsZero() {
+		q.Set("start_time", o.StartTime.UTC().Format(time.RFC3339))
+	}
+	if !o.EndTime.IsZero() {
+		q.Set("end_time", o.EndTime.UTC().Format(time.RFC3339))
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
@@ -0,0 +1,107 @@
+package messages
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// ========================================
+// Framework Adapters (Echo / Gin)
+// ========================================
+//
+// The adapters are generic over the framework's context type so this package
+// does not import Echo or Gin. Instantiate them with the framework type and
+// the result is directly assignable to the framework's handler type:
+//
+//	e.POST("/webhooks/inbound", messages.EchoInbound[echo.Context](handler))
+//	r.POST("/webhooks/status", messages.GinStatus[*gin.Context](handler))
+
+// EchoContext is the subset of echo.Context used by the Echo adapters
+type EchoContext interface {
+	Request() *http.Request
+	NoContent(code int) error
+}
+
+// GinContext is the subset of *gin.Context used by the Gin adapters. The
+// context is passed to the handler's event sink and metrics; it carries
+// the request's cancellation when the Gin engine has ContextWithFallback
+// set.
+type GinContext interface {
+	context.Context
+	GetRawData() ([]byte, error)
+	GetHeader(key string) string
+	Status(code int)
+}
+
+// EchoInbound returns an Echo handler for the inbound message webhook
+func EchoInbound[C EchoContext](h *WebhookHandler) func(C) error {
+	return func(c C) error {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			h.logger.Error("Failed to read inbound webhook body", "error", err)
+			return c.NoContent(http.StatusOK) // Always 200 for webhooks
+		}
+		if err := h.verify(c.Request().Header.Get("Authorization"), body); err != nil {
+			h.logger.Warn("Rejected inbound webhook", "error", err)
+			return c.NoContent(http.StatusUnauthorized)
+		}
+		h.processInbound(c.Request().Context(), body)
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+// EchoStatus returns an Echo handler for the message status webhook
+func EchoStatus[C EchoContext](h *WebhookHandler) func(C) error {
+	return func(c C) error {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			h.logger.Error("Failed to read status webhook body", "error", err)
+			return c.NoContent(http.StatusOK)
+		}
+		if err := h.verify(c.Request().Header.Get("Authorization"), body); err != nil {
+			h.logger.Warn("Rejected status webhook", "error", err)
+			return c.NoContent(http.StatusUnauthorized)
+		}
+		h.processStatus(c.Request().Context(), body)
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+// GinInbound returns a Gin handler for the inbound message webhook
+func GinInbound[C GinContext](h *WebhookHandler) func(C) {
+	return func(c C) {
+		body, err := c.GetRawData()
+		if err != nil {
+			h.logger.Error("Failed to read inbound webhook body", "error", err)
+			c.Status(http.StatusOK) // Always 200 for webhooks
+			return
+		}
+		if err := h.verify(c.GetHeader("Authorization"), body); err != nil {
+			h.logger.Warn("Rejected inbound webhook", "error", err)
+			c.Status(http.StatusUnauthorized)
+			return
+		}
+		h.processInbound(c, body)
+		c.Status(http.StatusOK)
+	}
+}
+
+// GinStatus returns a Gin handler for the message status webhook
+func GinStatus[C GinContext](h *WebhookHandler) func(C) {
+	return func(c C) {
+		body, err := c.GetRawData()
+		if err != nil {
+			h.logger.Error("Failed to read status webhook body", "error", err)
+			c.Status(http.StatusOK)
+			return
+		}
+		if err := h.verify(c.GetHeader("Authorization"), body); err != nil {
+			h.logger.Warn("Rejected status webhook", "error", err)
+			c.Status(http.StatusUnauthorized)
+			return
+		}
+		h.processStatus(c, body)
+		c.Status(http.StatusOK)
+	}
+}
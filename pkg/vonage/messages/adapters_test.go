@@ -0,0 +1,194 @@
+package messages
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/vonatrigger/poc/pkg/vonage"
+)
+
+const adapterSecret = "signature-secret"
+
+// echoContext is the part of echo.Context the Echo adapters use
+type echoContext struct {
+	req    *http.Request
+	status int
+}
+
+func (c *echoContext) Request() *http.Request { return c.req }
+
+func (c *echoContext) NoContent(code int) error {
+	c.status = code
+	return nil
+}
+
+// ginContext is the part of *gin.Context the Gin adapters use, with the
+// request's context as a Gin engine with ContextWithFallback has
+type ginContext struct {
+	context.Context
+	req    *http.Request
+	status int
+}
+
+func (c *ginContext) GetRawData() ([]byte, error) { return io.ReadAll(c.req.Body) }
+func (c *ginContext) GetHeader(key string) string { return c.req.Header.Get(key) }
+func (c *ginContext) Status(code int)             { c.status = code }
+
+// serveAdapter passes req to an adapter and returns the status it wrote
+type serveAdapter func(h *WebhookHandler, req *http.Request) int
+
+func viaEcho(adapter func(*WebhookHandler) func(*echoContext) error) serveAdapter {
+	return func(h *WebhookHandler, req *http.Request) int {
+		c := &echoContext{req: req}
+		if err := adapter(h)(c); err != nil {
+			return 0
+		}
+		return c.status
+	}
+}
+
+func viaGin(adapter func(*WebhookHandler) func(*ginContext)) serveAdapter {
+	return func(h *WebhookHandler, req *http.Request) int {
+		c := &ginContext{Context: req.Context(), req: req}
+		adapter(h)(c)
+		return c.status
+	}
+}
+
+// signWebhook returns a Bearer Authorization header for body signed with
+// secret
+func signWebhook(t *testing.T, secret, body string) string {
+	t.Helper()
+	sum := sha256.Sum256([]byte(body))
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"payload_hash": hex.EncodeToString(sum[:]),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + signed
+}
+
+func TestWebhookAdapters(t *testing.T) {
+	const (
+		inbound = `{"message_uuid":"MSG-1","from":"447700900001","message_type":"text","text":"hi"}`
+		status  = `{"message_uuid":"MSG-1","status":"delivered"}`
+	)
+
+	adapters := []struct {
+		name   string
+		serve  serveAdapter
+		body   string
+		status bool
+	}{
+		{"EchoInbound", viaEcho(EchoInbound[*echoContext]), inbound, false},
+		{"EchoStatus", viaEcho(EchoStatus[*echoContext]), status, true},
+		{"GinInbound", viaGin(GinInbound[*ginContext]), inbound, false},
+		{"GinStatus", viaGin(GinStatus[*ginContext]), status, true},
+	}
+
+	tests := []struct {
+		name        string
+		verify      bool
+		secret      string
+		wantStatus  int
+		wantHandled bool
+	}{
+		{"unverified", false, "", http.StatusOK, true},
+		{"signed", true, adapterSecret, http.StatusOK, true},
+		{"unsigned", true, "", http.StatusUnauthorized, false},
+		{"wrong secret", true, "other-secret", http.StatusUnauthorized, false},
+	}
+
+	for _, a := range adapters {
+		for _, tt := range tests {
+			t.Run(a.name+"/"+tt.name, func(t *testing.T) {
+				var handled string
+				h := NewWebhookHandler().
+					OnInbound(func(msg *InboundMessage) error {
+						handled = "inbound " + msg.MessageUUID
+						return nil
+					}).
+					OnStatus(func(s *MessageStatus) error {
+						handled = "status " + s.MessageUUID
+						return nil
+					})
+				if tt.verify {
+					h.WithVerifier(vonage.NewWebhookVerifier(&vonage.Credentials{SignatureSecret: adapterSecret}))
+				}
+
+				req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(a.body))
+				if tt.secret != "" {
+					req.Header.Set("Authorization", signWebhook(t, tt.secret, a.body))
+				}
+
+				if got := a.serve(h, req); got != tt.wantStatus {
+					t.Errorf("status = %d, want %d", got, tt.wantStatus)
+				}
+				want := ""
+				if tt.wantHandled {
+					want = "inbound MSG-1"
+					if a.status {
+						want = "status MSG-1"
+					}
+				}
+				if handled != want {
+					t.Errorf("handled %q, want %q", handled, want)
+				}
+			})
+		}
+	}
+}
+
+func TestWebhookAdaptersHandlerError(t *testing.T) {
+	// Handler errors are logged, and the webhook still acknowledged so the
+	// API does not retry it
+	h := NewWebhookHandler().OnStatus(func(*MessageStatus) error {
+		return io.ErrUnexpectedEOF
+	})
+	for name, serve := range map[string]serveAdapter{
+		"Echo": viaEcho(EchoStatus[*echoContext]),
+		"Gin":  viaGin(GinStatus[*ginContext]),
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"message_uuid":"MSG-1","status":"rejected"}`))
+		if got := serve(h, req); got != http.StatusOK {
+			t.Errorf("%s status = %d, want 200", name, got)
+		}
+	}
+}
+
+func TestWebhookAdaptersContext(t *testing.T) {
+	type ctxKey struct{}
+
+	for name, serve := range map[string]serveAdapter{
+		"EchoInbound": viaEcho(EchoInbound[*echoContext]),
+		"EchoStatus":  viaEcho(EchoStatus[*echoContext]),
+		"GinInbound":  viaGin(GinInbound[*ginContext]),
+		"GinStatus":   viaGin(GinStatus[*ginContext]),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var got interface{}
+			h := NewWebhookHandler().WithEventSink(vonage.EventSinkFunc(func(ctx context.Context, _ vonage.Event) error {
+				got = ctx.Value(ctxKey{})
+				return nil
+			}))
+
+			body := `{"message_uuid":"MSG-1","from":"447700900001","message_type":"text","text":"hi","status":"delivered"}`
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+			req = req.WithContext(context.WithValue(req.Context(), ctxKey{}, "request"))
+			serve(h, req)
+
+			if got != "request" {
+				t.Errorf("sink context value = %v, want the request's", got)
+			}
+		})
+	}
+}
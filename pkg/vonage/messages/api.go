@@ -0,0 +1,25 @@
+package messages
+
+import (
+	"context"
+)
+
+// ========================================
+// API Interface
+// ========================================
+
+// API is the Messages API implemented by *Client. Application code can
+// depend on it and substitute vonagemock.Messages in tests.
+type API interface {
+	Send(ctx context.Context, req *SendRequest) (*SendResponse, error)
+	SendSMS(ctx context.Context, to, text string, opts ...SendOption) (*SendResponse, error)
+	SendSMSFrom(ctx context.Context, from, to, text string, opts ...SendOption) (*SendResponse, error)
+	SendMMS(ctx context.Context, to, imageURL, caption string, opts ...SendOption) (*SendResponse, error)
+	SendWhatsApp(ctx context.Context, to, text string, opts ...SendOption) (*SendResponse, error)
+	SendWhatsAppImage(ctx context.Context, to, imageURL, caption string, opts ...SendOption) (*SendResponse, error)
+	SendViber(ctx context.Context, to, text string, opts ...SendOption) (*SendResponse, error)
+	SendViberImageWithButton(ctx context.Context, to, imageURL, buttonText, buttonURL string, opts ...SendOption) (*SendResponse, error)
+	Preview(req *SendRequest) (*Preview, error)
+}
+
+var _ API = (*Client)(nil)
@@ -1,22 +1,18 @@
 package messages
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
-	"github.com/rs/zerolog/log"
-
 	vonage "github.com/vonatrigger/poc/pkg/vonage"
 )
 
 const (
 	// BaseURL is the Vonage Messages API base URL
-	BaseURL = "https://api.nexmo.com"
+	BaseURL = vonage.BaseURLREST
 )
 
 // Client handles Vonage Messages API operations
@@ -24,7 +20,22 @@ type Client struct {
 	baseURL      string
 	phoneNumber  string
 	jwtGenerator *vonage.JWTGenerator
+	auth         vonage.Authenticator
 	httpClient   *http.Client
+	middleware   []vonage.Middleware
+	transport    *vonage.Transport
+	logger       vonage.Logger
+	uaSuffix     string
+	mode         vonage.Mode
+	metrics      MetricsHook
+
+	skipValidation bool
+	autoSplit      bool
+	senders        *SenderPool
+	budget         *vonage.Budget
+	allowlist      *vonage.DestinationAllowlist
+	templates      TemplateValidator
+	webhookVersion string
 }
 
 // ClientOption is a functional option for configuring the messages client
@@ -44,6 +55,14 @@ func WithBaseURL(url string) ClientOption {
 	}
 }
 
+// WithAuthenticator authenticates requests with auth instead of the JWT
+// generator, e.g. vonage.BasicAuth for accounts without an application
+func WithAuthenticator(auth vonage.Authenticator) ClientOption {
+	return func(c *Client) {
+		c.auth = auth
+	}
+}
+
 // WithPhoneNumber sets the default sender phone number
 func WithPhoneNumber(number string) ClientOption {
 	return func(c *Client) {
@@ -51,35 +70,207 @@ func WithPhoneNumber(number string) ClientOption {
 	}
 }
 
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l vonage.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier such as
+// "checkin-service/1.4" to the User-Agent
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.uaSuffix = suffix
+	}
+}
+
+// WithMiddleware adds transport middleware (retries, tracing, metrics)
+func WithMiddleware(mw ...vonage.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithRequestInterceptor runs fn on every outgoing request, e.g. to add
+// headers or inject faults in tests
+func WithRequestInterceptor(fn vonage.RequestInterceptor) ClientOption {
+	return WithMiddleware(vonage.InterceptRequest(fn))
+}
+
+// WithResponseInterceptor runs fn on every response before it is decoded
+func WithResponseInterceptor(fn vonage.ResponseInterceptor) ClientOption {
+	return WithMiddleware(vonage.InterceptResponse(fn))
+}
+
+// WithMode sets the client's mode (default vonage.ModeLive). In
+// vonage.ModeDryRun messages are logged instead of sent, and outside
+// vonage.ModeLive WithAllowedDestinations applies. With WithTransport the
+// transport's own mode decides what is sent.
+func WithMode(m vonage.Mode) ClientOption {
+	return func(c *Client) {
+		c.mode = m
+	}
+}
+
+// WithSettings applies the HTTP client, logger, middleware, User-Agent
+// suffix and mode shared by a root vonage.Client, see
+// vonage.Client.Settings
+func WithSettings(s vonage.Settings) ClientOption {
+	return func(c *Client) {
+		if s.HTTPClient != nil {
+			c.httpClient = s.HTTPClient
+		}
+		if s.Logger != nil {
+			c.logger = s.Logger
+		}
+		c.middleware = append(c.middleware, s.Middleware...)
+		c.uaSuffix = s.UserAgentSuffix
+		c.mode = s.Mode
+	}
+}
+
+// WithTransport uses a shared transport instead of building one. It must
+// point at the Messages API host (vonage.BaseURLREST, or the sandbox), not
+// the Video one. Requests then use only the transport's configuration:
+// WithBaseURL, WithHTTPClient, WithAuthenticator, WithMiddleware,
+// WithRequestInterceptor, WithResponseInterceptor, WithUserAgentSuffix and
+// the matching parts of WithSettings are ignored, as is the JWT generator
+// passed to NewClient. WithLogger and WithMode still apply to the client's
+// own logs and destination allowlist, but not to request logging or mock
+// responses.
+func WithTransport(t *vonage.Transport) ClientOption {
+	return func(c *Client) {
+		c.transport = t
+	}
+}
+
+// WithMetricsHook registers a hook that observes every send
+func WithMetricsHook(hook MetricsHook) ClientOption {
+	return func(c *Client) {
+		c.metrics = hook
+	}
+}
+
+// WithoutValidation disables pre-send validation against the channel
+// capability matrix, leaving all checks to the API
+func WithoutValidation() ClientOption {
+	return func(c *Client) {
+		c.skipValidation = true
+	}
+}
+
+// WithAutoSplit splits text messages longer than the channel's maximum
+// length into several sequential sends instead of failing validation
+func WithAutoSplit() ClientOption {
+	return func(c *Client) {
+		c.autoSplit = true
+	}
+}
+
+// WithSenderPool picks the sender for messages without an explicit From
+// from the given pool. The default phone number is still used for
+// messages no pooled sender matches.
+func WithSenderPool(pool *SenderPool) ClientOption {
+	return func(c *Client) {
+		c.senders = pool
+	}
+}
+
+// WithBudget charges every send, including each part of a split message,
+// against b; sends over its limits fail with vonage.ErrBudgetExceeded
+func WithBudget(b *vonage.Budget) ClientOption {
+	return func(c *Client) {
+		c.budget = b
+	}
+}
+
+// WithAllowedDestinations restricts sends to destinations matching
+// patterns (see vonage.NewDestinationAllowlist) unless the client runs in
+// vonage.ModeLive. Other sends fail with vonage.ErrDestinationBlocked.
+func WithAllowedDestinations(patterns ...string) ClientOption {
+	return func(c *Client) {
+		c.allowlist = vonage.NewDestinationAllowlist(patterns...)
+	}
+}
+
+// Status webhook versions
+const (
+	WebhookVersionV01 = "v0.1"
+	WebhookVersionV1  = "v1"
+)
+
+// WithWebhookVersion sets the status webhook version of every message that
+// does not set SendRequest.WebhookVersion itself, for accounts whose
+// applications default to a different version
+func WithWebhookVersion(version string) ClientOption {
+	return func(c *Client) {
+		c.webhookVersion = version
+	}
+}
+
+// TemplateValidator checks a template message against the templates the
+// sender has registered, e.g. a whatsapp.Registry
+type TemplateValidator interface {
+	ValidateTemplate(req *SendRequest) error
+}
+
+// WithTemplateValidator checks template messages with v before they are
+// sent, so a wrong template name or parameter count fails locally instead
+// of in a failed status webhook
+func WithTemplateValidator(v TemplateValidator) ClientOption {
+	return func(c *Client) {
+		c.templates = v
+	}
+}
+
 // NewClient creates a new Vonage Messages API client
 func NewClient(jwtGenerator *vonage.JWTGenerator, opts ...ClientOption) *Client {
 	c := &Client{
 		baseURL:      BaseURL,
 		jwtGenerator: jwtGenerator,
 		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		logger:       vonage.NopLogger(),
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	if c.transport == nil {
+		var auth vonage.Authenticator = jwtGenerator
+		if c.auth != nil {
+			auth = c.auth
+		}
+		c.transport = vonage.NewTransport(c.baseURL, auth,
+			vonage.WithTransportHTTPClient(c.httpClient),
+			vonage.WithMiddleware(c.middleware...),
+			vonage.WithTransportLogger(c.logger),
+			vonage.WithTransportUserAgentSuffix(c.uaSuffix),
+			vonage.WithTransportMode(c.mode),
+		)
+	}
+
 	return c
 }
 
-// NewClientFromCredentials creates a new client from Vonage credentials
+// NewClientFromCredentials creates a new client from Vonage credentials. It
+// signs requests with an application JWT, or uses Basic authentication if
+// only an API key and secret are configured.
 func NewClientFromCredentials(creds *vonage.Credentials, opts ...ClientOption) (*Client, error) {
-	if !creds.HasApplication() {
-		return nil, vonage.ErrNotConfigured
+	auth, err := vonage.AuthenticatorFromCredentials(creds)
+	if err != nil {
+		return nil, err
 	}
 
-	jwtGen := vonage.NewJWTGenerator(creds.AppID, creds.PrivateKey)
-	allOpts := make([]ClientOption, 0, len(opts)+1)
+	allOpts := make([]ClientOption, 0, len(opts)+3)
+	allOpts = append(allOpts, WithMode(creds.Mode), WithAuthenticator(auth))
 	if creds.PhoneNumber != "" {
 		allOpts = append(allOpts, WithPhoneNumber(creds.PhoneNumber))
 	}
 	allOpts = append(allOpts, opts...)
 
-	return NewClient(jwtGen, allOpts...), nil
+	return NewClient(nil, allOpts...), nil
 }
 
 // PhoneNumber returns the configured default phone number
@@ -91,54 +282,110 @@ func (c *Client) PhoneNumber() string {
 // Send Message (Generic)
 // ========================================
 
-// Send sends a message using the Vonage Messages API
+// Send sends a message using the Vonage Messages API. The sender and
+// webhook version are resolved on a copy, so req can be reused.
 func (c *Client) Send(ctx context.Context, req *SendRequest) (*SendResponse, error) {
-	// Apply default sender if not set
-	if req.From == "" {
-		req.From = c.phoneNumber
+	resolved := *req
+	req = &resolved
+	if err := c.resolve(req); err != nil {
+		return nil, err
 	}
 
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	if maxLen := c.splitLimit(req); maxLen > 0 {
+		return c.sendSplit(ctx, req, maxLen)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/v1/messages", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	return c.sendOne(ctx, req)
+}
 
-	if err := c.setAuthHeaders(httpReq); err != nil {
+// sendOne validates and sends a single request
+func (c *Client) sendOne(ctx context.Context, req *SendRequest) (*SendResponse, error) {
+	if !c.skipValidation {
+		if err := req.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.validateTemplate(req); err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("API request failed: %w", err)
+	usage := vonage.Usage{Channel: string(req.Channel), Messages: 1}
+	if c.budget != nil {
+		if err := c.budget.Reserve(ctx, usage); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.metrics != nil {
+		c.metrics.OnSendStart(ctx, req.Channel)
+	}
+	start := time.Now()
+	resp, statusCode, err := c.doSend(ctx, req)
+	if err != nil && c.budget != nil {
+		c.budget.Release(ctx, usage)
+	}
+	if c.metrics != nil {
+		c.metrics.OnSendComplete(ctx, SendResult{
+			Channel:    req.Channel,
+			StatusCode: statusCode,
+			Latency:    time.Since(start),
+			Err:        err,
+		})
+	}
+	if c.senders != nil {
+		c.senders.record(req.From, err)
+	}
+	return resp, err
+}
+
+// resolve applies the default sender and webhook version and checks the
+// destination. The pool's sender wins over the default phone number, which
+// is used when no pooled sender matches.
+func (c *Client) resolve(req *SendRequest) error {
+	if req.WebhookVersion == "" {
+		req.WebhookVersion = c.webhookVersion
+	}
+	if req.From == "" {
+		from := c.phoneNumber
+		if c.senders != nil {
+			picked, err := c.senders.Pick(req.Channel, req.To)
+			switch {
+			case err == nil:
+				from = picked
+			case !errors.Is(err, ErrNoSender) || from == "":
+				return err
+			}
+		}
+		req.From = from
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		log.Error().
-			Int("status", resp.StatusCode).
-			Str("body", string(respBody)).
-			Msg("Vonage Messages API error")
-		return nil, vonage.NewError(resp.StatusCode, string(respBody))
+	return c.allowlist.Check(c.mode, req.To)
+}
+
+// validateTemplate checks template messages with the template validator
+func (c *Client) validateTemplate(req *SendRequest) error {
+	if c.templates == nil || req.MessageType != MessageTypeTemplate {
+		return nil
 	}
+	return c.templates.ValidateTemplate(req)
+}
 
+// doSend performs the HTTP request and returns the response status code
+// (0 if no response was received)
+func (c *Client) doSend(ctx context.Context, req *SendRequest) (*SendResponse, int, error) {
 	var sendResp SendResponse
-	if err := json.NewDecoder(resp.Body).Decode(&sendResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	statusCode, err := c.transport.DoStatus(ctx, http.MethodPost, "/v1/messages", req, &sendResp)
+	if err != nil {
+		return nil, statusCode, err
 	}
 
-	log.Debug().
-		Str("messageUUID", sendResp.MessageUUID).
-		Str("to", req.To).
-		Str("channel", string(req.Channel)).
-		Msg("Message sent")
+	c.logger.Debug("Message sent",
+		"messageUUID", sendResp.MessageUUID,
+		"to", req.To,
+		"channel", string(req.Channel),
+	)
 
-	return &sendResp, nil
+	return &sendResp, statusCode, nil
 }
 
 // ========================================
@@ -240,6 +487,45 @@ func (c *Client) SendWhatsAppImage(ctx context.Context, to, imageURL, caption st
 	return c.Send(ctx, req)
 }
 
+// ========================================
+// Viber Convenience Methods
+// ========================================
+
+// SendViber sends a Viber text message
+func (c *Client) SendViber(ctx context.Context, to, text string, opts ...SendOption) (*SendResponse, error) {
+	req := &SendRequest{
+		To:          to,
+		MessageType: MessageTypeText,
+		Text:        text,
+		Channel:     ChannelViber,
+	}
+
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	return c.Send(ctx, req)
+}
+
+// SendViberImageWithButton sends a Viber image with a call-to-action button
+func (c *Client) SendViberImageWithButton(ctx context.Context, to, imageURL, buttonText, buttonURL string, opts ...SendOption) (*SendResponse, error) {
+	req := &SendRequest{
+		To:          to,
+		MessageType: MessageTypeImage,
+		Channel:     ChannelViber,
+		Image:       &MediaContent{URL: imageURL},
+		Viber: &ViberOptions{
+			Action: &ViberAction{URL: buttonURL, Text: buttonText},
+		},
+	}
+
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	return c.Send(ctx, req)
+}
+
 // ========================================
 // Message Builder (Fluent API)
 // ========================================
@@ -252,11 +538,9 @@ type MessageBuilder struct {
 
 // NewMessage creates a new message builder
 func (c *Client) NewMessage() *MessageBuilder {
+	// From is resolved by Send (default number or sender pool)
 	return &MessageBuilder{
 		client: c,
-		req: SendRequest{
-			From: c.phoneNumber,
-		},
 	}
 }
 
@@ -325,6 +609,36 @@ func (b *MessageBuilder) File(url, name string) *MessageBuilder {
 	return b
 }
 
+// ViberVideo sets a Viber video with its thumbnail, duration (seconds) and
+// file size (MB), all of which Viber requires
+func (b *MessageBuilder) ViberVideo(url, thumbURL string, durationSeconds, fileSizeMB int) *MessageBuilder {
+	b.req.Channel = ChannelViber
+	b.req.MessageType = MessageTypeVideo
+	b.req.Video = &MediaContent{URL: url, ThumbURL: thumbURL}
+	viber := viberOptions(&b.req)
+	viber.Duration = strconv.Itoa(durationSeconds)
+	viber.FileSize = strconv.Itoa(fileSizeMB)
+	return b
+}
+
+// Button adds a Viber call-to-action button
+func (b *MessageBuilder) Button(text, url string) *MessageBuilder {
+	viberOptions(&b.req).Action = &ViberAction{URL: url, Text: text}
+	return b
+}
+
+// ViberCategory sets the Viber message category
+func (b *MessageBuilder) ViberCategory(category ViberCategory) *MessageBuilder {
+	viberOptions(&b.req).Category = category
+	return b
+}
+
+// ViberTTL sets the Viber delivery time-to-live in seconds
+func (b *MessageBuilder) ViberTTL(seconds int) *MessageBuilder {
+	viberOptions(&b.req).TTL = seconds
+	return b
+}
+
 // ClientRef sets a client reference for tracking
 func (b *MessageBuilder) ClientRef(ref string) *MessageBuilder {
 	b.req.ClientRef = ref
@@ -337,15 +651,13 @@ func (b *MessageBuilder) Send(ctx context.Context) (*SendResponse, error) {
 }
 
 // ========================================
-// Auth helpers
+// Health
 // ========================================
 
-func (c *Client) setAuthHeaders(req *http.Request) error {
-	token, err := c.jwtGenerator.GenerateAPIJWT()
-	if err != nil {
-		return fmt.Errorf("failed to generate JWT: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
-	return nil
+// Ping checks that a JWT can be generated and the Messages API answers,
+// for readiness probes. The API has no read endpoint, so the probe is a
+// GET that is refused once routed; credentials are only fully verified by
+// a send.
+func (c *Client) Ping(ctx context.Context) *vonage.Health {
+	return c.transport.Ping(ctx, "messages", "/v1/messages")
 }
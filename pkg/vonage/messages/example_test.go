@@ -2,10 +2,18 @@ package messages_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
 
 	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/media"
 	"github.com/vonatrigger/poc/pkg/vonage/messages"
+	"github.com/vonatrigger/poc/pkg/vonage/messages/messagestest"
 )
 
 func ExampleClient_sendSMS() {
@@ -155,6 +163,10 @@ func ExampleWebhookHandler() {
 	// Register with your HTTP router
 	// http.HandleFunc("/webhooks/vonage/sms/inbound", handler.HandleInbound())
 	// http.HandleFunc("/webhooks/vonage/sms/status", handler.HandleStatus())
+
+	// Or bind directly to Echo / Gin
+	// e.POST("/webhooks/vonage/sms/inbound", messages.EchoInbound[echo.Context](handler))
+	// r.POST("/webhooks/vonage/sms/status", messages.GinStatus[*gin.Context](handler))
 	_ = handler
 }
 
@@ -180,3 +192,334 @@ func ExampleParseInboundMessage() {
 	}
 	fmt.Printf("UUID: %s, Text: %s\n", msg.MessageUUID, msg.Text)
 }
+
+func ExampleSendMetrics() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+		vonage.WithPhoneNumber("81501234567"),
+	)
+
+	// Record send volume, status codes and latency per channel
+	metrics := messages.NewSendMetrics()
+	client, _ := messages.NewClientFromCredentials(creds, messages.WithMetricsHook(metrics))
+	_ = client
+
+	// Expose for Prometheus scraping
+	// http.Handle("/metrics/vonage", metrics)
+}
+
+func ExampleSendRequest_Validate() {
+	req := &messages.SendRequest{
+		From:        "81501234567",
+		To:          "81901234567",
+		Channel:     messages.ChannelSMS,
+		MessageType: messages.MessageTypeFile,
+		File:        &messages.MediaContent{URL: "https://example.com/map.pdf"},
+	}
+
+	var verr *messages.ValidationError
+	if err := req.Validate(); errors.As(err, &verr) {
+		for _, f := range verr.Fields {
+			fmt.Printf("%s: %s\n", f.Field, f.Reason)
+		}
+	}
+	// Output: message_type: file messages are not supported on sms
+}
+
+func ExampleClient_viberRichMedia() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+		vonage.WithPhoneNumber("81501234567"),
+	)
+	client, _ := messages.NewClientFromCredentials(creds)
+	ctx := context.Background()
+
+	// Image with a call-to-action button
+	_, _ = client.SendViberImageWithButton(ctx, "81901234567",
+		"https://example.com/event-banner.jpg",
+		"詳細を見る", "https://example.com/event",
+		messages.WithViberCategory(messages.ViberCategoryPromotion),
+	)
+
+	// Video (thumbnail, duration and size are mandatory on Viber)
+	_, _ = client.NewMessage().
+		To("81901234567").
+		ViberVideo("https://example.com/trailer.mp4", "https://example.com/thumb.jpg", 42, 8).
+		ViberTTL(3600).
+		Send(ctx)
+}
+
+func ExampleSplitText() {
+	parts := messages.SplitText("最初の文です。次の文です。最後の文です。", 8)
+	for _, p := range parts {
+		fmt.Println(p)
+	}
+	// Output:
+	// 最初の文です。
+	// 次の文です。
+	// 最後の文です。
+}
+
+func ExampleSenderPool() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+	)
+
+	pool := messages.NewSenderPool().
+		Add(messages.Sender{ID: "81501234567", CountryPrefixes: []string{"81"}}).
+		Add(messages.Sender{ID: "14155550100", CountryPrefixes: []string{"1"}, Weight: 2}).
+		Add(messages.Sender{ID: "14155550101", CountryPrefixes: []string{"1"}, Weight: 1}).
+		Add(messages.Sender{ID: "447700900000", Channels: []messages.Channel{messages.ChannelWhatsApp}})
+
+	client, _ := messages.NewClientFromCredentials(creds, messages.WithSenderPool(pool))
+
+	// From is picked automatically: 81501234567 for Japanese numbers
+	_, _ = client.SendSMS(context.Background(), "81901234567", "ヒントです")
+
+	for _, st := range pool.Stats() {
+		fmt.Printf("%s sent=%d failed=%d\n", st.ID, st.Sent, st.Failed)
+	}
+}
+
+func ExampleInboundMessage_IsReply() {
+	body := []byte(`{"message_uuid":"uuid-002","from":"81901234567","to":"81501234567","channel":"whatsapp","message_type":"text","text":"これです！","context":{"message_uuid":"uuid-001","message_from":"81501234567"},"profile":{"name":"Taro"}}`)
+	msg, err := messages.ParseInboundMessage(body)
+	if err != nil {
+		panic(err)
+	}
+	if msg.IsReply() {
+		fmt.Printf("%s replied to %s: %s\n", msg.SenderName(), msg.Context.MessageUUID, msg.Text)
+	}
+	// Output: Taro replied to uuid-001: これです！
+}
+
+func ExampleUploadFile() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+		vonage.WithPhoneNumber("81501234567"),
+	)
+	client, _ := messages.NewClientFromCredentials(creds)
+	ctx := context.Background()
+
+	// Upload via presigned URLs issued by your storage (e.g. S3)
+	uploader := messages.NewPresignedUploader(func(ctx context.Context, name, contentType string) (string, string, error) {
+		return "https://bucket.s3.amazonaws.com/" + name + "?X-Amz-Signature=...",
+			"https://cdn.example.com/" + name, nil
+	}, nil)
+
+	media, err := messages.UploadFile(ctx, uploader, messages.ChannelWhatsApp, "./hint-map.jpg")
+	if err != nil {
+		panic(err)
+	}
+
+	req := &messages.SendRequest{To: "81901234567", Channel: messages.ChannelWhatsApp}
+	media.Apply(req)
+	_, _ = client.Send(ctx, req)
+}
+
+func ExampleNewMediaAPIUploader() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+		vonage.WithPhoneNumber("81501234567"),
+	)
+	client, _ := messages.NewClientFromCredentials(creds)
+	mediaClient, _ := media.NewClientFromCredentials(creds)
+	ctx := context.Background()
+
+	// Host the file with Vonage for a day instead of your own storage
+	uploader := messages.NewMediaAPIUploader(mediaClient, &media.UploadOptions{TTL: 24 * time.Hour})
+
+	prepared, err := messages.UploadFile(ctx, uploader, messages.ChannelWhatsApp, "./hint-map.jpg")
+	if err != nil {
+		panic(err)
+	}
+
+	req := &messages.SendRequest{To: "81901234567", Channel: messages.ChannelWhatsApp}
+	prepared.Apply(req)
+	_, _ = client.Send(ctx, req)
+}
+
+func ExampleWithMiddleware_circuitBreaker() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+	)
+
+	// Fail fast for a minute after 3 consecutive outages
+	breaker := vonage.NewCircuitBreaker(vonage.CircuitBreakerConfig{
+		FailureThreshold: 3,
+		OpenTimeout:      time.Minute,
+	})
+	client, _ := messages.NewClientFromCredentials(creds, messages.WithMiddleware(breaker.Middleware()))
+
+	_, err := client.SendSMS(context.Background(), "81901234567", "Hello")
+	if errors.Is(err, vonage.ErrCircuitOpen) {
+		fmt.Println("Vonage unavailable, skipping")
+	}
+}
+
+func ExampleClient_apiErrors() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+	)
+	client, _ := messages.NewClientFromCredentials(creds)
+
+	_, err := client.SendSMS(context.Background(), "81901234567", "Hello")
+	switch {
+	case errors.Is(err, vonage.ErrRateLimited):
+		fmt.Println("Slow down")
+	case errors.Is(err, vonage.ErrInvalidRequest):
+		if apiErr, ok := vonage.AsError(err); ok {
+			for _, p := range apiErr.InvalidParameters {
+				fmt.Printf("%s: %s\n", p.Name, p.Reason)
+			}
+		}
+	}
+}
+
+func ExampleWithLogger() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+	)
+
+	// *slog.Logger satisfies vonage.Logger; the default discards all output
+	client, _ := messages.NewClientFromCredentials(creds, messages.WithLogger(slog.Default()))
+	handler := messages.NewWebhookHandler().WithLogger(slog.Default())
+
+	_, _ = client, handler
+}
+
+func ExampleWebhookHandler_WithSignatureVerification() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+		vonage.WithSignatureSecret("signature-secret", vonage.SignatureSHA256),
+	)
+
+	// Unsigned or tampered webhooks are rejected with 401
+	handler := messages.NewWebhookHandler().
+		WithSignatureVerification(creds).
+		OnInbound(func(msg *messages.InboundMessage) error {
+			return nil
+		})
+
+	// http.HandleFunc("/webhooks/inbound", handler.HandleInbound())
+	_ = handler
+}
+
+func ExampleWithAllowedDestinations() {
+	// Staging would use vonage.ModeSandbox, which really sends; mock mode
+	// keeps this example offline
+	client := messages.NewClient(nil,
+		messages.WithPhoneNumber("81501234567"),
+		messages.WithMode(vonage.ModeMock),
+		messages.WithAllowedDestinations("+81 90-0000-0001", "+81 80-0000-*"),
+	)
+
+	ctx := context.Background()
+	for _, to := range []string{"819000000001", "818000001234", "819012345678"} {
+		_, err := client.SendSMS(ctx, to, "Your code is 1234")
+		fmt.Println(to, errors.Is(err, vonage.ErrDestinationBlocked))
+	}
+	// Output:
+	// 819000000001 false
+	// 818000001234 false
+	// 819012345678 true
+}
+
+func ExampleWebhookHandler_OnUnknown() {
+	handler := messages.NewWebhookHandler().
+		OnInbound(func(msg *messages.InboundMessage) error {
+			fmt.Println("inbound:", msg.Text)
+			return nil
+		}).
+		OnUnknown(func(msg *messages.UnknownMessage) error {
+			// Persist msg.Body for audit
+			fmt.Println("unknown:", msg.Channel, msg.MessageType, len(msg.Body) > 0)
+			return nil
+		})
+
+	for _, body := range []string{
+		`{"message_uuid":"m-1","channel":"whatsapp","message_type":"text","text":"Hi"}`,
+		`{"message_uuid":"m-2","channel":"whatsapp","message_type":"reaction","reaction":{"action":"react","emoji":"👍"}}`,
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/webhooks/inbound", strings.NewReader(body))
+		handler.HandleInbound()(httptest.NewRecorder(), r)
+	}
+	// Output:
+	// inbound: Hi
+	// unknown: whatsapp reaction true
+}
+
+func ExampleClient_Preview() {
+	client := messages.NewClient(nil,
+		messages.WithPhoneNumber("447700900000"),
+		messages.WithMode(vonage.ModeMock),
+		messages.WithWebhookVersion(messages.WebhookVersionV1),
+	)
+
+	preview, err := client.Preview(&messages.SendRequest{
+		To:          "447700900001",
+		Channel:     messages.ChannelSMS,
+		MessageType: messages.MessageTypeText,
+		Text:        "Your order has shipped",
+		ClientRef:   "campaign-42",
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, payload := range preview.Payloads {
+		fmt.Println(string(payload))
+	}
+	// Output:
+	// {"from":"447700900000","to":"447700900001","message_type":"text","text":"Your order has shipped","channel":"sms","client_ref":"campaign-42","webhook_version":"v1"}
+}
+
+func ExampleOutbox() {
+	// A status webhook handler routes delivery reports to the outbox
+	var outbox *messages.Outbox
+	webhooks := messages.NewWebhookHandler().OnStatus(func(status *messages.MessageStatus) error {
+		return outbox.HandleStatus(context.Background(), status)
+	})
+
+	sim := messagestest.NewSimulator(
+		messagestest.WithStatusHandler(webhooks.HandleStatus()),
+		messagestest.WithOutcome(messagestest.ByRecipient(map[string]messagestest.Outcome{
+			"819000000002": messagestest.Failed("unreachable", "Handset switched off"),
+		}, messagestest.Delivered())),
+	)
+	defer sim.Close()
+
+	outbox = messages.NewOutbox(sim.Client(messages.WithPhoneNumber("815012345678")),
+		messages.WithOutboxRate(50),
+	)
+
+	ctx := context.Background()
+	for i, to := range []string{"819000000001", "819000000002"} {
+		// The ID makes enqueueing the same notification twice harmless
+		id := fmt.Sprintf("reminder-%d", i+1)
+		outbox.Enqueue(ctx, id, &messages.SendRequest{
+			To:          to,
+			Channel:     messages.ChannelSMS,
+			MessageType: messages.MessageTypeText,
+			Text:        "Your booking starts in one hour",
+		})
+	}
+
+	// Run(ctx) drains periodically; drain once here
+	sent, _ := outbox.Drain(ctx)
+	sim.Wait()
+
+	fmt.Println("sent:", sent)
+	for _, id := range []string{"reminder-1", "reminder-2"} {
+		item, _ := outbox.Get(ctx, id)
+		if item.LastError != "" {
+			fmt.Println(item.ID, item.Status, item.LastError)
+		} else {
+			fmt.Println(item.ID, item.Status)
+		}
+	}
+	// Output:
+	// sent: 2
+	// reminder-1 delivered
+	// reminder-2 failed Handset switched off
+}
@@ -0,0 +1,233 @@
+package messages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vonatrigger/poc/pkg/vonage/media"
+)
+
+// ========================================
+// Media Upload
+// ========================================
+
+var (
+	// ErrMediaTooLarge is returned when a file exceeds the channel's size limit
+	ErrMediaTooLarge = errors.New("messages: media exceeds channel size limit")
+	// ErrUnsupportedMedia is returned when the channel can't carry the media type
+	ErrUnsupportedMedia = errors.New("messages: media type not supported on channel")
+)
+
+// MediaUploader stores media somewhere the Messages API can fetch it and
+// returns the public URL: PresignedUploader for the application's own
+// storage, MediaAPIUploader for the Vonage Media API
+type MediaUploader interface {
+	Upload(ctx context.Context, name, contentType string, size int64, r io.Reader) (string, error)
+}
+
+// MediaUploaderFunc adapts a function to the MediaUploader interface
+type MediaUploaderFunc func(ctx context.Context, name, contentType string, size int64, r io.Reader) (string, error)
+
+// Upload implements MediaUploader
+func (f MediaUploaderFunc) Upload(ctx context.Context, name, contentType string, size int64, r io.Reader) (string, error) {
+	return f(ctx, name, contentType, size, r)
+}
+
+// PresignFunc returns a URL to PUT the object to and the URL it will be
+// publicly readable at (e.g. an S3 presigned PUT and a CloudFront URL)
+type PresignFunc func(ctx context.Context, name, contentType string) (putURL, publicURL string, err error)
+
+// PresignedUploader uploads media with an HTTP PUT to a presigned URL
+type PresignedUploader struct {
+	presign    PresignFunc
+	httpClient *http.Client
+}
+
+// NewPresignedUploader creates an uploader that PUTs to presigned URLs
+func NewPresignedUploader(presign PresignFunc, httpClient *http.Client) *PresignedUploader {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &PresignedUploader{presign: presign, httpClient: httpClient}
+}
+
+// Upload implements MediaUploader
+func (u *PresignedUploader) Upload(ctx context.Context, name, contentType string, size int64, r io.Reader) (string, error) {
+	putURL, publicURL, err := u.presign(ctx, name, contentType)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign upload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", putURL, r)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := u.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("upload failed: status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return publicURL, nil
+}
+
+// MediaAPIUploader uploads media to the Vonage Media API and returns the
+// item's URL, for applications without storage of their own. Items are
+// made public so the Messages API can fetch them.
+type MediaAPIUploader struct {
+	client *media.Client
+	opts   media.UploadOptions
+}
+
+// NewMediaAPIUploader creates an uploader that stores media with client.
+// opts may set a TTL, download limit or metadata for the items; they are
+// always public.
+func NewMediaAPIUploader(client *media.Client, opts *media.UploadOptions) *MediaAPIUploader {
+	u := &MediaAPIUploader{client: client}
+	if opts != nil {
+		u.opts = *opts
+	}
+	u.opts.Public = true
+	return u
+}
+
+// Upload implements MediaUploader
+func (u *MediaAPIUploader) Upload(ctx context.Context, name, contentType string, size int64, r io.Reader) (string, error) {
+	mediaID, err := u.client.Upload(ctx, name, contentType, r, &u.opts)
+	if err != nil {
+		return "", fmt.Errorf("upload failed: %w", err)
+	}
+	return u.client.URL(mediaID), nil
+}
+
+// PreparedMedia is the result of uploading a local file for sending
+type PreparedMedia struct {
+	MessageType MessageType
+	ContentType string
+	Size        int64
+	Content     *MediaContent
+}
+
+// Apply sets the media on a send request
+func (m *PreparedMedia) Apply(req *SendRequest) {
+	req.MessageType = m.MessageType
+	switch m.MessageType {
+	case MessageTypeImage:
+		req.Image = m.Content
+	case MessageTypeAudio:
+		req.Audio = m.Content
+	case MessageTypeVideo:
+		req.Video = m.Content
+	default:
+		req.File = m.Content
+	}
+}
+
+// UploadFile detects the content type of a local file, validates its size
+// against the channel's limits and uploads it, returning media ready to be
+// attached to a message
+func UploadFile(ctx context.Context, uploader MediaUploader, channel Channel, path string) (*PreparedMedia, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open media: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat media: %w", err)
+	}
+
+	contentType, err := DetectContentType(f, path)
+	if err != nil {
+		return nil, err
+	}
+
+	messageType := MessageTypeForContentType(contentType)
+	if err := CheckMediaSize(channel, messageType, info.Size()); err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(path)
+	url, err := uploader.Upload(ctx, name, contentType, info.Size(), f)
+	if err != nil {
+		return nil, err
+	}
+
+	content := &MediaContent{URL: url}
+	if messageType == MessageTypeFile {
+		content.Name = name
+	}
+
+	return &PreparedMedia{
+		MessageType: messageType,
+		ContentType: contentType,
+		Size:        info.Size(),
+		Content:     content,
+	}, nil
+}
+
+// DetectContentType sniffs the content type from the first 512 bytes,
+// falling back to the file extension, and rewinds the reader
+func DetectContentType(rs io.ReadSeeker, name string) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(rs, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("failed to read media: %w", err)
+	}
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind media: %w", err)
+	}
+
+	contentType := http.DetectContentType(head[:n])
+	if contentType == "application/octet-stream" || strings.HasPrefix(contentType, "text/plain") {
+		if byExt := mime.TypeByExtension(filepath.Ext(name)); byExt != "" {
+			contentType = byExt
+		}
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+	return contentType, nil
+}
+
+// MessageTypeForContentType maps a MIME type to the message type to send it as
+func MessageTypeForContentType(contentType string) MessageType {
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		return MessageTypeImage
+	case strings.HasPrefix(contentType, "audio/"):
+		return MessageTypeAudio
+	case strings.HasPrefix(contentType, "video/"):
+		return MessageTypeVideo
+	default:
+		return MessageTypeFile
+	}
+}
+
+// CheckMediaSize validates a media size against the channel's limit
+func CheckMediaSize(channel Channel, messageType MessageType, size int64) error {
+	caps, ok := Capabilities(channel)
+	if !ok || !caps.Supports(messageType) {
+		return fmt.Errorf("%w: %s on %s", ErrUnsupportedMedia, messageType, channel)
+	}
+	if limit, ok := caps.MaxMediaBytes[messageType]; ok && size > limit {
+		return fmt.Errorf("%w: %s is %d bytes, %s allows %d", ErrMediaTooLarge, messageType, size, channel, limit)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package messages
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/media"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// writeMedia writes content to name in a temporary directory, padded with
+// zeros to size bytes, and returns its path
+func writeMedia(t *testing.T, name string, content []byte, size int64) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if size > int64(len(content)) {
+		if err := os.Truncate(path, size); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+// mediaUpload is an upload received by newMediaAPIServer
+type mediaUpload struct {
+	fields      map[string]string
+	filename    string
+	contentType string
+	content     []byte
+}
+
+// newMediaAPIServer accepts Media API uploads, records them and answers
+// with the Location of item "MEDIA-1"
+func newMediaAPIServer(t *testing.T, got *mediaUpload) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v3/media" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mr, err := r.MultipartReader()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		got.fields = map[string]string{}
+		for {
+			part, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			data, _ := io.ReadAll(part)
+			if part.FormName() == "filedata" {
+				got.filename = part.FileName()
+				got.contentType = part.Header.Get("Content-Type")
+				got.content = data
+			} else {
+				got.fields[part.FormName()] = string(data)
+			}
+		}
+		w.Header().Set("Location", "https://api.nexmo.com/v3/media/MEDIA-1")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestUploadFileMediaAPI(t *testing.T) {
+	var got mediaUpload
+	srv := newMediaAPIServer(t, &got)
+	client := media.NewClient(nil, media.WithTransport(vonage.NewTransport(srv.URL, nil)))
+	uploader := NewMediaAPIUploader(client, &media.UploadOptions{MetadataPrimary: "hint-map"})
+
+	path := writeMedia(t, "hint-map.png", pngHeader, 0)
+	prepared, err := UploadFile(context.Background(), uploader, ChannelWhatsApp, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := srv.URL + "/v3/media/MEDIA-1"; prepared.Content.URL != want {
+		t.Errorf("URL = %q, want %q", prepared.Content.URL, want)
+	}
+	if prepared.MessageType != MessageTypeImage || prepared.ContentType != "image/png" {
+		t.Errorf("prepared = %s %s, want an image/png image", prepared.MessageType, prepared.ContentType)
+	}
+	if got.filename != "hint-map.png" || got.contentType != "image/png" || !bytes.Equal(got.content, pngHeader) {
+		t.Errorf("uploaded %q (%s) %q, want the file", got.filename, got.contentType, got.content)
+	}
+	if got.fields["public"] != "true" || got.fields["metadata_primary"] != "hint-map" {
+		t.Errorf("fields = %v, want a public item with metadata", got.fields)
+	}
+}
+
+func TestMediaAPIUploaderNoLocation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	client := media.NewClient(nil, media.WithTransport(vonage.NewTransport(srv.URL, nil)))
+	_, err := NewMediaAPIUploader(client, nil).Upload(context.Background(), "a.png", "image/png", 1, strings.NewReader("a"))
+	if err == nil {
+		t.Fatal("Upload() without a Location succeeded")
+	}
+}
+
+func TestPresignedUploader(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"stored", http.StatusOK, false},
+		{"rejected", http.StatusForbidden, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contentType string
+			var content []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentType = r.Header.Get("Content-Type")
+				content, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			uploader := NewPresignedUploader(func(ctx context.Context, name, contentType string) (string, string, error) {
+				return srv.URL + "/put/" + name, "https://cdn.example.com/" + name, nil
+			}, nil)
+			got, err := uploader.Upload(context.Background(), "a.png", "image/png", int64(len(pngHeader)), bytes.NewReader(pngHeader))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Upload() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != "https://cdn.example.com/a.png" {
+				t.Errorf("URL = %q, want the public URL", got)
+			}
+			if contentType != "image/png" || !bytes.Equal(content, pngHeader) {
+				t.Errorf("PUT %s %q, want the media", contentType, content)
+			}
+		})
+	}
+}
+
+func TestUploadFileValidation(t *testing.T) {
+	uploads := 0
+	uploader := MediaUploaderFunc(func(ctx context.Context, name, contentType string, size int64, r io.Reader) (string, error) {
+		uploads++
+		return "https://cdn.example.com/" + name, nil
+	})
+
+	tests := []struct {
+		name     string
+		channel  Channel
+		file     string
+		content  []byte
+		size     int64
+		wantType MessageType
+		wantErr  error
+	}{
+		{"image", ChannelWhatsApp, "a.png", pngHeader, 0, MessageTypeImage, nil},
+		{"image at limit", ChannelWhatsApp, "a.png", pngHeader, 5 << 20, MessageTypeImage, nil},
+		{"image over limit", ChannelWhatsApp, "a.png", pngHeader, 5<<20 + 1, "", ErrMediaTooLarge},
+		{"document by extension", ChannelWhatsApp, "menu.pdf", []byte("%PDF-1.4"), 0, MessageTypeFile, nil},
+		{"not on channel", ChannelSMS, "a.png", pngHeader, 0, "", ErrUnsupportedMedia},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploads = 0
+			path := writeMedia(t, tt.file, tt.content, tt.size)
+			prepared, err := UploadFile(context.Background(), uploader, tt.channel, path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UploadFile() = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if uploads != 0 {
+					t.Errorf("rejected media was uploaded")
+				}
+				return
+			}
+			if prepared.MessageType != tt.wantType {
+				t.Errorf("MessageType = %s, want %s", prepared.MessageType, tt.wantType)
+			}
+			// Only files carry their name
+			if wantName := tt.wantType == MessageTypeFile; (prepared.Content.Name != "") != wantName {
+				t.Errorf("Name = %q for a %s", prepared.Content.Name, tt.wantType)
+			}
+		})
+	}
+}
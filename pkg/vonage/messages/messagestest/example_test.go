@@ -0,0 +1,73 @@
+package messagestest_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vonatrigger/poc/pkg/vonage/messages"
+	"github.com/vonatrigger/poc/pkg/vonage/messages/messagestest"
+)
+
+func ExampleSimulator() {
+	// The application under test asks for confirmation and records answers
+	var (
+		mu        sync.Mutex
+		confirmed []string
+		failed    []string
+	)
+	webhooks := messages.NewWebhookHandler().
+		OnInbound(func(msg *messages.InboundMessage) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if msg.Text == "YES" {
+				confirmed = append(confirmed, msg.From)
+			}
+			return nil
+		}).
+		OnStatus(func(status *messages.MessageStatus) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if status.Status.IsFailed() {
+				failed = append(failed, status.To+" "+status.Error.Detail)
+			}
+			return nil
+		})
+
+	sim := messagestest.NewSimulator(
+		messagestest.WithStatusHandler(webhooks.HandleStatus()),
+		messagestest.WithInboundHandler(webhooks.HandleInbound()),
+		messagestest.WithDelays(10*time.Millisecond, 20*time.Millisecond),
+		messagestest.WithOutcome(messagestest.ByRecipient(map[string]messagestest.Outcome{
+			"819000000002": messagestest.Failed("unreachable", "Handset switched off"),
+		}, messagestest.Replied("YES"))),
+	)
+	defer sim.Close()
+
+	client := sim.Client(messages.WithPhoneNumber("815012345678"))
+	ctx := context.Background()
+	for _, to := range []string{"819000000001", "819000000002", "819000000003"} {
+		if _, err := client.SendSMS(ctx, to, "Reply YES to confirm your booking"); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := sim.Wait(); err != nil {
+		log.Fatal(err)
+	}
+
+	sort.Strings(confirmed)
+	fmt.Println("confirmed:", confirmed)
+	fmt.Println("failed:", failed)
+	for _, sent := range sim.Sent() {
+		fmt.Println(sent.Request.To, sent.Statuses)
+	}
+	// Output:
+	// confirmed: [819000000001 819000000003]
+	// failed: [819000000002 Handset switched off]
+	// 819000000001 [submitted delivered]
+	// 819000000002 [submitted failed]
+	// 819000000003 [submitted delivered]
+}
@@ -0,0 +1,373 @@
+// Package messagestest simulates the Messages API for conversation tests
+// that run in CI without Vonage: a Simulator accepts sends, delivers their
+// status webhooks (submitted, then delivered or a failure) to the
+// application's status handler after configurable delays, and sends
+// inbound replies to its inbound handler.
+package messagestest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/messages"
+)
+
+// ========================================
+// Outcomes
+// ========================================
+
+// Outcome is how the simulated channel treats a sent message
+type Outcome struct {
+	// Status is the final status (default messages.StatusDelivered)
+	Status messages.Status
+	// Error is reported with a failed or rejected status
+	Error *messages.Error
+	// Reply, if set, is sent back from the recipient as an inbound message
+	// once the message is delivered
+	Reply string
+}
+
+// OutcomeFunc decides the outcome of a sent message
+type OutcomeFunc func(req messages.SendRequest) Outcome
+
+// Delivered is the outcome of a message that is delivered
+func Delivered() Outcome {
+	return Outcome{Status: messages.StatusDelivered}
+}
+
+// Read is the outcome of a message that is delivered and read
+func Read() Outcome {
+	return Outcome{Status: messages.StatusRead}
+}
+
+// Failed is the outcome of a message the channel fails to deliver, with the
+// error type and detail the status webhook reports
+func Failed(errType, detail string) Outcome {
+	return Outcome{
+		Status: messages.StatusFailed,
+		Error:  &messages.Error{Type: errType, Title: "Delivery failed", Detail: detail},
+	}
+}
+
+// Rejected is the outcome of a message Vonage rejects, e.g. for an
+// unsupported destination
+func Rejected(errType, detail string) Outcome {
+	return Outcome{
+		Status: messages.StatusRejected,
+		Error:  &messages.Error{Type: errType, Title: "Rejected", Detail: detail},
+	}
+}
+
+// Replied is the outcome of a message that is delivered and answered with
+// text
+func Replied(text string) Outcome {
+	return Outcome{Status: messages.StatusDelivered, Reply: text}
+}
+
+// ByRecipient returns an OutcomeFunc choosing the outcome by the "to"
+// number, and def for other recipients
+func ByRecipient(outcomes map[string]Outcome, def Outcome) OutcomeFunc {
+	return func(req messages.SendRequest) Outcome {
+		if o, ok := outcomes[req.To]; ok {
+			return o
+		}
+		return def
+	}
+}
+
+// ========================================
+// Simulator
+// ========================================
+
+// Sent is a message sent to the simulator
+type Sent struct {
+	UUID    string
+	Request messages.SendRequest
+	// Statuses are the statuses posted so far, in order
+	Statuses []messages.Status
+}
+
+// Simulator is a fake Messages API backed by httptest.Server. Point a
+// messages client at it with Client or WithBaseURL(URL).
+type Simulator struct {
+	*httptest.Server
+
+	statusHandler  http.Handler
+	inboundHandler http.Handler
+	outcome        OutcomeFunc
+	submitDelay    time.Duration
+	deliverDelay   time.Duration
+	replyDelay     time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu   sync.Mutex
+	sent []*Sent
+	errs []error
+}
+
+// Option is a functional option for configuring a Simulator
+type Option func(*Simulator)
+
+// WithStatusHandler sets the handler status webhooks are posted to, e.g.
+// the application's messages.WebhookHandler HandleStatus
+func WithStatusHandler(h http.Handler) Option {
+	return func(s *Simulator) {
+		s.statusHandler = h
+	}
+}
+
+// WithInboundHandler sets the handler inbound messages are posted to, e.g.
+// the application's messages.WebhookHandler HandleInbound
+func WithInboundHandler(h http.Handler) Option {
+	return func(s *Simulator) {
+		s.inboundHandler = h
+	}
+}
+
+// WithOutcome decides the outcome of every sent message (default
+// Delivered)
+func WithOutcome(fn OutcomeFunc) Option {
+	return func(s *Simulator) {
+		s.outcome = fn
+	}
+}
+
+// WithDelays sets how long after a send the submitted status is posted,
+// and how long after that the final status (default 0 for both)
+func WithDelays(submit, deliver time.Duration) Option {
+	return func(s *Simulator) {
+		s.submitDelay = submit
+		s.deliverDelay = deliver
+	}
+}
+
+// WithReplyDelay sets how long after delivery a reply is sent (default 0)
+func WithReplyDelay(d time.Duration) Option {
+	return func(s *Simulator) {
+		s.replyDelay = d
+	}
+}
+
+// NewSimulator starts a simulator. Close it when done.
+func NewSimulator(opts ...Option) *Simulator {
+	s := &Simulator{
+		outcome: func(messages.SendRequest) Outcome { return Delivered() },
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/messages", s.handleSend)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Client returns a messages client sending to the simulator
+func (s *Simulator) Client(opts ...messages.ClientOption) *messages.Client {
+	opts = append([]messages.ClientOption{messages.WithTransport(vonage.NewTransport(s.URL, nil))}, opts...)
+	return messages.NewClient(nil, opts...)
+}
+
+// Close stops pending webhooks and shuts the server down
+func (s *Simulator) Close() {
+	s.cancel()
+	s.wg.Wait()
+	s.Server.Close()
+}
+
+// Wait blocks until every status webhook and reply of the messages sent so
+// far has been posted, and returns the errors of any the handlers failed
+func (s *Simulator) Wait() error {
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := errors.Join(s.errs...)
+	s.errs = nil
+	return err
+}
+
+// Sent returns the messages sent so far, oldest first
+func (s *Simulator) Sent() []Sent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sent := make([]Sent, len(s.sent))
+	for i, m := range s.sent {
+		sent[i] = *m
+		sent[i].Statuses = append([]messages.Status(nil), m.Statuses...)
+	}
+	return sent
+}
+
+// Inbound posts an inbound message from a user to the inbound handler, as
+// if they wrote to the application's number
+func (s *Simulator) Inbound(channel messages.Channel, from, to, text string) error {
+	return s.post(s.inboundHandler, "inbound", messages.InboundMessage{
+		MessageUUID: uuid.NewString(),
+		From:        from,
+		To:          to,
+		Timestamp:   time.Now().UTC(),
+		Channel:     channel,
+		MessageType: string(messages.MessageTypeText),
+		Text:        text,
+	})
+}
+
+// handleSend accepts a send and schedules its webhooks
+func (s *Simulator) handleSend(w http.ResponseWriter, r *http.Request) {
+	var req messages.SendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, http.StatusBadRequest, "Bad Request", err.Error())
+		return
+	}
+	if req.To == "" || req.From == "" || req.Channel == "" {
+		writeProblem(w, http.StatusUnprocessableEntity, "Invalid params", "to, from and channel are required")
+		return
+	}
+
+	msg := &Sent{UUID: uuid.NewString(), Request: req}
+	outcome := s.outcome(req)
+	if outcome.Status == "" {
+		outcome.Status = messages.StatusDelivered
+	}
+
+	s.mu.Lock()
+	s.sent = append(s.sent, msg)
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.deliver(msg, outcome)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(messages.SendResponse{MessageUUID: msg.UUID})
+}
+
+// deliver posts a message's statuses and reply in order
+func (s *Simulator) deliver(msg *Sent, outcome Outcome) {
+	defer s.wg.Done()
+
+	// Rejected messages never reach the channel
+	if outcome.Status != messages.StatusRejected {
+		if !s.sleep(s.submitDelay) || !s.postStatus(msg, messages.StatusSubmitted, nil) {
+			return
+		}
+	}
+	if !s.sleep(s.deliverDelay) || !s.postStatus(msg, outcome.Status, outcome.Error) {
+		return
+	}
+	if outcome.Reply == "" || !outcome.Status.IsDelivered() || !s.sleep(s.replyDelay) {
+		return
+	}
+
+	reply := messages.InboundMessage{
+		MessageUUID: uuid.NewString(),
+		From:        msg.Request.To,
+		To:          msg.Request.From,
+		Timestamp:   time.Now().UTC(),
+		Channel:     msg.Request.Channel,
+		MessageType: string(messages.MessageTypeText),
+		Text:        outcome.Reply,
+	}
+	if msg.Request.Channel == messages.ChannelWhatsApp {
+		reply.Context = &messages.InboundContext{MessageUUID: msg.UUID, MessageFrom: msg.Request.From}
+	}
+	s.record(s.post(s.inboundHandler, "inbound", reply))
+}
+
+// postStatus posts one status of msg and reports whether to continue
+func (s *Simulator) postStatus(msg *Sent, status messages.Status, statusErr *messages.Error) bool {
+	s.mu.Lock()
+	msg.Statuses = append(msg.Statuses, status)
+	s.mu.Unlock()
+
+	event := messages.MessageStatus{
+		MessageUUID: msg.UUID,
+		To:          msg.Request.To,
+		From:        msg.Request.From,
+		Timestamp:   time.Now().UTC(),
+		Status:      status,
+		Channel:     msg.Request.Channel,
+		Error:       statusErr,
+		ClientRef:   msg.Request.ClientRef,
+	}
+	if status.IsDelivered() {
+		event.Usage = &messages.Usage{Currency: "EUR", Price: "0.0735"}
+	}
+	err := s.post(s.statusHandler, "status", event)
+	s.record(err)
+	return err == nil
+}
+
+// post sends payload to h as a JSON webhook, if h is set
+func (s *Simulator) post(h http.Handler, kind string, payload interface{}) error {
+	if h == nil {
+		return nil
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("messagestest: failed to marshal %s webhook: %w", kind, err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/"+kind, bytes.NewReader(body)).WithContext(s.ctx)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code < 200 || rec.Code >= 300 {
+		return fmt.Errorf("messagestest: %s webhook returned status %d", kind, rec.Code)
+	}
+	return nil
+}
+
+// record keeps a webhook error for Wait
+func (s *Simulator) record(err error) {
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.errs = append(s.errs, err)
+	s.mu.Unlock()
+}
+
+// sleep waits for d and reports false if the simulator was closed
+func (s *Simulator) sleep(d time.Duration) bool {
+	if d <= 0 {
+		return s.ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+func writeProblem(w http.ResponseWriter, status int, title, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"type":   "https://developer.vonage.com/api-errors",
+		"title":  title,
+		"detail": detail,
+	})
+}
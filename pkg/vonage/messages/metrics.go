@@ -0,0 +1,164 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ========================================
+// Metrics Hook
+// ========================================
+
+// MetricsHook observes message sends. Implementations must be safe for
+// concurrent use.
+type MetricsHook interface {
+	// OnSendStart is called before the request is sent
+	OnSendStart(ctx context.Context, channel Channel)
+	// OnSendComplete is called after the request finishes, successfully or not
+	OnSendComplete(ctx context.Context, result SendResult)
+}
+
+// SendResult describes a completed send attempt
+type SendResult struct {
+	Channel Channel
+	// StatusCode is the HTTP status code, or 0 if no response was received
+	StatusCode int
+	Latency    time.Duration
+	Err        error
+}
+
+// DefaultLatencyBuckets are the histogram upper bounds (seconds) used by SendMetrics
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// SendMetrics is an in-memory MetricsHook that exposes its counters in the
+// Prometheus text exposition format. Mount it on your metrics endpoint or
+// call WritePrometheus from an existing collector.
+type SendMetrics struct {
+	buckets []float64
+
+	mu       sync.Mutex
+	inFlight map[Channel]int64
+	sent     map[sendKey]int64
+	latency  map[Channel]*histogram
+}
+
+type sendKey struct {
+	channel Channel
+	code    int
+}
+
+type histogram struct {
+	counts []int64
+	count  int64
+	sum    float64
+}
+
+// NewSendMetrics creates a SendMetrics using DefaultLatencyBuckets
+func NewSendMetrics() *SendMetrics {
+	return &SendMetrics{
+		buckets:  DefaultLatencyBuckets,
+		inFlight: make(map[Channel]int64),
+		sent:     make(map[sendKey]int64),
+		latency:  make(map[Channel]*histogram),
+	}
+}
+
+// OnSendStart implements MetricsHook
+func (m *SendMetrics) OnSendStart(_ context.Context, channel Channel) {
+	m.mu.Lock()
+	m.inFlight[channel]++
+	m.mu.Unlock()
+}
+
+// OnSendComplete implements MetricsHook
+func (m *SendMetrics) OnSendComplete(_ context.Context, result SendResult) {
+	seconds := result.Latency.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight[result.Channel]--
+	m.sent[sendKey{channel: result.Channel, code: result.StatusCode}]++
+
+	h, ok := m.latency[result.Channel]
+	if !ok {
+		h = &histogram{counts: make([]int64, len(m.buckets))}
+		m.latency[result.Channel] = h
+	}
+	for i, le := range m.buckets {
+		if seconds <= le {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// WritePrometheus writes all metrics in the Prometheus text exposition format
+func (m *SendMetrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var err error
+	printf := func(format string, args ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	printf("# HELP vonage_messages_sent_total Messages API send attempts by channel and HTTP status code.\n")
+	printf("# TYPE vonage_messages_sent_total counter\n")
+	keys := make([]sendKey, 0, len(m.sent))
+	for k := range m.sent {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].channel != keys[j].channel {
+			return keys[i].channel < keys[j].channel
+		}
+		return keys[i].code < keys[j].code
+	})
+	for _, k := range keys {
+		printf("vonage_messages_sent_total{channel=%q,code=\"%d\"} %d\n", k.channel, k.code, m.sent[k])
+	}
+
+	printf("# HELP vonage_messages_in_flight Messages API sends currently in progress.\n")
+	printf("# TYPE vonage_messages_in_flight gauge\n")
+	for _, ch := range sortedChannels(m.inFlight) {
+		printf("vonage_messages_in_flight{channel=%q} %d\n", ch, m.inFlight[ch])
+	}
+
+	printf("# HELP vonage_messages_send_duration_seconds Messages API send latency.\n")
+	printf("# TYPE vonage_messages_send_duration_seconds histogram\n")
+	for _, ch := range sortedChannels(m.latency) {
+		h := m.latency[ch]
+		for i, le := range m.buckets {
+			printf("vonage_messages_send_duration_seconds_bucket{channel=%q,le=\"%g\"} %d\n", ch, le, h.counts[i])
+		}
+		printf("vonage_messages_send_duration_seconds_bucket{channel=%q,le=\"+Inf\"} %d\n", ch, h.count)
+		printf("vonage_messages_send_duration_seconds_sum{channel=%q} %g\n", ch, h.sum)
+		printf("vonage_messages_send_duration_seconds_count{channel=%q} %d\n", ch, h.count)
+	}
+
+	return err
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format
+func (m *SendMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_ = m.WritePrometheus(w)
+}
+
+func sortedChannels[V any](m map[Channel]V) []Channel {
+	channels := make([]Channel, 0, len(m))
+	for ch := range m {
+		channels = append(channels, ch)
+	}
+	sort.Slice(channels, func(i, j int) bool { return channels[i] < channels[j] })
+	return channels
+}
@@ -0,0 +1,140 @@
+package messages
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+func TestSendMetricsPrometheus(t *testing.T) {
+	ctx := context.Background()
+	m := NewSendMetrics()
+
+	sends := []SendResult{
+		{Channel: ChannelSMS, StatusCode: 202, Latency: 40 * time.Millisecond},
+		{Channel: ChannelSMS, StatusCode: 202, Latency: 300 * time.Millisecond},
+		{Channel: ChannelSMS, StatusCode: 429, Latency: 2 * time.Second},
+		{Channel: ChannelWhatsApp, StatusCode: 0, Latency: 20 * time.Second},
+	}
+	for _, r := range sends {
+		m.OnSendStart(ctx, r.Channel)
+		m.OnSendComplete(ctx, r)
+	}
+	m.OnSendStart(ctx, ChannelSMS)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+	lines := strings.Split(rec.Body.String(), "\n")
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"sent", `vonage_messages_sent_total{channel="sms",code="202"} 2`},
+		{"rate limited", `vonage_messages_sent_total{channel="sms",code="429"} 1`},
+		{"no response", `vonage_messages_sent_total{channel="whatsapp",code="0"} 1`},
+		{"in flight", `vonage_messages_in_flight{channel="sms"} 1`},
+		{"done", `vonage_messages_in_flight{channel="whatsapp"} 0`},
+		{"fastest bucket", `vonage_messages_send_duration_seconds_bucket{channel="sms",le="0.05"} 1`},
+		{"cumulative bucket", `vonage_messages_send_duration_seconds_bucket{channel="sms",le="0.5"} 2`},
+		{"slowest bucket", `vonage_messages_send_duration_seconds_bucket{channel="sms",le="10"} 3`},
+		{"over every bucket", `vonage_messages_send_duration_seconds_bucket{channel="whatsapp",le="10"} 0`},
+		{"infinity bucket", `vonage_messages_send_duration_seconds_bucket{channel="whatsapp",le="+Inf"} 1`},
+		{"sum", `vonage_messages_send_duration_seconds_sum{channel="sms"} 2.34`},
+		{"count", `vonage_messages_send_duration_seconds_count{channel="sms"} 3`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, line := range lines {
+				if line == tt.want {
+					return
+				}
+			}
+			t.Errorf("missing %s\n%s", tt.want, rec.Body.String())
+		})
+	}
+}
+
+// recordingHook records the sends it observes
+type recordingHook struct {
+	mu      sync.Mutex
+	started []Channel
+	results []SendResult
+}
+
+func (h *recordingHook) OnSendStart(_ context.Context, channel Channel) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.started = append(h.started, channel)
+}
+
+func (h *recordingHook) OnSendComplete(_ context.Context, result SendResult) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.results = append(h.results, result)
+}
+
+func TestSendMetricsHook(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"accepted", http.StatusAccepted, false},
+		{"rejected", http.StatusUnprocessableEntity, true},
+		{"rate limited", http.StatusTooManyRequests, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(5 * time.Millisecond)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"message_uuid":"MSG-1"}`))
+			}))
+			defer srv.Close()
+
+			hook := &recordingHook{}
+			client := NewClient(nil,
+				WithTransport(vonage.NewTransport(srv.URL, nil)),
+				WithPhoneNumber("81501234567"),
+				WithMetricsHook(hook),
+			)
+			_, err := client.SendSMS(context.Background(), "819012345678", "hello")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendSMS() = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(hook.started) != 1 || hook.started[0] != ChannelSMS || len(hook.results) != 1 {
+				t.Fatalf("observed %v starts and %d results, want one SMS send", hook.started, len(hook.results))
+			}
+			got := hook.results[0]
+			if got.Channel != ChannelSMS || got.StatusCode != tt.status || (got.Err != nil) != tt.wantErr {
+				t.Errorf("result = %s %d %v, want sms %d", got.Channel, got.StatusCode, got.Err, tt.status)
+			}
+			if got.Latency < 5*time.Millisecond {
+				t.Errorf("latency = %s, want at least the server's 5ms", got.Latency)
+			}
+		})
+	}
+
+	// Requests rejected before sending are not observed
+	hook := &recordingHook{}
+	client := NewClient(nil, WithMode(vonage.ModeMock), WithMetricsHook(hook))
+	if _, err := client.SendSMS(context.Background(), "819012345678", "hello"); err == nil {
+		t.Fatal("SendSMS() without a sender succeeded")
+	}
+	if len(hook.started) != 0 || len(hook.results) != 0 {
+		t.Errorf("observed %d starts and %d results for an invalid send", len(hook.started), len(hook.results))
+	}
+}
@@ -0,0 +1,629 @@
+package messages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// Outbox
+// ========================================
+
+// Outbox defaults
+const (
+	// DefaultOutboxInterval is how often Outbox.Run looks for due items
+	DefaultOutboxInterval = time.Second
+	// DefaultOutboxRate is the maximum sends per second
+	DefaultOutboxRate = 10
+	// DefaultOutboxBatch is the maximum items sent per drain
+	DefaultOutboxBatch = 100
+	// DefaultConfirmDeadline is how long a sent item waits for a delivery
+	// status without a confirm timeout
+	DefaultConfirmDeadline = 72 * time.Hour
+)
+
+var (
+	// ErrOutboxItemNotFound is returned for unknown outbox item IDs
+	ErrOutboxItemNotFound = errors.New("messages: outbox item not found")
+	// ErrOutboxFull is returned by Enqueue when the outbox holds its
+	// capacity of unfinished items
+	ErrOutboxFull = errors.New("messages: outbox is full")
+)
+
+// OutboxStatus is the state of an outbox item
+type OutboxStatus string
+
+const (
+	// OutboxStatusPending items are waiting to be sent (or retried)
+	OutboxStatusPending OutboxStatus = "pending"
+	// OutboxStatusSent items were accepted by the API and await a status
+	// webhook
+	OutboxStatusSent OutboxStatus = "sent"
+	// OutboxStatusDelivered items were confirmed delivered (or read)
+	OutboxStatusDelivered OutboxStatus = "delivered"
+	// OutboxStatusFailed items ran out of attempts, were rejected by the API
+	// or failed delivery
+	OutboxStatusFailed OutboxStatus = "failed"
+	// OutboxStatusUnconfirmed items were sent, but no delivery status
+	// arrived within the confirm deadline. A later status still completes
+	// them.
+	OutboxStatusUnconfirmed OutboxStatus = "unconfirmed"
+)
+
+// IsTerminal reports whether the outbox is done with the item
+func (s OutboxStatus) IsTerminal() bool {
+	return s == OutboxStatusDelivered || s == OutboxStatusFailed || s == OutboxStatusUnconfirmed
+}
+
+// OutboxRetry controls how failed sends are retried: after Backoff,
+// doubling per attempt up to MaxBackoff, up to MaxAttempts in total
+type OutboxRetry struct {
+	MaxAttempts int           `json:"max_attempts"`
+	Backoff     time.Duration `json:"backoff"`
+	MaxBackoff  time.Duration `json:"max_backoff"`
+}
+
+// DefaultOutboxRetry tries five times, backing off from 10 seconds to 10
+// minutes
+func DefaultOutboxRetry() OutboxRetry {
+	return OutboxRetry{MaxAttempts: 5, Backoff: 10 * time.Second, MaxBackoff: 10 * time.Minute}
+}
+
+// delay returns the backoff after attempt (1-based)
+func (r OutboxRetry) delay(attempt int) time.Duration {
+	d := r.Backoff
+	for i := 1; i < attempt && (r.MaxBackoff <= 0 || d < r.MaxBackoff); i++ {
+		d *= 2
+	}
+	if r.MaxBackoff > 0 && d > r.MaxBackoff {
+		d = r.MaxBackoff
+	}
+	return d
+}
+
+// OutboxItem is a message queued for sending
+type OutboxItem struct {
+	ID      string      `json:"id"`
+	Request SendRequest `json:"request"`
+
+	Status   OutboxStatus `json:"status"`
+	Attempts int          `json:"attempts"`
+	// NextAttempt is when a pending item is due, or when a sent item
+	// without confirmation is sent again (with a confirm timeout) or ends
+	// unconfirmed (without one)
+	NextAttempt time.Time `json:"next_attempt"`
+	MessageUUID string    `json:"message_uuid,omitempty"`
+	// LastStatus is the last status webhook received for the item
+	LastStatus Status    `json:"last_status,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// OutboxStore persists outbox items so they survive restarts.
+// Implementations shared by several instances must make Due claim items
+// atomically, e.g. by leasing them, so each item is sent by one instance.
+type OutboxStore interface {
+	Save(ctx context.Context, item *OutboxItem) error
+	Get(ctx context.Context, id string) (*OutboxItem, error)
+	// GetByMessageUUID returns the item whose last send has messageUUID
+	GetByMessageUUID(ctx context.Context, messageUUID string) (*OutboxItem, error)
+	// Due returns up to limit pending and sent items whose NextAttempt is
+	// set and not after now, oldest first
+	Due(ctx context.Context, now time.Time, limit int) ([]*OutboxItem, error)
+	// Unfinished counts the items that are not terminal
+	Unfinished(ctx context.Context) (int, error)
+}
+
+// MemoryOutboxStore is an in-process OutboxStore. It does not survive
+// restarts; use a persistent store for at-least-once delivery.
+type MemoryOutboxStore struct {
+	mu    sync.Mutex
+	items map[string]OutboxItem
+}
+
+// NewMemoryOutboxStore creates an empty in-process store
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{items: make(map[string]OutboxItem)}
+}
+
+// Save implements OutboxStore
+func (m *MemoryOutboxStore) Save(ctx context.Context, item *OutboxItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[item.ID] = *item
+	return nil
+}
+
+// Get implements OutboxStore
+func (m *MemoryOutboxStore) Get(ctx context.Context, id string) (*OutboxItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item, ok := m.items[id]
+	if !ok {
+		return nil, ErrOutboxItemNotFound
+	}
+	return &item, nil
+}
+
+// GetByMessageUUID implements OutboxStore
+func (m *MemoryOutboxStore) GetByMessageUUID(ctx context.Context, messageUUID string) (*OutboxItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, item := range m.items {
+		if item.MessageUUID == messageUUID {
+			return &item, nil
+		}
+	}
+	return nil, ErrOutboxItemNotFound
+}
+
+// Due implements OutboxStore
+func (m *MemoryOutboxStore) Due(ctx context.Context, now time.Time, limit int) ([]*OutboxItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due []*OutboxItem
+	for _, item := range m.items {
+		if item.Status.IsTerminal() || item.NextAttempt.IsZero() || item.NextAttempt.After(now) {
+			continue
+		}
+		item := item
+		due = append(due, &item)
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttempt.Equal(due[j].NextAttempt) {
+			return due[i].NextAttempt.Before(due[j].NextAttempt)
+		}
+		return due[i].CreatedAt.Before(due[j].CreatedAt)
+	})
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+// Unfinished implements OutboxStore
+func (m *MemoryOutboxStore) Unfinished(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, item := range m.items {
+		if !item.Status.IsTerminal() {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Outbox queues sends in a store and drains them with rate limiting and
+// retries; status webhooks mark items delivered or failed. With a
+// persistent store this gives at-least-once sending across restarts: an
+// item is only done once its delivery is confirmed or its confirm deadline
+// passes. Call Run in a goroutine and route status webhooks to
+// HandleStatus.
+type Outbox struct {
+	client   API
+	store    OutboxStore
+	logger   vonage.Logger
+	interval time.Duration
+	rate     float64
+	batch    int
+	capacity int
+	retry    OutboxRetry
+	confirm  time.Duration
+	deadline time.Duration
+	now      func() time.Time
+
+	// drainMu keeps concurrent drains from sending an item twice
+	drainMu sync.Mutex
+	// mu serializes state changes so Drain and HandleStatus do not race.
+	// It is not held during sends.
+	mu sync.Mutex
+	// pausedUntil holds back sends after the API rate limited them
+	pausedUntil time.Time
+	// inflight counts sends waiting for the API; early holds the final
+	// statuses that arrive meanwhile for messages not yet in the store
+	inflight int
+	early    map[string]*MessageStatus
+}
+
+// OutboxOption is a functional option for configuring an Outbox
+type OutboxOption func(*Outbox)
+
+// WithOutboxStore replaces the default in-memory store
+func WithOutboxStore(s OutboxStore) OutboxOption {
+	return func(o *Outbox) {
+		o.store = s
+	}
+}
+
+// WithOutboxRate sets the maximum sends per second (default
+// DefaultOutboxRate)
+func WithOutboxRate(perSecond float64) OutboxOption {
+	return func(o *Outbox) {
+		o.rate = perSecond
+	}
+}
+
+// WithOutboxCapacity makes Enqueue fail with ErrOutboxFull while n items
+// are unfinished (default: unlimited)
+func WithOutboxCapacity(n int) OutboxOption {
+	return func(o *Outbox) {
+		o.capacity = n
+	}
+}
+
+// WithOutboxRetry replaces DefaultOutboxRetry
+func WithOutboxRetry(r OutboxRetry) OutboxOption {
+	return func(o *Outbox) {
+		o.retry = r
+	}
+}
+
+// WithConfirmTimeout sends items again if no delivery status arrives
+// within d of sending, counting as an attempt (default: don't, see
+// WithConfirmDeadline). An
+// item still unconfirmed after its last attempt fails. Only use it on
+// channels that always report delivery, as a message whose status webhook
+// is lost is sent twice.
+func WithConfirmTimeout(d time.Duration) OutboxOption {
+	return func(o *Outbox) {
+		o.confirm = d
+	}
+}
+
+// WithConfirmDeadline sets how long a sent item waits for a delivery status
+// when there is no confirm timeout (default DefaultConfirmDeadline). It then
+// ends as OutboxStatusUnconfirmed and stops counting against the capacity.
+// d <= 0 waits forever.
+func WithConfirmDeadline(d time.Duration) OutboxOption {
+	return func(o *Outbox) {
+		o.deadline = d
+	}
+}
+
+// WithOutboxInterval sets how often Run looks for due items (default
+// DefaultOutboxInterval)
+func WithOutboxInterval(d time.Duration) OutboxOption {
+	return func(o *Outbox) {
+		o.interval = d
+	}
+}
+
+// WithOutboxBatch sets the maximum items sent per drain (default
+// DefaultOutboxBatch)
+func WithOutboxBatch(n int) OutboxOption {
+	return func(o *Outbox) {
+		o.batch = n
+	}
+}
+
+// WithOutboxLogger sets the logger (default: no logging)
+func WithOutboxLogger(l vonage.Logger) OutboxOption {
+	return func(o *Outbox) {
+		o.logger = l
+	}
+}
+
+// NewOutbox creates an outbox that sends with client
+func NewOutbox(client API, opts ...OutboxOption) *Outbox {
+	o := &Outbox{
+		client:   client,
+		store:    NewMemoryOutboxStore(),
+		logger:   vonage.NopLogger(),
+		interval: DefaultOutboxInterval,
+		rate:     DefaultOutboxRate,
+		batch:    DefaultOutboxBatch,
+		retry:    DefaultOutboxRetry(),
+		deadline: DefaultConfirmDeadline,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// Enqueue stores req to be sent and returns its item. id makes enqueueing
+// idempotent: an existing item with the same ID is returned unchanged. An
+// empty id gets a random one.
+func (o *Outbox) Enqueue(ctx context.Context, id string, req *SendRequest) (*OutboxItem, error) {
+	if req == nil {
+		return nil, errors.New("messages: outbox request is required")
+	}
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if existing, err := o.store.Get(ctx, id); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, ErrOutboxItemNotFound) {
+		return nil, fmt.Errorf("messages: failed to load outbox item: %w", err)
+	}
+
+	if o.capacity > 0 {
+		n, err := o.store.Unfinished(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("messages: failed to count outbox items: %w", err)
+		}
+		if n >= o.capacity {
+			return nil, ErrOutboxFull
+		}
+	}
+
+	now := o.now()
+	item := &OutboxItem{
+		ID:          id,
+		Request:     *req,
+		Status:      OutboxStatusPending,
+		NextAttempt: now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := o.store.Save(ctx, item); err != nil {
+		return nil, fmt.Errorf("messages: failed to save outbox item: %w", err)
+	}
+	return item, nil
+}
+
+// Get returns an outbox item
+func (o *Outbox) Get(ctx context.Context, id string) (*OutboxItem, error) {
+	return o.store.Get(ctx, id)
+}
+
+// Run drains due items every interval until ctx is done
+func (o *Outbox) Run(ctx context.Context) error {
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := o.Drain(ctx); err != nil && ctx.Err() == nil {
+			o.logger.Error("Failed to drain outbox", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain sends the items that are due, at most the configured rate, and
+// returns how many the API accepted. Run calls it periodically. When the
+// API rate limits a send, the rest of the batch waits for a later drain.
+func (o *Outbox) Drain(ctx context.Context) (int, error) {
+	o.drainMu.Lock()
+	defer o.drainMu.Unlock()
+
+	o.mu.Lock()
+	paused := o.now().Before(o.pausedUntil)
+	o.mu.Unlock()
+	if paused {
+		return 0, nil
+	}
+
+	due, err := o.store.Due(ctx, o.now(), o.batch)
+	if err != nil {
+		return 0, fmt.Errorf("messages: failed to load due outbox items: %w", err)
+	}
+
+	var gap time.Duration
+	if o.rate > 0 {
+		gap = time.Duration(float64(time.Second) / o.rate)
+	}
+
+	sent := 0
+	for i, item := range due {
+		if i > 0 && gap > 0 {
+			if err := sleepContext(ctx, gap); err != nil {
+				return sent, err
+			}
+		}
+
+		ok, limited, err := o.send(ctx, item)
+		if err != nil {
+			return sent, err
+		}
+		if ok {
+			sent++
+		}
+		if limited {
+			break
+		}
+	}
+	return sent, nil
+}
+
+// send sends one item and saves the result. It reports whether the API
+// accepted it and whether the API is rate limiting.
+func (o *Outbox) send(ctx context.Context, item *OutboxItem) (ok, limited bool, err error) {
+	o.mu.Lock()
+	// A status webhook may have completed the item since it was loaded
+	if current, err := o.store.Get(ctx, item.ID); err == nil {
+		if current.Status.IsTerminal() {
+			o.mu.Unlock()
+			return false, false, nil
+		}
+		item = current
+	}
+	// A sent item is due again when its confirm timeout or deadline passed
+	if item.Status == OutboxStatusSent && (o.confirm <= 0 || item.Attempts >= o.retry.MaxAttempts) {
+		item.Status = OutboxStatusFailed
+		item.LastError = "no delivery status within the confirm timeout"
+		if o.confirm <= 0 {
+			item.Status = OutboxStatusUnconfirmed
+			item.LastError = "no delivery status within the confirm deadline"
+		}
+		item.NextAttempt = time.Time{}
+		item.UpdatedAt = o.now()
+		o.logger.Warn("Outbox item unconfirmed", "id", item.ID, "attempts", item.Attempts)
+		err := o.store.Save(ctx, item)
+		o.mu.Unlock()
+		if err != nil {
+			return false, false, fmt.Errorf("messages: failed to save outbox item: %w", err)
+		}
+		return false, false, nil
+	}
+	o.inflight++
+	o.mu.Unlock()
+
+	req := item.Request
+	resp, sendErr := o.client.Send(ctx, &req)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	defer o.sendDone()
+
+	// A status of an earlier send may have completed the item meanwhile
+	if current, err := o.store.Get(ctx, item.ID); err == nil && current.Status.IsTerminal() {
+		return sendErr == nil, false, nil
+	}
+
+	now := o.now()
+	item.UpdatedAt = now
+	switch {
+	case sendErr == nil:
+		item.Attempts++
+		item.Status = OutboxStatusSent
+		item.MessageUUID = resp.MessageUUID
+		item.LastError = ""
+		item.NextAttempt = time.Time{}
+		if o.confirm > 0 {
+			item.NextAttempt = now.Add(o.confirm)
+		} else if o.deadline > 0 {
+			item.NextAttempt = now.Add(o.deadline)
+		}
+		ok = true
+		o.logger.Debug("Outbox item sent", "id", item.ID, "messageUUID", resp.MessageUUID, "attempt", item.Attempts)
+
+		// The status webhook may have arrived before the API responded
+		if status, found := o.early[resp.MessageUUID]; found {
+			delete(o.early, resp.MessageUUID)
+			applyStatus(item, status, now)
+		}
+
+	case errors.Is(sendErr, vonage.ErrRateLimited):
+		// Not the item's fault: leave it due and hold back the whole outbox
+		limited = true
+		item.LastError = sendErr.Error()
+		o.pausedUntil = now.Add(o.retry.delay(1))
+		o.logger.Warn("Outbox rate limited", "id", item.ID, "until", o.pausedUntil)
+
+	case ctx.Err() != nil:
+		return false, false, ctx.Err()
+
+	default:
+		item.Attempts++
+		item.LastError = sendErr.Error()
+		o.retryOrFail(item, !errors.Is(sendErr, vonage.ErrInvalidRequest))
+		o.logger.Warn("Outbox send failed", "id", item.ID, "attempt", item.Attempts, "error", sendErr)
+	}
+
+	if err := o.store.Save(ctx, item); err != nil {
+		return ok, limited, fmt.Errorf("messages: failed to save outbox item: %w", err)
+	}
+	return ok, limited, nil
+}
+
+// sendDone ends a send. Early statuses no send claimed belong to messages
+// the outbox did not send. Called with mu held.
+func (o *Outbox) sendDone() {
+	o.inflight--
+	if o.inflight == 0 {
+		o.early = nil
+	}
+}
+
+// retryOrFail schedules the next attempt of item, or fails it
+func (o *Outbox) retryOrFail(item *OutboxItem, retryable bool) {
+	if !retryable || item.Attempts >= o.retry.MaxAttempts {
+		item.Status = OutboxStatusFailed
+		item.NextAttempt = time.Time{}
+		return
+	}
+	item.Status = OutboxStatusPending
+	item.NextAttempt = o.now().Add(o.retry.delay(item.Attempts))
+}
+
+// HandleStatus applies a status webhook to the item it belongs to: delivered
+// and read complete it, rejected and failed fail it. Statuses of messages
+// the outbox did not send, or of items it is done with, are ignored, except
+// that unconfirmed items still take their late status.
+func (o *Outbox) HandleStatus(ctx context.Context, status *MessageStatus) error {
+	if !status.Status.IsTerminal() {
+		return nil
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	item, err := o.store.GetByMessageUUID(ctx, status.MessageUUID)
+	if errors.Is(err, ErrOutboxItemNotFound) {
+		// The message may be one whose send has not returned yet
+		if o.inflight > 0 {
+			if o.early == nil {
+				o.early = make(map[string]*MessageStatus)
+			}
+			o.early[status.MessageUUID] = status
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if item.Status.IsTerminal() && item.Status != OutboxStatusUnconfirmed {
+		return nil
+	}
+
+	applyStatus(item, status, o.now())
+	return o.store.Save(ctx, item)
+}
+
+// applyStatus completes item with a final status webhook
+func applyStatus(item *OutboxItem, status *MessageStatus, now time.Time) {
+	item.LastStatus = status.Status
+	item.UpdatedAt = now
+	item.NextAttempt = time.Time{}
+	if status.Status.IsDelivered() {
+		item.Status = OutboxStatusDelivered
+	} else {
+		item.Status = OutboxStatusFailed
+		if status.Error != nil {
+			item.LastError = status.Error.Detail
+		}
+	}
+}
+
+// StatusHandler returns HandleStatus as a StatusHandler for
+// WebhookHandler.OnStatus
+func (o *Outbox) StatusHandler() StatusHandler {
+	return func(status *MessageStatus) error {
+		return o.HandleStatus(context.Background(), status)
+	}
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,366 @@
+package messages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// fakeSender implements Send of API with a function; other methods panic
+type fakeSender struct {
+	API
+	mu    sync.Mutex
+	calls []string
+	send  func(n int, req *SendRequest) (*SendResponse, error)
+}
+
+func (f *fakeSender) Send(ctx context.Context, req *SendRequest) (*SendResponse, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, req.To)
+	n := len(f.calls)
+	f.mu.Unlock()
+	return f.send(n, req)
+}
+
+func (f *fakeSender) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.calls)
+}
+
+// accept answers every send with a message UUID numbered by call
+func accept(n int, req *SendRequest) (*SendResponse, error) {
+	return &SendResponse{MessageUUID: fmt.Sprintf("MSG-%d", n)}, nil
+}
+
+// clock is a settable time source for the outbox
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func newTestOutbox(client API, opts ...OutboxOption) (*Outbox, *clock) {
+	clk := &clock{now: time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)}
+	opts = append([]OutboxOption{
+		WithOutboxRate(0),
+		WithOutboxRetry(OutboxRetry{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: time.Hour}),
+	}, opts...)
+	o := NewOutbox(client, opts...)
+	o.now = clk.Now
+	return o, clk
+}
+
+func enqueue(t *testing.T, o *Outbox, id, to string) {
+	t.Helper()
+	if _, err := o.Enqueue(context.Background(), id, &SendRequest{To: to, Channel: ChannelSMS, MessageType: MessageTypeText, Text: "hi"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func getItem(t *testing.T, o *Outbox, id string) *OutboxItem {
+	t.Helper()
+	item, err := o.Get(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return item
+}
+
+func TestOutboxRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		send         func(n int, req *SendRequest) (*SendResponse, error)
+		wantStatus   OutboxStatus
+		wantAttempts int
+	}{
+		{
+			name: "succeeds on third attempt",
+			send: func(n int, req *SendRequest) (*SendResponse, error) {
+				if n < 3 {
+					return nil, vonage.NewError(http.StatusInternalServerError, "")
+				}
+				return accept(n, req)
+			},
+			wantStatus:   OutboxStatusSent,
+			wantAttempts: 3,
+		},
+		{
+			name: "fails after max attempts",
+			send: func(n int, req *SendRequest) (*SendResponse, error) {
+				return nil, vonage.NewError(http.StatusInternalServerError, "")
+			},
+			wantStatus:   OutboxStatusFailed,
+			wantAttempts: 3,
+		},
+		{
+			name: "invalid request is not retried",
+			send: func(n int, req *SendRequest) (*SendResponse, error) {
+				return nil, vonage.NewError(http.StatusUnprocessableEntity, "")
+			},
+			wantStatus:   OutboxStatusFailed,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, clk := newTestOutbox(&fakeSender{send: tt.send})
+			enqueue(t, o, "item-1", "447700900001")
+
+			for i := 0; i < 5; i++ {
+				if _, err := o.Drain(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+				clk.Advance(time.Hour)
+			}
+
+			item := getItem(t, o, "item-1")
+			if item.Status != tt.wantStatus || item.Attempts != tt.wantAttempts {
+				t.Errorf("item = %s after %d attempts, want %s after %d", item.Status, item.Attempts, tt.wantStatus, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestOutboxRetryBackoff(t *testing.T) {
+	client := &fakeSender{send: func(n int, req *SendRequest) (*SendResponse, error) {
+		return nil, vonage.NewError(http.StatusInternalServerError, "")
+	}}
+	o, clk := newTestOutbox(client)
+	enqueue(t, o, "item-1", "447700900001")
+
+	o.Drain(context.Background())
+	clk.Advance(59 * time.Second)
+	o.Drain(context.Background())
+	if got := client.count(); got != 1 {
+		t.Fatalf("sends before backoff = %d, want 1", got)
+	}
+	clk.Advance(time.Second)
+	o.Drain(context.Background())
+	if got := client.count(); got != 2 {
+		t.Fatalf("sends after backoff = %d, want 2", got)
+	}
+}
+
+func TestOutboxRateLimited(t *testing.T) {
+	limited := true
+	client := &fakeSender{send: func(n int, req *SendRequest) (*SendResponse, error) {
+		if limited {
+			return nil, vonage.NewError(http.StatusTooManyRequests, "")
+		}
+		return accept(n, req)
+	}}
+	o, clk := newTestOutbox(client)
+	enqueue(t, o, "item-1", "447700900001")
+	enqueue(t, o, "item-2", "447700900002")
+	ctx := context.Background()
+
+	// The first send is rate limited and the rest of the batch waits
+	if sent, err := o.Drain(ctx); sent != 0 || err != nil {
+		t.Fatalf("Drain() = %d, %v, want 0, nil", sent, err)
+	}
+	if got := client.count(); got != 1 {
+		t.Fatalf("sends = %d, want 1", got)
+	}
+
+	// The outbox stays paused for the first backoff
+	limited = false
+	clk.Advance(30 * time.Second)
+	if sent, _ := o.Drain(ctx); sent != 0 {
+		t.Fatalf("Drain() while paused sent %d, want 0", sent)
+	}
+
+	clk.Advance(30 * time.Second)
+	if sent, _ := o.Drain(ctx); sent != 2 {
+		t.Fatalf("Drain() after pause sent %d, want 2", sent)
+	}
+	// Rate limiting does not count as an attempt
+	if item := getItem(t, o, "item-1"); item.Attempts != 1 {
+		t.Errorf("attempts = %d, want 1", item.Attempts)
+	}
+}
+
+func TestOutboxConfirmTimeout(t *testing.T) {
+	client := &fakeSender{send: accept}
+	o, clk := newTestOutbox(client, WithConfirmTimeout(10*time.Minute))
+	enqueue(t, o, "item-1", "447700900001")
+	ctx := context.Background()
+
+	// No status webhook ever arrives
+	for i := 0; i < 6; i++ {
+		o.Drain(ctx)
+		clk.Advance(10 * time.Minute)
+	}
+
+	if got := client.count(); got != 3 {
+		t.Errorf("sends = %d, want 3", got)
+	}
+	item := getItem(t, o, "item-1")
+	if item.Status != OutboxStatusFailed || item.LastError == "" {
+		t.Errorf("item = %s (%q), want failed with an error", item.Status, item.LastError)
+	}
+	if due, _ := o.store.Due(ctx, clk.Now().Add(24*time.Hour), 0); len(due) != 0 {
+		t.Errorf("Due() = %d items, want 0", len(due))
+	}
+}
+
+func TestOutboxConfirmed(t *testing.T) {
+	client := &fakeSender{send: accept}
+	o, clk := newTestOutbox(client, WithConfirmTimeout(10*time.Minute))
+	enqueue(t, o, "item-1", "447700900001")
+	ctx := context.Background()
+
+	o.Drain(ctx)
+	if err := o.HandleStatus(ctx, &MessageStatus{MessageUUID: "MSG-1", Status: StatusDelivered}); err != nil {
+		t.Fatal(err)
+	}
+	clk.Advance(time.Hour)
+	o.Drain(ctx)
+
+	if got := client.count(); got != 1 {
+		t.Errorf("sends = %d, want 1", got)
+	}
+	if item := getItem(t, o, "item-1"); item.Status != OutboxStatusDelivered {
+		t.Errorf("status = %s, want delivered", item.Status)
+	}
+}
+
+func TestOutboxStorePersistence(t *testing.T) {
+	store := NewMemoryOutboxStore()
+	ctx := context.Background()
+
+	// The first process enqueues and stops before draining
+	first, _ := newTestOutbox(&fakeSender{send: accept}, WithOutboxStore(store))
+	enqueue(t, first, "item-1", "447700900001")
+	enqueue(t, first, "item-2", "447700900002")
+
+	// The next one sends the stored items, and enqueueing again is a no-op
+	client := &fakeSender{send: accept}
+	second, _ := newTestOutbox(client, WithOutboxStore(store))
+	enqueue(t, second, "item-1", "447700900001")
+	if sent, err := second.Drain(ctx); sent != 2 || err != nil {
+		t.Fatalf("Drain() = %d, %v, want 2, nil", sent, err)
+	}
+	if sent, _ := second.Drain(ctx); sent != 0 {
+		t.Errorf("second Drain() sent %d, want 0", sent)
+	}
+
+	item, err := store.Get(ctx, "item-2")
+	if err != nil || item.Status != OutboxStatusSent || item.MessageUUID == "" {
+		t.Errorf("stored item = %+v, %v, want sent with a message UUID", item, err)
+	}
+}
+
+func TestOutboxSendDoesNotBlockStatus(t *testing.T) {
+	release := make(chan struct{})
+	var o *Outbox
+	client := &fakeSender{send: func(n int, req *SendRequest) (*SendResponse, error) {
+		// item-2 may be enqueued in time to be drained too, even first
+		uuid := "MSG-" + req.To
+		if req.To == "447700900001" {
+			// The status webhook arrives before the API responds
+			o.HandleStatus(context.Background(), &MessageStatus{MessageUUID: uuid, Status: StatusDelivered})
+			<-release
+		}
+		return &SendResponse{MessageUUID: uuid}, nil
+	}}
+	o, _ = newTestOutbox(client)
+	enqueue(t, o, "item-1", "447700900001")
+
+	done := make(chan struct{})
+	go func() {
+		o.Drain(context.Background())
+		close(done)
+	}()
+
+	// Other webhooks and enqueues go ahead during the send
+	handled := make(chan struct{})
+	go func() {
+		o.HandleStatus(context.Background(), &MessageStatus{MessageUUID: "OTHER", Status: StatusDelivered})
+		enqueue(t, o, "item-2", "447700900002")
+		close(handled)
+	}()
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("HandleStatus blocked behind Send")
+	}
+
+	close(release)
+	<-done
+	if item := getItem(t, o, "item-1"); item.Status != OutboxStatusDelivered {
+		t.Errorf("status = %s, want delivered from the early webhook", item.Status)
+	}
+}
+
+func TestOutboxConfirmDeadline(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []OutboxOption
+		wait       time.Duration
+		wantStatus OutboxStatus
+	}{
+		{"within the default deadline", nil, DefaultConfirmDeadline - time.Minute, OutboxStatusSent},
+		{"past the default deadline", nil, DefaultConfirmDeadline, OutboxStatusUnconfirmed},
+		{"past a custom deadline", []OutboxOption{WithConfirmDeadline(time.Hour)}, time.Hour, OutboxStatusUnconfirmed},
+		{"without a deadline", []OutboxOption{WithConfirmDeadline(0)}, 365 * 24 * time.Hour, OutboxStatusSent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeSender{send: accept}
+			o, clk := newTestOutbox(client, append(tt.opts, WithOutboxCapacity(1))...)
+			enqueue(t, o, "item-1", "447700900001")
+			ctx := context.Background()
+
+			o.Drain(ctx)
+			clk.Advance(tt.wait)
+			o.Drain(ctx)
+
+			if got := client.count(); got != 1 {
+				t.Errorf("sends = %d, want 1", got)
+			}
+			if item := getItem(t, o, "item-1"); item.Status != tt.wantStatus {
+				t.Fatalf("status = %s, want %s", item.Status, tt.wantStatus)
+			}
+
+			// Unconfirmed items no longer count against the capacity
+			_, err := o.Enqueue(ctx, "item-2", &SendRequest{To: "447700900002", Channel: ChannelSMS, MessageType: MessageTypeText, Text: "hi"})
+			if full := errors.Is(err, ErrOutboxFull); full != (tt.wantStatus == OutboxStatusSent) {
+				t.Errorf("Enqueue() = %v with the first item %s", err, tt.wantStatus)
+			}
+		})
+	}
+
+	// A late status still completes an unconfirmed item
+	o, clk := newTestOutbox(&fakeSender{send: accept})
+	enqueue(t, o, "item-1", "447700900001")
+	ctx := context.Background()
+	o.Drain(ctx)
+	clk.Advance(DefaultConfirmDeadline)
+	o.Drain(ctx)
+	if err := o.HandleStatus(ctx, &MessageStatus{MessageUUID: "MSG-1", Status: StatusDelivered}); err != nil {
+		t.Fatal(err)
+	}
+	if item := getItem(t, o, "item-1"); item.Status != OutboxStatusDelivered {
+		t.Errorf("status after a late status = %s, want delivered", item.Status)
+	}
+}
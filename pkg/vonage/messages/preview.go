@@ -0,0 +1,62 @@
+package messages
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ========================================
+// Preview
+// ========================================
+
+// Preview is a message rendered exactly as Send would send it
+type Preview struct {
+	// Requests are the resolved requests, with the sender and defaults
+	// applied; one per part when WithAutoSplit splits the text
+	Requests []*SendRequest
+	// Payloads are the JSON bodies Send would POST, one per request
+	Payloads []json.RawMessage
+}
+
+// Preview validates and renders req without sending it, e.g. for audit
+// logs and previews in a campaign builder. It resolves the sender as Send
+// does, but leaves req unchanged and charges no budget. Validation runs
+// even when the client was created WithoutValidation.
+//
+// A sender pool picks among equally weighted senders at random, so the
+// previewed sender may differ from the one a later Send picks.
+func (c *Client) Preview(req *SendRequest) (*Preview, error) {
+	resolved := *req
+	if err := c.resolve(&resolved); err != nil {
+		return nil, err
+	}
+
+	parts := []*SendRequest{&resolved}
+	if maxLen := c.splitLimit(&resolved); maxLen > 0 {
+		parts = parts[:0]
+		for _, text := range SplitText(resolved.Text, maxLen) {
+			part := resolved
+			part.Text = text
+			parts = append(parts, &part)
+		}
+	}
+
+	preview := &Preview{
+		Requests: parts,
+		Payloads: make([]json.RawMessage, len(parts)),
+	}
+	for i, part := range parts {
+		if err := part.Validate(); err != nil {
+			return nil, err
+		}
+		if err := c.validateTemplate(part); err != nil {
+			return nil, err
+		}
+		payload, err := json.Marshal(part)
+		if err != nil {
+			return nil, fmt.Errorf("messages: failed to encode request: %w", err)
+		}
+		preview.Payloads[i] = payload
+	}
+	return preview, nil
+}
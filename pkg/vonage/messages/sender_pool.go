@@ -0,0 +1,188 @@
+package messages
+
+import (
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ========================================
+// Sender Pool
+// ========================================
+
+// ErrNoSender is returned when no registered sender matches a message
+var ErrNoSender = errors.New("messages: no sender matches destination and channel")
+
+// Sender is a sender identity (phone number, WhatsApp number, Viber service
+// ID) together with the rules that decide when it may be used
+type Sender struct {
+	// ID is the value sent as "from"
+	ID string
+	// Channels restricts the sender to these channels (empty = any)
+	Channels []Channel
+	// CountryPrefixes restricts the sender to destinations starting with one
+	// of these country calling codes, e.g. "81" (empty = any)
+	CountryPrefixes []string
+	// Weight is the relative share of traffic among equally specific
+	// matches (default 1)
+	Weight int
+}
+
+// SenderStats holds per-sender throughput accounting
+type SenderStats struct {
+	ID       string
+	Sent     int64
+	Failed   int64
+	LastUsed time.Time
+}
+
+// SenderPool picks a sender for each message from a set of registered
+// identities. It is safe for concurrent use.
+type SenderPool struct {
+	mu      sync.Mutex
+	senders []Sender
+	stats   map[string]*SenderStats
+}
+
+// NewSenderPool creates an empty sender pool
+func NewSenderPool() *SenderPool {
+	return &SenderPool{
+		stats: make(map[string]*SenderStats),
+	}
+}
+
+// Add registers a sender
+func (p *SenderPool) Add(s Sender) *SenderPool {
+	if s.Weight <= 0 {
+		s.Weight = 1
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.senders = append(p.senders, s)
+	if _, ok := p.stats[s.ID]; !ok {
+		p.stats[s.ID] = &SenderStats{ID: s.ID}
+	}
+	return p
+}
+
+// Pick selects a sender for the destination and channel. Senders with a
+// matching country prefix win over catch-all senders (the longest prefix
+// wins); ties are broken by weighted random choice.
+func (p *SenderPool) Pick(channel Channel, to string) (string, error) {
+	to = strings.TrimPrefix(to, "+")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var candidates []Sender
+	best := -1
+	for _, s := range p.senders {
+		if len(s.Channels) > 0 && !containsChannel(s.Channels, channel) {
+			continue
+		}
+		specificity, ok := prefixMatch(s.CountryPrefixes, to)
+		if !ok {
+			continue
+		}
+		switch {
+		case specificity > best:
+			best = specificity
+			candidates = []Sender{s}
+		case specificity == best:
+			candidates = append(candidates, s)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", ErrNoSender
+	}
+	return weightedChoice(candidates).ID, nil
+}
+
+// Stats returns a snapshot of per-sender throughput
+func (p *SenderPool) Stats() []SenderStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]SenderStats, 0, len(p.stats))
+	for _, s := range p.senders {
+		if st, ok := p.stats[s.ID]; ok {
+			out = append(out, *st)
+		}
+	}
+	return dedupeStats(out)
+}
+
+// record updates the accounting for a sender; unknown IDs are ignored
+func (p *SenderPool) record(id string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	st, ok := p.stats[id]
+	if !ok {
+		return
+	}
+	st.LastUsed = time.Now()
+	if err != nil {
+		st.Failed++
+	} else {
+		st.Sent++
+	}
+}
+
+// prefixMatch returns the length of the longest matching prefix (0 for a
+// catch-all sender) and whether the sender applies at all
+func prefixMatch(prefixes []string, to string) (int, bool) {
+	if len(prefixes) == 0 {
+		return 0, true
+	}
+	longest, ok := 0, false
+	for _, prefix := range prefixes {
+		prefix = strings.TrimPrefix(prefix, "+")
+		if strings.HasPrefix(to, prefix) && len(prefix) >= longest {
+			longest, ok = len(prefix), true
+		}
+	}
+	return longest, ok
+}
+
+func weightedChoice(senders []Sender) Sender {
+	if len(senders) == 1 {
+		return senders[0]
+	}
+	total := 0
+	for _, s := range senders {
+		total += s.Weight
+	}
+	n := rand.IntN(total)
+	for _, s := range senders {
+		if n < s.Weight {
+			return s
+		}
+		n -= s.Weight
+	}
+	return senders[len(senders)-1]
+}
+
+func containsChannel(channels []Channel, ch Channel) bool {
+	for _, c := range channels {
+		if c == ch {
+			return true
+		}
+	}
+	return false
+}
+
+func dedupeStats(stats []SenderStats) []SenderStats {
+	seen := make(map[string]bool, len(stats))
+	out := stats[:0]
+	for _, st := range stats {
+		if !seen[st.ID] {
+			seen[st.ID] = true
+			out = append(out, st)
+		}
+	}
+	return out
+}
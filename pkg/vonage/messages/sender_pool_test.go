@@ -0,0 +1,121 @@
+package messages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+func newTestPool() *SenderPool {
+	return NewSenderPool().
+		Add(Sender{ID: "JP-SMS", Channels: []Channel{ChannelSMS}, CountryPrefixes: []string{"81"}}).
+		Add(Sender{ID: "JP-TOKYO", Channels: []Channel{ChannelSMS}, CountryPrefixes: []string{"+813"}}).
+		Add(Sender{ID: "UK-WA", Channels: []Channel{ChannelWhatsApp}, CountryPrefixes: []string{"44"}})
+}
+
+func TestSenderPoolPick(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel Channel
+		to      string
+		want    string
+		wantErr error
+	}{
+		{"country prefix", ChannelSMS, "819012345678", "JP-SMS", nil},
+		{"longest prefix wins", ChannelSMS, "+81312345678", "JP-TOKYO", nil},
+		{"channel restricted", ChannelWhatsApp, "447700900000", "UK-WA", nil},
+		{"wrong channel", ChannelSMS, "447700900000", "", ErrNoSender},
+		{"no prefix matches", ChannelSMS, "15551234567", "", ErrNoSender},
+	}
+
+	pool := newTestPool()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pool.Pick(tt.channel, tt.to)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("Pick() = %q, %v, want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// newFromServer answers message sends and records each request's From
+func newFromServer(t *testing.T, from *[]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req SendRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		*from = append(*from, req.From)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"message_uuid":"MSG-1"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSendSenderFallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		number  string
+		to      string
+		want    string
+		wantErr error
+	}{
+		{"pooled sender", "81501234567", "819012345678", "JP-SMS", nil},
+		{"default number when none matches", "81501234567", "15551234567", "81501234567", nil},
+		{"no default number", "", "15551234567", "", ErrNoSender},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var from []string
+			srv := newFromServer(t, &from)
+			client := NewClient(nil,
+				WithTransport(vonage.NewTransport(srv.URL, nil)),
+				WithPhoneNumber(tt.number),
+				WithSenderPool(newTestPool()),
+			)
+
+			_, err := client.NewMessage().To(tt.to).SMS().Text("hello").Send(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Send() = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(from) != 1 || from[0] != tt.want {
+				t.Errorf("sent from %q, want %q", from, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendLeavesRequestUnchanged(t *testing.T) {
+	var from []string
+	srv := newFromServer(t, &from)
+	client := NewClient(nil,
+		WithTransport(vonage.NewTransport(srv.URL, nil)),
+		WithWebhookVersion("v1"),
+		WithSenderPool(newTestPool()),
+	)
+
+	req := &SendRequest{To: "819012345678", Channel: ChannelSMS, MessageType: MessageTypeText, Text: "hello"}
+	for _, to := range []string{"819012345678", "+81312345678"} {
+		req.To = to
+		if _, err := client.Send(context.Background(), req); err != nil {
+			t.Fatal(err)
+		}
+		if req.From != "" || req.WebhookVersion != "" {
+			t.Fatalf("Send() set From %q and WebhookVersion %q on the caller's request", req.From, req.WebhookVersion)
+		}
+	}
+
+	// Reusing the request picks a sender for each destination
+	if want := []string{"JP-SMS", "JP-TOKYO"}; len(from) != 2 || from[0] != want[0] || from[1] != want[1] {
+		t.Errorf("sent from %q, want %q", from, want)
+	}
+}
@@ -0,0 +1,111 @@
+package messages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ========================================
+// Long Message Splitting
+// ========================================
+
+// SplitError is returned when a split send fails part-way. The parts sent
+// before the failure are available in Sent.
+type SplitError struct {
+	// Part is the 1-based index of the part that failed
+	Part  int
+	Total int
+	Sent  []*SendResponse
+	Err   error
+}
+
+func (e *SplitError) Error() string {
+	return fmt.Sprintf("messages: split send failed at part %d/%d: %v", e.Part, e.Total, e.Err)
+}
+
+func (e *SplitError) Unwrap() error {
+	return e.Err
+}
+
+// sendSplit sends a long text as sequential parts. Each part is only sent
+// after the previous one was accepted, so parts are submitted in order.
+func (c *Client) sendSplit(ctx context.Context, req *SendRequest, maxLen int) (*SendResponse, error) {
+	parts := SplitText(req.Text, maxLen)
+	results := make([]*SendResponse, 0, len(parts))
+
+	for i, text := range parts {
+		partReq := *req
+		partReq.Text = text
+
+		resp, err := c.sendOne(ctx, &partReq)
+		if err != nil {
+			return nil, &SplitError{Part: i + 1, Total: len(parts), Sent: results, Err: err}
+		}
+		results = append(results, resp)
+	}
+
+	return &SendResponse{
+		MessageUUID: results[0].MessageUUID,
+		Parts:       results,
+	}, nil
+}
+
+// splitLimit returns the max text length to split at, or 0 if the request
+// does not need splitting
+func (c *Client) splitLimit(req *SendRequest) int {
+	if !c.autoSplit || req.MessageType != MessageTypeText {
+		return 0
+	}
+	caps, ok := Capabilities(req.Channel)
+	if !ok || caps.MaxTextLength == 0 || utf8.RuneCountInString(req.Text) <= caps.MaxTextLength {
+		return 0
+	}
+	return caps.MaxTextLength
+}
+
+// SplitText splits text into parts of at most maxLen characters. It prefers
+// to break after a newline, then after sentence punctuation (including
+// Japanese 。！？), then at whitespace, and only cuts mid-word as a last resort.
+func SplitText(text string, maxLen int) []string {
+	if maxLen <= 0 || utf8.RuneCountInString(text) <= maxLen {
+		return []string{text}
+	}
+
+	var parts []string
+	runes := []rune(text)
+	for len(runes) > maxLen {
+		cut := breakPoint(runes[:maxLen])
+		part := strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace)
+		if part != "" {
+			parts = append(parts, part)
+		}
+		runes = []rune(strings.TrimLeftFunc(string(runes[cut:]), unicode.IsSpace))
+	}
+	if len(runes) > 0 {
+		parts = append(parts, string(runes))
+	}
+	return parts
+}
+
+// breakPoint returns the index after which window should be cut
+func breakPoint(window []rune) int {
+	// Don't produce tiny fragments: only look for breaks in the second half
+	floor := len(window) / 2
+
+	for _, isBreak := range []func(rune) bool{
+		func(r rune) bool { return r == '\n' },
+		func(r rune) bool { return strings.ContainsRune(".!?。！？", r) },
+		unicode.IsSpace,
+		func(r rune) bool { return strings.ContainsRune("、，,", r) },
+	} {
+		for i := len(window) - 1; i >= floor; i-- {
+			if isBreak(window[i]) {
+				return i + 1
+			}
+		}
+	}
+	return len(window)
+}
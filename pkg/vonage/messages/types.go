@@ -28,6 +28,10 @@ const (
 	MessageTypeFile     MessageType = "file"
 	MessageTypeCustom   MessageType = "custom"
 	MessageTypeTemplate MessageType = "template"
+
+	// Inbound-only message types
+	MessageTypeReply MessageType = "reply"
+	MessageTypeOrder MessageType = "order"
 )
 
 // ========================================
@@ -51,6 +55,9 @@ type SendRequest struct {
 	// WhatsApp specific
 	WhatsApp *WhatsAppOptions `json:"whatsapp,omitempty"`
 
+	// Viber specific
+	Viber *ViberOptions `json:"viber_service,omitempty"`
+
 	// Client reference (for matching status webhooks)
 	ClientRef string `json:"client_ref,omitempty"`
 
@@ -62,6 +69,10 @@ type SendRequest struct {
 // SendResponse represents the Vonage Messages API response
 type SendResponse struct {
 	MessageUUID string `json:"message_uuid"`
+
+	// Parts holds the per-part results, in send order, when the text was
+	// split by WithAutoSplit. MessageUUID is then the first part's UUID.
+	Parts []*SendResponse `json:"-"`
 }
 
 // MediaContent represents media content for MMS/WhatsApp messages
@@ -69,6 +80,8 @@ type MediaContent struct {
 	URL     string `json:"url"`
 	Caption string `json:"caption,omitempty"`
 	Name    string `json:"name,omitempty"`
+	// ThumbURL is the video thumbnail (required for Viber video)
+	ThumbURL string `json:"thumb_url,omitempty"`
 }
 
 // WhatsAppOptions contains WhatsApp-specific message options
@@ -89,6 +102,34 @@ type WhatsAppTemplateParam struct {
 	Default string `json:"default"`
 }
 
+// ViberCategory is the Viber message category
+type ViberCategory string
+
+const (
+	ViberCategoryTransaction ViberCategory = "transaction"
+	ViberCategoryPromotion   ViberCategory = "promotion"
+)
+
+// ViberOptions contains Viber-specific message options (viber_service)
+type ViberOptions struct {
+	Category ViberCategory `json:"category,omitempty"`
+	// TTL is the delivery time-to-live in seconds
+	TTL  int    `json:"ttl,omitempty"`
+	Type string `json:"type,omitempty"`
+	// Action adds a button to text and image messages
+	Action *ViberAction `json:"action,omitempty"`
+	// Duration is the video length in seconds (required for video)
+	Duration string `json:"duration,omitempty"`
+	// FileSize is the video size in MB (required for video)
+	FileSize string `json:"file_size,omitempty"`
+}
+
+// ViberAction is a Viber call-to-action button
+type ViberAction struct {
+	URL  string `json:"url"`
+	Text string `json:"text"`
+}
+
 // ========================================
 // Inbound Message (Webhook)
 // ========================================
@@ -111,13 +152,87 @@ type InboundMessage struct {
 	Audio *InboundMedia `json:"audio,omitempty"`
 	Video *InboundMedia `json:"video,omitempty"`
 	File  *InboundMedia `json:"file,omitempty"`
+
+	// Viber specific
+	Viber *ViberOptions `json:"viber_service,omitempty"`
+
+	// WhatsApp specific
+	Context  *InboundContext  `json:"context,omitempty"`
+	Profile  *InboundProfile  `json:"profile,omitempty"`
+	Reply    *InboundReply    `json:"reply,omitempty"`
+	Order    *InboundOrder    `json:"order,omitempty"`
+	WhatsApp *InboundWhatsApp `json:"whatsapp,omitempty"`
+}
+
+// IsReply returns true if the message quotes an earlier message
+func (m *InboundMessage) IsReply() bool {
+	return m.Context != nil && m.Context.MessageUUID != ""
+}
+
+// SenderName returns the sender's profile name, if provided
+func (m *InboundMessage) SenderName() string {
+	if m.Profile == nil {
+		return ""
+	}
+	return m.Profile.Name
+}
+
+// InboundContext identifies the message the user replied to (quoted)
+type InboundContext struct {
+	MessageUUID string `json:"message_uuid"`
+	MessageFrom string `json:"message_from,omitempty"`
+}
+
+// InboundProfile is the sender's WhatsApp profile
+type InboundProfile struct {
+	Name string `json:"name"`
+}
+
+// InboundReply is the user's selection from an interactive button or list
+type InboundReply struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// InboundOrder is a WhatsApp catalog order
+type InboundOrder struct {
+	CatalogID    string             `json:"catalog_id"`
+	ProductItems []InboundOrderItem `json:"product_items"`
+}
+
+// InboundOrderItem is a single product line in an order
+type InboundOrderItem struct {
+	ProductRetailerID string `json:"product_retailer_id"`
+	Quantity          string `json:"quantity"`
+	ItemPrice         string `json:"item_price"`
+	Currency          string `json:"currency"`
+}
+
+// InboundWhatsApp holds WhatsApp-specific inbound metadata
+type InboundWhatsApp struct {
+	Referral *InboundReferral `json:"referral,omitempty"`
+}
+
+// InboundReferral describes the ad or post that led the user to message
+// (click-to-WhatsApp)
+type InboundReferral struct {
+	Body       string `json:"body,omitempty"`
+	Headline   string `json:"headline,omitempty"`
+	SourceID   string `json:"source_id,omitempty"`
+	SourceType string `json:"source_type,omitempty"`
+	SourceURL  string `json:"source_url,omitempty"`
+	MediaType  string `json:"media_type,omitempty"`
+	MediaURL   string `json:"media_url,omitempty"`
+	CtwaClid   string `json:"ctwa_clid,omitempty"`
 }
 
 // InboundMedia represents media in an inbound message
 type InboundMedia struct {
-	URL     string `json:"url"`
-	Caption string `json:"caption,omitempty"`
-	Name    string `json:"name,omitempty"`
+	URL      string `json:"url"`
+	Caption  string `json:"caption,omitempty"`
+	Name     string `json:"name,omitempty"`
+	ThumbURL string `json:"thumb_url,omitempty"`
 }
 
 // InboundSMS represents a legacy inbound SMS webhook payload
@@ -215,6 +330,34 @@ func WithClientRef(ref string) SendOption {
 	}
 }
 
+// WithViberAction adds a Viber call-to-action button
+func WithViberAction(url, text string) SendOption {
+	return func(r *SendRequest) {
+		viberOptions(r).Action = &ViberAction{URL: url, Text: text}
+	}
+}
+
+// WithViberCategory sets the Viber message category
+func WithViberCategory(category ViberCategory) SendOption {
+	return func(r *SendRequest) {
+		viberOptions(r).Category = category
+	}
+}
+
+// WithViberTTL sets the Viber delivery time-to-live in seconds
+func WithViberTTL(seconds int) SendOption {
+	return func(r *SendRequest) {
+		viberOptions(r).TTL = seconds
+	}
+}
+
+func viberOptions(r *SendRequest) *ViberOptions {
+	if r.Viber == nil {
+		r.Viber = &ViberOptions{}
+	}
+	return r.Viber
+}
+
 // WithWebhookURL overrides the status webhook URL for this message
 func WithWebhookURL(url string) SendOption {
 	return func(r *SendRequest) {
@@ -0,0 +1,243 @@
+package messages
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ========================================
+// Channel Capability Matrix
+// ========================================
+
+// ChannelCapabilities describes what a channel can deliver
+type ChannelCapabilities struct {
+	// MessageTypes lists the message types the channel accepts
+	MessageTypes []MessageType
+	// MaxTextLength is the maximum text length in characters (0 = unlimited)
+	MaxTextLength int
+	// CaptionTypes lists the media message types that accept a caption
+	CaptionTypes []MessageType
+	// USOnly restricts delivery to US/Canada (+1) numbers
+	USOnly bool
+	// MaxMediaBytes is the maximum media size per message type
+	MaxMediaBytes map[MessageType]int64
+}
+
+// Supports returns true if the channel accepts the given message type
+func (c ChannelCapabilities) Supports(t MessageType) bool {
+	return containsType(c.MessageTypes, t)
+}
+
+// SupportsCaption returns true if the given media type accepts a caption
+func (c ChannelCapabilities) SupportsCaption(t MessageType) bool {
+	return containsType(c.CaptionTypes, t)
+}
+
+var channelCapabilities = map[Channel]ChannelCapabilities{
+	ChannelSMS: {
+		MessageTypes:  []MessageType{MessageTypeText},
+		MaxTextLength: 1000,
+	},
+	ChannelMMS: {
+		MessageTypes:  []MessageType{MessageTypeText, MessageTypeImage, MessageTypeAudio, MessageTypeVideo},
+		MaxTextLength: 2000,
+		CaptionTypes:  []MessageType{MessageTypeImage, MessageTypeVideo},
+		USOnly:        true,
+		MaxMediaBytes: map[MessageType]int64{
+			MessageTypeImage: 5 * mb,
+			MessageTypeAudio: 5 * mb,
+			MessageTypeVideo: 5 * mb,
+		},
+	},
+	ChannelWhatsApp: {
+		MessageTypes: []MessageType{
+			MessageTypeText, MessageTypeImage, MessageTypeAudio, MessageTypeVideo,
+			MessageTypeFile, MessageTypeTemplate, MessageTypeCustom,
+		},
+		MaxTextLength: 4096,
+		CaptionTypes:  []MessageType{MessageTypeImage, MessageTypeVideo, MessageTypeFile},
+		MaxMediaBytes: map[MessageType]int64{
+			MessageTypeImage: 5 * mb,
+			MessageTypeAudio: 16 * mb,
+			MessageTypeVideo: 16 * mb,
+			MessageTypeFile:  100 * mb,
+		},
+	},
+	ChannelViber: {
+		MessageTypes:  []MessageType{MessageTypeText, MessageTypeImage, MessageTypeVideo, MessageTypeFile},
+		MaxTextLength: 1000,
+		CaptionTypes:  []MessageType{MessageTypeVideo},
+		MaxMediaBytes: map[MessageType]int64{
+			MessageTypeImage: 200 * kb,
+			MessageTypeVideo: 200 * mb,
+			MessageTypeFile:  200 * mb,
+		},
+	},
+	ChannelMessenger: {
+		MessageTypes:  []MessageType{MessageTypeText, MessageTypeImage, MessageTypeAudio, MessageTypeVideo, MessageTypeFile},
+		MaxTextLength: 640,
+		MaxMediaBytes: map[MessageType]int64{
+			MessageTypeImage: 25 * mb,
+			MessageTypeAudio: 25 * mb,
+			MessageTypeVideo: 25 * mb,
+			MessageTypeFile:  25 * mb,
+		},
+	},
+}
+
+const (
+	kb = 1024
+	mb = 1024 * kb
+)
+
+// Capabilities returns the capability matrix entry for a channel
+func Capabilities(channel Channel) (ChannelCapabilities, bool) {
+	caps, ok := channelCapabilities[channel]
+	return caps, ok
+}
+
+// ========================================
+// Validation Errors
+// ========================================
+
+// FieldError describes a single invalid field in a request
+type FieldError struct {
+	// Field is the JSON path of the offending field (e.g. "image.caption")
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Reason)
+}
+
+// ValidationError is returned when a request fails pre-send validation
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Error()
+	}
+	return "messages: invalid request: " + strings.Join(parts, "; ")
+}
+
+// ========================================
+// Request Validation
+// ========================================
+
+// Validate checks the request against the channel capability matrix. It
+// returns a *ValidationError listing every problem found, or nil.
+func (r *SendRequest) Validate() error {
+	var errs []FieldError
+	add := func(field, reason string, args ...interface{}) {
+		errs = append(errs, FieldError{Field: field, Reason: fmt.Sprintf(reason, args...)})
+	}
+
+	if r.To == "" {
+		add("to", "recipient is required")
+	}
+	if r.From == "" {
+		add("from", "sender is required")
+	}
+	if r.MessageType == "" {
+		add("message_type", "message type is required")
+	}
+
+	switch r.WebhookVersion {
+	case "", WebhookVersionV01, WebhookVersionV1:
+	default:
+		add("webhook_version", "must be %s or %s, got %q", WebhookVersionV01, WebhookVersionV1, r.WebhookVersion)
+	}
+
+	caps, ok := Capabilities(r.Channel)
+	if !ok {
+		add("channel", "unsupported channel %q", r.Channel)
+		return validationResult(errs)
+	}
+
+	if r.MessageType != "" && !caps.Supports(r.MessageType) {
+		add("message_type", "%s messages are not supported on %s", r.MessageType, r.Channel)
+	}
+
+	if caps.USOnly && r.To != "" && !strings.HasPrefix(strings.TrimPrefix(r.To, "+"), "1") {
+		add("to", "%s is only available for US/Canada (+1) numbers", r.Channel)
+	}
+
+	if r.MessageType == MessageTypeText {
+		if r.Text == "" {
+			add("text", "text is required for text messages")
+		} else if caps.MaxTextLength > 0 && utf8.RuneCountInString(r.Text) > caps.MaxTextLength {
+			add("text", "text is %d characters, %s allows at most %d",
+				utf8.RuneCountInString(r.Text), r.Channel, caps.MaxTextLength)
+		}
+	}
+
+	media := []struct {
+		messageType MessageType
+		content     *MediaContent
+	}{
+		{MessageTypeImage, r.Image},
+		{MessageTypeAudio, r.Audio},
+		{MessageTypeVideo, r.Video},
+		{MessageTypeFile, r.File},
+	}
+	for _, m := range media {
+		field := string(m.messageType)
+		if m.messageType == r.MessageType && (m.content == nil || m.content.URL == "") {
+			add(field+".url", "media URL is required for %s messages", m.messageType)
+		}
+		if m.content != nil && m.content.Caption != "" && !caps.SupportsCaption(m.messageType) {
+			add(field+".caption", "captions are not supported for %s on %s", m.messageType, r.Channel)
+		}
+	}
+
+	if r.Channel == ChannelViber {
+		errs = append(errs, r.validateViber()...)
+	} else if r.Viber != nil {
+		add("viber_service", "viber_service options are only valid on %s", ChannelViber)
+	}
+
+	return validationResult(errs)
+}
+
+// validateViber applies the Viber-specific rich media rules
+func (r *SendRequest) validateViber() []FieldError {
+	var errs []FieldError
+	if r.Viber != nil && r.Viber.Action != nil {
+		if r.MessageType != MessageTypeText && r.MessageType != MessageTypeImage {
+			errs = append(errs, FieldError{Field: "viber_service.action", Reason: "buttons are only supported on text and image messages"})
+		}
+		if r.Viber.Action.URL == "" || r.Viber.Action.Text == "" {
+			errs = append(errs, FieldError{Field: "viber_service.action", Reason: "button requires both url and text"})
+		}
+	}
+	if r.MessageType == MessageTypeVideo {
+		if r.Video != nil && r.Video.ThumbURL == "" {
+			errs = append(errs, FieldError{Field: "video.thumb_url", Reason: "thumbnail is required for Viber video"})
+		}
+		if r.Viber == nil || r.Viber.Duration == "" || r.Viber.FileSize == "" {
+			errs = append(errs, FieldError{Field: "viber_service", Reason: "duration and file_size are required for Viber video"})
+		}
+	}
+	return errs
+}
+
+func validationResult(errs []FieldError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: errs}
+}
+
+func containsType(types []MessageType, t MessageType) bool {
+	for _, candidate := range types {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
@@ -1,12 +1,14 @@
 package messages
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
-	"github.com/rs/zerolog/log"
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
 )
 
 // ========================================
@@ -19,16 +21,43 @@ type InboundHandler func(msg *InboundMessage) error
 // StatusHandler is a function that handles message status updates
 type StatusHandler func(status *MessageStatus) error
 
+// UnknownMessage is an inbound webhook of a message type the SDK does not
+// model, such as a WhatsApp reaction, or of an unrecognized format
+type UnknownMessage struct {
+	// Body is the raw webhook body
+	Body []byte
+	// Channel, MessageType and MessageUUID are set when the body has them
+	Channel     Channel
+	MessageType string
+	MessageUUID string
+}
+
+// UnknownHandler is a function that handles unknown inbound messages
+type UnknownHandler func(msg *UnknownMessage) error
+
 // WebhookHandler provides HTTP handler functions for Vonage webhooks
 type WebhookHandler struct {
 	onInbound InboundHandler
 	onStatus  StatusHandler
 	onLegacy  func(sms *InboundSMS) error
+	onUnknown UnknownHandler
+
+	logger vonage.Logger
+
+	verifier *vonage.WebhookVerifier
+	sink     vonage.EventSink
+	metrics  vonage.WebhookMetricsHook
 }
 
 // NewWebhookHandler creates a new webhook handler
 func NewWebhookHandler() *WebhookHandler {
-	return &WebhookHandler{}
+	return &WebhookHandler{logger: vonage.NopLogger()}
+}
+
+// WithLogger sets the logger for webhook errors (default: no logging)
+func (h *WebhookHandler) WithLogger(l vonage.Logger) *WebhookHandler {
+	h.logger = l
+	return h
 }
 
 // OnInbound sets the handler for inbound messages (Messages API format)
@@ -49,50 +78,202 @@ func (h *WebhookHandler) OnLegacySMS(handler func(sms *InboundSMS) error) *Webho
 	return h
 }
 
+// OnUnknown sets the handler for inbound webhooks the SDK cannot model,
+// e.g. to persist them for audit. Messages of unknown types, which would
+// otherwise reach OnInbound with no content, and bodies of unrecognized
+// formats, which would otherwise be dropped, are passed to it instead.
+func (h *WebhookHandler) OnUnknown(handler UnknownHandler) *WebhookHandler {
+	h.onUnknown = handler
+	return h
+}
+
+// WithSignatureVerification rejects webhooks whose signature does not
+// match the credentials' signature secret: the Bearer JWT of signed
+// webhooks, or the sig field of legacy signed SMS
+func (h *WebhookHandler) WithSignatureVerification(creds *vonage.Credentials) *WebhookHandler {
+	return h.WithVerifier(vonage.NewWebhookVerifier(creds))
+}
+
+// WithVerifier is like WithSignatureVerification with a configured
+// verifier, e.g. one with replay protection
+func (h *WebhookHandler) WithVerifier(v *vonage.WebhookVerifier) *WebhookHandler {
+	h.verifier = v
+	return h
+}
+
+// WithEventSink publishes verified webhooks to sink as "messages.inbound",
+// "messages.status" and, with OnUnknown, "messages.unknown" events with the
+// parsed message, status or *UnknownMessage as Payload, before the
+// registered handlers run
+func (h *WebhookHandler) WithEventSink(sink vonage.EventSink) *WebhookHandler {
+	h.sink = sink
+	return h
+}
+
+// WithMetrics reports every verified webhook to hook, e.g. a
+// vonage.WebhookCollector: its type ("messages.inbound", "messages.status"
+// or "messages.unknown"), how long the registered handler took and the
+// error it returned. Bodies of unrecognized formats are reported as
+// unknown.
+func (h *WebhookHandler) WithMetrics(hook vonage.WebhookMetricsHook) *WebhookHandler {
+	h.metrics = hook
+	return h
+}
+
+// observe reports a handled webhook to the metrics hook, if any
+func (h *WebhookHandler) observe(ctx context.Context, typ string, start time.Time, err error) {
+	vonage.ObserveWebhook(ctx, h.metrics, vonage.WebhookObservation{
+		Source:  vonage.EventSourceMessages,
+		Type:    typ,
+		Latency: time.Since(start),
+		Err:     err,
+	})
+}
+
+// observeUnknown reports a webhook of an unrecognized format
+func (h *WebhookHandler) observeUnknown(ctx context.Context) {
+	vonage.ObserveWebhook(ctx, h.metrics, vonage.WebhookObservation{
+		Source:  vonage.EventSourceMessages,
+		Unknown: true,
+	})
+}
+
+// publish publishes a parsed webhook to the event sink, if any
+func (h *WebhookHandler) publish(ctx context.Context, typ string, body []byte, payload interface{}) {
+	if h.sink == nil {
+		return
+	}
+	event := vonage.NewEvent(vonage.EventSourceMessages, typ, body)
+	event.Payload = payload
+	vonage.PublishEvent(ctx, h.sink, h.logger, event)
+}
+
+// verify checks the webhook signature if verification is enabled
+func (h *WebhookHandler) verify(authorization string, body []byte) error {
+	if !h.verifier.Enabled() {
+		return nil
+	}
+	return h.verifier.Verify(authorization, body)
+}
+
 // HandleInbound returns an http.HandlerFunc for the inbound message webhook
 func (h *WebhookHandler) HandleInbound() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to read inbound webhook body")
+			h.logger.Error("Failed to read inbound webhook body", "error", err)
 			w.WriteHeader(http.StatusOK) // Always 200 for webhooks
 			return
 		}
 		defer r.Body.Close()
 
-		// Try Messages API format first
-		var msg InboundMessage
-		if err := json.Unmarshal(body, &msg); err == nil && msg.MessageUUID != "" {
-			if h.onInbound != nil {
-				if err := h.onInbound(&msg); err != nil {
-					log.Error().Err(err).Str("messageUUID", msg.MessageUUID).Msg("Error handling inbound message")
-				}
-			}
-			w.WriteHeader(http.StatusOK)
+		if err := h.verify(r.Header.Get("Authorization"), body); err != nil {
+			h.logger.Warn("Rejected inbound webhook", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
 
-		// Fall back to legacy SMS format
-		var sms InboundSMS
-		if err := json.Unmarshal(body, &sms); err == nil && sms.MSISDN != "" {
-			if h.onLegacy != nil {
-				if err := h.onLegacy(&sms); err != nil {
-					log.Error().Err(err).Str("messageID", sms.MessageID).Msg("Error handling legacy inbound SMS")
-				}
-			} else if h.onInbound != nil {
-				// Convert legacy to unified format
-				unified := sms.ToInboundMessage()
-				if err := h.onInbound(unified); err != nil {
-					log.Error().Err(err).Str("from", sms.MSISDN).Msg("Error handling converted inbound SMS")
-				}
-			}
-			w.WriteHeader(http.StatusOK)
+		h.processInbound(r.Context(), body)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// processInbound dispatches an inbound webhook body to the registered handlers
+func (h *WebhookHandler) processInbound(ctx context.Context, body []byte) {
+	// Try Messages API format first
+	var msg InboundMessage
+	if err := json.Unmarshal(body, &msg); err == nil && msg.MessageUUID != "" {
+		if h.onUnknown != nil && !isKnownInboundType(msg.MessageType) {
+			h.processUnknown(ctx, body)
 			return
 		}
+		h.publish(ctx, "messages.inbound", body, &msg)
+		start := time.Now()
+		var err error
+		if h.onInbound != nil {
+			if err = h.onInbound(&msg); err != nil {
+				h.logger.Error("Error handling inbound message",
+					"error", err,
+					"messageUUID", msg.MessageUUID,
+				)
+			}
+		}
+		h.observe(ctx, "messages.inbound", start, err)
+		return
+	}
 
-		log.Warn().Str("body", string(body)).Msg("Unknown inbound webhook format")
-		w.WriteHeader(http.StatusOK)
+	// Fall back to legacy SMS format
+	var sms InboundSMS
+	if err := json.Unmarshal(body, &sms); err == nil && sms.MSISDN != "" {
+		h.publish(ctx, "messages.inbound", body, &sms)
+		start := time.Now()
+		var err error
+		if h.onLegacy != nil {
+			if err = h.onLegacy(&sms); err != nil {
+				h.logger.Error("Error handling legacy inbound SMS",
+					"error", err,
+					"messageID", sms.MessageID,
+				)
+			}
+		} else if h.onInbound != nil {
+			// Convert legacy to unified format
+			unified := sms.ToInboundMessage()
+			if err = h.onInbound(unified); err != nil {
+				h.logger.Error("Error handling converted inbound SMS",
+					"error", err,
+					"from", sms.MSISDN,
+				)
+			}
+		}
+		h.observe(ctx, "messages.inbound", start, err)
+		return
 	}
+
+	if h.onUnknown != nil {
+		h.processUnknown(ctx, body)
+		return
+	}
+	h.logger.Warn("Unknown inbound webhook format", "body", string(body))
+	h.observeUnknown(ctx)
+}
+
+// processUnknown passes an inbound webhook the SDK cannot model to the
+// unknown handler
+func (h *WebhookHandler) processUnknown(ctx context.Context, body []byte) {
+	msg := &UnknownMessage{Body: body}
+	var probe struct {
+		Channel     Channel `json:"channel"`
+		MessageType string  `json:"message_type"`
+		MessageUUID string  `json:"message_uuid"`
+	}
+	if json.Unmarshal(body, &probe) == nil {
+		msg.Channel = probe.Channel
+		msg.MessageType = probe.MessageType
+		msg.MessageUUID = probe.MessageUUID
+	}
+
+	h.publish(ctx, "messages.unknown", body, msg)
+	start := time.Now()
+	err := h.onUnknown(msg)
+	if err != nil {
+		h.logger.Error("Error handling unknown inbound message",
+			"error", err,
+			"channel", string(msg.Channel),
+			"messageType", msg.MessageType,
+		)
+	}
+	h.observe(ctx, "messages.unknown", start, err)
+}
+
+// isKnownInboundType reports whether InboundMessage models the content of
+// a message type
+func isKnownInboundType(t string) bool {
+	switch MessageType(t) {
+	case MessageTypeText, MessageTypeImage, MessageTypeAudio, MessageTypeVideo,
+		MessageTypeFile, MessageTypeReply, MessageTypeOrder:
+		return true
+	}
+	return false
 }
 
 // HandleStatus returns an http.HandlerFunc for the message status webhook
@@ -100,32 +281,47 @@ func (h *WebhookHandler) HandleStatus() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to read status webhook body")
+			h.logger.Error("Failed to read status webhook body", "error", err)
 			w.WriteHeader(http.StatusOK)
 			return
 		}
 		defer r.Body.Close()
 
-		var status MessageStatus
-		if err := json.Unmarshal(body, &status); err != nil {
-			log.Warn().Str("body", string(body)).Msg("Failed to parse status webhook")
-			w.WriteHeader(http.StatusOK)
+		if err := h.verify(r.Header.Get("Authorization"), body); err != nil {
+			h.logger.Warn("Rejected status webhook", "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
 
-		if h.onStatus != nil {
-			if err := h.onStatus(&status); err != nil {
-				log.Error().Err(err).
-					Str("messageUUID", status.MessageUUID).
-					Str("status", string(status.Status)).
-					Msg("Error handling message status")
-			}
-		}
-
+		h.processStatus(r.Context(), body)
 		w.WriteHeader(http.StatusOK)
 	}
 }
 
+// processStatus dispatches a status webhook body to the registered handler
+func (h *WebhookHandler) processStatus(ctx context.Context, body []byte) {
+	var status MessageStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		h.logger.Warn("Failed to parse status webhook", "body", string(body))
+		h.observeUnknown(ctx)
+		return
+	}
+	h.publish(ctx, "messages.status", body, &status)
+
+	start := time.Now()
+	var err error
+	if h.onStatus != nil {
+		if err = h.onStatus(&status); err != nil {
+			h.logger.Error("Error handling message status",
+				"error", err,
+				"messageUUID", status.MessageUUID,
+				"status", string(status.Status),
+			)
+		}
+	}
+	h.observe(ctx, "messages.status", start, err)
+}
+
 // ========================================
 // Parse Helpers (for use with Echo/Gin/etc)
 // ========================================
@@ -0,0 +1,157 @@
+package vonage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ========================================
+// Mode
+// ========================================
+
+// EnvMode selects the Mode in NewCredentialsFromEnv: "live", "sandbox",
+// "dry-run" or "mock"
+const EnvMode = "VONAGE_MODE"
+
+// Mode controls whether clients talk to the Vonage API. It is set on
+// Credentials (WithMode) and picked up by the clients built from them, or
+// per client with each package's WithMode option.
+type Mode int
+
+const (
+	// ModeLive sends every request. This is the default.
+	ModeLive Mode = iota
+	// ModeDryRun logs each request that would change state (anything but
+	// GET and HEAD) instead of sending it, and answers it with an empty
+	// 202 response. Reads are still sent. Use it in staging environments
+	// that hold real customer numbers.
+	ModeDryRun
+	// ModeMock sends nothing: every request is logged and answered with an
+	// empty 202 response, and clients that can fake results do so (video
+	// returns mock sessions). For local development.
+	ModeMock
+	// ModeSandbox sends every request like ModeLive but marks a
+	// non-production environment, where safeguards such as the clients'
+	// WithAllowedDestinations apply. For staging environments that really
+	// send, to test numbers.
+	ModeSandbox
+)
+
+// String returns the mode's name as accepted by ParseMode
+func (m Mode) String() string {
+	switch m {
+	case ModeLive:
+		return "live"
+	case ModeDryRun:
+		return "dry-run"
+	case ModeMock:
+		return "mock"
+	case ModeSandbox:
+		return "sandbox"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// ParseMode parses a mode name. The empty string is ModeLive.
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "live":
+		return ModeLive, nil
+	case "dry-run", "dryrun", "dry_run":
+		return ModeDryRun, nil
+	case "mock":
+		return ModeMock, nil
+	case "sandbox":
+		return ModeSandbox, nil
+	default:
+		return ModeLive, fmt.Errorf("vonage: unknown mode %q", s)
+	}
+}
+
+// IsProduction reports whether m is ModeLive, the only mode in which
+// non-production safeguards are off
+func (m Mode) IsProduction() bool {
+	return m == ModeLive
+}
+
+// WithMode sets the mode of clients created from the credentials
+func WithMode(m Mode) CredentialsOption {
+	return func(c *Credentials) error {
+		c.Mode = m
+		return nil
+	}
+}
+
+// WithTransportMode sets the transport's mode (default ModeLive)
+func WithTransportMode(m Mode) TransportOption {
+	return func(t *Transport) {
+		t.mode = m
+	}
+}
+
+// skips reports whether the transport answers req itself instead of
+// sending it
+func (m Mode) skips(req *http.Request) bool {
+	switch m {
+	case ModeDryRun:
+		return req.Method != http.MethodGet && req.Method != http.MethodHead
+	case ModeMock:
+		return true
+	default:
+		return false
+	}
+}
+
+// dryRun logs req exactly as it would be sent, minus its credentials, and
+// answers it with an empty 202 response
+func (t *Transport) dryRun(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			body, _ = io.ReadAll(rc)
+			rc.Close()
+		}
+	}
+
+	LoggerFromContext(req.Context(), t.logger).Info("Vonage API request not sent",
+		"mode", t.mode.String(),
+		"method", req.Method,
+		"url", logURL(req.URL),
+		"contentType", req.Header.Get("Content-Type"),
+		"body", logBody(req.Header.Get("Content-Type"), body),
+	)
+
+	return &http.Response{
+		StatusCode: http.StatusAccepted,
+		Status:     "202 Accepted",
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader([]byte("{}"))),
+		Request:    req,
+	}, nil
+}
+
+// logBody returns body for logging, with the API secret and signature of
+// form-encoded bodies redacted as in logURL
+func logBody(contentType string, body []byte) string {
+	if contentType != "application/x-www-form-urlencoded" {
+		return string(body)
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return string(body)
+	}
+	for _, key := range []string{"api_secret", "sig"} {
+		if form.Has(key) {
+			form.Set(key, "REDACTED")
+		}
+	}
+	return form.Encode()
+}
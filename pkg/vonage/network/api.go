@@ -0,0 +1,23 @@
+package network
+
+import (
+	"context"
+	"time"
+)
+
+// ========================================
+// API Interface
+// ========================================
+
+// API is the Network API implemented by *Client. Application code can
+// depend on it and substitute vonagemock.Network in tests.
+type API interface {
+	CheckSimSwap(ctx context.Context, phoneNumber string, maxAge time.Duration) (bool, error)
+	SimSwapDate(ctx context.Context, phoneNumber string) (time.Time, error)
+	AuthURL(redirectURI, phoneNumber, state string) (string, error)
+	ExchangeCode(ctx context.Context, code, redirectURI string) (*Token, error)
+	VerifyNumber(ctx context.Context, token *Token, phoneNumber string) (bool, error)
+	CIBAToken(ctx context.Context, phoneNumber string, scope Scope) (*Token, error)
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,366 @@
+// Package network implements the CAMARA-based Network APIs: SIM Swap,
+// to check whether a number's SIM changed recently before sending it an
+// OTP, and Number Verification, to confirm a mobile device is using the
+// number it claims without sending an OTP at all.
+//
+// Both APIs need an access token bound to the phone number. SIM Swap gets
+// one server-side through the OpenID Connect CIBA flow, which the client
+// handles internally. Number Verification uses the authorization code flow:
+// the user's device opens AuthURL over its mobile data connection, and
+// the code delivered to the redirect URI is exchanged with ExchangeCode.
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+const (
+	// BaseURL is the Vonage Network APIs base URL
+	BaseURL = vonage.BaseURLRegional
+
+	// AuthBaseURL is the OpenID Connect provider used for Number
+	// Verification's authorization code flow
+	AuthBaseURL = vonage.BaseURLNetworkAuth
+
+	// DefaultSimSwapMaxAge is the period CheckSimSwap looks back over when
+	// maxAge is zero
+	DefaultSimSwapMaxAge = 240 * time.Hour
+
+	// MaxSimSwapMaxAge is the longest period the API accepts
+	MaxSimSwapMaxAge = 2400 * time.Hour
+)
+
+const cibaGrantType = "urn:openid:params:grant-type:ciba"
+
+var (
+	// ErrPhoneNumberRequired is returned when no phone number is given
+	ErrPhoneNumberRequired = errors.New("network: phone number is required")
+	// ErrInvalidMaxAge is returned when a SIM Swap maxAge is out of range
+	ErrInvalidMaxAge = errors.New("network: maxAge must be between 1 and 2400 hours")
+	// ErrTokenRequired is returned when calling Number Verification
+	// without an access token
+	ErrTokenRequired = errors.New("network: access token is required")
+	// ErrNoSimSwapDate is returned when the operator has no SIM change
+	// date for the number
+	ErrNoSimSwapDate = errors.New("network: no SIM change date available")
+)
+
+// Client handles Vonage Network API operations
+type Client struct {
+	baseURL      string
+	authBaseURL  string
+	appID        string
+	jwtGenerator *vonage.JWTGenerator
+	httpClient   *http.Client
+	middleware   []vonage.Middleware
+	logger       vonage.Logger
+	uaSuffix     string
+	mode         vonage.Mode
+
+	// oauth authenticates with the application JWT; camara with the
+	// access token carried in the request context
+	oauth  *vonage.Transport
+	camara *vonage.Transport
+}
+
+// ClientOption is a functional option for configuring the network client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the base URL (useful for testing)
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithAuthBaseURL overrides the OpenID Connect provider URL used by AuthURL
+func WithAuthBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.authBaseURL = url
+	}
+}
+
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l vonage.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier such as
+// "checkin-service/1.4" to the User-Agent
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.uaSuffix = suffix
+	}
+}
+
+// WithMiddleware adds transport middleware (retries, tracing, metrics)
+func WithMiddleware(mw ...vonage.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithMode sets the client's mode (default vonage.ModeLive). In
+// vonage.ModeMock no request is sent, and in vonage.ModeDryRun only
+// reads are.
+func WithMode(m vonage.Mode) ClientOption {
+	return func(c *Client) {
+		c.mode = m
+	}
+}
+
+// WithSettings applies the HTTP client, logger, middleware, User-Agent
+// suffix and mode shared by a root vonage.Client, see
+// vonage.Client.Settings
+func WithSettings(s vonage.Settings) ClientOption {
+	return func(c *Client) {
+		if s.HTTPClient != nil {
+			c.httpClient = s.HTTPClient
+		}
+		if s.Logger != nil {
+			c.logger = s.Logger
+		}
+		c.middleware = append(c.middleware, s.Middleware...)
+		c.uaSuffix = s.UserAgentSuffix
+		c.mode = s.Mode
+	}
+}
+
+// NewClient creates a new Vonage Network API client. The application must
+// have the Network APIs capability enabled.
+func NewClient(appID string, jwtGenerator *vonage.JWTGenerator, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:      BaseURL,
+		authBaseURL:  AuthBaseURL,
+		appID:        appID,
+		jwtGenerator: jwtGenerator,
+		httpClient:   &http.Client{Timeout: vonage.DefaultTimeout},
+		logger:       vonage.NopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	transportOpts := []vonage.TransportOption{
+		vonage.WithTransportHTTPClient(c.httpClient),
+		vonage.WithMiddleware(c.middleware...),
+		vonage.WithTransportLogger(c.logger),
+		vonage.WithTransportUserAgentSuffix(c.uaSuffix),
+		vonage.WithTransportMode(c.mode),
+	}
+	c.oauth = vonage.NewTransport(c.baseURL, jwtGenerator, transportOpts...)
+	c.camara = vonage.NewTransport(c.baseURL, contextToken{}, transportOpts...)
+
+	return c
+}
+
+// NewClientFromCredentials creates a new client from Vonage credentials
+func NewClientFromCredentials(creds *vonage.Credentials, opts ...ClientOption) (*Client, error) {
+	if !creds.HasApplication() {
+		return nil, vonage.ErrNotConfigured
+	}
+
+	jwtGen := vonage.NewJWTGeneratorFromCredentials(creds)
+	return NewClient(creds.AppID, jwtGen, append([]ClientOption{WithMode(creds.Mode)}, opts...)...), nil
+}
+
+// IsConfigured returns true if the client has valid credentials
+func (c *Client) IsConfigured() bool {
+	return c.jwtGenerator != nil && c.appID != ""
+}
+
+// ========================================
+// SIM Swap
+// ========================================
+
+// CheckSimSwap reports whether the number's SIM changed within maxAge
+// (default DefaultSimSwapMaxAge, rounded up to whole hours). A recent swap
+// is a strong signal of account takeover: skip the SMS OTP and fall back
+// to another factor.
+func (c *Client) CheckSimSwap(ctx context.Context, phoneNumber string, maxAge time.Duration) (bool, error) {
+	if maxAge == 0 {
+		maxAge = DefaultSimSwapMaxAge
+	}
+	hours := int((maxAge + time.Hour - 1) / time.Hour)
+	if hours < 1 || maxAge > MaxSimSwapMaxAge {
+		return false, ErrInvalidMaxAge
+	}
+
+	token, err := c.CIBAToken(ctx, phoneNumber, ScopeSimSwapCheck)
+	if err != nil {
+		return false, err
+	}
+
+	var resp simSwapCheckResponse
+	req := simSwapRequest{PhoneNumber: toE164(phoneNumber), MaxAge: hours}
+	if err := c.camara.Do(withToken(ctx, token), http.MethodPost, "/camara/sim-swap/v040/check", req, &resp); err != nil {
+		return false, err
+	}
+
+	if resp.Swapped {
+		c.logger.Warn("Recent SIM swap detected", "maxAgeHours", hours)
+	}
+	return resp.Swapped, nil
+}
+
+// SimSwapDate returns when the number's SIM last changed. It returns
+// ErrNoSimSwapDate if the operator does not know.
+func (c *Client) SimSwapDate(ctx context.Context, phoneNumber string) (time.Time, error) {
+	token, err := c.CIBAToken(ctx, phoneNumber, ScopeSimSwapRetrieveDate)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var resp simSwapDateResponse
+	req := simSwapRequest{PhoneNumber: toE164(phoneNumber)}
+	if err := c.camara.Do(withToken(ctx, token), http.MethodPost, "/camara/sim-swap/v040/retrieve-date", req, &resp); err != nil {
+		return time.Time{}, err
+	}
+
+	if resp.LatestSimChange == nil {
+		return time.Time{}, ErrNoSimSwapDate
+	}
+	return *resp.LatestSimChange, nil
+}
+
+// ========================================
+// Number Verification
+// ========================================
+
+// AuthURL returns the URL the user's device must open, over its mobile
+// data connection, to start Number Verification. The operator identifies
+// the device from the connection and redirects to redirectURI with a code
+// and the given state.
+func (c *Client) AuthURL(redirectURI, phoneNumber, state string) (string, error) {
+	if phoneNumber == "" {
+		return "", ErrPhoneNumberRequired
+	}
+
+	q := url.Values{}
+	q.Set("client_id", c.appID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("response_type", "code")
+	q.Set("scope", "openid "+string(ScopeNumberVerification))
+	q.Set("login_hint", "tel:"+toE164(phoneNumber))
+	if state != "" {
+		q.Set("state", state)
+	}
+	return strings.TrimSuffix(c.authBaseURL, "/") + "/oauth2/auth?" + q.Encode(), nil
+}
+
+// ExchangeCode exchanges the code delivered to redirectURI for an access
+// token. redirectURI must match the one passed to AuthURL.
+func (c *Client) ExchangeCode(ctx context.Context, code, redirectURI string) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+
+	var resp tokenResponse
+	if err := c.oauth.Do(ctx, http.MethodPost, "/oauth2/token", form, &resp); err != nil {
+		return nil, err
+	}
+	return resp.token(), nil
+}
+
+// VerifyNumber reports whether the device that completed the
+// authorization code flow is using phoneNumber
+func (c *Client) VerifyNumber(ctx context.Context, token *Token, phoneNumber string) (bool, error) {
+	if token == nil || token.AccessToken == "" {
+		return false, ErrTokenRequired
+	}
+	if phoneNumber == "" {
+		return false, ErrPhoneNumberRequired
+	}
+
+	var resp verifyNumberResponse
+	req := verifyNumberRequest{PhoneNumber: toE164(phoneNumber)}
+	if err := c.camara.Do(withToken(ctx, token), http.MethodPost, "/camara/number-verification/v031/verify", req, &resp); err != nil {
+		return false, err
+	}
+	return resp.DevicePhoneNumberVerified, nil
+}
+
+// ========================================
+// OAuth
+// ========================================
+
+// CIBAToken obtains an access token for phoneNumber and scope through the
+// OpenID Connect client-initiated backchannel authentication flow. The
+// SIM Swap methods call it for you.
+func (c *Client) CIBAToken(ctx context.Context, phoneNumber string, scope Scope) (*Token, error) {
+	if phoneNumber == "" {
+		return nil, ErrPhoneNumberRequired
+	}
+
+	form := url.Values{}
+	form.Set("login_hint", "tel:"+toE164(phoneNumber))
+	form.Set("scope", "openid "+string(scope))
+
+	var auth authorizeResponse
+	if err := c.oauth.Do(ctx, http.MethodPost, "/oauth2/bc-authorize", form, &auth); err != nil {
+		return nil, fmt.Errorf("network: authorize failed: %w", err)
+	}
+
+	form = url.Values{}
+	form.Set("grant_type", cibaGrantType)
+	form.Set("auth_req_id", auth.AuthReqID)
+
+	var resp tokenResponse
+	if err := c.oauth.Do(ctx, http.MethodPost, "/oauth2/token", form, &resp); err != nil {
+		return nil, fmt.Errorf("network: token request failed: %w", err)
+	}
+	return resp.token(), nil
+}
+
+// ========================================
+// Helpers
+// ========================================
+
+// tokenKey carries the access token of a CAMARA request
+type tokenKey struct{}
+
+func withToken(ctx context.Context, token *Token) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// contextToken authenticates CAMARA requests with the access token carried
+// in the request context. It implements vonage.Authenticator.
+type contextToken struct{}
+
+func (contextToken) Authenticate(req *http.Request) error {
+	token, _ := req.Context().Value(tokenKey{}).(*Token)
+	if token == nil || token.AccessToken == "" {
+		return ErrTokenRequired
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	return nil
+}
+
+// toE164 adds the leading + the CAMARA APIs require. Vonage numbers are
+// usually written without it.
+func toE164(number string) string {
+	number = strings.TrimSpace(number)
+	if number == "" || strings.HasPrefix(number, "+") {
+		return number
+	}
+	return "+" + number
+}
@@ -0,0 +1,55 @@
+package network_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/network"
+)
+
+func ExampleClient_CheckSimSwap() {
+	creds, _ := vonage.NewCredentialsFromEnv()
+	client, _ := network.NewClientFromCredentials(creds)
+
+	// Don't send an OTP to a number whose SIM changed in the last week
+	swapped, err := client.CheckSimSwap(context.Background(), "447700900000", 7*24*time.Hour)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if swapped {
+		fmt.Println("SIM recently swapped, use another factor")
+	}
+}
+
+func ExampleClient_AuthURL() {
+	creds, _ := vonage.NewCredentialsFromEnv()
+	client, _ := network.NewClientFromCredentials(creds)
+
+	const redirectURI = "https://example.com/verify/callback"
+
+	// 1. The app opens this URL over mobile data, not Wi-Fi
+	authURL, _ := client.AuthURL(redirectURI, "447700900000", "session-123")
+	fmt.Println(authURL)
+
+	// 2. The operator redirects back with a code
+	http.HandleFunc("/verify/callback", func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		token, err := client.ExchangeCode(ctx, r.URL.Query().Get("code"), redirectURI)
+		if err != nil {
+			http.Error(w, "verification failed", http.StatusBadGateway)
+			return
+		}
+
+		// 3. Check the device is using the number it claimed
+		verified, err := client.VerifyNumber(ctx, token, "447700900000")
+		if err != nil || !verified {
+			http.Error(w, "number not verified", http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,76 @@
+package network
+
+import "time"
+
+// Scope is an OAuth scope granting access to one Network API operation
+type Scope string
+
+const (
+	ScopeSimSwapCheck        Scope = "dpv:FraudPreventionAndDetection#check-sim-swap"
+	ScopeSimSwapRetrieveDate Scope = "dpv:FraudPreventionAndDetection#retrieve-sim-swap-date"
+	ScopeNumberVerification  Scope = "dpv:FraudPreventionAndDetection#number-verification-verify-read"
+)
+
+// Token is an access token for the CAMARA endpoints. Each token is bound
+// to one phone number and scope.
+type Token struct {
+	AccessToken string
+	TokenType   string
+	ExpiresAt   time.Time
+}
+
+// Expired returns true if the token has expired
+func (t *Token) Expired() bool {
+	return !t.ExpiresAt.IsZero() && time.Now().After(t.ExpiresAt)
+}
+
+// tokenResponse is the body of the token endpoint
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func (r *tokenResponse) token() *Token {
+	t := &Token{
+		AccessToken: r.AccessToken,
+		TokenType:   r.TokenType,
+	}
+	if r.ExpiresIn > 0 {
+		t.ExpiresAt = time.Now().Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+	return t
+}
+
+// authorizeResponse is the body of the CIBA backchannel authorize endpoint
+type authorizeResponse struct {
+	AuthReqID string `json:"auth_req_id"`
+	ExpiresIn int    `json:"expires_in"`
+	Interval  int    `json:"interval"`
+}
+
+// simSwapRequest is the body of the SIM Swap endpoints
+type simSwapRequest struct {
+	PhoneNumber string `json:"phoneNumber"`
+	MaxAge      int    `json:"maxAge,omitempty"`
+}
+
+// simSwapCheckResponse is the body of the SIM Swap check endpoint
+type simSwapCheckResponse struct {
+	Swapped bool `json:"swapped"`
+}
+
+// simSwapDateResponse is the body of the SIM Swap retrieve-date endpoint
+type simSwapDateResponse struct {
+	LatestSimChange *time.Time `json:"latestSimChange"`
+}
+
+// verifyNumberRequest is the body of the Number Verification endpoint
+type verifyNumberRequest struct {
+	PhoneNumber string `json:"phoneNumber"`
+}
+
+// verifyNumberResponse is the body of the Number Verification endpoint
+type verifyNumberResponse struct {
+	DevicePhoneNumberVerified bool `json:"devicePhoneNumberVerified"`
+}
@@ -0,0 +1,21 @@
+package numbers
+
+import (
+	"context"
+)
+
+// ========================================
+// API Interface
+// ========================================
+
+// API is the Numbers API implemented by *Client. Application code can
+// depend on it and substitute vonagemock.Numbers in tests.
+type API interface {
+	Search(ctx context.Context, country string, opts *SearchOptions) (*SearchResult, error)
+	List(ctx context.Context, opts *ListOptions) (*OwnedList, error)
+	Buy(ctx context.Context, country, msisdn string) error
+	Cancel(ctx context.Context, country, msisdn string) error
+	Update(ctx context.Context, country, msisdn string, opts *UpdateOptions) error
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,298 @@
+// Package numbers manages the account's Vonage phone numbers: searching
+// available numbers, buying and cancelling them, and configuring their
+// webhooks.
+package numbers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+const (
+	// BaseURL is the Vonage Numbers API base URL
+	BaseURL = vonage.BaseURLLegacy
+)
+
+// ErrNotConfigured is returned when the client has no API key and secret
+var ErrNotConfigured = errors.New("numbers: API key and secret required")
+
+// Client handles Vonage Numbers API operations. The API authenticates with
+// the account API key and secret.
+type Client struct {
+	apiKey    string
+	apiSecret string
+	provider  vonage.CredentialsProvider
+
+	baseURL    string
+	httpClient *http.Client
+	middleware []vonage.Middleware
+	transport  *vonage.Transport
+	logger     vonage.Logger
+	uaSuffix   string
+	mode       vonage.Mode
+}
+
+// ClientOption is a functional option for configuring the numbers client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the base URL (useful for testing)
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l vonage.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier such as
+// "checkin-service/1.4" to the User-Agent
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.uaSuffix = suffix
+	}
+}
+
+// WithMiddleware adds transport middleware (retries, tracing, metrics)
+func WithMiddleware(mw ...vonage.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithMode sets the client's mode (default vonage.ModeLive). In
+// vonage.ModeMock no request is sent, and in vonage.ModeDryRun only
+// reads are.
+func WithMode(m vonage.Mode) ClientOption {
+	return func(c *Client) {
+		c.mode = m
+	}
+}
+
+// WithSettings applies the HTTP client, logger, middleware, User-Agent
+// suffix and mode shared by a root vonage.Client, see
+// vonage.Client.Settings
+func WithSettings(s vonage.Settings) ClientOption {
+	return func(c *Client) {
+		if s.HTTPClient != nil {
+			c.httpClient = s.HTTPClient
+		}
+		if s.Logger != nil {
+			c.logger = s.Logger
+		}
+		c.middleware = append(c.middleware, s.Middleware...)
+		c.uaSuffix = s.UserAgentSuffix
+		c.mode = s.Mode
+	}
+}
+
+// WithCredentialsProvider fetches the API key and secret from p for every
+// request instead of using those passed to NewClient, so rotated keys are
+// picked up
+func WithCredentialsProvider(p vonage.CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.provider = p
+	}
+}
+
+// WithTransport uses a shared transport instead of building one; the
+// base URL, HTTP client, middleware and credentials provider options are
+// then ignored, and the transport must authenticate with vonage.QueryAuth
+func WithTransport(t *vonage.Transport) ClientOption {
+	return func(c *Client) {
+		c.transport = t
+	}
+}
+
+// NewClient creates a new Vonage Numbers API client
+func NewClient(apiKey, apiSecret string, opts ...ClientOption) *Client {
+	c := &Client{
+		apiKey:     apiKey,
+		apiSecret:  apiSecret,
+		baseURL:    BaseURL,
+		httpClient: &http.Client{Timeout: vonage.DefaultTimeout},
+		logger:     vonage.NopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.transport == nil {
+		provider := c.provider
+		if provider == nil {
+			provider = &vonage.Credentials{APIKey: apiKey, APISecret: apiSecret}
+		}
+		c.transport = vonage.NewTransport(c.baseURL, vonage.NewAuthChain(provider, vonage.AuthQuery),
+			vonage.WithTransportHTTPClient(c.httpClient),
+			vonage.WithMiddleware(c.middleware...),
+			vonage.WithTransportLogger(c.logger),
+			vonage.WithTransportUserAgentSuffix(c.uaSuffix),
+			vonage.WithTransportMode(c.mode),
+		)
+	}
+
+	return c
+}
+
+// NewClientFromCredentials creates a new client from Vonage credentials
+func NewClientFromCredentials(creds *vonage.Credentials, opts ...ClientOption) (*Client, error) {
+	if !creds.HasAPIKey() {
+		return nil, ErrNotConfigured
+	}
+	return NewClient(creds.APIKey, creds.APISecret, append([]ClientOption{WithMode(creds.Mode)}, opts...)...), nil
+}
+
+// IsConfigured returns true if the client has an API key and secret or a
+// credentials provider
+func (c *Client) IsConfigured() bool {
+	return c.provider != nil || (c.apiKey != "" && c.apiSecret != "")
+}
+
+// ========================================
+// Search and List
+// ========================================
+
+// Search finds numbers available to buy in a country (ISO 3166-1 alpha-2)
+func (c *Client) Search(ctx context.Context, country string, opts *SearchOptions) (*SearchResult, error) {
+	if !c.IsConfigured() {
+		return nil, ErrNotConfigured
+	}
+
+	q := url.Values{}
+	q.Set("country", country)
+	opts.query(q)
+
+	var result SearchResult
+	if err := c.transport.Do(ctx, http.MethodGet, "/number/search?"+q.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// List returns the numbers owned by the account
+func (c *Client) List(ctx context.Context, opts *ListOptions) (*OwnedList, error) {
+	if !c.IsConfigured() {
+		return nil, ErrNotConfigured
+	}
+
+	q := url.Values{}
+	opts.query(q)
+
+	var result OwnedList
+	if err := c.transport.Do(ctx, http.MethodGet, "/account/numbers?"+q.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// defaultListSize is the page size Vonage uses when none is given
+const defaultListSize = 10
+
+// NewPager returns a pager over the owned numbers matching opts, from
+// opts.Index on
+func NewPager(c API, opts *ListOptions, pagerOpts ...vonage.PagerOption) *vonage.Pager[OwnedNumber] {
+	var base ListOptions
+	if opts != nil {
+		base = *opts
+	}
+	return vonage.NewPager(func(ctx context.Context, token string) ([]OwnedNumber, string, error) {
+		o := base
+		if token != "" {
+			o.Index = vonage.PageNumber(token)
+		}
+		list, err := c.List(ctx, &o)
+		if err != nil {
+			return nil, "", err
+		}
+
+		index, size := max(o.Index, 1), o.Size
+		if size == 0 {
+			size = defaultListSize
+		}
+		if len(list.Numbers) == 0 || index*size >= list.Count {
+			return list.Numbers, "", nil
+		}
+		return list.Numbers, strconv.Itoa(index + 1), nil
+	}, pagerOpts...)
+}
+
+// ========================================
+// Buy, Cancel and Update
+// ========================================
+
+// Buy purchases an available number
+func (c *Client) Buy(ctx context.Context, country, msisdn string) error {
+	if err := c.post(ctx, "/number/buy", country, msisdn, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Bought Vonage number", "country", country, "msisdn", msisdn)
+	return nil
+}
+
+// Cancel releases a number from the account
+func (c *Client) Cancel(ctx context.Context, country, msisdn string) error {
+	if err := c.post(ctx, "/number/cancel", country, msisdn, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Cancelled Vonage number", "country", country, "msisdn", msisdn)
+	return nil
+}
+
+// Update sets a number's application link and webhook configuration
+func (c *Client) Update(ctx context.Context, country, msisdn string, opts *UpdateOptions) error {
+	return c.post(ctx, "/number/update", country, msisdn, opts)
+}
+
+// post sends a form request for a single number and checks the body's
+// error-code
+func (c *Client) post(ctx context.Context, path, country, msisdn string, opts *UpdateOptions) error {
+	if !c.IsConfigured() {
+		return ErrNotConfigured
+	}
+
+	form := url.Values{}
+	form.Set("country", country)
+	form.Set("msisdn", msisdn)
+	opts.form(form)
+
+	var resp response
+	if err := c.transport.Do(ctx, http.MethodPost, path, form, &resp); err != nil {
+		return err
+	}
+	return resp.err()
+}
+
+// ========================================
+// Health
+// ========================================
+
+// Ping checks the credentials and connectivity for readiness probes; it
+// lists one owned number
+func (c *Client) Ping(ctx context.Context) *vonage.Health {
+	if !c.IsConfigured() {
+		return &vonage.Health{Service: "numbers", CheckedAt: time.Now(), Err: ErrNotConfigured}
+	}
+	q := url.Values{}
+	q.Set("size", "1")
+	return c.transport.Ping(ctx, "numbers", "/account/numbers?"+q.Encode())
+}
@@ -0,0 +1,64 @@
+package numbers_test
+
+import (
+	"context"
+	"fmt"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/numbers"
+)
+
+func ExampleClient_Search() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithAPIKey("api-key", "api-secret"),
+	)
+	client, _ := numbers.NewClientFromCredentials(creds)
+
+	ctx := context.Background()
+
+	// Find a Tokyo (03) voice number
+	result, err := client.Search(ctx, "JP", &numbers.SearchOptions{
+		Type:          numbers.TypeLandline,
+		Pattern:       "813",
+		SearchPattern: numbers.PatternStartsWith,
+		Features:      []numbers.Feature{numbers.FeatureVoice},
+		Size:          10,
+	})
+	if err != nil || result.Count == 0 {
+		fmt.Println("no numbers available")
+		return
+	}
+
+	number := result.Numbers[0]
+	if err := client.Buy(ctx, number.Country, number.MSISDN); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// Route calls to our application's answer/event webhooks
+	err = client.Update(ctx, number.Country, number.MSISDN, &numbers.UpdateOptions{
+		AppID: "app-id",
+	})
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+func ExampleClient_List() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithAPIKey("api-key", "api-secret"),
+	)
+	client, _ := numbers.NewClientFromCredentials(creds)
+
+	unlinked := false
+	list, err := client.List(context.Background(), &numbers.ListOptions{
+		HasApplication: &unlinked,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, n := range list.Numbers {
+		fmt.Printf("%s (%s) is not linked to an application\n", n.MSISDN, n.Country)
+	}
+}
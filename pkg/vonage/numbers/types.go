@@ -0,0 +1,230 @@
+package numbers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// NumberType is the type of a phone number
+type NumberType string
+
+const (
+	TypeLandline         NumberType = "landline"
+	TypeMobile           NumberType = "mobile-lvn"
+	TypeLandlineTollFree NumberType = "landline-toll-free"
+)
+
+// Feature is a capability of a phone number
+type Feature string
+
+const (
+	FeatureSMS   Feature = "SMS"
+	FeatureVoice Feature = "VOICE"
+	FeatureMMS   Feature = "MMS"
+)
+
+// SearchPattern controls where Pattern must appear in the number
+type SearchPattern int
+
+const (
+	PatternStartsWith SearchPattern = 0
+	PatternContains   SearchPattern = 1
+	PatternEndsWith   SearchPattern = 2
+)
+
+// CallbackType is the type of a number's voice callback
+type CallbackType string
+
+const (
+	CallbackApp  CallbackType = "app"
+	CallbackSIP  CallbackType = "sip"
+	CallbackTel  CallbackType = "tel"
+	CallbackVXML CallbackType = "vxml"
+)
+
+// ========================================
+// Search
+// ========================================
+
+// SearchOptions filters available numbers
+type SearchOptions struct {
+	Type NumberType
+	// Pattern is a digit sequence to match, positioned by SearchPattern
+	Pattern       string
+	SearchPattern SearchPattern
+	Features      []Feature
+	// Size is the page size (max 100) and Index the 1-based page
+	Size  int
+	Index int
+}
+
+// AvailableNumber is a number that can be bought
+type AvailableNumber struct {
+	Country  string    `json:"country"`
+	MSISDN   string    `json:"msisdn"`
+	Type     string    `json:"type"`
+	Cost     string    `json:"cost"`
+	Features []Feature `json:"features"`
+}
+
+// SearchResult is a page of available numbers
+type SearchResult struct {
+	Count   int               `json:"count"`
+	Numbers []AvailableNumber `json:"numbers"`
+}
+
+// query encodes search options
+func (o *SearchOptions) query(q url.Values) {
+	if o == nil {
+		return
+	}
+	if o.Type != "" {
+		q.Set("type", string(o.Type))
+	}
+	if o.Pattern != "" {
+		q.Set("pattern", o.Pattern)
+		q.Set("search_pattern", strconv.Itoa(int(o.SearchPattern)))
+	}
+	if len(o.Features) > 0 {
+		features := make([]string, len(o.Features))
+		for i, f := range o.Features {
+			features[i] = string(f)
+		}
+		q.Set("features", strings.Join(features, ","))
+	}
+	setPage(q, o.Size, o.Index)
+}
+
+// ========================================
+// Owned Numbers
+// ========================================
+
+// ListOptions filters the account's numbers
+type ListOptions struct {
+	// ApplicationID limits results to numbers linked to an application
+	ApplicationID string
+	// HasApplication limits results to linked (true) or unlinked (false)
+	// numbers when set
+	HasApplication *bool
+	Country        string
+	Pattern        string
+	SearchPattern  SearchPattern
+	Size           int
+	Index          int
+}
+
+// OwnedNumber is a number on the account and its webhook configuration
+type OwnedNumber struct {
+	Country                string       `json:"country"`
+	MSISDN                 string       `json:"msisdn"`
+	Type                   string       `json:"type"`
+	Features               []Feature    `json:"features"`
+	AppID                  string       `json:"app_id,omitempty"`
+	MOHTTPURL              string       `json:"moHttpUrl,omitempty"`
+	VoiceCallbackType      CallbackType `json:"voiceCallbackType,omitempty"`
+	VoiceCallbackValue     string       `json:"voiceCallbackValue,omitempty"`
+	VoiceStatusCallbackURL string       `json:"voiceStatusCallbackUrl,omitempty"`
+	MessagesCallbackType   string       `json:"messagesCallbackType,omitempty"`
+	MessagesCallbackValue  string       `json:"messagesCallbackValue,omitempty"`
+}
+
+// OwnedList is a page of the account's numbers
+type OwnedList struct {
+	Count   int           `json:"count"`
+	Numbers []OwnedNumber `json:"numbers"`
+}
+
+// query encodes list options
+func (o *ListOptions) query(q url.Values) {
+	if o == nil {
+		return
+	}
+	if o.ApplicationID != "" {
+		q.Set("application_id", o.ApplicationID)
+	}
+	if o.HasApplication != nil {
+		q.Set("has_application", strconv.FormatBool(*o.HasApplication))
+	}
+	if o.Country != "" {
+		q.Set("country", o.Country)
+	}
+	if o.Pattern != "" {
+		q.Set("pattern", o.Pattern)
+		q.Set("search_pattern", strconv.Itoa(int(o.SearchPattern)))
+	}
+	setPage(q, o.Size, o.Index)
+}
+
+// ========================================
+// Update
+// ========================================
+
+// UpdateOptions sets a number's webhook configuration. Empty fields are
+// cleared by the API, so pass the full desired configuration.
+type UpdateOptions struct {
+	// AppID links the number to an application, whose webhooks then apply
+	AppID string
+	// MOHTTPURL receives inbound SMS (when not linked to an application)
+	MOHTTPURL          string
+	VoiceCallbackType  CallbackType
+	VoiceCallbackValue string
+	// VoiceStatusCallback receives voice call events
+	VoiceStatusCallback string
+}
+
+// form encodes update options
+func (o *UpdateOptions) form(f url.Values) {
+	if o == nil {
+		return
+	}
+	setIf(f, "app_id", o.AppID)
+	setIf(f, "moHttpUrl", o.MOHTTPURL)
+	setIf(f, "voiceCallbackType", string(o.VoiceCallbackType))
+	setIf(f, "voiceCallbackValue", o.VoiceCallbackValue)
+	setIf(f, "voiceStatusCallback", o.VoiceStatusCallback)
+}
+
+// ========================================
+// Errors
+// ========================================
+
+// Error is a Numbers API error reported in the response body
+type Error struct {
+	Code  string
+	Label string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("numbers: error %s - %s", e.Code, e.Label)
+}
+
+// response is the body returned by buy, cancel and update
+type response struct {
+	ErrorCode      string `json:"error-code"`
+	ErrorCodeLabel string `json:"error-code-label"`
+}
+
+// err converts a non-200 error-code into an *Error
+func (r *response) err() error {
+	if r.ErrorCode == "" || r.ErrorCode == "200" {
+		return nil
+	}
+	return &Error{Code: r.ErrorCode, Label: r.ErrorCodeLabel}
+}
+
+func setPage(q url.Values, size, index int) {
+	if size > 0 {
+		q.Set("size", strconv.Itoa(size))
+	}
+	if index > 0 {
+		q.Set("index", strconv.Itoa(index))
+	}
+}
+
+func setIf(v url.Values, key, value string) {
+	if value != "" {
+		v.Set(key, value)
+	}
+}
@@ -0,0 +1,37 @@
+package otelvonage_test
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/messages"
+	"github.com/vonatrigger/poc/pkg/vonage/otelvonage"
+	"github.com/vonatrigger/poc/pkg/vonage/voice"
+)
+
+func ExampleMiddleware() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+	)
+
+	// Spans and request metrics for every Voice API call
+	client, _ := voice.NewClientFromCredentials(creds,
+		voice.WithMiddleware(otelvonage.Middleware(
+			otelvonage.WithTracerProvider(otel.GetTracerProvider()),
+			otelvonage.WithMeterProvider(otel.GetMeterProvider()),
+		)),
+	)
+	_ = client
+}
+
+func ExampleWebhook() {
+	handler := messages.NewWebhookHandler().
+		OnInbound(func(msg *messages.InboundMessage) error {
+			return nil
+		})
+
+	// Continue the caller's trace in the inbound webhook
+	http.Handle("/webhooks/inbound", otelvonage.Webhook("inbound", handler.HandleInbound()))
+}
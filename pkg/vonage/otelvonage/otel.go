@@ -0,0 +1,210 @@
+// Package otelvonage provides OpenTelemetry tracing and metrics for the
+// Vonage SDK as transport middleware, plus trace context propagation into
+// webhook handlers.
+package otelvonage
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// instrumentationName identifies this package to tracer and meter providers
+const instrumentationName = "github.com/vonatrigger/poc/pkg/vonage/otelvonage"
+
+// Attribute keys set on spans and metrics
+const (
+	AttrAPI    = attribute.Key("vonage.api")
+	AttrMethod = attribute.Key("http.request.method")
+	AttrStatus = attribute.Key("http.response.status_code")
+)
+
+// ========================================
+// Options
+// ========================================
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagators    propagation.TextMapPropagator
+	api            string
+}
+
+// Option configures the middleware and webhook wrapper
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider (default: the global provider)
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the meter provider (default: the global provider)
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// WithPropagators sets the propagators used to read and write trace
+// context (default: the global propagators)
+func WithPropagators(p propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = p
+	}
+}
+
+// WithAPI sets the vonage.api attribute instead of deriving it from the
+// request path
+func WithAPI(name string) Option {
+	return func(c *config) {
+		c.api = name
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagators:    otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ========================================
+// Transport Middleware
+// ========================================
+
+// Middleware returns transport middleware that records a client span and
+// request metrics for every API call. Install it with the sub-clients'
+// WithMiddleware option or vonage.WithMiddleware.
+func Middleware(opts ...Option) vonage.Middleware {
+	cfg := newConfig(opts)
+	tracer := cfg.tracerProvider.Tracer(instrumentationName)
+	meter := cfg.meterProvider.Meter(instrumentationName)
+
+	// Instrument creation only fails for invalid names, which are constant
+	requests, _ := meter.Int64Counter("vonage.client.requests",
+		metric.WithDescription("Number of Vonage API requests"))
+	errs, _ := meter.Int64Counter("vonage.client.errors",
+		metric.WithDescription("Number of failed Vonage API requests (transport errors and non-2xx)"))
+	duration, _ := meter.Float64Histogram("vonage.client.request.duration",
+		metric.WithDescription("Duration of Vonage API requests"),
+		metric.WithUnit("s"))
+
+	return func(next vonage.RoundTripFunc) vonage.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			api := cfg.api
+			if api == "" {
+				api = apiName(req.URL.Path)
+			}
+			attrs := []attribute.KeyValue{AttrAPI.String(api), AttrMethod.String(req.Method)}
+
+			ctx, span := tracer.Start(req.Context(), fmt.Sprintf("vonage.%s %s", api, req.Method),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+			)
+			defer span.End()
+
+			req = req.Clone(ctx)
+			cfg.propagators.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			start := time.Now()
+			resp, err := next(req)
+			elapsed := time.Since(start).Seconds()
+
+			failed := err != nil
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			} else {
+				attrs = append(attrs, AttrStatus.Int(resp.StatusCode))
+				span.SetAttributes(AttrStatus.Int(resp.StatusCode))
+				if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+					failed = true
+					span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+				}
+			}
+
+			set := metric.WithAttributes(attrs...)
+			requests.Add(ctx, 1, set)
+			duration.Record(ctx, elapsed, set)
+			if failed {
+				errs.Add(ctx, 1, set)
+			}
+			return resp, err
+		}
+	}
+}
+
+// apiPrefixes maps the path prefixes of every sub-client to its API name.
+// More specific prefixes come first: numbers live under /account.
+var apiPrefixes = []struct {
+	prefix string
+	api    string
+}{
+	{"/v1/calls", "voice"},
+	{"/v1/files", "voice"},
+	{"/v1/messages", "messages"},
+	{"/v1/users", "users"},
+	{"/v3/media", "media"},
+	{"/v2/project", "video"},
+	{"/session", "video"},
+	{"/account/numbers", "numbers"},
+	{"/number/", "numbers"},
+	{"/account", "account"},
+	{"/v2/verify", "verify"},
+	{"/verify/", "verify"},
+	{"/v2/reports", "reports"},
+	{"/beta/chatapp-accounts", "externalaccounts"},
+	{"/v2/whatsapp-manager", "whatsapp"},
+	{"/v0.1/bulk", "proactive"},
+	{"/camara/", "network"},
+	{"/oauth2/", "network"},
+}
+
+// apiName derives the Vonage API from a request path
+func apiName(path string) string {
+	for _, p := range apiPrefixes {
+		if strings.HasPrefix(path, p.prefix) {
+			return p.api
+		}
+	}
+	return "unknown"
+}
+
+// ========================================
+// Webhooks
+// ========================================
+
+// Webhook wraps a webhook handler, extracting incoming trace context from
+// the request headers and recording a server span around the handler
+func Webhook(name string, h http.Handler, opts ...Option) http.Handler {
+	cfg := newConfig(opts)
+	tracer := cfg.tracerProvider.Tracer(instrumentationName)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := cfg.propagators.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, "vonage.webhook "+name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(AttrMethod.String(r.Method)),
+		)
+		defer span.End()
+
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,43 @@
+package otelvonage
+
+import "testing"
+
+func TestAPIName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/v1/calls", "voice"},
+		{"/v1/calls/CALL-1/talk", "voice"},
+		{"/v1/files/recording-1", "voice"},
+		{"/v1/messages", "messages"},
+		{"/v1/users/USR-1", "users"},
+		{"/v3/media/MEDIA-1/info", "media"},
+		{"/v2/project/app-1/archive", "video"},
+		{"/session/create", "video"},
+		{"/account/numbers", "numbers"},
+		{"/number/search", "numbers"},
+		{"/number/buy", "numbers"},
+		{"/account/get-balance", "account"},
+		{"/accounts/abc123/secrets", "account"},
+		{"/verify/json", "verify"},
+		{"/verify/check/json", "verify"},
+		{"/v2/verify/REQ-1", "verify"},
+		{"/v2/reports/REQ-1", "reports"},
+		{"/beta/chatapp-accounts/EXT-1", "externalaccounts"},
+		{"/v2/whatsapp-manager/wabas/WABA-1/templates", "whatsapp"},
+		{"/v0.1/bulk/lists", "proactive"},
+		{"/camara/sim-swap/v040/check", "network"},
+		{"/oauth2/token", "network"},
+		{"/webhooks/inbound", "unknown"},
+		{"/verifyx", "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := apiName(tt.path); got != tt.want {
+				t.Errorf("apiName(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package vonage
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ========================================
+// Pagination
+// ========================================
+
+// ErrNoMorePages is returned by Pager.Next after the last page
+var ErrNoMorePages = errors.New("vonage: no more pages")
+
+// Pager defaults
+const (
+	DefaultPagerRetries = 3
+	DefaultPagerBackoff = time.Second
+)
+
+// PageFunc fetches the page at token, "" for the first page, and returns
+// its items and the next page's token, "" after the last page. Tokens are
+// cursors or page numbers, depending on the endpoint.
+type PageFunc[T any] func(ctx context.Context, token string) (items []T, next string, err error)
+
+// Pager iterates the pages of a list endpoint. Sub-clients return pagers
+// from their NewPager functions. Rate-limited fetches (ErrRateLimited) are
+// retried after the API's Retry-After, or with exponential backoff. A
+// Pager is not safe for concurrent use.
+type Pager[T any] struct {
+	fetch    PageFunc[T]
+	settings pagerSettings
+
+	token string
+	done  bool
+
+	// Prefetching
+	results chan pageResult[T]
+	cancel  context.CancelFunc
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// pageResult is a page fetched ahead
+type pageResult[T any] struct {
+	token string
+	items []T
+	next  string
+	err   error
+}
+
+// pagerSettings are the options of a Pager
+type pagerSettings struct {
+	prefetch int
+	delay    time.Duration
+	retries  int
+	backoff  time.Duration
+}
+
+// PagerOption is a functional option for configuring a Pager
+type PagerOption func(*pagerSettings)
+
+// WithPrefetch fetches up to n pages ahead in the background while the
+// caller processes the current one. Prefetching runs under the context of
+// the first Next call; cancel it or call Close to stop early.
+func WithPrefetch(n int) PagerOption {
+	return func(s *pagerSettings) {
+		s.prefetch = n
+	}
+}
+
+// WithPageDelay spaces page fetches at least d apart, to stay under an
+// endpoint's rate limit
+func WithPageDelay(d time.Duration) PagerOption {
+	return func(s *pagerSettings) {
+		s.delay = d
+	}
+}
+
+// WithRateLimitRetry sets how often a rate-limited fetch is retried and
+// the first backoff, which doubles on each retry (default
+// DefaultPagerRetries and DefaultPagerBackoff). A Retry-After from the API
+// takes precedence over the backoff.
+func WithRateLimitRetry(retries int, backoff time.Duration) PagerOption {
+	return func(s *pagerSettings) {
+		s.retries = retries
+		s.backoff = backoff
+	}
+}
+
+// NewPager creates a pager over fetch
+func NewPager[T any](fetch PageFunc[T], opts ...PagerOption) *Pager[T] {
+	p := &Pager[T]{
+		fetch: fetch,
+		settings: pagerSettings{
+			retries: DefaultPagerRetries,
+			backoff: DefaultPagerBackoff,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(&p.settings)
+	}
+
+	return p
+}
+
+// Next returns the next page's items, or ErrNoMorePages after the last
+// page. After an error, Next retries the same page.
+func (p *Pager[T]) Next(ctx context.Context) ([]T, error) {
+	if p.settings.prefetch > 0 {
+		return p.nextPrefetched(ctx)
+	}
+	if p.done {
+		return nil, ErrNoMorePages
+	}
+
+	items, next, err := p.fetchPage(ctx, p.token)
+	if err != nil {
+		return nil, err
+	}
+	p.token = next
+	p.done = next == ""
+	return items, nil
+}
+
+// All returns the items of all remaining pages
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	defer p.Close()
+
+	var all []T
+	for {
+		items, err := p.Next(ctx)
+		if errors.Is(err, ErrNoMorePages) {
+			return all, nil
+		}
+		if err != nil {
+			return all, err
+		}
+		all = append(all, items...)
+	}
+}
+
+// Close stops prefetching. The pager can still be used afterwards.
+func (p *Pager[T]) Close() {
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+	p.results = nil
+}
+
+// nextPrefetched returns the next page from the prefetching goroutine,
+// starting it if needed
+func (p *Pager[T]) nextPrefetched(ctx context.Context) ([]T, error) {
+	for {
+		if p.done {
+			return nil, ErrNoMorePages
+		}
+		if p.results == nil {
+			pctx, cancel := context.WithCancel(ctx)
+			p.cancel = cancel
+			p.results = make(chan pageResult[T], p.settings.prefetch)
+			go p.prefetch(pctx, p.token, p.results)
+		}
+
+		select {
+		case r, ok := <-p.results:
+			if !ok {
+				// The prefetch context ended before the last page
+				p.Close()
+				continue
+			}
+			if r.err != nil {
+				p.Close()
+				p.token = r.token
+				return nil, r.err
+			}
+			p.token = r.next
+			p.done = r.next == ""
+			return r.items, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// prefetch fetches pages from token on until the last page, an error or
+// the end of ctx
+func (p *Pager[T]) prefetch(ctx context.Context, token string, results chan<- pageResult[T]) {
+	defer close(results)
+	for {
+		items, next, err := p.fetchPage(ctx, token)
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case results <- pageResult[T]{token: token, items: items, next: next, err: err}:
+		case <-ctx.Done():
+			return
+		}
+		if err != nil || next == "" {
+			return
+		}
+		token = next
+	}
+}
+
+// fetchPage fetches a page, spacing fetches and retrying rate limits
+func (p *Pager[T]) fetchPage(ctx context.Context, token string) ([]T, string, error) {
+	backoff := p.settings.backoff
+	for attempt := 0; ; attempt++ {
+		if err := p.wait(ctx); err != nil {
+			return nil, "", err
+		}
+
+		items, next, err := p.fetch(ctx, token)
+		if err == nil || !errors.Is(err, ErrRateLimited) || attempt >= p.settings.retries {
+			return items, next, err
+		}
+
+		delay := backoff
+		if apiErr, ok := AsError(err); ok && apiErr.RetryAfter > 0 {
+			delay = apiErr.RetryAfter
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// wait blocks until the page delay since the last fetch has passed
+func (p *Pager[T]) wait(ctx context.Context) error {
+	p.mu.Lock()
+	wait := time.Until(p.last.Add(p.settings.delay))
+	p.last = time.Now().Add(max(wait, 0))
+	p.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// PageNumber parses the token of a page-numbered endpoint, where pages
+// are numbered from 1 and "" is the first page. Sub-clients use it in
+// their PageFuncs.
+func PageNumber(token string) int {
+	n, err := strconv.Atoi(token)
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
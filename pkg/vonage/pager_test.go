@@ -0,0 +1,118 @@
+package vonage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// page is a page served by pageServer
+type page struct {
+	Items []string `json:"items"`
+	Next  string   `json:"next"`
+}
+
+// pageServer serves three pages of two items, numbered from 1. The first
+// request for page 2 is rate limited with a Retry-After of one second.
+func pageServer(requests *int32) *httptest.Server {
+	var limited atomic.Bool
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		n := PageNumber(r.URL.Query().Get("page"))
+		if n == 2 && !limited.Swap(true) {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		next := ""
+		if n < 3 {
+			next = fmt.Sprint(n + 1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"items":["%d-a","%d-b"],"next":%q}`, n, n, next)
+	}))
+}
+
+// pageFetch fetches the pages of a pageServer through t
+func pageFetch(t *Transport) PageFunc[string] {
+	return func(ctx context.Context, token string) ([]string, string, error) {
+		var p page
+		if err := t.Do(ctx, http.MethodGet, "/v1/items?page="+fmt.Sprint(PageNumber(token)), nil, &p); err != nil {
+			return nil, "", err
+		}
+		return p.Items, p.Next, nil
+	}
+}
+
+func TestPagerRetryAfter(t *testing.T) {
+	want := []string{"1-a", "1-b", "2-a", "2-b", "3-a", "3-b"}
+
+	tests := []struct {
+		name string
+		opts []PagerOption
+	}{
+		{"sequential", nil},
+		{"prefetch", []PagerOption{WithPrefetch(2)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			srv := pageServer(&requests)
+			defer srv.Close()
+
+			// The backoff outlasts the test, so only Retry-After lets the
+			// pager finish in time
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			opts := append([]PagerOption{WithRateLimitRetry(1, time.Hour)}, tt.opts...)
+			pager := NewPager(pageFetch(NewTransport(srv.URL, nil)), opts...)
+
+			start := time.Now()
+			got, err := pager.All(ctx)
+			if err != nil {
+				t.Fatalf("All() = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("All() = %v, want %v", got, want)
+			}
+			if n := atomic.LoadInt32(&requests); n != 4 {
+				t.Errorf("requests = %d, want 4 with one retry", n)
+			}
+			if d := time.Since(start); d < time.Second {
+				t.Errorf("All() took %s, want at least the one second Retry-After", d)
+			}
+		})
+	}
+}
+
+func TestPagerRateLimitExhausted(t *testing.T) {
+	var requests int32
+	srv := pageServer(&requests)
+	defer srv.Close()
+
+	pager := NewPager(pageFetch(NewTransport(srv.URL, nil)), WithRateLimitRetry(0, time.Hour))
+	ctx := context.Background()
+	if items, err := pager.Next(ctx); err != nil || len(items) != 2 {
+		t.Fatalf("Next() = %v, %v, want the first page", items, err)
+	}
+
+	// Without retries the rate limit is returned, with the requested wait
+	_, err := pager.Next(ctx)
+	apiErr, ok := AsError(err)
+	if !errors.Is(err, ErrRateLimited) || !ok || apiErr.RetryAfter != time.Second {
+		t.Fatalf("Next() = %v, want a rate limit asking to wait a second", err)
+	}
+
+	// The failed page is fetched again on the next call
+	if items, err := pager.Next(ctx); err != nil || !reflect.DeepEqual(items, []string{"2-a", "2-b"}) {
+		t.Errorf("Next() after the rate limit = %v, %v, want page 2", items, err)
+	}
+}
@@ -0,0 +1,114 @@
+package vonage
+
+import (
+	"crypto"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The fixtures in testdata/keys were generated with OpenSSL 3 from rsa.pem
+// (openssl genpkey -algorithm RSA) and ec_sec1.pem (openssl ecparam
+// -genkey -name prime256v1), encrypted with testPassphrase:
+//
+//	openssl pkcs8 -topk8 -v2 <cipher> -v2prf <prf>   rsa_pkcs8_*, ec_pkcs8_*
+//	openssl pkcs8 -topk8 -v1 PBE-SHA1-3DES           rsa_pkcs8_pbes1.pem
+//	openssl rsa -traditional -<cipher>               rsa_legacy_*
+//	openssl ec -aes256                               ec_legacy_aes256.pem
+const testPassphrase = "vonage-test-passphrase"
+
+func readKeyFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "keys", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func parseKeyFixture(t *testing.T, name string) crypto.Signer {
+	t.Helper()
+	key, err := ParsePrivateKey(readKeyFixture(t, name))
+	if err != nil {
+		t.Fatalf("ParsePrivateKey(%s) = %v", name, err)
+	}
+	return key
+}
+
+func TestParseEncryptedPrivateKey(t *testing.T) {
+	tests := []struct {
+		file  string
+		plain string
+	}{
+		{"rsa_pkcs8_aes128_sha1.pem", "rsa.pem"},
+		{"rsa_pkcs8_aes192_sha256.pem", "rsa.pem"},
+		{"rsa_pkcs8_aes256_sha512.pem", "rsa.pem"},
+		{"rsa_pkcs8_des3.pem", "rsa.pem"},
+		{"ec_pkcs8_aes256.pem", "ec_sec1.pem"},
+		{"rsa_legacy_aes256.pem", "rsa.pem"},
+		{"rsa_legacy_des3.pem", "rsa.pem"},
+		{"ec_legacy_aes256.pem", "ec_sec1.pem"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			pemStr := readKeyFixture(t, tt.file)
+			want := parseKeyFixture(t, tt.plain)
+
+			key, err := ParseEncryptedPrivateKey(pemStr, []byte(testPassphrase))
+			if err != nil {
+				t.Fatalf("ParseEncryptedPrivateKey() = %v", err)
+			}
+			if !key.(interface{ Equal(crypto.PrivateKey) bool }).Equal(want) {
+				t.Error("decrypted key differs from the plain key")
+			}
+
+			for _, passphrase := range []string{"", "wrong-passphrase"} {
+				if _, err := ParseEncryptedPrivateKey(pemStr, []byte(passphrase)); !errors.Is(err, ErrEncryptedKey) {
+					t.Errorf("passphrase %q: err = %v, want ErrEncryptedKey", passphrase, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseEncryptedPrivateKeyUnsupported(t *testing.T) {
+	_, err := ParseEncryptedPrivateKey(readKeyFixture(t, "rsa_pkcs8_pbes1.pem"), []byte(testPassphrase))
+	if !errors.Is(err, ErrInvalidPrivateKey) || errors.Is(err, ErrEncryptedKey) {
+		t.Errorf("err = %v, want ErrInvalidPrivateKey for the unsupported scheme", err)
+	}
+}
+
+func TestWithPrivateKeyPassphrase(t *testing.T) {
+	path := filepath.Join("testdata", "keys", "ec_pkcs8_aes256.pem")
+
+	tests := []struct {
+		name    string
+		opts    []CredentialsOption
+		wantErr error
+	}{
+		{"passphrase after file", []CredentialsOption{WithPrivateKeyFile(path), WithPrivateKeyPassphrase(testPassphrase)}, nil},
+		{"passphrase before file", []CredentialsOption{WithPrivateKeyPassphrase(testPassphrase), WithPrivateKeyFile(path)}, nil},
+		{"no passphrase", []CredentialsOption{WithPrivateKeyFile(path)}, ErrEncryptedKey},
+		{"wrong passphrase", []CredentialsOption{WithPrivateKeyFile(path), WithPrivateKeyPassphrase("wrong-passphrase")}, ErrEncryptedKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds, err := NewCredentials(append([]CredentialsOption{WithApplication("app-id", "")}, tt.opts...)...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("NewCredentials() = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewCredentials() = %v", err)
+			}
+			if creds.PrivateKey == nil || algorithmFor(creds.Algorithm, creds.PrivateKey) != JWTAlgorithmES256 {
+				t.Errorf("PrivateKey = %T, want an EC key", creds.PrivateKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package proactive
+
+import (
+	"context"
+	"io"
+)
+
+// ========================================
+// API Interface
+// ========================================
+
+// API is the Proactive Connect API implemented by *Client. Application
+// code can depend on it and substitute vonagemock.Proactive in tests.
+type API interface {
+	CreateList(ctx context.Context, list *List) (*List, error)
+	GetList(ctx context.Context, listID string) (*List, error)
+	UpdateList(ctx context.Context, listID string, list *List) (*List, error)
+	DeleteList(ctx context.Context, listID string) error
+	ListLists(ctx context.Context, opts *PageOptions) (*ListPage, error)
+	CreateItem(ctx context.Context, listID string, data map[string]interface{}) (*Item, error)
+	GetItem(ctx context.Context, listID, itemID string) (*Item, error)
+	UpdateItem(ctx context.Context, listID, itemID string, data map[string]interface{}) (*Item, error)
+	DeleteItem(ctx context.Context, listID, itemID string) error
+	ListItems(ctx context.Context, listID string, opts *PageOptions) (*ItemPage, error)
+	ImportItems(ctx context.Context, listID string, csv io.Reader) (*ImportResult, error)
+	DownloadItems(ctx context.Context, listID string, w io.Writer) (int64, error)
+	ClearItems(ctx context.Context, listID string) error
+	FetchList(ctx context.Context, listID string) error
+	ListEvents(ctx context.Context, opts *EventOptions) (*EventPage, error)
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,400 @@
+// Package proactive manages Proactive Connect lists and their items, so
+// campaign audiences can be maintained server-side instead of uploading
+// CSVs in the dashboard. Actions configured on a list run against its
+// items; their outcomes are available as events.
+package proactive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+const (
+	// BaseURL is the Vonage Proactive Connect API base URL
+	BaseURL = vonage.BaseURLRegional
+
+	// basePath prefixes every Proactive Connect endpoint
+	basePath = "/v0.1/bulk"
+)
+
+// ErrNameRequired is returned when creating a list without a name
+var ErrNameRequired = errors.New("proactive: list name is required")
+
+// Client handles Vonage Proactive Connect API operations
+type Client struct {
+	baseURL      string
+	jwtGenerator *vonage.JWTGenerator
+	httpClient   *http.Client
+	middleware   []vonage.Middleware
+	transport    *vonage.Transport
+	logger       vonage.Logger
+	uaSuffix     string
+	mode         vonage.Mode
+}
+
+// ClientOption is a functional option for configuring the proactive client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the base URL (useful for testing)
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l vonage.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier such as
+// "checkin-service/1.4" to the User-Agent
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.uaSuffix = suffix
+	}
+}
+
+// WithMiddleware adds transport middleware (retries, tracing, metrics)
+func WithMiddleware(mw ...vonage.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithMode sets the client's mode (default vonage.ModeLive). In
+// vonage.ModeMock no request is sent, and in vonage.ModeDryRun only
+// reads are.
+func WithMode(m vonage.Mode) ClientOption {
+	return func(c *Client) {
+		c.mode = m
+	}
+}
+
+// WithSettings applies the HTTP client, logger, middleware, User-Agent
+// suffix and mode shared by a root vonage.Client, see
+// vonage.Client.Settings
+func WithSettings(s vonage.Settings) ClientOption {
+	return func(c *Client) {
+		if s.HTTPClient != nil {
+			c.httpClient = s.HTTPClient
+		}
+		if s.Logger != nil {
+			c.logger = s.Logger
+		}
+		c.middleware = append(c.middleware, s.Middleware...)
+		c.uaSuffix = s.UserAgentSuffix
+		c.mode = s.Mode
+	}
+}
+
+// WithTransport uses a shared transport instead of building one; the
+// base URL, HTTP client and middleware options are then ignored
+func WithTransport(t *vonage.Transport) ClientOption {
+	return func(c *Client) {
+		c.transport = t
+	}
+}
+
+// NewClient creates a new Vonage Proactive Connect API client
+func NewClient(jwtGenerator *vonage.JWTGenerator, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:      BaseURL,
+		jwtGenerator: jwtGenerator,
+		httpClient:   &http.Client{Timeout: vonage.DefaultTimeout},
+		logger:       vonage.NopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.transport == nil {
+		c.transport = vonage.NewTransport(c.baseURL, jwtGenerator,
+			vonage.WithTransportHTTPClient(c.httpClient),
+			vonage.WithMiddleware(c.middleware...),
+			vonage.WithTransportLogger(c.logger),
+			vonage.WithTransportUserAgentSuffix(c.uaSuffix),
+			vonage.WithTransportMode(c.mode),
+		)
+	}
+
+	return c
+}
+
+// NewClientFromCredentials creates a new client from Vonage credentials
+func NewClientFromCredentials(creds *vonage.Credentials, opts ...ClientOption) (*Client, error) {
+	if !creds.HasApplication() {
+		return nil, vonage.ErrNotConfigured
+	}
+
+	jwtGen := vonage.NewJWTGeneratorFromCredentials(creds)
+	return NewClient(jwtGen, append([]ClientOption{WithMode(creds.Mode)}, opts...)...), nil
+}
+
+// ========================================
+// Lists
+// ========================================
+
+// CreateList creates a list
+func (c *Client) CreateList(ctx context.Context, list *List) (*List, error) {
+	if list == nil || list.Name == "" {
+		return nil, ErrNameRequired
+	}
+
+	var created List
+	if err := c.transport.Do(ctx, http.MethodPost, basePath+"/lists", list, &created); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Created Proactive Connect list", "listID", created.ID, "name", created.Name)
+	return &created, nil
+}
+
+// GetList retrieves a list
+func (c *Client) GetList(ctx context.Context, listID string) (*List, error) {
+	var list List
+	if err := c.transport.Do(ctx, http.MethodGet, listPath(listID), nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// UpdateList replaces a list's definition. Name is required.
+func (c *Client) UpdateList(ctx context.Context, listID string, list *List) (*List, error) {
+	if list == nil || list.Name == "" {
+		return nil, ErrNameRequired
+	}
+
+	var updated List
+	if err := c.transport.Do(ctx, http.MethodPut, listPath(listID), list, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteList deletes a list and its items
+func (c *Client) DeleteList(ctx context.Context, listID string) error {
+	if err := c.transport.Do(ctx, http.MethodDelete, listPath(listID), nil, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Deleted Proactive Connect list", "listID", listID)
+	return nil
+}
+
+// ListLists returns one page of lists
+func (c *Client) ListLists(ctx context.Context, opts *PageOptions) (*ListPage, error) {
+	var resp pageResponse
+	if err := c.transport.Do(ctx, http.MethodGet, basePath+"/lists"+encode(opts.values()), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &ListPage{Lists: resp.Embedded.Lists, PageInfo: resp.PageInfo}, nil
+}
+
+// ========================================
+// Items
+// ========================================
+
+// CreateItem adds an item to a list
+func (c *Client) CreateItem(ctx context.Context, listID string, data map[string]interface{}) (*Item, error) {
+	var item Item
+	if err := c.transport.Do(ctx, http.MethodPost, listPath(listID)+"/items", Item{Data: data}, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetItem retrieves a list item
+func (c *Client) GetItem(ctx context.Context, listID, itemID string) (*Item, error) {
+	var item Item
+	if err := c.transport.Do(ctx, http.MethodGet, itemPath(listID, itemID), nil, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// UpdateItem replaces a list item's data
+func (c *Client) UpdateItem(ctx context.Context, listID, itemID string, data map[string]interface{}) (*Item, error) {
+	var item Item
+	if err := c.transport.Do(ctx, http.MethodPut, itemPath(listID, itemID), Item{Data: data}, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// DeleteItem removes an item from a list
+func (c *Client) DeleteItem(ctx context.Context, listID, itemID string) error {
+	return c.transport.Do(ctx, http.MethodDelete, itemPath(listID, itemID), nil, nil)
+}
+
+// ListItems returns one page of a list's items
+func (c *Client) ListItems(ctx context.Context, listID string, opts *PageOptions) (*ItemPage, error) {
+	var resp pageResponse
+	if err := c.transport.Do(ctx, http.MethodGet, listPath(listID)+"/items"+encode(opts.values()), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &ItemPage{Items: resp.Embedded.Items, PageInfo: resp.PageInfo}, nil
+}
+
+// ========================================
+// Bulk Actions
+// ========================================
+
+// ImportItems uploads a CSV file of items to a list. The header row must
+// match the list's attribute names; rows whose key attribute matches an
+// existing item update it. This is the way to load large audiences.
+func (c *Client) ImportItems(ctx context.Context, listID string, csv io.Reader) (*ImportResult, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	part, err := mw.CreateFormFile("file", "items.csv")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build upload: %w", err)
+	}
+	if _, err := io.Copy(part, csv); err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build upload: %w", err)
+	}
+
+	body := vonage.RawBody{ContentType: mw.FormDataContentType(), Body: &buf}
+
+	var result ImportResult
+	if err := c.transport.Do(ctx, http.MethodPost, listPath(listID)+"/items/import", body, &result); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Imported Proactive Connect list items", "listID", listID, "inserted", result.Inserted)
+	return &result, nil
+}
+
+// DownloadItems streams all of a list's items to w as CSV
+func (c *Client) DownloadItems(ctx context.Context, listID string, w io.Writer) (int64, error) {
+	return c.transport.Download(ctx, listPath(listID)+"/items/download", w)
+}
+
+// ClearItems deletes all of a list's items, keeping the list
+func (c *Client) ClearItems(ctx context.Context, listID string) error {
+	if err := c.transport.Do(ctx, http.MethodPost, listPath(listID)+"/clear", nil, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Cleared Proactive Connect list", "listID", listID)
+	return nil
+}
+
+// FetchList starts a sync of a list from its datasource (e.g. Salesforce).
+// The sync runs asynchronously; poll GetList for its SyncStatus.
+func (c *Client) FetchList(ctx context.Context, listID string) error {
+	return c.transport.Do(ctx, http.MethodPost, listPath(listID)+"/fetch", nil, nil)
+}
+
+// ========================================
+// Events
+// ========================================
+
+// ListEvents returns one page of action events, e.g. to follow the
+// progress of a campaign run
+func (c *Client) ListEvents(ctx context.Context, opts *EventOptions) (*EventPage, error) {
+	var resp pageResponse
+	if err := c.transport.Do(ctx, http.MethodGet, basePath+"/events"+opts.query(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &EventPage{Events: resp.Embedded.Events, PageInfo: resp.PageInfo}, nil
+}
+
+// NewListPager returns a pager over the lists, from opts.Page on
+func NewListPager(c API, opts *PageOptions, pagerOpts ...vonage.PagerOption) *vonage.Pager[List] {
+	base := pageOptions(opts)
+	return vonage.NewPager(func(ctx context.Context, token string) ([]List, string, error) {
+		page, err := c.ListLists(ctx, base.at(token))
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Lists, page.nextToken(), nil
+	}, pagerOpts...)
+}
+
+// NewItemPager returns a pager over a list's items, from opts.Page on
+func NewItemPager(c API, listID string, opts *PageOptions, pagerOpts ...vonage.PagerOption) *vonage.Pager[Item] {
+	base := pageOptions(opts)
+	return vonage.NewPager(func(ctx context.Context, token string) ([]Item, string, error) {
+		page, err := c.ListItems(ctx, listID, base.at(token))
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Items, page.nextToken(), nil
+	}, pagerOpts...)
+}
+
+// NewEventPager returns a pager over the events matching opts, from
+// opts.Page on
+func NewEventPager(c API, opts *EventOptions, pagerOpts ...vonage.PagerOption) *vonage.Pager[Event] {
+	var base EventOptions
+	if opts != nil {
+		base = *opts
+	}
+	return vonage.NewPager(func(ctx context.Context, token string) ([]Event, string, error) {
+		o := base
+		o.PageOptions = *base.PageOptions.at(token)
+		page, err := c.ListEvents(ctx, &o)
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Events, page.nextToken(), nil
+	}, pagerOpts...)
+}
+
+// pageOptions copies opts, which may be nil
+func pageOptions(opts *PageOptions) PageOptions {
+	if opts == nil {
+		return PageOptions{}
+	}
+	return *opts
+}
+
+// at returns a copy of the options for the page of a pager token
+func (o PageOptions) at(token string) *PageOptions {
+	if token != "" {
+		o.Page = vonage.PageNumber(token)
+	}
+	return &o
+}
+
+// listPath returns the path of a list resource
+func listPath(listID string) string {
+	return basePath + "/lists/" + url.PathEscape(listID)
+}
+
+// itemPath returns the path of a list item resource
+func itemPath(listID, itemID string) string {
+	return listPath(listID) + "/items/" + url.PathEscape(itemID)
+}
+
+// ========================================
+// Health
+// ========================================
+
+// Ping checks the credentials and connectivity for readiness probes; it
+// lists one list
+func (c *Client) Ping(ctx context.Context) *vonage.Health {
+	return c.transport.Ping(ctx, "proactive", basePath+"/lists?page_size=1")
+}
@@ -0,0 +1,64 @@
+package proactive_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/proactive"
+)
+
+func ExampleClient_ImportItems() {
+	creds, _ := vonage.NewCredentialsFromEnv()
+	client, _ := proactive.NewClientFromCredentials(creds)
+
+	ctx := context.Background()
+	list, err := client.CreateList(ctx, &proactive.List{
+		Name: "October reactivation",
+		Tags: []string{"campaign"},
+		Attributes: []proactive.Attribute{
+			{Name: "phone", Key: true},
+			{Name: "first_name"},
+		},
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	f, err := os.Open("audience.csv")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer f.Close()
+
+	result, err := client.ImportItems(ctx, list.ID, f)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("imported", result.Inserted)
+}
+
+func ExampleClient_ListItems() {
+	creds, _ := vonage.NewCredentialsFromEnv()
+	client, _ := proactive.NewClientFromCredentials(creds)
+
+	opts := &proactive.PageOptions{Page: 1, PageSize: 500}
+	for {
+		page, err := client.ListItems(context.Background(), "list-id", opts)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		for _, item := range page.Items {
+			fmt.Println(item.Data["phone"])
+		}
+		if !page.HasNext() {
+			break
+		}
+		opts.Page++
+	}
+}
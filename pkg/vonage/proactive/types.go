@@ -0,0 +1,216 @@
+package proactive
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ========================================
+// Lists
+// ========================================
+
+// DatasourceType is where a list's items come from
+type DatasourceType string
+
+const (
+	// DatasourceManual lists are filled through the API or CSV imports
+	DatasourceManual DatasourceType = "manual"
+	// DatasourceSalesforce lists are synced from a Salesforce SOQL query
+	DatasourceSalesforce DatasourceType = "salesforce"
+)
+
+// List is a Proactive Connect list: a campaign audience
+type List struct {
+	ID          string      `json:"id,omitempty"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Tags        []string    `json:"tags,omitempty"`
+	Attributes  []Attribute `json:"attributes,omitempty"`
+	Datasource  *Datasource `json:"datasource,omitempty"`
+
+	// Read only
+	ItemsCount int         `json:"items_count,omitempty"`
+	SyncStatus *SyncStatus `json:"sync_status,omitempty"`
+	CreatedAt  *time.Time  `json:"created_at,omitempty"`
+	UpdatedAt  *time.Time  `json:"updated_at,omitempty"`
+}
+
+// Attribute is a column of a list
+type Attribute struct {
+	Name  string `json:"name"`
+	Alias string `json:"alias,omitempty"`
+	// Key marks the attribute that identifies an item (e.g. the phone
+	// number); imports update items with a matching key
+	Key bool `json:"key,omitempty"`
+}
+
+// Datasource describes where a list's items come from
+type Datasource struct {
+	Type DatasourceType `json:"type"`
+	// IntegrationID and SOQL are used by Salesforce lists
+	IntegrationID string `json:"integration_id,omitempty"`
+	SOQL          string `json:"soql,omitempty"`
+}
+
+// SyncStatus is the state of a list's last datasource sync
+type SyncStatus struct {
+	Value            string `json:"value"`
+	Details          string `json:"details,omitempty"`
+	MetadataModified bool   `json:"metadata_modified"`
+	DataModified     bool   `json:"data_modified"`
+	Dirty            bool   `json:"dirty"`
+}
+
+// Item is a row of a list
+type Item struct {
+	ID        string                 `json:"id,omitempty"`
+	ListID    string                 `json:"list_id,omitempty"`
+	Data      map[string]interface{} `json:"data"`
+	CreatedAt *time.Time             `json:"created_at,omitempty"`
+	UpdatedAt *time.Time             `json:"updated_at,omitempty"`
+}
+
+// ImportResult is the outcome of a CSV import
+type ImportResult struct {
+	Inserted int `json:"inserted"`
+}
+
+// ========================================
+// Events
+// ========================================
+
+// Event is a record of an action run against list items, e.g. a message
+// sent or a call placed
+type Event struct {
+	ID          string                 `json:"id"`
+	Type        string                 `json:"type"`
+	CreatedAt   time.Time              `json:"created_at"`
+	JobID       string                 `json:"job_id,omitempty"`
+	SrcCtx      string                 `json:"src_ctx,omitempty"`
+	ActionID    string                 `json:"action_id,omitempty"`
+	RunID       string                 `json:"run_id,omitempty"`
+	RunItemID   string                 `json:"run_item_id,omitempty"`
+	RecipientID string                 `json:"recipient_id,omitempty"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+}
+
+// EventOptions filters the event list
+type EventOptions struct {
+	PageOptions
+	ActionID    string
+	RunID       string
+	RunItemID   string
+	RecipientID string
+	Type        string
+	DateStart   time.Time
+	DateEnd     time.Time
+}
+
+// ========================================
+// Paging
+// ========================================
+
+// PageOptions pages a list endpoint. Pages are numbered from 1.
+type PageOptions struct {
+	Page int
+	// PageSize is the number of results per page (max 1000)
+	PageSize int
+}
+
+// ListPage is one page of lists
+type ListPage struct {
+	Lists []List
+	PageInfo
+}
+
+// ItemPage is one page of list items
+type ItemPage struct {
+	Items []Item
+	PageInfo
+}
+
+// EventPage is one page of events
+type EventPage struct {
+	Events []Event
+	PageInfo
+}
+
+// PageInfo describes the position of a page
+type PageInfo struct {
+	Page       int `json:"page"`
+	PageSize   int `json:"page_size"`
+	TotalItems int `json:"total_items"`
+	TotalPages int `json:"total_pages"`
+}
+
+// HasNext returns true if there are more pages after this one
+func (p PageInfo) HasNext() bool {
+	return p.Page < p.TotalPages
+}
+
+// nextToken returns the pager token of the next page
+func (p PageInfo) nextToken() string {
+	if !p.HasNext() {
+		return ""
+	}
+	return strconv.Itoa(p.Page + 1)
+}
+
+// pageResponse is the HAL body of the paged endpoints
+type pageResponse struct {
+	PageInfo
+	Embedded struct {
+		Lists  []List  `json:"lists"`
+		Items  []Item  `json:"items"`
+		Events []Event `json:"events"`
+	} `json:"_embedded"`
+}
+
+// values encodes page options
+func (o *PageOptions) values() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	if o.Page > 0 {
+		q.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PageSize > 0 {
+		q.Set("page_size", strconv.Itoa(o.PageSize))
+	}
+	return q
+}
+
+// query encodes event options
+func (o *EventOptions) query() string {
+	if o == nil {
+		return ""
+	}
+	q := o.PageOptions.values()
+	setIf(q, "action_id", o.ActionID)
+	setIf(q, "run_id", o.RunID)
+	setIf(q, "run_item_id", o.RunItemID)
+	setIf(q, "recipient_id", o.RecipientID)
+	setIf(q, "type", o.Type)
+	if !o.DateStart.IsZero() {
+		q.Set("date_start", o.DateStart.UTC().Format(time.RFC3339))
+	}
+	if !o.DateEnd.IsZero() {
+		q.Set("date_end", o.DateEnd.UTC().Format(time.RFC3339))
+	}
+	return encode(q)
+}
+
+func setIf(q url.Values, key, value string) {
+	if value != "" {
+		q.Set(key, value)
+	}
+}
+
+func encode(q url.Values) string {
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
@@ -0,0 +1,151 @@
+package vonage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ========================================
+// Credentials Providers
+// ========================================
+
+// CredentialsProvider supplies the credentials to use for a request. It is
+// consulted on every request, so implementations can rotate keys without
+// recreating clients.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (*Credentials, error)
+}
+
+// CredentialsProviderFunc adapts a function to CredentialsProvider, e.g. a
+// secrets-manager lookup
+type CredentialsProviderFunc func(ctx context.Context) (*Credentials, error)
+
+// Credentials implements CredentialsProvider
+func (f CredentialsProviderFunc) Credentials(ctx context.Context) (*Credentials, error) {
+	return f(ctx)
+}
+
+// Credentials implements CredentialsProvider for static credentials
+func (c *Credentials) Credentials(context.Context) (*Credentials, error) {
+	return c, nil
+}
+
+// NewProviderAuthenticator returns an Authenticator that fetches
+// credentials from p for every request and signs with a JWT. Use
+// NewAuthChain to fall back to the API key and secret.
+func NewProviderAuthenticator(p CredentialsProvider) Authenticator {
+	return NewAuthChain(p, AuthJWT)
+}
+
+// ========================================
+// Rotating Credentials
+// ========================================
+
+// RotatingCredentials is a CredentialsProvider whose credentials can be
+// replaced at runtime, either directly with Set or by the WatchKeyFile
+// and RefreshEvery helpers
+type RotatingCredentials struct {
+	// OnError, if set, receives reload failures from the watch helpers.
+	// The previous credentials stay in use after a failure.
+	OnError func(err error)
+
+	mu    sync.RWMutex
+	creds *Credentials
+}
+
+// NewRotatingCredentials creates a provider starting with initial
+func NewRotatingCredentials(initial *Credentials) *RotatingCredentials {
+	return &RotatingCredentials{creds: initial}
+}
+
+// Credentials implements CredentialsProvider
+func (r *RotatingCredentials) Credentials(context.Context) (*Credentials, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.creds, nil
+}
+
+// Set replaces the current credentials
+func (r *RotatingCredentials) Set(creds *Credentials) {
+	r.mu.Lock()
+	r.creds = creds
+	r.mu.Unlock()
+}
+
+// WatchKeyFile polls path every interval and swaps in the private key
+// whenever the file's modification time changes. It returns when ctx is
+// cancelled.
+func (r *RotatingCredentials) WatchKeyFile(ctx context.Context, path string, interval time.Duration) {
+	var lastMod time.Time
+	if info, err := os.Stat(path); err == nil {
+		lastMod = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil {
+				r.fail(err)
+				continue
+			}
+			if !info.ModTime().After(lastMod) {
+				continue
+			}
+			lastMod = info.ModTime()
+			r.reloadKey(path)
+		}
+	}
+}
+
+// reloadKey replaces the private key with the contents of path
+func (r *RotatingCredentials) reloadKey(path string) {
+	current, _ := r.Credentials(context.Background())
+	next := *current
+	if err := WithPrivateKeyFile(path)(&next); err != nil {
+		r.fail(err)
+		return
+	}
+	if next.pendingKey != "" {
+		r.fail(fmt.Errorf("%s: %w, a passphrase is required (WithPrivateKeyPassphrase)", path, ErrEncryptedKey))
+		return
+	}
+	r.Set(&next)
+}
+
+// RefreshEvery calls fetch every interval and swaps in the returned
+// credentials, e.g. from a secrets manager. It returns when ctx is
+// cancelled.
+func (r *RotatingCredentials) RefreshEvery(ctx context.Context, interval time.Duration, fetch CredentialsProviderFunc) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			creds, err := fetch(ctx)
+			if err != nil {
+				r.fail(err)
+				continue
+			}
+			r.Set(creds)
+		}
+	}
+}
+
+// fail reports a reload error to OnError
+func (r *RotatingCredentials) fail(err error) {
+	if r.OnError != nil {
+		r.OnError(err)
+	}
+}
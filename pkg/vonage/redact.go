@@ -0,0 +1,257 @@
+package vonage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// ========================================
+// Request Body Logging
+// ========================================
+
+// Default fields redacted by a Redactor, matched case-insensitively at any
+// depth of a JSON body or as form keys
+var (
+	DefaultNumberFields = []string{"to", "from", "number", "msisdn", "phone_number", "sender"}
+	DefaultTextFields   = []string{"text", "body", "caption"}
+	DefaultSecretFields = []string{"api_secret", "password", "secret", "pin", "code", "sig", "signature_secret"}
+)
+
+// DefaultTextLimit is how many characters of a text field a Redactor keeps
+const DefaultTextLimit = 8
+
+// Redactor masks personal data in request bodies so they can be logged:
+// phone numbers keep their first and last digits, message text is
+// truncated and secrets are replaced. JSON bodies are re-encoded with
+// sorted keys, so identical requests log identically.
+type Redactor struct {
+	numbers   map[string]bool
+	texts     map[string]bool
+	secrets   map[string]bool
+	textLimit int
+}
+
+// RedactorOption is a functional option for configuring a Redactor
+type RedactorOption func(*Redactor)
+
+// WithRedactedNumbers adds fields masked as phone numbers
+func WithRedactedNumbers(fields ...string) RedactorOption {
+	return func(r *Redactor) {
+		addFields(r.numbers, fields)
+	}
+}
+
+// WithRedactedTexts adds fields truncated as message text
+func WithRedactedTexts(fields ...string) RedactorOption {
+	return func(r *Redactor) {
+		addFields(r.texts, fields)
+	}
+}
+
+// WithRedactedSecrets adds fields replaced entirely
+func WithRedactedSecrets(fields ...string) RedactorOption {
+	return func(r *Redactor) {
+		addFields(r.secrets, fields)
+	}
+}
+
+// WithRedactedTextLimit sets how many characters of text fields are kept (default
+// DefaultTextLimit); 0 hides text entirely
+func WithRedactedTextLimit(n int) RedactorOption {
+	return func(r *Redactor) {
+		r.textLimit = n
+	}
+}
+
+// NewRedactor creates a redactor for the default fields and any added by
+// opts
+func NewRedactor(opts ...RedactorOption) *Redactor {
+	r := &Redactor{
+		numbers:   make(map[string]bool),
+		texts:     make(map[string]bool),
+		secrets:   make(map[string]bool),
+		textLimit: DefaultTextLimit,
+	}
+	addFields(r.numbers, DefaultNumberFields)
+	addFields(r.texts, DefaultTextFields)
+	addFields(r.secrets, DefaultSecretFields)
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+func addFields(set map[string]bool, fields []string) {
+	for _, f := range fields {
+		set[strings.ToLower(f)] = true
+	}
+}
+
+// RedactJSON returns a redacted copy of a JSON document with sorted keys.
+// Documents that fail to parse are withheld rather than logged as-is.
+func (r *Redactor) RedactJSON(data []byte) string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "[unparsable JSON body withheld]"
+	}
+	out, err := json.Marshal(r.redactValue("", v))
+	if err != nil {
+		return "[unencodable JSON body withheld]"
+	}
+	return string(out)
+}
+
+// RedactForm returns a redacted copy of form values, encoded with sorted
+// keys
+func (r *Redactor) RedactForm(form url.Values) string {
+	out := make(url.Values, len(form))
+	for key, values := range form {
+		for _, v := range values {
+			out.Add(key, r.redactString(key, v))
+		}
+	}
+	return out.Encode()
+}
+
+// redactValue redacts v, found under key, recursively
+func (r *Redactor) redactValue(key string, v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = r.redactValue(k, item)
+		}
+		return out
+	case []interface{}:
+		// Array items inherit the key, e.g. "to": ["15551234567"]
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = r.redactValue(key, item)
+		}
+		return out
+	case string:
+		return r.redactString(key, val)
+	case json.Number:
+		if redacted := r.redactString(key, val.String()); redacted != val.String() {
+			return redacted
+		}
+	}
+	return v
+}
+
+// redactString redacts a string value by its key
+func (r *Redactor) redactString(key, s string) string {
+	key = strings.ToLower(key)
+	switch {
+	case r.secrets[key]:
+		return "REDACTED"
+	case r.numbers[key]:
+		return MaskNumber(s)
+	case r.texts[key]:
+		return TruncateText(s, r.textLimit)
+	}
+	return s
+}
+
+// MaskNumber masks the middle digits of a phone number, keeping the first
+// three and last two, e.g. "81901234567" becomes "819******67". Short
+// values are masked entirely; values without digits, such as alphanumeric
+// sender IDs, are kept.
+func MaskNumber(s string) string {
+	digits := 0
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			digits++
+		}
+	}
+	if digits == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	seen := 0
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			b.WriteRune(c)
+			continue
+		}
+		if digits > 6 && (seen < 3 || seen >= digits-2) {
+			b.WriteRune(c)
+		} else {
+			b.WriteByte('*')
+		}
+		seen++
+	}
+	return b.String()
+}
+
+// TruncateText keeps the first n characters of s and notes how many were
+// dropped, e.g. "Your cod…(+12 chars)"
+func TruncateText(s string, n int) string {
+	total := utf8.RuneCountInString(s)
+	if total <= n {
+		return s
+	}
+	kept := []rune(s)[:max(n, 0)]
+	return fmt.Sprintf("%s…(+%d chars)", string(kept), total-len(kept))
+}
+
+// LogRequestBodies returns middleware that logs each outgoing JSON or form
+// request body at debug level, redacted by r (NewRedactor() if nil). Add
+// it with WithTransportMiddleware to debug payloads without writing
+// customer data to logs. l is used unless the request context carries a
+// logger (see ContextWithLogger).
+func LogRequestBodies(l Logger, r *Redactor) Middleware {
+	if r == nil {
+		r = NewRedactor()
+	}
+	return InterceptRequest(func(req *http.Request) error {
+		if req.GetBody == nil {
+			return nil
+		}
+		mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if mediaType != "application/json" && mediaType != "application/x-www-form-urlencoded" {
+			return nil
+		}
+
+		// Read a copy so the body itself is still sent
+		body, err := req.GetBody()
+		if err != nil {
+			return nil
+		}
+		data, err := io.ReadAll(body)
+		body.Close()
+		if err != nil {
+			return nil
+		}
+
+		var redacted string
+		if mediaType == "application/json" {
+			redacted = r.RedactJSON(data)
+		} else {
+			form, err := url.ParseQuery(string(data))
+			if err != nil {
+				return nil
+			}
+			redacted = r.RedactForm(form)
+		}
+
+		LoggerFromContext(req.Context(), l).Debug("Vonage API request body",
+			"method", req.Method,
+			"url", logURL(req.URL),
+			"body", redacted,
+		)
+		return nil
+	})
+}
@@ -0,0 +1,26 @@
+package reports
+
+import (
+	"context"
+	"io"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// API Interface
+// ========================================
+
+// API is the Reports API implemented by *Client. Application code can
+// depend on it and substitute vonagemock.Reports in tests.
+type API interface {
+	Create(ctx context.Context, opts CreateOptions) (*Report, error)
+	Get(ctx context.Context, requestID string) (*Report, error)
+	Cancel(ctx context.Context, requestID string) error
+	Wait(ctx context.Context, requestID string, interval time.Duration) (*Report, error)
+	Download(ctx context.Context, report *Report, w io.Writer, opts ...vonage.DownloadOption) (int64, error)
+	DownloadFile(ctx context.Context, report *Report, path string, opts ...vonage.DownloadOption) error
+}
+
+var _ API = (*Client)(nil)
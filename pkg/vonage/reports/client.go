@@ -0,0 +1,295 @@
+// Package reports creates asynchronous usage report jobs, waits for them to
+// complete and downloads the resulting files.
+package reports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+const (
+	// BaseURL is the Vonage Reports API base URL
+	BaseURL = vonage.BaseURLREST
+
+	// DefaultPollInterval is used by Wait when interval is zero
+	DefaultPollInterval = 10 * time.Second
+)
+
+var (
+	// ErrNotConfigured is returned when the client has no API key and secret
+	ErrNotConfigured = errors.New("reports: API key and secret required")
+	// ErrNoFile is returned when downloading a report that has no file
+	ErrNoFile = errors.New("reports: report has no file")
+)
+
+// Client handles Vonage Reports API operations. The API authenticates with
+// the account API key and secret.
+type Client struct {
+	apiKey    string
+	apiSecret string
+	provider  vonage.CredentialsProvider
+
+	baseURL    string
+	httpClient *http.Client
+	middleware []vonage.Middleware
+	transport  *vonage.Transport
+	logger     vonage.Logger
+	uaSuffix   string
+	mode       vonage.Mode
+}
+
+// ClientOption is a functional option for configuring the reports client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the base URL (useful for testing)
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l vonage.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier such as
+// "checkin-service/1.4" to the User-Agent
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.uaSuffix = suffix
+	}
+}
+
+// WithMiddleware adds transport middleware (retries, tracing, metrics)
+func WithMiddleware(mw ...vonage.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithMode sets the client's mode (default vonage.ModeLive). In
+// vonage.ModeMock no request is sent, and in vonage.ModeDryRun only
+// reads are.
+func WithMode(m vonage.Mode) ClientOption {
+	return func(c *Client) {
+		c.mode = m
+	}
+}
+
+// WithSettings applies the HTTP client, logger, middleware, User-Agent
+// suffix and mode shared by a root vonage.Client, see
+// vonage.Client.Settings
+func WithSettings(s vonage.Settings) ClientOption {
+	return func(c *Client) {
+		if s.HTTPClient != nil {
+			c.httpClient = s.HTTPClient
+		}
+		if s.Logger != nil {
+			c.logger = s.Logger
+		}
+		c.middleware = append(c.middleware, s.Middleware...)
+		c.uaSuffix = s.UserAgentSuffix
+		c.mode = s.Mode
+	}
+}
+
+// WithCredentialsProvider fetches the API key and secret from p for every
+// request instead of using those passed to NewClient, so rotated secrets
+// are picked up. Reports default to the account of the API key passed to
+// NewClient.
+func WithCredentialsProvider(p vonage.CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.provider = p
+	}
+}
+
+// NewClient creates a new Vonage Reports API client
+func NewClient(apiKey, apiSecret string, opts ...ClientOption) *Client {
+	c := &Client{
+		apiKey:     apiKey,
+		apiSecret:  apiSecret,
+		baseURL:    BaseURL,
+		httpClient: &http.Client{Timeout: vonage.DefaultTimeout},
+		logger:     vonage.NopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	provider := c.provider
+	if provider == nil {
+		provider = &vonage.Credentials{APIKey: apiKey, APISecret: apiSecret}
+	}
+	c.transport = vonage.NewTransport(c.baseURL, vonage.NewAuthChain(provider, vonage.AuthBasic),
+		vonage.WithTransportHTTPClient(c.httpClient),
+		vonage.WithMiddleware(c.middleware...),
+		vonage.WithTransportLogger(c.logger),
+		vonage.WithTransportUserAgentSuffix(c.uaSuffix),
+		vonage.WithTransportMode(c.mode),
+	)
+
+	return c
+}
+
+// NewClientFromCredentials creates a new client from Vonage credentials
+func NewClientFromCredentials(creds *vonage.Credentials, opts ...ClientOption) (*Client, error) {
+	if !creds.HasAPIKey() {
+		return nil, ErrNotConfigured
+	}
+	return NewClient(creds.APIKey, creds.APISecret, append([]ClientOption{WithMode(creds.Mode)}, opts...)...), nil
+}
+
+// ========================================
+// Report Jobs
+// ========================================
+
+// Create starts an asynchronous report job
+func (c *Client) Create(ctx context.Context, opts CreateOptions) (*Report, error) {
+	req := createRequest{
+		AccountID:          opts.AccountID,
+		Product:            opts.Product,
+		Direction:          opts.Direction,
+		IncludeSubaccounts: opts.IncludeSubaccounts,
+		IncludeMessage:     opts.IncludeMessage,
+		CallbackURL:        opts.CallbackURL,
+	}
+	if req.AccountID == "" {
+		req.AccountID = c.apiKey
+	}
+	if !opts.DateStart.IsZero() {
+		req.DateStart = opts.DateStart.UTC().Format(time.RFC3339)
+	}
+	if !opts.DateEnd.IsZero() {
+		req.DateEnd = opts.DateEnd.UTC().Format(time.RFC3339)
+	}
+
+	var resp reportResponse
+	if err := c.transport.Do(ctx, http.MethodPost, "/v2/reports", req, &resp); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Created Vonage report job",
+		"requestID", resp.RequestID,
+		"product", string(opts.Product),
+	)
+	return resp.report(), nil
+}
+
+// Get returns the current state of a report job
+func (c *Client) Get(ctx context.Context, requestID string) (*Report, error) {
+	var resp reportResponse
+	if err := c.transport.Do(ctx, http.MethodGet, reportPath(requestID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.report(), nil
+}
+
+// Cancel aborts a pending or processing report job
+func (c *Client) Cancel(ctx context.Context, requestID string) error {
+	return c.transport.Do(ctx, http.MethodDelete, reportPath(requestID), nil, nil)
+}
+
+// Wait polls a report job every interval until it reaches a terminal
+// status or ctx is done
+func (c *Client) Wait(ctx context.Context, requestID string, interval time.Duration) (*Report, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := c.Get(ctx, requestID)
+		if err != nil {
+			return nil, err
+		}
+		if report.Status.IsTerminal() {
+			return report, nil
+		}
+
+		c.logger.Debug("Waiting for Vonage report",
+			"requestID", requestID,
+			"status", string(report.Status),
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ========================================
+// Download
+// ========================================
+
+// Download streams a completed report's file (a zipped CSV) to w
+func (c *Client) Download(ctx context.Context, report *Report, w io.Writer, opts ...vonage.DownloadOption) (int64, error) {
+	if report == nil || report.DownloadURL == "" || !report.Status.HasFile() {
+		return 0, ErrNoFile
+	}
+	return c.transport.Download(ctx, report.DownloadURL, w, opts...)
+}
+
+// DownloadFile saves a completed report's file to path. If path already
+// holds a partial download, it is resumed from where it stopped, so a
+// failed call can simply be retried. A vonage.WithChecksum option only
+// covers the bytes downloaded by this call.
+func (c *Client) DownloadFile(ctx context.Context, report *Report, path string, opts ...vonage.DownloadOption) error {
+	if report == nil || report.DownloadURL == "" || !report.Status.HasFile() {
+		return ErrNoFile
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open report file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat report file: %w", err)
+	}
+
+	n, err := c.transport.DownloadFrom(ctx, report.DownloadURL, info.Size(), f, opts...)
+	if err != nil {
+		var apiErr *vonage.Error
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			// The file was already complete
+			return nil
+		}
+		return err
+	}
+
+	c.logger.Info("Downloaded Vonage report",
+		"requestID", report.RequestID,
+		"bytes", info.Size()+n,
+	)
+	return nil
+}
+
+// reportPath returns the path of a report job
+func reportPath(requestID string) string {
+	return "/v2/reports/" + url.PathEscape(requestID)
+}
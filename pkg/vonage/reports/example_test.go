@@ -0,0 +1,51 @@
+package reports_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/reports"
+)
+
+func ExampleClient_Create() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithAPIKey("api-key", "api-secret"),
+	)
+	client, _ := reports.NewClientFromCredentials(creds)
+
+	// Last month's outbound SMS usage
+	end := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-time.Now().UTC().Day())
+	start := end.AddDate(0, -1, 0)
+
+	ctx := context.Background()
+	report, err := client.Create(ctx, reports.CreateOptions{
+		Product:   reports.ProductSMS,
+		Direction: reports.DirectionOutbound,
+		DateStart: start,
+		DateEnd:   end,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+
+	report, err = client.Wait(ctx, report.RequestID, time.Minute)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if !report.Status.HasFile() {
+		fmt.Println("report", report.Status)
+		return
+	}
+
+	// Safe to retry: a partial file is resumed
+	if err := client.DownloadFile(ctx, report, "sms-usage.zip"); err != nil {
+		fmt.Println(err)
+	}
+}
@@ -0,0 +1,126 @@
+package reports
+
+import "time"
+
+// Product is the Vonage product a report covers
+type Product string
+
+const (
+	ProductSMS           Product = "SMS"
+	ProductVoiceCall     Product = "VOICE-CALL"
+	ProductVoiceFailed   Product = "VOICE-FAILED"
+	ProductVerify        Product = "VERIFY-API"
+	ProductVerifyV2      Product = "VERIFY-V2"
+	ProductMessages      Product = "MESSAGES"
+	ProductConversations Product = "CONVERSATIONS"
+	ProductNumberInsight Product = "NUMBER-INSIGHT"
+	ProductASR           Product = "ASR"
+	ProductAMD           Product = "AMD"
+	ProductWebSocketCall Product = "WEBSOCKET-CALL"
+	ProductInAppVoice    Product = "IN-APP-VOICE"
+)
+
+// Direction is the traffic direction a report covers
+type Direction string
+
+const (
+	DirectionInbound  Direction = "inbound"
+	DirectionOutbound Direction = "outbound"
+)
+
+// Status is the status of a report job
+type Status string
+
+const (
+	StatusPending    Status = "PENDING"
+	StatusProcessing Status = "PROCESSING"
+	StatusSuccess    Status = "SUCCESS"
+	StatusAborted    Status = "ABORTED"
+	StatusFailed     Status = "FAILED"
+	StatusTruncated  Status = "TRUNCATED"
+)
+
+// IsTerminal returns true once the job will not change any more
+func (s Status) IsTerminal() bool {
+	switch s {
+	case StatusSuccess, StatusAborted, StatusFailed, StatusTruncated:
+		return true
+	}
+	return false
+}
+
+// HasFile returns true if the job produced a downloadable file. Truncated
+// reports hit the record limit but still have a file.
+func (s Status) HasFile() bool {
+	return s == StatusSuccess || s == StatusTruncated
+}
+
+// CreateOptions describes a report job. AccountID defaults to the
+// client's API key.
+type CreateOptions struct {
+	AccountID string
+	Product   Product
+	Direction Direction
+	DateStart time.Time
+	DateEnd   time.Time
+
+	IncludeSubaccounts bool
+	// IncludeMessage adds message bodies (SMS and Messages only)
+	IncludeMessage bool
+	// CallbackURL is notified when the job completes
+	CallbackURL string
+}
+
+// createRequest is the body of the create endpoint
+type createRequest struct {
+	AccountID          string    `json:"account_id"`
+	Product            Product   `json:"product"`
+	Direction          Direction `json:"direction,omitempty"`
+	DateStart          string    `json:"date_start,omitempty"`
+	DateEnd            string    `json:"date_end,omitempty"`
+	IncludeSubaccounts bool      `json:"include_subaccounts,omitempty"`
+	IncludeMessage     bool      `json:"include_message,omitempty"`
+	CallbackURL        string    `json:"callback_url,omitempty"`
+}
+
+// Report is a report job and, once complete, the location of its file
+type Report struct {
+	RequestID   string
+	Status      Status
+	Product     Product
+	AccountID   string
+	ItemsCount  int
+	ReceiveTime time.Time
+	// DownloadURL is set once Status.HasFile
+	DownloadURL string
+}
+
+// reportResponse is the HAL body of a report job
+type reportResponse struct {
+	RequestID     string    `json:"request_id"`
+	RequestStatus Status    `json:"request_status"`
+	Product       Product   `json:"product"`
+	AccountID     string    `json:"account_id"`
+	ItemsCount    int       `json:"items_count"`
+	ReceiveTime   time.Time `json:"receive_time"`
+	Links         struct {
+		DownloadReport *struct {
+			Href string `json:"href"`
+		} `json:"download_report"`
+	} `json:"_links"`
+}
+
+func (r *reportResponse) report() *Report {
+	report := &Report{
+		RequestID:   r.RequestID,
+		Status:      r.RequestStatus,
+		Product:     r.Product,
+		AccountID:   r.AccountID,
+		ItemsCount:  r.ItemsCount,
+		ReceiveTime: r.ReceiveTime,
+	}
+	if r.Links.DownloadReport != nil {
+		report.DownloadURL = r.Links.DownloadReport.Href
+	}
+	return report
+}
@@ -0,0 +1,158 @@
+package vonage
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ========================================
+// Retry
+// ========================================
+
+// RetryPolicy retries requests that failed in a way that is safe to
+// repeat: 429 responses and connections that could not be made for any
+// method, and other transport errors and 5xx responses for idempotent
+// methods. Install it with WithRetry, or on every sub-client of a root
+// client with WithRetryPolicy.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first
+	// (default 3)
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled on each retry
+	// (default 500ms). A Retry-After header takes precedence.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between attempts (default 10s)
+	MaxBackoff time.Duration
+	// RetryNonIdempotent also retries POST and PATCH requests on 5xx
+	// responses and transport errors, at the risk of repeating a request
+	// the API processed, e.g. placing a call or sending a message twice
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a policy of 3 attempts starting at 500ms
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, Backoff: 500 * time.Millisecond, MaxBackoff: 10 * time.Second}
+}
+
+// WithRetry installs the retry policy as transport middleware
+func WithRetry(p RetryPolicy) TransportOption {
+	return WithMiddleware(p.Middleware())
+}
+
+// Middleware returns the policy as transport middleware
+func (p RetryPolicy) Middleware() Middleware {
+	def := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = def.MaxAttempts
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = def.Backoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = def.MaxBackoff
+	}
+
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			backoff := p.Backoff
+			for attempt := 1; ; attempt++ {
+				resp, err := next(req)
+				if attempt >= p.MaxAttempts || !p.retryable(req, resp, err) {
+					return resp, err
+				}
+
+				// Rewind the body; requests that cannot be replayed are
+				// returned as they are
+				if req.Body != nil && req.Body != http.NoBody {
+					if req.GetBody == nil {
+						return resp, err
+					}
+					body, bodyErr := req.GetBody()
+					if bodyErr != nil {
+						return resp, err
+					}
+					req.Body = body
+				}
+
+				delay := backoff
+				if resp != nil {
+					if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+						delay = d
+					}
+					io.Copy(io.Discard, io.LimitReader(resp.Body, bodySnippetBytes))
+					resp.Body.Close()
+				}
+				if delay > p.MaxBackoff {
+					delay = p.MaxBackoff
+				}
+
+				t := time.NewTimer(delay)
+				select {
+				case <-t.C:
+				case <-req.Context().Done():
+					t.Stop()
+					return nil, req.Context().Err()
+				}
+				backoff *= 2
+			}
+		}
+	}
+}
+
+// retryable reports whether a failed attempt may be repeated
+func (p RetryPolicy) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false
+		}
+		// A request may have reached the API before a read timeout or
+		// reset, so only a failed dial is safe to repeat for any method
+		return p.RetryNonIdempotent || isIdempotent(req.Method) || notSent(err)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return p.RetryNonIdempotent || isIdempotent(req.Method)
+	}
+	return false
+}
+
+// notSent reports whether err shows the request never left the client,
+// because no connection could be made
+func notSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isIdempotent reports whether repeating a request with method has the
+// same effect as sending it once
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header in seconds or as an HTTP date
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
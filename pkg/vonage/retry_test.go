@@ -0,0 +1,123 @@
+package vonage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// retryTransport returns a transport to baseURL with a fast retry policy
+func retryTransport(baseURL string, p RetryPolicy) *Transport {
+	p.MaxAttempts = 3
+	p.Backoff = time.Millisecond
+	return NewTransport(baseURL, nil, WithRetry(p))
+}
+
+// resetServer drops every connection after reading the request, as if the
+// response was lost after the API processed it
+func resetServer(attempts *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(attempts, 1)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+}
+
+func TestRetryPolicyTransportErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		policy RetryPolicy
+		want   int32
+	}{
+		{"POST is not repeated", http.MethodPost, RetryPolicy{}, 1},
+		{"POST with RetryNonIdempotent", http.MethodPost, RetryPolicy{RetryNonIdempotent: true}, 3},
+		{"GET is repeated", http.MethodGet, RetryPolicy{}, 3},
+		{"DELETE is repeated", http.MethodDelete, RetryPolicy{}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			srv := resetServer(&attempts)
+			defer srv.Close()
+
+			var body interface{}
+			if tt.method == http.MethodPost {
+				body = map[string]string{"to": "447700900000"}
+			}
+			err := retryTransport(srv.URL, tt.policy).Do(context.Background(), tt.method, "/v1/calls", body, nil)
+			if err == nil {
+				t.Fatal("Do() = nil, want a transport error")
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.want {
+				t.Errorf("attempts = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDialError(t *testing.T) {
+	// A closed server refuses connections, so the POST never left
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	var attempts int32
+	count := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&attempts, 1)
+			return next(req)
+		}
+	}
+	p := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	tr := NewTransport(url, nil, WithRetry(p), WithMiddleware(count))
+
+	err := tr.Do(context.Background(), http.MethodPost, "/v1/messages", map[string]string{"to": "447700900000"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "connect") {
+		t.Fatalf("Do() = %v, want a connection error", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestRetryPolicyStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		status int
+		want   int32
+	}{
+		{"429 POST", http.MethodPost, http.StatusTooManyRequests, 2},
+		{"503 POST", http.MethodPost, http.StatusServiceUnavailable, 1},
+		{"503 GET", http.MethodGet, http.StatusServiceUnavailable, 2},
+		{"400 GET", http.MethodGet, http.StatusBadRequest, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) == 1 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.Write([]byte(`{}`))
+			}))
+			defer srv.Close()
+
+			retryTransport(srv.URL, RetryPolicy{}).Do(context.Background(), tt.method, "/v1/calls", nil, nil)
+			if got := atomic.LoadInt32(&attempts); got != tt.want {
+				t.Errorf("attempts = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package rtc_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/rtc"
+)
+
+func ExampleWebhookHandler() {
+	webhooks := rtc.NewWebhookHandler().
+		OnMemberJoined(func(e *rtc.Event) error {
+			fmt.Println("joined:", e.Member.User.Name, "via", e.Member.Channel.Type)
+			return nil
+		}).
+		OnMemberLeft(func(e *rtc.Event) error {
+			fmt.Println("left:", e.Member.User.Name)
+			return nil
+		}).
+		OnAudio(func(e *rtc.Event) error {
+			if e.Type == rtc.EventAudioDTMF {
+				fmt.Println("pressed:", e.Audio.Digit)
+			}
+			return nil
+		})
+
+	router := vonage.NewWebhookRouter().RTC(webhooks.Handle())
+
+	for _, body := range []string{
+		`{"type":"member:joined","id":1,"conversation_id":"CON-1","from":"MEM-1","timestamp":"2026-01-02T03:04:05.000Z",
+		  "body":{"member_id":"MEM-1","user":{"id":"USR-1","name":"alice"},"channel":{"type":"app"}}}`,
+		`{"type":"audio:dtmf","id":2,"conversation_id":"CON-1","from":"MEM-1","timestamp":"2026-01-02T03:04:09.000Z",
+		  "body":{"digit":"5","duration":250}}`,
+		`{"type":"member:left","id":3,"conversation_id":"CON-1","from":"MEM-1","timestamp":"2026-01-02T03:05:00.000Z",
+		  "body":{"member_id":"MEM-1","user":{"id":"USR-1","name":"alice"},"reason":{"code":"hangup"}}}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, router.Paths().RTC, strings.NewReader(body))
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	// Output:
+	// joined: alice via app
+	// pressed: 5
+	// left: alice
+}
@@ -0,0 +1,138 @@
+package rtc
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// ========================================
+// RTC Events
+// ========================================
+
+// EventType is the type of an RTC event
+type EventType string
+
+// Member events
+const (
+	EventMemberInvited EventType = "member:invited"
+	EventMemberJoined  EventType = "member:joined"
+	EventMemberLeft    EventType = "member:left"
+	EventMemberMedia   EventType = "member:media"
+)
+
+// Audio events
+const (
+	EventAudioDTMF        EventType = "audio:dtmf"
+	EventAudioPlay        EventType = "audio:play"
+	EventAudioPlayDone    EventType = "audio:play:done"
+	EventAudioSay         EventType = "audio:say"
+	EventAudioSayDone     EventType = "audio:say:done"
+	EventAudioRecord      EventType = "audio:record"
+	EventAudioRecordDone  EventType = "audio:record:done"
+	EventAudioSpeakingOn  EventType = "audio:speaking:on"
+	EventAudioSpeakingOff EventType = "audio:speaking:off"
+	EventAudioMuteOn      EventType = "audio:mute:on"
+	EventAudioMuteOff     EventType = "audio:mute:off"
+	EventAudioEarmuffOn   EventType = "audio:earmuff:on"
+	EventAudioEarmuffOff  EventType = "audio:earmuff:off"
+)
+
+// IsMember reports whether t is a member event
+func (t EventType) IsMember() bool {
+	return strings.HasPrefix(string(t), "member:")
+}
+
+// IsAudio reports whether t is an audio event
+func (t EventType) IsAudio() bool {
+	return strings.HasPrefix(string(t), "audio:")
+}
+
+// Event is an RTC event webhook, sent to the RTC event URL of an
+// application with RTC capability, e.g. when a Client SDK user joins an
+// in-app voice call
+type Event struct {
+	Type           EventType `json:"type"`
+	ID             int       `json:"id"`
+	ApplicationID  string    `json:"application_id,omitempty"`
+	ConversationID string    `json:"conversation_id"`
+	// From is the ID of the member the event is from
+	From      string    `json:"from,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	// Body is the raw event body; Member or Audio holds it parsed
+	Body json.RawMessage `json:"body,omitempty"`
+
+	// Member is set for member events
+	Member *MemberBody `json:"-"`
+	// Audio is set for audio events
+	Audio *AudioBody `json:"-"`
+}
+
+// MemberBody is the body of a member event
+type MemberBody struct {
+	MemberID  string            `json:"member_id,omitempty"`
+	User      User              `json:"user"`
+	State     string            `json:"state,omitempty"`
+	Channel   *Channel          `json:"channel,omitempty"`
+	Media     *MediaSettings    `json:"media,omitempty"`
+	Timestamp MemberTimestamps  `json:"timestamp"`
+	Reason    *MemberLeftReason `json:"reason,omitempty"`
+}
+
+// User is the user of a member
+type User struct {
+	ID          string `json:"id"`
+	Name        string `json:"name,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+// Channel is how a member is connected, e.g. "app" for the Client SDK or
+// "phone"
+type Channel struct {
+	Type  string `json:"type"`
+	ID    string `json:"id,omitempty"`
+	LegID string `json:"leg_id,omitempty"`
+}
+
+// MediaSettings is the media state of a member
+type MediaSettings struct {
+	AudioSettings *AudioSettings `json:"audio_settings,omitempty"`
+	Audio         bool           `json:"audio,omitempty"`
+}
+
+// AudioSettings is the audio state of a member
+type AudioSettings struct {
+	Enabled   bool `json:"enabled"`
+	Muted     bool `json:"muted"`
+	Earmuffed bool `json:"earmuffed"`
+}
+
+// MemberTimestamps are the state change times of a member
+type MemberTimestamps struct {
+	Invited *time.Time `json:"invited,omitempty"`
+	Joined  *time.Time `json:"joined,omitempty"`
+	Left    *time.Time `json:"left,omitempty"`
+}
+
+// MemberLeftReason is why a member left
+type MemberLeftReason struct {
+	Code string `json:"code,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+// AudioBody is the body of an audio event. Fields are set by the event
+// types they apply to.
+type AudioBody struct {
+	Channel *Channel `json:"channel,omitempty"`
+	// Digit is the DTMF digit of audio:dtmf
+	Digit string `json:"digit,omitempty"`
+	// Duration is the DTMF tone length in milliseconds
+	Duration int `json:"duration,omitempty"`
+	// PlayID and SayID identify the audio of play and say events
+	PlayID string `json:"play_id,omitempty"`
+	SayID  string `json:"say_id,omitempty"`
+	// RecordingID, DestinationURL and Format are set on audio:record:done
+	RecordingID    string `json:"recording_id,omitempty"`
+	DestinationURL string `json:"destination_url,omitempty"`
+	Format         string `json:"format,omitempty"`
+}
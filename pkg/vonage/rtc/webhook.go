@@ -0,0 +1,199 @@
+// Package rtc parses and handles the RTC event webhooks Vonage sends to
+// applications with RTC capability, such as members joining and leaving
+// a Client SDK in-app voice call and audio events within it.
+package rtc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// RTC Event Webhooks
+// ========================================
+
+// EventHandler handles an RTC event
+type EventHandler func(event *Event) error
+
+// WebhookHandler provides an HTTP handler for the RTC event webhook
+type WebhookHandler struct {
+	onMemberJoined EventHandler
+	onMemberLeft   EventHandler
+	onAudio        EventHandler
+	onEvent        EventHandler
+
+	logger vonage.Logger
+
+	verifier *vonage.WebhookVerifier
+	sink     vonage.EventSink
+	metrics  vonage.WebhookMetricsHook
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler() *WebhookHandler {
+	return &WebhookHandler{logger: vonage.NopLogger()}
+}
+
+// WithLogger sets the logger for webhook errors (default: no logging)
+func (h *WebhookHandler) WithLogger(l vonage.Logger) *WebhookHandler {
+	h.logger = l
+	return h
+}
+
+// OnMemberJoined sets the handler for members joining a conversation
+func (h *WebhookHandler) OnMemberJoined(handler EventHandler) *WebhookHandler {
+	h.onMemberJoined = handler
+	return h
+}
+
+// OnMemberLeft sets the handler for members leaving a conversation
+func (h *WebhookHandler) OnMemberLeft(handler EventHandler) *WebhookHandler {
+	h.onMemberLeft = handler
+	return h
+}
+
+// OnAudio sets the handler for audio events, such as DTMF and speaking
+// detection
+func (h *WebhookHandler) OnAudio(handler EventHandler) *WebhookHandler {
+	h.onAudio = handler
+	return h
+}
+
+// OnEvent sets the handler for events without a more specific handler
+func (h *WebhookHandler) OnEvent(handler EventHandler) *WebhookHandler {
+	h.onEvent = handler
+	return h
+}
+
+// WithSignatureVerification rejects webhooks whose signature does not
+// match the credentials' signature secret
+func (h *WebhookHandler) WithSignatureVerification(creds *vonage.Credentials) *WebhookHandler {
+	return h.WithVerifier(vonage.NewWebhookVerifier(creds))
+}
+
+// WithVerifier is like WithSignatureVerification with a configured
+// verifier, e.g. one with replay protection
+func (h *WebhookHandler) WithVerifier(v *vonage.WebhookVerifier) *WebhookHandler {
+	h.verifier = v
+	return h
+}
+
+// WithEventSink publishes verified events to sink before the registered
+// handlers run, as "rtc.<type>" events (e.g. "rtc.member:joined") keyed by
+// conversation ID with the parsed *Event as Payload
+func (h *WebhookHandler) WithEventSink(sink vonage.EventSink) *WebhookHandler {
+	h.sink = sink
+	return h
+}
+
+// WithMetrics reports every verified event to hook, e.g. a
+// vonage.WebhookCollector, as "rtc.<type>". Bodies that fail to parse are
+// reported as unknown.
+func (h *WebhookHandler) WithMetrics(hook vonage.WebhookMetricsHook) *WebhookHandler {
+	h.metrics = hook
+	return h
+}
+
+// Handle returns an http.HandlerFunc for the RTC event URL
+func (h *WebhookHandler) Handle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			h.logger.Error("Failed to read RTC webhook body", "error", err)
+			w.WriteHeader(http.StatusOK) // Always 200 for webhooks
+			return
+		}
+		defer r.Body.Close()
+
+		if h.verifier.Enabled() {
+			if err := h.verifier.Verify(r.Header.Get("Authorization"), body); err != nil {
+				h.logger.Warn("Rejected RTC webhook", "error", err)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+
+		h.process(r.Context(), body)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// process dispatches an event body to the registered handler
+func (h *WebhookHandler) process(ctx context.Context, body []byte) {
+	event, err := ParseEvent(body)
+	if err != nil {
+		h.logger.Warn("Unknown RTC webhook format", "error", err, "body", string(body))
+		vonage.ObserveWebhook(ctx, h.metrics, vonage.WebhookObservation{Source: vonage.EventSourceRTC, Unknown: true})
+		return
+	}
+	typ := "rtc." + string(event.Type)
+
+	if h.sink != nil {
+		e := vonage.NewEvent(vonage.EventSourceRTC, typ, body)
+		e.Key, e.Payload = event.ConversationID, event
+		vonage.PublishEvent(ctx, h.sink, h.logger, e)
+	}
+
+	handler := h.onEvent
+	switch {
+	case event.Type == EventMemberJoined && h.onMemberJoined != nil:
+		handler = h.onMemberJoined
+	case event.Type == EventMemberLeft && h.onMemberLeft != nil:
+		handler = h.onMemberLeft
+	case event.Type.IsAudio() && h.onAudio != nil:
+		handler = h.onAudio
+	}
+
+	start := time.Now()
+	if handler != nil {
+		err = handler(event)
+		if err != nil {
+			h.logger.Error("Error handling RTC event",
+				"error", err,
+				"conversationID", event.ConversationID,
+				"type", string(event.Type),
+			)
+		}
+	}
+	vonage.ObserveWebhook(ctx, h.metrics, vonage.WebhookObservation{
+		Source:  vonage.EventSourceRTC,
+		Type:    typ,
+		Latency: time.Since(start),
+		Err:     err,
+	})
+}
+
+// ParseEvent parses an RTC event webhook body, with Member or Audio set
+// for member and audio events
+func ParseEvent(body []byte) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("rtc: failed to parse event: %w", err)
+	}
+	if event.Type == "" {
+		return nil, fmt.Errorf("rtc: event has no type")
+	}
+	if len(event.Body) == 0 {
+		return &event, nil
+	}
+
+	switch {
+	case event.Type.IsMember():
+		event.Member = &MemberBody{}
+		if err := json.Unmarshal(event.Body, event.Member); err != nil {
+			return nil, fmt.Errorf("rtc: failed to parse %s body: %w", event.Type, err)
+		}
+	case event.Type.IsAudio():
+		event.Audio = &AudioBody{}
+		if err := json.Unmarshal(event.Body, event.Audio); err != nil {
+			return nil, fmt.Errorf("rtc: failed to parse %s body: %w", event.Type, err)
+		}
+	}
+	return &event, nil
+}
@@ -0,0 +1,290 @@
+// Package sdk provides a single entry point that constructs every Vonage
+// sub-client from one set of credentials. It lives outside the vonage
+// package because the sub-clients import vonage.
+package sdk
+
+import (
+	"context"
+	"sync"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/account"
+	"github.com/vonatrigger/poc/pkg/vonage/externalaccounts"
+	"github.com/vonatrigger/poc/pkg/vonage/media"
+	"github.com/vonatrigger/poc/pkg/vonage/messages"
+	"github.com/vonatrigger/poc/pkg/vonage/network"
+	"github.com/vonatrigger/poc/pkg/vonage/numbers"
+	"github.com/vonatrigger/poc/pkg/vonage/proactive"
+	"github.com/vonatrigger/poc/pkg/vonage/reports"
+	"github.com/vonatrigger/poc/pkg/vonage/users"
+	"github.com/vonatrigger/poc/pkg/vonage/verify"
+	"github.com/vonatrigger/poc/pkg/vonage/video"
+	"github.com/vonatrigger/poc/pkg/vonage/voice"
+	"github.com/vonatrigger/poc/pkg/vonage/whatsapp"
+)
+
+// Client is the unified Vonage client. Sub-clients are created on first use
+// and share the credentials, endpoints and vonage.Settings (HTTP client,
+// timeout, logger, middleware, interceptors and retry policy) of the
+// embedded *vonage.Client; voice and messages also share one transport
+// unless the Messages endpoint differs.
+type Client struct {
+	*vonage.Client
+
+	rest     *vonage.Transport
+	messages *vonage.Transport
+	video    *vonage.Transport
+
+	mu              sync.Mutex
+	voiceClient     *voice.Client
+	messagesClient  *messages.Client
+	videoClient     *video.Client
+	verifyClient    *verify.Client
+	numbersClient   *numbers.Client
+	accountClient   *account.Client
+	usersClient     *users.Client
+	reportsClient   *reports.Client
+	networkClient   *network.Client
+	proactiveClient *proactive.Client
+	externalClient  *externalaccounts.Client
+	mediaClient     *media.Client
+	whatsappClient  *whatsapp.Client
+}
+
+// NewClient creates a unified client
+func NewClient(credentials *vonage.Credentials, opts ...vonage.ClientOption) *Client {
+	core := vonage.NewClient(credentials, opts...)
+	endpoints := core.Endpoints()
+	c := &Client{
+		Client: core,
+		rest:   core.NewTransport(endpoints.REST),
+		video:  core.NewTransport(endpoints.Video),
+	}
+	c.messages = c.rest
+	if endpoints.Messages != endpoints.REST {
+		c.messages = core.NewTransport(endpoints.Messages)
+	}
+	return c
+}
+
+// Voice returns the Voice API client
+func (c *Client) Voice() *voice.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.voiceClient == nil {
+		c.voiceClient = voice.NewClient(c.JWTGenerator(),
+			voice.WithTransport(c.rest),
+			voice.WithPhoneNumber(c.Credentials().PhoneNumber),
+			voice.WithLogger(c.Logger()),
+			voice.WithMode(c.Credentials().Mode),
+		)
+	}
+	return c.voiceClient
+}
+
+// Messages returns the Messages API client
+func (c *Client) Messages() *messages.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.messagesClient == nil {
+		c.messagesClient = messages.NewClient(c.JWTGenerator(),
+			messages.WithTransport(c.messages),
+			messages.WithPhoneNumber(c.Credentials().PhoneNumber),
+			messages.WithLogger(c.Logger()),
+			messages.WithMode(c.Credentials().Mode),
+		)
+	}
+	return c.messagesClient
+}
+
+// Video returns the Video API client
+func (c *Client) Video() *video.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.videoClient == nil {
+		c.videoClient = video.NewClient(c.Credentials().AppID, c.JWTGenerator(),
+			video.WithTransport(c.video),
+			video.WithLogger(c.Logger()),
+			video.WithMode(c.Credentials().Mode),
+		)
+	}
+	return c.videoClient
+}
+
+// Verify returns the Verify API client
+func (c *Client) Verify() *verify.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.verifyClient == nil {
+		creds := c.Credentials()
+		c.verifyClient = verify.NewClient(creds.APIKey, creds.APISecret, c.JWTGenerator(),
+			verify.WithSettings(c.Settings()),
+			verify.WithBaseURL(c.Endpoints().REST),
+			verify.WithCredentialsProvider(c.CredentialsProvider()),
+		)
+	}
+	return c.verifyClient
+}
+
+// Numbers returns the Numbers API client. It requires an API key and
+// secret in the credentials.
+func (c *Client) Numbers() *numbers.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.numbersClient == nil {
+		creds := c.Credentials()
+		c.numbersClient = numbers.NewClient(creds.APIKey, creds.APISecret,
+			numbers.WithSettings(c.Settings()),
+			numbers.WithBaseURL(c.Endpoints().Legacy),
+			numbers.WithCredentialsProvider(c.CredentialsProvider()),
+		)
+	}
+	return c.numbersClient
+}
+
+// Account returns the Account API client. It requires an API key and
+// secret in the credentials.
+func (c *Client) Account() *account.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.accountClient == nil {
+		creds := c.Credentials()
+		c.accountClient = account.NewClient(creds.APIKey, creds.APISecret,
+			account.WithSettings(c.Settings()),
+			account.WithBaseURL(c.Endpoints().Legacy),
+			account.WithAPIBaseURL(c.Endpoints().REST),
+			account.WithCredentialsProvider(c.CredentialsProvider()),
+		)
+	}
+	return c.accountClient
+}
+
+// Users returns the Users API client
+func (c *Client) Users() *users.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.usersClient == nil {
+		c.usersClient = users.NewClient(c.JWTGenerator(),
+			users.WithTransport(c.rest),
+			users.WithLogger(c.Logger()),
+		)
+	}
+	return c.usersClient
+}
+
+// Reports returns the Reports API client. It requires an API key and
+// secret in the credentials.
+func (c *Client) Reports() *reports.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.reportsClient == nil {
+		creds := c.Credentials()
+		c.reportsClient = reports.NewClient(creds.APIKey, creds.APISecret,
+			reports.WithSettings(c.Settings()),
+			reports.WithBaseURL(c.Endpoints().REST),
+			reports.WithCredentialsProvider(c.CredentialsProvider()),
+		)
+	}
+	return c.reportsClient
+}
+
+// Network returns the Network API client (SIM Swap, Number Verification)
+func (c *Client) Network() *network.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.networkClient == nil {
+		c.networkClient = network.NewClient(c.Credentials().AppID, c.JWTGenerator(),
+			network.WithSettings(c.Settings()),
+			network.WithBaseURL(c.Endpoints().Regional),
+			network.WithAuthBaseURL(c.Endpoints().NetworkAuth),
+		)
+	}
+	return c.networkClient
+}
+
+// Proactive returns the Proactive Connect API client
+func (c *Client) Proactive() *proactive.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.proactiveClient == nil {
+		c.proactiveClient = proactive.NewClient(c.JWTGenerator(),
+			proactive.WithSettings(c.Settings()),
+			proactive.WithBaseURL(c.Endpoints().Regional),
+		)
+	}
+	return c.proactiveClient
+}
+
+// ExternalAccounts returns the External Accounts API client. It requires
+// an API key and secret in the credentials.
+func (c *Client) ExternalAccounts() *externalaccounts.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.externalClient == nil {
+		creds := c.Credentials()
+		c.externalClient = externalaccounts.NewClient(creds.APIKey, creds.APISecret,
+			externalaccounts.WithSettings(c.Settings()),
+			externalaccounts.WithBaseURL(c.Endpoints().REST),
+			externalaccounts.WithCredentialsProvider(c.CredentialsProvider()),
+		)
+	}
+	return c.externalClient
+}
+
+// Media returns the Media API client
+func (c *Client) Media() *media.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.mediaClient == nil {
+		c.mediaClient = media.NewClient(c.JWTGenerator(),
+			media.WithTransport(c.rest),
+			media.WithLogger(c.Logger()),
+		)
+	}
+	return c.mediaClient
+}
+
+// WhatsApp returns the WhatsApp template management client. It requires
+// an API key and secret in the credentials.
+func (c *Client) WhatsApp() *whatsapp.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.whatsappClient == nil {
+		creds := c.Credentials()
+		c.whatsappClient = whatsapp.NewClient(creds.APIKey, creds.APISecret,
+			whatsapp.WithSettings(c.Settings()),
+			whatsapp.WithBaseURL(c.Endpoints().REST),
+			whatsapp.WithCredentialsProvider(c.CredentialsProvider()),
+		)
+	}
+	return c.whatsappClient
+}
+
+// Ping probes, concurrently, the APIs the credentials give access to:
+// voice, messages, video and users with an application, account and
+// numbers with an API key and secret. Serve it with vonage.HealthHandler
+// for readiness probes.
+func (c *Client) Ping(ctx context.Context) []*vonage.Health {
+	creds := c.Credentials()
+	var pingers []vonage.Pinger
+	if creds.HasApplication() {
+		pingers = append(pingers, c.Voice(), c.Messages(), c.Video(), c.Users())
+	}
+	if creds.HasAPIKey() {
+		pingers = append(pingers, c.Account(), c.Numbers())
+	}
+	return vonage.PingAll(ctx, pingers...)
+}
@@ -0,0 +1,106 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// secretServer answers every request with an empty JSON object and records
+// the API secret it was authenticated with, from the query or Basic auth
+type secretServer struct {
+	mu      sync.Mutex
+	secrets []string
+}
+
+func (s *secretServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	secret := r.URL.Query().Get("api_secret")
+	if _, pass, ok := r.BasicAuth(); ok {
+		secret = pass
+	}
+	s.mu.Lock()
+	s.secrets = append(s.secrets, secret)
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}"))
+}
+
+func (s *secretServer) last() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.secrets) == 0 {
+		return ""
+	}
+	return s.secrets[len(s.secrets)-1]
+}
+
+func TestKeyClientsUseCredentialsProvider(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(ctx context.Context, c *Client) error
+	}{
+		{"numbers", func(ctx context.Context, c *Client) error {
+			_, err := c.Numbers().List(ctx, nil)
+			return err
+		}},
+		{"account", func(ctx context.Context, c *Client) error {
+			_, err := c.Account().GetBalance(ctx)
+			return err
+		}},
+		{"account secrets", func(ctx context.Context, c *Client) error {
+			_, err := c.Account().ListSecrets(ctx)
+			return err
+		}},
+		{"verify v1", func(ctx context.Context, c *Client) error {
+			_, err := c.Verify().CheckV1(ctx, "req-1", "1234")
+			return err
+		}},
+		{"reports", func(ctx context.Context, c *Client) error {
+			_, err := c.Reports().Get(ctx, "req-1")
+			return err
+		}},
+		{"externalaccounts", func(ctx context.Context, c *Client) error {
+			_, err := c.ExternalAccounts().Get(ctx, "ext-1")
+			return err
+		}},
+		{"whatsapp", func(ctx context.Context, c *Client) error {
+			_, err := c.WhatsApp().ListTemplates(ctx, "waba-1", nil)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &secretServer{}
+			ts := httptest.NewServer(srv)
+			defer ts.Close()
+
+			creds := &vonage.Credentials{APIKey: "key", APISecret: "old-secret"}
+			rotating := vonage.NewRotatingCredentials(creds)
+			c := NewClient(creds,
+				vonage.WithCredentialsProvider(rotating),
+				vonage.WithEndpoints(vonage.Endpoints{REST: ts.URL, Legacy: ts.URL}),
+			)
+			ctx := context.Background()
+
+			if err := tt.call(ctx, c); err != nil {
+				t.Fatal(err)
+			}
+			if got := srv.last(); got != "old-secret" {
+				t.Errorf("secret = %q, want old-secret", got)
+			}
+
+			rotating.Set(&vonage.Credentials{APIKey: "key", APISecret: "new-secret"})
+			if err := tt.call(ctx, c); err != nil {
+				t.Fatal(err)
+			}
+			if got := srv.last(); got != "new-secret" {
+				t.Errorf("secret after rotation = %q, want new-secret", got)
+			}
+		})
+	}
+}
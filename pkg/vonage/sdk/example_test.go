@@ -0,0 +1,106 @@
+package sdk_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/sdk"
+)
+
+func ExampleNewClient() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+		vonage.WithAPIKey("api-key", "api-secret"),
+		vonage.WithPhoneNumber("81501234567"),
+	)
+
+	client := sdk.NewClient(creds,
+		vonage.WithTimeout(10*time.Second),
+		vonage.WithLogger(slog.Default()),
+	)
+
+	ctx := context.Background()
+	_, _ = client.Messages().SendSMS(ctx, "81901234567", "Hello")
+	_, _ = client.Voice().CreateCallToPhone(ctx, "81901234567",
+		"https://example.com/answer", "https://example.com/event")
+	_, _ = client.Video().CreateSession(ctx, nil)
+	_, _ = client.Verify().StartVerification(ctx, "81901234567", nil)
+}
+
+func ExampleNewClient_endpoints() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Println(r.Method, r.URL.Path)
+		w.Write([]byte(`{"message_uuid":"MSG-1","value":10.5}`))
+	}))
+	defer srv.Close()
+
+	creds, _ := vonage.NewCredentials(
+		vonage.WithAPIKey("api-key", "api-secret"),
+		vonage.WithPhoneNumber("81501234567"),
+	)
+
+	// Every sub-client inherits the endpoints, here a test server
+	client := sdk.NewClient(creds, vonage.WithEndpoints(vonage.TestEndpoints(srv.URL)))
+
+	ctx := context.Background()
+	client.Messages().SendSMS(ctx, "81901234567", "Hello")
+	client.Account().GetBalance(ctx)
+
+	// Regions and the Messages sandbox only change the affected hosts
+	eu := vonage.RegionalEndpoints(vonage.RegionEU)
+	fmt.Println(eu.REST, eu.Video)
+	fmt.Println(vonage.SandboxEndpoints().Messages)
+	// Output:
+	// POST /v1/messages
+	// GET /account/get-balance
+	// https://api-eu.vonage.com https://video.api.vonage.com
+	// https://messages-sandbox.nexmo.com
+}
+
+func ExampleNewClient_sharedOptions() {
+	// The test server fails the first balance request
+	failed := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Println(r.Method, r.URL.Path, r.Header.Get("X-Request-Source"))
+		if r.URL.Path == "/account/get-balance" && !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"message_uuid":"MSG-1","value":10.5}`))
+	}))
+	defer srv.Close()
+
+	creds, _ := vonage.NewCredentials(
+		vonage.WithAPIKey("api-key", "api-secret"),
+		vonage.WithPhoneNumber("81501234567"),
+	)
+
+	// The timeout, interceptors and retry policy apply to every sub-client
+	client := sdk.NewClient(creds,
+		vonage.WithEndpoints(vonage.TestEndpoints(srv.URL)),
+		vonage.WithTimeout(5*time.Second),
+		vonage.WithTransportRequestInterceptor(func(req *http.Request) error {
+			req.Header.Set("X-Request-Source", "checkin")
+			return nil
+		}),
+		vonage.WithRetryPolicy(vonage.RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond}),
+	)
+
+	ctx := context.Background()
+	client.Messages().SendSMS(ctx, "81901234567", "Hello")
+	balance, err := client.Account().GetBalance(ctx)
+	fmt.Println(balance.Value, err)
+	fmt.Println(client.HTTPClient().Timeout)
+	// Output:
+	// POST /v1/messages checkin
+	// GET /account/get-balance checkin
+	// GET /account/get-balance checkin
+	// 10.5 <nil>
+	// 5s
+}
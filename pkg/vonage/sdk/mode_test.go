@@ -0,0 +1,110 @@
+package sdk
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/proactive"
+	"github.com/vonatrigger/poc/pkg/vonage/reports"
+)
+
+func TestSubClientsHonorMode(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each call changes state, so dry runs must not send it either
+	calls := []struct {
+		name string
+		call func(ctx context.Context, c *Client) error
+	}{
+		{"verify", func(ctx context.Context, c *Client) error {
+			_, err := c.Verify().StartV1(ctx, "447700900000", nil)
+			return err
+		}},
+		{"numbers", func(ctx context.Context, c *Client) error {
+			return c.Numbers().Buy(ctx, "GB", "447700900000")
+		}},
+		{"account", func(ctx context.Context, c *Client) error {
+			return c.Account().TopUp(ctx, "txn-1")
+		}},
+		{"account secrets", func(ctx context.Context, c *Client) error {
+			_, err := c.Account().CreateSecret(ctx, "Secret-1234")
+			return err
+		}},
+		{"reports", func(ctx context.Context, c *Client) error {
+			_, err := c.Reports().Create(ctx, reports.CreateOptions{
+				Product:   reports.ProductSMS,
+				Direction: reports.DirectionOutbound,
+				DateStart: time.Now().Add(-time.Hour),
+			})
+			return err
+		}},
+		{"externalaccounts", func(ctx context.Context, c *Client) error {
+			return c.ExternalAccounts().Link(ctx, "ext-1", "app-1")
+		}},
+		{"whatsapp", func(ctx context.Context, c *Client) error {
+			return c.WhatsApp().DeleteTemplate(ctx, "waba-1", "welcome")
+		}},
+		{"network", func(ctx context.Context, c *Client) error {
+			_, err := c.Network().CheckSimSwap(ctx, "447700900000", 24*time.Hour)
+			return err
+		}},
+		{"proactive", func(ctx context.Context, c *Client) error {
+			_, err := c.Proactive().CreateList(ctx, &proactive.List{Name: "customers"})
+			return err
+		}},
+	}
+
+	modes := []struct {
+		mode vonage.Mode
+		sent bool
+	}{
+		{vonage.ModeLive, true},
+		{vonage.ModeDryRun, false},
+		{vonage.ModeMock, false},
+	}
+
+	for _, m := range modes {
+		for _, tt := range calls {
+			t.Run(m.mode.String()+"/"+tt.name, func(t *testing.T) {
+				var requests atomic.Int32
+				ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					requests.Add(1)
+					w.Header().Set("Content-Type", "application/json")
+					w.Write([]byte("{}"))
+				}))
+				defer ts.Close()
+
+				creds := &vonage.Credentials{
+					APIKey:     "key",
+					APISecret:  "secret",
+					AppID:      "app-1",
+					PrivateKey: key,
+					Mode:       m.mode,
+				}
+				c := NewClient(creds, vonage.WithEndpoints(vonage.Endpoints{
+					REST:        ts.URL,
+					Legacy:      ts.URL,
+					Regional:    ts.URL,
+					NetworkAuth: ts.URL,
+				}))
+
+				// Mock answers cannot always be decoded, so only what
+				// reaches the server matters here
+				tt.call(context.Background(), c)
+				if got := requests.Load() > 0; got != m.sent {
+					t.Errorf("server received %d requests in %s mode", requests.Load(), m.mode)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package vonage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ========================================
+// Signatures
+// ========================================
+
+// ErrInvalidSignature is returned when a webhook or signed request fails
+// signature verification
+var ErrInvalidSignature = errors.New("vonage: invalid signature")
+
+// SignatureMethod is the algorithm configured for the account's signature
+// secret in the Vonage dashboard
+type SignatureMethod string
+
+const (
+	// SignatureMD5Hash appends the secret and takes an MD5 digest (legacy)
+	SignatureMD5Hash SignatureMethod = "md5hash"
+	SignatureMD5     SignatureMethod = "md5"
+	SignatureSHA1    SignatureMethod = "sha1"
+	SignatureSHA256  SignatureMethod = "sha256"
+	SignatureSHA512  SignatureMethod = "sha512"
+)
+
+// WithSignatureSecret sets the account signature secret and method used to
+// verify signed webhooks and sign legacy SMS requests
+func WithSignatureSecret(secret string, method SignatureMethod) CredentialsOption {
+	return func(c *Credentials) error {
+		c.SignatureSecret = secret
+		c.SignatureMethod = method
+		return nil
+	}
+}
+
+// HasSignatureSecret returns true if a signature secret is configured
+func (c *Credentials) HasSignatureSecret() bool {
+	return c.SignatureSecret != ""
+}
+
+// SignParams computes the legacy "sig" parameter for params. Any existing
+// "sig" entry is ignored.
+func SignParams(params map[string]string, secret string, method SignatureMethod) (string, error) {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k != "sig" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	clean := strings.NewReplacer("&", "_", "=", "_")
+	for _, k := range keys {
+		b.WriteString("&" + k + "=" + clean.Replace(params[k]))
+	}
+
+	var h hash.Hash
+	switch method {
+	case SignatureMD5Hash, "":
+		b.WriteString(secret)
+		sum := md5.Sum([]byte(b.String()))
+		return hex.EncodeToString(sum[:]), nil
+	case SignatureMD5:
+		h = hmac.New(md5.New, []byte(secret))
+	case SignatureSHA1:
+		h = hmac.New(sha1.New, []byte(secret))
+	case SignatureSHA256:
+		h = hmac.New(sha256.New, []byte(secret))
+	case SignatureSHA512:
+		h = hmac.New(sha512.New, []byte(secret))
+	default:
+		return "", fmt.Errorf("vonage: unknown signature method %q", method)
+	}
+	h.Write([]byte(b.String()))
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifySignedParams checks the "sig" parameter of a legacy signed request
+func VerifySignedParams(params map[string]string, secret string, method SignatureMethod) error {
+	sig, ok := params["sig"]
+	if !ok {
+		return fmt.Errorf("%w: missing sig parameter", ErrInvalidSignature)
+	}
+	expected, err := SignParams(params, secret, method)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(strings.ToLower(sig)), []byte(expected)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyWebhookJWT verifies the Bearer JWT that Vonage sends with signed
+// webhooks (HS256 with the signature secret) and that its payload_hash
+// claim matches body. Tokens without payload_hash are only accepted for an
+// empty body.
+func VerifyWebhookJWT(authorization string, body []byte, secret string) error {
+	_, err := parseWebhookJWT(authorization, body, secret)
+	return err
+}
+
+// VerifyWebhook verifies a webhook signed either way Vonage signs them:
+// the Bearer JWT in authorization if there is one, otherwise the sig field
+// of a legacy signed JSON body
+func VerifyWebhook(authorization string, body []byte, secret string, method SignatureMethod) error {
+	if authorization != "" {
+		return VerifyWebhookJWT(authorization, body, secret)
+	}
+
+	params, err := webhookParams(body)
+	if err != nil {
+		return err
+	}
+	return VerifySignedParams(params, secret, method)
+}
+
+// parseWebhookJWT verifies a webhook JWT and returns its claims
+func parseWebhookJWT(authorization string, body []byte, secret string) (jwt.MapClaims, error) {
+	tokenStr, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing bearer token", ErrInvalidSignature)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenStr, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	want, ok := claims["payload_hash"].(string)
+	if !ok {
+		if len(body) > 0 {
+			return nil, fmt.Errorf("%w: missing payload hash", ErrInvalidSignature)
+		}
+		return claims, nil
+	}
+	sum := sha256.Sum256(body)
+	if subtle.ConstantTimeCompare([]byte(strings.ToLower(want)), []byte(hex.EncodeToString(sum[:]))) != 1 {
+		return nil, fmt.Errorf("%w: payload hash mismatch", ErrInvalidSignature)
+	}
+	return claims, nil
+}
+
+// webhookParams returns the fields of a legacy signed JSON body, which
+// carry a sig field alongside the other parameters
+func webhookParams(body []byte) (map[string]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("%w: unsigned webhook", ErrInvalidSignature)
+	}
+	params := make(map[string]string, len(fields))
+	for k, v := range fields {
+		params[k] = fmt.Sprint(v)
+	}
+	return params, nil
+}
@@ -0,0 +1,481 @@
+package vonage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ========================================
+// Transport
+// ========================================
+
+// Authenticator adds credentials to an outgoing request. *JWTGenerator
+// implements it with a Bearer API JWT.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// RoundTripFunc sends a single HTTP request
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTripFunc to add cross-cutting behaviour such as
+// retries, tracing or metrics. Middleware runs after authentication, so
+// each attempt sees the final request.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// RequestInterceptor inspects or mutates an outgoing request. Returning an
+// error aborts the request.
+type RequestInterceptor func(req *http.Request) error
+
+// ResponseInterceptor inspects or mutates a response before it is decoded.
+// Returning an error fails the request. Interceptors that read the body
+// must replace it.
+type ResponseInterceptor func(resp *http.Response) error
+
+// InterceptRequest adapts a RequestInterceptor to Middleware
+func InterceptRequest(fn RequestInterceptor) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if err := fn(req); err != nil {
+				return nil, err
+			}
+			return next(req)
+		}
+	}
+}
+
+// InterceptResponse adapts a ResponseInterceptor to Middleware
+func InterceptResponse(fn ResponseInterceptor) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			resp, err := next(req)
+			if err != nil {
+				return nil, err
+			}
+			if err := fn(resp); err != nil {
+				resp.Body.Close()
+				return nil, err
+			}
+			return resp, nil
+		}
+	}
+}
+
+// DefaultMaxResponseBytes is the default limit on response body size
+const DefaultMaxResponseBytes = 10 << 20
+
+// bodySnippetBytes is how much of an undecodable body is kept in errors
+const bodySnippetBytes = 512
+
+// ErrResponseTooLarge is returned when a response body exceeds the
+// transport's MaxResponseBytes
+var ErrResponseTooLarge = errors.New("vonage: response body too large")
+
+// ErrUntrustedHost is returned for an absolute URL outside the transport's
+// host and the Vonage API domains, such as a recording URL taken from a
+// forged webhook. Credentials are never sent to such hosts.
+var ErrUntrustedHost = errors.New("vonage: untrusted host")
+
+// vonageDomains are the domains whose HTTPS hosts may receive credentials
+var vonageDomains = []string{"nexmo.com", "vonage.com"}
+
+// DecodeError is returned when a 2xx response body cannot be decoded, for
+// example when a proxy answers with an HTML page
+type DecodeError struct {
+	StatusCode  int
+	ContentType string
+	// Snippet holds the first bytes of the body for diagnosis
+	Snippet string
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("vonage: failed to decode response (status: %d, content-type: %q): %v: %q",
+		e.StatusCode, e.ContentType, e.Err, e.Snippet)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// RawBody is a request body sent as-is with its content type, for
+// endpoints that take neither JSON nor form data (e.g. multipart uploads).
+// Use a *bytes.Buffer, *bytes.Reader or *strings.Reader so middleware can
+// replay it.
+type RawBody struct {
+	ContentType string
+	Body        io.Reader
+}
+
+// Transport builds, authenticates and sends API requests on behalf of the
+// sub-clients, and maps non-2xx responses to *Error
+type Transport struct {
+	baseURL    string
+	auth       Authenticator
+	httpClient *http.Client
+	middleware []Middleware
+	logger     Logger
+	userAgent  string
+	maxBody    int64
+	mode       Mode
+}
+
+// UserAgent returns the default User-Agent sent by the SDK,
+// e.g. "vonage-go-sdk/0.2.0 go/1.22.5"
+func UserAgent() string {
+	return fmt.Sprintf("vonage-go-sdk/%s go/%s", Version, strings.TrimPrefix(runtime.Version(), "go"))
+}
+
+// TransportOption is a functional option for configuring a Transport
+type TransportOption func(*Transport)
+
+// WithTransportHTTPClient sets the HTTP client used to send requests
+func WithTransportHTTPClient(httpClient *http.Client) TransportOption {
+	return func(t *Transport) {
+		t.httpClient = httpClient
+	}
+}
+
+// WithTransportLogger sets the logger for request and error logging
+func WithTransportLogger(l Logger) TransportOption {
+	return func(t *Transport) {
+		t.logger = l
+	}
+}
+
+// WithTransportUserAgentSuffix appends an application identifier such as
+// "checkin-service/1.4" to the User-Agent
+func WithTransportUserAgentSuffix(suffix string) TransportOption {
+	return func(t *Transport) {
+		if suffix != "" {
+			t.userAgent += " " + suffix
+		}
+	}
+}
+
+// WithMaxResponseBytes limits how much of a response body is read
+// (default DefaultMaxResponseBytes). Larger bodies fail with
+// ErrResponseTooLarge; n <= 0 removes the limit.
+func WithMaxResponseBytes(n int64) TransportOption {
+	return func(t *Transport) {
+		t.maxBody = n
+	}
+}
+
+// WithMiddleware appends middleware. The first middleware is outermost.
+func WithMiddleware(mw ...Middleware) TransportOption {
+	return func(t *Transport) {
+		t.middleware = append(t.middleware, mw...)
+	}
+}
+
+// WithRequestInterceptor runs fn on every outgoing request, after
+// authentication
+func WithRequestInterceptor(fn RequestInterceptor) TransportOption {
+	return WithMiddleware(InterceptRequest(fn))
+}
+
+// WithResponseInterceptor runs fn on every response received
+func WithResponseInterceptor(fn ResponseInterceptor) TransportOption {
+	return WithMiddleware(InterceptResponse(fn))
+}
+
+// NewTransport creates a transport for the given base URL. auth may be nil
+// for unauthenticated endpoints.
+func NewTransport(baseURL string, auth Authenticator, opts ...TransportOption) *Transport {
+	t := &Transport{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		auth:       auth,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		logger:     NopLogger(),
+		userAgent:  UserAgent(),
+		maxBody:    DefaultMaxResponseBytes,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// BaseURL returns the base URL requests are sent to
+func (t *Transport) BaseURL() string {
+	return t.baseURL
+}
+
+// Do sends a request and decodes the JSON response into out (if non-nil).
+// path is appended to the base URL unless it is an absolute URL, which
+// must be on the base URL's host or a Vonage API host (see
+// ErrUntrustedHost) unless the transport has no Authenticator. body is
+// sent as JSON, form-encoded if it is url.Values, or as-is if it is a
+// RawBody. Any 2xx status is treated as success; other statuses return
+// *Error.
+func (t *Transport) Do(ctx context.Context, method, path string, body, out interface{}) error {
+	_, err := t.DoStatus(ctx, method, path, body, out)
+	return err
+}
+
+// DoStatus is like Do but also returns the response status code (0 if no
+// response was received)
+func (t *Transport) DoStatus(ctx context.Context, method, path string, body, out interface{}) (int, error) {
+	status, _, err := t.do(ctx, method, path, body, out)
+	return status, err
+}
+
+// DoHeader is like Do but also returns the response headers (nil if no
+// response was received), e.g. for the Location of a created resource
+func (t *Transport) DoHeader(ctx context.Context, method, path string, body, out interface{}) (http.Header, error) {
+	_, header, err := t.do(ctx, method, path, body, out)
+	return header, err
+}
+
+// do sends a request for Do, DoStatus and DoHeader
+func (t *Transport) do(ctx context.Context, method, path string, body, out interface{}) (int, http.Header, error) {
+	ctx, cancel, httpClient := t.withTimeout(ctx)
+	defer cancel()
+
+	req, err := t.newRequest(ctx, method, path, body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	logger := LoggerFromContext(ctx, t.logger)
+
+	start := time.Now()
+	resp, err := t.send(httpClient, req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	var respReader io.Reader = resp.Body
+	if t.maxBody > 0 {
+		respReader = &limitedReader{r: respReader, remaining: t.maxBody}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// A truncated error body is still useful, so the limit error is
+		// ignored here
+		respBody, _ := io.ReadAll(respReader)
+		logger.Error("Vonage API error",
+			"method", method,
+			"url", logURL(req.URL),
+			"status", resp.StatusCode,
+			"body", string(respBody),
+		)
+		apiErr := NewError(resp.StatusCode, string(respBody))
+		apiErr.RetryAfter, _ = retryAfter(resp.Header.Get("Retry-After"))
+		return resp.StatusCode, resp.Header, apiErr
+	}
+
+	logger.Debug("Vonage API request",
+		"method", method,
+		"url", logURL(req.URL),
+		"status", resp.StatusCode,
+		"latency", time.Since(start),
+	)
+
+	if out == nil {
+		return resp.StatusCode, resp.Header, nil
+	}
+
+	snippet := &snippetWriter{max: bodySnippetBytes}
+	if err := json.NewDecoder(io.TeeReader(respReader, snippet)).Decode(out); err != nil && err != io.EOF {
+		return resp.StatusCode, resp.Header, &DecodeError{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Snippet:     string(snippet.buf),
+			Err:         err,
+		}
+	}
+	return resp.StatusCode, resp.Header, nil
+}
+
+// logURL returns u for logging with the API secret and signature redacted,
+// since some endpoints take them in the query string
+func logURL(u *url.URL) string {
+	q := u.Query()
+	redacted := false
+	for _, key := range []string{"api_secret", "sig"} {
+		if q.Has(key) {
+			q.Set(key, "REDACTED")
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.String()
+	}
+	c := *u
+	c.RawQuery = q.Encode()
+	return c.String()
+}
+
+// limitedReader fails with ErrResponseTooLarge once more than remaining
+// bytes have been read, rather than silently truncating like io.LimitReader
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		// Probe for one more byte to tell EOF apart from an oversized body
+		var probe [1]byte
+		n, err := l.r.Read(probe[:])
+		if n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+// snippetWriter keeps the first max bytes written to it
+type snippetWriter struct {
+	buf []byte
+	max int
+}
+
+func (w *snippetWriter) Write(p []byte) (int, error) {
+	if room := w.max - len(w.buf); room > 0 {
+		if len(p) < room {
+			room = len(p)
+		}
+		w.buf = append(w.buf, p[:room]...)
+	}
+	return len(p), nil
+}
+
+// send authenticates req and passes it through the middleware chain. In
+// dry-run and mock mode the chain ends in dryRun instead of the network.
+func (t *Transport) send(httpClient *http.Client, req *http.Request) (*http.Response, error) {
+	skip := t.mode.skips(req)
+
+	// Mock mode needs no credentials
+	if t.auth != nil && t.mode != ModeMock {
+		if !t.trusted(req.URL) {
+			return nil, fmt.Errorf("%w: %s", ErrUntrustedHost, req.URL.Host)
+		}
+		if err := t.auth.Authenticate(req); err != nil {
+			return nil, err
+		}
+	}
+
+	roundTrip := RoundTripFunc(httpClient.Do)
+	if skip {
+		roundTrip = t.dryRun
+	}
+	for i := len(t.middleware) - 1; i >= 0; i-- {
+		roundTrip = t.middleware[i](roundTrip)
+	}
+
+	resp, err := roundTrip(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// trusted reports whether credentials may be sent to u: the transport's
+// own host, or a host in the Vonage API domains over HTTPS
+func (t *Transport) trusted(u *url.URL) bool {
+	if base, err := url.Parse(t.baseURL); err == nil && base.Scheme == u.Scheme && base.Host == u.Host {
+		return true
+	}
+	if u.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, domain := range vonageDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// withTimeout applies a WithRequestTimeout override. The HTTP client's own
+// timeout would still cut the request short, so a copy without it is
+// returned and the deadline is carried by the context instead.
+func (t *Transport) withTimeout(ctx context.Context) (context.Context, context.CancelFunc, *http.Client) {
+	d, ok := ctx.Value(requestTimeoutKey{}).(time.Duration)
+	if !ok {
+		return ctx, func() {}, t.httpClient
+	}
+
+	httpClient := *t.httpClient
+	httpClient.Timeout = 0
+	if d <= 0 {
+		return ctx, func() {}, &httpClient
+	}
+	ctx, cancel := context.WithTimeout(ctx, d)
+	return ctx, cancel, &httpClient
+}
+
+// requestTimeoutKey is the context key for WithRequestTimeout
+type requestTimeoutKey struct{}
+
+// WithRequestTimeout returns a context whose requests are allowed d to
+// complete (including reading the body) instead of the HTTP client's
+// timeout, so a single slow call such as a recording download can run
+// longer than DefaultTimeout without changing the shared client. d <= 0
+// removes the timeout, leaving only ctx's own deadline.
+func WithRequestTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutKey{}, d)
+}
+
+// newRequest builds the HTTP request with its body and default headers
+func (t *Transport) newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	target := path
+	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
+		target = t.baseURL + path
+	}
+
+	var reader io.Reader
+	contentType := ""
+	switch b := body.(type) {
+	case nil:
+	case RawBody:
+		reader = b.Body
+		contentType = b.ContentType
+	case url.Values:
+		if len(b) > 0 {
+			reader = strings.NewReader(b.Encode())
+			contentType = "application/x-www-form-urlencoded"
+		}
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+		contentType = "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", t.userAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, nil
+}
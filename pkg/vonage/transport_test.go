@@ -0,0 +1,38 @@
+package vonage
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestTransportTrusted(t *testing.T) {
+	tr := NewTransport("http://127.0.0.1:8080", nil)
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"http://127.0.0.1:8080/v1/files/rec-1", true},
+		{"https://api.nexmo.com/v1/files/rec-1", true},
+		{"https://api-us.nexmo.com/v1/files/rec-1", true},
+		{"https://api.vonage.com/v3/media/m-1", true},
+		{"https://API.Nexmo.com/v1/files/rec-1", true},
+		{"http://api.nexmo.com/v1/files/rec-1", false},
+		{"http://127.0.0.1:9090/v1/files/rec-1", false},
+		{"https://api.nexmo.com.example.net/v1/files/rec-1", false},
+		{"https://evilnexmo.com/v1/files/rec-1", false},
+		{"https://example.net/api.nexmo.com/v1/files/rec-1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tr.trusted(u); got != tt.want {
+				t.Errorf("trusted(%s) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package users
+
+import (
+	"context"
+)
+
+// ========================================
+// API Interface
+// ========================================
+
+// API is the Users API implemented by *Client. Application code can
+// depend on it and substitute vonagemock.Users in tests.
+type API interface {
+	Create(ctx context.Context, user *User) (*User, error)
+	Get(ctx context.Context, userID string) (*User, error)
+	GetByName(ctx context.Context, name string) (*User, error)
+	Update(ctx context.Context, userID string, user *User) (*User, error)
+	Delete(ctx context.Context, userID string) error
+	List(ctx context.Context, opts *ListOptions) (*Page, error)
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,241 @@
+// Package users manages Conversations users, which Client SDK JWTs refer
+// to in their "sub" claim.
+package users
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+const (
+	// BaseURL is the Vonage Users API base URL
+	BaseURL = vonage.BaseURLREST
+)
+
+// ErrNameRequired is returned when creating a user without a name
+var ErrNameRequired = errors.New("users: name is required")
+
+// Client handles Vonage Users API operations
+type Client struct {
+	baseURL      string
+	jwtGenerator *vonage.JWTGenerator
+	httpClient   *http.Client
+	middleware   []vonage.Middleware
+	transport    *vonage.Transport
+	logger       vonage.Logger
+	uaSuffix     string
+}
+
+// ClientOption is a functional option for configuring the users client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the base URL (useful for testing)
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l vonage.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier such as
+// "checkin-service/1.4" to the User-Agent
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.uaSuffix = suffix
+	}
+}
+
+// WithMiddleware adds transport middleware (retries, tracing, metrics)
+func WithMiddleware(mw ...vonage.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithSettings applies the HTTP client, logger, middleware and User-Agent
+// suffix shared by a root vonage.Client, see
+// vonage.Client.Settings
+func WithSettings(s vonage.Settings) ClientOption {
+	return func(c *Client) {
+		if s.HTTPClient != nil {
+			c.httpClient = s.HTTPClient
+		}
+		if s.Logger != nil {
+			c.logger = s.Logger
+		}
+		c.middleware = append(c.middleware, s.Middleware...)
+		c.uaSuffix = s.UserAgentSuffix
+	}
+}
+
+// WithTransport uses a shared transport instead of building one, pointing
+// at the REST host (BaseURL). Its configuration replaces WithBaseURL,
+// WithHTTPClient, WithMiddleware, WithUserAgentSuffix and the matching
+// parts of WithSettings, and it authenticates instead of the JWT generator
+// passed to NewClient; WithLogger then only applies to the client's own
+// logs.
+func WithTransport(t *vonage.Transport) ClientOption {
+	return func(c *Client) {
+		c.transport = t
+	}
+}
+
+// NewClient creates a new Vonage Users API client
+func NewClient(jwtGenerator *vonage.JWTGenerator, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:      BaseURL,
+		jwtGenerator: jwtGenerator,
+		httpClient:   &http.Client{Timeout: vonage.DefaultTimeout},
+		logger:       vonage.NopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.transport == nil {
+		c.transport = vonage.NewTransport(c.baseURL, jwtGenerator,
+			vonage.WithTransportHTTPClient(c.httpClient),
+			vonage.WithMiddleware(c.middleware...),
+			vonage.WithTransportLogger(c.logger),
+			vonage.WithTransportUserAgentSuffix(c.uaSuffix),
+		)
+	}
+
+	return c
+}
+
+// NewClientFromCredentials creates a new client from Vonage credentials
+func NewClientFromCredentials(creds *vonage.Credentials, opts ...ClientOption) (*Client, error) {
+	if !creds.HasApplication() {
+		return nil, vonage.ErrNotConfigured
+	}
+
+	jwtGen := vonage.NewJWTGeneratorFromCredentials(creds)
+	return NewClient(jwtGen, opts...), nil
+}
+
+// ========================================
+// Users
+// ========================================
+
+// Create creates a user. Name must be unique within the application.
+func (c *Client) Create(ctx context.Context, user *User) (*User, error) {
+	if user == nil || user.Name == "" {
+		return nil, ErrNameRequired
+	}
+
+	var created User
+	if err := c.transport.Do(ctx, http.MethodPost, "/v1/users", user, &created); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Created Vonage user", "userID", created.ID, "name", created.Name)
+	return &created, nil
+}
+
+// Get retrieves a user by ID
+func (c *Client) Get(ctx context.Context, userID string) (*User, error) {
+	var user User
+	if err := c.transport.Do(ctx, http.MethodGet, userPath(userID), nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByName retrieves a user by name, returning an *vonage.Error with
+// IsNotFound true if there is none
+func (c *Client) GetByName(ctx context.Context, name string) (*User, error) {
+	page, err := c.List(ctx, &ListOptions{Name: name, PageSize: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(page.Users) == 0 {
+		return nil, vonage.NewError(http.StatusNotFound, "user "+name+" not found")
+	}
+	return c.Get(ctx, page.Users[0].ID)
+}
+
+// Update changes the non-empty fields of user (PATCH semantics)
+func (c *Client) Update(ctx context.Context, userID string, user *User) (*User, error) {
+	var updated User
+	if err := c.transport.Do(ctx, http.MethodPatch, userPath(userID), user, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Delete deletes a user
+func (c *Client) Delete(ctx context.Context, userID string) error {
+	if err := c.transport.Do(ctx, http.MethodDelete, userPath(userID), nil, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Deleted Vonage user", "userID", userID)
+	return nil
+}
+
+// List returns one page of users. Pass the page's NextCursor in
+// opts.Cursor to fetch the next one.
+func (c *Client) List(ctx context.Context, opts *ListOptions) (*Page, error) {
+	var resp listResponse
+	if err := c.transport.Do(ctx, http.MethodGet, "/v1/users"+opts.query(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &Page{
+		Users:      resp.Embedded.Users,
+		NextCursor: resp.nextCursor(),
+	}, nil
+}
+
+// NewPager returns a pager over the users matching opts, from opts.Cursor
+// on
+func NewPager(c API, opts *ListOptions, pagerOpts ...vonage.PagerOption) *vonage.Pager[User] {
+	var base ListOptions
+	if opts != nil {
+		base = *opts
+	}
+	return vonage.NewPager(func(ctx context.Context, token string) ([]User, string, error) {
+		o := base
+		if token != "" {
+			o.Cursor = token
+		}
+		page, err := c.List(ctx, &o)
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Users, page.NextCursor, nil
+	}, pagerOpts...)
+}
+
+// userPath returns the path of a user resource
+func userPath(userID string) string {
+	return "/v1/users/" + url.PathEscape(userID)
+}
+
+// ========================================
+// Health
+// ========================================
+
+// Ping checks the credentials and connectivity for readiness probes; it
+// lists one user
+func (c *Client) Ping(ctx context.Context) *vonage.Health {
+	return c.transport.Ping(ctx, "users", "/v1/users?page_size=1")
+}
@@ -0,0 +1,53 @@
+package users_test
+
+import (
+	"context"
+	"fmt"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/users"
+)
+
+func ExampleClient_Create() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+	)
+	client, _ := users.NewClientFromCredentials(creds)
+
+	// Provision a user before issuing Client SDK JWTs with sub "staff-42"
+	user, err := client.Create(context.Background(), &users.User{
+		Name:        "staff-42",
+		DisplayName: "Front Desk",
+		Properties: &users.Properties{
+			CustomData: map[string]interface{}{"store": "shibuya"},
+		},
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(user.ID)
+}
+
+func ExampleClient_List() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+	)
+	client, _ := users.NewClientFromCredentials(creds)
+
+	opts := &users.ListOptions{PageSize: 100}
+	for {
+		page, err := client.List(context.Background(), opts)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		for _, u := range page.Users {
+			fmt.Println(u.Name)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+}
@@ -0,0 +1,135 @@
+package users
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// User is a Conversations / Client SDK user
+type User struct {
+	ID          string      `json:"id,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	DisplayName string      `json:"display_name,omitempty"`
+	ImageURL    string      `json:"image_url,omitempty"`
+	Properties  *Properties `json:"properties,omitempty"`
+	Channels    *Channels   `json:"channels,omitempty"`
+}
+
+// Properties holds application data attached to a user
+type Properties struct {
+	CustomData map[string]interface{} `json:"custom_data,omitempty"`
+}
+
+// Channels are the endpoints a user can be reached on
+type Channels struct {
+	PSTN      []NumberChannel    `json:"pstn,omitempty"`
+	SIP       []SIPChannel       `json:"sip,omitempty"`
+	WebSocket []WebSocketChannel `json:"websocket,omitempty"`
+	SMS       []NumberChannel    `json:"sms,omitempty"`
+	MMS       []NumberChannel    `json:"mms,omitempty"`
+	WhatsApp  []NumberChannel    `json:"whatsapp,omitempty"`
+	Viber     []NumberChannel    `json:"viber,omitempty"`
+	Messenger []IDChannel        `json:"messenger,omitempty"`
+}
+
+// NumberChannel is a phone number channel (pstn, sms, mms, whatsapp, viber)
+type NumberChannel struct {
+	Number string `json:"number"`
+}
+
+// SIPChannel is a SIP endpoint
+type SIPChannel struct {
+	URI      string `json:"uri"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// WebSocketChannel is a WebSocket endpoint
+type WebSocketChannel struct {
+	URI         string            `json:"uri"`
+	ContentType string            `json:"content-type,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// IDChannel is a channel addressed by an ID (messenger)
+type IDChannel struct {
+	ID string `json:"id"`
+}
+
+// ========================================
+// Listing
+// ========================================
+
+// Order is the sort order of a user list
+type Order string
+
+const (
+	OrderAsc  Order = "asc"
+	OrderDesc Order = "desc"
+)
+
+// ListOptions filters and pages the user list
+type ListOptions struct {
+	// PageSize is the number of users per page (max 100)
+	PageSize int
+	Order    Order
+	// Cursor is the NextCursor of the previous page
+	Cursor string
+	// Name limits the result to the user with this name
+	Name string
+}
+
+// Page is one page of users
+type Page struct {
+	Users []User
+	// NextCursor is empty on the last page
+	NextCursor string
+}
+
+// listResponse is the HAL body of the list endpoint
+type listResponse struct {
+	Embedded struct {
+		Users []User `json:"users"`
+	} `json:"_embedded"`
+	Links struct {
+		Next *struct {
+			Href string `json:"href"`
+		} `json:"next"`
+	} `json:"_links"`
+}
+
+// nextCursor extracts the cursor from the next link
+func (r *listResponse) nextCursor() string {
+	if r.Links.Next == nil {
+		return ""
+	}
+	u, err := url.Parse(r.Links.Next.Href)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("cursor")
+}
+
+// query encodes list options
+func (o *ListOptions) query() string {
+	if o == nil {
+		return ""
+	}
+	q := url.Values{}
+	if o.PageSize > 0 {
+		q.Set("page_size", strconv.Itoa(o.PageSize))
+	}
+	if o.Order != "" {
+		q.Set("order", string(o.Order))
+	}
+	if o.Cursor != "" {
+		q.Set("cursor", o.Cursor)
+	}
+	if o.Name != "" {
+		q.Set("name", o.Name)
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
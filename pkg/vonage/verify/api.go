@@ -0,0 +1,19 @@
+package verify
+
+import (
+	"context"
+)
+
+// ========================================
+// API Interface
+// ========================================
+
+// API is the unified Verify API implemented by *Client. Application code
+// can depend on it and substitute vonagemock.Verify in tests.
+type API interface {
+	StartVerification(ctx context.Context, number string, opts *StartOptions) (*StartResult, error)
+	CheckVerification(ctx context.Context, requestID, code string) (*CheckResult, error)
+	CancelVerification(ctx context.Context, requestID string) error
+}
+
+var _ API = (*Client)(nil)
@@ -0,0 +1,422 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+const (
+	// BaseURL is the Vonage Verify API base URL
+	BaseURL = vonage.BaseURLREST
+)
+
+var (
+	// ErrV1NotConfigured is returned when Verify v1 is used without an API key
+	ErrV1NotConfigured = errors.New("verify: v1 requires API key and secret")
+	// ErrV2NotConfigured is returned when Verify v2 is used without an application
+	ErrV2NotConfigured = errors.New("verify: v2 requires application credentials")
+)
+
+// Error is a Verify v1 error, reported by the API with HTTP 200 and a
+// non-zero status
+type Error struct {
+	RequestID string
+	Status    string
+	Text      string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("verify: status %s - %s", e.Status, e.Text)
+}
+
+// Client handles Vonage Verify API operations (v1 and v2)
+type Client struct {
+	apiKey    string
+	apiSecret string
+	provider  vonage.CredentialsProvider
+
+	brand      string
+	locale     string
+	codeLength int
+
+	baseURL      string
+	jwtGenerator *vonage.JWTGenerator
+	httpClient   *http.Client
+	middleware   []vonage.Middleware
+	logger       vonage.Logger
+	uaSuffix     string
+	mode         vonage.Mode
+
+	// v1 authenticates with the API key in the query; v2 with a JWT
+	v1 *vonage.Transport
+	v2 *vonage.Transport
+}
+
+// ClientOption is a functional option for configuring the verify client
+type ClientOption func(*Client)
+
+// WithBrand sets the default brand shown in messages
+func WithBrand(brand string) ClientOption {
+	return func(c *Client) {
+		c.brand = brand
+	}
+}
+
+// WithLocale sets the default message locale
+func WithLocale(locale string) ClientOption {
+	return func(c *Client) {
+		c.locale = locale
+	}
+}
+
+// WithCodeLength sets the default code length
+func WithCodeLength(n int) ClientOption {
+	return func(c *Client) {
+		c.codeLength = n
+	}
+}
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the base URL (useful for testing)
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l vonage.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier such as
+// "checkin-service/1.4" to the User-Agent
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.uaSuffix = suffix
+	}
+}
+
+// WithMiddleware adds transport middleware (retries, tracing, metrics)
+func WithMiddleware(mw ...vonage.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithMode sets the client's mode (default vonage.ModeLive). In
+// vonage.ModeMock no request is sent, and in vonage.ModeDryRun only
+// reads are.
+func WithMode(m vonage.Mode) ClientOption {
+	return func(c *Client) {
+		c.mode = m
+	}
+}
+
+// WithSettings applies the HTTP client, logger, middleware, User-Agent
+// suffix and mode shared by a root vonage.Client, see
+// vonage.Client.Settings
+func WithSettings(s vonage.Settings) ClientOption {
+	return func(c *Client) {
+		if s.HTTPClient != nil {
+			c.httpClient = s.HTTPClient
+		}
+		if s.Logger != nil {
+			c.logger = s.Logger
+		}
+		c.middleware = append(c.middleware, s.Middleware...)
+		c.uaSuffix = s.UserAgentSuffix
+		c.mode = s.Mode
+	}
+}
+
+// WithCredentialsProvider fetches the v1 API key and secret from p for
+// every request instead of using those passed to NewClient, so rotated
+// keys are picked up. It enables v1.
+func WithCredentialsProvider(p vonage.CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.provider = p
+	}
+}
+
+// NewClient creates a new Vonage Verify API client. apiKey/apiSecret enable
+// v1 and jwtGenerator enables v2; either may be empty.
+func NewClient(apiKey, apiSecret string, jwtGenerator *vonage.JWTGenerator, opts ...ClientOption) *Client {
+	c := &Client{
+		apiKey:       apiKey,
+		apiSecret:    apiSecret,
+		brand:        "Vonage",
+		baseURL:      BaseURL,
+		jwtGenerator: jwtGenerator,
+		httpClient:   &http.Client{Timeout: vonage.DefaultTimeout},
+		logger:       vonage.NopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	transportOpts := []vonage.TransportOption{
+		vonage.WithTransportHTTPClient(c.httpClient),
+		vonage.WithMiddleware(c.middleware...),
+		vonage.WithTransportLogger(c.logger),
+		vonage.WithTransportUserAgentSuffix(c.uaSuffix),
+		vonage.WithTransportMode(c.mode),
+	}
+	provider := c.provider
+	if provider == nil {
+		provider = &vonage.Credentials{APIKey: apiKey, APISecret: apiSecret}
+	}
+	c.v1 = vonage.NewTransport(c.baseURL, vonage.NewAuthChain(provider, vonage.AuthQuery), transportOpts...)
+	c.v2 = vonage.NewTransport(c.baseURL, jwtGenerator, transportOpts...)
+
+	return c
+}
+
+// NewClientFromCredentials creates a new client from Vonage credentials
+func NewClientFromCredentials(creds *vonage.Credentials, opts ...ClientOption) (*Client, error) {
+	if !creds.HasAPIKey() && !creds.HasApplication() {
+		return nil, vonage.ErrNotConfigured
+	}
+
+	var jwtGen *vonage.JWTGenerator
+	if creds.HasApplication() {
+		jwtGen = vonage.NewJWTGeneratorFromCredentials(creds)
+	}
+	return NewClient(creds.APIKey, creds.APISecret, jwtGen, append([]ClientOption{WithMode(creds.Mode)}, opts...)...), nil
+}
+
+// hasV1 returns true if v1 credentials are configured
+func (c *Client) hasV1() bool {
+	return c.provider != nil || (c.apiKey != "" && c.apiSecret != "")
+}
+
+// hasV2 returns true if v2 credentials are configured
+func (c *Client) hasV2() bool {
+	return c.jwtGenerator != nil
+}
+
+// ========================================
+// Unified API
+// ========================================
+
+// StartVerification sends a verification code to number. It uses v2 when
+// opts.V2Channels is set or no API key is configured, and v1 otherwise.
+func (c *Client) StartVerification(ctx context.Context, number string, opts *StartOptions) (*StartResult, error) {
+	if (opts != nil && len(opts.V2Channels) > 0) || !c.hasV1() {
+		return c.StartV2(ctx, number, opts)
+	}
+	return c.StartV1(ctx, number, opts)
+}
+
+// CheckVerification checks a code against a request started with either
+// version. v2 request IDs are UUIDs; v1 IDs are not.
+func (c *Client) CheckVerification(ctx context.Context, requestID, code string) (*CheckResult, error) {
+	if versionOf(requestID) == V2 {
+		return c.CheckV2(ctx, requestID, code)
+	}
+	return c.CheckV1(ctx, requestID, code)
+}
+
+// CancelVerification cancels a pending verification
+func (c *Client) CancelVerification(ctx context.Context, requestID string) error {
+	if versionOf(requestID) == V2 {
+		return c.v2.Do(ctx, http.MethodDelete, "/v2/verify/"+url.PathEscape(requestID), nil, nil)
+	}
+
+	if !c.hasV1() {
+		return ErrV1NotConfigured
+	}
+	form := url.Values{}
+	form.Set("request_id", requestID)
+	form.Set("cmd", "cancel")
+	var resp v1Response
+	if err := c.v1.Do(ctx, http.MethodPost, "/verify/control/json", form, &resp); err != nil {
+		return err
+	}
+	return resp.err()
+}
+
+// versionOf infers the API version from a request ID
+func versionOf(requestID string) Version {
+	if strings.Contains(requestID, "-") {
+		return V2
+	}
+	return V1
+}
+
+// ========================================
+// Verify v1
+// ========================================
+
+// StartV1 starts a verification with the legacy v1 API
+func (c *Client) StartV1(ctx context.Context, number string, opts *StartOptions) (*StartResult, error) {
+	if !c.hasV1() {
+		return nil, ErrV1NotConfigured
+	}
+	if opts == nil {
+		opts = &StartOptions{}
+	}
+
+	form := url.Values{}
+	form.Set("number", number)
+	form.Set("brand", orDefault(opts.Brand, c.brand))
+	if n := orDefaultInt(opts.CodeLength, c.codeLength); n > 0 {
+		form.Set("code_length", strconv.Itoa(n))
+	}
+	if lg := orDefault(opts.Locale, c.locale); lg != "" {
+		form.Set("lg", lg)
+	}
+	if opts.PINExpiry > 0 {
+		form.Set("pin_expiry", strconv.Itoa(opts.PINExpiry))
+	}
+	if opts.WorkflowID > 0 {
+		form.Set("workflow_id", strconv.Itoa(int(opts.WorkflowID)))
+	}
+
+	var resp v1Response
+	if err := c.v1.Do(ctx, http.MethodPost, "/verify/json", form, &resp); err != nil {
+		return nil, err
+	}
+	if err := resp.err(); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Started verification", "requestID", resp.RequestID, "version", 1)
+	return &StartResult{RequestID: resp.RequestID, Version: V1}, nil
+}
+
+// CheckV1 checks a code with the legacy v1 API. A wrong code returns a
+// CheckResult with Verified false rather than an error.
+func (c *Client) CheckV1(ctx context.Context, requestID, code string) (*CheckResult, error) {
+	if !c.hasV1() {
+		return nil, ErrV1NotConfigured
+	}
+
+	form := url.Values{}
+	form.Set("request_id", requestID)
+	form.Set("code", code)
+
+	var resp v1Response
+	if err := c.v1.Do(ctx, http.MethodPost, "/verify/check/json", form, &resp); err != nil {
+		return nil, err
+	}
+
+	result := &CheckResult{
+		RequestID: requestID,
+		Verified:  resp.Status == "0",
+		Status:    resp.Status,
+		Price:     resp.Price,
+		Currency:  resp.Currency,
+	}
+	// Status 16 is "wrong code"; anything else non-zero is a real error
+	if !result.Verified && resp.Status != "16" {
+		return result, resp.err()
+	}
+	return result, nil
+}
+
+// err converts a non-zero v1 status into an *Error
+func (r *v1Response) err() error {
+	if r.Status == "" || r.Status == "0" {
+		return nil
+	}
+	return &Error{RequestID: r.RequestID, Status: r.Status, Text: r.ErrorText}
+}
+
+// ========================================
+// Verify v2
+// ========================================
+
+// StartV2 starts a verification with the v2 API. Without V2Channels it
+// sends a single SMS.
+func (c *Client) StartV2(ctx context.Context, number string, opts *StartOptions) (*StartResult, error) {
+	if !c.hasV2() {
+		return nil, ErrV2NotConfigured
+	}
+	if opts == nil {
+		opts = &StartOptions{}
+	}
+
+	channels := opts.V2Channels
+	if len(channels) == 0 {
+		channels = []V2Channel{V2ChannelSMS}
+	}
+
+	req := v2StartRequest{
+		Brand:          orDefault(opts.Brand, c.brand),
+		Locale:         orDefault(opts.Locale, c.locale),
+		CodeLength:     orDefaultInt(opts.CodeLength, c.codeLength),
+		ChannelTimeout: opts.ChannelTimeout,
+	}
+	for _, ch := range channels {
+		req.Workflow = append(req.Workflow, v2Workflow{Channel: ch, To: number})
+	}
+
+	var resp v2StartResponse
+	if err := c.v2.Do(ctx, http.MethodPost, "/v2/verify", req, &resp); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Started verification", "requestID", resp.RequestID, "version", 2)
+	return &StartResult{RequestID: resp.RequestID, Version: V2}, nil
+}
+
+// CheckV2 checks a code with the v2 API. A wrong code returns a
+// CheckResult with Verified false rather than an error.
+func (c *Client) CheckV2(ctx context.Context, requestID, code string) (*CheckResult, error) {
+	if !c.hasV2() {
+		return nil, ErrV2NotConfigured
+	}
+
+	var resp v2CheckResponse
+	err := c.v2.Do(ctx, http.MethodPost, "/v2/verify/"+url.PathEscape(requestID), v2CheckRequest{Code: code}, &resp)
+	if err != nil {
+		var apiErr *vonage.Error
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+			return &CheckResult{RequestID: requestID, Status: "invalid_code"}, nil
+		}
+		return nil, err
+	}
+
+	return &CheckResult{
+		RequestID: requestID,
+		Verified:  resp.Status == "completed",
+		Status:    resp.Status,
+	}, nil
+}
+
+// ========================================
+// Helpers
+// ========================================
+
+// orDefault returns v, or def if v is empty
+func orDefault(v, def string) string {
+	if v != "" {
+		return v
+	}
+	return def
+}
+
+// orDefaultInt returns v, or def if v is not positive
+func orDefaultInt(v, def int) int {
+	if v > 0 {
+		return v
+	}
+	return def
+}
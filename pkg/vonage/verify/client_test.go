@@ -0,0 +1,43 @@
+package verify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+func TestNewClientFromCredentialsMode(t *testing.T) {
+	tests := []struct {
+		mode vonage.Mode
+		want int32
+	}{
+		{vonage.ModeLive, 1},
+		{vonage.ModeDryRun, 0},
+		{vonage.ModeMock, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			var requests atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.Write([]byte(`{"request_id":"req-1","status":"0"}`))
+			}))
+			defer srv.Close()
+
+			creds := &vonage.Credentials{APIKey: "key", APISecret: "secret", Mode: tt.mode}
+			c, err := NewClientFromCredentials(creds, WithBaseURL(srv.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.StartV1(context.Background(), "447700900000", nil)
+			if got := requests.Load(); got != tt.want {
+				t.Errorf("server received %d requests, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package verify_test
+
+import (
+	"context"
+	"fmt"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/verify"
+)
+
+func ExampleClient_StartVerification() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithAPIKey("api-key", "api-secret"),
+	)
+	client, _ := verify.NewClientFromCredentials(creds,
+		verify.WithBrand("MyApp"),
+		verify.WithLocale("ja-jp"),
+		verify.WithCodeLength(6),
+	)
+
+	ctx := context.Background()
+
+	// Step 1: send the code and store the request ID
+	result, err := client.StartVerification(ctx, "81901234567", nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// Step 2: check the code entered by the user
+	check, err := client.CheckVerification(ctx, result.RequestID, "123456")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("Verified: %v (status %s)\n", check.Verified, check.Status)
+}
+
+func ExampleClient_StartV2() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+	)
+	client, _ := verify.NewClientFromCredentials(creds)
+
+	// SMS, then WhatsApp after 60 seconds, then a voice call
+	result, _ := client.StartVerification(context.Background(), "81901234567", &verify.StartOptions{
+		Brand: "VonaTrigger",
+		V2Channels: []verify.V2Channel{
+			verify.V2ChannelSMS,
+			verify.V2ChannelWhatsApp,
+			verify.V2ChannelVoice,
+		},
+		ChannelTimeout: 60,
+	})
+	_ = result
+}
@@ -0,0 +1,118 @@
+package verify
+
+// Workflow is a Verify v1 workflow ID
+type Workflow int
+
+const (
+	// WorkflowSMSTTSTTS sends an SMS, then two voice calls (default)
+	WorkflowSMSTTSTTS Workflow = 1
+	// WorkflowSMSSMSTTS sends two SMS, then a voice call
+	WorkflowSMSSMSTTS Workflow = 2
+	// WorkflowTTSTTSTTS places three voice calls
+	WorkflowTTSTTSTTS Workflow = 3
+	// WorkflowSMSSMS sends two SMS
+	WorkflowSMSSMS Workflow = 4
+	// WorkflowSMSTTS sends an SMS, then a voice call
+	WorkflowSMSTTS Workflow = 5
+	// WorkflowSMS sends a single SMS
+	WorkflowSMS Workflow = 6
+	// WorkflowTTS places a single voice call
+	WorkflowTTS Workflow = 7
+)
+
+// V2Channel is a Verify v2 delivery channel
+type V2Channel string
+
+const (
+	V2ChannelSMS        V2Channel = "sms"
+	V2ChannelWhatsApp   V2Channel = "whatsapp"
+	V2ChannelVoice      V2Channel = "voice"
+	V2ChannelEmail      V2Channel = "email"
+	V2ChannelSilentAuth V2Channel = "silent_auth"
+)
+
+// Version identifies the Verify API version used for a request
+type Version int
+
+const (
+	V1 Version = 1
+	V2 Version = 2
+)
+
+// StartOptions contains options for starting a verification. Unset fields
+// fall back to the client defaults.
+type StartOptions struct {
+	// Brand is the name shown in the verification message
+	Brand string
+	// CodeLength is the number of digits in the code (4 or 6)
+	CodeLength int
+	// Locale is the language of the message, e.g. "ja-jp"
+	Locale string
+
+	// PINExpiry is the code lifetime in seconds (v1 only)
+	PINExpiry int
+	// WorkflowID selects the v1 delivery workflow (v1 only)
+	WorkflowID Workflow
+
+	// V2Channels selects Verify v2 with the given fallback order
+	V2Channels []V2Channel
+	// ChannelTimeout is the number of seconds before falling back to the
+	// next channel (v2 only)
+	ChannelTimeout int
+}
+
+// StartResult is the result of starting a verification
+type StartResult struct {
+	RequestID string
+	Version   Version
+}
+
+// CheckResult is the result of checking a code
+type CheckResult struct {
+	RequestID string
+	Verified  bool
+	// Status is the raw API status ("0" for v1 success, "completed" for v2)
+	Status   string
+	Price    string
+	Currency string
+}
+
+// ========================================
+// API request/response types
+// ========================================
+
+// v1Response is the common Verify v1 response. Errors are reported with a
+// non-zero status and HTTP 200.
+type v1Response struct {
+	RequestID string `json:"request_id"`
+	Status    string `json:"status"`
+	ErrorText string `json:"error_text"`
+	Price     string `json:"price"`
+	Currency  string `json:"currency"`
+}
+
+type v2Workflow struct {
+	Channel V2Channel `json:"channel"`
+	To      string    `json:"to"`
+}
+
+type v2StartRequest struct {
+	Brand          string       `json:"brand"`
+	Locale         string       `json:"locale,omitempty"`
+	CodeLength     int          `json:"code_length,omitempty"`
+	ChannelTimeout int          `json:"channel_timeout,omitempty"`
+	Workflow       []v2Workflow `json:"workflow"`
+}
+
+type v2StartResponse struct {
+	RequestID string `json:"request_id"`
+}
+
+type v2CheckRequest struct {
+	Code string `json:"code"`
+}
+
+type v2CheckResponse struct {
+	RequestID string `json:"request_id"`
+	Status    string `json:"status"`
+}
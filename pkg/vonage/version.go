@@ -0,0 +1,8 @@
+package vonage
+
+// Version is the semantic version of this SDK.
+//
+// Breaking changes to exported signatures bump the minor version while the
+// SDK is pre-1.0; the previous signatures stay available in the compat
+// package for at least one minor release.
+const Version = "0.2.0"
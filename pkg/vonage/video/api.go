@@ -0,0 +1,139 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// API Interfaces
+// ========================================
+
+// API is the Video API implemented by *Client. Application code can depend
+// on it, or on one of the smaller interfaces, and substitute
+// vonagemock.Video in tests.
+type API interface {
+	SessionAPI
+	ArchiveAPI
+	BroadcastAPI
+	ModerationAPI
+	SIPAPI
+	CaptionsAPI
+	RenderAPI
+	ProjectAPI
+}
+
+var _ API = (*Client)(nil)
+
+// SessionAPI creates and looks up sessions
+type SessionAPI interface {
+	CreateSession(ctx context.Context, opts *CreateSessionOptions) (*Session, error)
+	CreateSessionForSpot(ctx context.Context, spotID string, opts *CreateSessionOptions) (*Session, error)
+	GetSession(ctx context.Context, sessionID string) (*Session, error)
+	GetOrCreateSession(ctx context.Context, spotID string, opts *CreateSessionOptions) (*Session, error)
+	FindSessionsByTag(ctx context.Context, key, value string) ([]*Session, error)
+}
+
+// ArchiveAPI manages archives
+type ArchiveAPI interface {
+	StartArchive(ctx context.Context, sessionID string, opts *ArchiveOptions) (*Archive, error)
+	StopArchive(ctx context.Context, archiveID string) (*Archive, error)
+	GetArchive(ctx context.Context, archiveID string) (*Archive, error)
+	ListArchives(ctx context.Context, opts *ListOptions) (*ArchiveList, error)
+	DeleteArchive(ctx context.Context, archiveID string) error
+	SetArchiveLayout(ctx context.Context, archiveID string, layout Layout) error
+	DownloadArchive(ctx context.Context, archive *Archive, w io.Writer, opts ...vonage.DownloadOption) (int64, error)
+}
+
+// BroadcastAPI manages live streaming broadcasts
+type BroadcastAPI interface {
+	StartBroadcast(ctx context.Context, sessionID string, opts *BroadcastOptions) (*Broadcast, error)
+	StopBroadcast(ctx context.Context, broadcastID string) (*Broadcast, error)
+	GetBroadcast(ctx context.Context, broadcastID string) (*Broadcast, error)
+	ListBroadcasts(ctx context.Context, opts *ListOptions) (*BroadcastList, error)
+	SetBroadcastLayout(ctx context.Context, broadcastID string, layout Layout) error
+}
+
+// ModerationAPI disconnects and mutes participants
+type ModerationAPI interface {
+	ForceDisconnect(ctx context.Context, sessionID, connectionID string) error
+	MuteStream(ctx context.Context, sessionID, streamID string) error
+	MuteAllStreams(ctx context.Context, sessionID string, excludedStreamIDs ...string) error
+	DisableForceMute(ctx context.Context, sessionID string) error
+}
+
+// SIPAPI connects SIP calls to sessions
+type SIPAPI interface {
+	Dial(ctx context.Context, sessionID, token, sipURI string, opts *SIPOptions) (*SIPCall, error)
+	PlayDTMF(ctx context.Context, sessionID, connectionID, digits string) error
+	PlayDTMFToSession(ctx context.Context, sessionID, digits string) error
+	HangupSIPCall(ctx context.Context, sessionID string, call *SIPCall) error
+}
+
+// CaptionsAPI manages live captions
+type CaptionsAPI interface {
+	EnableCaptions(ctx context.Context, sessionID, token string, opts *CaptionsOptions) (string, error)
+	DisableCaptions(ctx context.Context, captionsID string) error
+	GetCaptionsStatus(ctx context.Context, captionsID string) (*CaptionsStatus, error)
+}
+
+// RenderAPI manages Experience Composer renders
+type RenderAPI interface {
+	StartRender(ctx context.Context, sessionID, token, pageURL string, opts *RenderOptions) (*Render, error)
+	StopRender(ctx context.Context, renderID string) error
+	GetRender(ctx context.Context, renderID string) (*Render, error)
+	ListRenders(ctx context.Context, opts *ListOptions) (*RenderList, error)
+}
+
+// ProjectAPI manages archive storage and callbacks
+type ProjectAPI interface {
+	SetArchiveStorage(ctx context.Context, storage ArchiveStorage) error
+	DeleteArchiveStorage(ctx context.Context) error
+	RegisterCallback(ctx context.Context, group CallbackGroup, event CallbackEvent, callbackURL string) (*Callback, error)
+	ListCallbacks(ctx context.Context) ([]Callback, error)
+	DeleteCallback(ctx context.Context, callbackID string) error
+}
+
+// ========================================
+// REST helpers
+// ========================================
+
+// projectPath returns the path of a project-scoped Video REST endpoint
+func (c *Client) projectPath(path string) string {
+	return fmt.Sprintf("/v2/project/%s%s", c.appID, path)
+}
+
+// doJSON sends a JSON request to the Video API via the transport and
+// decodes the response into out (if non-nil)
+func (c *Client) doJSON(ctx context.Context, method, path string, in, out interface{}) error {
+	if !c.IsConfigured() {
+		return vonage.ErrNotConfigured
+	}
+	return c.transport.Do(ctx, method, path, in, out)
+}
+
+// query encodes list options as a URL query string
+func (o *ListOptions) query() string {
+	if o == nil {
+		return ""
+	}
+	q := url.Values{}
+	if o.SessionID != "" {
+		q.Set("sessionId", o.SessionID)
+	}
+	if o.Offset > 0 {
+		q.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.Count > 0 {
+		q.Set("count", strconv.Itoa(o.Count))
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
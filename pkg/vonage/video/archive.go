@@ -0,0 +1,106 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ErrArchiveNotAvailable is returned when downloading an archive that is
+// not available from Vonage storage
+var ErrArchiveNotAvailable = errors.New("video: archive not available for download")
+
+// ========================================
+// Archive (Recording) API
+// ========================================
+
+// StartArchive starts recording a session
+func (c *Client) StartArchive(ctx context.Context, sessionID string, opts *ArchiveOptions) (*Archive, error) {
+	req := startArchiveRequest{SessionID: sessionID}
+	if opts != nil {
+		req.ArchiveOptions = *opts
+	}
+
+	var archive Archive
+	if err := c.doJSON(ctx, http.MethodPost, c.projectPath("/archive"), req, &archive); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Started Vonage Video archive", "archiveID", archive.ID, "sessionID", sessionID)
+
+	return &archive, nil
+}
+
+// StopArchive stops a running archive
+func (c *Client) StopArchive(ctx context.Context, archiveID string) (*Archive, error) {
+	var archive Archive
+	path := fmt.Sprintf("/archive/%s/stop", url.PathEscape(archiveID))
+	if err := c.doJSON(ctx, http.MethodPost, c.projectPath(path), nil, &archive); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Stopped Vonage Video archive", "archiveID", archiveID)
+	return &archive, nil
+}
+
+// GetArchive retrieves an archive by ID
+func (c *Client) GetArchive(ctx context.Context, archiveID string) (*Archive, error) {
+	var archive Archive
+	path := "/archive/" + url.PathEscape(archiveID)
+	if err := c.doJSON(ctx, http.MethodGet, c.projectPath(path), nil, &archive); err != nil {
+		return nil, err
+	}
+	return &archive, nil
+}
+
+// ListArchives lists archives, optionally filtered by session
+func (c *Client) ListArchives(ctx context.Context, opts *ListOptions) (*ArchiveList, error) {
+	var list ArchiveList
+	if err := c.doJSON(ctx, http.MethodGet, c.projectPath("/archive")+opts.query(), nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// DeleteArchive deletes an archive and its recording
+func (c *Client) DeleteArchive(ctx context.Context, archiveID string) error {
+	path := "/archive/" + url.PathEscape(archiveID)
+	if err := c.doJSON(ctx, http.MethodDelete, c.projectPath(path), nil, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Deleted Vonage Video archive", "archiveID", archiveID)
+	return nil
+}
+
+// SetArchiveLayout changes the layout of a running composed archive
+func (c *Client) SetArchiveLayout(ctx context.Context, archiveID string, layout Layout) error {
+	path := fmt.Sprintf("/archive/%s/layout", url.PathEscape(archiveID))
+	return c.doJSON(ctx, http.MethodPut, c.projectPath(path), layout, nil)
+}
+
+// DownloadArchive streams an available archive's recording to w, returning
+// the number of bytes written. The archive URL is pre-signed and expires
+// after ten minutes; fetch the archive again with GetArchive for a fresh
+// one. The client's HTTP timeout does not apply, so bound large downloads
+// with ctx. opts add progress reporting, resuming and checksum checks.
+func (c *Client) DownloadArchive(ctx context.Context, archive *Archive, w io.Writer, opts ...vonage.DownloadOption) (int64, error) {
+	if archive == nil || archive.URL == "" || archive.Status != ArchiveStatusAvailable {
+		return 0, ErrArchiveNotAvailable
+	}
+
+	// The pre-signed URL carries its own authorization, so the request
+	// goes without the client's credentials
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+	t := vonage.NewTransport("", nil,
+		vonage.WithTransportHTTPClient(&httpClient),
+		vonage.WithTransportLogger(c.logger),
+	)
+	return t.Download(ctx, archive.URL, w, opts...)
+}
@@ -0,0 +1,70 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ========================================
+// Broadcast (Live Streaming) API
+// ========================================
+
+// StartBroadcast starts streaming a session to HLS and/or RTMP outputs
+func (c *Client) StartBroadcast(ctx context.Context, sessionID string, opts *BroadcastOptions) (*Broadcast, error) {
+	if opts == nil || (opts.Outputs.HLS == nil && len(opts.Outputs.RTMP) == 0) {
+		return nil, fmt.Errorf("video: broadcast requires at least one HLS or RTMP output")
+	}
+
+	req := startBroadcastRequest{SessionID: sessionID, BroadcastOptions: *opts}
+
+	var broadcast Broadcast
+	if err := c.doJSON(ctx, http.MethodPost, c.projectPath("/broadcast"), req, &broadcast); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Started Vonage Video broadcast",
+		"broadcastID", broadcast.ID,
+		"sessionID", sessionID,
+	)
+
+	return &broadcast, nil
+}
+
+// StopBroadcast stops a live broadcast
+func (c *Client) StopBroadcast(ctx context.Context, broadcastID string) (*Broadcast, error) {
+	var broadcast Broadcast
+	path := fmt.Sprintf("/broadcast/%s/stop", url.PathEscape(broadcastID))
+	if err := c.doJSON(ctx, http.MethodPost, c.projectPath(path), nil, &broadcast); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Stopped Vonage Video broadcast", "broadcastID", broadcastID)
+	return &broadcast, nil
+}
+
+// GetBroadcast retrieves a broadcast by ID
+func (c *Client) GetBroadcast(ctx context.Context, broadcastID string) (*Broadcast, error) {
+	var broadcast Broadcast
+	path := "/broadcast/" + url.PathEscape(broadcastID)
+	if err := c.doJSON(ctx, http.MethodGet, c.projectPath(path), nil, &broadcast); err != nil {
+		return nil, err
+	}
+	return &broadcast, nil
+}
+
+// ListBroadcasts lists broadcasts, optionally filtered by session
+func (c *Client) ListBroadcasts(ctx context.Context, opts *ListOptions) (*BroadcastList, error) {
+	var list BroadcastList
+	if err := c.doJSON(ctx, http.MethodGet, c.projectPath("/broadcast")+opts.query(), nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// SetBroadcastLayout changes the layout of a live broadcast
+func (c *Client) SetBroadcastLayout(ctx context.Context, broadcastID string, layout Layout) error {
+	path := fmt.Sprintf("/broadcast/%s/layout", url.PathEscape(broadcastID))
+	return c.doJSON(ctx, http.MethodPut, c.projectPath(path), layout, nil)
+}
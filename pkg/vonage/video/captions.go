@@ -0,0 +1,58 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ========================================
+// Live Captions API
+// ========================================
+
+// EnableCaptions starts live captioning of a session's audio and returns
+// the captions ID. The token must be a moderator token for the session.
+func (c *Client) EnableCaptions(ctx context.Context, sessionID, token string, opts *CaptionsOptions) (string, error) {
+	req := enableCaptionsRequest{SessionID: sessionID, Token: token}
+	if opts != nil {
+		req.CaptionsOptions = *opts
+	}
+	if req.LanguageCode == "" {
+		req.LanguageCode = "en-US"
+	}
+
+	var resp enableCaptionsResponse
+	if err := c.doJSON(ctx, http.MethodPost, c.projectPath("/captions"), req, &resp); err != nil {
+		return "", err
+	}
+
+	c.logger.Info("Enabled Vonage Video live captions",
+		"captionsID", resp.CaptionsID,
+		"sessionID", sessionID,
+		"language", req.LanguageCode,
+	)
+
+	return resp.CaptionsID, nil
+}
+
+// DisableCaptions stops live captioning
+func (c *Client) DisableCaptions(ctx context.Context, captionsID string) error {
+	path := fmt.Sprintf("/captions/%s/stop", url.PathEscape(captionsID))
+	if err := c.doJSON(ctx, http.MethodPost, c.projectPath(path), nil, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Disabled Vonage Video live captions", "captionsID", captionsID)
+	return nil
+}
+
+// GetCaptionsStatus retrieves the status of a captioning job
+func (c *Client) GetCaptionsStatus(ctx context.Context, captionsID string) (*CaptionsStatus, error) {
+	var status CaptionsStatus
+	path := "/captions/" + url.PathEscape(captionsID)
+	if err := c.doJSON(ctx, http.MethodGet, c.projectPath(path), nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
@@ -0,0 +1,74 @@
+package video
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// ========================================
+// Background Session Cleanup
+// ========================================
+
+// CleanupJitter is the fraction of the interval by which each cleanup run
+// is randomly shifted, so many instances don't sweep in lockstep
+const CleanupJitter = 0.1
+
+// MinCleanupInterval is the shortest interval StartCleanup sweeps at
+const MinCleanupInterval = time.Second
+
+// CleanupStats holds counters for expired-session cleanup
+type CleanupStats struct {
+	// Runs is the number of cleanup sweeps performed
+	Runs int64
+	// Removed is the total number of sessions removed
+	Removed int64
+	// LastRun is when the last sweep finished
+	LastRun time.Time
+	// LastRemoved is the number of sessions removed by the last sweep
+	LastRemoved int
+}
+
+// StartCleanup runs CleanupExpiredSessions every interval (with jitter) in a
+// background goroutine until ctx is cancelled. Intervals shorter than
+// MinCleanupInterval, including zero and negative ones, are raised to it.
+func (c *Client) StartCleanup(ctx context.Context, interval time.Duration) {
+	if interval < MinCleanupInterval {
+		c.logger.Warn("Video session cleanup interval too short, using the minimum",
+			"interval", interval,
+			"minimum", MinCleanupInterval,
+		)
+		interval = MinCleanupInterval
+	}
+
+	go func() {
+		c.logger.Debug("Started video session cleanup", "interval", interval)
+
+		timer := time.NewTimer(jitter(interval))
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				c.logger.Debug("Stopped video session cleanup")
+				return
+			case <-timer.C:
+				c.CleanupExpiredSessions(ctx)
+				timer.Reset(jitter(interval))
+			}
+		}
+	}()
+}
+
+// CleanupStats returns a snapshot of the cleanup counters
+func (c *Client) CleanupStats() CleanupStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cleanupStats
+}
+
+// jitter returns d shifted by up to ±CleanupJitter
+func jitter(d time.Duration) time.Duration {
+	spread := float64(d) * CleanupJitter
+	return d + time.Duration((rand.Float64()*2-1)*spread)
+}
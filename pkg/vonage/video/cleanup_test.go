@@ -0,0 +1,37 @@
+package video
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStartCleanupShortInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Minute, time.Nanosecond} {
+		t.Run(interval.String(), func(t *testing.T) {
+			c := NewClient("app-1", nil)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			c.StartCleanup(ctx, interval)
+			time.Sleep(100 * time.Millisecond)
+
+			// The interval is raised to MinCleanupInterval instead of
+			// sweeping in a busy loop
+			if runs := c.CleanupStats().Runs; runs != 0 {
+				t.Errorf("Runs = %d after 100ms, want 0", runs)
+			}
+		})
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for _, d := range []time.Duration{MinCleanupInterval, time.Minute, time.Hour} {
+		spread := time.Duration(float64(d) * CleanupJitter)
+		for i := 0; i < 100; i++ {
+			if got := jitter(d); got < d-spread || got > d+spread {
+				t.Fatalf("jitter(%s) = %s, want within ±%s", d, got, spread)
+			}
+		}
+	}
+}
@@ -1,23 +1,21 @@
 package video
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
-	"strings"
 	"sync"
 	"time"
 
-	"github.com/rs/zerolog/log"
-
 	vonage "github.com/vonatrigger/poc/pkg/vonage"
 )
 
 const (
 	// BaseURL is the Vonage Video API base URL
-	BaseURL = "https://video.api.vonage.com"
+	BaseURL = vonage.BaseURLVideo
 
 	// DefaultSessionTTL is the default session time-to-live
 	DefaultSessionTTL = 24 * time.Hour
@@ -25,13 +23,38 @@ const (
 
 // Client handles Vonage Video API operations
 type Client struct {
+	baseURL      string
 	appID        string
 	jwtGenerator *vonage.JWTGenerator
 	httpClient   *http.Client
+	middleware   []vonage.Middleware
+	transport    *vonage.Transport
+	logger       vonage.Logger
+	uaSuffix     string
+	mode         vonage.Mode
+
+	// mockFallback substitutes mock sessions when the API is unavailable
+	mockFallback bool
 
 	// Session cache
-	sessions map[string]*Session
-	mu       sync.RWMutex
+	store        SessionStore
+	cleanupStats CleanupStats
+	mu           sync.RWMutex
+
+	// In-flight session creations per spot (singleflight)
+	spotCalls   map[string]*spotCall
+	spotCallsMu sync.Mutex
+}
+
+// spotCallTimeout bounds a shared CreateSessionForSpot, which no single
+// caller's context can cancel
+const spotCallTimeout = 2 * vonage.DefaultTimeout
+
+// spotCall is an in-flight CreateSessionForSpot shared by concurrent callers
+type spotCall struct {
+	done    chan struct{}
+	session *Session
+	err     error
 }
 
 // ClientOption is a functional option for configuring the video client
@@ -44,19 +67,137 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 	}
 }
 
+// WithBaseURL overrides the base URL (useful for testing)
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l vonage.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier such as
+// "checkin-service/1.4" to the User-Agent
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.uaSuffix = suffix
+	}
+}
+
+// WithMiddleware adds transport middleware (retries, tracing, metrics)
+func WithMiddleware(mw ...vonage.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithRequestInterceptor runs fn on every outgoing request, e.g. to add
+// headers or inject faults in tests
+func WithRequestInterceptor(fn vonage.RequestInterceptor) ClientOption {
+	return WithMiddleware(vonage.InterceptRequest(fn))
+}
+
+// WithResponseInterceptor runs fn on every response before it is decoded
+func WithResponseInterceptor(fn vonage.ResponseInterceptor) ClientOption {
+	return WithMiddleware(vonage.InterceptResponse(fn))
+}
+
+// WithMode sets the client's mode (default vonage.ModeLive). In
+// vonage.ModeMock sessions are always mock sessions, as with
+// WithMockFallback but without calling the API.
+func WithMode(m vonage.Mode) ClientOption {
+	return func(c *Client) {
+		c.mode = m
+	}
+}
+
+// WithSettings applies the HTTP client, logger, middleware, User-Agent
+// suffix and mode shared by a root vonage.Client, see
+// vonage.Client.Settings
+func WithSettings(s vonage.Settings) ClientOption {
+	return func(c *Client) {
+		if s.HTTPClient != nil {
+			c.httpClient = s.HTTPClient
+		}
+		if s.Logger != nil {
+			c.logger = s.Logger
+		}
+		c.middleware = append(c.middleware, s.Middleware...)
+		c.uaSuffix = s.UserAgentSuffix
+		c.mode = s.Mode
+	}
+}
+
+// WithTransport uses a shared transport instead of building one. It must
+// point at the Video API host (BaseURL, vonage.BaseURLVideo), so it cannot
+// be shared with the voice or messages clients. Requests then use only the
+// transport's configuration: WithBaseURL, WithHTTPClient, WithMiddleware,
+// WithRequestInterceptor, WithResponseInterceptor, WithUserAgentSuffix and
+// the matching parts of WithSettings are ignored, as is the JWT generator
+// passed to NewClient. WithLogger and WithMode still apply to the
+// client's own logs and mock sessions, but not to request logging.
+func WithTransport(t *vonage.Transport) ClientOption {
+	return func(c *Client) {
+		c.transport = t
+	}
+}
+
+// WithStrictMode makes session creation fail when the API is not configured
+// or returns an error. This is the default; it overrides WithMockFallback.
+func WithStrictMode() ClientOption {
+	return func(c *Client) {
+		c.mockFallback = false
+	}
+}
+
+// WithMockFallback makes session creation return a mock session (IsMock)
+// when the API is not configured or fails, together with a
+// *MockSessionError matching ErrMockSession. Intended for local
+// development only; tests should run against a vonagetest.Server instead.
+func WithMockFallback() ClientOption {
+	return func(c *Client) {
+		c.mockFallback = true
+	}
+}
+
+// WithSessionStore replaces the default in-memory session store
+func WithSessionStore(store SessionStore) ClientOption {
+	return func(c *Client) {
+		c.store = store
+	}
+}
+
 // NewClient creates a new Vonage Video API client
 func NewClient(appID string, jwtGenerator *vonage.JWTGenerator, opts ...ClientOption) *Client {
 	c := &Client{
+		baseURL:      BaseURL,
 		appID:        appID,
 		jwtGenerator: jwtGenerator,
 		httpClient:   &http.Client{Timeout: 30 * time.Second},
-		sessions:     make(map[string]*Session),
+		logger:       vonage.NopLogger(),
+		store:        NewMemorySessionStore(),
+		spotCalls:    make(map[string]*spotCall),
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	if c.transport == nil {
+		c.transport = vonage.NewTransport(c.baseURL, jwtGenerator,
+			vonage.WithTransportHTTPClient(c.httpClient),
+			vonage.WithMiddleware(c.middleware...),
+			vonage.WithTransportLogger(c.logger),
+			vonage.WithTransportUserAgentSuffix(c.uaSuffix),
+			vonage.WithTransportMode(c.mode),
+		)
+	}
+
 	return c
 }
 
@@ -66,8 +207,8 @@ func NewClientFromCredentials(creds *vonage.Credentials, opts ...ClientOption) (
 		return nil, vonage.ErrNotConfigured
 	}
 
-	jwtGen := vonage.NewJWTGenerator(creds.AppID, creds.PrivateKey)
-	return NewClient(creds.AppID, jwtGen, opts...), nil
+	jwtGen := vonage.NewJWTGeneratorFromCredentials(creds)
+	return NewClient(creds.AppID, jwtGen, append([]ClientOption{WithMode(creds.Mode)}, opts...)...), nil
 }
 
 // IsConfigured returns true if the client has valid credentials
@@ -80,75 +221,130 @@ func (c *Client) AppID() string {
 	return c.appID
 }
 
-// CreateSession creates a new video session
-func (c *Client) CreateSession(opts *CreateSessionOptions) (*Session, error) {
-	if !c.IsConfigured() {
-		log.Warn().Msg("Vonage Video API not configured, using mock session")
-		return c.createMockSession("")
-	}
-
-	session, err := c.createSessionViaAPI(opts)
+// CreateSession creates a new video session. With WithMockFallback, a
+// substituted mock session comes with a *MockSessionError.
+func (c *Client) CreateSession(ctx context.Context, opts *CreateSessionOptions) (*Session, error) {
+	session, err := c.newSession(ctx, "", opts)
 	if err != nil {
-		log.Warn().Err(err).Msg("Failed to create session via API, using mock session")
-		return c.createMockSession("")
+		return session, err
 	}
 
-	// Cache the session
-	c.mu.Lock()
-	c.sessions[session.SessionID] = session
-	c.mu.Unlock()
-
-	log.Info().Str("sessionID", session.SessionID).Msg("Created Vonage Video session")
+	if !session.IsMock {
+		c.logger.Info("Created Vonage Video session", "sessionID", session.SessionID)
+	}
 	return session, nil
 }
 
-// CreateSessionForSpot creates a session associated with a specific spot
-func (c *Client) CreateSessionForSpot(spotID string, opts *CreateSessionOptions) (*Session, error) {
+// CreateSessionForSpot creates a session associated with a specific spot.
+// Concurrent calls for the same spot share a single API request, so a spot
+// never ends up with two sessions. The shared request is detached from the
+// caller that started it: a canceled caller returns its own ctx.Err() while
+// the others still get the session. Store errors other than
+// vonage.ErrSessionNotFound are returned rather than creating a session.
+func (c *Client) CreateSessionForSpot(ctx context.Context, spotID string, opts *CreateSessionOptions) (*Session, error) {
 	// Check cache first
-	c.mu.RLock()
-	for _, session := range c.sessions {
-		if session.SpotID == spotID && session.IsValid() {
-			c.mu.RUnlock()
-			return session, nil
-		}
+	session, err := c.store.FindBySpot(ctx, spotID)
+	if err == nil {
+		return session, c.checkMock(session, nil)
+	}
+	if !errors.Is(err, vonage.ErrSessionNotFound) {
+		return nil, fmt.Errorf("failed to find session: %w", err)
+	}
+
+	c.spotCallsMu.Lock()
+	call, ok := c.spotCalls[spotID]
+	if !ok {
+		call = &spotCall{done: make(chan struct{})}
+		c.spotCalls[spotID] = call
+		flightCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), spotCallTimeout)
+		go func() {
+			defer cancel()
+			c.runSpotCall(flightCtx, call, spotID, opts)
+		}()
+	}
+	c.spotCallsMu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.session, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// runSpotCall performs a shared CreateSessionForSpot and wakes its callers
+func (c *Client) runSpotCall(ctx context.Context, call *spotCall, spotID string, opts *CreateSessionOptions) {
+	defer func() {
+		c.spotCallsMu.Lock()
+		delete(c.spotCalls, spotID)
+		c.spotCallsMu.Unlock()
+		close(call.done)
+	}()
+
+	// Another flight may have finished between the cache check and now
+	session, err := c.store.FindBySpot(ctx, spotID)
+	if err == nil {
+		call.session, call.err = session, c.checkMock(session, nil)
+		return
+	}
+	if !errors.Is(err, vonage.ErrSessionNotFound) {
+		call.err = fmt.Errorf("failed to find session: %w", err)
+		return
+	}
+
+	call.session, call.err = c.newSession(ctx, spotID, opts)
+	if call.err == nil && !call.session.IsMock {
+		c.logger.Info("Created Vonage Video session for spot",
+			"sessionID", call.session.SessionID,
+			"spotID", spotID,
+		)
+	}
+}
+
+// newSession creates and caches a session via the API, falling back to a
+// mock session and a *MockSessionError only when WithMockFallback is set
+func (c *Client) newSession(ctx context.Context, spotID string, opts *CreateSessionOptions) (*Session, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	if c.mode == vonage.ModeMock {
+		return c.createMockSession(ctx, spotID, opts)
 	}
-	c.mu.RUnlock()
 
 	if !c.IsConfigured() {
-		log.Warn().Msg("Vonage Video API not configured, using mock session")
-		return c.createMockSession(spotID)
+		if !c.mockFallback {
+			return nil, vonage.ErrNotConfigured
+		}
+		c.logger.Warn("Vonage Video API not configured, using mock session")
+		return c.fallbackSession(ctx, spotID, opts, vonage.ErrNotConfigured)
 	}
 
-	session, err := c.createSessionViaAPI(opts)
+	session, err := c.createSessionViaAPI(ctx, opts)
 	if err != nil {
-		log.Warn().Err(err).Msg("Failed to create session via API, using mock session")
-		return c.createMockSession(spotID)
+		if !c.mockFallback {
+			return nil, err
+		}
+		c.logger.Warn("Failed to create session via API, using mock session", "error", err)
+		return c.fallbackSession(ctx, spotID, opts, err)
 	}
 
 	session.SpotID = spotID
+	if opts != nil {
+		session.MaxParticipants = opts.MaxParticipants
+		session.Metadata = copyMetadata(opts.Metadata)
+	}
 
 	// Cache the session
-	c.mu.Lock()
-	c.sessions[session.SessionID] = session
-	c.mu.Unlock()
-
-	log.Info().
-		Str("sessionID", session.SessionID).
-		Str("spotID", spotID).
-		Msg("Created Vonage Video session for spot")
+	if err := c.store.Put(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to store session: %w", err)
+	}
 
 	return session, nil
 }
 
 // createSessionViaAPI calls the Vonage Video API to create a session
-func (c *Client) createSessionViaAPI(opts *CreateSessionOptions) (*Session, error) {
-	apiJWT, err := c.jwtGenerator.GenerateAPIJWT()
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate API JWT: %w", err)
-	}
-
-	apiURL := fmt.Sprintf("%s/session/create", BaseURL)
-
+func (c *Client) createSessionViaAPI(ctx context.Context, opts *CreateSessionOptions) (*Session, error) {
 	// Build form data for session options
 	formData := url.Values{}
 	if opts != nil {
@@ -161,40 +357,14 @@ func (c *Client) createSessionViaAPI(opts *CreateSessionOptions) (*Session, erro
 		if opts.ArchiveMode != "" {
 			formData.Set("archiveMode", string(opts.ArchiveMode))
 		}
-	}
-
-	var req *http.Request
-	if len(formData) > 0 {
-		req, err = http.NewRequest("POST", apiURL, strings.NewReader(formData.Encode()))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	} else {
-		req, err = http.NewRequest("POST", apiURL, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
+		if opts.E2EE {
+			formData.Set("e2ee", "true")
 		}
 	}
 
-	req.Header.Set("Authorization", "Bearer "+apiJWT)
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("API request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusOK {
-		log.Error().
-			Int("status", resp.StatusCode).
-			Str("body", string(body)).
-			Str("url", apiURL).
-			Msg("Vonage Video API error")
-		return nil, vonage.NewError(resp.StatusCode, string(body))
+	var body json.RawMessage
+	if err := c.transport.Do(ctx, http.MethodPost, "/session/create", formData, &body); err != nil {
+		return nil, err
 	}
 
 	// Response is an array of session objects
@@ -203,10 +373,10 @@ func (c *Client) createSessionViaAPI(opts *CreateSessionOptions) (*Session, erro
 		// Try single object response
 		var single CreateSessionResponse
 		if err := json.Unmarshal(body, &single); err != nil {
-			log.Error().
-				Str("body", string(body)).
-				Err(err).
-				Msg("Failed to parse Vonage Video API response")
+			c.logger.Error("Failed to parse Vonage Video API response",
+				"body", string(body),
+				"error", err,
+			)
 			return nil, fmt.Errorf("failed to parse response: %w", err)
 		}
 		results = []CreateSessionResponse{single}
@@ -220,93 +390,138 @@ func (c *Client) createSessionViaAPI(opts *CreateSessionOptions) (*Session, erro
 		SessionID: results[0].SessionID,
 		ProjectID: results[0].ProjectID,
 		CreatedAt: time.Now(),
-		ExpiresAt: time.Now().Add(DefaultSessionTTL),
+		ExpiresAt: time.Now().Add(opts.ttl()),
+		E2EE:      opts != nil && opts.E2EE,
 	}, nil
 }
 
+// fallbackSession creates a mock session in place of a real one that
+// could not be created because of cause
+func (c *Client) fallbackSession(ctx context.Context, spotID string, opts *CreateSessionOptions, cause error) (*Session, error) {
+	session, err := c.createMockSession(ctx, spotID, opts)
+	if err != nil {
+		return nil, err
+	}
+	return session, &MockSessionError{SessionID: session.SessionID, Cause: cause}
+}
+
+// checkMock returns a *MockSessionError for a mock session, unless the
+// client runs in vonage.ModeMock where mocks are expected
+func (c *Client) checkMock(session *Session, cause error) error {
+	if !session.IsMock || c.mode == vonage.ModeMock {
+		return nil
+	}
+	return &MockSessionError{SessionID: session.SessionID, Cause: cause}
+}
+
 // createMockSession creates a mock session for development/testing
-func (c *Client) createMockSession(spotID string) (*Session, error) {
+func (c *Client) createMockSession(ctx context.Context, spotID string, opts *CreateSessionOptions) (*Session, error) {
 	appIDPrefix := "mock"
 	if len(c.appID) >= 8 {
 		appIDPrefix = c.appID[:8]
 	}
 	sessionID := fmt.Sprintf("mock_%s_%d", appIDPrefix, time.Now().UnixNano())
 
-	log.Info().
-		Str("sessionID", sessionID).
-		Str("spotID", spotID).
-		Msg("Created mock video session")
+	c.logger.Info("Created mock video session", "sessionID", sessionID, "spotID", spotID)
 
 	session := &Session{
 		SessionID: sessionID,
 		SpotID:    spotID,
 		CreatedAt: time.Now(),
-		ExpiresAt: time.Now().Add(DefaultSessionTTL),
+		ExpiresAt: time.Now().Add(opts.ttl()),
 		IsMock:    true,
 	}
+	if opts != nil {
+		session.MaxParticipants = opts.MaxParticipants
+		session.E2EE = opts.E2EE
+		session.Metadata = copyMetadata(opts.Metadata)
+	}
 
-	c.mu.Lock()
-	c.sessions[sessionID] = session
-	c.mu.Unlock()
+	if err := c.store.Put(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to store session: %w", err)
+	}
 
 	return session, nil
 }
 
-// GetSession retrieves a cached session by ID
-func (c *Client) GetSession(sessionID string) (*Session, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	session, ok := c.sessions[sessionID]
-	if !ok {
-		return nil, vonage.ErrSessionNotFound
+// GetSession retrieves a cached session by ID. It returns
+// vonage.ErrSessionNotFound or vonage.ErrSessionExpired if there is none,
+// and a mock session with a *MockSessionError.
+func (c *Client) GetSession(ctx context.Context, sessionID string) (*Session, error) {
+	session, err := c.store.Get(ctx, sessionID)
+	if err != nil {
+		return nil, err
 	}
 
 	if session.IsExpired() {
 		return nil, vonage.ErrSessionExpired
 	}
 
-	return session, nil
+	return session, c.checkMock(session, nil)
 }
 
-// GetOrCreateSession gets an existing session or creates a new one for a spot
-func (c *Client) GetOrCreateSession(spotID string, opts *CreateSessionOptions) (*Session, error) {
-	// Check cache first
-	c.mu.RLock()
-	for _, session := range c.sessions {
-		if session.SpotID == spotID && session.IsValid() {
-			c.mu.RUnlock()
-			return session, nil
-		}
-	}
-	c.mu.RUnlock()
+// FindSessionsByTag returns the valid cached sessions whose metadata has
+// key set to value
+func (c *Client) FindSessionsByTag(ctx context.Context, key, value string) ([]*Session, error) {
+	return c.store.FindByTag(ctx, key, value)
+}
 
-	return c.CreateSessionForSpot(spotID, opts)
+// GetOrCreateSession gets an existing session or creates a new one for a spot
+func (c *Client) GetOrCreateSession(ctx context.Context, spotID string, opts *CreateSessionOptions) (*Session, error) {
+	// CreateSessionForSpot checks the cache first
+	return c.CreateSessionForSpot(ctx, spotID, opts)
 }
 
 // CleanupExpiredSessions removes expired sessions from the cache
-func (c *Client) CleanupExpiredSessions() int {
+func (c *Client) CleanupExpiredSessions(ctx context.Context) int {
+	count, err := c.store.DeleteExpired(ctx)
+	if err != nil {
+		c.logger.Error("Failed to clean up expired video sessions", "error", err)
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	count := 0
-	for id, session := range c.sessions {
-		if session.IsExpired() {
-			delete(c.sessions, id)
-			count++
-		}
-	}
+	c.cleanupStats.Runs++
+	c.cleanupStats.Removed += int64(count)
+	c.cleanupStats.LastRun = time.Now()
+	c.cleanupStats.LastRemoved = count
 
 	if count > 0 {
-		log.Debug().Int("count", count).Msg("Cleaned up expired video sessions")
+		c.logger.Debug("Cleaned up expired video sessions", "count", count)
 	}
 
 	return count
 }
 
 // CachedSessionCount returns the number of cached sessions
-func (c *Client) CachedSessionCount() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return len(c.sessions)
+func (c *Client) CachedSessionCount(ctx context.Context) int {
+	count, err := c.store.Count(ctx)
+	if err != nil {
+		c.logger.Error("Failed to count cached video sessions", "error", err)
+	}
+	return count
+}
+
+// copyMetadata copies metadata so later changes to the options don't leak
+// into stored sessions
+func copyMetadata(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// ========================================
+// Health
+// ========================================
+
+// Ping checks the credentials and connectivity for readiness probes; it
+// lists one archive
+func (c *Client) Ping(ctx context.Context) *vonage.Health {
+	return c.transport.Ping(ctx, "video", c.projectPath("/archive?count=1"))
 }
@@ -0,0 +1,116 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// newSessionServer answers session creations once release is closed and
+// counts them; started receives a value as each request arrives
+func newSessionServer(t *testing.T, started chan<- struct{}, release <-chan struct{}) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		started <- struct{}{}
+		<-release
+		w.Write([]byte(`[{"session_id":"SESSION-1","project_id":"PROJECT-1"}]`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func newTestClient(baseURL string, opts ...ClientOption) *Client {
+	return NewClient("app-1", vonage.NewJWTGenerator("app-1", nil),
+		append([]ClientOption{WithTransport(vonage.NewTransport(baseURL, nil))}, opts...)...)
+}
+
+func TestCreateSessionForSpotLeaderCanceled(t *testing.T) {
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	srv, requests := newSessionServer(t, started, release)
+	c := newTestClient(srv.URL)
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := c.CreateSessionForSpot(leaderCtx, "spot-1", nil)
+		leaderErr <- err
+	}()
+	<-started
+
+	const waiters = 5
+	var wg sync.WaitGroup
+	sessions := make([]*Session, waiters)
+	errs := make([]error, waiters)
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sessions[i], errs[i] = c.CreateSessionForSpot(context.Background(), "spot-1", nil)
+		}(i)
+	}
+
+	// The caller that started the request gives up; the others still wait
+	cancelLeader()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader error = %v, want context.Canceled", err)
+	}
+	close(release)
+	wg.Wait()
+
+	for i := range sessions {
+		if errs[i] != nil || sessions[i] == nil || sessions[i].SessionID != "SESSION-1" {
+			t.Errorf("waiter %d = %v, %v, want SESSION-1", i, sessions[i], errs[i])
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("sent %d session creations, want 1", n)
+	}
+}
+
+// failingStore is a session store whose spot lookups fail
+type failingStore struct {
+	*MemorySessionStore
+	err error
+}
+
+func (s *failingStore) FindBySpot(ctx context.Context, spotID string) (*Session, error) {
+	return nil, s.err
+}
+
+func TestCreateSessionForSpotStoreErrors(t *testing.T) {
+	errStoreDown := errors.New("store down")
+
+	tests := []struct {
+		name         string
+		err          error
+		wantErr      error
+		wantRequests int32
+	}{
+		{"not found creates a session", vonage.ErrSessionNotFound, nil, 1},
+		{"store error is returned", errStoreDown, errStoreDown, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started, release := make(chan struct{}, 1), make(chan struct{})
+			close(release)
+			srv, requests := newSessionServer(t, started, release)
+			c := newTestClient(srv.URL, WithSessionStore(&failingStore{NewMemorySessionStore(), tt.err}))
+
+			_, err := c.CreateSessionForSpot(context.Background(), "spot-1", nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateSessionForSpot() = %v, want %v", err, tt.wantErr)
+			}
+			if n := requests.Load(); n != tt.wantRequests {
+				t.Errorf("sent %d session creations, want %d", n, tt.wantRequests)
+			}
+		})
+	}
+}
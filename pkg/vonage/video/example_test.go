@@ -1,11 +1,19 @@
 package video_test
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"time"
 
 	vonage "github.com/vonatrigger/poc/pkg/vonage"
 	"github.com/vonatrigger/poc/pkg/vonage/video"
+	"github.com/vonatrigger/poc/pkg/vonage/vonagemock"
 )
 
 func ExampleClient_basic() {
@@ -26,7 +34,7 @@ func ExampleClient_basic() {
 	}
 
 	// Create a session
-	session, err := client.CreateSession(nil)
+	session, err := client.CreateSession(context.Background(), nil)
 	if err != nil {
 		panic(err)
 	}
@@ -49,7 +57,7 @@ func ExampleClient_withOptions() {
 	client, _ := video.NewClientFromCredentials(creds)
 
 	// Create session with options
-	session, err := client.CreateSession(&video.CreateSessionOptions{
+	session, err := client.CreateSession(context.Background(), &video.CreateSessionOptions{
 		MediaMode:   video.MediaModeRouted,
 		ArchiveMode: video.ArchiveModeManual,
 	})
@@ -87,13 +95,438 @@ func ExampleClient_spotManagement() {
 	client, _ := video.NewClientFromCredentials(creds)
 
 	// Create or get existing session for a specific spot
-	session, err := client.GetOrCreateSession("spot-tokyo-tower", nil)
+	session, err := client.GetOrCreateSession(context.Background(), "spot-tokyo-tower", nil)
 	if err != nil {
 		panic(err)
 	}
 	fmt.Printf("Session for spot: %s\n", session.SessionID)
 
 	// Cleanup expired sessions periodically
-	cleaned := client.CleanupExpiredSessions()
+	cleaned := client.CleanupExpiredSessions(context.Background())
 	fmt.Printf("Cleaned up %d expired sessions\n", cleaned)
 }
+
+func ExampleClient_StartCleanup() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("your-app-id", "your-private-key-pem"),
+	)
+	client, _ := video.NewClientFromCredentials(creds)
+
+	// Sweep expired sessions every 10 minutes until shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client.StartCleanup(ctx, 10*time.Minute)
+
+	stats := client.CleanupStats()
+	fmt.Printf("runs=%d removed=%d\n", stats.Runs, stats.Removed)
+}
+
+func ExampleClient_archive() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("your-app-id", "your-private-key-pem"),
+	)
+	client, _ := video.NewClientFromCredentials(creds)
+	ctx := context.Background()
+
+	// Start a composed recording
+	archive, err := client.StartArchive(ctx, "session-id", &video.ArchiveOptions{
+		Name:       "spot-tokyo-tower",
+		OutputMode: video.OutputModeComposed,
+		Layout:     &video.Layout{Type: video.LayoutBestFit},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Switch layout while recording
+	_ = client.SetArchiveLayout(ctx, archive.ID, video.Layout{Type: video.LayoutPIP})
+
+	// Stop and fetch the download URL once available
+	_, _ = client.StopArchive(ctx, archive.ID)
+	archive, _ = client.GetArchive(ctx, archive.ID)
+	if archive.IsAvailable() {
+		fmt.Printf("Download: %s (%s)\n", archive.URL, archive.DurationTime())
+	}
+
+	// List recordings for a session
+	list, _ := client.ListArchives(ctx, &video.ListOptions{SessionID: "session-id"})
+	fmt.Printf("%d archives\n", list.Count)
+}
+
+func ExampleClient_broadcast() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("your-app-id", "your-private-key-pem"),
+	)
+	client, _ := video.NewClientFromCredentials(creds)
+	ctx := context.Background()
+
+	// Stream to HLS and YouTube Live at the same time
+	broadcast, err := client.StartBroadcast(ctx, "session-id", &video.BroadcastOptions{
+		Outputs: video.BroadcastOutputs{
+			HLS: &video.HLSConfig{LowLatency: true},
+			RTMP: []video.RTMPConfig{{
+				ID:         "youtube",
+				ServerURL:  "rtmp://a.rtmp.youtube.com/live2",
+				StreamName: "stream-key",
+			}},
+		},
+		Layout:     &video.Layout{Type: video.LayoutBestFit},
+		Resolution: "1280x720",
+	})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("HLS: %s\n", broadcast.BroadcastURLs.HLS)
+
+	_, _ = client.StopBroadcast(ctx, broadcast.ID)
+}
+
+func ExampleClient_moderation() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("your-app-id", "your-private-key-pem"),
+	)
+	client, _ := video.NewClientFromCredentials(creds)
+	ctx := context.Background()
+
+	// Mute everyone except the host, then remove a misbehaving participant
+	_ = client.MuteAllStreams(ctx, "session-id", "host-stream-id")
+	_ = client.ForceDisconnect(ctx, "session-id", "connection-id")
+
+	// Allow participants to unmute again
+	_ = client.DisableForceMute(ctx, "session-id")
+}
+
+func ExampleClient_Dial() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("your-app-id", "your-private-key-pem"),
+	)
+	client, _ := video.NewClientFromCredentials(creds)
+	tokenGen := video.NewTokenGenerator(creds.AppID, vonage.NewJWTGenerator(creds.AppID, creds.PrivateKey))
+	ctx := context.Background()
+
+	token, _ := tokenGen.GeneratePublisherToken("session-id", "phone-participant")
+
+	// Let a phone participant join the video room
+	call, err := client.Dial(ctx, "session-id", token.Token, "sip:+81501234567@sip.example.com;transport=tls", &video.SIPOptions{
+		From:   "81501234567",
+		Secure: true,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Navigate an IVR on the far end, then hang up
+	_ = client.PlayDTMF(ctx, "session-id", call.ConnectionID, "1p2#")
+	_ = client.HangupSIPCall(ctx, "session-id", call)
+}
+
+func ExampleClient_StartRender() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("your-app-id", "your-private-key-pem"),
+	)
+	client, _ := video.NewClientFromCredentials(creds)
+	tokenGen := video.NewTokenGenerator(creds.AppID, vonage.NewJWTGenerator(creds.AppID, creds.PrivateKey))
+	ctx := context.Background()
+
+	token, _ := tokenGen.GeneratePublisherToken("session-id", "game-overlay")
+
+	// Publish the game scoreboard page into the room
+	render, err := client.StartRender(ctx, "session-id", token.Token, "https://example.com/overlay/scoreboard", &video.RenderOptions{
+		Resolution: "1280x720",
+		Properties: &video.RenderProperties{Name: "Scoreboard"},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	_ = client.StopRender(ctx, render.ID)
+}
+
+func ExampleClient_EnableCaptions() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("your-app-id", "your-private-key-pem"),
+	)
+	client, _ := video.NewClientFromCredentials(creds)
+	tokenGen := video.NewTokenGenerator(creds.AppID, vonage.NewJWTGenerator(creds.AppID, creds.PrivateKey))
+	ctx := context.Background()
+
+	token, _ := tokenGen.GenerateModeratorToken("session-id", "captions-bot")
+
+	partial := false
+	captionsID, err := client.EnableCaptions(ctx, "session-id", token.Token, &video.CaptionsOptions{
+		LanguageCode:    "en-US",
+		PartialCaptions: &partial,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	status, _ := client.GetCaptionsStatus(ctx, captionsID)
+	fmt.Printf("Captions: %s\n", status.Status)
+
+	_ = client.DisableCaptions(ctx, captionsID)
+}
+
+func ExampleVerifyToken() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("your-app-id", "your-private-key-pem"),
+	)
+
+	// Token sent back by a client, e.g. in an API request header
+	tokenString := "eyJhbGciOiJSUzI1NiIs..."
+
+	claims, err := video.VerifyToken(tokenString, creds.AppID, creds.PrivateKey.Public())
+	if err != nil {
+		panic(err)
+	}
+	if err := claims.CheckSession("session-id"); err != nil {
+		panic(err)
+	}
+	if err := claims.CheckRole(video.RoleModerator); err != nil {
+		fmt.Println("moderator permission required")
+	}
+}
+
+func ExampleClient_SetArchiveStorage() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("your-app-id", "your-private-key-pem"),
+	)
+	client, _ := video.NewClientFromCredentials(creds)
+	ctx := context.Background()
+
+	// Upload completed archives to our own bucket
+	_ = client.SetArchiveStorage(ctx, video.NewS3Storage(video.S3StorageConfig{
+		AccessKey: "AKIA...",
+		SecretKey: "secret",
+		Bucket:    "vonatrigger-archives",
+	}, video.StorageFallbackVonage))
+
+	// Track room occupancy
+	for _, group := range []video.CallbackGroup{video.CallbackGroupConnection, video.CallbackGroupStream} {
+		for _, event := range []video.CallbackEvent{video.CallbackEventCreated, video.CallbackEventDestroyed} {
+			_, _ = client.RegisterCallback(ctx, group, event, "https://example.com/webhooks/video")
+		}
+	}
+}
+
+func ExampleWebhookHandler() {
+	occupancy := video.NewOccupancyTracker()
+
+	handler := occupancy.Attach(video.NewWebhookHandler()).
+		OnArchiveStatus(func(archive *video.Archive) error {
+			if archive.IsAvailable() {
+				fmt.Printf("Archive %s ready: %s\n", archive.ID, archive.URL)
+			}
+			return nil
+		})
+
+	// http.HandleFunc("/webhooks/video", handler.Handle())
+	_ = handler
+
+	fmt.Printf("Participants: %d\n", occupancy.Connections("session-id"))
+}
+
+func ExampleTokenGenerator_clockSkew() {
+	// Hosts whose clocks run slightly fast: backdate iat/nbf by 30 seconds
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("your-app-id", "your-private-key-pem"),
+		vonage.WithClockSkew(30*time.Second),
+	)
+
+	tokenGen := video.NewTokenGenerator(creds.AppID, vonage.NewJWTGeneratorFromCredentials(creds))
+	token, _ := tokenGen.GeneratePublisherToken("session-id", "user-123")
+	fmt.Printf("Token: %s\n", token.Token)
+}
+
+func ExampleClient_capacity() {
+	ctx := context.Background()
+	occupancy := video.NewOccupancyTracker()
+
+	// Share sessions between instances by plugging in a custom SessionStore
+	client := video.NewClient("app-id", nil,
+		video.WithMockFallback(),
+		video.WithSessionStore(video.NewMemorySessionStore()),
+	)
+
+	// Concurrent calls for the same spot share one session. Without
+	// credentials the client falls back to a mock session, which this
+	// development setup accepts.
+	session, err := client.CreateSessionForSpot(ctx, "spot-123", &video.CreateSessionOptions{
+		MaxParticipants: 4,
+	})
+	if err != nil && !errors.Is(err, video.ErrMockSession) {
+		fmt.Println(err)
+		return
+	}
+
+	if err := session.CheckCapacity(occupancy.Connections(session.SessionID)); err != nil {
+		fmt.Println("Spot is full")
+		return
+	}
+	fmt.Println("Room available")
+	// Output: Room available
+}
+
+func ExampleWithClaimsInterceptor() {
+	var jwtGen *vonage.JWTGenerator // from vonage.NewJWTGeneratorFromCredentials
+
+	tokenGen := video.NewTokenGenerator("your-app-id", jwtGen,
+		// Attach connection metadata to every token
+		video.WithClaimsInterceptor(func(claims vonage.JWTClaims) {
+			claims["data"] = `{"tenant":"acme"}`
+		}),
+	)
+
+	_, err := tokenGen.GenerateToken("session-id", "user-123", video.TokenOptions{
+		Data: strings.Repeat("x", 2000),
+	})
+
+	var tooLong *video.DataTooLongError
+	if errors.As(err, &tooLong) {
+		fmt.Printf("Data too long: %d > %d\n", tooLong.Length, tooLong.Max)
+	}
+	// Output: Data too long: 2000 > 1000
+}
+
+func ExampleCreateSessionOptions_Validate() {
+	opts := &video.CreateSessionOptions{
+		MediaMode: video.MediaModeRelayed,
+		E2EE:      true,
+	}
+
+	if err := opts.Validate(); errors.Is(err, video.ErrE2EERelayed) {
+		fmt.Println("E2EE needs routed media")
+	}
+	// Output: E2EE needs routed media
+}
+
+func ExampleWithRequestInterceptor() {
+	errInjected := errors.New("injected fault")
+
+	client := video.NewClient("app-id", nil,
+		// Tag every request for the API gateway
+		video.WithRequestInterceptor(func(req *http.Request) error {
+			req.Header.Set("X-Request-Source", "checkin-service")
+			return nil
+		}),
+		// Simulate an outage of the archive API in tests
+		video.WithRequestInterceptor(func(req *http.Request) error {
+			if strings.Contains(req.URL.Path, "/archive") {
+				return errInjected
+			}
+			return nil
+		}),
+	)
+	_ = client
+}
+
+func ExampleClient_FindSessionsByTag() {
+	ctx := context.Background()
+	client := video.NewClient("app-id", nil, video.WithMode(vonage.ModeMock))
+
+	for _, spot := range []struct{ id, area string }{
+		{"spot-shibuya", "tokyo"},
+		{"spot-shinjuku", "tokyo"},
+		{"spot-umeda", "osaka"},
+	} {
+		_, err := client.CreateSessionForSpot(ctx, spot.id, &video.CreateSessionOptions{
+			Metadata: map[string]string{"area": spot.area, "event": "summer-rally"},
+			// Rally sessions only live for the afternoon
+			TTL: 6 * time.Hour,
+		})
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
+	sessions, _ := client.FindSessionsByTag(ctx, "area", "tokyo")
+	fmt.Println(len(sessions), "sessions in tokyo")
+	// Output: 2 sessions in tokyo
+}
+
+func ExampleTokenRevoker() {
+	// The stub stands in for the video client's moderation API
+	moderation := &vonagemock.Video{
+		ForceDisconnectFunc: func(ctx context.Context, sessionID, connectionID string) error {
+			fmt.Println("disconnected", connectionID, "from", sessionID)
+			return nil
+		},
+	}
+	revoker := video.NewTokenRevoker(moderation)
+
+	// Track connections from session monitoring callbacks, and reject
+	// revoked tokens on your own endpoints
+	var api http.Handler = http.NotFoundHandler()
+	mux := http.NewServeMux()
+	mux.Handle("/webhooks/video", revoker.Attach(video.NewWebhookHandler()).Handle())
+	mux.Handle("/api/", revoker.Middleware(api))
+
+	// A player joins (normally delivered to the webhook)
+	revoker.Observe(&video.SessionEvent{
+		SessionID:  "session-1",
+		Event:      video.EventConnectionCreated,
+		Connection: &video.Connection{ID: "conn-1", Data: "player-42"},
+	})
+
+	// Ban the player: they are ejected and cannot get a new token
+	if err := revoker.RevokeUser(context.Background(), "player-42"); err != nil {
+		fmt.Println(err)
+	}
+	tokenGen := video.NewTokenGenerator("app-id", nil, video.WithTokenRevoker(revoker))
+	_, err := tokenGen.GeneratePublisherToken("session-1", "player-42")
+	fmt.Println(errors.Is(err, video.ErrTokenRevoked))
+	// Output:
+	// disconnected conn-1 from session-1
+	// true
+}
+
+// memoryStorage is a Storage keeping uploads in memory
+type memoryStorage map[string][]byte
+
+func (m memoryStorage) Put(ctx context.Context, key string, r io.Reader, size int64) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	m[key] = data
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func ExampleArchivePipeline() {
+	// The stub stands in for the video client
+	archives := &vonagemock.Video{
+		DownloadArchiveFunc: func(ctx context.Context, archive *video.Archive, w io.Writer, opts ...vonage.DownloadOption) (int64, error) {
+			n, err := io.WriteString(w, "recording")
+			return int64(n), err
+		},
+		DeleteArchiveFunc: func(ctx context.Context, archiveID string) error {
+			return nil
+		},
+	}
+	storage := memoryStorage{}
+
+	pipeline := video.NewArchivePipeline(archives, storage,
+		video.WithDeleteAfterUpload(),
+		video.WithArchiveResult(func(u *video.ArchiveUpload) {
+			fmt.Println(u.Key, u.Size, u.Deleted, u.Err)
+		}),
+	)
+	mux := http.NewServeMux()
+	mux.Handle("/webhooks/archive", pipeline.Attach(video.NewWebhookHandler()).Handle())
+
+	// Delivered by the archive status callback
+	pipeline.Observe(&video.Archive{
+		ID:        "archive-1",
+		SessionID: "session-1",
+		Status:    video.ArchiveStatusAvailable,
+		Size:      9,
+		URL:       "https://example.com/archive-1.mp4",
+	})
+	pipeline.Wait()
+	fmt.Println(string(storage["session-1/archive-1.mp4"]))
+	// Output:
+	// session-1/archive-1.mp4 9 true <nil>
+	// recording
+}
@@ -0,0 +1,65 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ========================================
+// Moderation API
+// ========================================
+
+// ForceDisconnect removes a connection from a session
+func (c *Client) ForceDisconnect(ctx context.Context, sessionID, connectionID string) error {
+	path := fmt.Sprintf("/session/%s/connection/%s", url.PathEscape(sessionID), url.PathEscape(connectionID))
+	if err := c.doJSON(ctx, http.MethodDelete, c.projectPath(path), nil, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Force-disconnected Vonage Video connection",
+		"sessionID", sessionID,
+		"connectionID", connectionID,
+	)
+
+	return nil
+}
+
+// MuteStream mutes the audio of a single published stream
+func (c *Client) MuteStream(ctx context.Context, sessionID, streamID string) error {
+	path := fmt.Sprintf("/session/%s/stream/%s/mute", url.PathEscape(sessionID), url.PathEscape(streamID))
+	if err := c.doJSON(ctx, http.MethodPost, c.projectPath(path), nil, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Muted Vonage Video stream", "sessionID", sessionID, "streamID", streamID)
+
+	return nil
+}
+
+// MuteAllStreams mutes every stream in a session except the excluded ones,
+// and keeps streams published later muted until DisableForceMute is called
+func (c *Client) MuteAllStreams(ctx context.Context, sessionID string, excludedStreamIDs ...string) error {
+	if err := c.setForceMute(ctx, sessionID, true, excludedStreamIDs); err != nil {
+		return err
+	}
+
+	c.logger.Info("Muted all Vonage Video streams",
+		"sessionID", sessionID,
+		"excluded", len(excludedStreamIDs),
+	)
+
+	return nil
+}
+
+// DisableForceMute lets participants unmute again after MuteAllStreams
+func (c *Client) DisableForceMute(ctx context.Context, sessionID string) error {
+	return c.setForceMute(ctx, sessionID, false, nil)
+}
+
+func (c *Client) setForceMute(ctx context.Context, sessionID string, active bool, excluded []string) error {
+	req := forceMuteRequest{Active: active, ExcludedStreamIDs: excluded}
+	path := fmt.Sprintf("/session/%s/mute", url.PathEscape(sessionID))
+	return c.doJSON(ctx, http.MethodPost, c.projectPath(path), req, nil)
+}
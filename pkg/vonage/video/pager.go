@@ -0,0 +1,73 @@
+package video
+
+import (
+	"context"
+	"strconv"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// Pagination
+// ========================================
+
+// NewArchivePager returns a pager over the archives matching opts, from
+// opts.Offset on, with opts.Count archives per page
+func NewArchivePager(c API, opts *ListOptions, pagerOpts ...vonage.PagerOption) *vonage.Pager[Archive] {
+	return newOffsetPager(opts, func(ctx context.Context, o *ListOptions) ([]Archive, int, error) {
+		list, err := c.ListArchives(ctx, o)
+		if err != nil {
+			return nil, 0, err
+		}
+		return list.Items, list.Count, nil
+	}, pagerOpts)
+}
+
+// NewBroadcastPager returns a pager over the broadcasts matching opts, from
+// opts.Offset on, with opts.Count broadcasts per page
+func NewBroadcastPager(c API, opts *ListOptions, pagerOpts ...vonage.PagerOption) *vonage.Pager[Broadcast] {
+	return newOffsetPager(opts, func(ctx context.Context, o *ListOptions) ([]Broadcast, int, error) {
+		list, err := c.ListBroadcasts(ctx, o)
+		if err != nil {
+			return nil, 0, err
+		}
+		return list.Items, list.Count, nil
+	}, pagerOpts)
+}
+
+// NewRenderPager returns a pager over the renders, from opts.Offset on,
+// with opts.Count renders per page
+func NewRenderPager(c API, opts *ListOptions, pagerOpts ...vonage.PagerOption) *vonage.Pager[Render] {
+	return newOffsetPager(opts, func(ctx context.Context, o *ListOptions) ([]Render, int, error) {
+		list, err := c.ListRenders(ctx, o)
+		if err != nil {
+			return nil, 0, err
+		}
+		return list.Items, list.Count, nil
+	}, pagerOpts)
+}
+
+// newOffsetPager pages an offset-based list endpoint; the pager token is
+// the offset of the next page
+func newOffsetPager[T any](opts *ListOptions, list func(ctx context.Context, o *ListOptions) ([]T, int, error), pagerOpts []vonage.PagerOption) *vonage.Pager[T] {
+	var base ListOptions
+	if opts != nil {
+		base = *opts
+	}
+	return vonage.NewPager(func(ctx context.Context, token string) ([]T, string, error) {
+		o := base
+		if token != "" {
+			o.Offset, _ = strconv.Atoi(token)
+		}
+		items, total, err := list(ctx, &o)
+		if err != nil {
+			return nil, "", err
+		}
+
+		next := o.Offset + len(items)
+		if len(items) == 0 || next >= total {
+			return items, "", nil
+		}
+		return items, strconv.Itoa(next), nil
+	}, pagerOpts...)
+}
@@ -0,0 +1,295 @@
+package video
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// Archive Upload Pipeline
+// ========================================
+
+// Pipeline defaults
+const (
+	DefaultPipelineAttempts = 3
+	DefaultPipelineBackoff  = 5 * time.Second
+)
+
+// ErrChecksumMismatch is returned when the storage reports a different
+// checksum than the downloaded archive's
+var ErrChecksumMismatch = errors.New("video: archive checksum mismatch")
+
+// Storage is where an ArchivePipeline uploads archives, e.g. a GCS bucket
+// or a CDN origin. Put stores size bytes read from r under key and returns
+// the hex SHA-256 digest of what it stored, which the pipeline compares
+// with the download's.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64) (sha256 string, err error)
+}
+
+// ArchiveUpload is the result of moving one archive to storage
+type ArchiveUpload struct {
+	Archive *Archive
+	Key     string
+	Size    int64
+	// SHA256 is the hex digest of the archive file
+	SHA256   string
+	Attempts int
+	// Deleted is true if the Vonage copy was deleted
+	Deleted bool
+	Err     error
+}
+
+// ArchivePipeline moves completed archives from Vonage to a Storage. Feed
+// it archive status callbacks with Attach or Observe; each available
+// archive is downloaded to a temporary file, uploaded with retries and
+// checksum verification, and optionally deleted from Vonage.
+type ArchivePipeline struct {
+	archives ArchiveAPI
+	storage  Storage
+
+	keyFunc    func(*Archive) string
+	attempts   int
+	backoff    time.Duration
+	tempDir    string
+	deleteCopy bool
+	onResult   func(*ArchiveUpload)
+	logger     vonage.Logger
+
+	mu      sync.Mutex
+	handled map[string]bool
+	wg      sync.WaitGroup
+}
+
+// ArchivePipelineOption is a functional option for configuring an
+// ArchivePipeline
+type ArchivePipelineOption func(*ArchivePipeline)
+
+// WithArchiveKey sets the storage key of an archive (default
+// "<sessionID>/<archiveID>.mp4", ".zip" for individual stream archives)
+func WithArchiveKey(fn func(*Archive) string) ArchivePipelineOption {
+	return func(p *ArchivePipeline) {
+		p.keyFunc = fn
+	}
+}
+
+// WithArchiveRetry sets how many times an archive is attempted and the
+// first backoff, which doubles on each retry (default
+// DefaultPipelineAttempts and DefaultPipelineBackoff)
+func WithArchiveRetry(attempts int, backoff time.Duration) ArchivePipelineOption {
+	return func(p *ArchivePipeline) {
+		p.attempts = attempts
+		p.backoff = backoff
+	}
+}
+
+// WithArchiveTempDir sets the directory archives are downloaded to
+// (default os.TempDir)
+func WithArchiveTempDir(dir string) ArchivePipelineOption {
+	return func(p *ArchivePipeline) {
+		p.tempDir = dir
+	}
+}
+
+// WithDeleteAfterUpload deletes the Vonage copy of each archive once it is
+// verified in storage
+func WithDeleteAfterUpload() ArchivePipelineOption {
+	return func(p *ArchivePipeline) {
+		p.deleteCopy = true
+	}
+}
+
+// WithArchiveResult calls fn with the result of each archive, e.g. to
+// record the storage key or alert on failures
+func WithArchiveResult(fn func(*ArchiveUpload)) ArchivePipelineOption {
+	return func(p *ArchivePipeline) {
+		p.onResult = fn
+	}
+}
+
+// WithPipelineLogger sets the logger (default: no logging)
+func WithPipelineLogger(l vonage.Logger) ArchivePipelineOption {
+	return func(p *ArchivePipeline) {
+		p.logger = l
+	}
+}
+
+// NewArchivePipeline creates a pipeline from archives, usually the video
+// *Client, to storage
+func NewArchivePipeline(archives ArchiveAPI, storage Storage, opts ...ArchivePipelineOption) *ArchivePipeline {
+	p := &ArchivePipeline{
+		archives: archives,
+		storage:  storage,
+		keyFunc:  defaultArchiveKey,
+		attempts: DefaultPipelineAttempts,
+		backoff:  DefaultPipelineBackoff,
+		logger:   vonage.NopLogger(),
+		handled:  make(map[string]bool),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Attach registers the pipeline as the archive status handler of h.
+// Handlers registered on h afterwards replace it.
+func (p *ArchivePipeline) Attach(h *WebhookHandler) *WebhookHandler {
+	return h.OnArchiveStatus(p.Observe)
+}
+
+// Observe starts processing an available archive in the background, so the
+// webhook is answered at once. Other statuses and archives already being
+// or successfully processed (Vonage retries callbacks) are ignored. Use
+// Wait to drain.
+func (p *ArchivePipeline) Observe(archive *Archive) error {
+	if archive.Status != ArchiveStatusAvailable {
+		return nil
+	}
+
+	p.mu.Lock()
+	if p.handled[archive.ID] {
+		p.mu.Unlock()
+		return nil
+	}
+	p.handled[archive.ID] = true
+	p.mu.Unlock()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		if result := p.Process(context.Background(), archive); result.Err != nil {
+			// Let a later callback for the archive try again
+			p.mu.Lock()
+			delete(p.handled, archive.ID)
+			p.mu.Unlock()
+		}
+	}()
+	return nil
+}
+
+// Wait blocks until the archives being processed are done
+func (p *ArchivePipeline) Wait() {
+	p.wg.Wait()
+}
+
+// Process moves one archive to storage, retrying failed attempts, and
+// reports the result to the WithArchiveResult callback
+func (p *ArchivePipeline) Process(ctx context.Context, archive *Archive) *ArchiveUpload {
+	result := &ArchiveUpload{Archive: archive, Key: p.keyFunc(archive)}
+
+	backoff := p.backoff
+	for result.Attempts < max(p.attempts, 1) {
+		if result.Attempts > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				result.Err = ctx.Err()
+				return p.finish(result)
+			}
+			backoff *= 2
+
+			// The download URL expires after ten minutes
+			fresh, err := p.archives.GetArchive(ctx, archive.ID)
+			if err == nil {
+				archive = fresh
+			}
+		}
+		result.Attempts++
+
+		result.Err = p.transfer(ctx, archive, result)
+		if result.Err == nil {
+			break
+		}
+		p.logger.Warn("Archive upload attempt failed",
+			"archiveID", archive.ID,
+			"attempt", result.Attempts,
+			"error", result.Err,
+		)
+	}
+
+	if result.Err == nil && p.deleteCopy {
+		if err := p.archives.DeleteArchive(ctx, archive.ID); err != nil {
+			result.Err = fmt.Errorf("archive uploaded but not deleted from Vonage: %w", err)
+		} else {
+			result.Deleted = true
+		}
+	}
+	return p.finish(result)
+}
+
+// transfer downloads an archive to a temporary file and uploads it
+func (p *ArchivePipeline) transfer(ctx context.Context, archive *Archive, result *ArchiveUpload) error {
+	f, err := os.CreateTemp(p.tempDir, "vonage-archive-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	hash := sha256.New()
+	size, err := p.archives.DownloadArchive(ctx, archive, io.MultiWriter(f, hash))
+	if err != nil {
+		return err
+	}
+	if archive.Size > 0 && size != archive.Size {
+		return fmt.Errorf("video: downloaded %d bytes of archive, expected %d", size, archive.Size)
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind archive file: %w", err)
+	}
+	stored, err := p.storage.Put(ctx, result.Key, f, size)
+	if err != nil {
+		return fmt.Errorf("storage upload failed: %w", err)
+	}
+	if stored != sum {
+		return fmt.Errorf("%w: downloaded %s, stored %s", ErrChecksumMismatch, sum, stored)
+	}
+
+	result.Size, result.SHA256 = size, sum
+	return nil
+}
+
+// finish logs and reports a result
+func (p *ArchivePipeline) finish(result *ArchiveUpload) *ArchiveUpload {
+	if result.Err != nil {
+		p.logger.Error("Failed to move archive to storage",
+			"archiveID", result.Archive.ID,
+			"attempts", result.Attempts,
+			"error", result.Err,
+		)
+	} else {
+		p.logger.Info("Moved archive to storage",
+			"archiveID", result.Archive.ID,
+			"key", result.Key,
+			"bytes", result.Size,
+		)
+	}
+	if p.onResult != nil {
+		p.onResult(result)
+	}
+	return result
+}
+
+// defaultArchiveKey returns "<sessionID>/<archiveID>.mp4", or ".zip" for
+// individual stream archives
+func defaultArchiveKey(a *Archive) string {
+	ext := ".mp4"
+	if a.OutputMode == OutputModeIndividual {
+		ext = ".zip"
+	}
+	return a.SessionID + "/" + a.ID + ext
+}
@@ -0,0 +1,62 @@
+package video
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ========================================
+// Project Management API
+// ========================================
+
+// SetArchiveStorage configures the S3 or Azure target that completed
+// archives are uploaded to
+func (c *Client) SetArchiveStorage(ctx context.Context, storage ArchiveStorage) error {
+	if err := c.doJSON(ctx, http.MethodPut, c.projectPath("/archive/storage"), storage, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Configured Vonage Video archive storage", "type", string(storage.Type))
+	return nil
+}
+
+// DeleteArchiveStorage removes the archive upload target; archives are then
+// kept in Vonage storage
+func (c *Client) DeleteArchiveStorage(ctx context.Context) error {
+	return c.doJSON(ctx, http.MethodDelete, c.projectPath("/archive/storage"), nil, nil)
+}
+
+// RegisterCallback registers a session monitoring callback URL for a
+// group/event pair
+func (c *Client) RegisterCallback(ctx context.Context, group CallbackGroup, event CallbackEvent, callbackURL string) (*Callback, error) {
+	req := Callback{Group: group, Event: event, URL: callbackURL}
+
+	var callback Callback
+	if err := c.doJSON(ctx, http.MethodPost, c.projectPath("/callback"), req, &callback); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Registered Vonage Video callback",
+		"callbackID", callback.ID,
+		"group", string(group),
+		"event", string(event),
+	)
+
+	return &callback, nil
+}
+
+// ListCallbacks lists the registered session monitoring callbacks
+func (c *Client) ListCallbacks(ctx context.Context) ([]Callback, error) {
+	var callbacks []Callback
+	if err := c.doJSON(ctx, http.MethodGet, c.projectPath("/callback"), nil, &callbacks); err != nil {
+		return nil, err
+	}
+	return callbacks, nil
+}
+
+// DeleteCallback unregisters a session monitoring callback
+func (c *Client) DeleteCallback(ctx context.Context, callbackID string) error {
+	path := "/callback/" + url.PathEscape(callbackID)
+	return c.doJSON(ctx, http.MethodDelete, c.projectPath(path), nil, nil)
+}
@@ -0,0 +1,67 @@
+package video
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ========================================
+// Experience Composer API
+// ========================================
+
+// StartRender starts an Experience Composer that loads the given web page
+// and publishes it into the session as a stream. The token must be valid
+// for the session.
+func (c *Client) StartRender(ctx context.Context, sessionID, token, pageURL string, opts *RenderOptions) (*Render, error) {
+	req := startRenderRequest{
+		SessionID: sessionID,
+		Token:     token,
+		URL:       pageURL,
+	}
+	if opts != nil {
+		req.RenderOptions = *opts
+	}
+
+	var render Render
+	if err := c.doJSON(ctx, http.MethodPost, c.projectPath("/render"), req, &render); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Started Vonage Video Experience Composer",
+		"renderID", render.ID,
+		"sessionID", sessionID,
+	)
+
+	return &render, nil
+}
+
+// StopRender stops an Experience Composer
+func (c *Client) StopRender(ctx context.Context, renderID string) error {
+	path := "/render/" + url.PathEscape(renderID)
+	if err := c.doJSON(ctx, http.MethodDelete, c.projectPath(path), nil, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Stopped Vonage Video Experience Composer", "renderID", renderID)
+	return nil
+}
+
+// GetRender retrieves an Experience Composer by ID
+func (c *Client) GetRender(ctx context.Context, renderID string) (*Render, error) {
+	var render Render
+	path := "/render/" + url.PathEscape(renderID)
+	if err := c.doJSON(ctx, http.MethodGet, c.projectPath(path), nil, &render); err != nil {
+		return nil, err
+	}
+	return &render, nil
+}
+
+// ListRenders lists Experience Composers
+func (c *Client) ListRenders(ctx context.Context, opts *ListOptions) (*RenderList, error) {
+	var list RenderList
+	if err := c.doJSON(ctx, http.MethodGet, c.projectPath("/render")+opts.query(), nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
@@ -0,0 +1,219 @@
+package video
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// Token Revocation
+// ========================================
+
+// ErrTokenRevoked is returned for tokens on a revocation list, and for new
+// tokens of revoked users
+var ErrTokenRevoked = errors.New("video: token revoked")
+
+// TokenRevoker keeps a revocation list of video tokens, by token ID (the
+// jti claim) or by user, and ejects revoked users from their sessions.
+// Users are identified by their connection data, which
+// GeneratePublisherToken and friends set to the user ID; use
+// WithRevokerUserFunc for structured data. It is safe for concurrent use.
+type TokenRevoker struct {
+	moderation ModerationAPI
+	userFunc   func(data string) string
+	logger     vonage.Logger
+
+	mu     sync.RWMutex
+	tokens map[string]time.Time // jti -> token expiry
+	users  map[string]bool
+	// Live connections per user: connection ID -> session ID
+	connections map[string]map[string]string
+}
+
+// TokenRevokerOption is a functional option for configuring a TokenRevoker
+type TokenRevokerOption func(*TokenRevoker)
+
+// WithRevokerUserFunc sets how the user is read from connection data
+// (default: the data is the user ID)
+func WithRevokerUserFunc(fn func(data string) string) TokenRevokerOption {
+	return func(r *TokenRevoker) {
+		r.userFunc = fn
+	}
+}
+
+// WithRevokerLogger sets the logger (default: no logging)
+func WithRevokerLogger(l vonage.Logger) TokenRevokerOption {
+	return func(r *TokenRevoker) {
+		r.logger = l
+	}
+}
+
+// NewTokenRevoker creates a revoker that disconnects revoked users through
+// moderation, usually the video *Client
+func NewTokenRevoker(moderation ModerationAPI, opts ...TokenRevokerOption) *TokenRevoker {
+	r := &TokenRevoker{
+		moderation:  moderation,
+		userFunc:    func(data string) string { return data },
+		logger:      vonage.NopLogger(),
+		tokens:      make(map[string]time.Time),
+		users:       make(map[string]bool),
+		connections: make(map[string]map[string]string),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// RevokeToken adds a token ID to the revocation list until the token
+// expires
+func (r *TokenRevoker) RevokeToken(jti string, expiresAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens[jti] = expiresAt
+	r.pruneLocked()
+}
+
+// RevokeUser revokes every token of the user and force-disconnects the
+// user's live connections. Connections are known from the session
+// monitoring events passed to Observe; errors disconnecting them are
+// joined.
+func (r *TokenRevoker) RevokeUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	r.users[userID] = true
+	live := r.connections[userID]
+	delete(r.connections, userID)
+	r.mu.Unlock()
+
+	r.logger.Info("Revoked video user", "userID", userID, "connections", len(live))
+
+	var errs []error
+	for connectionID, sessionID := range live {
+		if err := r.moderation.ForceDisconnect(ctx, sessionID, connectionID); err != nil {
+			errs = append(errs, fmt.Errorf("disconnect %s from %s: %w", connectionID, sessionID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RestoreUser removes the user from the revocation list
+func (r *TokenRevoker) RestoreUser(userID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.users, userID)
+}
+
+// IsUserRevoked returns true if the user is on the revocation list
+func (r *TokenRevoker) IsUserRevoked(userID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.users[userID]
+}
+
+// IsTokenRevoked returns true if the token ID is on the revocation list
+func (r *TokenRevoker) IsTokenRevoked(jti string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.tokens[jti]
+	return ok
+}
+
+// Check returns ErrTokenRevoked if the token, or its user, is revoked. It
+// reads the token's claims without verifying its signature, so it
+// complements authentication rather than replacing it.
+func (r *TokenRevoker) Check(token string) error {
+	var claims ExtendedTokenClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil {
+		return fmt.Errorf("video: invalid token: %w", err)
+	}
+	if claims.ID != "" && r.IsTokenRevoked(claims.ID) {
+		return ErrTokenRevoked
+	}
+	if user := r.userFunc(claims.Data); user != "" && r.IsUserRevoked(user) {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// Middleware rejects requests to your own endpoints whose bearer video
+// token is revoked with 403 Forbidden. Requests without a bearer token
+// pass through.
+func (r *TokenRevoker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if ok {
+			if err := r.Check(token); err != nil {
+				r.logger.Warn("Rejected revoked video token", "path", req.URL.Path, "error", err)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// Attach registers the revoker's connection callbacks on a webhook
+// handler. Handlers registered on h afterwards replace the revoker's; to
+// combine it with an OccupancyTracker, call both Observe methods from one
+// handler.
+func (r *TokenRevoker) Attach(h *WebhookHandler) *WebhookHandler {
+	return h.
+		OnConnectionCreated(r.Observe).
+		OnConnectionDestroyed(r.Observe)
+}
+
+// Observe tracks users' live connections from session events. A revoked
+// user who connects anyway, e.g. with a token issued elsewhere, is
+// disconnected at once.
+func (r *TokenRevoker) Observe(event *SessionEvent) error {
+	if event.Connection == nil {
+		return nil
+	}
+	user := r.userFunc(event.Connection.Data)
+	if user == "" {
+		return nil
+	}
+
+	switch event.Event {
+	case EventConnectionCreated:
+		if r.IsUserRevoked(user) {
+			return r.moderation.ForceDisconnect(context.Background(), event.SessionID, event.Connection.ID)
+		}
+		r.mu.Lock()
+		if r.connections[user] == nil {
+			r.connections[user] = make(map[string]string)
+		}
+		r.connections[user][event.Connection.ID] = event.SessionID
+		r.mu.Unlock()
+	case EventConnectionDestroyed:
+		r.mu.Lock()
+		delete(r.connections[user], event.Connection.ID)
+		if len(r.connections[user]) == 0 {
+			delete(r.connections, user)
+		}
+		r.mu.Unlock()
+	}
+	return nil
+}
+
+// pruneLocked drops expired token IDs; callers hold r.mu
+func (r *TokenRevoker) pruneLocked() {
+	now := time.Now()
+	for jti, expiresAt := range r.tokens {
+		if now.After(expiresAt) {
+			delete(r.tokens, jti)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package video
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ========================================
+// SIP Interconnect API
+// ========================================
+
+// Dial connects a SIP endpoint (e.g. a PSTN gateway) to a session so phone
+// participants can join. The token must be valid for the session.
+func (c *Client) Dial(ctx context.Context, sessionID, token, sipURI string, opts *SIPOptions) (*SIPCall, error) {
+	req := dialRequest{
+		SessionID: sessionID,
+		Token:     token,
+		SIP:       sipRequest{URI: sipURI},
+	}
+	if opts != nil {
+		req.SIP.SIPOptions = *opts
+	}
+
+	var call SIPCall
+	if err := c.doJSON(ctx, http.MethodPost, c.projectPath("/dial"), req, &call); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Dialed SIP endpoint into Vonage Video session",
+		"sessionID", sessionID,
+		"connectionID", call.ConnectionID,
+	)
+
+	return &call, nil
+}
+
+// PlayDTMF sends DTMF digits to a single connection (e.g. a SIP call).
+// Valid digits are 0-9, *, #, and p (500ms pause).
+func (c *Client) PlayDTMF(ctx context.Context, sessionID, connectionID, digits string) error {
+	path := fmt.Sprintf("/session/%s/connection/%s/play-dtmf", url.PathEscape(sessionID), url.PathEscape(connectionID))
+	return c.doJSON(ctx, http.MethodPost, c.projectPath(path), dtmfRequest{Digits: digits}, nil)
+}
+
+// PlayDTMFToSession sends DTMF digits to every SIP participant in a session
+func (c *Client) PlayDTMFToSession(ctx context.Context, sessionID, digits string) error {
+	path := fmt.Sprintf("/session/%s/play-dtmf", url.PathEscape(sessionID))
+	return c.doJSON(ctx, http.MethodPost, c.projectPath(path), dtmfRequest{Digits: digits}, nil)
+}
+
+// HangupSIPCall ends a SIP call by disconnecting its connection
+func (c *Client) HangupSIPCall(ctx context.Context, sessionID string, call *SIPCall) error {
+	return c.ForceDisconnect(ctx, sessionID, call.ConnectionID)
+}
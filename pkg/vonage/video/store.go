@@ -0,0 +1,124 @@
+package video
+
+import (
+	"context"
+	"sync"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// Session Store
+// ========================================
+
+// SessionStore persists sessions known to the client. Implement it on top
+// of Redis or a database to share sessions between instances; the default
+// is an in-memory MemorySessionStore.
+type SessionStore interface {
+	// Get returns the session or vonage.ErrSessionNotFound
+	Get(ctx context.Context, sessionID string) (*Session, error)
+	// Put inserts or replaces a session
+	Put(ctx context.Context, session *Session) error
+	// Delete removes a session; deleting an unknown session is not an error
+	Delete(ctx context.Context, sessionID string) error
+	// FindBySpot returns a valid (non-expired) session for the spot or
+	// vonage.ErrSessionNotFound
+	FindBySpot(ctx context.Context, spotID string) (*Session, error)
+	// FindByTag returns the valid sessions whose metadata has key set to
+	// value, in no particular order
+	FindByTag(ctx context.Context, key, value string) ([]*Session, error)
+	// DeleteExpired removes expired sessions and returns how many were removed
+	DeleteExpired(ctx context.Context) (int, error)
+	// Count returns the number of stored sessions
+	Count(ctx context.Context) (int, error)
+}
+
+// MemorySessionStore is an in-memory SessionStore
+type MemorySessionStore struct {
+	sessions map[string]*Session
+	mu       sync.RWMutex
+}
+
+// NewMemorySessionStore creates an empty in-memory session store
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: make(map[string]*Session),
+	}
+}
+
+// Get implements SessionStore
+func (s *MemorySessionStore) Get(_ context.Context, sessionID string) (*Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return nil, vonage.ErrSessionNotFound
+	}
+	return session, nil
+}
+
+// Put implements SessionStore
+func (s *MemorySessionStore) Put(_ context.Context, session *Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.SessionID] = session
+	return nil
+}
+
+// Delete implements SessionStore
+func (s *MemorySessionStore) Delete(_ context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, sessionID)
+	return nil
+}
+
+// FindBySpot implements SessionStore
+func (s *MemorySessionStore) FindBySpot(_ context.Context, spotID string) (*Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, session := range s.sessions {
+		if session.SpotID == spotID && session.IsValid() {
+			return session, nil
+		}
+	}
+	return nil, vonage.ErrSessionNotFound
+}
+
+// FindByTag implements SessionStore
+func (s *MemorySessionStore) FindByTag(_ context.Context, key, value string) ([]*Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var sessions []*Session
+	for _, session := range s.sessions {
+		if session.HasTag(key, value) && session.IsValid() {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+// DeleteExpired implements SessionStore
+func (s *MemorySessionStore) DeleteExpired(_ context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for id, session := range s.sessions {
+		if session.IsExpired() {
+			delete(s.sessions, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Count implements SessionStore
+func (s *MemorySessionStore) Count(_ context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.sessions), nil
+}
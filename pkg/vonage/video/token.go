@@ -3,32 +3,118 @@ package video
 import (
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"github.com/rs/zerolog/log"
 
 	vonage "github.com/vonatrigger/poc/pkg/vonage"
 )
 
+// DefaultMaxTokenDataLength is the maximum length of connection data
+// accepted by the Vonage Video API
+const DefaultMaxTokenDataLength = 1000
+
+// DataTooLongError is returned when token connection data exceeds the
+// configured maximum length
+type DataTooLongError struct {
+	Length int
+	Max    int
+}
+
+func (e *DataTooLongError) Error() string {
+	return fmt.Sprintf("video: token data is %d characters, max %d", e.Length, e.Max)
+}
+
 // TokenGenerator generates tokens for Vonage Video sessions
 type TokenGenerator struct {
-	appID        string
-	jwtGenerator *vonage.JWTGenerator
+	appID         string
+	jwtGenerator  *vonage.JWTGenerator
+	interceptors  []func(claims vonage.JWTClaims)
+	maxDataLength int
+	logger        vonage.Logger
+	mode          vonage.Mode
+	revoker       *TokenRevoker
+}
+
+// TokenGeneratorOption is a functional option for configuring the token generator
+type TokenGeneratorOption func(*TokenGenerator)
+
+// WithClaimsInterceptor registers a function that may add or modify claims
+// before each token is signed. Interceptors run in registration order.
+func WithClaimsInterceptor(fn func(claims vonage.JWTClaims)) TokenGeneratorOption {
+	return func(g *TokenGenerator) {
+		g.interceptors = append(g.interceptors, fn)
+	}
+}
+
+// WithMaxDataLength overrides the maximum connection data length
+// (default DefaultMaxTokenDataLength)
+func WithMaxDataLength(n int) TokenGeneratorOption {
+	return func(g *TokenGenerator) {
+		g.maxDataLength = n
+	}
+}
+
+// WithTokenLogger sets the logger (default: no logging)
+func WithTokenLogger(l vonage.Logger) TokenGeneratorOption {
+	return func(g *TokenGenerator) {
+		g.logger = l
+	}
+}
+
+// WithTokenMode sets the generator's mode (default vonage.ModeLive). In
+// vonage.ModeMock it generates mock tokens, as it does without a JWT
+// generator.
+func WithTokenMode(m vonage.Mode) TokenGeneratorOption {
+	return func(g *TokenGenerator) {
+		g.mode = m
+	}
+}
+
+// WithTokenRevoker refuses tokens for users revoked on r with
+// ErrTokenRevoked
+func WithTokenRevoker(r *TokenRevoker) TokenGeneratorOption {
+	return func(g *TokenGenerator) {
+		g.revoker = r
+	}
 }
 
 // NewTokenGenerator creates a new token generator
-func NewTokenGenerator(appID string, jwtGenerator *vonage.JWTGenerator) *TokenGenerator {
-	return &TokenGenerator{
-		appID:        appID,
-		jwtGenerator: jwtGenerator,
+func NewTokenGenerator(appID string, jwtGenerator *vonage.JWTGenerator, opts ...TokenGeneratorOption) *TokenGenerator {
+	g := &TokenGenerator{
+		appID:         appID,
+		jwtGenerator:  jwtGenerator,
+		maxDataLength: DefaultMaxTokenDataLength,
+		logger:        vonage.NopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// checkData returns a DataTooLongError if data exceeds the maximum length
+func (g *TokenGenerator) checkData(data string) error {
+	if n := len([]rune(data)); g.maxDataLength > 0 && n > g.maxDataLength {
+		return &DataTooLongError{Length: n, Max: g.maxDataLength}
 	}
+	return nil
 }
 
 // GenerateToken creates a JWT token for a user to join a video session
 func (g *TokenGenerator) GenerateToken(sessionID, userID string, opts TokenOptions) (*Token, error) {
-	if g.jwtGenerator == nil {
+	if err := g.checkData(opts.Data); err != nil {
+		return nil, err
+	}
+	if g.revoker != nil && g.revoker.IsUserRevoked(userID) {
+		return nil, ErrTokenRevoked
+	}
+
+	if g.jwtGenerator == nil || g.mode == vonage.ModeMock {
 		return g.generateMockToken(sessionID, userID, opts)
 	}
 
@@ -42,9 +128,9 @@ func (g *TokenGenerator) GenerateToken(sessionID, userID string, opts TokenOptio
 
 	now := time.Now()
 
-	// Build claims for Vonage Video client token
+	// Build claims for Vonage Video client token (iat and nbf come from
+	// the JWT generator so its clock skew settings apply)
 	claims := vonage.JWTClaims{
-		"iat":        now.Unix(),
 		"exp":        opts.ExpireTime.Unix(),
 		"jti":        uuid.New().String(),
 		"scope":      "session.connect",
@@ -61,16 +147,31 @@ func (g *TokenGenerator) GenerateToken(sessionID, userID string, opts TokenOptio
 		claims["initial_layout_class_list"] = opts.InitialLayoutClassList
 	}
 
+	if !opts.NotBefore.IsZero() {
+		claims["nbf"] = opts.NotBefore.Unix()
+	}
+
+	for _, intercept := range g.interceptors {
+		intercept(claims)
+	}
+
+	// Interceptors may have replaced the connection data
+	if data, ok := claims["data"].(string); ok {
+		if err := g.checkData(data); err != nil {
+			return nil, err
+		}
+	}
+
 	token, err := g.jwtGenerator.GenerateJWT(opts.ExpireTime.Sub(now), claims)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Debug().
-		Str("sessionID", sessionID).
-		Str("userID", userID).
-		Str("role", string(opts.Role)).
-		Msg("Generated Vonage Video token")
+	g.logger.Debug("Generated Vonage Video token",
+		"sessionID", sessionID,
+		"userID", userID,
+		"role", string(opts.Role),
+	)
 
 	return &Token{
 		Token:     token,
@@ -124,10 +225,7 @@ func (g *TokenGenerator) generateMockToken(sessionID, userID string, opts TokenO
 	jsonData, _ := json.Marshal(mockData)
 	mockToken := "mock_" + base64.StdEncoding.EncodeToString(jsonData)
 
-	log.Debug().
-		Str("sessionID", sessionID).
-		Str("userID", userID).
-		Msg("Generated mock video token")
+	g.logger.Debug("Generated mock video token", "sessionID", sessionID, "userID", userID)
 
 	return &Token{
 		Token:     mockToken,
@@ -179,6 +277,12 @@ func (b *TokenBuilder) WithData(data string) *TokenBuilder {
 	return b
 }
 
+// WithNotBefore sets the time before which the token is not valid
+func (b *TokenBuilder) WithNotBefore(t time.Time) *TokenBuilder {
+	b.opts.NotBefore = t
+	return b
+}
+
 // WithLayoutClasses sets the initial layout class list
 func (b *TokenBuilder) WithLayoutClasses(classes ...string) *TokenBuilder {
 	b.opts.InitialLayoutClassList = classes
@@ -1,6 +1,47 @@
 package video
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrSessionFull is returned when a session has reached MaxParticipants
+	ErrSessionFull = errors.New("video: session is full")
+	// ErrE2EERelayed is returned when end-to-end encryption is requested for
+	// a relayed session; E2EE requires routed media
+	ErrE2EERelayed = errors.New("video: e2ee requires routed media mode")
+	// ErrMockSession is matched by *MockSessionError
+	ErrMockSession = errors.New("video: mock session")
+)
+
+// MockSessionError is returned together with a mock session by a client
+// created WithMockFallback, so code that must not hand out fake sessions
+// can tell them apart. Callers that accept mocks check
+// errors.Is(err, ErrMockSession) and use the session.
+type MockSessionError struct {
+	SessionID string
+	// Cause is why the mock was substituted, nil for a cached mock session
+	Cause error
+}
+
+func (e *MockSessionError) Error() string {
+	if e.Cause == nil {
+		return fmt.Sprintf("video: %s is a mock session", e.SessionID)
+	}
+	return fmt.Sprintf("video: substituted mock session %s: %v", e.SessionID, e.Cause)
+}
+
+// Is makes errors.Is(err, ErrMockSession) true
+func (e *MockSessionError) Is(target error) bool {
+	return target == ErrMockSession
+}
+
+// Unwrap returns the cause
+func (e *MockSessionError) Unwrap() error {
+	return e.Cause
+}
 
 // Session represents a Vonage Video session
 type Session struct {
@@ -10,6 +51,19 @@ type Session struct {
 	CreatedAt time.Time `json:"createdAt"`
 	ExpiresAt time.Time `json:"expiresAt"`
 	IsMock    bool      `json:"isMock,omitempty"`
+	// MaxParticipants caps the number of connections (0 = unlimited)
+	MaxParticipants int `json:"maxParticipants,omitempty"`
+	// E2EE is true if the session was created with end-to-end encryption
+	E2EE bool `json:"e2ee,omitempty"`
+	// Metadata holds application attributes as key/value tags, e.g. a
+	// spot's area or event; see Client.FindSessionsByTag
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// HasTag returns true if the session's metadata has key set to value
+func (s *Session) HasTag(key, value string) bool {
+	v, ok := s.Metadata[key]
+	return ok && v == value
 }
 
 // IsExpired returns true if the session has expired
@@ -22,6 +76,21 @@ func (s *Session) IsValid() bool {
 	return s.SessionID != "" && !s.IsExpired()
 }
 
+// HasCapacity returns true if another participant may join a session that
+// currently has the given number of connections
+func (s *Session) HasCapacity(connections int) bool {
+	return s.MaxParticipants == 0 || connections < s.MaxParticipants
+}
+
+// CheckCapacity returns ErrSessionFull if the session cannot admit another
+// participant. Pair with OccupancyTracker.Connections for live counts.
+func (s *Session) CheckCapacity(connections int) error {
+	if !s.HasCapacity(connections) {
+		return ErrSessionFull
+	}
+	return nil
+}
+
 // Token represents a video session token
 type Token struct {
 	Token     string `json:"token"`
@@ -72,6 +141,34 @@ type CreateSessionOptions struct {
 	ArchiveMode ArchiveMode
 	// P2PPreference is deprecated, use MediaMode instead
 	P2PPreference string
+	// MaxParticipants caps the number of connections, tracked on the
+	// stored Session (0 = unlimited)
+	MaxParticipants int
+	// E2EE enables end-to-end encryption; requires routed media mode
+	E2EE bool
+	// Metadata is stored on the Session
+	Metadata map[string]string
+	// TTL overrides DefaultSessionTTL for this session
+	TTL time.Duration
+}
+
+// ttl returns the session TTL of the options
+func (o *CreateSessionOptions) ttl() time.Duration {
+	if o == nil || o.TTL <= 0 {
+		return DefaultSessionTTL
+	}
+	return o.TTL
+}
+
+// Validate checks that the options are consistent
+func (o *CreateSessionOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+	if o.E2EE && (o.MediaMode == MediaModeRelayed || o.P2PPreference == "enabled") {
+		return ErrE2EERelayed
+	}
+	return nil
 }
 
 // CreateSessionResponse represents the Vonage API response for session creation
@@ -92,6 +189,8 @@ type TokenOptions struct {
 	Data string
 	// InitialLayoutClassList is a list of layout classes for the stream
 	InitialLayoutClassList []string
+	// NotBefore overrides the nbf claim (default: the JWT generator's setting)
+	NotBefore time.Time
 }
 
 // DefaultTokenOptions returns the default token options
@@ -101,3 +200,510 @@ func DefaultTokenOptions() TokenOptions {
 		ExpireTime: time.Now().Add(24 * time.Hour),
 	}
 }
+
+// ========================================
+// Layout
+// ========================================
+
+// LayoutType is a predefined layout for composed archives and broadcasts
+type LayoutType string
+
+const (
+	LayoutBestFit                LayoutType = "bestFit"
+	LayoutCustom                 LayoutType = "custom"
+	LayoutHorizontalPresentation LayoutType = "horizontalPresentation"
+	LayoutPIP                    LayoutType = "pip"
+	LayoutVerticalPresentation   LayoutType = "verticalPresentation"
+)
+
+// Layout describes how streams are arranged in composed output
+type Layout struct {
+	Type LayoutType `json:"type"`
+	// Stylesheet is the CSS used when Type is LayoutCustom
+	Stylesheet string `json:"stylesheet,omitempty"`
+	// ScreenshareType is the layout applied while a screen is shared
+	ScreenshareType LayoutType `json:"screenshareType,omitempty"`
+}
+
+// ListOptions contains paging and filter options for list endpoints
+type ListOptions struct {
+	SessionID string
+	Offset    int
+	Count     int
+}
+
+// ========================================
+// Archive
+// ========================================
+
+// ArchiveStatus represents the status of an archive
+type ArchiveStatus string
+
+const (
+	ArchiveStatusStarted   ArchiveStatus = "started"
+	ArchiveStatusPaused    ArchiveStatus = "paused"
+	ArchiveStatusStopped   ArchiveStatus = "stopped"
+	ArchiveStatusUploaded  ArchiveStatus = "uploaded"
+	ArchiveStatusAvailable ArchiveStatus = "available"
+	ArchiveStatusExpired   ArchiveStatus = "expired"
+	ArchiveStatusFailed    ArchiveStatus = "failed"
+)
+
+// OutputMode determines whether streams are composed or recorded individually
+type OutputMode string
+
+const (
+	OutputModeComposed   OutputMode = "composed"
+	OutputModeIndividual OutputMode = "individual"
+)
+
+// StreamMode determines whether streams are selected automatically
+type StreamMode string
+
+const (
+	StreamModeAuto   StreamMode = "auto"
+	StreamModeManual StreamMode = "manual"
+)
+
+// ArchiveOptions contains options for starting an archive
+type ArchiveOptions struct {
+	Name       string     `json:"name,omitempty"`
+	HasAudio   *bool      `json:"hasAudio,omitempty"`
+	HasVideo   *bool      `json:"hasVideo,omitempty"`
+	OutputMode OutputMode `json:"outputMode,omitempty"`
+	StreamMode StreamMode `json:"streamMode,omitempty"`
+	// Resolution is "640x480", "1280x720", "1920x1080" (or portrait variants)
+	Resolution      string  `json:"resolution,omitempty"`
+	Layout          *Layout `json:"layout,omitempty"`
+	MultiArchiveTag string  `json:"multiArchiveTag,omitempty"`
+}
+
+type startArchiveRequest struct {
+	SessionID string `json:"sessionId"`
+	ArchiveOptions
+}
+
+// Archive represents a Vonage Video archive (recording)
+type Archive struct {
+	ID            string        `json:"id"`
+	Status        ArchiveStatus `json:"status"`
+	Name          string        `json:"name,omitempty"`
+	Reason        string        `json:"reason,omitempty"`
+	SessionID     string        `json:"sessionId"`
+	ApplicationID string        `json:"applicationId,omitempty"`
+	// CreatedAt is the creation time in Unix milliseconds
+	CreatedAt int64 `json:"createdAt"`
+	// Size is the file size in bytes
+	Size int64 `json:"size"`
+	// Duration is the recording length in seconds
+	Duration   int        `json:"duration"`
+	OutputMode OutputMode `json:"outputMode,omitempty"`
+	StreamMode StreamMode `json:"streamMode,omitempty"`
+	HasAudio   bool       `json:"hasAudio"`
+	HasVideo   bool       `json:"hasVideo"`
+	Resolution string     `json:"resolution,omitempty"`
+	// URL is the download URL, set once the archive is available
+	URL             string `json:"url,omitempty"`
+	MultiArchiveTag string `json:"multiArchiveTag,omitempty"`
+}
+
+// CreatedTime returns CreatedAt as a time.Time
+func (a *Archive) CreatedTime() time.Time {
+	return time.UnixMilli(a.CreatedAt)
+}
+
+// DurationTime returns Duration as a time.Duration
+func (a *Archive) DurationTime() time.Duration {
+	return time.Duration(a.Duration) * time.Second
+}
+
+// IsAvailable returns true if the recording can be downloaded
+func (a *Archive) IsAvailable() bool {
+	return a.Status == ArchiveStatusAvailable || a.Status == ArchiveStatusUploaded
+}
+
+// ArchiveList is a page of archives
+type ArchiveList struct {
+	Count int       `json:"count"`
+	Items []Archive `json:"items"`
+}
+
+// ========================================
+// Broadcast
+// ========================================
+
+// BroadcastStatus represents the status of a broadcast
+type BroadcastStatus string
+
+const (
+	BroadcastStatusStarted BroadcastStatus = "started"
+	BroadcastStatusStopped BroadcastStatus = "stopped"
+)
+
+// BroadcastOptions contains options for starting a broadcast
+type BroadcastOptions struct {
+	Outputs BroadcastOutputs `json:"outputs"`
+	Layout  *Layout          `json:"layout,omitempty"`
+	// MaxDuration is the maximum broadcast length in seconds (default 7200)
+	MaxDuration int `json:"maxDuration,omitempty"`
+	// MaxBitrate is the maximum video bitrate in bits per second
+	MaxBitrate        int        `json:"maxBitrate,omitempty"`
+	Resolution        string     `json:"resolution,omitempty"`
+	StreamMode        StreamMode `json:"streamMode,omitempty"`
+	MultiBroadcastTag string     `json:"multiBroadcastTag,omitempty"`
+}
+
+type startBroadcastRequest struct {
+	SessionID string `json:"sessionId"`
+	BroadcastOptions
+}
+
+// BroadcastOutputs configures where a broadcast is streamed to
+type BroadcastOutputs struct {
+	HLS  *HLSConfig   `json:"hls,omitempty"`
+	RTMP []RTMPConfig `json:"rtmp,omitempty"`
+}
+
+// HLSConfig configures HLS output
+type HLSConfig struct {
+	// DVR enables rewinding (incompatible with LowLatency)
+	DVR        bool `json:"dvr,omitempty"`
+	LowLatency bool `json:"lowLatency,omitempty"`
+}
+
+// RTMPConfig configures an RTMP output (e.g. YouTube Live)
+type RTMPConfig struct {
+	ID         string `json:"id,omitempty"`
+	ServerURL  string `json:"serverUrl"`
+	StreamName string `json:"streamName"`
+}
+
+// RTMPStatus is an RTMP output with its delivery status
+type RTMPStatus struct {
+	RTMPConfig
+	// Status is "connecting", "live", "offline" or "error"
+	Status string `json:"status,omitempty"`
+}
+
+// BroadcastURLs are the playback and ingest URLs of a broadcast
+type BroadcastURLs struct {
+	HLS  string       `json:"hls,omitempty"`
+	RTMP []RTMPStatus `json:"rtmp,omitempty"`
+}
+
+// Broadcast represents a Vonage Video live broadcast
+type Broadcast struct {
+	ID            string          `json:"id"`
+	SessionID     string          `json:"sessionId"`
+	ApplicationID string          `json:"applicationId,omitempty"`
+	Status        BroadcastStatus `json:"status"`
+	// CreatedAt and UpdatedAt are Unix milliseconds
+	CreatedAt         int64         `json:"createdAt"`
+	UpdatedAt         int64         `json:"updatedAt"`
+	MaxDuration       int           `json:"maxDuration,omitempty"`
+	MaxBitrate        int           `json:"maxBitrate,omitempty"`
+	Resolution        string        `json:"resolution,omitempty"`
+	StreamMode        StreamMode    `json:"streamMode,omitempty"`
+	MultiBroadcastTag string        `json:"multiBroadcastTag,omitempty"`
+	BroadcastURLs     BroadcastURLs `json:"broadcastUrls"`
+}
+
+// IsLive returns true if the broadcast is running
+func (b *Broadcast) IsLive() bool {
+	return b.Status == BroadcastStatusStarted
+}
+
+// BroadcastList is a page of broadcasts
+type BroadcastList struct {
+	Count int         `json:"count"`
+	Items []Broadcast `json:"items"`
+}
+
+// ========================================
+// Moderation
+// ========================================
+
+type forceMuteRequest struct {
+	Active            bool     `json:"active"`
+	ExcludedStreamIDs []string `json:"excludedStreamIds,omitempty"`
+}
+
+// ========================================
+// SIP
+// ========================================
+
+// SIPOptions contains options for dialing a SIP endpoint into a session
+type SIPOptions struct {
+	// From is the caller ID presented to the SIP endpoint
+	From string `json:"from,omitempty"`
+	// Headers are custom X- headers added to the SIP INVITE
+	Headers map[string]string `json:"headers,omitempty"`
+	Auth    *SIPAuth          `json:"auth,omitempty"`
+	// Secure enables SRTP media encryption
+	Secure bool `json:"secure,omitempty"`
+	// Video enables video on the SIP call
+	Video bool `json:"video,omitempty"`
+	// ObserveForceMute makes the SIP call honor MuteAllStreams
+	ObserveForceMute bool `json:"observeForceMute,omitempty"`
+}
+
+// SIPAuth contains digest credentials for the SIP endpoint
+type SIPAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// SIPCall is a SIP participant connected to a session
+type SIPCall struct {
+	ID           string `json:"id"`
+	ConnectionID string `json:"connectionId"`
+	StreamID     string `json:"streamId"`
+}
+
+type dialRequest struct {
+	SessionID string     `json:"sessionId"`
+	Token     string     `json:"token"`
+	SIP       sipRequest `json:"sip"`
+}
+
+type sipRequest struct {
+	URI string `json:"uri"`
+	SIPOptions
+}
+
+type dtmfRequest struct {
+	Digits string `json:"digits"`
+}
+
+// ========================================
+// Experience Composer
+// ========================================
+
+// RenderStatus represents the status of an Experience Composer
+type RenderStatus string
+
+const (
+	RenderStatusStarting RenderStatus = "starting"
+	RenderStatusStarted  RenderStatus = "started"
+	RenderStatusStopped  RenderStatus = "stopped"
+	RenderStatusFailed   RenderStatus = "failed"
+)
+
+// RenderOptions contains options for starting an Experience Composer
+type RenderOptions struct {
+	// MaxDuration is the maximum render length in seconds (default 7200)
+	MaxDuration int    `json:"maxDuration,omitempty"`
+	Resolution  string `json:"resolution,omitempty"`
+	// Properties are applied to the published stream
+	Properties *RenderProperties `json:"properties,omitempty"`
+}
+
+// RenderProperties are properties of the stream published by a render
+type RenderProperties struct {
+	// Name is the stream name shown to other participants
+	Name string `json:"name,omitempty"`
+}
+
+type startRenderRequest struct {
+	SessionID string `json:"sessionId"`
+	Token     string `json:"token"`
+	URL       string `json:"url"`
+	RenderOptions
+}
+
+// Render represents a Vonage Video Experience Composer
+type Render struct {
+	ID            string       `json:"id"`
+	SessionID     string       `json:"sessionId"`
+	ApplicationID string       `json:"applicationId,omitempty"`
+	Status        RenderStatus `json:"status"`
+	URL           string       `json:"url"`
+	Resolution    string       `json:"resolution,omitempty"`
+	// StreamID is the stream published into the session once started
+	StreamID string `json:"streamId,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	// CreatedAt and UpdatedAt are Unix milliseconds
+	CreatedAt int64 `json:"createdAt"`
+	UpdatedAt int64 `json:"updatedAt"`
+}
+
+// RenderList is a page of Experience Composers
+type RenderList struct {
+	Count int      `json:"count"`
+	Items []Render `json:"items"`
+}
+
+// ========================================
+// Live Captions
+// ========================================
+
+// CaptionsOptions contains options for enabling live captions
+type CaptionsOptions struct {
+	// LanguageCode is the spoken language hint (default "en-US")
+	LanguageCode string `json:"languageCode,omitempty"`
+	// MaxDuration is the maximum captioning length in seconds (default 14400)
+	MaxDuration int `json:"maxDuration,omitempty"`
+	// PartialCaptions delivers interim results before a phrase is final
+	PartialCaptions *bool `json:"partialCaptions,omitempty"`
+	// StatusCallbackURL receives captions status change events
+	StatusCallbackURL string `json:"statusCallbackUrl,omitempty"`
+}
+
+type enableCaptionsRequest struct {
+	SessionID string `json:"sessionId"`
+	Token     string `json:"token"`
+	CaptionsOptions
+}
+
+type enableCaptionsResponse struct {
+	CaptionsID string `json:"captionsId"`
+}
+
+// CaptionsStatus describes a live captioning job
+type CaptionsStatus struct {
+	CaptionsID    string `json:"captionId"`
+	ApplicationID string `json:"applicationId,omitempty"`
+	SessionID     string `json:"sessionId"`
+	// Status is "started", "stopped", "paused" or "failed"
+	Status       string `json:"status"`
+	LanguageCode string `json:"languageCode,omitempty"`
+	Provider     string `json:"provider,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+	// CreatedAt and UpdatedAt are Unix milliseconds
+	CreatedAt int64 `json:"createdAt"`
+	UpdatedAt int64 `json:"updatedAt"`
+	// Duration is the captioned length in seconds
+	Duration int `json:"duration,omitempty"`
+}
+
+// ========================================
+// Project Management
+// ========================================
+
+// StorageType is the kind of archive upload target
+type StorageType string
+
+const (
+	StorageTypeS3    StorageType = "s3"
+	StorageTypeAzure StorageType = "azure"
+)
+
+// StorageFallback decides what happens when an upload to the target fails
+type StorageFallback string
+
+const (
+	// StorageFallbackNone leaves failed uploads unavailable
+	StorageFallbackNone StorageFallback = "none"
+	// StorageFallbackVonage keeps failed uploads in Vonage storage
+	StorageFallbackVonage StorageFallback = "opentok"
+)
+
+// ArchiveStorage configures where completed archives are uploaded. Set
+// either S3 or Azure to match Type; NewS3Storage and NewAzureStorage do so.
+type ArchiveStorage struct {
+	Type     StorageType     `json:"type"`
+	Config   interface{}     `json:"config"`
+	Fallback StorageFallback `json:"fallback,omitempty"`
+}
+
+// S3StorageConfig configures an Amazon S3 (or S3-compatible) upload target
+type S3StorageConfig struct {
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+	Bucket    string `json:"bucket"`
+	// Endpoint is set for S3-compatible services other than AWS
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// AzureStorageConfig configures a Microsoft Azure upload target
+type AzureStorageConfig struct {
+	AccountName string `json:"accountName"`
+	AccountKey  string `json:"accountKey"`
+	Container   string `json:"container"`
+	Domain      string `json:"domain,omitempty"`
+}
+
+// NewS3Storage returns an S3 archive storage configuration
+func NewS3Storage(cfg S3StorageConfig, fallback StorageFallback) ArchiveStorage {
+	return ArchiveStorage{Type: StorageTypeS3, Config: cfg, Fallback: fallback}
+}
+
+// NewAzureStorage returns an Azure archive storage configuration
+func NewAzureStorage(cfg AzureStorageConfig, fallback StorageFallback) ArchiveStorage {
+	return ArchiveStorage{Type: StorageTypeAzure, Config: cfg, Fallback: fallback}
+}
+
+// CallbackGroup is the category of session monitoring events
+type CallbackGroup string
+
+const (
+	CallbackGroupConnection CallbackGroup = "connection"
+	CallbackGroupStream     CallbackGroup = "stream"
+)
+
+// CallbackEvent is a session monitoring event within a group
+type CallbackEvent string
+
+const (
+	CallbackEventCreated   CallbackEvent = "created"
+	CallbackEventDestroyed CallbackEvent = "destroyed"
+)
+
+// Callback is a registered session monitoring callback
+type Callback struct {
+	ID    string        `json:"id,omitempty"`
+	Group CallbackGroup `json:"group"`
+	Event CallbackEvent `json:"event"`
+	URL   string        `json:"url"`
+	// CreatedAt is Unix milliseconds
+	CreatedAt int64 `json:"createdAt,omitempty"`
+}
+
+// ========================================
+// Session Monitoring Events
+// ========================================
+
+// SessionEventType is the "event" field of a monitoring callback
+type SessionEventType string
+
+const (
+	EventConnectionCreated   SessionEventType = "connectionCreated"
+	EventConnectionDestroyed SessionEventType = "connectionDestroyed"
+	EventStreamCreated       SessionEventType = "streamCreated"
+	EventStreamDestroyed     SessionEventType = "streamDestroyed"
+	EventArchive             SessionEventType = "archive"
+)
+
+// SessionEvent is a session monitoring callback payload
+type SessionEvent struct {
+	SessionID string           `json:"sessionId"`
+	ProjectID string           `json:"projectId"`
+	Event     SessionEventType `json:"event"`
+	// Timestamp is Unix milliseconds
+	Timestamp int64 `json:"timestamp"`
+	// Reason is set on destroyed events (e.g. "clientDisconnected",
+	// "forceDisconnected", "networkDisconnected")
+	Reason     string      `json:"reason,omitempty"`
+	Connection *Connection `json:"connection,omitempty"`
+	Stream     *Stream     `json:"stream,omitempty"`
+}
+
+// Connection is a participant's connection to a session
+type Connection struct {
+	ID string `json:"id"`
+	// CreatedAt is Unix milliseconds
+	CreatedAt int64 `json:"createdAt"`
+	// Data is the token's connection data
+	Data string `json:"data,omitempty"`
+}
+
+// Stream is a published audio/video stream
+type Stream struct {
+	ID         string     `json:"id"`
+	Connection Connection `json:"connection"`
+	// CreatedAt is Unix milliseconds
+	CreatedAt int64  `json:"createdAt"`
+	Name      string `json:"name,omitempty"`
+	// VideoType is "camera", "screen" or "custom"
+	VideoType string `json:"videoType,omitempty"`
+}
@@ -0,0 +1,112 @@
+package video
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ========================================
+// Token Verification
+// ========================================
+
+var (
+	// ErrInvalidToken is returned when a token fails signature or claim checks
+	ErrInvalidToken = errors.New("video: invalid token")
+	// ErrTokenSessionMismatch is returned when a token is for another session
+	ErrTokenSessionMismatch = errors.New("video: token is not valid for this session")
+	// ErrTokenRoleMismatch is returned when a token lacks the required role
+	ErrTokenRoleMismatch = errors.New("video: token role is insufficient")
+)
+
+// tokenScope is the scope of client tokens issued by TokenGenerator
+const tokenScope = "session.connect"
+
+// VerifyToken verifies a client token's signature and expiry against the
+// application's public key and returns its claims. The key is an
+// *rsa.PublicKey for RS256 tokens or an *ecdsa.PublicKey for ES256 ones;
+// the token must also have been issued for the application appID.
+func VerifyToken(tokenString, appID string, publicKey crypto.PublicKey) (*ExtendedTokenClaims, error) {
+	method, err := verifyMethod(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &ExtendedTokenClaims{}
+	_, err = jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return publicKey, nil
+	}, jwt.WithValidMethods([]string{method.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if claims.Scope != tokenScope {
+		return nil, fmt.Errorf("%w: unexpected scope %q", ErrInvalidToken, claims.Scope)
+	}
+	if claims.ApplicationID != appID {
+		return nil, fmt.Errorf("%w: issued for application %q", ErrInvalidToken, claims.ApplicationID)
+	}
+
+	return claims, nil
+}
+
+// verifyMethod returns the signing method tokens verified with key must use
+func verifyMethod(key crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		return jwt.SigningMethodES256, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported public key type %T", ErrInvalidToken, key)
+}
+
+// DecodeToken parses a client token's claims WITHOUT verifying its
+// signature. Use it only for introspection of trusted tokens; use
+// VerifyToken for anything received from a client.
+func DecodeToken(tokenString string) (*ExtendedTokenClaims, error) {
+	claims := &ExtendedTokenClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return claims, nil
+}
+
+// TokenRole returns the role claim as a Role
+func (c *ExtendedTokenClaims) TokenRole() Role {
+	return Role(c.Role)
+}
+
+// CheckSession returns ErrTokenSessionMismatch if the token was not issued
+// for the given session
+func (c *ExtendedTokenClaims) CheckSession(sessionID string) error {
+	if c.SessionID != sessionID {
+		return ErrTokenSessionMismatch
+	}
+	return nil
+}
+
+// CheckRole returns ErrTokenRoleMismatch unless the token's role is at least
+// the required one (subscriber < publisher < moderator)
+func (c *ExtendedTokenClaims) CheckRole(required Role) error {
+	if roleRank(c.TokenRole()) < roleRank(required) {
+		return ErrTokenRoleMismatch
+	}
+	return nil
+}
+
+func roleRank(r Role) int {
+	switch r {
+	case RoleSubscriber:
+		return 1
+	case RolePublisher:
+		return 2
+	case RoleModerator:
+		return 3
+	}
+	return 0
+}
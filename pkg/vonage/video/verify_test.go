@@ -0,0 +1,87 @@
+package video
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+const testAppID = "app-1"
+
+func generateRSA(t *testing.T) crypto.Signer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func generateEC(t *testing.T) crypto.Signer {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// issueToken returns a publisher token for session-1 signed with key
+func issueToken(t *testing.T, appID string, key crypto.Signer) string {
+	t.Helper()
+	g := NewTokenGenerator(appID, vonage.NewJWTGenerator(appID, key))
+	token, err := g.GenerateToken("session-1", "user-1", TokenOptions{Role: RolePublisher})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token.Token
+}
+
+func TestVerifyToken(t *testing.T) {
+	rsaKey, otherRSA := generateRSA(t), generateRSA(t)
+	ecKey, otherEC := generateEC(t), generateEC(t)
+
+	tests := []struct {
+		name      string
+		signer    crypto.Signer
+		issuer    string
+		publicKey crypto.PublicKey
+		wantErr   bool
+	}{
+		{"RS256", rsaKey, testAppID, rsaKey.Public(), false},
+		{"ES256", ecKey, testAppID, ecKey.Public(), false},
+		{"RS256 other key", rsaKey, testAppID, otherRSA.Public(), true},
+		{"ES256 other key", ecKey, testAppID, otherEC.Public(), true},
+		{"RS256 token with EC key", rsaKey, testAppID, ecKey.Public(), true},
+		{"ES256 token with RSA key", ecKey, testAppID, rsaKey.Public(), true},
+		{"RS256 other application", rsaKey, "app-2", rsaKey.Public(), true},
+		{"ES256 other application", ecKey, "app-2", ecKey.Public(), true},
+		{"unsupported key", rsaKey, testAppID, []byte("secret"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := issueToken(t, tt.issuer, tt.signer)
+
+			claims, err := VerifyToken(token, testAppID, tt.publicKey)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("VerifyToken() = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyToken() = %v, want nil", err)
+			}
+			if claims.SessionID != "session-1" || claims.TokenRole() != RolePublisher || claims.ApplicationID != testAppID {
+				t.Errorf("claims = %+v", claims)
+			}
+		})
+	}
+}
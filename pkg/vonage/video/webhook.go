@@ -0,0 +1,269 @@
+package video
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// Session Monitoring Webhooks
+// ========================================
+
+// ConnectionHandler handles connection created/destroyed events
+type ConnectionHandler func(event *SessionEvent) error
+
+// StreamHandler handles stream created/destroyed events
+type StreamHandler func(event *SessionEvent) error
+
+// ArchiveHandler handles archive status callbacks
+type ArchiveHandler func(archive *Archive) error
+
+// WebhookHandler provides an HTTP handler for Video session monitoring and
+// archive status callbacks
+type WebhookHandler struct {
+	onConnectionCreated   ConnectionHandler
+	onConnectionDestroyed ConnectionHandler
+	onStreamCreated       StreamHandler
+	onStreamDestroyed     StreamHandler
+	onArchive             ArchiveHandler
+
+	logger vonage.Logger
+	sink   vonage.EventSink
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler() *WebhookHandler {
+	return &WebhookHandler{logger: vonage.NopLogger()}
+}
+
+// WithLogger sets the logger for webhook errors (default: no logging)
+func (h *WebhookHandler) WithLogger(l vonage.Logger) *WebhookHandler {
+	h.logger = l
+	return h
+}
+
+// OnConnectionCreated sets the handler for participants joining
+func (h *WebhookHandler) OnConnectionCreated(handler ConnectionHandler) *WebhookHandler {
+	h.onConnectionCreated = handler
+	return h
+}
+
+// OnConnectionDestroyed sets the handler for participants leaving
+func (h *WebhookHandler) OnConnectionDestroyed(handler ConnectionHandler) *WebhookHandler {
+	h.onConnectionDestroyed = handler
+	return h
+}
+
+// OnStreamCreated sets the handler for streams being published
+func (h *WebhookHandler) OnStreamCreated(handler StreamHandler) *WebhookHandler {
+	h.onStreamCreated = handler
+	return h
+}
+
+// OnStreamDestroyed sets the handler for streams being unpublished
+func (h *WebhookHandler) OnStreamDestroyed(handler StreamHandler) *WebhookHandler {
+	h.onStreamDestroyed = handler
+	return h
+}
+
+// OnArchiveStatus sets the handler for archive status changes
+func (h *WebhookHandler) OnArchiveStatus(handler ArchiveHandler) *WebhookHandler {
+	h.onArchive = handler
+	return h
+}
+
+// WithEventSink publishes callbacks to sink before the registered handlers
+// run, as "video.<event>" events (e.g. "video.streamCreated") with the
+// parsed *SessionEvent or *Archive as Payload
+func (h *WebhookHandler) WithEventSink(sink vonage.EventSink) *WebhookHandler {
+	h.sink = sink
+	return h
+}
+
+// Handle returns an http.HandlerFunc for the callback URL
+func (h *WebhookHandler) Handle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			h.logger.Error("Failed to read video webhook body", "error", err)
+			w.WriteHeader(http.StatusOK) // Always 200 for webhooks
+			return
+		}
+		defer r.Body.Close()
+
+		h.process(r.Context(), body)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// process dispatches a callback body to the registered handler
+func (h *WebhookHandler) process(ctx context.Context, body []byte) {
+	event, archive, err := ParseCallback(body)
+	if err != nil {
+		h.logger.Warn("Unknown video webhook format", "error", err, "body", string(body))
+		return
+	}
+
+	if h.sink != nil {
+		e := vonage.NewEvent(vonage.EventSourceVideo, "", body)
+		if archive != nil {
+			e.Type, e.Key, e.Payload = "video."+string(EventArchive), archive.SessionID, archive
+		} else {
+			e.Type, e.Payload = "video."+string(event.Event), event
+		}
+		vonage.PublishEvent(ctx, h.sink, h.logger, e)
+	}
+
+	if archive != nil {
+		if h.onArchive != nil {
+			if err := h.onArchive(archive); err != nil {
+				h.logger.Error("Error handling archive status",
+					"error", err,
+					"archiveID", archive.ID,
+					"status", string(archive.Status),
+				)
+			}
+		}
+		return
+	}
+
+	var handler func(*SessionEvent) error
+	switch event.Event {
+	case EventConnectionCreated:
+		handler = h.onConnectionCreated
+	case EventConnectionDestroyed:
+		handler = h.onConnectionDestroyed
+	case EventStreamCreated:
+		handler = h.onStreamCreated
+	case EventStreamDestroyed:
+		handler = h.onStreamDestroyed
+	}
+
+	if handler != nil {
+		if err := handler(event); err != nil {
+			h.logger.Error("Error handling video session event",
+				"error", err,
+				"sessionID", event.SessionID,
+				"event", string(event.Event),
+			)
+		}
+	}
+}
+
+// ParseCallback parses a callback body into either a session event or an
+// archive status update (exactly one of the results is non-nil)
+func ParseCallback(body []byte) (*SessionEvent, *Archive, error) {
+	var probe struct {
+		Event SessionEventType `json:"event"`
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse video callback: %w", err)
+	}
+
+	switch probe.Event {
+	case EventArchive:
+		var archive Archive
+		if err := json.Unmarshal(body, &archive); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse archive callback: %w", err)
+		}
+		return nil, &archive, nil
+	case EventConnectionCreated, EventConnectionDestroyed, EventStreamCreated, EventStreamDestroyed:
+		var event SessionEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse session event: %w", err)
+		}
+		return &event, nil, nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown video callback event %q", probe.Event)
+}
+
+// ========================================
+// Room Occupancy
+// ========================================
+
+// OccupancyTracker counts live connections and streams per session from
+// monitoring callbacks
+type OccupancyTracker struct {
+	mu          sync.RWMutex
+	connections map[string]map[string]bool
+	streams     map[string]map[string]bool
+}
+
+// NewOccupancyTracker creates an occupancy tracker
+func NewOccupancyTracker() *OccupancyTracker {
+	return &OccupancyTracker{
+		connections: make(map[string]map[string]bool),
+		streams:     make(map[string]map[string]bool),
+	}
+}
+
+// Attach registers the tracker's callbacks on a webhook handler. Handlers
+// registered on h afterwards replace the tracker's.
+func (t *OccupancyTracker) Attach(h *WebhookHandler) *WebhookHandler {
+	return h.
+		OnConnectionCreated(t.Observe).
+		OnConnectionDestroyed(t.Observe).
+		OnStreamCreated(t.Observe).
+		OnStreamDestroyed(t.Observe)
+}
+
+// Observe updates the counts from a session event
+func (t *OccupancyTracker) Observe(event *SessionEvent) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch event.Event {
+	case EventConnectionCreated:
+		if event.Connection != nil {
+			addMember(t.connections, event.SessionID, event.Connection.ID)
+		}
+	case EventConnectionDestroyed:
+		if event.Connection != nil {
+			removeMember(t.connections, event.SessionID, event.Connection.ID)
+		}
+	case EventStreamCreated:
+		if event.Stream != nil {
+			addMember(t.streams, event.SessionID, event.Stream.ID)
+		}
+	case EventStreamDestroyed:
+		if event.Stream != nil {
+			removeMember(t.streams, event.SessionID, event.Stream.ID)
+		}
+	}
+	return nil
+}
+
+// Connections returns the number of live connections in a session
+func (t *OccupancyTracker) Connections(sessionID string) int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.connections[sessionID])
+}
+
+// Streams returns the number of published streams in a session
+func (t *OccupancyTracker) Streams(sessionID string) int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.streams[sessionID])
+}
+
+func addMember(m map[string]map[string]bool, sessionID, id string) {
+	if m[sessionID] == nil {
+		m[sessionID] = make(map[string]bool)
+	}
+	m[sessionID][id] = true
+}
+
+func removeMember(m map[string]map[string]bool, sessionID, id string) {
+	delete(m[sessionID], id)
+	if len(m[sessionID]) == 0 {
+		delete(m, sessionID)
+	}
+}
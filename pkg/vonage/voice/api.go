@@ -0,0 +1,38 @@
+package voice
+
+import (
+	"context"
+	"io"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// API Interface
+// ========================================
+
+// API is the Voice API implemented by *Client. Application code can depend
+// on it and substitute vonagemock.Voice in tests.
+type API interface {
+	CreateCall(ctx context.Context, opts CreateCallOptions) (*CreateCallResponse, error)
+	CreateCallToPhone(ctx context.Context, toNumber, answerURL, eventURL string) (*CreateCallResponse, error)
+	CreateCallWithNCCO(ctx context.Context, toNumber string, ncco NCCO, eventURL string) (*CreateCallResponse, error)
+	GetCallInfo(ctx context.Context, callUUID string) (*CallInfo, error)
+	ListCalls(ctx context.Context, opts *ListCallsOptions) (*CallList, error)
+	GetCallsByConversation(ctx context.Context, conversationUUID string) ([]CallInfo, error)
+	GetConversationLegs(ctx context.Context, conversationUUID string) (*ConversationLegs, error)
+	TransferCall(ctx context.Context, callUUID, nccoURL string) error
+	HangupCall(ctx context.Context, callUUID string) error
+	MuteCall(ctx context.Context, callUUID string) error
+	UnmuteCall(ctx context.Context, callUUID string) error
+	EarmuffCall(ctx context.Context, callUUID string) error
+	UnearmuffCall(ctx context.Context, callUUID string) error
+	SendDTMF(ctx context.Context, callUUID, digits string) error
+	TalkIntoCall(ctx context.Context, callUUID string, opts TalkIntoCallOptions) error
+	StopTalk(ctx context.Context, callUUID string) error
+	StreamIntoCall(ctx context.Context, callUUID string, opts StreamIntoCallOptions) error
+	StopStream(ctx context.Context, callUUID string) error
+	DownloadRecording(ctx context.Context, recordingURL string, w io.Writer, opts ...vonage.DownloadOption) (int64, error)
+}
+
+var _ API = (*Client)(nil)
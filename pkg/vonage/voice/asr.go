@@ -0,0 +1,252 @@
+package voice
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ========================================
+// ASR Confidence
+// ========================================
+
+var (
+	// ErrNoSpeech is returned when an ASR result holds no hypotheses
+	ErrNoSpeech = errors.New("voice: no speech recognized")
+	// ErrLowConfidence is returned when no hypothesis reaches the required
+	// confidence. Flows should re-prompt the caller.
+	ErrLowConfidence = errors.New("voice: speech recognized with low confidence")
+	// ErrNoAnswerMatch is returned when no confident hypothesis matches an
+	// expected answer
+	ErrNoAnswerMatch = errors.New("voice: speech matches no expected answer")
+)
+
+// DefaultAnswerScore is the similarity MatchAnswers requires by default
+const DefaultAnswerScore = 0.8
+
+// ConfidenceValue parses the match's confidence, a string in the webhook,
+// as a number between 0 and 1
+func (m ASRMatch) ConfidenceValue() (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(m.Confidence), 64)
+	if err != nil {
+		return 0, fmt.Errorf("voice: invalid ASR confidence %q: %w", m.Confidence, err)
+	}
+	return v, nil
+}
+
+// confidence returns the parsed confidence, or 0 if it is unparseable
+func (m ASRMatch) confidence() float64 {
+	v, _ := m.ConfidenceValue()
+	return v
+}
+
+// Hypotheses returns the speech hypotheses by descending confidence
+func (r *ASRResult) Hypotheses() []ASRMatch {
+	matches := append([]ASRMatch(nil), r.Speech.Results...)
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].confidence() > matches[j].confidence()
+	})
+	return matches
+}
+
+// BestAboveThreshold returns the most confident hypothesis if its
+// confidence is at least min. It returns ErrNoSpeech without hypotheses
+// and ErrLowConfidence if none is confident enough.
+func (r *ASRResult) BestAboveThreshold(min float64) (ASRMatch, error) {
+	hypotheses := r.Hypotheses()
+	if len(hypotheses) == 0 {
+		return ASRMatch{}, ErrNoSpeech
+	}
+	if best := hypotheses[0]; best.confidence() >= min {
+		return best, nil
+	}
+	return ASRMatch{}, fmt.Errorf("%w: best %s, required %.2f", ErrLowConfidence, hypotheses[0].Confidence, min)
+}
+
+// ========================================
+// Answer Matching
+// ========================================
+
+// AnswerMatch is an expected answer matched by a speech hypothesis
+type AnswerMatch struct {
+	// Answer is the expected answer as given
+	Answer string
+	// Transcript is the hypothesis that matched
+	Transcript string
+	// Confidence is the hypothesis' ASR confidence
+	Confidence float64
+	// Score is the similarity of the normalized texts, from 0 to 1
+	Score float64
+}
+
+// MatchAnswers matches the hypotheses with a confidence of at least
+// minConfidence against the expected answers and returns the best match
+// scoring at least minScore (DefaultAnswerScore if 0). Give readings as
+// extra answers, e.g. "東京" and "とうきょう", since kanji are not
+// converted to kana.
+func (r *ASRResult) MatchAnswers(expected []string, minConfidence, minScore float64) (AnswerMatch, error) {
+	if minScore <= 0 {
+		minScore = DefaultAnswerScore
+	}
+
+	hypotheses := r.Hypotheses()
+	if len(hypotheses) == 0 {
+		return AnswerMatch{}, ErrNoSpeech
+	}
+
+	var (
+		best      AnswerMatch
+		confident bool
+	)
+	for _, h := range hypotheses {
+		confidence := h.confidence()
+		if confidence < minConfidence {
+			continue
+		}
+		confident = true
+		if m, ok := MatchAnswer(h.Text, expected, minScore); ok && m.Score > best.Score {
+			m.Confidence = confidence
+			best = m
+		}
+	}
+
+	switch {
+	case !confident:
+		return AnswerMatch{}, ErrLowConfidence
+	case best.Answer == "":
+		return AnswerMatch{}, ErrNoAnswerMatch
+	}
+	return best, nil
+}
+
+// MatchAnswer returns the expected answer most similar to transcript after
+// NormalizeAnswer, if it scores at least minScore. A transcript that
+// contains an answer, such as "I think it's Tokyo", scores 0.9.
+func MatchAnswer(transcript string, expected []string, minScore float64) (AnswerMatch, bool) {
+	got := []rune(NormalizeAnswer(transcript))
+	if len(got) == 0 {
+		return AnswerMatch{}, false
+	}
+
+	var best AnswerMatch
+	for _, answer := range expected {
+		want := []rune(NormalizeAnswer(answer))
+		if len(want) == 0 {
+			continue
+		}
+
+		var score float64
+		switch {
+		case string(got) == string(want):
+			score = 1
+		case strings.Contains(string(got), string(want)):
+			score = 0.9
+		default:
+			score = 1 - float64(editDistance(got, want))/float64(max(len(got), len(want)))
+		}
+		if score > best.Score {
+			best = AnswerMatch{Answer: answer, Transcript: transcript, Score: score}
+		}
+	}
+
+	return best, best.Answer != "" && best.Score >= minScore
+}
+
+// NormalizeAnswer folds the variations speech recognition produces for the
+// same answer: case, full-width characters, katakana (to hiragana), common
+// kanji variants (髙 to 高), the prolonged sound mark, spaces and
+// punctuation
+func NormalizeAnswer(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		// Half-width katakana, combining a following voiced sound mark
+		if kana, ok := halfwidthKana[r]; ok {
+			if i+1 < len(runes) && (runes[i+1] == 'ﾞ' || runes[i+1] == 'ﾟ') {
+				if voiced, ok := voicedKana[string([]rune{kana, runes[i+1]})]; ok {
+					kana = voiced
+					i++
+				}
+			}
+			r = kana
+		}
+
+		switch {
+		case r >= '！' && r <= '～':
+			r -= 0xFEE0
+		case r >= 'ァ' && r <= 'ヶ':
+			r -= 0x60
+		}
+		if v, ok := kanjiVariants[r]; ok {
+			r = v
+		}
+
+		if r == 'ー' || r == 'ｰ' || unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// kanjiVariants maps traditional and variant kanji, common in names, to
+// the forms recognizers usually output
+var kanjiVariants = map[rune]rune{
+	'髙': '高', '﨑': '崎', '嵜': '崎', '邊': '辺', '邉': '辺', '齋': '斎',
+	'齊': '斉', '濱': '浜', '澤': '沢', '國': '国', '學': '学', '廣': '広',
+	'櫻': '桜', '驛': '駅', '圓': '円', '會': '会', '眞': '真', '德': '徳',
+	'冨': '富', '藝': '芸', '體': '体', '黑': '黒', '發': '発', '條': '条',
+}
+
+// halfwidthKana maps half-width katakana to full-width katakana
+var halfwidthKana = func() map[rune]rune {
+	half := []rune("ｦｧｨｩｪｫｬｭｮｯｱｲｳｴｵｶｷｸｹｺｻｼｽｾｿﾀﾁﾂﾃﾄﾅﾆﾇﾈﾉﾊﾋﾌﾍﾎﾏﾐﾑﾒﾓﾔﾕﾖﾗﾘﾙﾚﾛﾜﾝ")
+	full := []rune("ヲァィゥェォャュョッアイウエオカキクケコサシスセソタチツテトナニヌネノハヒフヘホマミムメモヤユヨラリルレロワン")
+	m := make(map[rune]rune, len(half))
+	for i := range half {
+		m[half[i]] = full[i]
+	}
+	return m
+}()
+
+// voicedKana maps a full-width katakana followed by a half-width (semi-)
+// voiced sound mark to the combined katakana
+var voicedKana = func() map[string]rune {
+	plain := []rune("カキクケコサシスセソタチツテトハヒフヘホウ")
+	voiced := []rune("ガギグゲゴザジズゼゾダヂヅデドバビブベボヴ")
+	m := make(map[string]rune, len(plain)+5)
+	for i := range plain {
+		m[string([]rune{plain[i], 'ﾞ'})] = voiced[i]
+	}
+	semi := []rune("パピプペポ")
+	for i, r := range []rune("ハヒフヘホ") {
+		m[string([]rune{r, 'ﾟ'})] = semi[i]
+	}
+	return m
+}()
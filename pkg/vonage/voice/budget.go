@@ -0,0 +1,28 @@
+package voice
+
+import (
+	"context"
+	"strconv"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// Call Budget
+// ========================================
+
+// RecordCallUsage charges a completed call's minutes and price against b.
+// Other events are ignored, so every call event can be passed. Calls
+// without a price are charged the budget's estimated per-minute price.
+func RecordCallUsage(ctx context.Context, b *vonage.Budget, event *CallEvent) error {
+	if event.Status != CallStatusCompleted {
+		return nil
+	}
+
+	seconds, _ := strconv.ParseFloat(event.Duration, 64)
+	price, _ := strconv.ParseFloat(event.Price, 64)
+	if seconds <= 0 && price <= 0 {
+		return nil
+	}
+	return b.Record(ctx, vonage.Usage{CallMinutes: seconds / 60, Spend: price})
+}
@@ -0,0 +1,265 @@
+package voice
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ========================================
+// Call Info Decoding
+// ========================================
+
+// PriceDecimals is the number of decimal places a Price holds, matching the
+// precision of the API's prices and rates
+const PriceDecimals = 8
+
+const priceUnit = 100000000 // 10^PriceDecimals
+
+// Price is an exact decimal amount in the account currency, e.g. the price
+// or per-minute rate of a call. Unlike float64, sums of many prices do not
+// drift.
+type Price int64
+
+// ParsePrice parses a decimal price such as "0.01270000"
+func ParsePrice(s string) (Price, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	neg := false
+	digits := s
+	switch digits[0] {
+	case '-':
+		neg = true
+		digits = digits[1:]
+	case '+':
+		digits = digits[1:]
+	}
+
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("voice: invalid price %q", s)
+	}
+	if len(frac) > PriceDecimals {
+		// Anything past the precision the API uses must be zero
+		if strings.Trim(frac[PriceDecimals:], "0") != "" {
+			return 0, fmt.Errorf("voice: price %q has more than %d decimals", s, PriceDecimals)
+		}
+		frac = frac[:PriceDecimals]
+	}
+	frac += strings.Repeat("0", PriceDecimals-len(frac))
+
+	var units int64
+	for _, part := range []string{whole, frac} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return 0, fmt.Errorf("voice: invalid price %q", s)
+			}
+			if units > (math.MaxInt64-9)/10 {
+				return 0, fmt.Errorf("voice: price %q out of range", s)
+			}
+			units = units*10 + int64(c-'0')
+		}
+	}
+	if neg {
+		units = -units
+	}
+	return Price(units), nil
+}
+
+// Float64 returns the price as a float, e.g. for display or metrics
+func (p Price) Float64() float64 {
+	return float64(p) / priceUnit
+}
+
+// String formats the price with PriceDecimals decimals, like the API
+func (p Price) String() string {
+	sign := ""
+	u := uint64(p)
+	if p < 0 {
+		sign = "-"
+		u = uint64(-p)
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, u/priceUnit, PriceDecimals, u%priceUnit)
+}
+
+// MarshalJSON encodes the price as a decimal string, like the API
+func (p Price) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON accepts a decimal string or a JSON number
+func (p *Price) UnmarshalJSON(data []byte) error {
+	s, err := unquoteScalar(data)
+	if err != nil {
+		return fmt.Errorf("voice: invalid price %s", data)
+	}
+	price, err := ParsePrice(s)
+	if err != nil {
+		return err
+	}
+	*p = price
+	return nil
+}
+
+// timestampLayouts are the formats the API has been seen to send
+// timestamps in, tried in order
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// ParseTimestamp parses a timestamp from a Voice API response or webhook.
+// Besides RFC 3339 it accepts offsets without a colon ("+0000"), a space
+// instead of "T", no offset at all, which is taken as UTC, and Unix times in
+// seconds or milliseconds.
+func ParseTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n > 1e12 {
+			return time.UnixMilli(n).UTC(), nil
+		}
+		return time.Unix(n, 0).UTC(), nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("voice: unrecognized timestamp %q", s)
+}
+
+// parseSeconds parses a duration in seconds, such as "60" or "1.5"
+func parseSeconds(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("voice: invalid duration %q", s)
+	}
+	return time.Duration(math.Round(seconds * float64(time.Second))), nil
+}
+
+// unquoteScalar returns a JSON string or number as a string, and null as ""
+func unquoteScalar(data []byte) (string, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return "", nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		err := json.Unmarshal(data, &s)
+		return s, err
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return "", err
+	}
+	return n.String(), nil
+}
+
+// callInfoJSON is the wire format of CallInfo, with the fields the API
+// sends as strings in varying formats left raw
+type callInfoJSON struct {
+	UUID             string          `json:"uuid"`
+	Status           CallStatus      `json:"status"`
+	Direction        CallDirection   `json:"direction"`
+	Rate             Price           `json:"rate"`
+	Price            Price           `json:"price"`
+	Duration         json.RawMessage `json:"duration,omitempty"`
+	StartTime        json.RawMessage `json:"start_time,omitempty"`
+	EndTime          json.RawMessage `json:"end_time,omitempty"`
+	ConversationUUID string          `json:"conversation_uuid"`
+	Network          string          `json:"network,omitempty"`
+	To               Endpoint        `json:"to,omitempty"`
+	From             Endpoint        `json:"from,omitempty"`
+}
+
+// UnmarshalJSON decodes a call from the API, parsing the duration in
+// seconds and timestamps with ParseTimestamp
+func (c *CallInfo) UnmarshalJSON(data []byte) error {
+	var raw callInfoJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	duration, err := decodeField(raw.Duration, parseSeconds)
+	if err != nil {
+		return err
+	}
+	start, err := decodeField(raw.StartTime, ParseTimestamp)
+	if err != nil {
+		return err
+	}
+	end, err := decodeField(raw.EndTime, ParseTimestamp)
+	if err != nil {
+		return err
+	}
+
+	*c = CallInfo{
+		UUID:             raw.UUID,
+		Status:           raw.Status,
+		Direction:        raw.Direction,
+		Rate:             raw.Rate,
+		Price:            raw.Price,
+		Duration:         duration,
+		StartTime:        start,
+		EndTime:          end,
+		ConversationUUID: raw.ConversationUUID,
+		Network:          raw.Network,
+		To:               raw.To,
+		From:             raw.From,
+	}
+	return nil
+}
+
+// MarshalJSON encodes a call in the API's format, with the duration in
+// whole seconds and unset timestamps omitted
+func (c CallInfo) MarshalJSON() ([]byte, error) {
+	raw := callInfoJSON{
+		UUID:             c.UUID,
+		Status:           c.Status,
+		Direction:        c.Direction,
+		Rate:             c.Rate,
+		Price:            c.Price,
+		ConversationUUID: c.ConversationUUID,
+		Network:          c.Network,
+		To:               c.To,
+		From:             c.From,
+	}
+	raw.Duration, _ = json.Marshal(strconv.FormatInt(int64(c.Duration/time.Second), 10))
+	if !c.StartTime.IsZero() {
+		raw.StartTime, _ = json.Marshal(c.StartTime.Format(time.RFC3339Nano))
+	}
+	if !c.EndTime.IsZero() {
+		raw.EndTime, _ = json.Marshal(c.EndTime.Format(time.RFC3339Nano))
+	}
+	return json.Marshal(raw)
+}
+
+// decodeField parses a raw JSON string or number field with parse
+func decodeField[T any](data json.RawMessage, parse func(string) (T, error)) (T, error) {
+	var zero T
+	if len(data) == 0 {
+		return zero, nil
+	}
+	s, err := unquoteScalar(data)
+	if err != nil {
+		return zero, fmt.Errorf("voice: invalid call field %s", data)
+	}
+	return parse(s)
+}
@@ -0,0 +1,205 @@
+package voice
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// callInfoPayload is a GET /v1/calls/:uuid response as returned by the API
+const callInfoPayload = `{
+  "_links": {"self": {"href": "/calls/63f61863-4a51-4f6b-86e1-46edebcf9356"}},
+  "uuid": "63f61863-4a51-4f6b-86e1-46edebcf9356",
+  "conversation_uuid": "CON-f972836a-550f-45fa-956c-12a2ab5b7d22",
+  "to": {"type": "phone", "number": "447700900000"},
+  "from": {"type": "phone", "number": "447700900001"},
+  "status": "completed",
+  "direction": "outbound",
+  "rate": "0.39000000",
+  "price": "23.40000000",
+  "duration": "60",
+  "start_time": "2020-01-01 12:00:00",
+  "end_time": "2020-01-01 12:01:00",
+  "network": "65512"
+}`
+
+func TestCallInfoUnmarshalPayload(t *testing.T) {
+	var info CallInfo
+	if err := json.Unmarshal([]byte(callInfoPayload), &info); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if info.Duration != time.Minute {
+		t.Errorf("Duration = %s, want 1m0s", info.Duration)
+	}
+	if want := Price(39000000); info.Rate != want {
+		t.Errorf("Rate = %s, want %s", info.Rate, want)
+	}
+	if want := Price(2340000000); info.Price != want {
+		t.Errorf("Price = %s, want %s", info.Price, want)
+	}
+	if want := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC); !info.StartTime.Equal(want) {
+		t.Errorf("StartTime = %s, want %s", info.StartTime, want)
+	}
+	if got := info.EndTime.Sub(info.StartTime); got != info.Duration {
+		t.Errorf("EndTime - StartTime = %s, want %s", got, info.Duration)
+	}
+	if info.Status != CallStatusCompleted || info.To.Number != "447700900000" || info.Network != "65512" {
+		t.Errorf("unexpected fields: %+v", info)
+	}
+}
+
+func TestCallInfoUnmarshalInProgress(t *testing.T) {
+	// Calls in progress have no end time, duration or price yet
+	payload := `{"uuid":"CALL-1","status":"answered","rate":"0.01270000","start_time":"2024-04-01T09:00:00.000Z","end_time":null}`
+
+	var info CallInfo
+	if err := json.Unmarshal([]byte(payload), &info); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if info.Duration != 0 || info.Price != 0 || !info.EndTime.IsZero() {
+		t.Errorf("got duration %s, price %s, end %s; want zero values", info.Duration, info.Price, info.EndTime)
+	}
+	if info.Rate.String() != "0.01270000" {
+		t.Errorf("Rate = %s, want 0.01270000", info.Rate)
+	}
+}
+
+func TestCallInfoUnmarshalInvalid(t *testing.T) {
+	for _, payload := range []string{
+		`{"duration":"a minute"}`,
+		`{"price":"0.1.2"}`,
+		`{"start_time":"yesterday"}`,
+		`{"end_time":true}`,
+	} {
+		var info CallInfo
+		if err := json.Unmarshal([]byte(payload), &info); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded, want error", payload)
+		}
+	}
+}
+
+func TestCallInfoRoundTrip(t *testing.T) {
+	var info CallInfo
+	if err := json.Unmarshal([]byte(callInfoPayload), &info); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Unmarshal map: %v", err)
+	}
+	want := map[string]string{
+		"duration":   "60",
+		"rate":       "0.39000000",
+		"price":      "23.40000000",
+		"start_time": "2020-01-01T12:00:00Z",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("%s = %v, want %q", k, fields[k], v)
+		}
+	}
+
+	var again CallInfo
+	if err := json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("Unmarshal again: %v", err)
+	}
+	if again != info {
+		t.Errorf("round trip = %+v, want %+v", again, info)
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Price
+		wantErr bool
+	}{
+		{in: "", want: 0},
+		{in: "0", want: 0},
+		{in: "0.0045", want: 450000},
+		{in: "23.40000000", want: 2340000000},
+		{in: ".5", want: 50000000},
+		{in: "-0.01", want: -1000000},
+		{in: "1.1234567800", want: 112345678},
+		{in: "1.123456789", wantErr: true},
+		{in: "1e-3", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: "99999999999999999999", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePrice(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePrice(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePrice(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPriceSumIsExact(t *testing.T) {
+	// 0.1 has no exact float64 representation; ten of them must still be 1
+	var sum Price
+	for i := 0; i < 10; i++ {
+		var p Price
+		if err := json.Unmarshal([]byte(`"0.10000000"`), &p); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		sum += p
+	}
+	if sum.String() != "1.00000000" {
+		t.Errorf("sum = %s, want 1.00000000", sum)
+	}
+}
+
+func TestPriceUnmarshalNumber(t *testing.T) {
+	var p Price
+	if err := json.Unmarshal([]byte(`0.0127`), &p); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if p != 1270000 {
+		t.Errorf("p = %d, want 1270000", p)
+	}
+	if got := Price(-1270000).String(); got != "-0.01270000" {
+		t.Errorf("String() = %s, want -0.01270000", got)
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, in := range []string{
+		"2020-01-01T12:00:00Z",
+		"2020-01-01T12:00:00.000Z",
+		"2020-01-01T12:00:00+00:00",
+		"2020-01-01T12:00:00.000+0000",
+		"2020-01-01T21:00:00+0900",
+		"2020-01-01T12:00:00",
+		"2020-01-01 12:00:00",
+		"2020-01-01 12:00:00.000",
+		"2020-01-01 12:00:00 +0000",
+		"1577880000",
+		"1577880000000",
+	} {
+		got, err := ParseTimestamp(in)
+		if err != nil {
+			t.Errorf("ParseTimestamp(%q): %v", in, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("ParseTimestamp(%q) = %s, want %s", in, got, want)
+		}
+	}
+
+	for _, in := range []string{"01/01/2020", "2020-13-01T00:00:00Z", "noon"} {
+		if _, err := ParseTimestamp(in); err == nil {
+			t.Errorf("ParseTimestamp(%q) succeeded, want error", in)
+		}
+	}
+}
@@ -1,22 +1,21 @@
 package voice
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
 	"time"
 
-	"github.com/rs/zerolog/log"
-
 	vonage "github.com/vonatrigger/poc/pkg/vonage"
 )
 
 const (
 	// BaseURL is the Vonage Voice API base URL
-	BaseURL = "https://api.nexmo.com"
+	BaseURL = vonage.BaseURLREST
 )
 
 // Client handles Vonage Voice API operations
@@ -25,6 +24,15 @@ type Client struct {
 	phoneNumber  string
 	jwtGenerator *vonage.JWTGenerator
 	httpClient   *http.Client
+	middleware   []vonage.Middleware
+	transport    *vonage.Transport
+	logger       vonage.Logger
+	uaSuffix     string
+	mode         vonage.Mode
+	budget       *vonage.Budget
+	allowlist    *vonage.DestinationAllowlist
+	guard        *callGuard
+	urls         *URLBuilder
 }
 
 // ClientOption is a functional option for configuring the voice client
@@ -51,18 +59,143 @@ func WithPhoneNumber(number string) ClientOption {
 	}
 }
 
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l vonage.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier such as
+// "checkin-service/1.4" to the User-Agent
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.uaSuffix = suffix
+	}
+}
+
+// WithMiddleware adds transport middleware (retries, tracing, metrics)
+func WithMiddleware(mw ...vonage.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithRequestInterceptor runs fn on every outgoing request, e.g. to add
+// headers or inject faults in tests
+func WithRequestInterceptor(fn vonage.RequestInterceptor) ClientOption {
+	return WithMiddleware(vonage.InterceptRequest(fn))
+}
+
+// WithResponseInterceptor runs fn on every response before it is decoded
+func WithResponseInterceptor(fn vonage.ResponseInterceptor) ClientOption {
+	return WithMiddleware(vonage.InterceptResponse(fn))
+}
+
+// WithMode sets the client's mode (default vonage.ModeLive). In
+// vonage.ModeDryRun calls are logged instead of placed, and outside
+// vonage.ModeLive WithAllowedDestinations applies. With WithTransport the
+// transport's own mode decides what is sent.
+func WithMode(m vonage.Mode) ClientOption {
+	return func(c *Client) {
+		c.mode = m
+	}
+}
+
+// WithSettings applies the HTTP client, logger, middleware, User-Agent
+// suffix and mode shared by a root vonage.Client, see
+// vonage.Client.Settings
+func WithSettings(s vonage.Settings) ClientOption {
+	return func(c *Client) {
+		if s.HTTPClient != nil {
+			c.httpClient = s.HTTPClient
+		}
+		if s.Logger != nil {
+			c.logger = s.Logger
+		}
+		c.middleware = append(c.middleware, s.Middleware...)
+		c.uaSuffix = s.UserAgentSuffix
+		c.mode = s.Mode
+	}
+}
+
+// WithBudget charges every call against b; calls over its limits fail
+// with vonage.ErrBudgetExceeded. Feed completed call events to
+// RecordCallUsage to charge call minutes and prices.
+func WithBudget(b *vonage.Budget) ClientOption {
+	return func(c *Client) {
+		c.budget = b
+	}
+}
+
+// WithTransport uses a shared transport instead of building one. It must
+// point at the Voice API host (BaseURL, vonage.BaseURLREST), which voice
+// shares with messages, users and media but not video. Requests then use
+// only the transport's configuration: WithBaseURL, WithHTTPClient,
+// WithMiddleware, WithRequestInterceptor, WithResponseInterceptor,
+// WithUserAgentSuffix and the matching parts of WithSettings are ignored,
+// the JWT generator passed to NewClient does not sign requests, and
+// WithLogger and WithMode apply to the client's own logs and destination
+// allowlist but not to request logging or mock responses.
+func WithTransport(t *vonage.Transport) ClientOption {
+	return func(c *Client) {
+		c.transport = t
+	}
+}
+
+// WithURLSigner signs the custom data CreateCall adds to answer and event
+// URLs with b's secret (see WithURLSigningSecret), so that webhook
+// handlers can verify it with the same builder. CreateCall refuses custom
+// data without it.
+func WithURLSigner(b *URLBuilder) ClientOption {
+	return func(c *Client) {
+		c.urls = b
+	}
+}
+
+// WithAllowedDestinations restricts calls to destinations matching
+// patterns (see vonage.NewDestinationAllowlist) unless the client runs in
+// vonage.ModeLive. Other calls fail with vonage.ErrDestinationBlocked.
+func WithAllowedDestinations(patterns ...string) ClientOption {
+	return func(c *Client) {
+		c.allowlist = vonage.NewDestinationAllowlist(patterns...)
+	}
+}
+
+// WithCallSerialization serializes the requests that change a call
+// (transfer, hangup, mute, earmuff, DTMF, talk and stream) per call UUID,
+// so webhook handlers acting on the same call concurrently don't fail
+// with 400 "action in progress". Requests for different calls still run
+// in parallel.
+func WithCallSerialization() ClientOption {
+	return func(c *Client) {
+		c.guard = newCallGuard()
+	}
+}
+
 // NewClient creates a new Vonage Voice API client
 func NewClient(jwtGenerator *vonage.JWTGenerator, opts ...ClientOption) *Client {
 	c := &Client{
 		baseURL:      BaseURL,
 		jwtGenerator: jwtGenerator,
 		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		logger:       vonage.NopLogger(),
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	if c.transport == nil {
+		c.transport = vonage.NewTransport(c.baseURL, jwtGenerator,
+			vonage.WithTransportHTTPClient(c.httpClient),
+			vonage.WithMiddleware(c.middleware...),
+			vonage.WithTransportLogger(c.logger),
+			vonage.WithTransportUserAgentSuffix(c.uaSuffix),
+			vonage.WithTransportMode(c.mode),
+		)
+	}
+
 	return c
 }
 
@@ -72,8 +205,9 @@ func NewClientFromCredentials(creds *vonage.Credentials, opts ...ClientOption) (
 		return nil, vonage.ErrNotConfigured
 	}
 
-	jwtGen := vonage.NewJWTGenerator(creds.AppID, creds.PrivateKey)
-	allOpts := make([]ClientOption, 0, len(opts)+1)
+	jwtGen := vonage.NewJWTGeneratorFromCredentials(creds)
+	allOpts := make([]ClientOption, 0, len(opts)+2)
+	allOpts = append(allOpts, WithMode(creds.Mode))
 	if creds.PhoneNumber != "" {
 		allOpts = append(allOpts, WithPhoneNumber(creds.PhoneNumber))
 	}
@@ -93,6 +227,10 @@ func (c *Client) PhoneNumber() string {
 
 // CreateCall initiates a new outbound call
 func (c *Client) CreateCall(ctx context.Context, opts CreateCallOptions) (*CreateCallResponse, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
 	from := Endpoint{Type: EndpointTypePhone, Number: c.phoneNumber}
 	if opts.From != nil {
 		from = *opts.From
@@ -113,7 +251,7 @@ func (c *Client) CreateCall(ctx context.Context, opts CreateCallOptions) (*Creat
 		if opts.AnswerMethod != "" {
 			req.AnswerMethod = opts.AnswerMethod
 		} else {
-			req.AnswerMethod = "POST"
+			req.AnswerMethod = MethodPOST
 		}
 	}
 
@@ -122,7 +260,17 @@ func (c *Client) CreateCall(ctx context.Context, opts CreateCallOptions) (*Creat
 		if opts.EventMethod != "" {
 			req.EventMethod = opts.EventMethod
 		} else {
-			req.EventMethod = "POST"
+			req.EventMethod = MethodPOST
+		}
+	}
+
+	for _, urls := range [][]string{req.AnswerURL, req.EventURL} {
+		for i, u := range urls {
+			tagged, err := withCustomData(u, opts.CustomData, c.urls)
+			if err != nil {
+				return nil, err
+			}
+			urls[i] = tagged
 		}
 	}
 
@@ -148,40 +296,28 @@ func (c *Client) CreateCallWithNCCO(ctx context.Context, toNumber string, ncco N
 }
 
 func (c *Client) doCreateCall(ctx context.Context, req CreateCallRequest) (*CreateCallResponse, error) {
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/v1/calls", bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	if err := c.setAuthHeaders(httpReq); err != nil {
-		return nil, err
-	}
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("API request failed: %w", err)
+	for _, to := range req.To {
+		if err := c.allowlist.Check(c.mode, to.Destination()); err != nil {
+			return nil, err
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return nil, vonage.NewError(resp.StatusCode, string(respBody))
+	usage := vonage.Usage{Calls: 1}
+	if c.budget != nil {
+		if err := c.budget.Reserve(ctx, usage); err != nil {
+			return nil, err
+		}
 	}
 
 	var callResp CreateCallResponse
-	if err := json.NewDecoder(resp.Body).Decode(&callResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.transport.Do(ctx, http.MethodPost, "/v1/calls", req, &callResp); err != nil {
+		if c.budget != nil {
+			c.budget.Release(ctx, usage)
+		}
+		return nil, err
 	}
 
-	log.Debug().
-		Str("uuid", callResp.UUID).
-		Str("status", callResp.Status).
-		Msg("Call created")
+	c.logger.Debug("Call created", "uuid", callResp.UUID, "status", callResp.Status)
 
 	return &callResp, nil
 }
@@ -192,32 +328,127 @@ func (c *Client) doCreateCall(ctx context.Context, req CreateCallRequest) (*Crea
 
 // GetCallInfo retrieves information about a specific call
 func (c *Client) GetCallInfo(ctx context.Context, callUUID string) (*CallInfo, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/v1/calls/%s", c.baseURL, callUUID), nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var callInfo CallInfo
+	if err := c.transport.Do(ctx, http.MethodGet, callPath(callUUID, ""), nil, &callInfo); err != nil {
+		return nil, err
 	}
+	return &callInfo, nil
+}
+
+// ========================================
+// List Calls
+// ========================================
 
-	if err := c.setAuthHeaders(httpReq); err != nil {
+// ListCalls returns one page of calls matching opts
+func (c *Client) ListCalls(ctx context.Context, opts *ListCallsOptions) (*CallList, error) {
+	var resp listCallsResponse
+	if err := c.transport.Do(ctx, http.MethodGet, "/v1/calls"+opts.query(), nil, &resp); err != nil {
 		return nil, err
 	}
+	return &CallList{
+		Count:       resp.Count,
+		PageSize:    resp.PageSize,
+		RecordIndex: resp.RecordIndex,
+		Calls:       resp.Embedded.Calls,
+	}, nil
+}
+
+// NewCallPager returns a pager over the calls matching opts, from
+// opts.RecordIndex on
+func NewCallPager(c API, opts *ListCallsOptions, pagerOpts ...vonage.PagerOption) *vonage.Pager[CallInfo] {
+	var base ListCallsOptions
+	if opts != nil {
+		base = *opts
+	}
+	return vonage.NewPager(func(ctx context.Context, token string) ([]CallInfo, string, error) {
+		o := base
+		if token != "" {
+			o.RecordIndex, _ = strconv.Atoi(token)
+		}
+		list, err := c.ListCalls(ctx, &o)
+		if err != nil {
+			return nil, "", err
+		}
+		if !list.HasNext() {
+			return list.Calls, "", nil
+		}
+		return list.Calls, strconv.Itoa(list.RecordIndex + len(list.Calls)), nil
+	}, pagerOpts...)
+}
 
-	resp, err := c.httpClient.Do(httpReq)
+// GetCallsByConversation lists every leg of a conversation, across pages,
+// in the order they started, e.g. to hang up all of them when a session
+// ends
+func (c *Client) GetCallsByConversation(ctx context.Context, conversationUUID string) ([]CallInfo, error) {
+	legs, err := NewCallPager(c, &ListCallsOptions{
+		ConversationUUID: conversationUUID,
+		PageSize:         100,
+		Order:            "asc",
+	}).All(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("API request failed: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return nil, vonage.NewError(resp.StatusCode, string(respBody))
+	sort.SliceStable(legs, func(i, j int) bool {
+		return legs[i].StartTime.Before(legs[j].StartTime)
+	})
+	return legs, nil
+}
+
+// GetConversationLegs lists every leg of a conversation, across pages,
+// and sums their durations and prices
+func (c *Client) GetConversationLegs(ctx context.Context, conversationUUID string) (*ConversationLegs, error) {
+	legs, err := c.GetCallsByConversation(ctx, conversationUUID)
+	if err != nil {
+		return nil, err
 	}
 
-	var callInfo CallInfo
-	if err := json.NewDecoder(resp.Body).Decode(&callInfo); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	result := &ConversationLegs{ConversationUUID: conversationUUID, Legs: legs}
+	for _, leg := range legs {
+		result.Duration += leg.Duration
+		result.Price += leg.Price
+
+		if !leg.StartTime.IsZero() && (result.StartTime.IsZero() || leg.StartTime.Before(result.StartTime)) {
+			result.StartTime = leg.StartTime
+		}
+		if leg.EndTime.After(result.EndTime) {
+			result.EndTime = leg.EndTime
+		}
 	}
+	return result, nil
+}
 
-	return &callInfo, nil
+// query encodes list options
+func (o *ListCallsOptions) query() string {
+	if o == nil {
+		return ""
+	}
+	q := url.Values{}
+	if o.Status != "" {
+		q.Set("status", string(o.Status))
+	}
+	if !o.DateStart.IsZero() {
+		q.Set("date_start", o.DateStart.UTC().Format(time.RFC3339))
+	}
+	if !o.DateEnd.IsZero() {
+		q.Set("date_end", o.DateEnd.UTC().Format(time.RFC3339))
+	}
+	if o.PageSize > 0 {
+		q.Set("page_size", strconv.Itoa(o.PageSize))
+	}
+	if o.RecordIndex > 0 {
+		q.Set("record_index", strconv.Itoa(o.RecordIndex))
+	}
+	if o.Order != "" {
+		q.Set("order", o.Order)
+	}
+	if o.ConversationUUID != "" {
+		q.Set("conversation_uuid", o.ConversationUUID)
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
 }
 
 // ========================================
@@ -234,35 +465,11 @@ func (c *Client) TransferCall(ctx context.Context, callUUID, nccoURL string) err
 		},
 	}
 
-	body, err := json.Marshal(req)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/v1/calls/%s", c.baseURL, callUUID), bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	if err := c.setAuthHeaders(httpReq); err != nil {
+	if err := c.modifyCall(ctx, http.MethodPut, callUUID, "", req); err != nil {
 		return err
 	}
 
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		respBody, _ := io.ReadAll(resp.Body)
-		return vonage.NewError(resp.StatusCode, string(respBody))
-	}
-
-	log.Debug().
-		Str("callUUID", callUUID).
-		Str("nccoURL", nccoURL).
-		Msg("Call transferred")
+	c.logger.Debug("Call transferred", "callUUID", callUUID, "nccoURL", nccoURL)
 
 	return nil
 }
@@ -274,34 +481,11 @@ func (c *Client) TransferCall(ctx context.Context, callUUID, nccoURL string) err
 // HangupCall terminates an active call
 func (c *Client) HangupCall(ctx context.Context, callUUID string) error {
 	reqBody := map[string]string{"action": "hangup"}
-	body, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/v1/calls/%s", c.baseURL, callUUID), bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	if err := c.setAuthHeaders(httpReq); err != nil {
+	if err := c.modifyCall(ctx, http.MethodPut, callUUID, "", reqBody); err != nil {
 		return err
 	}
 
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		respBody, _ := io.ReadAll(resp.Body)
-		return vonage.NewError(resp.StatusCode, string(respBody))
-	}
-
-	log.Debug().
-		Str("callUUID", callUUID).
-		Msg("Call hung up")
+	c.logger.Debug("Call hung up", "callUUID", callUUID)
 
 	return nil
 }
@@ -332,35 +516,11 @@ func (c *Client) UnearmuffCall(ctx context.Context, callUUID string) error {
 
 func (c *Client) callAction(ctx context.Context, callUUID, action string) error {
 	reqBody := map[string]string{"action": action}
-	body, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/v1/calls/%s", c.baseURL, callUUID), bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	if err := c.setAuthHeaders(httpReq); err != nil {
+	if err := c.modifyCall(ctx, http.MethodPut, callUUID, "", reqBody); err != nil {
 		return err
 	}
 
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		respBody, _ := io.ReadAll(resp.Body)
-		return vonage.NewError(resp.StatusCode, string(respBody))
-	}
-
-	log.Debug().
-		Str("callUUID", callUUID).
-		Str("action", action).
-		Msg("Call action executed")
+	c.logger.Debug("Call action executed", "callUUID", callUUID, "action", action)
 
 	return nil
 }
@@ -372,32 +532,7 @@ func (c *Client) callAction(ctx context.Context, callUUID, action string) error
 // SendDTMF sends DTMF tones to an active call
 func (c *Client) SendDTMF(ctx context.Context, callUUID, digits string) error {
 	reqBody := map[string]string{"digits": digits}
-	body, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/v1/calls/%s/dtmf", c.baseURL, callUUID), bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	if err := c.setAuthHeaders(httpReq); err != nil {
-		return err
-	}
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return vonage.NewError(resp.StatusCode, string(respBody))
-	}
-
-	return nil
+	return c.modifyCall(ctx, http.MethodPut, callUUID, "/dtmf", reqBody)
 }
 
 // ========================================
@@ -405,63 +540,25 @@ func (c *Client) SendDTMF(ctx context.Context, callUUID, digits string) error {
 // ========================================
 
 // TalkIntoCall sends a TTS message into an active call
-func (c *Client) TalkIntoCall(ctx context.Context, callUUID, text, voiceName string, loop int) error {
-	reqBody := map[string]interface{}{
-		"text":      text,
-		"voice_name": voiceName,
-		"loop":      loop,
-	}
-	body, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/v1/calls/%s/talk", c.baseURL, callUUID), bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	if err := c.setAuthHeaders(httpReq); err != nil {
+func (c *Client) TalkIntoCall(ctx context.Context, callUUID string, opts TalkIntoCallOptions) error {
+	if err := opts.Loop.Validate(); err != nil {
 		return err
 	}
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
+	if err := validateLevel(opts.Level); err != nil {
+		return err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return vonage.NewError(resp.StatusCode, string(respBody))
+	reqBody := talkIntoCallRequest{
+		Text:      opts.Text,
+		VoiceName: opts.VoiceName,
+		Loop:      opts.Loop.param(),
+		Level:     opts.Level,
 	}
-
-	return nil
+	return c.modifyCall(ctx, http.MethodPut, callUUID, "/talk", reqBody)
 }
 
 // StopTalk stops TTS in an active call
 func (c *Client) StopTalk(ctx context.Context, callUUID string) error {
-	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/v1/calls/%s/talk", c.baseURL, callUUID), nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	if err := c.setAuthHeaders(httpReq); err != nil {
-		return err
-	}
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		respBody, _ := io.ReadAll(resp.Body)
-		return vonage.NewError(resp.StatusCode, string(respBody))
-	}
-
-	return nil
+	return c.modifyCall(ctx, http.MethodDelete, callUUID, "/talk", nil)
 }
 
 // ========================================
@@ -469,74 +566,69 @@ func (c *Client) StopTalk(ctx context.Context, callUUID string) error {
 // ========================================
 
 // StreamIntoCall streams audio into an active call
-func (c *Client) StreamIntoCall(ctx context.Context, callUUID string, streamURL string, loop int) error {
-	reqBody := map[string]interface{}{
-		"stream_url": []string{streamURL},
-		"loop":       loop,
-	}
-	body, err := json.Marshal(reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("%s/v1/calls/%s/stream", c.baseURL, callUUID), bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	if err := c.setAuthHeaders(httpReq); err != nil {
+func (c *Client) StreamIntoCall(ctx context.Context, callUUID string, opts StreamIntoCallOptions) error {
+	if err := opts.Loop.Validate(); err != nil {
 		return err
 	}
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
+	if err := validateLevel(opts.Level); err != nil {
+		return err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return vonage.NewError(resp.StatusCode, string(respBody))
+	reqBody := streamIntoCallRequest{
+		StreamURL: []string{opts.StreamURL},
+		Loop:      opts.Loop.param(),
+		Level:     opts.Level,
 	}
-
-	return nil
+	return c.modifyCall(ctx, http.MethodPut, callUUID, "/stream", reqBody)
 }
 
 // StopStream stops audio streaming in an active call
 func (c *Client) StopStream(ctx context.Context, callUUID string) error {
-	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("%s/v1/calls/%s/stream", c.baseURL, callUUID), nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+	return c.modifyCall(ctx, http.MethodDelete, callUUID, "/stream", nil)
+}
 
-	if err := c.setAuthHeaders(httpReq); err != nil {
-		return err
-	}
+// ========================================
+// Recordings
+// ========================================
 
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
-	defer resp.Body.Close()
+// DownloadRecording streams a call recording to w. recordingURL is the
+// recording_url from the record event. Recordings can be large; pass a
+// context from vonage.WithRequestTimeout to allow more than the client's
+// timeout, and opts such as vonage.WithProgress, vonage.WithResume and
+// vonage.WithSHA256. URLs outside the Vonage API domains fail with
+// vonage.ErrUntrustedHost, so a forged record event cannot obtain the
+// client's credentials.
+func (c *Client) DownloadRecording(ctx context.Context, recordingURL string, w io.Writer, opts ...vonage.DownloadOption) (int64, error) {
+	return c.transport.Download(ctx, recordingURL, w, opts...)
+}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		respBody, _ := io.ReadAll(resp.Body)
-		return vonage.NewError(resp.StatusCode, string(respBody))
-	}
+// ========================================
+// Request helpers
+// ========================================
 
-	return nil
+// callPath returns the path of a call resource with an optional suffix
+func callPath(callUUID, suffix string) string {
+	return fmt.Sprintf("/v1/calls/%s%s", callUUID, suffix)
 }
 
 // ========================================
-// Auth helpers
+// Health
 // ========================================
 
-func (c *Client) setAuthHeaders(req *http.Request) error {
-	token, err := c.jwtGenerator.GenerateAPIJWT()
-	if err != nil {
-		return fmt.Errorf("failed to generate JWT: %w", err)
+// Ping checks the credentials and connectivity for readiness probes; it
+// lists one call
+func (c *Client) Ping(ctx context.Context) *vonage.Health {
+	return c.transport.Ping(ctx, "voice", "/v1/calls?page_size=1")
+}
+
+// modifyCall sends a request that changes a call, one at a time per call
+// with WithCallSerialization
+func (c *Client) modifyCall(ctx context.Context, method, callUUID, suffix string, body interface{}) error {
+	if c.guard != nil {
+		unlock, err := c.guard.lock(ctx, callUUID)
+		if err != nil {
+			return err
+		}
+		defer unlock()
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
-	return nil
+	return c.transport.Do(ctx, method, callPath(callUUID, suffix), body, nil)
 }
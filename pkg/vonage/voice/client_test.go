@@ -0,0 +1,88 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// bearerAuth authenticates requests with a fixed token
+type bearerAuth struct{}
+
+func (bearerAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer app-jwt")
+	return nil
+}
+
+// recordingHost serves a recording and counts the requests that carried
+// an Authorization header
+func recordingHost(t *testing.T, requests, authorized *atomic.Int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "" {
+			authorized.Add(1)
+		}
+		w.Write([]byte("RIFF"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDownloadRecordingHosts(t *testing.T) {
+	var reported atomic.Int32
+	resume := []vonage.DownloadOption{
+		vonage.WithResume(3, 0),
+		vonage.WithSHA256("00"),
+		vonage.WithProgress(func(vonage.DownloadProgress) { reported.Add(1) }),
+	}
+
+	tests := []struct {
+		name    string
+		foreign bool
+		opts    []vonage.DownloadOption
+		wantErr error
+	}{
+		{"API host", false, nil, nil},
+		{"foreign host", true, nil, vonage.ErrUntrustedHost},
+		{"foreign host with resume", true, resume, vonage.ErrUntrustedHost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var apiRequests, apiAuthorized, foreignRequests, foreignAuthorized atomic.Int32
+			api := recordingHost(t, &apiRequests, &apiAuthorized)
+			foreign := recordingHost(t, &foreignRequests, &foreignAuthorized)
+			client := NewClient(nil, WithTransport(vonage.NewTransport(api.URL, bearerAuth{})))
+
+			// As a record event names it, possibly forged
+			recordingURL := api.URL + "/v1/files/rec-1"
+			if tt.foreign {
+				recordingURL = foreign.URL + "/v1/files/rec-1"
+			}
+
+			var buf bytes.Buffer
+			_, err := client.DownloadRecording(context.Background(), recordingURL, &buf, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DownloadRecording() = %v, want %v", err, tt.wantErr)
+			}
+			if n := foreignAuthorized.Load(); n != 0 {
+				t.Errorf("foreign host received %d authorized requests", n)
+			}
+			if tt.foreign {
+				if n := foreignRequests.Load(); n != 0 || reported.Load() != 0 {
+					t.Errorf("foreign host received %d requests, want none", n)
+				}
+				return
+			}
+			if buf.String() != "RIFF" || apiAuthorized.Load() != 1 {
+				t.Errorf("downloaded %q with %d authorized requests, want the recording with one", buf.String(), apiAuthorized.Load())
+			}
+		})
+	}
+}
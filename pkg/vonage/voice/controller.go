@@ -0,0 +1,305 @@
+package voice
+
+import (
+	"context"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// Call Controller
+// ========================================
+
+// Controller defaults
+const (
+	// DefaultSpeechRate is the assumed speaking rate in characters per
+	// second, suited to Japanese; English is nearer 15
+	DefaultSpeechRate = 8.0
+	// DefaultDigitDuration is how long one DTMF tone is assumed to take
+	DefaultDigitDuration = 300 * time.Millisecond
+)
+
+// CallController plays a queue of in-call actions on one call in order.
+// Talk and stream requests return as soon as the audio starts, and the
+// Voice API sends no event when it ends, so a second request sent at once
+// cuts into the first. The controller waits for each action to finish
+// before sending the next: until its estimated duration has passed, or
+// until Advance is called, e.g. from an input or notify webhook.
+//
+// Methods return the controller for chaining and are safe for concurrent
+// use. The first failed request stops the queue and later actions are
+// dropped until Cancel; Wait returns its error.
+type CallController struct {
+	api      API
+	callUUID string
+
+	voiceName    string
+	speechRate   float64
+	playDuration func(url string) time.Duration
+	logger       vonage.Logger
+
+	mu      sync.Mutex
+	queue   []callStep
+	running bool
+	idle    chan struct{}
+	current stepKind
+	err     error
+	ctx     context.Context
+	cancel  context.CancelFunc
+	advance chan struct{}
+}
+
+// callStep is one queued action
+type callStep struct {
+	kind stepKind
+	run  func(ctx context.Context) (time.Duration, error)
+}
+
+type stepKind int
+
+const (
+	stepNone stepKind = iota
+	stepSpeak
+	stepPlay
+	stepWait
+	stepDigits
+)
+
+// CallControllerOption is a functional option for configuring a
+// CallController
+type CallControllerOption func(*CallController)
+
+// WithControllerVoice sets the voice of Speak (default: the API default)
+func WithControllerVoice(voiceName string) CallControllerOption {
+	return func(c *CallController) {
+		c.voiceName = voiceName
+	}
+}
+
+// WithSpeechRate sets the speaking rate, in characters per second, used
+// to estimate when Speak ends (default DefaultSpeechRate). 0 makes Speak
+// wait for Advance.
+func WithSpeechRate(charsPerSecond float64) CallControllerOption {
+	return func(c *CallController) {
+		c.speechRate = charsPerSecond
+	}
+}
+
+// WithPlayDuration sets how long the audio at a URL plays, e.g. from a
+// table of prompt lengths. Without it, or if fn returns 0, Play waits for
+// Advance.
+func WithPlayDuration(fn func(url string) time.Duration) CallControllerOption {
+	return func(c *CallController) {
+		c.playDuration = fn
+	}
+}
+
+// WithControllerLogger sets the logger (default: no logging)
+func WithControllerLogger(l vonage.Logger) CallControllerOption {
+	return func(c *CallController) {
+		c.logger = l
+	}
+}
+
+// NewCallController creates a controller for the call callUUID, sending
+// requests through api, usually the voice *Client
+func NewCallController(api API, callUUID string, opts ...CallControllerOption) *CallController {
+	idle := make(chan struct{})
+	close(idle)
+
+	c := &CallController{
+		api:        api,
+		callUUID:   callUUID,
+		speechRate: DefaultSpeechRate,
+		logger:     vonage.NopLogger(),
+		idle:       idle,
+		advance:    make(chan struct{}, 1),
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Speak queues text-to-speech, finished after its estimated duration
+func (c *CallController) Speak(text string) *CallController {
+	return c.enqueue(stepSpeak, func(ctx context.Context) (time.Duration, error) {
+		err := c.api.TalkIntoCall(ctx, c.callUUID, TalkIntoCallOptions{Text: text, VoiceName: c.voiceName})
+		return c.speechDuration(text), err
+	})
+}
+
+// Play queues the audio at url, finished after its WithPlayDuration
+// duration or, if unknown, when Advance is called
+func (c *CallController) Play(url string) *CallController {
+	return c.enqueue(stepPlay, func(ctx context.Context) (time.Duration, error) {
+		err := c.api.StreamIntoCall(ctx, c.callUUID, StreamIntoCallOptions{StreamURL: url})
+		var d time.Duration
+		if c.playDuration != nil {
+			d = c.playDuration(url)
+		}
+		return d, err
+	})
+}
+
+// WaitSeconds queues a pause. Advance does not cut it short.
+func (c *CallController) WaitSeconds(n float64) *CallController {
+	return c.enqueue(stepWait, func(context.Context) (time.Duration, error) {
+		return time.Duration(n * float64(time.Second)), nil
+	})
+}
+
+// SendDigits queues DTMF tones, finished after DefaultDigitDuration per
+// digit
+func (c *CallController) SendDigits(digits string) *CallController {
+	return c.enqueue(stepDigits, func(ctx context.Context) (time.Duration, error) {
+		err := c.api.SendDTMF(ctx, c.callUUID, digits)
+		return time.Duration(len(digits)) * DefaultDigitDuration, err
+	})
+}
+
+// Advance marks the current Speak or Play as finished, e.g. when a
+// webhook shows the caller has heard it, so the next action starts at once
+func (c *CallController) Advance() {
+	select {
+	case c.advance <- struct{}{}:
+	default:
+	}
+}
+
+// Wait blocks until the queue is empty, and returns the error that stopped
+// it, if any
+func (c *CallController) Wait(ctx context.Context) error {
+	c.mu.Lock()
+	idle := c.idle
+	c.mu.Unlock()
+
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return c.Err()
+}
+
+// Err returns the error that stopped the queue, if any
+func (c *CallController) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Cancel drops the queued actions, stops the audio of the current one and
+// clears the error that stopped the queue. The controller can be used
+// again afterwards.
+func (c *CallController) Cancel(ctx context.Context) error {
+	c.mu.Lock()
+	c.queue = nil
+	c.err = nil
+	current := c.current
+	c.cancel()
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	c.mu.Unlock()
+
+	switch current {
+	case stepSpeak:
+		return c.api.StopTalk(ctx, c.callUUID)
+	case stepPlay:
+		return c.api.StopStream(ctx, c.callUUID)
+	}
+	return nil
+}
+
+// enqueue adds a step and starts the worker if it is not running
+func (c *CallController) enqueue(kind stepKind, run func(ctx context.Context) (time.Duration, error)) *CallController {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return c
+	}
+	c.queue = append(c.queue, callStep{kind: kind, run: run})
+	if !c.running {
+		c.running = true
+		c.idle = make(chan struct{})
+		go c.work()
+	}
+	return c
+}
+
+// work runs the queued steps in order until the queue is empty
+func (c *CallController) work() {
+	for {
+		c.mu.Lock()
+		if len(c.queue) == 0 || c.err != nil {
+			c.queue = nil
+			c.running = false
+			c.current = stepNone
+			close(c.idle)
+			c.mu.Unlock()
+			return
+		}
+		step := c.queue[0]
+		c.queue = c.queue[1:]
+		c.current = step.kind
+		ctx := c.ctx
+		c.mu.Unlock()
+
+		// An Advance for an earlier step must not end this one
+		select {
+		case <-c.advance:
+		default:
+		}
+
+		d, err := step.run(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				c.logger.Error("Call controller action failed", "callUUID", c.callUUID, "error", err)
+				c.mu.Lock()
+				c.err = err
+				c.mu.Unlock()
+			}
+			continue
+		}
+		c.pause(ctx, step.kind, d)
+	}
+}
+
+// pause waits for a step to finish. Speak and Play with an unknown
+// duration wait for Advance.
+func (c *CallController) pause(ctx context.Context, kind stepKind, d time.Duration) {
+	var timeout <-chan time.Time
+	if d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeout = timer.C
+	} else if kind == stepWait || kind == stepDigits {
+		return
+	}
+
+	advance := c.advance
+	if kind == stepWait {
+		advance = nil
+	}
+
+	select {
+	case <-timeout:
+	case <-advance:
+	case <-ctx.Done():
+	}
+}
+
+// speechDuration estimates how long text takes to speak
+func (c *CallController) speechDuration(text string) time.Duration {
+	if c.speechRate <= 0 {
+		return 0
+	}
+	seconds := float64(utf8.RuneCountInString(text)) / c.speechRate
+	return time.Duration(seconds * float64(time.Second))
+}
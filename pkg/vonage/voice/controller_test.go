@@ -0,0 +1,84 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+var errTalkFailed = errors.New("talk failed")
+
+// fakeTalkAPI records talk requests and fails those with the text "fail";
+// other API methods panic
+type fakeTalkAPI struct {
+	API
+	mu    sync.Mutex
+	talks []string
+}
+
+func (f *fakeTalkAPI) TalkIntoCall(ctx context.Context, callUUID string, opts TalkIntoCallOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.talks = append(f.talks, opts.Text)
+	if opts.Text == "fail" {
+		return errTalkFailed
+	}
+	return nil
+}
+
+func (f *fakeTalkAPI) StopTalk(ctx context.Context, callUUID string) error {
+	return nil
+}
+
+func (f *fakeTalkAPI) sent() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.talks...)
+}
+
+func newTestController(api API) *CallController {
+	// A fast speech rate keeps the estimated durations negligible
+	return NewCallController(api, "CALL-1", WithSpeechRate(1e6))
+}
+
+func TestCallControllerFailureStopsQueue(t *testing.T) {
+	api := &fakeTalkAPI{}
+	c := newTestController(api)
+	ctx := context.Background()
+
+	if err := c.Speak("hello").Speak("fail").Speak("after").Wait(ctx); !errors.Is(err, errTalkFailed) {
+		t.Fatalf("Wait() = %v, want the talk error", err)
+	}
+	// Until Cancel, new actions are dropped
+	if err := c.Speak("dropped").Wait(ctx); !errors.Is(err, errTalkFailed) {
+		t.Fatalf("Wait() = %v, want the talk error", err)
+	}
+	if got, want := api.sent(), []string{"hello", "fail"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("talks = %q, want %q", got, want)
+	}
+}
+
+func TestCallControllerCancelAfterFailure(t *testing.T) {
+	api := &fakeTalkAPI{}
+	c := newTestController(api)
+	ctx := context.Background()
+
+	if err := c.Speak("fail").Wait(ctx); !errors.Is(err, errTalkFailed) {
+		t.Fatalf("Wait() = %v, want the talk error", err)
+	}
+	if err := c.Cancel(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Err(); err != nil {
+		t.Fatalf("Err() after Cancel = %v, want nil", err)
+	}
+
+	if err := c.Speak("hello").Wait(ctx); err != nil {
+		t.Fatalf("Wait() after Cancel = %v, want nil", err)
+	}
+	if got, want := api.sent(), []string{"fail", "hello"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("talks = %q, want %q", got, want)
+	}
+}
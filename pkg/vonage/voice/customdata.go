@@ -0,0 +1,134 @@
+package voice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ========================================
+// Custom Data
+// ========================================
+
+// CustomDataParam is the query parameter that carries a call's custom data
+// in its answer and event URLs
+const CustomDataParam = "custom_data"
+
+// ErrUnsignedCustomData is returned by CreateCall for custom data when the
+// client has no signing URLBuilder (see WithURLSigner)
+var ErrUnsignedCustomData = errors.New("voice: custom data requires a signing URLBuilder, see WithURLSigner")
+
+// withCustomData returns rawURL with data added as JSON in CustomDataParam
+// and its parameters signed by urls. The Voice API has no field that
+// carries data from a call request to its webhooks, so the URLs Vonage
+// calls back carry it instead, signed so that callers cannot forge it.
+func withCustomData(rawURL string, data map[string]interface{}, urls *URLBuilder) (string, error) {
+	if rawURL == "" || len(data) == 0 {
+		return rawURL, nil
+	}
+	if urls == nil || urls.secret == nil {
+		return "", ErrUnsignedCustomData
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("voice: invalid webhook URL %q: %w", rawURL, err)
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("voice: failed to encode custom data: %w", err)
+	}
+	q := u.Query()
+	q.Set(CustomDataParam, string(encoded))
+	u.RawQuery = urls.Sign(q).Encode()
+	return u.String(), nil
+}
+
+// CustomDataFromRequest returns the custom data a webhook request carries
+// in its URL, nil if none. It fails with ErrInvalidURLSignature unless the
+// data is signed with the builder's secret.
+func (b *URLBuilder) CustomDataFromRequest(r *http.Request) (map[string]interface{}, error) {
+	q := r.URL.Query()
+	raw := q.Get(CustomDataParam)
+	if raw == "" {
+		return nil, nil
+	}
+	if b.secret == nil || !isSigned(q, CustomDataParam) {
+		return nil, ErrInvalidURLSignature
+	}
+	if err := b.Verify(q); err != nil {
+		return nil, err
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		return nil, fmt.Errorf("voice: invalid custom data: %w", err)
+	}
+	return data, nil
+}
+
+// ParseCallEvent parses a call event webhook like ParseCallEvent, adding
+// the custom data given to CreateCall once its signature is verified
+func (b *URLBuilder) ParseCallEvent(r *http.Request) (*CallEvent, error) {
+	data, err := b.CustomDataFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	event, err := ParseCallEvent(r)
+	if err != nil {
+		return nil, err
+	}
+	event.CustomData = mergeCustomData(event.CustomData, data)
+	event.VerifiedCustomData = data
+	return event, nil
+}
+
+// ParseCallEvent parses a call event webhook, from a JSON body (POST, the
+// default event_method) or query parameters (GET). Custom data in the URL
+// is not verified, so it is left to URLBuilder.ParseCallEvent.
+func ParseCallEvent(r *http.Request) (*CallEvent, error) {
+	var event CallEvent
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		event = CallEvent{
+			UUID:             q.Get("uuid"),
+			ConversationUUID: q.Get("conversation_uuid"),
+			Status:           CallStatus(q.Get("status")),
+			Direction:        q.Get("direction"),
+			Timestamp:        q.Get("timestamp"),
+			From:             q.Get("from"),
+			To:               q.Get("to"),
+			Duration:         q.Get("duration"),
+			Rate:             q.Get("rate"),
+			Price:            q.Get("price"),
+			Detail:           CallStatusDetail(q.Get("detail")),
+			DisconnectedBy:   DisconnectedBy(q.Get("disconnected_by")),
+		}
+	} else {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("voice: failed to read call event: %w", err)
+		}
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, fmt.Errorf("voice: failed to parse call event: %w", err)
+		}
+	}
+	return &event, nil
+}
+
+// mergeCustomData adds the verified entries to the unverified ones from a
+// webhook body, replacing those with the same key
+func mergeCustomData(unverified, verified map[string]interface{}) map[string]interface{} {
+	if len(verified) == 0 {
+		return unverified
+	}
+	merged := make(map[string]interface{}, len(unverified)+len(verified))
+	for k, v := range unverified {
+		merged[k] = v
+	}
+	for k, v := range verified {
+		merged[k] = v
+	}
+	return merged
+}
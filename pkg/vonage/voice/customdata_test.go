@@ -0,0 +1,176 @@
+package voice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+var testCustomData = map[string]interface{}{"booking_id": "B-42"}
+
+func newSigningBuilder() *URLBuilder {
+	return NewURLBuilder("https://example.com", WithURLSigningSecret([]byte("url-secret")))
+}
+
+func TestWithCustomData(t *testing.T) {
+	tests := []struct {
+		name    string
+		urls    *URLBuilder
+		wantErr error
+	}{
+		{"signed", newSigningBuilder(), nil},
+		{"no builder", nil, ErrUnsignedCustomData},
+		{"builder without secret", NewURLBuilder("https://example.com"), ErrUnsignedCustomData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tagged, err := withCustomData("https://example.com/webhooks/event?user=u1", testCustomData, tt.urls)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("withCustomData() = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			r := httptest.NewRequest(http.MethodPost, tagged, nil)
+			data, err := tt.urls.CustomDataFromRequest(r)
+			if err != nil || data["booking_id"] != "B-42" {
+				t.Errorf("CustomDataFromRequest() = %v, %v, want the booking ID", data, err)
+			}
+			// The URL's own parameters are signed along with the data
+			if r.URL.Query().Get("user") != "u1" || !strings.Contains(r.URL.Query().Get(SignedParamsParam), "user") {
+				t.Errorf("URL %s lost or left unsigned the user parameter", tagged)
+			}
+		})
+	}
+}
+
+func TestCustomDataFromRequest(t *testing.T) {
+	urls := newSigningBuilder()
+	signed, _ := withCustomData(urls.EventURL(nil), testCustomData, urls)
+	forged := urls.EventURL(url.Values{"user": {"u1"}}) + "&" + CustomDataParam + "=" + url.QueryEscape(`{"booking_id":"B-43"}`)
+
+	tests := []struct {
+		name    string
+		urls    *URLBuilder
+		target  string
+		want    interface{}
+		wantErr error
+	}{
+		{"signed", urls, signed, "B-42", nil},
+		{"no custom data", urls, urls.EventURL(nil), nil, nil},
+		{"tampered", urls, strings.Replace(signed, "B-42", "B-43", 1), nil, ErrInvalidURLSignature},
+		{"added to a signed URL", urls, forged, nil, ErrInvalidURLSignature},
+		{"other secret", NewURLBuilder("", WithURLSigningSecret([]byte("other"))), signed, nil, ErrInvalidURLSignature},
+		{"builder without secret", NewURLBuilder(""), signed, nil, ErrInvalidURLSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.urls.CustomDataFromRequest(httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CustomDataFromRequest() = %v, want %v", err, tt.wantErr)
+			}
+			if got := data["booking_id"]; got != tt.want {
+				t.Errorf("booking_id = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateCallCustomData(t *testing.T) {
+	var req CreateCallRequest
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Write([]byte(`{"uuid":"CALL-1"}`))
+	}))
+	defer api.Close()
+
+	urls := newSigningBuilder()
+	client := NewClient(nil, WithTransport(vonage.NewTransport(api.URL, nil)), WithURLSigner(urls))
+	_, err := client.CreateCall(context.Background(), CreateCallOptions{
+		To:         PhoneEndpoint("819012345678"),
+		AnswerURL:  urls.AnswerURL(nil),
+		EventURL:   urls.EventURL(nil),
+		CustomData: testCustomData,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answer, err := urls.ParseAnswerRequest(httptest.NewRequest(http.MethodGet, req.AnswerURL[0]+"&uuid=CALL-1", nil))
+	if err != nil || answer.CustomData["booking_id"] != "B-42" {
+		t.Errorf("answer custom data = %v, %v, want the booking ID", answer.CustomData, err)
+	}
+
+	body := `{"uuid":"CALL-1","status":"completed"}`
+	event, err := urls.ParseCallEvent(httptest.NewRequest(http.MethodPost, req.EventURL[0], strings.NewReader(body)))
+	if err != nil || event.CustomData["booking_id"] != "B-42" {
+		t.Errorf("event custom data = %v, %v, want the booking ID", event.CustomData, err)
+	}
+
+	// Without verification the URL's data is not trusted
+	event, err = ParseCallEvent(httptest.NewRequest(http.MethodPost, req.EventURL[0], strings.NewReader(body)))
+	if err != nil || event.CustomData != nil {
+		t.Errorf("ParseCallEvent() custom data = %v, %v, want none", event.CustomData, err)
+	}
+}
+
+func TestCreateCallCustomDataUnsigned(t *testing.T) {
+	client := NewClient(nil, WithTransport(vonage.NewTransport("http://127.0.0.1:0", nil)))
+	_, err := client.CreateCall(context.Background(), CreateCallOptions{
+		To:         PhoneEndpoint("819012345678"),
+		AnswerURL:  "https://example.com/webhooks/answer",
+		CustomData: testCustomData,
+	})
+	if !errors.Is(err, ErrUnsignedCustomData) {
+		t.Errorf("CreateCall() = %v, want ErrUnsignedCustomData", err)
+	}
+}
+
+func TestCustomDataCollision(t *testing.T) {
+	urls := newSigningBuilder()
+	body := `{"uuid":"CALL-1","to":"819012345678","status":"completed","custom_data":{"booking_id":"B-666","channel":"app"}}`
+
+	tests := []struct {
+		name  string
+		parse func(r *http.Request) (data, verified map[string]interface{}, err error)
+	}{
+		{"event", func(r *http.Request) (map[string]interface{}, map[string]interface{}, error) {
+			event, err := urls.ParseCallEvent(r)
+			if err != nil {
+				return nil, nil, err
+			}
+			return event.CustomData, event.VerifiedCustomData, nil
+		}},
+		{"answer", func(r *http.Request) (map[string]interface{}, map[string]interface{}, error) {
+			answer, err := urls.ParseAnswerRequest(r)
+			return answer.CustomData, answer.VerifiedCustomData, err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, _ := withCustomData(urls.EventURL(nil), testCustomData, urls)
+			data, verified, err := tt.parse(httptest.NewRequest(http.MethodPost, signed, strings.NewReader(body)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The signed value wins over the body's
+			if data["booking_id"] != "B-42" || data["channel"] != "app" {
+				t.Errorf("custom data = %v, want the signed booking ID and the body's channel", data)
+			}
+			if len(verified) != 1 || verified["booking_id"] != "B-42" {
+				t.Errorf("verified custom data = %v, want only the signed booking ID", verified)
+			}
+		})
+	}
+}
@@ -2,10 +2,23 @@ package voice_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	vonage "github.com/vonatrigger/poc/pkg/vonage"
 	"github.com/vonatrigger/poc/pkg/vonage/voice"
+	"github.com/vonatrigger/poc/pkg/vonage/vonagemock"
 )
 
 func ExampleClient_createCall() {
@@ -73,10 +86,16 @@ func ExampleClient_callManagement() {
 	_ = client.UnmuteCall(ctx, callUUID)
 
 	// Play TTS into active call
-	_ = client.TalkIntoCall(ctx, callUUID, "新しいメッセージです", "Mizuki", 1)
+	_ = client.TalkIntoCall(ctx, callUUID, voice.TalkIntoCallOptions{
+		Text:      "新しいメッセージです",
+		VoiceName: "Mizuki",
+	})
 
 	// Stream audio into active call
-	_ = client.StreamIntoCall(ctx, callUUID, "https://example.com/audio.mp3", 1)
+	_ = client.StreamIntoCall(ctx, callUUID, voice.StreamIntoCallOptions{
+		StreamURL: "https://example.com/audio.mp3",
+		Loop:      voice.LoopInfinite,
+	})
 
 	// Hangup
 	_ = client.HangupCall(ctx, callUUID)
@@ -201,3 +220,471 @@ func ExampleASRResult_processing() {
 		fmt.Printf("DTMF: %s\n", asr.DTMF)
 	}
 }
+
+func ExampleWithTransport() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+	)
+	jwtGen := vonage.NewJWTGeneratorFromCredentials(creds)
+
+	// Log the latency of every API call made through the transport
+	timing := func(next vonage.RoundTripFunc) vonage.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			fmt.Printf("%s %s took %s\n", req.Method, req.URL.Path, time.Since(start))
+			return resp, err
+		}
+	}
+
+	// The transport can be shared with the messages, users and media
+	// clients, which use the same host; video needs its own for
+	// vonage.BaseURLVideo. Its logger, mode and User-Agent apply, not the
+	// voice client's options.
+	transport := vonage.NewTransport(voice.BaseURL, jwtGen,
+		vonage.WithMiddleware(timing),
+		vonage.WithTransportLogger(vonage.NopLogger()),
+		vonage.WithTransportUserAgentSuffix("checkin-service/1.4"),
+	)
+	client := voice.NewClient(jwtGen, voice.WithTransport(transport))
+
+	_ = client.HangupCall(context.Background(), "call-uuid")
+}
+
+func ExampleClient_DownloadRecording() {
+	creds, _ := vonage.NewCredentials(
+		vonage.WithApplication("app-id", "private-key-pem"),
+	)
+	client, _ := voice.NewClientFromCredentials(creds)
+
+	f, err := os.Create("recording.mp3")
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	// Allow 5 minutes for this download only; other calls keep the
+	// client's 30s timeout
+	ctx := vonage.WithRequestTimeout(context.Background(), 5*time.Minute)
+	n, err := client.DownloadRecording(ctx, "https://api.nexmo.com/v1/files/recording-id", f)
+	if err != nil {
+		fmt.Printf("download failed: %v\n", err)
+		return
+	}
+	fmt.Printf("saved %d bytes\n", n)
+}
+
+func ExampleTTSCache() {
+	// Audio produced by an external TTS service and uploaded to a CDN
+	synth := voice.SynthesizerFunc(func(ctx context.Context, p voice.Prompt) (string, error) {
+		return "https://cdn.example.com/prompts/" + p.Key()[:8] + ".mp3", nil
+	})
+	cache := voice.NewTTSCache(synth)
+
+	menu := voice.Prompt{Text: "Press 1 for reservations", Language: "en-US", VoiceName: "Amy"}
+	_ = cache.Warm(context.Background(), menu)
+
+	ncco := voice.NewNCCO().WithPromptCache(cache).
+		Talk(menu.Text).Language(menu.Language).VoiceName(menu.VoiceName).BargeIn().Done().
+		Talk("This prompt is not cached").Done().
+		Build()
+
+	fmt.Println(ncco[0].ActionType, ncco[1].ActionType)
+	// Output: stream talk
+}
+
+func ExampleNCCOServer() {
+	nccos := voice.NewNCCOServer(voice.WithNCCOBaseURL("https://game.example.com")).
+		Register("lobby", func(req voice.AnswerRequest) voice.NCCO {
+			return voice.NewNCCO().Talk("Welcome to table " + req.Params.Get("table")).Done().Build()
+		})
+
+	mux := http.NewServeMux()
+	mux.Handle(nccos.Prefix(), nccos)
+
+	// Pass this as the call's answer URL
+	fmt.Println(nccos.AnswerURL("lobby"))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ncco/lobby?table=7&to=15551234567", nil))
+	fmt.Print(rec.Body.String())
+	// Output:
+	// https://game.example.com/ncco/lobby
+	// [{"action":"talk","text":"Welcome to table 7"}]
+}
+
+func ExampleASRResult_MatchAnswers() {
+	var result voice.ASRResult
+	result.Speech.Results = []voice.ASRMatch{
+		{Text: "トウキョウ", Confidence: "0.91"},
+		{Text: "東京", Confidence: "0.62"},
+	}
+
+	// Accept the kanji and the reading of the answer
+	match, err := result.MatchAnswers([]string{"東京", "とうきょう"}, 0.5, 0)
+	fmt.Println(match.Answer, match.Score, err)
+
+	_, err = result.BestAboveThreshold(0.95)
+	fmt.Println(errors.Is(err, voice.ErrLowConfidence))
+	// Output:
+	// とうきょう 1 <nil>
+	// true
+}
+
+func ExampleReprompt() {
+	menu := voice.NewReprompt("https://example.com/input", 3).
+		Prompt(voice.NewNCCO().Talk("Press 1 to play, 2 for rules").BargeIn().Done().Build()).
+		RetryPrompt(voice.NewNCCO().Talk("Sorry, I didn't get that").Done().Build()).
+		Input(func(i *voice.InputBuilder) { i.DTMF().MaxDigits(1).TimeOut(5) }).
+		Fallback(voice.NewNCCO().Talk("Goodbye").Done().Build())
+
+	// Answer URL
+	ncco := voice.NewNCCO().Talk("Welcome").Done().Reprompt(menu).Build()
+	fmt.Println(len(ncco), ncco[2].EventURL[0])
+
+	// Input event URL: the caller pressed nothing
+	req := httptest.NewRequest(http.MethodPost, ncco[2].EventURL[0], nil)
+	next, _ := menu.Next(req, &voice.ASRResult{TimedOut: true})
+	fmt.Println(next[0].Text, next[2].EventURL[0])
+
+	// After the third attempt
+	req = httptest.NewRequest(http.MethodPost, "https://example.com/input?attempt=3", nil)
+	next, _ = menu.Next(req, &voice.ASRResult{TimedOut: true})
+	fmt.Println(next[0].Text)
+	// Output:
+	// 3 https://example.com/input?attempt=1
+	// Sorry, I didn't get that https://example.com/input?attempt=2
+	// Goodbye
+}
+
+func ExampleScheduler() {
+	client := &vonagemock.Voice{
+		CreateCallFunc: func(ctx context.Context, opts voice.CreateCallOptions) (*voice.CreateCallResponse, error) {
+			return &voice.CreateCallResponse{UUID: "CALL-1"}, nil
+		},
+	}
+	scheduler := voice.NewScheduler(client)
+	// go scheduler.Run(ctx), and route call events to scheduler.HandleEvent
+
+	ctx := context.Background()
+	_, _ = scheduler.Schedule(ctx, voice.ScheduledCall{
+		ID:       "reminder-42",
+		Options: voice.CreateCallOptions{To: voice.PhoneEndpoint("81901234567"), AnswerURL: "https://example.com/answer"},
+	})
+
+	// The destination was busy: the call is retried in 10 minutes
+	_, _ = scheduler.PlaceDue(ctx)
+	_ = scheduler.HandleEvent(ctx, &voice.CallEvent{UUID: "CALL-1", Status: "busy"})
+
+	call, _ := scheduler.Get(ctx, "reminder-42")
+	fmt.Println(call.Status, call.Attempts, call.LastOutcome)
+	// Output: pending 1 busy
+}
+
+func ExampleQuietHours_NextAllowed() {
+	quiet, _ := voice.NewQuietHours("21:00", "09:00")
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	fmt.Println(quiet.NextAllowed(time.Date(2024, 5, 1, 22, 30, 0, 0, tokyo)).Format(time.DateTime))
+	// Output: 2024-05-02 09:00:00
+}
+
+func ExampleJapaneseReading() {
+	fmt.Println(voice.JapaneseReading("ご予約は4月1日 9:05、料金は1,200円です"))
+	fmt.Println(voice.JapaneseReading("お問い合わせは03-1234-5678まで"))
+
+	// In an NCCO, as SSML with pauses between the number's groups
+	ncco := voice.NewNCCO().
+		Talk("03-1234-5678").Japanese().NormalizeJapanese().Done().
+		Build()
+	fmt.Println(ncco[0].Text)
+	// Output:
+	// ご予約はしがつついたち くじごふん、料金はせんにひゃく円です
+	// お問い合わせはぜろさん、いちにーさんよん、ごーろくななはちまで
+	// <speak>ぜろさん<break time="300ms"/>いちにーさんよん<break time="300ms"/>ごーろくななはち</speak>
+}
+
+func ExampleCreateCallOptions_Validate() {
+	opts := voice.CreateCallOptions{
+		To:           voice.PhoneEndpoint("819012345678"),
+		AnswerURL:    "https://example.com/answer",
+		AnswerMethod: voice.MethodGET,
+		EventMethod:  "PUT",
+	}
+	err := opts.Validate()
+	fmt.Println(errors.Is(err, voice.ErrInvalidHTTPMethod))
+	fmt.Println(err)
+
+	// Methods from configuration
+	m, err := voice.ParseHTTPMethod("post")
+	fmt.Println(m, err)
+	// Output:
+	// true
+	// event_method: voice: webhook method must be GET or POST, got "PUT"
+	// POST <nil>
+}
+
+func ExampleClient_GetConversationLegs() {
+	// Two legs of a conference, one per page
+	legs := []string{
+		`{"uuid":"LEG-1","conversation_uuid":"CON-1","duration":"125","price":"0.0250","start_time":"2024-04-01T09:00:00Z","end_time":"2024-04-01T09:02:05Z"}`,
+		`{"uuid":"LEG-2","conversation_uuid":"CON-1","duration":"60","price":"0.0120","start_time":"2024-04-01T09:00:30Z","end_time":"2024-04-01T09:01:30Z"}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		index, _ := strconv.Atoi(r.URL.Query().Get("record_index"))
+		fmt.Fprintf(w, `{"count":2,"page_size":1,"record_index":%d,"_embedded":{"calls":[%s]}}`, index, legs[index])
+	}))
+	defer srv.Close()
+
+	client := voice.NewClient(nil, voice.WithTransport(vonage.NewTransport(srv.URL, nil)))
+	conv, err := client.GetConversationLegs(context.Background(), "CON-1")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%d legs, %s, %s, %s\n", len(conv.Legs), conv.Duration, conv.Price, conv.EndTime.Sub(conv.StartTime))
+	// Output: 2 legs, 3m5s, 0.03700000, 2m5s
+}
+
+func ExampleURLBuilder_ParseCallEvent() {
+	var eventURL string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req voice.CreateCallRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		eventURL = req.EventURL[0]
+		w.Write([]byte(`{"uuid":"CALL-1"}`))
+	}))
+	defer api.Close()
+
+	// The custom data is signed, so callers cannot forge it
+	urls := voice.NewURLBuilder("https://example.com", voice.WithURLSigningSecret([]byte("url-secret")))
+	client := voice.NewClient(nil,
+		voice.WithTransport(vonage.NewTransport(api.URL, nil)),
+		voice.WithURLSigner(urls),
+	)
+	client.CreateCall(context.Background(), voice.CreateCallOptions{
+		To:         voice.PhoneEndpoint("819012345678"),
+		InlineNCCO: voice.TalkJapanese("こんにちは"),
+		EventURL:   "https://example.com/voice/event",
+		CustomData: map[string]interface{}{"booking_id": "B-42"},
+	})
+
+	// Vonage posts the call's events to the tagged event URL
+	r := httptest.NewRequest(http.MethodPost, eventURL,
+		strings.NewReader(`{"uuid":"CALL-1","status":"completed","duration":"31"}`))
+	event, err := urls.ParseCallEvent(r)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(event.Status, event.CustomData["booking_id"])
+	// Output: completed B-42
+}
+
+func ExampleNCCOBuilder_Clone() {
+	// Build the shared greeting once and extend a clone per call
+	greeting := voice.NewNCCO().Talk("お電話ありがとうございます").Japanese().Done()
+
+	for _, callID := range []string{"call-1", "call-2"} {
+		ncco := greeting.Clone().
+			Input().Speech().EventURL("https://example.com/input/" + callID).Done().
+			Build()
+		fmt.Println(len(ncco), ncco[1].EventURL[0])
+	}
+	fmt.Println(len(greeting.Build()))
+	// Output:
+	// 2 https://example.com/input/call-1
+	// 2 https://example.com/input/call-2
+	// 1
+}
+
+func ExampleTalkBuilder_Loop() {
+	// Zero values are sent: loop 0 repeats hold music until the call ends
+	ncco := voice.NewNCCO().
+		Talk("Please hold").Style(0).Level(-0.5).Done().
+		Stream("https://example.com/hold.mp3").Loop(0).Level(0).Done().
+		Build()
+
+	data, _ := json.Marshal(ncco)
+	fmt.Println(string(data))
+	// Output:
+	// [{"action":"talk","text":"Please hold","style":0,"level":-0.5},{"action":"stream","level":0,"loop":0,"streamUrl":["https://example.com/hold.mp3"]}]
+}
+
+func ExampleClient_StreamIntoCall() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fmt.Println(r.Method, r.URL.Path, string(body))
+	}))
+	defer srv.Close()
+	client := voice.NewClient(nil, voice.WithTransport(vonage.NewTransport(srv.URL, nil)))
+	ctx := context.Background()
+
+	// Quiet hold music under a louder announcement
+	_ = client.StreamIntoCall(ctx, "CALL-1", voice.StreamIntoCallOptions{
+		StreamURL: "https://example.com/hold.mp3",
+		Loop:      voice.LoopInfinite,
+		Level:     -0.6,
+	})
+	_ = client.TalkIntoCall(ctx, "CALL-1", voice.TalkIntoCallOptions{
+		Text:  "Your call is important to us",
+		Level: 0.4,
+	})
+
+	err := client.TalkIntoCall(ctx, "CALL-1", voice.TalkIntoCallOptions{Text: "Too loud", Level: 2})
+	fmt.Println(errors.Is(err, voice.ErrInvalidLevel))
+	// Output:
+	// PUT /v1/calls/CALL-1/stream {"stream_url":["https://example.com/hold.mp3"],"loop":0,"level":-0.6}
+	// PUT /v1/calls/CALL-1/talk {"text":"Your call is important to us","level":0.4}
+	// true
+}
+
+func ExampleClient_GetCallsByConversation() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			fmt.Println("hangup", strings.TrimPrefix(r.URL.Path, "/v1/calls/"))
+			return
+		}
+		fmt.Fprint(w, `{"count":2,"page_size":100,"record_index":0,"_embedded":{"calls":[`+
+			`{"uuid":"LEG-2","status":"answered","start_time":"2024-04-01T09:00:30Z"},`+
+			`{"uuid":"LEG-1","status":"answered","start_time":"2024-04-01T09:00:00Z"}]}}`)
+	}))
+	defer srv.Close()
+	client := voice.NewClient(nil, voice.WithTransport(vonage.NewTransport(srv.URL, nil)))
+	ctx := context.Background()
+
+	// The game session is over: hang up every leg
+	legs, err := client.GetCallsByConversation(ctx, "CON-1")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, leg := range legs {
+		_ = client.HangupCall(ctx, leg.UUID)
+	}
+	// Output:
+	// hangup LEG-1
+	// hangup LEG-2
+}
+
+func ExampleCallController() {
+	client := &vonagemock.Voice{
+		TalkIntoCallFunc: func(ctx context.Context, callUUID string, opts voice.TalkIntoCallOptions) error {
+			fmt.Println("talk", opts.Text)
+			return nil
+		},
+		StreamIntoCallFunc: func(ctx context.Context, callUUID string, opts voice.StreamIntoCallOptions) error {
+			fmt.Println("stream", opts.StreamURL)
+			return nil
+		},
+		SendDTMFFunc: func(ctx context.Context, callUUID, digits string) error {
+			fmt.Println("dtmf", digits)
+			return nil
+		},
+	}
+
+	// Each action starts when the previous one has finished playing
+	calls := voice.NewCallController(client, "CALL-1",
+		voice.WithSpeechRate(1000),
+		voice.WithPlayDuration(func(url string) time.Duration { return 10 * time.Millisecond }),
+	)
+	calls.Speak("Connecting you now").
+		Play("https://example.com/chime.mp3").
+		WaitSeconds(0.01).
+		SendDigits("123")
+
+	if err := calls.Wait(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+	// Output:
+	// talk Connecting you now
+	// stream https://example.com/chime.mp3
+	// dtmf 123
+}
+
+func ExampleWithCallSerialization() {
+	// Vonage answers 400 when a call is changed while a change is in
+	// progress; this server records how many overlap
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	client := voice.NewClient(nil,
+		voice.WithTransport(vonage.NewTransport(srv.URL, nil)),
+		voice.WithCallSerialization(),
+	)
+
+	// Webhook handlers acting on the same call at once
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = client.TalkIntoCall(ctx, "CALL-1", voice.TalkIntoCallOptions{Text: "Round over"})
+			_ = client.SendDTMF(ctx, "CALL-1", "1")
+		}()
+	}
+	wg.Wait()
+	fmt.Println(maxInFlight)
+	// Output: 1
+}
+
+func ExampleURLBuilder() {
+	urls := voice.NewURLBuilder("https://game.example.com/",
+		voice.WithURLSigningSecret([]byte("url-secret")),
+	)
+
+	answerURL := urls.AnswerURL(url.Values{"user": {"u 42"}})
+	fmt.Println(answerURL)
+	fmt.Println(urls.InputURL("conv/1"))
+
+	// The answer webhook arrives with the signed parameters plus Vonage's own
+	req := httptest.NewRequest(http.MethodGet, answerURL+"&uuid=call-1", nil)
+	answer, err := urls.ParseAnswerRequest(req)
+	fmt.Println(answer.UUID, answer.Params.Get("user"), err)
+
+	tampered := httptest.NewRequest(http.MethodGet, strings.Replace(answerURL, "u+42", "u+43", 1), nil)
+	_, err = urls.ParseAnswerRequest(tampered)
+	fmt.Println(err)
+	// Output:
+	// https://game.example.com/webhooks/answer?sig=9495fb0114ac3a9eeb58f3ba0b8706e631386f1e3584d0b28609fc35c95be739&sig_params=user&user=u+42
+	// https://game.example.com/webhooks/input/conv%2F1
+	// call-1 u 42 <nil>
+	// voice: invalid webhook URL signature
+}
+
+func ExampleCallStatusDetail_IsPermanent() {
+	events := []string{
+		`{"uuid":"CALL-1","status":"rejected","detail":"unallocated_number"}`,
+		`{"uuid":"CALL-2","status":"failed","detail":"carrier_timeout"}`,
+		`{"uuid":"CALL-3","status":"completed","duration":"42","disconnected_by":"user"}`,
+	}
+	for _, body := range events {
+		r := httptest.NewRequest(http.MethodPost, "/webhooks/event", strings.NewReader(body))
+		event, err := voice.ParseCallEvent(r)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		switch {
+		case event.IsFailure() && event.Detail.IsPermanent():
+			fmt.Println(event.UUID, "remove number:", event.Detail)
+		case event.IsFailure():
+			fmt.Println(event.UUID, "retry later:", event.Detail, event.Detail.IsCarrierFailure())
+		default:
+			fmt.Println(event.UUID, event.Status, "by", event.DisconnectedBy)
+		}
+	}
+	// Output:
+	// CALL-1 remove number: unallocated_number
+	// CALL-2 retry later: carrier_timeout true
+	// CALL-3 completed by user
+}
@@ -0,0 +1,304 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/voice"
+)
+
+// ========================================
+// Engine
+// ========================================
+
+// DefaultMaxRetries is how often a state is re-prompted after unmatched
+// input before its NoMatch state is entered
+const DefaultMaxRetries = 2
+
+// ErrNoSession is returned by Handle for conversations that were never
+// started or have already ended
+var ErrNoSession = errors.New("flow: no session for conversation")
+
+// IntentClassifier maps a speech transcript to an intent name for Intent
+// matchers, e.g. with an NLU service. An empty intent matches nothing.
+type IntentClassifier interface {
+	Classify(ctx context.Context, transcript string) (intent string, err error)
+}
+
+// IntentClassifierFunc adapts a function to IntentClassifier
+type IntentClassifierFunc func(ctx context.Context, transcript string) (string, error)
+
+// Classify implements IntentClassifier
+func (f IntentClassifierFunc) Classify(ctx context.Context, transcript string) (string, error) {
+	return f(ctx, transcript)
+}
+
+// Engine runs a flow for many conversations. Serve Start from the answer
+// URL and Handle from the input event URL.
+type Engine struct {
+	flow       *Flow
+	store      Store
+	classifier IntentClassifier
+	logger     vonage.Logger
+	eventURL   string
+	maxRetries int
+	noMatch    string
+	language   string
+	voiceName  string
+
+	// input configures the input actions the engine emits
+	endOnSilence float64
+	startTimeout int
+	maxDigits    int
+}
+
+// EngineOption is a functional option for configuring an Engine
+type EngineOption func(*Engine)
+
+// WithStore replaces the default in-memory session store
+func WithStore(s Store) EngineOption {
+	return func(e *Engine) {
+		e.store = s
+	}
+}
+
+// WithIntentClassifier classifies speech input for Intent matchers
+func WithIntentClassifier(c IntentClassifier) EngineOption {
+	return func(e *Engine) {
+		e.classifier = c
+	}
+}
+
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l vonage.Logger) EngineOption {
+	return func(e *Engine) {
+		e.logger = l
+	}
+}
+
+// WithMaxRetries sets how often unmatched input re-prompts a state
+// (default DefaultMaxRetries)
+func WithMaxRetries(n int) EngineOption {
+	return func(e *Engine) {
+		e.maxRetries = n
+	}
+}
+
+// WithNoMatchState sets the state entered when a state without its own
+// NoMatch runs out of retries (default: hang up)
+func WithNoMatchState(name string) EngineOption {
+	return func(e *Engine) {
+		e.noMatch = name
+	}
+}
+
+// WithVoice sets the language and voice of prompts that set neither
+func WithVoice(language, voiceName string) EngineOption {
+	return func(e *Engine) {
+		e.language = language
+		e.voiceName = voiceName
+	}
+}
+
+// WithInputSettings sets the input action's end-on-silence (seconds),
+// start timeout (seconds) and maximum DTMF digits. Zero keeps Vonage's
+// default.
+func WithInputSettings(endOnSilence float64, startTimeout, maxDigits int) EngineOption {
+	return func(e *Engine) {
+		e.endOnSilence = endOnSilence
+		e.startTimeout = startTimeout
+		e.maxDigits = maxDigits
+	}
+}
+
+// NewEngine creates an engine for f. eventURL receives the input webhooks
+// that must be passed to Handle.
+func NewEngine(f *Flow, eventURL string, opts ...EngineOption) (*Engine, error) {
+	e := &Engine{
+		flow:       f,
+		store:      NewMemoryStore(),
+		logger:     vonage.NopLogger(),
+		eventURL:   eventURL,
+		maxRetries: DefaultMaxRetries,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	if _, ok := f.State(e.noMatch); e.noMatch != "" && !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownState, e.noMatch)
+	}
+
+	return e, nil
+}
+
+// Start begins the flow for a conversation and returns the initial
+// state's NCCO
+func (e *Engine) Start(ctx context.Context, conversationUUID string) (voice.NCCO, error) {
+	sess := &Session{ConversationUUID: conversationUUID}
+	return e.enter(ctx, sess, e.flow.initial)
+}
+
+// Handle consumes an input webhook and returns the NCCO that continues the
+// conversation
+func (e *Engine) Handle(ctx context.Context, result *voice.ASRResult) (voice.NCCO, error) {
+	return e.HandleInput(ctx, result.ConversationUUID, InputFromASR(result))
+}
+
+// HandleInput is like Handle for input obtained elsewhere
+func (e *Engine) HandleInput(ctx context.Context, conversationUUID string, in Input) (voice.NCCO, error) {
+	sess, err := e.store.Load(ctx, conversationUUID)
+	if err != nil {
+		return nil, fmt.Errorf("flow: failed to load session: %w", err)
+	}
+	if sess == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoSession, conversationUUID)
+	}
+
+	state, ok := e.flow.State(sess.State)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownState, sess.State)
+	}
+
+	if in.Transcript != "" && e.classifier != nil {
+		intent, err := e.classifier.Classify(ctx, in.Transcript)
+		if err != nil {
+			e.logger.Warn("Failed to classify speech input", "conversationUUID", conversationUUID, "error", err)
+		}
+		in.Intent = intent
+	}
+
+	if in.Empty() && state.Timeout != "" {
+		return e.enter(ctx, sess, state.Timeout)
+	}
+
+	for _, t := range state.Transitions {
+		if !t.Match.Match(in) {
+			continue
+		}
+		if t.Action != nil {
+			if err := t.Action(sess, in); err != nil {
+				return nil, fmt.Errorf("flow: transition from %q failed: %w", state.Name, err)
+			}
+		}
+		return e.enter(ctx, sess, t.Target)
+	}
+
+	return e.noMatchFor(ctx, sess, state)
+}
+
+// End discards a conversation's session, e.g. when the caller hangs up
+// mid-flow
+func (e *Engine) End(ctx context.Context, conversationUUID string) error {
+	return e.store.Delete(ctx, conversationUUID)
+}
+
+// noMatchFor re-prompts state or, once its retries are used up, leaves it
+func (e *Engine) noMatchFor(ctx context.Context, sess *Session, state *State) (voice.NCCO, error) {
+	maxRetries := e.maxRetries
+	if state.MaxRetries > 0 {
+		maxRetries = state.MaxRetries
+	}
+
+	if sess.Retries < maxRetries {
+		sess.Retries++
+		return e.render(ctx, sess, state)
+	}
+
+	target := state.NoMatch
+	if target == "" {
+		target = e.noMatch
+	}
+	if target == "" {
+		e.logger.Info("No input matched, ending flow", "conversationUUID", sess.ConversationUUID, "state", state.Name)
+		if err := e.store.Delete(ctx, sess.ConversationUUID); err != nil {
+			return nil, fmt.Errorf("flow: failed to delete session: %w", err)
+		}
+		return voice.NCCO{}, nil
+	}
+	return e.enter(ctx, sess, target)
+}
+
+// enter moves the session to the named state
+func (e *Engine) enter(ctx context.Context, sess *Session, name string) (voice.NCCO, error) {
+	state, ok := e.flow.State(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownState, name)
+	}
+
+	sess.State = name
+	sess.Retries = 0
+	e.logger.Debug("Entered flow state", "conversationUUID", sess.ConversationUUID, "state", name)
+	return e.render(ctx, sess, state)
+}
+
+// render saves the session and returns the state's NCCO
+func (e *Engine) render(ctx context.Context, sess *Session, state *State) (voice.NCCO, error) {
+	ncco := e.ncco(sess, state)
+
+	var err error
+	if state.Final() {
+		err = e.store.Delete(ctx, sess.ConversationUUID)
+	} else {
+		sess.UpdatedAt = time.Now()
+		err = e.store.Save(ctx, sess)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("flow: failed to save session: %w", err)
+	}
+	return ncco, nil
+}
+
+// ncco builds a state's prompts followed by its input action
+func (e *Engine) ncco(sess *Session, state *State) voice.NCCO {
+	b := voice.NewNCCO()
+	final := state.Final()
+
+	for _, p := range state.prompts(sess) {
+		if p.AudioURL != "" {
+			stream := b.Stream(p.AudioURL)
+			if !final {
+				stream.BargeIn()
+			}
+			stream.Done()
+			continue
+		}
+
+		language, voiceName := p.Language, p.VoiceName
+		if language == "" && voiceName == "" {
+			language, voiceName = e.language, e.voiceName
+		}
+		talk := b.Talk(p.Text).Language(language).VoiceName(voiceName)
+		if !final {
+			talk.BargeIn()
+		}
+		talk.Done()
+	}
+
+	if final {
+		return b.Build()
+	}
+
+	input := b.Input().EventURL(e.eventURL)
+	dtmf, speech := state.inputTypes()
+	if dtmf {
+		input.DTMF()
+		if e.maxDigits > 0 {
+			input.MaxDigits(e.maxDigits)
+		}
+	}
+	if speech {
+		input.Speech()
+		if e.endOnSilence > 0 {
+			input.EndOnSilence(e.endOnSilence)
+		}
+	}
+	if e.startTimeout > 0 {
+		input.StartTimeout(e.startTimeout)
+	}
+	return input.Done().Build()
+}
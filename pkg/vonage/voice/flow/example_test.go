@@ -0,0 +1,57 @@
+package flow_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/vonatrigger/poc/pkg/vonage/voice"
+	"github.com/vonatrigger/poc/pkg/vonage/voice/flow"
+)
+
+func ExampleEngine() {
+	quiz, err := flow.New("question",
+		flow.State{
+			Name:    "question",
+			Prompts: []flow.Prompt{flow.Talk("What is 2 + 2? Press or say your answer.")},
+			Transitions: []flow.Transition{
+				flow.On(flow.DTMF("4"), "correct").Do(score),
+				flow.On(flow.Speech("four"), "correct").Do(score),
+				flow.On(flow.Any(), "wrong"),
+			},
+			NoMatch: "wrong",
+		},
+		flow.State{
+			Name: "correct",
+			PromptFunc: func(s *flow.Session) []flow.Prompt {
+				return []flow.Prompt{flow.Talk("Correct! Your score is " + s.Get("score"))}
+			},
+		},
+		flow.State{Name: "wrong", Prompts: []flow.Prompt{flow.Talk("Sorry, the answer was 4.")}},
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	engine, _ := flow.NewEngine(quiz, "https://example.com/input",
+		flow.WithVoice("en-US", "Amy"),
+	)
+
+	ctx := context.Background()
+	ncco, _ := engine.Start(ctx, "CON-1")
+	fmt.Println(ncco[0].Text, ncco[1].ActionType, ncco[1].Type)
+
+	// The input webhook from the caller pressing 4
+	ncco, _ = engine.Handle(ctx, &voice.ASRResult{ConversationUUID: "CON-1", DTMF: "4"})
+	fmt.Println(ncco[0].Text)
+	// Output:
+	// What is 2 + 2? Press or say your answer. input [dtmf speech]
+	// Correct! Your score is 1
+}
+
+func score(s *flow.Session, in flow.Input) error {
+	n, _ := strconv.Atoi(s.Get("score"))
+	s.Set("score", strconv.Itoa(n+1))
+	return nil
+}
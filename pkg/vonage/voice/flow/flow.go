@@ -0,0 +1,272 @@
+// Package flow runs IVR call flows as state machines. A Flow is a set of
+// named states, each with prompts, the input it expects and transitions to
+// other states; an Engine turns input webhooks into the next state's NCCO
+// and keeps per-conversation state in a Store.
+package flow
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vonatrigger/poc/pkg/vonage/voice"
+)
+
+// ========================================
+// Prompts
+// ========================================
+
+// Prompt is spoken (Text) or played (AudioURL) when a state is entered
+type Prompt struct {
+	Text      string
+	AudioURL  string
+	Language  string
+	VoiceName string
+}
+
+// Talk returns a text-to-speech prompt
+func Talk(text string) Prompt {
+	return Prompt{Text: text}
+}
+
+// Stream returns a prompt that plays audio from url
+func Stream(url string) Prompt {
+	return Prompt{AudioURL: url}
+}
+
+// In returns p spoken in language with voiceName
+func (p Prompt) In(language, voiceName string) Prompt {
+	p.Language = language
+	p.VoiceName = voiceName
+	return p
+}
+
+// ========================================
+// Input and Matchers
+// ========================================
+
+// Input is what the caller entered in response to a state's prompts
+type Input struct {
+	DTMF       string
+	Transcript string
+	// Intent is set by the engine's IntentClassifier, if any
+	Intent   string
+	TimedOut bool
+}
+
+// InputFromASR returns the input carried by an input webhook
+func InputFromASR(r *voice.ASRResult) Input {
+	return Input{
+		DTMF:       r.DTMF,
+		Transcript: r.BestTranscript(),
+		TimedOut:   r.TimedOut || (!r.HasDTMF() && !r.HasSpeech()),
+	}
+}
+
+// Empty reports whether the caller entered nothing
+func (in Input) Empty() bool {
+	return in.DTMF == "" && in.Transcript == ""
+}
+
+// Matcher decides whether an input selects a transition
+type Matcher interface {
+	Match(in Input) bool
+}
+
+// MatcherFunc adapts a function to Matcher. It accepts both DTMF and
+// speech.
+type MatcherFunc func(in Input) bool
+
+// Match implements Matcher
+func (f MatcherFunc) Match(in Input) bool {
+	return f(in)
+}
+
+// dtmfMatcher matches DTMF digits against an anchored pattern
+type dtmfMatcher struct {
+	re *regexp.Regexp
+}
+
+func (m dtmfMatcher) Match(in Input) bool {
+	return in.DTMF != "" && m.re.MatchString(in.DTMF)
+}
+
+// DTMF matches digits against a regular expression that must match all of
+// them, e.g. "1", "[1-3]" or `\d{4}`. It panics if pattern is invalid.
+func DTMF(pattern string) Matcher {
+	return dtmfMatcher{re: regexp.MustCompile(`^(?:` + pattern + `)$`)}
+}
+
+// speechMatcher matches transcripts containing one of its phrases
+type speechMatcher struct {
+	phrases []string
+}
+
+func (m speechMatcher) Match(in Input) bool {
+	transcript := strings.ToLower(in.Transcript)
+	for _, p := range m.phrases {
+		if transcript != "" && strings.Contains(transcript, strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Speech matches transcripts that contain any of phrases, ignoring case
+func Speech(phrases ...string) Matcher {
+	return speechMatcher{phrases: phrases}
+}
+
+// intentMatcher matches the classified intent of speech input
+type intentMatcher struct {
+	intent string
+}
+
+func (m intentMatcher) Match(in Input) bool {
+	return in.Intent != "" && in.Intent == m.intent
+}
+
+// Intent matches speech classified as intent by the engine's
+// IntentClassifier
+func Intent(intent string) Matcher {
+	return intentMatcher{intent: intent}
+}
+
+// Any matches any non-empty input
+func Any() Matcher {
+	return MatcherFunc(func(in Input) bool { return !in.Empty() })
+}
+
+// ========================================
+// States
+// ========================================
+
+// Transition moves to Target when Match accepts the input. Action, if set,
+// runs first and may record the input in the session.
+type Transition struct {
+	Match  Matcher
+	Target string
+	Action func(s *Session, in Input) error
+}
+
+// On returns a transition to target for inputs accepted by m
+func On(m Matcher, target string) Transition {
+	return Transition{Match: m, Target: target}
+}
+
+// Do returns t with action a
+func (t Transition) Do(a func(s *Session, in Input) error) Transition {
+	t.Action = a
+	return t
+}
+
+// State is a step of a flow. A state without transitions is final: its
+// prompts are played and the flow ends.
+type State struct {
+	Name    string
+	Prompts []Prompt
+	// PromptFunc, if set, replaces Prompts with prompts built from the
+	// session, e.g. the current quiz question
+	PromptFunc func(s *Session) []Prompt
+
+	Transitions []Transition
+
+	// NoMatch is entered when no transition matches after MaxRetries
+	// re-prompts (default: the engine's fallback state, or hang up)
+	NoMatch string
+	// Timeout is entered when the caller enters nothing; if empty a
+	// timeout counts as a no-match
+	Timeout string
+	// MaxRetries overrides the engine's retry limit for this state
+	MaxRetries int
+}
+
+// Final reports whether the flow ends in s
+func (s *State) Final() bool {
+	return len(s.Transitions) == 0
+}
+
+// prompts returns the prompts to play for a session in s
+func (s *State) prompts(sess *Session) []Prompt {
+	if s.PromptFunc != nil {
+		return s.PromptFunc(sess)
+	}
+	return s.Prompts
+}
+
+// inputTypes returns the input types the transitions expect
+func (s *State) inputTypes() (dtmf, speech bool) {
+	for _, t := range s.Transitions {
+		switch t.Match.(type) {
+		case dtmfMatcher:
+			dtmf = true
+		case speechMatcher, intentMatcher:
+			speech = true
+		default:
+			dtmf, speech = true, true
+		}
+	}
+	return dtmf, speech
+}
+
+// ========================================
+// Flow
+// ========================================
+
+// ErrUnknownState is returned for references to states a flow lacks
+var ErrUnknownState = errors.New("flow: unknown state")
+
+// Flow is a validated set of states
+type Flow struct {
+	initial string
+	states  map[string]*State
+}
+
+// New creates a flow starting in initial. Every referenced state must be
+// defined.
+func New(initial string, states ...State) (*Flow, error) {
+	f := &Flow{
+		initial: initial,
+		states:  make(map[string]*State, len(states)),
+	}
+	for i := range states {
+		s := states[i]
+		if _, dup := f.states[s.Name]; dup {
+			return nil, fmt.Errorf("flow: duplicate state %q", s.Name)
+		}
+		f.states[s.Name] = &s
+	}
+
+	if _, ok := f.states[initial]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownState, initial)
+	}
+	for _, s := range f.states {
+		for _, t := range s.Transitions {
+			if t.Match == nil {
+				return nil, fmt.Errorf("flow: state %q has a transition without matcher", s.Name)
+			}
+			if _, ok := f.states[t.Target]; !ok {
+				return nil, fmt.Errorf("%w: %q", ErrUnknownState, t.Target)
+			}
+		}
+		for _, name := range []string{s.NoMatch, s.Timeout} {
+			if _, ok := f.states[name]; name != "" && !ok {
+				return nil, fmt.Errorf("%w: %q", ErrUnknownState, name)
+			}
+		}
+	}
+
+	return f, nil
+}
+
+// Initial returns the name of the first state
+func (f *Flow) Initial() string {
+	return f.initial
+}
+
+// State returns the named state
+func (f *Flow) State(name string) (*State, bool) {
+	s, ok := f.states[name]
+	return s, ok
+}
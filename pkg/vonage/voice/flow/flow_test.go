@@ -0,0 +1,283 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/vonatrigger/poc/pkg/vonage/voice"
+)
+
+// newMenu returns a support line: press 1 for sales or 2 for support, or
+// ask for an operator; silence leads to "timeout"
+func newMenu(t *testing.T) *Flow {
+	t.Helper()
+	f, err := New("menu",
+		State{
+			Name:    "menu",
+			Prompts: []Prompt{Talk("Press 1 for sales or 2 for support.")},
+			Transitions: []Transition{
+				On(DTMF("1"), "sales").Do(func(s *Session, in Input) error {
+					s.Set("choice", in.DTMF)
+					return nil
+				}),
+				On(DTMF("2"), "support"),
+				On(Speech("operator"), "operator"),
+			},
+			Timeout: "timeout",
+		},
+		State{
+			Name:        "account",
+			Prompts:     []Prompt{Talk("Enter your four digit account number.")},
+			Transitions: []Transition{On(DTMF(`\d{4}`), "sales")},
+			NoMatch:     "operator",
+			MaxRetries:  1,
+		},
+		State{Name: "sales", Prompts: []Prompt{Talk("Connecting you to sales.")}},
+		State{Name: "support", Prompts: []Prompt{Talk("Connecting you to support.")}},
+		State{Name: "operator", Prompts: []Prompt{Talk("Connecting you to an operator.")}},
+		State{Name: "timeout", Prompts: []Prompt{Talk("We did not hear from you.")}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestNewMissingState(t *testing.T) {
+	final := State{Name: "end"}
+
+	tests := []struct {
+		name    string
+		initial string
+		states  []State
+		wantErr error
+	}{
+		{"valid", "start", []State{{Name: "start", Transitions: []Transition{On(Any(), "end")}}, final}, nil},
+		{"missing initial", "start", []State{final}, ErrUnknownState},
+		{"missing target", "start", []State{{Name: "start", Transitions: []Transition{On(Any(), "gone")}}}, ErrUnknownState},
+		{"missing no-match", "start", []State{{Name: "start", NoMatch: "gone", Transitions: []Transition{On(Any(), "end")}}, final}, ErrUnknownState},
+		{"missing timeout", "start", []State{{Name: "start", Timeout: "gone", Transitions: []Transition{On(Any(), "end")}}, final}, ErrUnknownState},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.initial, tt.states...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("New() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := New("start", State{Name: "start"}, State{Name: "start"}); err == nil {
+		t.Error("New() with a duplicate state succeeded")
+	}
+	if _, err := New("start", State{Name: "start", Transitions: []Transition{{Target: "start"}}}); err == nil {
+		t.Error("New() with a transition without matcher succeeded")
+	}
+	if _, err := NewEngine(newMenu(t), "https://example.com/input", WithNoMatchState("gone")); !errors.Is(err, ErrUnknownState) {
+		t.Errorf("NewEngine() with a missing no-match state = %v, want ErrUnknownState", err)
+	}
+}
+
+func TestMatchers(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher Matcher
+		in      Input
+		want    bool
+	}{
+		{"digit", DTMF("1"), Input{DTMF: "1"}, true},
+		{"digit is anchored", DTMF("1"), Input{DTMF: "12"}, false},
+		{"digit class", DTMF("[1-3]"), Input{DTMF: "3"}, true},
+		{"alternation is anchored", DTMF("1|2"), Input{DTMF: "21"}, false},
+		{"digit count", DTMF(`\d{4}`), Input{DTMF: "1234"}, true},
+		{"speech is not DTMF", DTMF("1"), Input{Transcript: "1"}, false},
+		{"phrase", Speech("Operator"), Input{Transcript: "an operator please"}, true},
+		{"other phrase", Speech("operator", "agent"), Input{Transcript: "Agent"}, true},
+		{"no phrase", Speech("operator"), Input{Transcript: "sales"}, false},
+		{"DTMF is not speech", Speech("1"), Input{DTMF: "1"}, false},
+		{"intent", Intent("billing"), Input{Transcript: "my bill", Intent: "billing"}, true},
+		{"unclassified", Intent("billing"), Input{Transcript: "my bill"}, false},
+		{"any digit", Any(), Input{DTMF: "9"}, true},
+		{"any speech", Any(), Input{Transcript: "hello"}, true},
+		{"any silence", Any(), Input{TimedOut: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.matcher.Match(tt.in); got != tt.want {
+				t.Errorf("Match(%+v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngineTransitions(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   []EngineOption
+		inputs    []Input
+		wantText  string
+		wantState string
+	}{
+		{"DTMF 1", nil, []Input{{DTMF: "1"}}, "Connecting you to sales.", ""},
+		{"DTMF 2", nil, []Input{{DTMF: "2"}}, "Connecting you to support.", ""},
+		{"speech", nil, []Input{{Transcript: "Operator please"}}, "Connecting you to an operator.", ""},
+		{"timeout", nil, []Input{{TimedOut: true}}, "We did not hear from you.", ""},
+		{"re-prompt", nil, []Input{{DTMF: "9"}}, "Press 1 for sales or 2 for support.", "menu"},
+		{"match after re-prompt", nil, []Input{{DTMF: "9"}, {DTMF: "2"}}, "Connecting you to support.", ""},
+		{"retries used up hang up", nil, []Input{{DTMF: "9"}, {DTMF: "9"}, {DTMF: "9"}}, "", ""},
+		{"retries used up fallback", []EngineOption{WithNoMatchState("operator")}, []Input{{DTMF: "9"}, {DTMF: "9"}, {DTMF: "9"}}, "Connecting you to an operator.", ""},
+		{"engine retry limit", []EngineOption{WithMaxRetries(0), WithNoMatchState("operator")}, []Input{{DTMF: "9"}}, "Connecting you to an operator.", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := NewMemoryStore()
+			e, err := NewEngine(newMenu(t), "https://example.com/input", append([]EngineOption{WithStore(store)}, tt.options...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := e.Start(ctx, "CON-1"); err != nil {
+				t.Fatal(err)
+			}
+
+			var ncco voice.NCCO
+			for _, in := range tt.inputs {
+				if ncco, err = e.HandleInput(ctx, "CON-1", in); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var text string
+			if len(ncco) > 0 {
+				text = ncco[0].Text
+			}
+			if text != tt.wantText {
+				t.Errorf("NCCO says %q, want %q", text, tt.wantText)
+			}
+			// Final states and hang-ups end the session
+			sess, _ := store.Load(ctx, "CON-1")
+			if got := sessionState(sess); got != tt.wantState {
+				t.Errorf("session state = %q, want %q", got, tt.wantState)
+			}
+		})
+	}
+}
+
+func sessionState(s *Session) string {
+	if s == nil {
+		return ""
+	}
+	return s.State
+}
+
+func TestEngineStateRetryLimit(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	e, _ := NewEngine(newMenu(t), "https://example.com/input", WithStore(store))
+	e.Start(ctx, "CON-1")
+	store.Save(ctx, &Session{ConversationUUID: "CON-1", State: "account"})
+
+	// The state allows one retry, then enters its own NoMatch state
+	if ncco, _ := e.HandleInput(ctx, "CON-1", Input{DTMF: "12"}); ncco[0].Text != "Enter your four digit account number." {
+		t.Errorf("first miss says %q, want a re-prompt", ncco[0].Text)
+	}
+	if ncco, _ := e.HandleInput(ctx, "CON-1", Input{DTMF: "12"}); ncco[0].Text != "Connecting you to an operator." {
+		t.Errorf("second miss says %q, want the operator", ncco[0].Text)
+	}
+}
+
+func TestEngineTransitionAction(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	f, _ := New("menu",
+		State{Name: "menu", Transitions: []Transition{
+			On(DTMF("1"), "menu").Do(func(s *Session, in Input) error {
+				s.Set("choice", in.DTMF)
+				return nil
+			}),
+			On(DTMF("2"), "menu").Do(func(s *Session, in Input) error {
+				return errors.New("rejected")
+			}),
+		}},
+	)
+	e, _ := NewEngine(f, "https://example.com/input", WithStore(store))
+	e.Start(ctx, "CON-1")
+
+	if _, err := e.HandleInput(ctx, "CON-1", Input{DTMF: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if sess, _ := store.Load(ctx, "CON-1"); sess.Get("choice") != "1" {
+		t.Errorf("choice = %q, want the action to record 1", sess.Get("choice"))
+	}
+	if _, err := e.HandleInput(ctx, "CON-1", Input{DTMF: "2"}); err == nil {
+		t.Error("HandleInput() with a failing action succeeded")
+	}
+}
+
+func TestEngineSessionErrors(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	e, _ := NewEngine(newMenu(t), "https://example.com/input", WithStore(store))
+
+	if _, err := e.HandleInput(ctx, "CON-1", Input{DTMF: "1"}); !errors.Is(err, ErrNoSession) {
+		t.Errorf("HandleInput() before Start = %v, want ErrNoSession", err)
+	}
+
+	// A session saved by an older version of the flow
+	store.Save(ctx, &Session{ConversationUUID: "CON-2", State: "retired"})
+	if _, err := e.HandleInput(ctx, "CON-2", Input{DTMF: "1"}); !errors.Is(err, ErrUnknownState) {
+		t.Errorf("HandleInput() in a missing state = %v, want ErrUnknownState", err)
+	}
+
+	e.Start(ctx, "CON-3")
+	if err := e.End(ctx, "CON-3"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.HandleInput(ctx, "CON-3", Input{DTMF: "1"}); !errors.Is(err, ErrNoSession) {
+		t.Errorf("HandleInput() after End = %v, want ErrNoSession", err)
+	}
+}
+
+func TestEngineInputTypes(t *testing.T) {
+	tests := []struct {
+		name        string
+		transitions []Transition
+		want        []string
+	}{
+		{"DTMF only", []Transition{On(DTMF("1"), "end"), On(DTMF("2"), "end")}, []string{"dtmf"}},
+		{"speech only", []Transition{On(Speech("yes"), "end"), On(Intent("no"), "end")}, []string{"speech"}},
+		{"DTMF and speech", []Transition{On(DTMF("1"), "end"), On(Speech("one"), "end")}, []string{"dtmf", "speech"}},
+		{"custom matcher", []Transition{On(Any(), "end")}, []string{"dtmf", "speech"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New("start", State{Name: "start", Transitions: tt.transitions}, State{Name: "end"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			e, _ := NewEngine(f, "https://example.com/input", WithInputSettings(1.5, 5, 4))
+			ncco, err := e.Start(context.Background(), "CON-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			input := ncco[len(ncco)-1]
+			if input.ActionType != "input" || !reflect.DeepEqual(input.Type, tt.want) {
+				t.Fatalf("last action = %s %v, want input %v", input.ActionType, input.Type, tt.want)
+			}
+			// Settings only apply to the input types in use
+			hasDTMF, hasSpeech := input.MaxDigits == 4, input.EndOnSilence == 1.5
+			if want := tt.want[0] == "dtmf"; hasDTMF != want {
+				t.Errorf("maxDigits = %d with types %v", input.MaxDigits, tt.want)
+			}
+			if want := tt.want[len(tt.want)-1] == "speech"; hasSpeech != want {
+				t.Errorf("endOnSilence = %g with types %v", input.EndOnSilence, tt.want)
+			}
+		})
+	}
+}
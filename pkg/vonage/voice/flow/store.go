@@ -0,0 +1,105 @@
+package flow
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ========================================
+// Sessions
+// ========================================
+
+// Session is the state of one conversation in a flow
+type Session struct {
+	ConversationUUID string            `json:"conversation_uuid"`
+	State            string            `json:"state"`
+	Retries          int               `json:"retries"`
+	Data             map[string]string `json:"data,omitempty"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
+
+// Get returns a value stored in the session
+func (s *Session) Get(key string) string {
+	return s.Data[key]
+}
+
+// Set stores a value in the session, e.g. a quiz answer or score
+func (s *Session) Set(key, value string) {
+	if s.Data == nil {
+		s.Data = make(map[string]string)
+	}
+	s.Data[key] = value
+}
+
+// Store persists sessions between webhooks. Deployments with several
+// instances need a shared implementation, e.g. backed by Redis; Session
+// is JSON-encodable for that purpose. Load returns nil, nil for unknown
+// conversations.
+type Store interface {
+	Load(ctx context.Context, conversationUUID string) (*Session, error)
+	Save(ctx context.Context, s *Session) error
+	Delete(ctx context.Context, conversationUUID string) error
+}
+
+// MemoryStore is an in-process Store
+type MemoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]Session
+}
+
+// NewMemoryStore creates an empty in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: make(map[string]Session)}
+}
+
+// Load implements Store
+func (m *MemoryStore) Load(ctx context.Context, conversationUUID string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[conversationUUID]
+	if !ok {
+		return nil, nil
+	}
+	s.Data = copyData(s.Data)
+	return &s, nil
+}
+
+// Save implements Store
+func (m *MemoryStore) Save(ctx context.Context, s *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *s
+	stored.Data = copyData(s.Data)
+	m.sessions[s.ConversationUUID] = stored
+	return nil
+}
+
+// Delete implements Store
+func (m *MemoryStore) Delete(ctx context.Context, conversationUUID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, conversationUUID)
+	return nil
+}
+
+// Len returns the number of stored sessions
+func (m *MemoryStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+// copyData copies session data so callers cannot mutate stored sessions
+func copyData(data map[string]string) map[string]string {
+	if data == nil {
+		return nil
+	}
+	c := make(map[string]string, len(data))
+	for k, v := range data {
+		c[k] = v
+	}
+	return c
+}
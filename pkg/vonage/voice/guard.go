@@ -0,0 +1,61 @@
+package voice
+
+import (
+	"context"
+	"sync"
+)
+
+// ========================================
+// Per-Call Serialization
+// ========================================
+
+// callGuard holds one lock per call UUID. Locks are dropped once nobody
+// holds or waits for them, so finished calls don't accumulate.
+type callGuard struct {
+	mu    sync.Mutex
+	calls map[string]*callLock
+}
+
+// callLock is a lock that can be waited for with a context
+type callLock struct {
+	sem  chan struct{}
+	refs int
+}
+
+func newCallGuard() *callGuard {
+	return &callGuard{calls: make(map[string]*callLock)}
+}
+
+// lock waits until the call is free or ctx is done
+func (g *callGuard) lock(ctx context.Context, callUUID string) (unlock func(), err error) {
+	g.mu.Lock()
+	l := g.calls[callUUID]
+	if l == nil {
+		l = &callLock{sem: make(chan struct{}, 1)}
+		g.calls[callUUID] = l
+	}
+	l.refs++
+	g.mu.Unlock()
+
+	select {
+	case l.sem <- struct{}{}:
+		return func() {
+			<-l.sem
+			g.release(callUUID, l)
+		}, nil
+	case <-ctx.Done():
+		g.release(callUUID, l)
+		return nil, ctx.Err()
+	}
+}
+
+// release drops a reference to the call's lock
+func (g *callGuard) release(callUUID string, l *callLock) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	l.refs--
+	if l.refs == 0 {
+		delete(g.calls, callUUID)
+	}
+}
@@ -0,0 +1,355 @@
+package voice
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ========================================
+// Japanese TTS Readings
+// ========================================
+
+// JapaneseBreak is the SSML pause JapaneseSSML inserts between the groups
+// of a phone number
+const JapaneseBreak = `<break time="300ms"/>`
+
+// JapaneseReading rewrites the numbers in text as kana readings, since the
+// Japanese voices misread raw digits: dates ("2024/4/1", "4月1日"), times
+// ("9:05", "9時5分"), phone numbers (read digit by digit, with "、"
+// between groups) and other numbers, including "1,200" and "3.5".
+// Full-width digits are converted first. Counter words following a number
+// (人, 本, 回) are left to the voice.
+func JapaneseReading(text string) string {
+	return japaneseReading(text, "、")
+}
+
+// JapaneseSSML is like JapaneseReading but returns SSML, with text
+// escaped and a JapaneseBreak between the groups of phone numbers, for a
+// talk action's text
+func JapaneseSSML(text string) string {
+	return "<speak>" + japaneseReading(ssmlEscaper.Replace(text), JapaneseBreak) + "</speak>"
+}
+
+// NormalizeJapanese replaces the talk text with its JapaneseSSML form. Use
+// it with Japanese.
+func (t *TalkBuilder) NormalizeJapanese() *TalkBuilder {
+	t.action.Text = JapaneseSSML(t.action.Text)
+	return t
+}
+
+var (
+	ssmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;")
+
+	fullwidthDigits = strings.NewReplacer(
+		"０", "0", "１", "1", "２", "2", "３", "3", "４", "4",
+		"５", "5", "６", "6", "７", "7", "８", "8", "９", "9",
+		"，", ",", "．", ".", "：", ":", "／", "/", "－", "-", "＋", "+",
+	)
+
+	jaFullDate  = regexp.MustCompile(`\b(\d{4})(?:[/-](\d{1,2})[/-](\d{1,2})\b|年(\d{1,2})月(\d{1,2})日)`)
+	jaMonthDay  = regexp.MustCompile(`(\d{1,2})月(\d{1,2})日`)
+	jaDateUnit  = regexp.MustCompile(`(\d{1,4})(年|月|日)`)
+	jaClock     = regexp.MustCompile(`\b(\d{1,2}):(\d{2})\b|(\d{1,2})時(?:(\d{1,2})分)?`)
+	jaMinutes   = regexp.MustCompile(`(\d{1,2})分`)
+	jaPhone     = regexp.MustCompile(`(?:\+81[- ]?|\b0)\d{1,4}[- ]\d{1,4}[- ]\d{3,4}\b|\b0\d{9,10}\b`)
+	jaNumber    = regexp.MustCompile(`\d{1,3}(?:,\d{3})+(?:\.\d+)?|\d+(?:\.\d+)?`)
+	jaPhoneSeps = regexp.MustCompile(`[- ]+`)
+)
+
+// japaneseReading converts text, placing sep between phone number groups
+func japaneseReading(text, sep string) string {
+	text = fullwidthDigits.Replace(text)
+
+	text = jaFullDate.ReplaceAllStringFunc(text, func(s string) string {
+		m := jaFullDate.FindStringSubmatch(s)
+		month, day := m[2], m[3]
+		if month == "" {
+			month, day = m[4], m[5]
+		}
+		if r, ok := jaDate(m[1], month, day); ok {
+			return r
+		}
+		return s
+	})
+	text = jaMonthDay.ReplaceAllStringFunc(text, func(s string) string {
+		m := jaMonthDay.FindStringSubmatch(s)
+		if r, ok := jaDate("", m[1], m[2]); ok {
+			return r
+		}
+		return s
+	})
+	text = jaDateUnit.ReplaceAllStringFunc(text, func(s string) string {
+		m := jaDateUnit.FindStringSubmatch(s)
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "年":
+			return jaYear(n)
+		case "月":
+			if r, ok := jaMonth(n); ok {
+				return r
+			}
+		case "日":
+			if r, ok := jaDay(n); ok {
+				return r
+			}
+		}
+		return s
+	})
+
+	text = jaClock.ReplaceAllStringFunc(text, func(s string) string {
+		m := jaClock.FindStringSubmatch(s)
+		hour, minute := m[1], m[2]
+		if hour == "" {
+			hour, minute = m[3], m[4]
+		}
+		h, _ := strconv.Atoi(hour)
+		if h > 24 {
+			return s
+		}
+		r := jaHour(h)
+		if minute != "" {
+			mm, _ := strconv.Atoi(minute)
+			if mm > 59 {
+				return s
+			}
+			if mm > 0 {
+				r += jaMinute(mm)
+			}
+		}
+		return r
+	})
+	text = jaMinutes.ReplaceAllStringFunc(text, func(s string) string {
+		mm, _ := strconv.Atoi(strings.TrimSuffix(s, "分"))
+		return jaMinute(mm)
+	})
+
+	text = jaPhone.ReplaceAllStringFunc(text, func(s string) string {
+		if rest, ok := strings.CutPrefix(s, "+81"); ok {
+			s = "0" + strings.TrimLeft(rest, "- ")
+		}
+		groups := jaPhoneSeps.Split(s, -1)
+		for i, g := range groups {
+			groups[i] = jaDigits(g, jaPhoneDigits)
+		}
+		// A placeholder keeps the digits of an SSML sep from being read
+		return strings.Join(groups, "\x00")
+	})
+
+	text = jaNumber.ReplaceAllStringFunc(text, jaDecimal)
+	return strings.ReplaceAll(text, "\x00", sep)
+}
+
+// jaDecimal reads a number such as "1,200" or "3.14"
+func jaDecimal(s string) string {
+	whole, frac, _ := strings.Cut(strings.ReplaceAll(s, ",", ""), ".")
+	var r string
+	if n, err := strconv.ParseUint(whole, 10, 64); err != nil || len(whole) > 16 || (len(whole) > 1 && whole[0] == '0') {
+		// Codes and numbers with leading zeros are read digit by digit
+		r = jaDigits(whole, jaDigitReadings)
+	} else {
+		r = jaNumberReading(n)
+	}
+	if frac != "" {
+		r += "てん" + jaDigits(frac, jaDigitReadings)
+	}
+	return r
+}
+
+var (
+	jaDigitReadings = [10]string{"ぜろ", "いち", "に", "さん", "よん", "ご", "ろく", "なな", "はち", "きゅう"}
+	// jaPhoneDigits lengthens に and ご, as in phone numbers
+	jaPhoneDigits = [10]string{"ぜろ", "いち", "にー", "さん", "よん", "ごー", "ろく", "なな", "はち", "きゅう"}
+)
+
+// jaDigits reads each digit of s
+func jaDigits(s string, readings [10]string) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			b.WriteString(readings[c-'0'])
+		}
+	}
+	return b.String()
+}
+
+// jaNumberReading reads n, e.g. 3800 as さんぜんはっぴゃく
+func jaNumberReading(n uint64) string {
+	if n == 0 {
+		return "ぜろ"
+	}
+
+	units := []string{"", "まん", "おく", "ちょう", "けい"}
+	var parts []string
+	for i := 0; n > 0; i++ {
+		group := int(n % 10000)
+		n /= 10000
+		if group == 0 {
+			continue
+		}
+		parts = append([]string{jaGroupReading(group, units[i])}, parts...)
+	}
+	return strings.Join(parts, "")
+}
+
+// jaGroupReading reads a group of up to four digits followed by unit
+func jaGroupReading(n int, unit string) string {
+	th, h, t, o := n/1000, n/100%10, n/10%10, n%10
+
+	var b strings.Builder
+	switch th {
+	case 0:
+	case 1:
+		if unit != "" && n%1000 == 0 {
+			b.WriteString("いっせん")
+		} else {
+			b.WriteString("せん")
+		}
+	case 3:
+		b.WriteString("さんぜん")
+	case 8:
+		b.WriteString("はっせん")
+	default:
+		b.WriteString(jaDigitReadings[th] + "せん")
+	}
+
+	switch h {
+	case 0:
+	case 1:
+		b.WriteString("ひゃく")
+	case 3:
+		b.WriteString("さんびゃく")
+	case 6:
+		b.WriteString("ろっぴゃく")
+	case 8:
+		b.WriteString("はっぴゃく")
+	default:
+		b.WriteString(jaDigitReadings[h] + "ひゃく")
+	}
+
+	// Sound changes before ちょう and けい: いっちょう, はっちょう, じゅっちょう
+	geminate := unit == "ちょう" || unit == "けい"
+	switch {
+	case t == 0:
+	case t == 1 && o == 0 && geminate:
+		b.WriteString("じゅっ")
+	case t == 1:
+		b.WriteString("じゅう")
+	case o == 0 && geminate:
+		b.WriteString(jaDigitReadings[t] + "じゅっ")
+	default:
+		b.WriteString(jaDigitReadings[t] + "じゅう")
+	}
+
+	switch {
+	case o == 0:
+	case (o == 1 || o == 8) && geminate:
+		b.WriteString(strings.TrimSuffix(jaDigitReadings[o], "ち") + "っ")
+	default:
+		b.WriteString(jaDigitReadings[o])
+	}
+
+	return b.String() + unit
+}
+
+// jaCounter reads n with a counter whose 4, 7 and 9 take other readings,
+// e.g. よじ, しちじ, くじ
+func jaCounter(n int, counter string, four, seven, nine string) string {
+	tens := ""
+	if n >= 10 {
+		tens = jaNumberReading(uint64(n - n%10))
+	}
+	switch n % 10 {
+	case 0:
+		if n == 0 {
+			return "れい" + counter
+		}
+		return tens + counter
+	case 4:
+		return tens + four + counter
+	case 7:
+		return tens + seven + counter
+	case 9:
+		return tens + nine + counter
+	default:
+		return tens + jaDigitReadings[n%10] + counter
+	}
+}
+
+// jaYear reads a year, e.g. 2024年 as にせんにじゅうよねん
+func jaYear(n int) string {
+	return jaCounter(n, "ねん", "よ", "なな", "きゅう")
+}
+
+// jaMonth reads a month, e.g. 4月 as しがつ
+func jaMonth(n int) (string, bool) {
+	if n < 1 || n > 12 {
+		return "", false
+	}
+	return jaCounter(n, "がつ", "し", "しち", "く"), true
+}
+
+// jaDay reads a day of the month, e.g. 1日 as ついたち
+func jaDay(n int) (string, bool) {
+	if n < 1 || n > 31 {
+		return "", false
+	}
+	if r, ok := jaDayReadings[n]; ok {
+		return r, true
+	}
+	return jaCounter(n, "にち", "よん", "しち", "く"), true
+}
+
+// jaDayReadings are the irregular day readings
+var jaDayReadings = map[int]string{
+	1: "ついたち", 2: "ふつか", 3: "みっか", 4: "よっか", 5: "いつか",
+	6: "むいか", 7: "なのか", 8: "ようか", 9: "ここのか", 10: "とおか",
+	14: "じゅうよっか", 20: "はつか", 24: "にじゅうよっか",
+}
+
+// jaDate reads a date; year may be empty
+func jaDate(year, month, day string) (string, bool) {
+	m, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	mr, ok := jaMonth(m)
+	if !ok {
+		return "", false
+	}
+	dr, ok := jaDay(d)
+	if !ok {
+		return "", false
+	}
+	if year == "" {
+		return mr + dr, true
+	}
+	y, _ := strconv.Atoi(year)
+	return jaYear(y) + mr + dr, true
+}
+
+// jaHour reads an hour, e.g. 9時 as くじ
+func jaHour(n int) string {
+	return jaCounter(n, "じ", "よ", "しち", "く")
+}
+
+// jaMinute reads minutes, e.g. 10分 as じゅっぷん
+func jaMinute(n int) string {
+	tens := ""
+	if n >= 10 {
+		tens = jaNumberReading(uint64(n - n%10))
+	}
+	switch n % 10 {
+	case 0:
+		if n == 0 {
+			return "れいふん"
+		}
+		return strings.TrimSuffix(tens, "じゅう") + "じゅっぷん"
+	default:
+		return tens + jaMinuteReadings[n%10]
+	}
+}
+
+// jaMinuteReadings are the minute readings of 1 to 9
+var jaMinuteReadings = [10]string{
+	"", "いっぷん", "にふん", "さんぷん", "よんぷん",
+	"ごふん", "ろっぷん", "ななふん", "はっぷん", "きゅうふん",
+}
@@ -0,0 +1,66 @@
+package voice
+
+import "fmt"
+
+// ========================================
+// Loop
+// ========================================
+
+// Loop is how many times talk or stream audio is played into a call. The
+// zero value is unset, which leaves the API default of playing once;
+// LoopInfinite repeats the audio until the call ends or it is stopped.
+type Loop struct {
+	count int
+	set   bool
+}
+
+var (
+	// LoopInfinite repeats audio until the call ends or it is stopped
+	LoopInfinite = LoopTimes(0)
+	// LoopOnce plays audio once
+	LoopOnce = LoopTimes(1)
+)
+
+// LoopTimes plays audio n times; 0 repeats it until the call ends, as in
+// the Voice API
+func LoopTimes(n int) Loop {
+	return Loop{count: n, set: true}
+}
+
+// Count returns the number of plays and whether the loop is set
+func (l Loop) Count() (int, bool) {
+	return l.count, l.set
+}
+
+// IsInfinite returns true if the audio repeats until the call ends
+func (l Loop) IsInfinite() bool {
+	return l.set && l.count == 0
+}
+
+// String returns "infinite", the number of plays, or "default" if unset
+func (l Loop) String() string {
+	switch {
+	case !l.set:
+		return "default"
+	case l.count == 0:
+		return "infinite"
+	}
+	return fmt.Sprint(l.count)
+}
+
+// Validate returns an error wrapping ErrInvalidLoop for negative counts
+func (l Loop) Validate() error {
+	if l.set && l.count < 0 {
+		return fmt.Errorf("%w, got %d", ErrInvalidLoop, l.count)
+	}
+	return nil
+}
+
+// param returns the loop as a JSON field value, nil if unset
+func (l Loop) param() *int {
+	if !l.set {
+		return nil
+	}
+	n := l.count
+	return &n
+}
@@ -0,0 +1,204 @@
+package voice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+func TestBuilderLoopMarshalling(t *testing.T) {
+	tests := []struct {
+		name string
+		ncco NCCO
+		want string
+	}{
+		{
+			name: "talk default",
+			ncco: NewNCCO().Talk("hi").Done().Build(),
+			want: `[{"action":"talk","text":"hi"}]`,
+		},
+		{
+			name: "talk infinite",
+			ncco: NewNCCO().Talk("hi").Infinite().Done().Build(),
+			want: `[{"action":"talk","text":"hi","loop":0}]`,
+		},
+		{
+			name: "talk loop zero",
+			ncco: NewNCCO().Talk("hi").Loop(0).Done().Build(),
+			want: `[{"action":"talk","text":"hi","loop":0}]`,
+		},
+		{
+			name: "talk once",
+			ncco: NewNCCO().Talk("hi").Once().Done().Build(),
+			want: `[{"action":"talk","text":"hi","loop":1}]`,
+		},
+		{
+			name: "stream default",
+			ncco: NewNCCO().Stream("https://example.com/a.mp3").Done().Build(),
+			want: `[{"action":"stream","streamUrl":["https://example.com/a.mp3"]}]`,
+		},
+		{
+			name: "stream infinite",
+			ncco: NewNCCO().Stream("https://example.com/a.mp3").Infinite().Done().Build(),
+			want: `[{"action":"stream","loop":0,"streamUrl":["https://example.com/a.mp3"]}]`,
+		},
+		{
+			name: "stream three times",
+			ncco: NewNCCO().Stream("https://example.com/a.mp3").Loop(3).Done().Build(),
+			want: `[{"action":"stream","loop":3,"streamUrl":["https://example.com/a.mp3"]}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.ncco)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("got %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoopInfiniteSurvivesRoundTrip(t *testing.T) {
+	data, _ := json.Marshal(NewNCCO().Talk("hi").Infinite().Done().Build())
+
+	var ncco NCCO
+	if err := json.Unmarshal(data, &ncco); err != nil {
+		t.Fatal(err)
+	}
+	if ncco[0].Loop == nil || *ncco[0].Loop != 0 {
+		t.Fatalf("Loop = %v, want pointer to 0", ncco[0].Loop)
+	}
+}
+
+func TestLoopValue(t *testing.T) {
+	tests := []struct {
+		loop     Loop
+		count    int
+		set      bool
+		infinite bool
+		str      string
+	}{
+		{Loop{}, 0, false, false, "default"},
+		{LoopInfinite, 0, true, true, "infinite"},
+		{LoopOnce, 1, true, false, "1"},
+		{LoopTimes(4), 4, true, false, "4"},
+	}
+
+	for _, tt := range tests {
+		count, set := tt.loop.Count()
+		if count != tt.count || set != tt.set {
+			t.Errorf("%v.Count() = %d, %v, want %d, %v", tt.loop, count, set, tt.count, tt.set)
+		}
+		if got := tt.loop.IsInfinite(); got != tt.infinite {
+			t.Errorf("%v.IsInfinite() = %v, want %v", tt.loop, got, tt.infinite)
+		}
+		if got := tt.loop.String(); got != tt.str {
+			t.Errorf("String() = %q, want %q", got, tt.str)
+		}
+	}
+
+	if err := LoopTimes(-1).Validate(); !errors.Is(err, ErrInvalidLoop) {
+		t.Errorf("LoopTimes(-1).Validate() = %v, want ErrInvalidLoop", err)
+	}
+}
+
+// recordBodies returns a client whose requests' paths and bodies are
+// recorded in got
+func recordBodies(t *testing.T, got map[string]string) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got[r.URL.Path] = string(body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient(nil, WithTransport(vonage.NewTransport(srv.URL, nil)))
+}
+
+func TestTalkIntoCallLoop(t *testing.T) {
+	tests := []struct {
+		name string
+		loop Loop
+		want string
+	}{
+		{"default", Loop{}, `{"text":"hi","voice_name":"Mizuki"}`},
+		{"infinite", LoopInfinite, `{"text":"hi","voice_name":"Mizuki","loop":0}`},
+		{"once", LoopOnce, `{"text":"hi","voice_name":"Mizuki","loop":1}`},
+		{"twice", LoopTimes(2), `{"text":"hi","voice_name":"Mizuki","loop":2}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]string)
+			c := recordBodies(t, got)
+
+			err := c.TalkIntoCall(context.Background(), "CALL-1", TalkIntoCallOptions{
+				Text:      "hi",
+				VoiceName: "Mizuki",
+				Loop:      tt.loop,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if body := got["/v1/calls/CALL-1/talk"]; body != tt.want {
+				t.Errorf("body = %s, want %s", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamIntoCallLoop(t *testing.T) {
+	tests := []struct {
+		name string
+		loop Loop
+		want string
+	}{
+		{"default", Loop{}, `{"stream_url":["https://example.com/a.mp3"]}`},
+		{"infinite", LoopInfinite, `{"stream_url":["https://example.com/a.mp3"],"loop":0}`},
+		{"three times", LoopTimes(3), `{"stream_url":["https://example.com/a.mp3"],"loop":3}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]string)
+			c := recordBodies(t, got)
+
+			err := c.StreamIntoCall(context.Background(), "CALL-1", StreamIntoCallOptions{
+				StreamURL: "https://example.com/a.mp3",
+				Loop:      tt.loop,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if body := got["/v1/calls/CALL-1/stream"]; body != tt.want {
+				t.Errorf("body = %s, want %s", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntoCallRejectsNegativeLoop(t *testing.T) {
+	got := make(map[string]string)
+	c := recordBodies(t, got)
+	ctx := context.Background()
+
+	if err := c.TalkIntoCall(ctx, "CALL-1", TalkIntoCallOptions{Text: "hi", Loop: LoopTimes(-1)}); !errors.Is(err, ErrInvalidLoop) {
+		t.Errorf("TalkIntoCall error = %v, want ErrInvalidLoop", err)
+	}
+	if err := c.StreamIntoCall(ctx, "CALL-1", StreamIntoCallOptions{StreamURL: "https://example.com/a.mp3", Loop: LoopTimes(-1)}); !errors.Is(err, ErrInvalidLoop) {
+		t.Errorf("StreamIntoCall error = %v, want ErrInvalidLoop", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("sent %d requests, want none", len(got))
+	}
+}
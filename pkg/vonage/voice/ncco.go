@@ -1,6 +1,9 @@
 package voice
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"sync"
+)
 
 // ========================================
 // NCCO (Nexmo Call Control Objects)
@@ -14,20 +17,33 @@ func (n NCCO) JSON() ([]byte, error) {
 	return json.Marshal(n)
 }
 
+// Clone returns a deep copy of the NCCO
+func (n NCCO) Clone() NCCO {
+	if n == nil {
+		return nil
+	}
+	out := make(NCCO, len(n))
+	for i, a := range n {
+		out[i] = a.Clone()
+	}
+	return out
+}
+
 // Action represents a single NCCO action
 type Action struct {
 	// Common fields
 	ActionType string `json:"action"`
 
-	// Talk action
-	Text      string `json:"text,omitempty"`
-	VoiceName string `json:"voiceName,omitempty"`
-	Language  string `json:"language,omitempty"`
-	Style     int    `json:"style,omitempty"`
-	Premium   bool   `json:"premium,omitempty"`
-	Level     int    `json:"level,omitempty"`
-	BargeIn   *bool  `json:"bargeIn,omitempty"`
-	Loop      int    `json:"loop,omitempty"`
+	// Talk action. Style, Level and Loop are pointers because their zero
+	// values are meaningful: style 0, level 0 and loop 0 (infinite).
+	Text      string   `json:"text,omitempty"`
+	VoiceName string   `json:"voiceName,omitempty"`
+	Language  string   `json:"language,omitempty"`
+	Style     *int     `json:"style,omitempty"`
+	Premium   bool     `json:"premium,omitempty"`
+	Level     *float64 `json:"level,omitempty"`
+	BargeIn   *bool    `json:"bargeIn,omitempty"`
+	Loop      *int     `json:"loop,omitempty"`
 
 	// Stream action
 	StreamURL []string `json:"streamUrl,omitempty"`
@@ -35,7 +51,7 @@ type Action struct {
 	// Input action
 	Type         []string `json:"type,omitempty"`
 	EventURL     []string `json:"eventUrl,omitempty"`
-	EventMethod  string   `json:"eventMethod,omitempty"`
+	EventMethod  HTTPMethod `json:"eventMethod,omitempty"`
 	EndOnSilence float64  `json:"endOnSilence,omitempty"`
 	StartTimeout int      `json:"startTimeout,omitempty"`
 	MaxDuration  int      `json:"maxDuration,omitempty"`
@@ -56,13 +72,76 @@ type Action struct {
 	Split        string   `json:"split,omitempty"`
 }
 
+// Clone returns a deep copy of the action, sharing no slices, maps or
+// pointers with it
+func (a Action) Clone() Action {
+	a.Style = clonePtr(a.Style)
+	a.Level = clonePtr(a.Level)
+	a.Loop = clonePtr(a.Loop)
+	a.BargeIn = clonePtr(a.BargeIn)
+	a.BeepStart = clonePtr(a.BeepStart)
+	a.StreamURL = cloneStrings(a.StreamURL)
+	a.Type = cloneStrings(a.Type)
+	a.EventURL = cloneStrings(a.EventURL)
+	if a.Payload != nil {
+		a.Payload = cloneValue(a.Payload).(map[string]interface{})
+	}
+	return a
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s...)
+}
+
+// cloneValue deep-copies the maps and slices of a JSON-like value
+func cloneValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			out[k] = cloneValue(e)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			out[i] = cloneValue(e)
+		}
+		return out
+	case []string:
+		return cloneStrings(v)
+	}
+	return v
+}
+
 // ========================================
 // NCCO Builder
 // ========================================
 
-// NCCOBuilder provides a fluent API for building NCCO
+// NCCOBuilder provides a fluent API for building NCCO.
+//
+// A builder is safe for concurrent use: each action is added atomically
+// and Build returns a deep copy, so a built NCCO never changes when the
+// builder is used again. Actions added concurrently interleave, though;
+// to build per-call NCCOs from a shared template, Clone the template for
+// each call and add to the clone.
 type NCCOBuilder struct {
+	mu      sync.Mutex
 	actions []Action
+
+	// cache swaps talk actions for streams of pre-synthesized audio
+	cache PromptCache
 }
 
 // NewNCCO creates a new NCCO builder
@@ -72,9 +151,49 @@ func NewNCCO() *NCCOBuilder {
 	}
 }
 
-// Build returns the final NCCO
+// WithPromptCache makes Talk actions added after this call play cached
+// audio from c as Stream actions when available, avoiding per-call TTS
+// latency. Prompts that are not cached stay Talk actions.
+func (b *NCCOBuilder) WithPromptCache(c PromptCache) *NCCOBuilder {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cache = c
+	return b
+}
+
+// Build returns a deep copy of the NCCO built so far
 func (b *NCCOBuilder) Build() NCCO {
-	return NCCO(b.actions)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return NCCO(b.actions).Clone()
+}
+
+// Clone returns an independent builder with a copy of the actions and the
+// prompt cache, e.g. to extend a template NCCO per call
+func (b *NCCOBuilder) Clone() *NCCOBuilder {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return &NCCOBuilder{
+		actions: NCCO(b.actions).Clone(),
+		cache:   b.cache,
+	}
+}
+
+// add appends deep copies of actions
+func (b *NCCOBuilder) add(actions ...Action) *NCCOBuilder {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, a := range actions {
+		b.actions = append(b.actions, a.Clone())
+	}
+	return b
+}
+
+// promptCache returns the builder's prompt cache
+func (b *NCCOBuilder) promptCache() PromptCache {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.cache
 }
 
 // ========================================
@@ -110,9 +229,9 @@ func (t *TalkBuilder) Language(lang string) *TalkBuilder {
 	return t
 }
 
-// Style sets the voice style
+// Style sets the voice style; 0 is the voice's first style
 func (t *TalkBuilder) Style(style int) *TalkBuilder {
-	t.action.Style = style
+	t.action.Style = &style
 	return t
 }
 
@@ -122,9 +241,9 @@ func (t *TalkBuilder) Premium() *TalkBuilder {
 	return t
 }
 
-// Level sets the volume level (-1 to 1)
-func (t *TalkBuilder) Level(level int) *TalkBuilder {
-	t.action.Level = level
+// Level sets the volume level, from -1 to 1 in steps of 0.1 (default 0)
+func (t *TalkBuilder) Level(level float64) *TalkBuilder {
+	t.action.Level = &level
 	return t
 }
 
@@ -135,12 +254,23 @@ func (t *TalkBuilder) BargeIn() *TalkBuilder {
 	return t
 }
 
-// Loop sets the number of times to loop (0 = infinite)
+// Loop sets the number of times to play the text; 0 repeats it until the
+// call ends (default 1)
 func (t *TalkBuilder) Loop(count int) *TalkBuilder {
-	t.action.Loop = count
+	t.action.Loop = &count
 	return t
 }
 
+// Infinite repeats the text until the call ends
+func (t *TalkBuilder) Infinite() *TalkBuilder {
+	return t.Loop(0)
+}
+
+// Once plays the text once
+func (t *TalkBuilder) Once() *TalkBuilder {
+	return t.Loop(1)
+}
+
 // Japanese is a convenience method for Japanese TTS with Mizuki voice
 func (t *TalkBuilder) Japanese() *TalkBuilder {
 	t.action.VoiceName = "Mizuki"
@@ -148,10 +278,21 @@ func (t *TalkBuilder) Japanese() *TalkBuilder {
 	return t
 }
 
-// Done finalizes the talk action and returns the NCCO builder
+// Done finalizes the talk action and returns the NCCO builder. With a
+// prompt cache, a cached prompt is added as a Stream action instead.
 func (t *TalkBuilder) Done() *NCCOBuilder {
-	t.parent.actions = append(t.parent.actions, t.action)
-	return t.parent
+	if cache := t.parent.promptCache(); cache != nil {
+		if audioURL, ok := cache.Lookup(PromptFromAction(t.action)); ok {
+			return t.parent.add(Action{
+				ActionType: "stream",
+				StreamURL:  []string{audioURL},
+				Level:      t.action.Level,
+				BargeIn:    t.action.BargeIn,
+				Loop:       t.action.Loop,
+			})
+		}
+	}
+	return t.parent.add(t.action)
 }
 
 // ========================================
@@ -175,9 +316,9 @@ func (b *NCCOBuilder) Stream(urls ...string) *StreamBuilder {
 	}
 }
 
-// Level sets the volume level (-1 to 1)
-func (s *StreamBuilder) Level(level int) *StreamBuilder {
-	s.action.Level = level
+// Level sets the volume level, from -1 to 1 in steps of 0.1 (default 0)
+func (s *StreamBuilder) Level(level float64) *StreamBuilder {
+	s.action.Level = &level
 	return s
 }
 
@@ -188,16 +329,26 @@ func (s *StreamBuilder) BargeIn() *StreamBuilder {
 	return s
 }
 
-// Loop sets the number of times to loop (0 = infinite)
+// Loop sets the number of times to play the audio; 0 repeats it until
+// the call ends (default 1)
 func (s *StreamBuilder) Loop(count int) *StreamBuilder {
-	s.action.Loop = count
+	s.action.Loop = &count
 	return s
 }
 
+// Infinite repeats the audio until the call ends, e.g. for hold music
+func (s *StreamBuilder) Infinite() *StreamBuilder {
+	return s.Loop(0)
+}
+
+// Once plays the audio once
+func (s *StreamBuilder) Once() *StreamBuilder {
+	return s.Loop(1)
+}
+
 // Done finalizes the stream action and returns the NCCO builder
 func (s *StreamBuilder) Done() *NCCOBuilder {
-	s.parent.actions = append(s.parent.actions, s.action)
-	return s.parent
+	return s.parent.add(s.action)
 }
 
 // ========================================
@@ -245,7 +396,7 @@ func (i *InputBuilder) EventURL(url string) *InputBuilder {
 }
 
 // EventMethod sets the HTTP method for the event URL
-func (i *InputBuilder) EventMethod(method string) *InputBuilder {
+func (i *InputBuilder) EventMethod(method HTTPMethod) *InputBuilder {
 	i.action.EventMethod = method
 	return i
 }
@@ -290,10 +441,9 @@ func (i *InputBuilder) TimeOut(seconds int) *InputBuilder {
 func (i *InputBuilder) Done() *NCCOBuilder {
 	// Default to POST method
 	if i.action.EventMethod == "" {
-		i.action.EventMethod = "POST"
+		i.action.EventMethod = MethodPOST
 	}
-	i.parent.actions = append(i.parent.actions, i.action)
-	return i.parent
+	return i.parent.add(i.action)
 }
 
 // ========================================
@@ -361,8 +511,7 @@ func (r *RecordBuilder) Channels(channels int) *RecordBuilder {
 
 // Done finalizes the record action and returns the NCCO builder
 func (r *RecordBuilder) Done() *NCCOBuilder {
-	r.parent.actions = append(r.parent.actions, r.action)
-	return r.parent
+	return r.parent.add(r.action)
 }
 
 // ========================================
@@ -371,13 +520,12 @@ func (r *RecordBuilder) Done() *NCCOBuilder {
 
 // Notify adds a notify action to the NCCO
 func (b *NCCOBuilder) Notify(eventURL string, payload map[string]interface{}) *NCCOBuilder {
-	b.actions = append(b.actions, Action{
+	return b.add(Action{
 		ActionType:  "notify",
 		EventURL:    []string{eventURL},
-		EventMethod: "POST",
+		EventMethod: MethodPOST,
 		Payload:     payload,
 	})
-	return b
 }
 
 // ========================================
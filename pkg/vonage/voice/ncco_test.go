@@ -0,0 +1,106 @@
+package voice
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Run with -race: these tests share builders between goroutines.
+
+func TestNCCOBuilderConcurrentAdd(t *testing.T) {
+	b := NewNCCO()
+
+	const n = 50
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			b.Talk(fmt.Sprintf("prompt %d", i)).BargeIn().Done()
+			_ = b.Build()
+		}(i)
+	}
+	wg.Wait()
+
+	if got := len(b.Build()); got != n {
+		t.Fatalf("Build() has %d actions, want %d", got, n)
+	}
+}
+
+func TestNCCOBuilderBuildIsDeepCopy(t *testing.T) {
+	b := NewNCCO().
+		Talk("hello").BargeIn().Done().
+		Input().Speech().EventURL("https://example.com/input").Done().
+		Notify("https://example.com/notify", map[string]interface{}{
+			"step": "greeting",
+			"tags": []interface{}{"a"},
+		})
+
+	first := b.Build()
+	*first[0].BargeIn = false
+	first[1].Type[0] = "dtmf"
+	first[1].EventURL[0] = "https://evil.example.com"
+	first[2].Payload["step"] = "changed"
+	first[2].Payload["tags"].([]interface{})[0] = "b"
+
+	second := b.Build()
+	if !*second[0].BargeIn {
+		t.Error("BargeIn changed through a built NCCO")
+	}
+	if second[1].Type[0] != "speech" || second[1].EventURL[0] != "https://example.com/input" {
+		t.Errorf("input action changed through a built NCCO: %+v", second[1])
+	}
+	if second[2].Payload["step"] != "greeting" || second[2].Payload["tags"].([]interface{})[0] != "a" {
+		t.Errorf("notify payload changed through a built NCCO: %v", second[2].Payload)
+	}
+}
+
+func TestNCCOBuilderCopiesArguments(t *testing.T) {
+	urls := []string{"https://example.com/a.mp3"}
+	payload := map[string]interface{}{"step": "greeting"}
+	b := NewNCCO().
+		Stream(urls...).Done().
+		Notify("https://example.com/notify", payload)
+
+	urls[0] = "https://example.com/b.mp3"
+	payload["step"] = "changed"
+
+	ncco := b.Build()
+	if ncco[0].StreamURL[0] != "https://example.com/a.mp3" {
+		t.Errorf("stream URL = %q, want the URL at Done", ncco[0].StreamURL[0])
+	}
+	if ncco[1].Payload["step"] != "greeting" {
+		t.Errorf("payload = %v, want the payload at Notify", ncco[1].Payload)
+	}
+}
+
+func TestNCCOBuilderCloneTemplate(t *testing.T) {
+	template := NewNCCO().Talk("welcome").Japanese().Done()
+
+	const calls = 20
+	results := make([]NCCO, calls)
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = template.Clone().
+				Input().Speech().EventURL(fmt.Sprintf("https://example.com/input/%d", i)).Done().
+				Build()
+		}(i)
+	}
+	wg.Wait()
+
+	for i, ncco := range results {
+		if len(ncco) != 2 {
+			t.Fatalf("call %d: %d actions, want 2", i, len(ncco))
+		}
+		if want := fmt.Sprintf("https://example.com/input/%d", i); ncco[1].EventURL[0] != want {
+			t.Errorf("call %d: event URL %q, want %q", i, ncco[1].EventURL[0], want)
+		}
+	}
+	if got := len(template.Build()); got != 1 {
+		t.Errorf("template has %d actions after cloning, want 1", got)
+	}
+}
@@ -0,0 +1,133 @@
+package voice
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ========================================
+// Input Reprompt
+// ========================================
+
+// RepromptAttemptParam is the query parameter carrying the attempt number
+// in a Reprompt's event URL
+const RepromptAttemptParam = "attempt"
+
+// Reprompt composes the prompt → input → re-prompt on timeout → fallback
+// pattern. The attempt number travels in the input event URL, so the same
+// Reprompt, defined once, serves the answer URL (NCCO or
+// NCCOBuilder.Reprompt) and the input event URL (Next) without storing
+// per-call state.
+type Reprompt struct {
+	eventURL    string
+	maxAttempts int
+	prompt      NCCO
+	retry       NCCO
+	fallback    NCCO
+	input       func(i *InputBuilder)
+}
+
+// NewReprompt creates a reprompt that gives the caller maxAttempts
+// attempts (at least 1), sending input to eventURL
+func NewReprompt(eventURL string, maxAttempts int) *Reprompt {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &Reprompt{
+		eventURL:    eventURL,
+		maxAttempts: maxAttempts,
+		input:       func(i *InputBuilder) { i.DTMF() },
+	}
+}
+
+// Prompt sets the actions played before the input on every attempt
+func (r *Reprompt) Prompt(ncco NCCO) *Reprompt {
+	r.prompt = ncco
+	return r
+}
+
+// RetryPrompt sets actions played before the prompt on later attempts,
+// e.g. "Sorry, I didn't catch that"
+func (r *Reprompt) RetryPrompt(ncco NCCO) *Reprompt {
+	r.retry = ncco
+	return r
+}
+
+// Fallback sets the actions run when the last attempt times out (default:
+// none, ending the call)
+func (r *Reprompt) Fallback(ncco NCCO) *Reprompt {
+	r.fallback = ncco
+	return r
+}
+
+// Input configures the input action (default: DTMF with Vonage's
+// defaults). The event URL is set by the reprompt.
+func (r *Reprompt) Input(configure func(i *InputBuilder)) *Reprompt {
+	r.input = configure
+	return r
+}
+
+// NCCO returns the NCCO of the first attempt
+func (r *Reprompt) NCCO() NCCO {
+	return r.attempt(1)
+}
+
+// Next continues the pattern from an input webhook. If the caller entered
+// input it returns nil and false: the input is the application's to
+// handle. Otherwise it returns the next attempt's NCCO, or the fallback
+// after the last attempt, and true.
+func (r *Reprompt) Next(req *http.Request, result *ASRResult) (NCCO, bool) {
+	if result.HasDTMF() || result.HasSpeech() {
+		return nil, false
+	}
+
+	attempt := RepromptAttempt(req)
+	if attempt >= r.maxAttempts {
+		if r.fallback == nil {
+			return NCCO{}, true
+		}
+		return r.fallback, true
+	}
+	return r.attempt(attempt + 1), true
+}
+
+// RepromptAttempt returns the attempt number of an input webhook sent to a
+// Reprompt's event URL (1 if absent)
+func RepromptAttempt(req *http.Request) int {
+	n, err := strconv.Atoi(req.URL.Query().Get(RepromptAttemptParam))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// Reprompt appends the first attempt of r
+func (b *NCCOBuilder) Reprompt(r *Reprompt) *NCCOBuilder {
+	return b.add(r.NCCO()...)
+}
+
+// attempt returns the NCCO of attempt n
+func (r *Reprompt) attempt(n int) NCCO {
+	b := NewNCCO()
+	if n > 1 {
+		b.add(r.retry...)
+	}
+	b.add(r.prompt...)
+
+	input := b.Input()
+	r.input(input)
+	return input.EventURL(r.attemptURL(n)).Done().Build()
+}
+
+// attemptURL returns the event URL with the attempt number
+func (r *Reprompt) attemptURL(n int) string {
+	u, err := url.Parse(r.eventURL)
+	if err != nil {
+		return r.eventURL
+	}
+	q := u.Query()
+	q.Set(RepromptAttemptParam, strconv.Itoa(n))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
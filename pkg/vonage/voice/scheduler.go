@@ -0,0 +1,583 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// Call Scheduling
+// ========================================
+
+// Scheduler defaults
+const (
+	// DefaultSchedulerInterval is how often Scheduler.Run looks for due
+	// calls
+	DefaultSchedulerInterval = 30 * time.Second
+	// DefaultPlacementLease is how long a placed call waits for its outcome
+	DefaultPlacementLease = time.Hour
+)
+
+// ErrScheduledCallNotFound is returned for unknown scheduled call IDs
+var ErrScheduledCallNotFound = errors.New("voice: scheduled call not found")
+
+// ScheduleStatus is the state of a scheduled call
+type ScheduleStatus string
+
+const (
+	// ScheduleStatusPending calls are waiting for their time (or a retry)
+	ScheduleStatusPending ScheduleStatus = "pending"
+	// ScheduleStatusPlaced calls have been placed and await their outcome
+	// until their lease expires
+	ScheduleStatusPlaced ScheduleStatus = "placed"
+	// ScheduleStatusCompleted calls were answered
+	ScheduleStatusCompleted ScheduleStatus = "completed"
+	// ScheduleStatusFailed calls ran out of attempts or could not be retried
+	ScheduleStatusFailed ScheduleStatus = "failed"
+	// ScheduleStatusCancelled calls were cancelled before being placed
+	ScheduleStatusCancelled ScheduleStatus = "cancelled"
+)
+
+// IsTerminal reports whether the scheduler is done with the call
+func (s ScheduleStatus) IsTerminal() bool {
+	return s == ScheduleStatusCompleted || s == ScheduleStatusFailed || s == ScheduleStatusCancelled
+}
+
+// RetryRule retries a call outcome after Delay, up to MaxAttempts attempts
+// in total. The zero value gives up.
+type RetryRule struct {
+	Delay       time.Duration `json:"delay"`
+	MaxAttempts int           `json:"max_attempts"`
+}
+
+// RetryPolicy maps call outcomes (busy, timeout, failed, ...) to retry
+// rules. Outcomes without a rule give up.
+type RetryPolicy map[CallStatus]RetryRule
+
+// DefaultRetryPolicy retries busy lines after 10 minutes and unanswered
+// calls after 30 minutes, three attempts in total; failed and rejected
+// calls give up
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		CallStatusBusy:    {Delay: 10 * time.Minute, MaxAttempts: 3},
+		CallStatusTimeout: {Delay: 30 * time.Minute, MaxAttempts: 3},
+	}
+}
+
+// QuietHours is a daily window, in the call's time zone, in which no calls
+// are placed. Start and End are offsets from midnight; a window such as
+// 21:00–09:00 spans midnight.
+type QuietHours struct {
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+}
+
+// NewQuietHours parses a window such as "21:00", "09:00"
+func NewQuietHours(start, end string) (QuietHours, error) {
+	s, err := parseClock(start)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	e, err := parseClock(end)
+	if err != nil {
+		return QuietHours{}, err
+	}
+	return QuietHours{Start: s, End: e}, nil
+}
+
+// parseClock parses "HH:MM" as an offset from midnight
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("voice: invalid time of day %q: %w", s, err)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// NextAllowed returns t, or the end of the quiet window t falls into.
+// Times of day are read from the wall clock, so the window keeps its hours
+// on days with a daylight saving change.
+func (q QuietHours) NextAllowed(t time.Time) time.Time {
+	if q.Start == q.End {
+		return t
+	}
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+
+	switch {
+	case q.Start < q.End && offset >= q.Start && offset < q.End:
+		return wallClock(t, 0, q.End)
+	case q.Start > q.End && offset >= q.Start:
+		return wallClock(t, 1, q.End)
+	case q.Start > q.End && offset < q.End:
+		return wallClock(t, 0, q.End)
+	}
+	return t
+}
+
+// wallClock returns the time of day offset on the day days after t's, in
+// t's location
+func wallClock(t time.Time, days int, offset time.Duration) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+days,
+		int(offset/time.Hour), int(offset%time.Hour/time.Minute), int(offset%time.Minute/time.Second),
+		int(offset%time.Second), t.Location())
+}
+
+// ScheduledCall is a call to place at a future time
+type ScheduledCall struct {
+	ID      string            `json:"id"`
+	Options CreateCallOptions `json:"options"`
+	// At is the earliest time to place the call
+	At time.Time `json:"at"`
+	// TimeZone is the IANA zone of the destination for quiet hours, e.g.
+	// "Asia/Tokyo" (default: the scheduler's)
+	TimeZone string `json:"time_zone,omitempty"`
+	// Retry overrides the scheduler's retry policy for this destination
+	Retry RetryPolicy `json:"retry,omitempty"`
+
+	Status   ScheduleStatus `json:"status"`
+	Attempts int            `json:"attempts"`
+	// NextAttempt is when the call is due, after quiet hours and retries,
+	// or when the lease of a placed call expires
+	NextAttempt time.Time `json:"next_attempt"`
+	CallUUID    string    `json:"call_uuid,omitempty"`
+	// LastOutcome is the final status of the last attempt
+	LastOutcome CallStatus `json:"last_outcome,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+}
+
+// ScheduleStore persists scheduled calls so they survive restarts.
+// Implementations shared by several instances must make Due claim calls
+// atomically, e.g. by leasing them, so each call is placed once.
+type ScheduleStore interface {
+	Save(ctx context.Context, call *ScheduledCall) error
+	Get(ctx context.Context, id string) (*ScheduledCall, error)
+	// GetByCallUUID returns the call whose last attempt has callUUID
+	GetByCallUUID(ctx context.Context, callUUID string) (*ScheduledCall, error)
+	// Due returns pending calls and placed calls whose NextAttempt is set
+	// and not after now
+	Due(ctx context.Context, now time.Time) ([]*ScheduledCall, error)
+}
+
+// MemoryScheduleStore is an in-process ScheduleStore
+type MemoryScheduleStore struct {
+	mu    sync.Mutex
+	calls map[string]ScheduledCall
+}
+
+// NewMemoryScheduleStore creates an empty in-process store
+func NewMemoryScheduleStore() *MemoryScheduleStore {
+	return &MemoryScheduleStore{calls: make(map[string]ScheduledCall)}
+}
+
+// Save implements ScheduleStore
+func (m *MemoryScheduleStore) Save(ctx context.Context, call *ScheduledCall) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[call.ID] = *call
+	return nil
+}
+
+// Get implements ScheduleStore
+func (m *MemoryScheduleStore) Get(ctx context.Context, id string) (*ScheduledCall, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	call, ok := m.calls[id]
+	if !ok {
+		return nil, ErrScheduledCallNotFound
+	}
+	return &call, nil
+}
+
+// GetByCallUUID implements ScheduleStore
+func (m *MemoryScheduleStore) GetByCallUUID(ctx context.Context, callUUID string) (*ScheduledCall, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, call := range m.calls {
+		if call.CallUUID == callUUID {
+			return &call, nil
+		}
+	}
+	return nil, ErrScheduledCallNotFound
+}
+
+// Due implements ScheduleStore
+func (m *MemoryScheduleStore) Due(ctx context.Context, now time.Time) ([]*ScheduledCall, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due []*ScheduledCall
+	for _, call := range m.calls {
+		leased := call.Status == ScheduleStatusPlaced && !call.NextAttempt.IsZero()
+		if (call.Status == ScheduleStatusPending || leased) && !call.NextAttempt.After(now) {
+			call := call
+			due = append(due, &call)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttempt.Before(due[j].NextAttempt) })
+	return due, nil
+}
+
+// Scheduler places calls at future times, outside quiet hours, and retries
+// them according to their outcome. Call Run in a goroutine and route call
+// events to HandleEvent so outcomes are known.
+type Scheduler struct {
+	client   API
+	store    ScheduleStore
+	logger   vonage.Logger
+	interval time.Duration
+	policy   RetryPolicy
+	quiet    *QuietHours
+	location *time.Location
+	lease    time.Duration
+	now      func() time.Time
+
+	// mu serializes state changes so Run and HandleEvent do not race. It
+	// is not held while calls are placed.
+	mu sync.Mutex
+	// placing counts calls being placed; while it is non-zero, events of
+	// unknown calls are kept in early in case they belong to one of them
+	placing int
+	early   map[string]CallEvent
+}
+
+// SchedulerOption is a functional option for configuring a Scheduler
+type SchedulerOption func(*Scheduler)
+
+// WithScheduleStore replaces the default in-memory store
+func WithScheduleStore(s ScheduleStore) SchedulerOption {
+	return func(sc *Scheduler) {
+		sc.store = s
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy
+func WithRetryPolicy(p RetryPolicy) SchedulerOption {
+	return func(sc *Scheduler) {
+		sc.policy = p
+	}
+}
+
+// WithQuietHours defers calls that fall into q, in each call's time zone
+func WithQuietHours(q QuietHours) SchedulerOption {
+	return func(sc *Scheduler) {
+		sc.quiet = &q
+	}
+}
+
+// WithDefaultTimeZone sets the zone of calls without a TimeZone (default
+// UTC)
+func WithDefaultTimeZone(loc *time.Location) SchedulerOption {
+	return func(sc *Scheduler) {
+		sc.location = loc
+	}
+}
+
+// WithPlacementLease sets how long a placed call waits for its answered or
+// final event (default DefaultPlacementLease). A call whose event is lost,
+// or whose process stopped before recording it, is then retried like a
+// failed call. d <= 0 waits forever.
+func WithPlacementLease(d time.Duration) SchedulerOption {
+	return func(sc *Scheduler) {
+		sc.lease = d
+	}
+}
+
+// WithSchedulerInterval sets how often Run looks for due calls (default
+// DefaultSchedulerInterval)
+func WithSchedulerInterval(d time.Duration) SchedulerOption {
+	return func(sc *Scheduler) {
+		sc.interval = d
+	}
+}
+
+// WithSchedulerLogger sets the logger (default: no logging)
+func WithSchedulerLogger(l vonage.Logger) SchedulerOption {
+	return func(sc *Scheduler) {
+		sc.logger = l
+	}
+}
+
+// NewScheduler creates a scheduler that places calls with client
+func NewScheduler(client API, opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		client:   client,
+		store:    NewMemoryScheduleStore(),
+		logger:   vonage.NopLogger(),
+		interval: DefaultSchedulerInterval,
+		policy:   DefaultRetryPolicy(),
+		location: time.UTC,
+		lease:    DefaultPlacementLease,
+		now:      time.Now,
+		early:    make(map[string]CallEvent),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Schedule stores a call to be placed at call.At (or now, if zero),
+// deferred past quiet hours. call.ID is required.
+func (s *Scheduler) Schedule(ctx context.Context, call ScheduledCall) (*ScheduledCall, error) {
+	if call.ID == "" {
+		return nil, errors.New("voice: scheduled call ID is required")
+	}
+	if call.At.IsZero() {
+		call.At = s.now()
+	}
+
+	next, err := s.allowed(&call, call.At)
+	if err != nil {
+		return nil, err
+	}
+	call.Status = ScheduleStatusPending
+	call.NextAttempt = next
+	call.Attempts = 0
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.store.Save(ctx, &call); err != nil {
+		return nil, fmt.Errorf("voice: failed to save scheduled call: %w", err)
+	}
+	return &call, nil
+}
+
+// Cancel cancels a pending call. Calls already placed are not hung up.
+func (s *Scheduler) Cancel(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	call, err := s.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if call.Status != ScheduleStatusPending {
+		return fmt.Errorf("voice: scheduled call %s is %s", id, call.Status)
+	}
+	call.Status = ScheduleStatusCancelled
+	return s.store.Save(ctx, call)
+}
+
+// Get returns a scheduled call
+func (s *Scheduler) Get(ctx context.Context, id string) (*ScheduledCall, error) {
+	return s.store.Get(ctx, id)
+}
+
+// Run places due calls every interval until ctx is done
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.PlaceDue(ctx); err != nil {
+			s.logger.Error("Failed to place scheduled calls", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// PlaceDue places the calls that are due and returns how many were
+// placed. Run calls it periodically. Due calls are claimed first, so
+// Cancel and other PlaceDue calls leave them alone while they are placed.
+// Calls whose quiet hours cannot be checked, e.g. because of an invalid
+// time zone, fail instead of being placed.
+func (s *Scheduler) PlaceDue(ctx context.Context) (int, error) {
+	claimed, claimErr := s.claimDue(ctx)
+
+	placed := 0
+	errs := []error{claimErr}
+	for _, call := range claimed {
+		resp, err := s.client.CreateCall(ctx, call.Options)
+		if err == nil {
+			placed++
+		}
+		errs = append(errs, s.finishPlacement(ctx, call, resp, err))
+	}
+	return placed, errors.Join(errs...)
+}
+
+// claimDue marks the due calls outside quiet hours as placed and returns
+// them. Calls that are not placed are deferred or failed, and placed calls
+// whose lease expired are retried.
+func (s *Scheduler) claimDue(ctx context.Context) ([]*ScheduledCall, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	due, err := s.store.Due(ctx, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("voice: failed to load due calls: %w", err)
+	}
+
+	var claimed []*ScheduledCall
+	for _, call := range due {
+		if call.Status == ScheduleStatusPlaced {
+			s.logger.Warn("Scheduled call outcome lost", "id", call.ID, "callUUID", call.CallUUID, "attempt", call.Attempts)
+			call.LastError = "no outcome within the placement lease"
+			s.retry(call, CallStatusFailed)
+			if err := s.store.Save(ctx, call); err != nil {
+				return claimed, fmt.Errorf("voice: failed to save scheduled call: %w", err)
+			}
+			continue
+		}
+
+		// A quiet window may have started since the call was scheduled
+		next, err := s.allowed(call, s.now())
+		switch {
+		case err != nil:
+			s.logger.Warn("Failed scheduled call outside known quiet hours", "id", call.ID, "error", err)
+			call.Status = ScheduleStatusFailed
+			call.LastError = err.Error()
+		case next.After(s.now()):
+			call.NextAttempt = next
+		default:
+			call.Attempts++
+			call.Status = ScheduleStatusPlaced
+			call.CallUUID = ""
+			call.NextAttempt = s.leaseEnd()
+		}
+		if err := s.store.Save(ctx, call); err != nil {
+			return claimed, fmt.Errorf("voice: failed to save scheduled call: %w", err)
+		}
+		if call.Status == ScheduleStatusPlaced {
+			claimed = append(claimed, call)
+			s.placing++
+		}
+	}
+	return claimed, nil
+}
+
+// finishPlacement records the result of placing a claimed call and applies
+// any event that arrived before it was recorded
+func (s *Scheduler) finishPlacement(ctx context.Context, call *ScheduledCall, resp *CreateCallResponse, err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.placing--
+	if err != nil {
+		s.logger.Warn("Failed to place scheduled call", "id", call.ID, "attempt", call.Attempts, "error", err)
+		call.LastError = err.Error()
+		s.retry(call, CallStatusFailed)
+	} else {
+		call.CallUUID = resp.UUID
+		call.LastError = ""
+		call.NextAttempt = s.leaseEnd()
+		s.logger.Info("Placed scheduled call", "id", call.ID, "callUUID", resp.UUID, "attempt", call.Attempts)
+		if event, ok := s.early[resp.UUID]; ok {
+			delete(s.early, resp.UUID)
+			s.applyEvent(call, &event)
+		}
+	}
+	if s.placing == 0 {
+		// The remaining events belong to calls the scheduler did not place
+		clear(s.early)
+	}
+
+	if err := s.store.Save(ctx, call); err != nil {
+		return fmt.Errorf("voice: failed to save scheduled call: %w", err)
+	}
+	return nil
+}
+
+// HandleEvent applies a call event to the scheduled call it belongs to,
+// retrying or completing it on terminal statuses. Events of calls the
+// scheduler did not place are ignored.
+func (s *Scheduler) HandleEvent(ctx context.Context, event *CallEvent) error {
+	if !event.IsTerminal() && event.Status != CallStatusAnswered {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	call, err := s.store.GetByCallUUID(ctx, event.UUID)
+	if errors.Is(err, ErrScheduledCallNotFound) {
+		if s.placing > 0 {
+			// The event may belong to a call whose placement is not
+			// recorded yet
+			s.early[event.UUID] = *event
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if call.Status != ScheduleStatusPlaced {
+		return nil
+	}
+
+	s.applyEvent(call, event)
+	return s.store.Save(ctx, call)
+}
+
+// applyEvent completes or retries a placed call on its outcome
+func (s *Scheduler) applyEvent(call *ScheduledCall, event *CallEvent) {
+	outcome := event.Status
+	call.LastOutcome = outcome
+	switch outcome {
+	case CallStatusAnswered, CallStatusCompleted:
+		call.Status = ScheduleStatusCompleted
+	default:
+		s.retry(call, outcome)
+	}
+}
+
+// retry reschedules call after an unsuccessful attempt, or fails it
+func (s *Scheduler) retry(call *ScheduledCall, outcome CallStatus) {
+	policy := s.policy
+	if call.Retry != nil {
+		policy = call.Retry
+	}
+
+	rule, ok := policy[outcome]
+	if !ok || call.Attempts >= rule.MaxAttempts {
+		call.Status = ScheduleStatusFailed
+		s.logger.Info("Scheduled call gave up", "id", call.ID, "outcome", outcome, "attempts", call.Attempts)
+		return
+	}
+
+	next, err := s.allowed(call, s.now().Add(rule.Delay))
+	if err != nil {
+		call.Status = ScheduleStatusFailed
+		call.LastError = err.Error()
+		return
+	}
+	call.Status = ScheduleStatusPending
+	call.NextAttempt = next
+}
+
+// leaseEnd returns when the lease of a call placed now expires, or the
+// zero time without a lease
+func (s *Scheduler) leaseEnd() time.Time {
+	if s.lease <= 0 {
+		return time.Time{}
+	}
+	return s.now().Add(s.lease)
+}
+
+// allowed returns the first time at or after t outside quiet hours
+func (s *Scheduler) allowed(call *ScheduledCall, t time.Time) (time.Time, error) {
+	if s.quiet == nil {
+		return t, nil
+	}
+	loc := s.location
+	if call.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(call.TimeZone); err != nil {
+			return time.Time{}, fmt.Errorf("voice: invalid time zone %q: %w", call.TimeZone, err)
+		}
+	}
+	return s.quiet.NextAllowed(t.In(loc)), nil
+}
@@ -0,0 +1,194 @@
+package voice
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeCallAPI places calls with a function; other API methods panic
+type fakeCallAPI struct {
+	API
+	calls  atomic.Int32
+	create func(ctx context.Context, opts CreateCallOptions) (*CreateCallResponse, error)
+}
+
+func (f *fakeCallAPI) CreateCall(ctx context.Context, opts CreateCallOptions) (*CreateCallResponse, error) {
+	f.calls.Add(1)
+	if f.create != nil {
+		return f.create(ctx, opts)
+	}
+	return &CreateCallResponse{UUID: "CALL-1"}, nil
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestQuietHoursNextAllowed(t *testing.T) {
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	newYork := mustLoadLocation(t, "America/New_York")
+	night, _ := NewQuietHours("21:00", "09:00")
+	lunch, _ := NewQuietHours("12:00", "13:00")
+
+	tests := []struct {
+		name  string
+		quiet QuietHours
+		t     time.Time
+		want  time.Time
+	}{
+		{"outside", night, time.Date(2024, 4, 1, 10, 0, 0, 0, tokyo), time.Date(2024, 4, 1, 10, 0, 0, 0, tokyo)},
+		{"before midnight", night, time.Date(2024, 4, 1, 22, 0, 0, 0, tokyo), time.Date(2024, 4, 2, 9, 0, 0, 0, tokyo)},
+		{"after midnight", night, time.Date(2024, 4, 1, 3, 0, 0, 0, tokyo), time.Date(2024, 4, 1, 9, 0, 0, 0, tokyo)},
+		{"end is allowed", night, time.Date(2024, 4, 1, 9, 0, 0, 0, tokyo), time.Date(2024, 4, 1, 9, 0, 0, 0, tokyo)},
+		{"daytime window", lunch, time.Date(2024, 4, 1, 12, 30, 0, 0, tokyo), time.Date(2024, 4, 1, 13, 0, 0, 0, tokyo)},
+		// Clocks go forward at 02:00 on 10 March and back on 3 November
+		{"spring forward", night, time.Date(2024, 3, 10, 7, 0, 0, 0, newYork), time.Date(2024, 3, 10, 9, 0, 0, 0, newYork)},
+		{"spring forward after end", night, time.Date(2024, 3, 10, 9, 30, 0, 0, newYork), time.Date(2024, 3, 10, 9, 30, 0, 0, newYork)},
+		{"spring forward overnight", night, time.Date(2024, 3, 9, 23, 0, 0, 0, newYork), time.Date(2024, 3, 10, 9, 0, 0, 0, newYork)},
+		{"fall back", night, time.Date(2024, 11, 3, 8, 30, 0, 0, newYork), time.Date(2024, 11, 3, 9, 0, 0, 0, newYork)},
+		{"fall back before end", night, time.Date(2024, 11, 3, 8, 0, 0, 0, newYork), time.Date(2024, 11, 3, 9, 0, 0, 0, newYork)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quiet.NextAllowed(tt.t); !got.Equal(tt.want) {
+				t.Errorf("NextAllowed(%s) = %s, want %s", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaceDueInvalidTimeZone(t *testing.T) {
+	now := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	api := &fakeCallAPI{}
+	store := NewMemoryScheduleStore()
+	s := NewScheduler(api, WithScheduleStore(store), WithQuietHours(QuietHours{Start: 21 * time.Hour, End: 9 * time.Hour}))
+	s.now = func() time.Time { return now }
+
+	// Saved directly, e.g. by an older version that did not check zones
+	store.Save(context.Background(), &ScheduledCall{
+		ID:          "S-1",
+		TimeZone:    "Mars/Olympus_Mons",
+		Status:      ScheduleStatusPending,
+		NextAttempt: now,
+	})
+
+	placed, err := s.PlaceDue(context.Background())
+	if err != nil || placed != 0 {
+		t.Fatalf("PlaceDue() = %d, %v, want 0 placed", placed, err)
+	}
+	if n := api.calls.Load(); n != 0 {
+		t.Errorf("placed %d calls, want none", n)
+	}
+	if call, _ := s.Get(context.Background(), "S-1"); call.Status != ScheduleStatusFailed || call.LastError == "" {
+		t.Errorf("call = %s (%q), want failed with the error", call.Status, call.LastError)
+	}
+}
+
+func TestPlaceDueDoesNotHoldLock(t *testing.T) {
+	ctx := context.Background()
+	api := &fakeCallAPI{}
+	s := NewScheduler(api)
+
+	var wg sync.WaitGroup
+	api.create = func(ctx context.Context, opts CreateCallOptions) (*CreateCallResponse, error) {
+		// The call fails before PlaceDue records it was placed
+		wg.Add(1)
+		done := make(chan struct{})
+		go func() {
+			defer wg.Done()
+			if err := s.HandleEvent(ctx, &CallEvent{UUID: "CALL-1", Status: CallStatusBusy}); err != nil {
+				t.Errorf("HandleEvent() = %v", err)
+			}
+			if err := s.Cancel(ctx, "S-1"); err == nil {
+				t.Error("Cancel() of a call being placed succeeded")
+			}
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("HandleEvent blocked while the call was being placed")
+		}
+		return &CreateCallResponse{UUID: "CALL-1"}, nil
+	}
+
+	if _, err := s.Schedule(ctx, ScheduledCall{ID: "S-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if placed, err := s.PlaceDue(ctx); err != nil || placed != 1 {
+		t.Fatalf("PlaceDue() = %d, %v, want 1 placed", placed, err)
+	}
+	wg.Wait()
+
+	// The early busy event was applied once the placement was recorded
+	call, _ := s.Get(ctx, "S-1")
+	if call.LastOutcome != CallStatusBusy || call.Status != ScheduleStatusPending || call.Attempts != 1 {
+		t.Errorf("call = %s after %d attempts (outcome %q), want a pending retry of a busy call",
+			call.Status, call.Attempts, call.LastOutcome)
+	}
+	if len(s.early) != 0 {
+		t.Errorf("kept %d early events after placing", len(s.early))
+	}
+}
+
+func TestPlaceDueReclaimsExpiredLease(t *testing.T) {
+	retryFailed := RetryPolicy{CallStatusFailed: {Delay: time.Minute, MaxAttempts: 2}}
+
+	tests := []struct {
+		name       string
+		opts       []SchedulerOption
+		wait       time.Duration
+		wantStatus ScheduleStatus
+		wantCalls  int32
+	}{
+		{"within the lease", []SchedulerOption{WithRetryPolicy(retryFailed)}, DefaultPlacementLease - 5*time.Minute, ScheduleStatusPlaced, 1},
+		{"expired and retried", []SchedulerOption{WithRetryPolicy(retryFailed)}, DefaultPlacementLease, ScheduleStatusPlaced, 2},
+		{"expired without a retry rule", nil, DefaultPlacementLease, ScheduleStatusFailed, 1},
+		{"custom lease", []SchedulerOption{WithRetryPolicy(retryFailed), WithPlacementLease(10 * time.Minute)}, 10 * time.Minute, ScheduleStatusPlaced, 2},
+		{"without a lease", []SchedulerOption{WithRetryPolicy(retryFailed), WithPlacementLease(0)}, 365 * 24 * time.Hour, ScheduleStatusPlaced, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+			api := &fakeCallAPI{}
+			store := NewMemoryScheduleStore()
+			s := NewScheduler(api, append(tt.opts, WithScheduleStore(store))...)
+			s.now = func() time.Time { return now }
+
+			if _, err := s.Schedule(ctx, ScheduledCall{ID: "S-1"}); err != nil {
+				t.Fatal(err)
+			}
+			if placed, err := s.PlaceDue(ctx); err != nil || placed != 1 {
+				t.Fatalf("PlaceDue() = %d, %v, want 1 placed", placed, err)
+			}
+
+			// The process placing the call stopped, so no event is applied.
+			// A new scheduler on the same store reclaims the call.
+			s = NewScheduler(api, append(tt.opts, WithScheduleStore(store))...)
+			now = now.Add(tt.wait)
+			s.now = func() time.Time { return now }
+			for i := 0; i < 2; i++ {
+				if _, err := s.PlaceDue(ctx); err != nil {
+					t.Fatalf("PlaceDue() = %v", err)
+				}
+				now = now.Add(time.Minute)
+			}
+
+			call, _ := s.Get(ctx, "S-1")
+			if call.Status != tt.wantStatus || api.calls.Load() != tt.wantCalls {
+				t.Errorf("call = %s after %d calls, want %s after %d", call.Status, api.calls.Load(), tt.wantStatus, tt.wantCalls)
+			}
+		})
+	}
+}
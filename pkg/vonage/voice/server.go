@@ -0,0 +1,356 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// NCCO Server
+// ========================================
+
+// DefaultNCCOPrefix is the path NCCOServer serves NCCOs under
+const DefaultNCCOPrefix = "/ncco"
+
+// Query parameters signed into answer URLs with WithNCCOURLVerification
+const (
+	// NCCOKeyParam holds the key the answer URL was issued for
+	NCCOKeyParam = "ncco_key"
+	// NCCOExpiresParam holds the Unix time the answer URL expires at
+	NCCOExpiresParam = "expires"
+)
+
+// ErrAnswerURLExpired is returned for answer URLs past the expiry set with
+// WithNCCOURLExpiry
+var ErrAnswerURLExpired = errors.New("voice: answer URL expired")
+
+// AnswerRequest is the answer webhook Vonage sends when a call is answered.
+// It arrives as query parameters (GET, the default answer_method) or as a
+// JSON body (POST).
+type AnswerRequest struct {
+	To               string `json:"to"`
+	From             string `json:"from"`
+	UUID             string `json:"uuid"`
+	ConversationUUID string `json:"conversation_uuid"`
+	RegionURL        string `json:"region_url,omitempty"`
+	// CustomData is the data given to CreateCall, or sent by a Client SDK
+	// call. Entries from the request are not verified; verified entries
+	// replace them.
+	CustomData map[string]interface{} `json:"custom_data,omitempty"`
+	// VerifiedCustomData is only the data given to CreateCall, once its
+	// signature is verified
+	VerifiedCustomData map[string]interface{} `json:"-"`
+
+	// Params holds every query parameter of the request, including ones
+	// added to the answer URL by the application
+	Params url.Values `json:"-"`
+}
+
+// NCCOFactory builds the NCCO for an answered call
+type NCCOFactory func(req AnswerRequest) NCCO
+
+// NCCOServer serves dynamic NCCOs at stable answer URLs, one per key:
+// register a factory with Register, pass AnswerURL(key) as a call's answer
+// URL and mount the server at its prefix. It is an http.Handler.
+type NCCOServer struct {
+	prefix   string
+	baseURL  string
+	resolver vonage.WebhookBaseResolver
+	verifier *vonage.WebhookVerifier
+	urls     *URLBuilder
+	urlTTL   time.Duration
+	metrics  vonage.WebhookMetricsHook
+	logger   vonage.Logger
+	now      func() time.Time
+
+	mu        sync.RWMutex
+	factories map[string]NCCOFactory
+}
+
+// NCCOServerOption is a functional option for configuring an NCCOServer
+type NCCOServerOption func(*NCCOServer)
+
+// WithNCCOPrefix sets the path NCCOs are served under (default
+// DefaultNCCOPrefix)
+func WithNCCOPrefix(prefix string) NCCOServerOption {
+	return func(s *NCCOServer) {
+		s.prefix = "/" + strings.Trim(prefix, "/")
+	}
+}
+
+// WithNCCOBaseURL sets the public base URL, such as
+// "https://game.example.com", that AnswerURL prepends
+func WithNCCOBaseURL(baseURL string) NCCOServerOption {
+	return func(s *NCCOServer) {
+		s.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithNCCOBaseResolver resolves the public base URL on every AnswerURL
+// call, e.g. from a tunnel whose URL rotates. The WithNCCOBaseURL URL is
+// used when resolving fails.
+func WithNCCOBaseResolver(r vonage.WebhookBaseResolver) NCCOServerOption {
+	return func(s *NCCOServer) {
+		s.resolver = r
+	}
+}
+
+// WithNCCOVerifier rejects answer webhooks that fail v with 401
+func WithNCCOVerifier(v *vonage.WebhookVerifier) NCCOServerOption {
+	return func(s *NCCOServer) {
+		s.verifier = v
+	}
+}
+
+// WithNCCOSignatureVerification rejects answer webhooks whose signature does
+// not match the credentials' signature secret with 401
+func WithNCCOSignatureVerification(creds *vonage.Credentials) NCCOServerOption {
+	return WithNCCOVerifier(vonage.NewWebhookVerifier(creds))
+}
+
+// WithNCCOURLVerification signs the key into every answer URL with b's
+// secret and rejects answer webhooks with 401 unless their URL parameters
+// match their signature and were signed for the requested key
+func WithNCCOURLVerification(b *URLBuilder) NCCOServerOption {
+	return func(s *NCCOServer) {
+		s.urls = b
+	}
+}
+
+// WithNCCOURLExpiry makes answer URLs signed with WithNCCOURLVerification
+// expire ttl after AnswerURL returns them, so a leaked URL cannot start
+// new calls on the NCCO indefinitely. Use a ttl longer than calls take to
+// be answered.
+func WithNCCOURLExpiry(ttl time.Duration) NCCOServerOption {
+	return func(s *NCCOServer) {
+		s.urlTTL = ttl
+	}
+}
+
+// WithNCCOMetrics reports every answer webhook served to hook, e.g. a
+// vonage.WebhookCollector, as "voice.answer" with how long the factory
+// took. Bodies that fail to parse are reported as unknown.
+func WithNCCOMetrics(hook vonage.WebhookMetricsHook) NCCOServerOption {
+	return func(s *NCCOServer) {
+		s.metrics = hook
+	}
+}
+
+// WithNCCOLogger sets the logger (default: no logging)
+func WithNCCOLogger(l vonage.Logger) NCCOServerOption {
+	return func(s *NCCOServer) {
+		s.logger = l
+	}
+}
+
+// NewNCCOServer creates an NCCO server without factories
+func NewNCCOServer(opts ...NCCOServerOption) *NCCOServer {
+	s := &NCCOServer{
+		prefix:    DefaultNCCOPrefix,
+		logger:    vonage.NopLogger(),
+		factories: make(map[string]NCCOFactory),
+		now:       time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Register serves the NCCOs built by f at AnswerURL(key), replacing any
+// factory registered for key
+func (s *NCCOServer) Register(key string, f NCCOFactory) *NCCOServer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.factories[key] = f
+	return s
+}
+
+// RegisterStatic serves a fixed NCCO at AnswerURL(key)
+func (s *NCCOServer) RegisterStatic(key string, ncco NCCO) *NCCOServer {
+	return s.Register(key, func(AnswerRequest) NCCO { return ncco })
+}
+
+// Unregister stops serving key; its answer URL then returns 404
+func (s *NCCOServer) Unregister(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.factories, key)
+}
+
+// Keys returns the registered keys in sorted order
+func (s *NCCOServer) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.factories))
+	for k := range s.factories {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Prefix returns the path to mount the server at, e.g. "/ncco/" with
+// http.ServeMux
+func (s *NCCOServer) Prefix() string {
+	return s.prefix + "/"
+}
+
+// AnswerURL returns the answer URL for key: the base URL followed by the
+// prefix and the escaped key, signed if WithNCCOURLVerification is set
+func (s *NCCOServer) AnswerURL(key string) string {
+	answerURL, _ := s.AnswerURLContext(context.Background(), key)
+	return answerURL
+}
+
+// AnswerURLContext is like AnswerURL but resolves the base URL with ctx.
+// If the WithNCCOBaseResolver resolver fails, the URL is built from the
+// WithNCCOBaseURL URL and the error is returned with it.
+func (s *NCCOServer) AnswerURLContext(ctx context.Context, key string) (string, error) {
+	base := s.baseURL
+	var err error
+	if s.resolver != nil {
+		var resolved string
+		if resolved, err = s.resolver.WebhookBase(ctx); err == nil {
+			base = strings.TrimSuffix(resolved, "/")
+		} else {
+			s.logger.Warn("Failed to resolve webhook base URL", "key", key, "error", err)
+		}
+	}
+	answerURL := base + s.prefix + "/" + url.PathEscape(key)
+	if s.urls != nil && s.urls.secret != nil {
+		// Signing the key keeps one URL's signature from opening others
+		params := url.Values{NCCOKeyParam: {key}}
+		if s.urlTTL > 0 {
+			params.Set(NCCOExpiresParam, strconv.FormatInt(s.now().Add(s.urlTTL).Unix(), 10))
+		}
+		answerURL += "?" + s.urls.Sign(params).Encode()
+	}
+	return answerURL, err
+}
+
+// verifyURL checks that the parameters of an answer URL match their
+// signature, were signed for key and have not expired
+func (s *NCCOServer) verifyURL(key string, params url.Values) error {
+	if err := s.urls.Verify(params); err != nil {
+		return err
+	}
+	if s.urls.secret == nil {
+		return nil
+	}
+	if !isSigned(params, NCCOKeyParam) || params.Get(NCCOKeyParam) != key {
+		return ErrInvalidURLSignature
+	}
+	if !isSigned(params, NCCOExpiresParam) {
+		if s.urlTTL > 0 {
+			return ErrInvalidURLSignature
+		}
+		return nil
+	}
+	expires, err := strconv.ParseInt(params.Get(NCCOExpiresParam), 10, 64)
+	if err != nil || !s.now().Before(time.Unix(expires, 0)) {
+		return ErrAnswerURLExpired
+	}
+	return nil
+}
+
+// ServeHTTP serves the NCCO for the key in the request path. Unknown keys
+// get 404 so that Vonage falls back to the application's
+// fallback_answer_url.
+func (s *NCCOServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	key, err := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), s.prefix+"/"))
+	if err != nil || key == "" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	s.mu.RLock()
+	factory, ok := s.factories[key]
+	s.mu.RUnlock()
+	if !ok {
+		s.logger.Warn("No NCCO registered for key", "key", key)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.logger.Error("Failed to read answer webhook body", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer r.Body.Close()
+
+	if s.verifier.Enabled() {
+		if err := s.verifier.Verify(r.Header.Get("Authorization"), body); err != nil {
+			s.logger.Warn("Rejected answer webhook", "key", key, "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	if s.urls != nil {
+		if err := s.verifyURL(key, r.URL.Query()); err != nil {
+			s.logger.Warn("Rejected answer webhook", "key", key, "error", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	req, err := parseAnswerRequest(r, body)
+	if err != nil {
+		s.logger.Error("Failed to parse answer webhook", "key", key, "error", err)
+		vonage.ObserveWebhook(r.Context(), s.metrics, vonage.WebhookObservation{Source: vonage.EventSourceVoice, Unknown: true})
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if s.urls != nil {
+		if data, err := s.urls.CustomDataFromRequest(r); err != nil {
+			s.logger.Warn("Ignored invalid custom data", "key", key, "error", err)
+		} else {
+			req.CustomData = mergeCustomData(req.CustomData, data)
+			req.VerifiedCustomData = data
+		}
+	}
+
+	start := time.Now()
+	ncco := factory(req)
+	if ncco == nil {
+		ncco = NCCO{}
+	}
+	data, err := ncco.JSON()
+	vonage.ObserveWebhook(r.Context(), s.metrics, vonage.WebhookObservation{
+		Source:  vonage.EventSourceVoice,
+		Type:    "voice.answer",
+		Latency: time.Since(start),
+		Err:     err,
+	})
+	if err != nil {
+		s.logger.Error("Failed to encode NCCO", "key", key, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
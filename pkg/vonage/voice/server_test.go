@@ -0,0 +1,91 @@
+package voice
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestNCCOServer(opts ...NCCOServerOption) *NCCOServer {
+	s := NewNCCOServer(append([]NCCOServerOption{
+		WithNCCOBaseURL("https://example.com"),
+		WithNCCOURLVerification(newSigningBuilder()),
+	}, opts...)...)
+	s.RegisterStatic("lobby", NewNCCO().Talk("lobby").Done().Build())
+	s.RegisterStatic("admin", NewNCCO().Talk("admin").Done().Build())
+	return s
+}
+
+// answer sends a GET answer webhook to target and returns the status code
+func answer(s *NCCOServer, target string) int {
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target+"&uuid=CALL-1", nil))
+	return rec.Code
+}
+
+func TestNCCOServerURLVerification(t *testing.T) {
+	s := newTestNCCOServer()
+	lobby := s.AnswerURL("lobby")
+	admin := s.AnswerURL("admin")
+	if lobby[strings.Index(lobby, "sig="):] == admin[strings.Index(admin, "sig="):] {
+		t.Fatalf("answer URLs of different keys share a signature: %s, %s", lobby, admin)
+	}
+
+	unsigned, _ := url.Parse(lobby)
+	unsigned.RawQuery = newSigningBuilder().Sign(nil).Encode()
+
+	tests := []struct {
+		name   string
+		target string
+		want   int
+	}{
+		{"signed", lobby, http.StatusOK},
+		{"other key signed", admin, http.StatusOK},
+		{"path key tampered", strings.Replace(lobby, "/lobby?", "/admin?", 1), http.StatusUnauthorized},
+		{"query key tampered", strings.Replace(lobby, "ncco_key=lobby", "ncco_key=admin", 1), http.StatusUnauthorized},
+		{"key not signed", unsigned.String(), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := answer(s, tt.target); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNCCOServerURLExpiry(t *testing.T) {
+	now := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	s := newTestNCCOServer(WithNCCOURLExpiry(time.Hour))
+	s.now = func() time.Time { return now }
+	lobby := s.AnswerURL("lobby")
+
+	// A URL signed without an expiry is refused once expiry is required
+	withoutExpiry := newTestNCCOServer().AnswerURL("lobby")
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		target  string
+		want    int
+	}{
+		{"fresh", 0, lobby, http.StatusOK},
+		{"before expiry", 59 * time.Minute, lobby, http.StatusOK},
+		{"expired", time.Hour, lobby, http.StatusUnauthorized},
+		{"expiry extended", 0, strings.Replace(lobby, "expires=", "expires=9", 1), http.StatusUnauthorized},
+		{"no expiry", 0, withoutExpiry, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.now = func() time.Time { return now.Add(tt.advance) }
+			if got := answer(s, tt.target); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,420 @@
+package voice
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// TTS Pre-synthesis
+// ========================================
+
+const (
+	// DefaultSynthesizeThreshold is how many cache misses make TTSCache
+	// synthesize a prompt in the background
+	DefaultSynthesizeThreshold = 3
+
+	// DefaultSynthesizeTimeout bounds a background synthesis
+	DefaultSynthesizeTimeout = 2 * time.Minute
+
+	// DefaultMaxTrackedMisses is how many uncached prompts TTSCache counts
+	// misses for
+	DefaultMaxTrackedMisses = 10000
+)
+
+// Prompt is a text-to-speech rendering: the same text in another voice,
+// language or style is a different prompt
+type Prompt struct {
+	Text      string
+	Language  string
+	VoiceName string
+	Style     int
+	Premium   bool
+}
+
+// PromptFromAction returns the prompt spoken by a talk action
+func PromptFromAction(a Action) Prompt {
+	p := Prompt{
+		Text:      a.Text,
+		Language:  a.Language,
+		VoiceName: a.VoiceName,
+		Premium:   a.Premium,
+	}
+	if a.Style != nil {
+		p.Style = *a.Style
+	}
+	return p
+}
+
+// Key returns a stable identifier for the prompt, suitable as a cache key
+// or file name
+func (p Prompt) Key() string {
+	h := sha256.New()
+	for _, field := range []string{p.Text, p.Language, p.VoiceName, strconv.Itoa(p.Style), strconv.FormatBool(p.Premium)} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Synthesizer renders a prompt to audio and returns a URL that Vonage can
+// stream from
+type Synthesizer interface {
+	Synthesize(ctx context.Context, p Prompt) (audioURL string, err error)
+}
+
+// SynthesizerFunc adapts a function, such as a call to an external TTS
+// service followed by an upload, to Synthesizer
+type SynthesizerFunc func(ctx context.Context, p Prompt) (string, error)
+
+// Synthesize implements Synthesizer
+func (f SynthesizerFunc) Synthesize(ctx context.Context, p Prompt) (string, error) {
+	return f(ctx, p)
+}
+
+// PromptCache maps prompts to pre-synthesized audio. NCCOBuilder consults
+// it while building, so Lookup must not block on synthesis.
+type PromptCache interface {
+	Lookup(p Prompt) (audioURL string, ok bool)
+}
+
+// TTSCache is the default PromptCache. Prompts are synthesized up front
+// with Warm, or in the background once they have been looked up
+// DefaultSynthesizeThreshold times, so frequently used IVR menus switch
+// to cached audio on their own. Miss counts are kept for a bounded number
+// of prompts, dropping the least recently missed, so one-off texts such as
+// names or amounts don't grow the cache without limit.
+type TTSCache struct {
+	synth     Synthesizer
+	logger    vonage.Logger
+	threshold int
+	maxMisses int
+	timeout   time.Duration
+	ttl       time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]ttsEntry
+	misses    map[string]*list.Element
+	missOrder *list.List
+	inflight  map[string]bool
+}
+
+// ttsMiss counts the misses of an uncached prompt; missOrder holds them
+// most recently missed first
+type ttsMiss struct {
+	key   string
+	count int
+}
+
+// ttsEntry is a cached audio URL
+type ttsEntry struct {
+	audioURL  string
+	expiresAt time.Time
+}
+
+// TTSCacheOption is a functional option for configuring a TTSCache
+type TTSCacheOption func(*TTSCache)
+
+// WithSynthesizeThreshold sets how many misses trigger a background
+// synthesis (default DefaultSynthesizeThreshold). 0 disables background
+// synthesis; only Warm and Put fill the cache.
+func WithSynthesizeThreshold(n int) TTSCacheOption {
+	return func(c *TTSCache) {
+		c.threshold = n
+	}
+}
+
+// WithMaxTrackedMisses sets how many uncached prompts misses are counted
+// for (default DefaultMaxTrackedMisses). A prompt dropped from tracking
+// starts counting from zero when it is missed again.
+func WithMaxTrackedMisses(n int) TTSCacheOption {
+	return func(c *TTSCache) {
+		c.maxMisses = n
+	}
+}
+
+// WithSynthesizeTimeout bounds each background synthesis (default
+// DefaultSynthesizeTimeout)
+func WithSynthesizeTimeout(d time.Duration) TTSCacheOption {
+	return func(c *TTSCache) {
+		c.timeout = d
+	}
+}
+
+// WithAudioTTL expires cached URLs after d, for audio hosted at URLs that
+// stop working (default: never)
+func WithAudioTTL(d time.Duration) TTSCacheOption {
+	return func(c *TTSCache) {
+		c.ttl = d
+	}
+}
+
+// WithTTSCacheLogger sets the logger (default: no logging)
+func WithTTSCacheLogger(l vonage.Logger) TTSCacheOption {
+	return func(c *TTSCache) {
+		c.logger = l
+	}
+}
+
+// NewTTSCache creates a prompt cache that synthesizes with s
+func NewTTSCache(s Synthesizer, opts ...TTSCacheOption) *TTSCache {
+	c := &TTSCache{
+		synth:     s,
+		logger:    vonage.NopLogger(),
+		threshold: DefaultSynthesizeThreshold,
+		maxMisses: DefaultMaxTrackedMisses,
+		timeout:   DefaultSynthesizeTimeout,
+		entries:   make(map[string]ttsEntry),
+		misses:    make(map[string]*list.Element),
+		missOrder: list.New(),
+		inflight:  make(map[string]bool),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxMisses < 1 {
+		c.maxMisses = 1
+	}
+
+	return c
+}
+
+// Lookup implements PromptCache. A miss may start a background synthesis.
+func (c *TTSCache) Lookup(p Prompt) (string, bool) {
+	key := p.Key()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		if e.expiresAt.IsZero() || time.Now().Before(e.expiresAt) {
+			return e.audioURL, true
+		}
+		delete(c.entries, key)
+	}
+
+	if c.threshold <= 0 || c.inflight[key] {
+		return "", false
+	}
+	if c.countMiss(key) >= c.threshold {
+		// The prompt is promoted; a failed synthesis starts counting again
+		c.forgetMisses(key)
+		c.inflight[key] = true
+		go c.synthesizeInBackground(key, p)
+	}
+	return "", false
+}
+
+// countMiss records a miss for key and returns its count, dropping the
+// least recently missed prompt when maxMisses are tracked. c.mu must be
+// held.
+func (c *TTSCache) countMiss(key string) int {
+	if el, ok := c.misses[key]; ok {
+		c.missOrder.MoveToFront(el)
+		m := el.Value.(*ttsMiss)
+		m.count++
+		return m.count
+	}
+	if c.missOrder.Len() >= c.maxMisses {
+		oldest := c.missOrder.Back()
+		c.missOrder.Remove(oldest)
+		delete(c.misses, oldest.Value.(*ttsMiss).key)
+	}
+	c.misses[key] = c.missOrder.PushFront(&ttsMiss{key: key, count: 1})
+	return 1
+}
+
+// forgetMisses stops counting misses for key. c.mu must be held.
+func (c *TTSCache) forgetMisses(key string) {
+	if el, ok := c.misses[key]; ok {
+		c.missOrder.Remove(el)
+		delete(c.misses, key)
+	}
+}
+
+// Warm synthesizes the prompts that are not cached yet, e.g. every IVR
+// menu at startup. It returns the joined errors of failed prompts.
+func (c *TTSCache) Warm(ctx context.Context, prompts ...Prompt) error {
+	var errs []error
+	for _, p := range prompts {
+		if _, ok := c.peek(p.Key()); ok {
+			continue
+		}
+		audioURL, err := c.synth.Synthesize(ctx, p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("synthesize %q: %w", p.Text, err))
+			continue
+		}
+		c.Put(p, audioURL)
+	}
+	return errors.Join(errs...)
+}
+
+// Put caches audioURL for p, e.g. for audio produced by a build step
+func (c *TTSCache) Put(p Prompt, audioURL string) {
+	e := ttsEntry{audioURL: audioURL}
+	if c.ttl > 0 {
+		e.expiresAt = time.Now().Add(c.ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[p.Key()] = e
+	c.forgetMisses(p.Key())
+}
+
+// Invalidate removes p from the cache, e.g. after rewording a prompt's
+// audio
+func (c *TTSCache) Invalidate(p Prompt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, p.Key())
+}
+
+// Len returns the number of cached prompts
+func (c *TTSCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// peek returns a live cache entry without counting a miss
+func (c *TTSCache) peek(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || (!e.expiresAt.IsZero() && time.Now().After(e.expiresAt)) {
+		return "", false
+	}
+	return e.audioURL, true
+}
+
+// synthesizeInBackground fills the cache for a frequently missed prompt
+func (c *TTSCache) synthesizeInBackground(key string, p Prompt) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	audioURL, err := c.synth.Synthesize(ctx, p)
+	if err != nil {
+		c.logger.Warn("Failed to pre-synthesize TTS prompt", "prompt", key, "error", err)
+		return
+	}
+	c.Put(p, audioURL)
+	c.logger.Info("Pre-synthesized TTS prompt", "prompt", key)
+}
+
+// ========================================
+// Recording Synthesizer
+// ========================================
+
+// AudioStore hosts synthesized audio and returns a URL Vonage can stream
+// from. Recording URLs require a JWT, so they cannot be streamed directly.
+type AudioStore func(ctx context.Context, p Prompt, audio io.Reader) (audioURL string, err error)
+
+// RecordingSynthesizer synthesizes prompts with Vonage's own TTS: it
+// places a call whose NCCO records the call and speaks the prompt, then
+// downloads the recording and hands it to an AudioStore. The recorded
+// audio matches the voice callers would otherwise hear from a Talk action.
+//
+// Route the record webhook (recordEventURL) to HandleRecording.
+type RecordingSynthesizer struct {
+	client         *Client
+	to             Endpoint
+	recordEventURL string
+	store          AudioStore
+
+	mu      sync.Mutex
+	pending map[string]chan string
+}
+
+// NewRecordingSynthesizer creates a synthesizer that calls to, an endpoint
+// that answers and stays silent (such as a SIP sink), and records the
+// prompt. recordEventURL must deliver record events to HandleRecording.
+func NewRecordingSynthesizer(client *Client, to Endpoint, recordEventURL string, store AudioStore) *RecordingSynthesizer {
+	return &RecordingSynthesizer{
+		client:         client,
+		to:             to,
+		recordEventURL: recordEventURL,
+		store:          store,
+		pending:        make(map[string]chan string),
+	}
+}
+
+// Synthesize implements Synthesizer. It blocks until the recording is
+// stored or ctx is done.
+func (s *RecordingSynthesizer) Synthesize(ctx context.Context, p Prompt) (string, error) {
+	talk := NewNCCO().
+		Record().Format("mp3").EventURL(s.recordEventURL).Done().
+		Talk(p.Text).VoiceName(p.VoiceName).Language(p.Language).Style(p.Style)
+	if p.Premium {
+		talk.Premium()
+	}
+	ncco := talk.Done().Build()
+
+	call, err := s.client.CreateCall(ctx, CreateCallOptions{To: s.to, InlineNCCO: ncco})
+	if err != nil {
+		return "", fmt.Errorf("failed to start synthesis call: %w", err)
+	}
+
+	ready := make(chan string, 1)
+	s.mu.Lock()
+	s.pending[call.ConversationUUID] = ready
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, call.ConversationUUID)
+		s.mu.Unlock()
+	}()
+
+	var recordingURL string
+	select {
+	case recordingURL = <-ready:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := s.client.DownloadRecording(ctx, recordingURL, pw)
+		pw.CloseWithError(err)
+	}()
+	defer pr.Close()
+
+	return s.store(ctx, p, pr)
+}
+
+// HandleRecording delivers a record event to the pending synthesis of its
+// conversation. It returns false if the event belongs to no pending
+// synthesis. The recording is only available after the prompt has been
+// spoken, so the event cannot arrive before Synthesize starts waiting.
+func (s *RecordingSynthesizer) HandleRecording(event *RecordingEvent) bool {
+	s.mu.Lock()
+	ready, ok := s.pending[event.ConversationUUID]
+	s.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+	select {
+	case ready <- event.RecordingURL:
+	default:
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingSynth synthesizes every prompt to a URL derived from its text
+type countingSynth struct {
+	calls atomic.Int32
+}
+
+func (s *countingSynth) Synthesize(ctx context.Context, p Prompt) (string, error) {
+	s.calls.Add(1)
+	return "https://audio.example.com/" + p.Text + ".mp3", nil
+}
+
+// trackedMisses returns the miss count of every tracked prompt by text
+func trackedMisses(c *TTSCache, prompts ...Prompt) map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.misses) != c.missOrder.Len() {
+		panic("miss index and order disagree")
+	}
+	counts := map[string]int{}
+	for _, p := range prompts {
+		if el, ok := c.misses[p.Key()]; ok {
+			counts[p.Text] = el.Value.(*ttsMiss).count
+		}
+	}
+	return counts
+}
+
+func TestTTSCachePromotion(t *testing.T) {
+	synth := &countingSynth{}
+	c := NewTTSCache(synth, WithSynthesizeThreshold(3))
+	menu := Prompt{Text: "menu"}
+
+	for i := 0; i < 3; i++ {
+		if _, ok := c.Lookup(menu); ok {
+			t.Fatalf("lookup %d hit before synthesis", i+1)
+		}
+	}
+	// The counter is dropped as soon as the prompt is promoted
+	if got := trackedMisses(c, menu); len(got) != 0 {
+		t.Errorf("misses after promotion = %v, want none", got)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for c.Len() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if url, ok := c.Lookup(menu); !ok || url != "https://audio.example.com/menu.mp3" {
+		t.Fatalf("Lookup() = %q, %v, want the synthesized audio", url, ok)
+	}
+	if n := synth.calls.Load(); n != 1 {
+		t.Errorf("synthesized %d times, want 1", n)
+	}
+	if got := trackedMisses(c, menu); len(got) != 0 {
+		t.Errorf("misses after caching = %v, want none", got)
+	}
+}
+
+func TestTTSCacheMissTracking(t *testing.T) {
+	a, b, c3 := Prompt{Text: "a"}, Prompt{Text: "b"}, Prompt{Text: "c"}
+
+	tests := []struct {
+		name    string
+		max     int
+		lookups []Prompt
+		want    map[string]int
+	}{
+		{"counts repeated misses", 10, []Prompt{a, b, a}, map[string]int{"a": 2, "b": 1}},
+		{"drops least recently missed", 2, []Prompt{a, b, a, c3}, map[string]int{"a": 2, "c": 1}},
+		{"dropped prompt starts over", 2, []Prompt{a, a, b, c3, a}, map[string]int{"c": 1, "a": 1}},
+		{"at least one prompt", 0, []Prompt{a, b}, map[string]int{"b": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewTTSCache(&countingSynth{}, WithSynthesizeThreshold(100), WithMaxTrackedMisses(tt.max))
+			for _, p := range tt.lookups {
+				c.Lookup(p)
+			}
+			if got := trackedMisses(c, a, b, c3); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("misses = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTTSCacheMissTrackingBounded(t *testing.T) {
+	c := NewTTSCache(&countingSynth{}, WithSynthesizeThreshold(3), WithMaxTrackedMisses(100))
+	// One-off prompts such as caller names never reach the threshold
+	for i := 0; i < 10000; i++ {
+		c.Lookup(Prompt{Text: fmt.Sprintf("Hello caller %d", i)})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.misses) != 100 || c.missOrder.Len() != 100 {
+		t.Errorf("tracking %d misses (%d ordered), want 100", len(c.misses), c.missOrder.Len())
+	}
+}
@@ -21,6 +21,29 @@ const (
 	CallStatusTimeout   CallStatus = "timeout"
 )
 
+// Statuses only sent in call events
+const (
+	CallStatusUnanswered   CallStatus = "unanswered"
+	CallStatusDisconnected CallStatus = "disconnected"
+	CallStatusHuman        CallStatus = "human"
+	CallStatusMachine      CallStatus = "machine"
+)
+
+// IsTerminal returns true if the call has ended
+func (s CallStatus) IsTerminal() bool {
+	switch s {
+	case CallStatusCompleted, CallStatusFailed, CallStatusRejected, CallStatusBusy,
+		CallStatusCancelled, CallStatusTimeout, CallStatusUnanswered:
+		return true
+	}
+	return false
+}
+
+// IsFailure returns true if the call ended without being answered
+func (s CallStatus) IsFailure() bool {
+	return s.IsTerminal() && s != CallStatusCompleted
+}
+
 // CallDirection represents the direction of a call
 type CallDirection string
 
@@ -62,6 +85,14 @@ func SIPEndpoint(uri string) Endpoint {
 	}
 }
 
+// Destination returns the endpoint's number, or its URI if it has none
+func (e Endpoint) Destination() string {
+	if e.Number != "" {
+		return e.Number
+	}
+	return e.URI
+}
+
 // ========================================
 // Create Call
 // ========================================
@@ -72,9 +103,9 @@ type CreateCallRequest struct {
 	From         Endpoint   `json:"from"`
 	NCCO         NCCO       `json:"ncco,omitempty"`
 	AnswerURL    []string   `json:"answer_url,omitempty"`
-	AnswerMethod string     `json:"answer_method,omitempty"`
+	AnswerMethod HTTPMethod `json:"answer_method,omitempty"`
 	EventURL     []string   `json:"event_url,omitempty"`
-	EventMethod  string     `json:"event_method,omitempty"`
+	EventMethod  HTTPMethod `json:"event_method,omitempty"`
 }
 
 // CreateCallResponse represents the response from creating a call
@@ -94,27 +125,33 @@ type CreateCallOptions struct {
 	// AnswerURL is the URL for Vonage to request NCCO from
 	AnswerURL string
 	// AnswerMethod is the HTTP method for the answer URL (default: POST)
-	AnswerMethod string
+	AnswerMethod HTTPMethod
 	// EventURL is the URL for Vonage to send call events to
 	EventURL string
 	// EventMethod is the HTTP method for the event URL (default: POST)
-	EventMethod string
+	EventMethod HTTPMethod
 	// InlineNCCO is an NCCO to use instead of an answer URL
 	InlineNCCO NCCO
+	// CustomData is passed back in the call's answer and event webhooks,
+	// e.g. internal IDs to correlate them with. It travels as JSON in the
+	// URLs (see CustomDataParam), signed by the client's WithURLSigner
+	// builder, so keep it small and read it with the same builder.
+	CustomData map[string]interface{}
 }
 
 // ========================================
 // Call Info
 // ========================================
 
-// CallInfo represents information about a call
+// CallInfo represents information about a call. Its JSON encoding is the
+// API's, with Duration in seconds.
 type CallInfo struct {
 	UUID             string        `json:"uuid"`
 	Status           CallStatus    `json:"status"`
 	Direction        CallDirection `json:"direction"`
-	Rate             string        `json:"rate"`
-	Price            string        `json:"price"`
-	Duration         string        `json:"duration"`
+	Rate             Price         `json:"rate"`
+	Price            Price         `json:"price"`
+	Duration         time.Duration `json:"duration"`
 	StartTime        time.Time     `json:"start_time"`
 	EndTime          time.Time     `json:"end_time"`
 	ConversationUUID string        `json:"conversation_uuid"`
@@ -123,6 +160,58 @@ type CallInfo struct {
 	From             Endpoint      `json:"from,omitempty"`
 }
 
+// ListCallsOptions filters and pages the call list
+type ListCallsOptions struct {
+	Status    CallStatus
+	DateStart time.Time
+	DateEnd   time.Time
+	// PageSize is the number of calls per page (max 100)
+	PageSize int
+	// RecordIndex is the offset of the first call to return
+	RecordIndex int
+	// Order is "asc" or "desc" by start time
+	Order            string
+	ConversationUUID string
+}
+
+// CallList is one page of calls
+type CallList struct {
+	Count       int
+	PageSize    int
+	RecordIndex int
+	Calls       []CallInfo
+}
+
+// HasNext returns true if there are more calls after this page
+func (l *CallList) HasNext() bool {
+	return len(l.Calls) > 0 && l.RecordIndex+len(l.Calls) < l.Count
+}
+
+// listCallsResponse is the HAL body of the call list endpoint
+type listCallsResponse struct {
+	Count       int `json:"count"`
+	PageSize    int `json:"page_size"`
+	RecordIndex int `json:"record_index"`
+	Embedded    struct {
+		Calls []CallInfo `json:"calls"`
+	} `json:"_embedded"`
+}
+
+// ConversationLegs are the legs of a conversation with their totals, e.g.
+// for post-call analytics of a conference
+type ConversationLegs struct {
+	ConversationUUID string
+	// Legs are ordered by start time
+	Legs []CallInfo
+	// Duration is the sum of the legs' durations
+	Duration time.Duration
+	// Price is the sum of the legs' prices, in the account currency
+	Price Price
+	// StartTime is the first leg's start and EndTime the last leg's end
+	StartTime time.Time
+	EndTime   time.Time
+}
+
 // ========================================
 // Transfer Call
 // ========================================
@@ -139,33 +228,155 @@ type TransferDestination struct {
 	URL  []string `json:"url"`
 }
 
+// ========================================
+// Talk and Stream into Call
+// ========================================
+
+// TalkIntoCallOptions contains options for speaking into an active call
+type TalkIntoCallOptions struct {
+	Text      string
+	VoiceName string
+	// Loop defaults to playing once; use LoopInfinite to repeat until
+	// StopTalk or the end of the call
+	Loop Loop
+	// Level is the volume, from -1 to 1 (default 0)
+	Level float64
+}
+
+// StreamIntoCallOptions contains options for streaming audio into an
+// active call
+type StreamIntoCallOptions struct {
+	// StreamURL is the URL of an MP3 or WAV file
+	StreamURL string
+	// Loop defaults to playing once; use LoopInfinite to repeat until
+	// StopStream or the end of the call
+	Loop Loop
+	// Level is the volume, from -1 to 1 (default 0), e.g. -0.6 for quiet
+	// hold music under louder prompts
+	Level float64
+}
+
+// talkIntoCallRequest is the body of PUT /calls/{uuid}/talk
+type talkIntoCallRequest struct {
+	Text      string  `json:"text"`
+	VoiceName string  `json:"voice_name,omitempty"`
+	Loop      *int    `json:"loop,omitempty"`
+	Level     float64 `json:"level,omitempty"`
+}
+
+// streamIntoCallRequest is the body of PUT /calls/{uuid}/stream
+type streamIntoCallRequest struct {
+	StreamURL []string `json:"stream_url"`
+	Loop      *int     `json:"loop,omitempty"`
+	Level     float64  `json:"level,omitempty"`
+}
+
 // ========================================
 // Call Event Webhook
 // ========================================
 
 // CallEvent represents a Vonage call event webhook payload
 type CallEvent struct {
-	UUID             string `json:"uuid"`
-	ConversationUUID string `json:"conversation_uuid"`
-	Status           string `json:"status"`
-	Direction        string `json:"direction"`
-	Timestamp        string `json:"timestamp"`
-	From             string `json:"from,omitempty"`
-	To               string `json:"to,omitempty"`
-	Duration         string `json:"duration,omitempty"`
-	Rate             string `json:"rate,omitempty"`
-	Price            string `json:"price,omitempty"`
+	UUID             string     `json:"uuid"`
+	ConversationUUID string     `json:"conversation_uuid"`
+	Status           CallStatus `json:"status"`
+	Direction        string     `json:"direction"`
+	Timestamp        string     `json:"timestamp"`
+	From             string     `json:"from,omitempty"`
+	To               string     `json:"to,omitempty"`
+	Duration         string     `json:"duration,omitempty"`
+	Rate             string     `json:"rate,omitempty"`
+	Price            string     `json:"price,omitempty"`
+	// Detail is why a call failed, set on some failed, rejected,
+	// unanswered and timeout events
+	Detail CallStatusDetail `json:"detail,omitempty"`
+	// DisconnectedBy is which side hung up, set on completed events
+	DisconnectedBy DisconnectedBy `json:"disconnected_by,omitempty"`
+	// CustomData is the data given to CreateCall, or sent by a Client SDK
+	// call. ParseCallEvent fills the latter, URLBuilder.ParseCallEvent
+	// both. Entries from the body are not verified; verified entries
+	// replace them.
+	CustomData map[string]interface{} `json:"custom_data,omitempty"`
+	// VerifiedCustomData is only the data given to CreateCall, set by
+	// URLBuilder.ParseCallEvent once its signature is verified. Use it
+	// rather than CustomData to correlate events with your own records.
+	VerifiedCustomData map[string]interface{} `json:"-"`
 }
 
 // IsTerminal returns true if the call event represents a terminal state
 func (e *CallEvent) IsTerminal() bool {
-	switch e.Status {
-	case "completed", "failed", "rejected", "busy", "cancelled", "timeout":
+	return e.Status.IsTerminal()
+}
+
+// IsFailure returns true if the call event reports a call that ended
+// without being answered
+func (e *CallEvent) IsFailure() bool {
+	return e.Status.IsFailure()
+}
+
+// CallStatusDetail is the reason a call event gives for a failed call
+type CallStatusDetail string
+
+const (
+	CallDetailBlocked            CallStatusDetail = "blocked"
+	CallDetailCannotRoute        CallStatusDetail = "cannot_route"
+	CallDetailCarrierTimeout     CallStatusDetail = "carrier_timeout"
+	CallDetailDeclined           CallStatusDetail = "declined"
+	CallDetailInternalError      CallStatusDetail = "internal_error"
+	CallDetailInvalidNumber      CallStatusDetail = "invalid_number"
+	CallDetailMediaTimeout       CallStatusDetail = "media_timeout"
+	CallDetailNumberOutOfService CallStatusDetail = "number_out_of_service"
+	CallDetailRestricted         CallStatusDetail = "restricted"
+	CallDetailRingTimeout        CallStatusDetail = "ring_timeout"
+	CallDetailUnallocatedNumber  CallStatusDetail = "unallocated_number"
+	CallDetailUnavailable        CallStatusDetail = "unavailable"
+)
+
+// IsPermanent returns true if calling the number again will fail the same
+// way, e.g. because it is not allocated or is barred, so it should be
+// removed from call lists rather than retried
+func (d CallStatusDetail) IsPermanent() bool {
+	switch d {
+	case CallDetailBlocked, CallDetailInvalidNumber, CallDetailNumberOutOfService,
+		CallDetailRestricted, CallDetailUnallocatedNumber:
+		return true
+	}
+	return false
+}
+
+// IsCarrierFailure returns true if the call failed in the network rather
+// than at the callee, such as a carrier timeout or routing failure
+func (d CallStatusDetail) IsCarrierFailure() bool {
+	switch d {
+	case CallDetailCannotRoute, CallDetailCarrierTimeout, CallDetailInternalError, CallDetailMediaTimeout:
 		return true
 	}
 	return false
 }
 
+// DisconnectedBy is the side that ended a completed call
+type DisconnectedBy string
+
+const (
+	// DisconnectedByUser means the callee or caller hung up
+	DisconnectedByUser DisconnectedBy = "user"
+	// DisconnectedByPlatform means the call was ended by Vonage, e.g. by
+	// an NCCO finishing or a hangup through the API
+	DisconnectedByPlatform DisconnectedBy = "platform"
+)
+
+// RecordingEvent is the webhook payload sent to a record action's
+// eventUrl when a recording is available
+type RecordingEvent struct {
+	RecordingURL     string    `json:"recording_url"`
+	RecordingUUID    string    `json:"recording_uuid"`
+	ConversationUUID string    `json:"conversation_uuid"`
+	StartTime        time.Time `json:"start_time"`
+	EndTime          time.Time `json:"end_time"`
+	Size             int64     `json:"size"`
+	Timestamp        string    `json:"timestamp"`
+}
+
 // ========================================
 // ASR (Automatic Speech Recognition)
 // ========================================
@@ -0,0 +1,258 @@
+package voice
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ========================================
+// Webhook URLs
+// ========================================
+
+// Default URLBuilder paths
+const (
+	DefaultAnswerPath = "/webhooks/answer"
+	DefaultEventPath  = "/webhooks/event"
+	DefaultInputPath  = "/webhooks/input"
+)
+
+// Query parameters added by a signing URLBuilder
+const (
+	// SignatureParam holds the hex HMAC-SHA256 of the signed parameters
+	SignatureParam = "sig"
+	// SignedParamsParam lists the signed parameter names, comma-separated
+	SignedParamsParam = "sig_params"
+)
+
+// ErrInvalidURLSignature is returned when the parameters of a webhook URL
+// are unsigned or do not match their signature
+var ErrInvalidURLSignature = errors.New("voice: invalid webhook URL signature")
+
+// URLBuilder builds the answer, event and input webhook URLs of an
+// application from one base URL, escaping paths and parameters. With a
+// signing secret it also signs the parameters, so webhooks carrying
+// altered ones, such as a different user ID, can be rejected.
+type URLBuilder struct {
+	base       string
+	answerPath string
+	eventPath  string
+	inputPath  string
+	secret     []byte
+}
+
+// URLBuilderOption is a functional option for configuring a URLBuilder
+type URLBuilderOption func(*URLBuilder)
+
+// WithAnswerPath sets the answer webhook path (default DefaultAnswerPath)
+func WithAnswerPath(path string) URLBuilderOption {
+	return func(b *URLBuilder) {
+		b.answerPath = cleanPath(path)
+	}
+}
+
+// WithEventPath sets the event webhook path (default DefaultEventPath)
+func WithEventPath(path string) URLBuilderOption {
+	return func(b *URLBuilder) {
+		b.eventPath = cleanPath(path)
+	}
+}
+
+// WithInputPath sets the input webhook path the conversation ID is
+// appended to (default DefaultInputPath)
+func WithInputPath(path string) URLBuilderOption {
+	return func(b *URLBuilder) {
+		b.inputPath = cleanPath(path)
+	}
+}
+
+// WithURLSigningSecret signs answer and event URL parameters with secret
+func WithURLSigningSecret(secret []byte) URLBuilderOption {
+	return func(b *URLBuilder) {
+		b.secret = secret
+	}
+}
+
+// NewURLBuilder creates a builder for URLs under base, such as
+// "https://game.example.com"
+func NewURLBuilder(base string, opts ...URLBuilderOption) *URLBuilder {
+	b := &URLBuilder{
+		base:       strings.TrimSuffix(base, "/"),
+		answerPath: DefaultAnswerPath,
+		eventPath:  DefaultEventPath,
+		inputPath:  DefaultInputPath,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+func cleanPath(path string) string {
+	return "/" + strings.Trim(path, "/")
+}
+
+// WithBase returns a copy of the builder for another base URL, e.g. one
+// resolved per call by a vonage.WebhookBaseResolver
+func (b *URLBuilder) WithBase(base string) *URLBuilder {
+	c := *b
+	c.base = strings.TrimSuffix(base, "/")
+	return &c
+}
+
+// AnswerURL returns the answer URL with params, signed if configured
+func (b *URLBuilder) AnswerURL(params url.Values) string {
+	return b.build(b.answerPath, params)
+}
+
+// EventURL returns the event URL with params, signed if configured
+func (b *URLBuilder) EventURL(params url.Values) string {
+	return b.build(b.eventPath, params)
+}
+
+// InputURL returns the input URL of a conversation, with the escaped ID
+// as the last path segment
+func (b *URLBuilder) InputURL(conversationID string) string {
+	return b.base + b.inputPath + "/" + url.PathEscape(conversationID)
+}
+
+// build joins the base URL, path and encoded parameters
+func (b *URLBuilder) build(path string, params url.Values) string {
+	u := b.base + path
+	if b.secret != nil {
+		params = b.Sign(params)
+	}
+	if len(params) > 0 {
+		u += "?" + params.Encode()
+	}
+	return u
+}
+
+// Sign returns a copy of params with SignedParamsParam and SignatureParam
+// added. Parameter names must not contain commas.
+func (b *URLBuilder) Sign(params url.Values) url.Values {
+	signed := make(url.Values, len(params)+2)
+	keys := make([]string, 0, len(params))
+	for k, v := range params {
+		if k == SignatureParam || k == SignedParamsParam {
+			continue
+		}
+		signed[k] = append([]string(nil), v...)
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if len(keys) > 0 {
+		signed.Set(SignedParamsParam, strings.Join(keys, ","))
+	}
+	signed.Set(SignatureParam, b.signature(keys, signed))
+	return signed
+}
+
+// Verify checks the signature of webhook URL parameters, e.g.
+// r.URL.Query() in an event handler. Parameters Vonage adds, such as uuid,
+// are not signed and may be present. Without a signing secret every
+// parameter set is accepted.
+func (b *URLBuilder) Verify(params url.Values) error {
+	if b.secret == nil {
+		return nil
+	}
+	sig := params.Get(SignatureParam)
+	if sig == "" {
+		return ErrInvalidURLSignature
+	}
+
+	var keys []string
+	if list := params.Get(SignedParamsParam); list != "" {
+		keys = strings.Split(list, ",")
+	}
+	expected := b.signature(keys, params)
+	if !hmac.Equal([]byte(sig), []byte(expected)) {
+		return ErrInvalidURLSignature
+	}
+	return nil
+}
+
+// isSigned reports whether the parameter name is listed as signed in
+// params; Verify checks that the listed parameters match the signature
+func isSigned(params url.Values, name string) bool {
+	return slices.Contains(strings.Split(params.Get(SignedParamsParam), ","), name)
+}
+
+// signature computes the HMAC of the named parameters, encoded in order
+func (b *URLBuilder) signature(keys []string, params url.Values) string {
+	var canonical strings.Builder
+	for i, k := range keys {
+		for j, v := range params[k] {
+			if i > 0 || j > 0 {
+				canonical.WriteByte('&')
+			}
+			canonical.WriteString(url.QueryEscape(k) + "=" + url.QueryEscape(v))
+		}
+	}
+	mac := hmac.New(sha256.New, b.secret)
+	mac.Write([]byte(canonical.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ParseAnswerRequest parses an answer webhook like ParseAnswerRequest
+// and rejects it with ErrInvalidURLSignature if its URL parameters do not
+// match their signature. It adds the custom data given to CreateCall.
+func (b *URLBuilder) ParseAnswerRequest(r *http.Request) (AnswerRequest, error) {
+	if err := b.Verify(r.URL.Query()); err != nil {
+		return AnswerRequest{}, err
+	}
+	data, err := b.CustomDataFromRequest(r)
+	if err != nil {
+		return AnswerRequest{}, err
+	}
+	req, err := ParseAnswerRequest(r)
+	if err != nil {
+		return AnswerRequest{}, err
+	}
+	req.CustomData = mergeCustomData(req.CustomData, data)
+	req.VerifiedCustomData = data
+	return req, nil
+}
+
+// ========================================
+// Answer Request Parsing
+// ========================================
+
+// ParseAnswerRequest parses an answer webhook, from query parameters (GET)
+// or a JSON body (POST). Custom data in the URL is not verified, so it is
+// left to URLBuilder.ParseAnswerRequest.
+func ParseAnswerRequest(r *http.Request) (AnswerRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return AnswerRequest{}, err
+	}
+	return parseAnswerRequest(r, body)
+}
+
+// parseAnswerRequest parses an answer webhook whose body has been read
+func parseAnswerRequest(r *http.Request, body []byte) (AnswerRequest, error) {
+	req := AnswerRequest{Params: r.URL.Query()}
+	if r.Method == http.MethodPost && len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			return AnswerRequest{}, err
+		}
+		return req, nil
+	}
+	req.To = req.Params.Get("to")
+	req.From = req.Params.Get("from")
+	req.UUID = req.Params.Get("uuid")
+	req.ConversationUUID = req.Params.Get("conversation_uuid")
+	req.RegionURL = req.Params.Get("region_url")
+	return req, nil
+}
@@ -0,0 +1,100 @@
+package voice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ========================================
+// Webhook Methods
+// ========================================
+
+// HTTPMethod is the method Vonage uses to request a webhook URL
+type HTTPMethod string
+
+const (
+	MethodGET  HTTPMethod = "GET"
+	MethodPOST HTTPMethod = "POST"
+)
+
+// ErrInvalidHTTPMethod is returned for webhook methods other than GET and
+// POST, which Vonage would not deliver to
+var ErrInvalidHTTPMethod = errors.New("voice: webhook method must be GET or POST")
+
+// ParseHTTPMethod parses a method case-insensitively
+func ParseHTTPMethod(s string) (HTTPMethod, error) {
+	m := HTTPMethod(strings.ToUpper(strings.TrimSpace(s)))
+	if err := m.Validate(); err != nil {
+		return "", err
+	}
+	return m, nil
+}
+
+// Validate returns an error wrapping ErrInvalidHTTPMethod unless m is GET
+// or POST
+func (m HTTPMethod) Validate() error {
+	switch m {
+	case MethodGET, MethodPOST:
+		return nil
+	}
+	return fmt.Errorf("%w, got %q", ErrInvalidHTTPMethod, string(m))
+}
+
+// ========================================
+// Request Validation
+// ========================================
+
+// Validate checks the webhook methods of the options and of the inline
+// NCCO. Empty methods default to POST.
+func (o *CreateCallOptions) Validate() error {
+	if o.AnswerMethod != "" {
+		if err := o.AnswerMethod.Validate(); err != nil {
+			return fmt.Errorf("answer_method: %w", err)
+		}
+	}
+	if o.EventMethod != "" {
+		if err := o.EventMethod.Validate(); err != nil {
+			return fmt.Errorf("event_method: %w", err)
+		}
+	}
+	return o.InlineNCCO.Validate()
+}
+
+// ErrInvalidLevel is returned for talk and stream levels outside -1 to 1
+var ErrInvalidLevel = errors.New("voice: level must be between -1 and 1")
+
+// ErrInvalidLoop is returned for negative talk and stream loop counts
+var ErrInvalidLoop = errors.New("voice: loop must be 0 (infinite) or more")
+
+// Validate checks the webhook methods, levels and loop counts of the
+// NCCO's actions. Builders cannot fail, so check an NCCO built with values
+// from configuration before serving it.
+func (n NCCO) Validate() error {
+	for i, a := range n {
+		if a.Level != nil {
+			if err := validateLevel(*a.Level); err != nil {
+				return fmt.Errorf("ncco[%d] %s level: %w", i, a.ActionType, err)
+			}
+		}
+		if a.Loop != nil && *a.Loop < 0 {
+			return fmt.Errorf("ncco[%d] %s loop: %w, got %d", i, a.ActionType, ErrInvalidLoop, *a.Loop)
+		}
+		if a.EventMethod == "" {
+			continue
+		}
+		if err := a.EventMethod.Validate(); err != nil {
+			return fmt.Errorf("ncco[%d] %s eventMethod: %w", i, a.ActionType, err)
+		}
+	}
+	return nil
+}
+
+// validateLevel returns an error wrapping ErrInvalidLevel unless level is
+// between -1 and 1
+func validateLevel(level float64) error {
+	if level < -1 || level > 1 {
+		return fmt.Errorf("%w, got %g", ErrInvalidLevel, level)
+	}
+	return nil
+}
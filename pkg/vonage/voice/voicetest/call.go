@@ -0,0 +1,239 @@
+// Package voicetest fabricates realistic Voice API webhooks (answer
+// requests, call event sequences and input results) and posts them to a
+// handler under test, for table-driven tests of IVR flows that run without
+// Vonage. Build a Call for each test case, then drive the application's
+// webhook handler with a Driver.
+package voicetest
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/vonatrigger/poc/pkg/vonage/voice"
+)
+
+// ========================================
+// Call Fixtures
+// ========================================
+
+// Defaults of NewCall
+const (
+	DefaultFrom     = "819012345678"
+	DefaultTo       = "815012345678"
+	DefaultRingTime = 3 * time.Second
+)
+
+// DefaultRate is the per-minute rate of a call (default 0.0127)
+var DefaultRate = voice.Price(1270000)
+
+// timestampFormat is the format of webhook timestamps
+const timestampFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// Call is one fabricated call. Its clock starts at the start time and is
+// moved forward by the event sequences, or explicitly with Advance, so
+// event timestamps are consistent.
+type Call struct {
+	UUID             string
+	ConversationUUID string
+	From             string
+	To               string
+	Direction        voice.CallDirection
+	// CustomData is sent in answer and event webhooks, as when given to
+	// CreateCall; handlers only accept it when the driver signs it (see
+	// WithURLBuilder)
+	CustomData map[string]interface{}
+	// Rate is the per-minute rate used to price completed calls
+	Rate voice.Price
+	// RingTime is how long the call rings in event sequences
+	RingTime time.Duration
+
+	now time.Time
+}
+
+// CallOption is a functional option for configuring a Call
+type CallOption func(*Call)
+
+// WithFrom sets the caller number (default DefaultFrom)
+func WithFrom(number string) CallOption {
+	return func(c *Call) {
+		c.From = number
+	}
+}
+
+// WithTo sets the called number (default DefaultTo)
+func WithTo(number string) CallOption {
+	return func(c *Call) {
+		c.To = number
+	}
+}
+
+// WithDirection sets the call direction (default inbound)
+func WithDirection(direction voice.CallDirection) CallOption {
+	return func(c *Call) {
+		c.Direction = direction
+	}
+}
+
+// WithCustomData sets the call's custom data
+func WithCustomData(data map[string]interface{}) CallOption {
+	return func(c *Call) {
+		c.CustomData = data
+	}
+}
+
+// WithStartTime sets the time of the first event (default now), e.g. a
+// fixed time for reproducible payloads
+func WithStartTime(t time.Time) CallOption {
+	return func(c *Call) {
+		c.now = t.UTC()
+	}
+}
+
+// WithRate sets the per-minute rate (default DefaultRate)
+func WithRate(rate voice.Price) CallOption {
+	return func(c *Call) {
+		c.Rate = rate
+	}
+}
+
+// WithRingTime sets how long the call rings (default DefaultRingTime)
+func WithRingTime(d time.Duration) CallOption {
+	return func(c *Call) {
+		c.RingTime = d
+	}
+}
+
+// NewCall creates an inbound call with random UUIDs
+func NewCall(opts ...CallOption) *Call {
+	c := &Call{
+		UUID:             uuid.NewString(),
+		ConversationUUID: "CON-" + uuid.NewString(),
+		From:             DefaultFrom,
+		To:               DefaultTo,
+		Direction:        voice.CallDirectionInbound,
+		Rate:             DefaultRate,
+		RingTime:         DefaultRingTime,
+		now:              time.Now().UTC(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Now returns the call's clock
+func (c *Call) Now() time.Time {
+	return c.now
+}
+
+// Advance moves the call's clock forward by d
+func (c *Call) Advance(d time.Duration) *Call {
+	c.now = c.now.Add(d)
+	return c
+}
+
+// AnswerRequest returns the answer webhook of the call
+func (c *Call) AnswerRequest() voice.AnswerRequest {
+	return voice.AnswerRequest{
+		To:               c.To,
+		From:             c.From,
+		UUID:             c.UUID,
+		ConversationUUID: c.ConversationUUID,
+		CustomData:       c.CustomData,
+	}
+}
+
+// Event returns an event with status at the call's current time
+func (c *Call) Event(status voice.CallStatus) voice.CallEvent {
+	return voice.CallEvent{
+		UUID:             c.UUID,
+		ConversationUUID: c.ConversationUUID,
+		Status:           status,
+		Direction:        string(c.Direction),
+		Timestamp:        c.now.Format(timestampFormat),
+		From:             c.From,
+		To:               c.To,
+		CustomData:       c.CustomData,
+	}
+}
+
+// Answered returns the events of a call that rings, is answered and is hung
+// up by the caller after talk: started, ringing, answered and completed,
+// with the completed event priced at the call's rate
+func (c *Call) Answered(talk time.Duration) []voice.CallEvent {
+	events := []voice.CallEvent{c.Event(voice.CallStatusStarted), c.Event(voice.CallStatusRinging)}
+	c.Advance(c.RingTime)
+	events = append(events, c.Event(voice.CallStatusAnswered))
+	c.Advance(talk)
+	return append(events, c.Completed(talk, voice.DisconnectedByUser))
+}
+
+// Completed returns the completed event of a call answered talk ago
+func (c *Call) Completed(talk time.Duration, by voice.DisconnectedBy) voice.CallEvent {
+	seconds := int64(talk / time.Second)
+	event := c.Event(voice.CallStatusCompleted)
+	event.Duration = strconv.FormatInt(seconds, 10)
+	event.Rate = c.Rate.String()
+	event.Price = (c.Rate * voice.Price(seconds) / 60).String()
+	event.DisconnectedBy = by
+	return event
+}
+
+// Failed returns the events of a call that ends with status, such as busy
+// or rejected, and detail: started and ringing (unless rejected before
+// ringing), then the failure
+func (c *Call) Failed(status voice.CallStatus, detail voice.CallStatusDetail) []voice.CallEvent {
+	events := []voice.CallEvent{c.Event(voice.CallStatusStarted)}
+	if status != voice.CallStatusRejected && status != voice.CallStatusFailed {
+		events = append(events, c.Event(voice.CallStatusRinging))
+		c.Advance(c.RingTime)
+	}
+	event := c.Event(status)
+	event.Detail = detail
+	return append(events, event)
+}
+
+// ========================================
+// Input Results
+// ========================================
+
+// Speech returns the input webhook of the caller saying text, recognized
+// with confidence (0 to 1). Alternatives are added with lower confidence.
+func (c *Call) Speech(text string, confidence float64, alternatives ...string) voice.ASRResult {
+	result := c.input()
+	result.Speech.Results = append(result.Speech.Results, voice.ASRMatch{
+		Text:       text,
+		Confidence: strconv.FormatFloat(confidence, 'f', -1, 64),
+	})
+	for i, alt := range alternatives {
+		result.Speech.Results = append(result.Speech.Results, voice.ASRMatch{
+			Text:       alt,
+			Confidence: strconv.FormatFloat(confidence/float64(i+2), 'f', 4, 64),
+		})
+	}
+	return result
+}
+
+// DTMF returns the input webhook of the caller pressing digits
+func (c *Call) DTMF(digits string) voice.ASRResult {
+	result := c.input()
+	result.DTMF = digits
+	return result
+}
+
+// NoInput returns the input webhook of the caller saying and pressing
+// nothing before the start timeout
+func (c *Call) NoInput() voice.ASRResult {
+	result := c.input()
+	result.TimedOut = true
+	result.Speech.TimeoutReason = "start_timeout"
+	return result
+}
+
+func (c *Call) input() voice.ASRResult {
+	return voice.ASRResult{UUID: c.UUID, ConversationUUID: c.ConversationUUID}
+}
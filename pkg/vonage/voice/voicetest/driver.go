@@ -0,0 +1,140 @@
+package voicetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/vonatrigger/poc/pkg/vonage/voice"
+)
+
+// ========================================
+// Driver
+// ========================================
+
+// Driver posts fabricated webhooks to a handler under test in-process, at
+// the paths of a voice.URLBuilder, the way Vonage would during a call
+type Driver struct {
+	handler http.Handler
+	urls    *voice.URLBuilder
+}
+
+// DriverOption is a functional option for configuring a Driver
+type DriverOption func(*Driver)
+
+// WithURLBuilder sets the application's webhook paths and URL signing
+// secret (default voice.NewURLBuilder with the default paths). With a
+// secret, answer and event URLs are signed like the application's own.
+func WithURLBuilder(b *voice.URLBuilder) DriverOption {
+	return func(d *Driver) {
+		d.urls = b.WithBase("")
+	}
+}
+
+// NewDriver creates a driver for handler, typically the application's
+// mux with its answer, event and input handlers
+func NewDriver(handler http.Handler, opts ...DriverOption) *Driver {
+	d := &Driver{
+		handler: handler,
+		urls:    voice.NewURLBuilder(""),
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// Answer posts the call's answer webhook and returns the NCCO the handler
+// responded with
+func (d *Driver) Answer(c *Call) (voice.NCCO, error) {
+	params, err := customDataParams(c.CustomData)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := d.Post(d.urls.AnswerURL(params), c.AnswerRequest())
+	if err != nil {
+		return nil, err
+	}
+	return decodeNCCO(rec)
+}
+
+// Event posts one call event
+func (d *Driver) Event(event voice.CallEvent) error {
+	params, err := customDataParams(event.CustomData)
+	if err != nil {
+		return err
+	}
+	_, err = d.Post(d.urls.EventURL(params), event)
+	return err
+}
+
+// Events posts events in order, stopping at the first the handler fails
+func (d *Driver) Events(events []voice.CallEvent) error {
+	for _, event := range events {
+		if err := d.Event(event); err != nil {
+			return fmt.Errorf("voicetest: %s event: %w", event.Status, err)
+		}
+	}
+	return nil
+}
+
+// Input posts an input result to the call's input URL and returns the NCCO
+// the handler responded with, nil if the response had no body
+func (d *Driver) Input(c *Call, result voice.ASRResult) (voice.NCCO, error) {
+	rec, err := d.Post(d.urls.InputURL(c.ConversationUUID), result)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNCCO(rec)
+}
+
+// Post posts payload as JSON to target, a path with an optional query, and
+// returns the recorded response. Responses other than 2xx are errors.
+func (d *Driver) Post(target string, payload interface{}) (*httptest.ResponseRecorder, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("voicetest: failed to marshal webhook: %w", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	d.handler.ServeHTTP(rec, req)
+
+	if rec.Code < 200 || rec.Code >= 300 {
+		return rec, fmt.Errorf("voicetest: webhook %s returned status %d: %s", target, rec.Code, bytes.TrimSpace(rec.Body.Bytes()))
+	}
+	return rec, nil
+}
+
+// customDataParams returns the webhook URL parameters carrying a call's
+// custom data, as CreateCall adds them
+func customDataParams(data map[string]interface{}) (url.Values, error) {
+	params := url.Values{}
+	if len(data) == 0 {
+		return params, nil
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("voicetest: failed to encode custom data: %w", err)
+	}
+	params.Set(voice.CustomDataParam, string(encoded))
+	return params, nil
+}
+
+// decodeNCCO decodes the NCCO of a webhook response
+func decodeNCCO(rec *httptest.ResponseRecorder) (voice.NCCO, error) {
+	if rec.Body.Len() == 0 {
+		return nil, nil
+	}
+	var ncco voice.NCCO
+	if err := json.Unmarshal(rec.Body.Bytes(), &ncco); err != nil {
+		return nil, fmt.Errorf("voicetest: response is not an NCCO: %w", err)
+	}
+	return ncco, nil
+}
@@ -0,0 +1,110 @@
+package voicetest_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vonatrigger/poc/pkg/vonage/voice"
+	"github.com/vonatrigger/poc/pkg/vonage/voice/flow"
+	"github.com/vonatrigger/poc/pkg/vonage/voice/voicetest"
+)
+
+func ExampleDriver() {
+	// The application under test: a menu flow behind answer, input and
+	// event handlers
+	menu, _ := flow.New("menu",
+		flow.State{
+			Name:    "menu",
+			Prompts: []flow.Prompt{flow.Talk("Press 1 or say sales, press 2 or say support.")},
+			Transitions: []flow.Transition{
+				flow.On(flow.DTMF("1"), "sales"),
+				flow.On(flow.Speech("sales"), "sales"),
+				flow.On(flow.DTMF("2"), "support"),
+				flow.On(flow.Speech("support"), "support"),
+			},
+			NoMatch: "goodbye",
+		},
+		flow.State{Name: "sales", Prompts: []flow.Prompt{flow.Talk("Connecting you to sales.")}},
+		flow.State{Name: "support", Prompts: []flow.Prompt{flow.Talk("Connecting you to support.")}},
+		flow.State{Name: "goodbye", Prompts: []flow.Prompt{flow.Talk("Goodbye.")}},
+	)
+	engine, _ := flow.NewEngine(menu, "https://example.com"+voice.DefaultInputPath, flow.WithMaxRetries(0))
+
+	var completed []string
+	mux := http.NewServeMux()
+	mux.HandleFunc(voice.DefaultAnswerPath, func(w http.ResponseWriter, r *http.Request) {
+		req, _ := voice.ParseAnswerRequest(r)
+		ncco, _ := engine.Start(r.Context(), req.ConversationUUID)
+		json.NewEncoder(w).Encode(ncco)
+	})
+	mux.HandleFunc(voice.DefaultInputPath+"/", func(w http.ResponseWriter, r *http.Request) {
+		var result voice.ASRResult
+		json.NewDecoder(r.Body).Decode(&result)
+		ncco, err := engine.Handle(r.Context(), &result)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(ncco)
+	})
+	mux.HandleFunc(voice.DefaultEventPath, func(w http.ResponseWriter, r *http.Request) {
+		event, _ := voice.ParseCallEvent(r)
+		if event.Status == voice.CallStatusCompleted {
+			completed = append(completed, event.Duration+"s "+event.Price)
+		}
+	})
+
+	driver := voicetest.NewDriver(mux)
+	start := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		input func(c *voicetest.Call) voice.ASRResult
+	}{
+		{"dtmf", func(c *voicetest.Call) voice.ASRResult { return c.DTMF("1") }},
+		{"speech", func(c *voicetest.Call) voice.ASRResult { return c.Speech("support", 0.92, "sport") }},
+		{"silence", func(c *voicetest.Call) voice.ASRResult { return c.NoInput() }},
+	}
+	for _, tt := range tests {
+		call := voicetest.NewCall(voicetest.WithStartTime(start))
+		if _, err := driver.Answer(call); err != nil {
+			fmt.Println(tt.name, err)
+			continue
+		}
+		ncco, err := driver.Input(call, tt.input(call))
+		if err != nil {
+			fmt.Println(tt.name, err)
+			continue
+		}
+		fmt.Printf("%s: %s\n", tt.name, ncco[0].Text)
+
+		if err := driver.Events(call.Answered(90 * time.Second)); err != nil {
+			fmt.Println(tt.name, err)
+		}
+	}
+	fmt.Println(completed[0])
+	// Output:
+	// dtmf: Connecting you to sales.
+	// speech: Connecting you to support.
+	// silence: Goodbye.
+	// 90s 0.01905000
+}
+
+func ExampleCall_Failed() {
+	call := voicetest.NewCall(
+		voicetest.WithDirection(voice.CallDirectionOutbound),
+		voicetest.WithStartTime(time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)),
+	)
+	events := call.Failed(voice.CallStatusTimeout, voice.CallDetailRingTimeout)
+	for _, event := range events {
+		fmt.Println(event.Timestamp, event.Status)
+	}
+	fmt.Println(events[len(events)-1].Detail)
+	// Output:
+	// 2024-04-01T09:00:00.000Z started
+	// 2024-04-01T09:00:00.000Z ringing
+	// 2024-04-01T09:00:03.000Z timeout
+	// ring_timeout
+}
@@ -0,0 +1,86 @@
+package vonagemock
+
+import (
+	"context"
+
+	"github.com/vonatrigger/poc/pkg/vonage/account"
+)
+
+var _ account.API = (*Account)(nil)
+
+// Account is a stub account.API. Each method calls the matching Func field,
+// or returns ErrNotStubbed if it is nil.
+type Account struct {
+	GetBalanceFunc     func(ctx context.Context) (*account.Balance, error)
+	TopUpFunc          func(ctx context.Context, transactionID string) error
+	GetSettingsFunc    func(ctx context.Context) (*account.Settings, error)
+	UpdateSettingsFunc func(ctx context.Context, update *account.SettingsUpdate) (*account.Settings, error)
+	ListSecretsFunc    func(ctx context.Context) ([]account.Secret, error)
+	GetSecretFunc      func(ctx context.Context, secretID string) (*account.Secret, error)
+	CreateSecretFunc   func(ctx context.Context, secret string) (*account.Secret, error)
+	RevokeSecretFunc   func(ctx context.Context, secretID string) error
+}
+
+// GetBalance implements account.API
+func (m *Account) GetBalance(ctx context.Context) (*account.Balance, error) {
+	if m.GetBalanceFunc == nil {
+		return nil, notStubbed("Account.GetBalance")
+	}
+	return m.GetBalanceFunc(ctx)
+}
+
+// TopUp implements account.API
+func (m *Account) TopUp(ctx context.Context, transactionID string) error {
+	if m.TopUpFunc == nil {
+		return notStubbed("Account.TopUp")
+	}
+	return m.TopUpFunc(ctx, transactionID)
+}
+
+// GetSettings implements account.API
+func (m *Account) GetSettings(ctx context.Context) (*account.Settings, error) {
+	if m.GetSettingsFunc == nil {
+		return nil, notStubbed("Account.GetSettings")
+	}
+	return m.GetSettingsFunc(ctx)
+}
+
+// UpdateSettings implements account.API
+func (m *Account) UpdateSettings(ctx context.Context, update *account.SettingsUpdate) (*account.Settings, error) {
+	if m.UpdateSettingsFunc == nil {
+		return nil, notStubbed("Account.UpdateSettings")
+	}
+	return m.UpdateSettingsFunc(ctx, update)
+}
+
+// ListSecrets implements account.API
+func (m *Account) ListSecrets(ctx context.Context) ([]account.Secret, error) {
+	if m.ListSecretsFunc == nil {
+		return nil, notStubbed("Account.ListSecrets")
+	}
+	return m.ListSecretsFunc(ctx)
+}
+
+// GetSecret implements account.API
+func (m *Account) GetSecret(ctx context.Context, secretID string) (*account.Secret, error) {
+	if m.GetSecretFunc == nil {
+		return nil, notStubbed("Account.GetSecret")
+	}
+	return m.GetSecretFunc(ctx, secretID)
+}
+
+// CreateSecret implements account.API
+func (m *Account) CreateSecret(ctx context.Context, secret string) (*account.Secret, error) {
+	if m.CreateSecretFunc == nil {
+		return nil, notStubbed("Account.CreateSecret")
+	}
+	return m.CreateSecretFunc(ctx, secret)
+}
+
+// RevokeSecret implements account.API
+func (m *Account) RevokeSecret(ctx context.Context, secretID string) error {
+	if m.RevokeSecretFunc == nil {
+		return notStubbed("Account.RevokeSecret")
+	}
+	return m.RevokeSecretFunc(ctx, secretID)
+}
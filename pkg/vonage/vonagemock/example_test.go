@@ -0,0 +1,42 @@
+package vonagemock_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vonatrigger/poc/pkg/vonage/messages"
+	"github.com/vonatrigger/poc/pkg/vonage/vonagemock"
+)
+
+// Notifier is application code that depends on the interface rather than
+// *messages.Client
+type Notifier struct {
+	SMS messages.API
+}
+
+func (n *Notifier) TableReady(ctx context.Context, phone string) error {
+	_, err := n.SMS.SendSMS(ctx, phone, "Your table is ready")
+	return err
+}
+
+func ExampleMessages() {
+	var sent []string
+	stub := &vonagemock.Messages{
+		SendSMSFunc: func(ctx context.Context, to, text string, opts ...messages.SendOption) (*messages.SendResponse, error) {
+			sent = append(sent, to+": "+text)
+			return &messages.SendResponse{MessageUUID: "uuid-1"}, nil
+		},
+	}
+
+	n := &Notifier{SMS: stub}
+	_ = n.TableReady(context.Background(), "15551234567")
+
+	fmt.Println(sent)
+
+	// Methods without a Func return ErrNotStubbed
+	_, err := stub.SendWhatsApp(context.Background(), "15551234567", "hi")
+	fmt.Println(err)
+	// Output:
+	// [15551234567: Your table is ready]
+	// vonagemock: method not stubbed: Messages.SendWhatsApp
+}
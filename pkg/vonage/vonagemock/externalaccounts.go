@@ -0,0 +1,59 @@
+package vonagemock
+
+import (
+	"context"
+
+	"github.com/vonatrigger/poc/pkg/vonage/externalaccounts"
+)
+
+var _ externalaccounts.API = (*ExternalAccounts)(nil)
+
+// ExternalAccounts is a stub externalaccounts.API. Each method calls the matching Func field,
+// or returns ErrNotStubbed if it is nil.
+type ExternalAccounts struct {
+	ListFunc         func(ctx context.Context, opts *externalaccounts.ListOptions) (*externalaccounts.Page, error)
+	GetFunc          func(ctx context.Context, externalID string) (*externalaccounts.Account, error)
+	LinkFunc         func(ctx context.Context, externalID, appID string) error
+	UnlinkFunc       func(ctx context.Context, externalID, appID string) error
+	EnsureLinkedFunc func(ctx context.Context, externalID, appID string) error
+}
+
+// List implements externalaccounts.API
+func (m *ExternalAccounts) List(ctx context.Context, opts *externalaccounts.ListOptions) (*externalaccounts.Page, error) {
+	if m.ListFunc == nil {
+		return nil, notStubbed("ExternalAccounts.List")
+	}
+	return m.ListFunc(ctx, opts)
+}
+
+// Get implements externalaccounts.API
+func (m *ExternalAccounts) Get(ctx context.Context, externalID string) (*externalaccounts.Account, error) {
+	if m.GetFunc == nil {
+		return nil, notStubbed("ExternalAccounts.Get")
+	}
+	return m.GetFunc(ctx, externalID)
+}
+
+// Link implements externalaccounts.API
+func (m *ExternalAccounts) Link(ctx context.Context, externalID, appID string) error {
+	if m.LinkFunc == nil {
+		return notStubbed("ExternalAccounts.Link")
+	}
+	return m.LinkFunc(ctx, externalID, appID)
+}
+
+// Unlink implements externalaccounts.API
+func (m *ExternalAccounts) Unlink(ctx context.Context, externalID, appID string) error {
+	if m.UnlinkFunc == nil {
+		return notStubbed("ExternalAccounts.Unlink")
+	}
+	return m.UnlinkFunc(ctx, externalID, appID)
+}
+
+// EnsureLinked implements externalaccounts.API
+func (m *ExternalAccounts) EnsureLinked(ctx context.Context, externalID, appID string) error {
+	if m.EnsureLinkedFunc == nil {
+		return notStubbed("ExternalAccounts.EnsureLinked")
+	}
+	return m.EnsureLinkedFunc(ctx, externalID, appID)
+}
@@ -0,0 +1,79 @@
+package vonagemock
+
+import (
+	"context"
+	"io"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/media"
+)
+
+var _ media.API = (*Media)(nil)
+
+// Media is a stub media.API. Each method calls the matching Func field,
+// or returns ErrNotStubbed if it is nil.
+type Media struct {
+	ListFunc         func(ctx context.Context, opts *media.ListOptions) (*media.Page, error)
+	GetFunc          func(ctx context.Context, mediaID string) (*media.Item, error)
+	UpdateFunc       func(ctx context.Context, mediaID string, update *media.Update) error
+	DeleteFunc       func(ctx context.Context, mediaID string) error
+	UploadFunc       func(ctx context.Context, name, contentType string, r io.Reader, opts *media.UploadOptions) (string, error)
+	DownloadFunc     func(ctx context.Context, mediaID string, w io.Writer, opts ...vonage.DownloadOption) (int64, error)
+	DownloadFromFunc func(ctx context.Context, mediaID string, offset int64, w io.Writer, opts ...vonage.DownloadOption) (int64, error)
+}
+
+// List implements media.API
+func (m *Media) List(ctx context.Context, opts *media.ListOptions) (*media.Page, error) {
+	if m.ListFunc == nil {
+		return nil, notStubbed("Media.List")
+	}
+	return m.ListFunc(ctx, opts)
+}
+
+// Get implements media.API
+func (m *Media) Get(ctx context.Context, mediaID string) (*media.Item, error) {
+	if m.GetFunc == nil {
+		return nil, notStubbed("Media.Get")
+	}
+	return m.GetFunc(ctx, mediaID)
+}
+
+// Update implements media.API
+func (m *Media) Update(ctx context.Context, mediaID string, update *media.Update) error {
+	if m.UpdateFunc == nil {
+		return notStubbed("Media.Update")
+	}
+	return m.UpdateFunc(ctx, mediaID, update)
+}
+
+// Delete implements media.API
+func (m *Media) Delete(ctx context.Context, mediaID string) error {
+	if m.DeleteFunc == nil {
+		return notStubbed("Media.Delete")
+	}
+	return m.DeleteFunc(ctx, mediaID)
+}
+
+// Upload implements media.API
+func (m *Media) Upload(ctx context.Context, name, contentType string, r io.Reader, opts *media.UploadOptions) (string, error) {
+	if m.UploadFunc == nil {
+		return "", notStubbed("Media.Upload")
+	}
+	return m.UploadFunc(ctx, name, contentType, r, opts)
+}
+
+// Download implements media.API
+func (m *Media) Download(ctx context.Context, mediaID string, w io.Writer, opts ...vonage.DownloadOption) (int64, error) {
+	if m.DownloadFunc == nil {
+		return 0, notStubbed("Media.Download")
+	}
+	return m.DownloadFunc(ctx, mediaID, w, opts...)
+}
+
+// DownloadFrom implements media.API
+func (m *Media) DownloadFrom(ctx context.Context, mediaID string, offset int64, w io.Writer, opts ...vonage.DownloadOption) (int64, error) {
+	if m.DownloadFromFunc == nil {
+		return 0, notStubbed("Media.DownloadFrom")
+	}
+	return m.DownloadFromFunc(ctx, mediaID, offset, w, opts...)
+}
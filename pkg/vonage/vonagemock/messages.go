@@ -0,0 +1,95 @@
+package vonagemock
+
+import (
+	"context"
+
+	"github.com/vonatrigger/poc/pkg/vonage/messages"
+)
+
+var _ messages.API = (*Messages)(nil)
+
+// Messages is a stub messages.API. Each method calls the matching Func field,
+// or returns ErrNotStubbed if it is nil.
+type Messages struct {
+	SendFunc                     func(ctx context.Context, req *messages.SendRequest) (*messages.SendResponse, error)
+	SendSMSFunc                  func(ctx context.Context, to, text string, opts ...messages.SendOption) (*messages.SendResponse, error)
+	SendSMSFromFunc              func(ctx context.Context, from, to, text string, opts ...messages.SendOption) (*messages.SendResponse, error)
+	SendMMSFunc                  func(ctx context.Context, to, imageURL, caption string, opts ...messages.SendOption) (*messages.SendResponse, error)
+	SendWhatsAppFunc             func(ctx context.Context, to, text string, opts ...messages.SendOption) (*messages.SendResponse, error)
+	SendWhatsAppImageFunc        func(ctx context.Context, to, imageURL, caption string, opts ...messages.SendOption) (*messages.SendResponse, error)
+	SendViberFunc                func(ctx context.Context, to, text string, opts ...messages.SendOption) (*messages.SendResponse, error)
+	SendViberImageWithButtonFunc func(ctx context.Context, to, imageURL, buttonText, buttonURL string, opts ...messages.SendOption) (*messages.SendResponse, error)
+	PreviewFunc                  func(req *messages.SendRequest) (*messages.Preview, error)
+}
+
+// Send implements messages.API
+func (m *Messages) Send(ctx context.Context, req *messages.SendRequest) (*messages.SendResponse, error) {
+	if m.SendFunc == nil {
+		return nil, notStubbed("Messages.Send")
+	}
+	return m.SendFunc(ctx, req)
+}
+
+// SendSMS implements messages.API
+func (m *Messages) SendSMS(ctx context.Context, to, text string, opts ...messages.SendOption) (*messages.SendResponse, error) {
+	if m.SendSMSFunc == nil {
+		return nil, notStubbed("Messages.SendSMS")
+	}
+	return m.SendSMSFunc(ctx, to, text, opts...)
+}
+
+// SendSMSFrom implements messages.API
+func (m *Messages) SendSMSFrom(ctx context.Context, from, to, text string, opts ...messages.SendOption) (*messages.SendResponse, error) {
+	if m.SendSMSFromFunc == nil {
+		return nil, notStubbed("Messages.SendSMSFrom")
+	}
+	return m.SendSMSFromFunc(ctx, from, to, text, opts...)
+}
+
+// SendMMS implements messages.API
+func (m *Messages) SendMMS(ctx context.Context, to, imageURL, caption string, opts ...messages.SendOption) (*messages.SendResponse, error) {
+	if m.SendMMSFunc == nil {
+		return nil, notStubbed("Messages.SendMMS")
+	}
+	return m.SendMMSFunc(ctx, to, imageURL, caption, opts...)
+}
+
+// SendWhatsApp implements messages.API
+func (m *Messages) SendWhatsApp(ctx context.Context, to, text string, opts ...messages.SendOption) (*messages.SendResponse, error) {
+	if m.SendWhatsAppFunc == nil {
+		return nil, notStubbed("Messages.SendWhatsApp")
+	}
+	return m.SendWhatsAppFunc(ctx, to, text, opts...)
+}
+
+// SendWhatsAppImage implements messages.API
+func (m *Messages) SendWhatsAppImage(ctx context.Context, to, imageURL, caption string, opts ...messages.SendOption) (*messages.SendResponse, error) {
+	if m.SendWhatsAppImageFunc == nil {
+		return nil, notStubbed("Messages.SendWhatsAppImage")
+	}
+	return m.SendWhatsAppImageFunc(ctx, to, imageURL, caption, opts...)
+}
+
+// SendViber implements messages.API
+func (m *Messages) SendViber(ctx context.Context, to, text string, opts ...messages.SendOption) (*messages.SendResponse, error) {
+	if m.SendViberFunc == nil {
+		return nil, notStubbed("Messages.SendViber")
+	}
+	return m.SendViberFunc(ctx, to, text, opts...)
+}
+
+// SendViberImageWithButton implements messages.API
+func (m *Messages) SendViberImageWithButton(ctx context.Context, to, imageURL, buttonText, buttonURL string, opts ...messages.SendOption) (*messages.SendResponse, error) {
+	if m.SendViberImageWithButtonFunc == nil {
+		return nil, notStubbed("Messages.SendViberImageWithButton")
+	}
+	return m.SendViberImageWithButtonFunc(ctx, to, imageURL, buttonText, buttonURL, opts...)
+}
+
+// Preview implements messages.API
+func (m *Messages) Preview(req *messages.SendRequest) (*messages.Preview, error) {
+	if m.PreviewFunc == nil {
+		return nil, notStubbed("Messages.Preview")
+	}
+	return m.PreviewFunc(req)
+}
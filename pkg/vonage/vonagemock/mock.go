@@ -0,0 +1,19 @@
+// Package vonagemock provides stubs of the sub-packages' API interfaces
+// for unit testing code that depends on them. Set
+// the Func field of each method the code under test calls; unset methods
+// return ErrNotStubbed.
+//
+// For end-to-end tests over HTTP, use the vonagetest package instead.
+package vonagemock
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotStubbed is returned by a stub method whose Func field is nil
+var ErrNotStubbed = errors.New("vonagemock: method not stubbed")
+
+func notStubbed(method string) error {
+	return fmt.Errorf("%w: %s", ErrNotStubbed, method)
+}
@@ -0,0 +1,69 @@
+package vonagemock
+
+import (
+	"context"
+	"time"
+
+	"github.com/vonatrigger/poc/pkg/vonage/network"
+)
+
+var _ network.API = (*Network)(nil)
+
+// Network is a stub network.API. Each method calls the matching Func field,
+// or returns ErrNotStubbed if it is nil.
+type Network struct {
+	CheckSimSwapFunc func(ctx context.Context, phoneNumber string, maxAge time.Duration) (bool, error)
+	SimSwapDateFunc  func(ctx context.Context, phoneNumber string) (time.Time, error)
+	AuthURLFunc      func(redirectURI, phoneNumber, state string) (string, error)
+	ExchangeCodeFunc func(ctx context.Context, code, redirectURI string) (*network.Token, error)
+	VerifyNumberFunc func(ctx context.Context, token *network.Token, phoneNumber string) (bool, error)
+	CIBATokenFunc    func(ctx context.Context, phoneNumber string, scope network.Scope) (*network.Token, error)
+}
+
+// CheckSimSwap implements network.API
+func (m *Network) CheckSimSwap(ctx context.Context, phoneNumber string, maxAge time.Duration) (bool, error) {
+	if m.CheckSimSwapFunc == nil {
+		return false, notStubbed("Network.CheckSimSwap")
+	}
+	return m.CheckSimSwapFunc(ctx, phoneNumber, maxAge)
+}
+
+// SimSwapDate implements network.API
+func (m *Network) SimSwapDate(ctx context.Context, phoneNumber string) (time.Time, error) {
+	if m.SimSwapDateFunc == nil {
+		return time.Time{}, notStubbed("Network.SimSwapDate")
+	}
+	return m.SimSwapDateFunc(ctx, phoneNumber)
+}
+
+// AuthURL implements network.API
+func (m *Network) AuthURL(redirectURI, phoneNumber, state string) (string, error) {
+	if m.AuthURLFunc == nil {
+		return "", notStubbed("Network.AuthURL")
+	}
+	return m.AuthURLFunc(redirectURI, phoneNumber, state)
+}
+
+// ExchangeCode implements network.API
+func (m *Network) ExchangeCode(ctx context.Context, code, redirectURI string) (*network.Token, error) {
+	if m.ExchangeCodeFunc == nil {
+		return nil, notStubbed("Network.ExchangeCode")
+	}
+	return m.ExchangeCodeFunc(ctx, code, redirectURI)
+}
+
+// VerifyNumber implements network.API
+func (m *Network) VerifyNumber(ctx context.Context, token *network.Token, phoneNumber string) (bool, error) {
+	if m.VerifyNumberFunc == nil {
+		return false, notStubbed("Network.VerifyNumber")
+	}
+	return m.VerifyNumberFunc(ctx, token, phoneNumber)
+}
+
+// CIBAToken implements network.API
+func (m *Network) CIBAToken(ctx context.Context, phoneNumber string, scope network.Scope) (*network.Token, error) {
+	if m.CIBATokenFunc == nil {
+		return nil, notStubbed("Network.CIBAToken")
+	}
+	return m.CIBATokenFunc(ctx, phoneNumber, scope)
+}
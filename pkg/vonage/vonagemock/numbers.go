@@ -0,0 +1,59 @@
+package vonagemock
+
+import (
+	"context"
+
+	"github.com/vonatrigger/poc/pkg/vonage/numbers"
+)
+
+var _ numbers.API = (*Numbers)(nil)
+
+// Numbers is a stub numbers.API. Each method calls the matching Func field,
+// or returns ErrNotStubbed if it is nil.
+type Numbers struct {
+	SearchFunc func(ctx context.Context, country string, opts *numbers.SearchOptions) (*numbers.SearchResult, error)
+	ListFunc   func(ctx context.Context, opts *numbers.ListOptions) (*numbers.OwnedList, error)
+	BuyFunc    func(ctx context.Context, country, msisdn string) error
+	CancelFunc func(ctx context.Context, country, msisdn string) error
+	UpdateFunc func(ctx context.Context, country, msisdn string, opts *numbers.UpdateOptions) error
+}
+
+// Search implements numbers.API
+func (m *Numbers) Search(ctx context.Context, country string, opts *numbers.SearchOptions) (*numbers.SearchResult, error) {
+	if m.SearchFunc == nil {
+		return nil, notStubbed("Numbers.Search")
+	}
+	return m.SearchFunc(ctx, country, opts)
+}
+
+// List implements numbers.API
+func (m *Numbers) List(ctx context.Context, opts *numbers.ListOptions) (*numbers.OwnedList, error) {
+	if m.ListFunc == nil {
+		return nil, notStubbed("Numbers.List")
+	}
+	return m.ListFunc(ctx, opts)
+}
+
+// Buy implements numbers.API
+func (m *Numbers) Buy(ctx context.Context, country, msisdn string) error {
+	if m.BuyFunc == nil {
+		return notStubbed("Numbers.Buy")
+	}
+	return m.BuyFunc(ctx, country, msisdn)
+}
+
+// Cancel implements numbers.API
+func (m *Numbers) Cancel(ctx context.Context, country, msisdn string) error {
+	if m.CancelFunc == nil {
+		return notStubbed("Numbers.Cancel")
+	}
+	return m.CancelFunc(ctx, country, msisdn)
+}
+
+// Update implements numbers.API
+func (m *Numbers) Update(ctx context.Context, country, msisdn string, opts *numbers.UpdateOptions) error {
+	if m.UpdateFunc == nil {
+		return notStubbed("Numbers.Update")
+	}
+	return m.UpdateFunc(ctx, country, msisdn, opts)
+}
@@ -0,0 +1,150 @@
+package vonagemock
+
+import (
+	"context"
+	"io"
+
+	"github.com/vonatrigger/poc/pkg/vonage/proactive"
+)
+
+var _ proactive.API = (*Proactive)(nil)
+
+// Proactive is a stub proactive.API. Each method calls the matching Func field,
+// or returns ErrNotStubbed if it is nil.
+type Proactive struct {
+	CreateListFunc    func(ctx context.Context, list *proactive.List) (*proactive.List, error)
+	GetListFunc       func(ctx context.Context, listID string) (*proactive.List, error)
+	UpdateListFunc    func(ctx context.Context, listID string, list *proactive.List) (*proactive.List, error)
+	DeleteListFunc    func(ctx context.Context, listID string) error
+	ListListsFunc     func(ctx context.Context, opts *proactive.PageOptions) (*proactive.ListPage, error)
+	CreateItemFunc    func(ctx context.Context, listID string, data map[string]interface{}) (*proactive.Item, error)
+	GetItemFunc       func(ctx context.Context, listID, itemID string) (*proactive.Item, error)
+	UpdateItemFunc    func(ctx context.Context, listID, itemID string, data map[string]interface{}) (*proactive.Item, error)
+	DeleteItemFunc    func(ctx context.Context, listID, itemID string) error
+	ListItemsFunc     func(ctx context.Context, listID string, opts *proactive.PageOptions) (*proactive.ItemPage, error)
+	ImportItemsFunc   func(ctx context.Context, listID string, csv io.Reader) (*proactive.ImportResult, error)
+	DownloadItemsFunc func(ctx context.Context, listID string, w io.Writer) (int64, error)
+	ClearItemsFunc    func(ctx context.Context, listID string) error
+	FetchListFunc     func(ctx context.Context, listID string) error
+	ListEventsFunc    func(ctx context.Context, opts *proactive.EventOptions) (*proactive.EventPage, error)
+}
+
+// CreateList implements proactive.API
+func (m *Proactive) CreateList(ctx context.Context, list *proactive.List) (*proactive.List, error) {
+	if m.CreateListFunc == nil {
+		return nil, notStubbed("Proactive.CreateList")
+	}
+	return m.CreateListFunc(ctx, list)
+}
+
+// GetList implements proactive.API
+func (m *Proactive) GetList(ctx context.Context, listID string) (*proactive.List, error) {
+	if m.GetListFunc == nil {
+		return nil, notStubbed("Proactive.GetList")
+	}
+	return m.GetListFunc(ctx, listID)
+}
+
+// UpdateList implements proactive.API
+func (m *Proactive) UpdateList(ctx context.Context, listID string, list *proactive.List) (*proactive.List, error) {
+	if m.UpdateListFunc == nil {
+		return nil, notStubbed("Proactive.UpdateList")
+	}
+	return m.UpdateListFunc(ctx, listID, list)
+}
+
+// DeleteList implements proactive.API
+func (m *Proactive) DeleteList(ctx context.Context, listID string) error {
+	if m.DeleteListFunc == nil {
+		return notStubbed("Proactive.DeleteList")
+	}
+	return m.DeleteListFunc(ctx, listID)
+}
+
+// ListLists implements proactive.API
+func (m *Proactive) ListLists(ctx context.Context, opts *proactive.PageOptions) (*proactive.ListPage, error) {
+	if m.ListListsFunc == nil {
+		return nil, notStubbed("Proactive.ListLists")
+	}
+	return m.ListListsFunc(ctx, opts)
+}
+
+// CreateItem implements proactive.API
+func (m *Proactive) CreateItem(ctx context.Context, listID string, data map[string]interface{}) (*proactive.Item, error) {
+	if m.CreateItemFunc == nil {
+		return nil, notStubbed("Proactive.CreateItem")
+	}
+	return m.CreateItemFunc(ctx, listID, data)
+}
+
+// GetItem implements proactive.API
+func (m *Proactive) GetItem(ctx context.Context, listID, itemID string) (*proactive.Item, error) {
+	if m.GetItemFunc == nil {
+		return nil, notStubbed("Proactive.GetItem")
+	}
+	return m.GetItemFunc(ctx, listID, itemID)
+}
+
+// UpdateItem implements proactive.API
+func (m *Proactive) UpdateItem(ctx context.Context, listID, itemID string, data map[string]interface{}) (*proactive.Item, error) {
+	if m.UpdateItemFunc == nil {
+		return nil, notStubbed("Proactive.UpdateItem")
+	}
+	return m.UpdateItemFunc(ctx, listID, itemID, data)
+}
+
+// DeleteItem implements proactive.API
+func (m *Proactive) DeleteItem(ctx context.Context, listID, itemID string) error {
+	if m.DeleteItemFunc == nil {
+		return notStubbed("Proactive.DeleteItem")
+	}
+	return m.DeleteItemFunc(ctx, listID, itemID)
+}
+
+// ListItems implements proactive.API
+func (m *Proactive) ListItems(ctx context.Context, listID string, opts *proactive.PageOptions) (*proactive.ItemPage, error) {
+	if m.ListItemsFunc == nil {
+		return nil, notStubbed("Proactive.ListItems")
+	}
+	return m.ListItemsFunc(ctx, listID, opts)
+}
+
+// ImportItems implements proactive.API
+func (m *Proactive) ImportItems(ctx context.Context, listID string, csv io.Reader) (*proactive.ImportResult, error) {
+	if m.ImportItemsFunc == nil {
+		return nil, notStubbed("Proactive.ImportItems")
+	}
+	return m.ImportItemsFunc(ctx, listID, csv)
+}
+
+// DownloadItems implements proactive.API
+func (m *Proactive) DownloadItems(ctx context.Context, listID string, w io.Writer) (int64, error) {
+	if m.DownloadItemsFunc == nil {
+		return 0, notStubbed("Proactive.DownloadItems")
+	}
+	return m.DownloadItemsFunc(ctx, listID, w)
+}
+
+// ClearItems implements proactive.API
+func (m *Proactive) ClearItems(ctx context.Context, listID string) error {
+	if m.ClearItemsFunc == nil {
+		return notStubbed("Proactive.ClearItems")
+	}
+	return m.ClearItemsFunc(ctx, listID)
+}
+
+// FetchList implements proactive.API
+func (m *Proactive) FetchList(ctx context.Context, listID string) error {
+	if m.FetchListFunc == nil {
+		return notStubbed("Proactive.FetchList")
+	}
+	return m.FetchListFunc(ctx, listID)
+}
+
+// ListEvents implements proactive.API
+func (m *Proactive) ListEvents(ctx context.Context, opts *proactive.EventOptions) (*proactive.EventPage, error) {
+	if m.ListEventsFunc == nil {
+		return nil, notStubbed("Proactive.ListEvents")
+	}
+	return m.ListEventsFunc(ctx, opts)
+}
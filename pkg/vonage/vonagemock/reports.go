@@ -0,0 +1,71 @@
+package vonagemock
+
+import (
+	"context"
+	"io"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/reports"
+)
+
+var _ reports.API = (*Reports)(nil)
+
+// Reports is a stub reports.API. Each method calls the matching Func field,
+// or returns ErrNotStubbed if it is nil.
+type Reports struct {
+	CreateFunc       func(ctx context.Context, opts reports.CreateOptions) (*reports.Report, error)
+	GetFunc          func(ctx context.Context, requestID string) (*reports.Report, error)
+	CancelFunc       func(ctx context.Context, requestID string) error
+	WaitFunc         func(ctx context.Context, requestID string, interval time.Duration) (*reports.Report, error)
+	DownloadFunc     func(ctx context.Context, report *reports.Report, w io.Writer, opts ...vonage.DownloadOption) (int64, error)
+	DownloadFileFunc func(ctx context.Context, report *reports.Report, path string, opts ...vonage.DownloadOption) error
+}
+
+// Create implements reports.API
+func (m *Reports) Create(ctx context.Context, opts reports.CreateOptions) (*reports.Report, error) {
+	if m.CreateFunc == nil {
+		return nil, notStubbed("Reports.Create")
+	}
+	return m.CreateFunc(ctx, opts)
+}
+
+// Get implements reports.API
+func (m *Reports) Get(ctx context.Context, requestID string) (*reports.Report, error) {
+	if m.GetFunc == nil {
+		return nil, notStubbed("Reports.Get")
+	}
+	return m.GetFunc(ctx, requestID)
+}
+
+// Cancel implements reports.API
+func (m *Reports) Cancel(ctx context.Context, requestID string) error {
+	if m.CancelFunc == nil {
+		return notStubbed("Reports.Cancel")
+	}
+	return m.CancelFunc(ctx, requestID)
+}
+
+// Wait implements reports.API
+func (m *Reports) Wait(ctx context.Context, requestID string, interval time.Duration) (*reports.Report, error) {
+	if m.WaitFunc == nil {
+		return nil, notStubbed("Reports.Wait")
+	}
+	return m.WaitFunc(ctx, requestID, interval)
+}
+
+// Download implements reports.API
+func (m *Reports) Download(ctx context.Context, report *reports.Report, w io.Writer, opts ...vonage.DownloadOption) (int64, error) {
+	if m.DownloadFunc == nil {
+		return 0, notStubbed("Reports.Download")
+	}
+	return m.DownloadFunc(ctx, report, w, opts...)
+}
+
+// DownloadFile implements reports.API
+func (m *Reports) DownloadFile(ctx context.Context, report *reports.Report, path string, opts ...vonage.DownloadOption) error {
+	if m.DownloadFileFunc == nil {
+		return notStubbed("Reports.DownloadFile")
+	}
+	return m.DownloadFileFunc(ctx, report, path, opts...)
+}
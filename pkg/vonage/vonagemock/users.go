@@ -0,0 +1,68 @@
+package vonagemock
+
+import (
+	"context"
+
+	"github.com/vonatrigger/poc/pkg/vonage/users"
+)
+
+var _ users.API = (*Users)(nil)
+
+// Users is a stub users.API. Each method calls the matching Func field,
+// or returns ErrNotStubbed if it is nil.
+type Users struct {
+	CreateFunc    func(ctx context.Context, user *users.User) (*users.User, error)
+	GetFunc       func(ctx context.Context, userID string) (*users.User, error)
+	GetByNameFunc func(ctx context.Context, name string) (*users.User, error)
+	UpdateFunc    func(ctx context.Context, userID string, user *users.User) (*users.User, error)
+	DeleteFunc    func(ctx context.Context, userID string) error
+	ListFunc      func(ctx context.Context, opts *users.ListOptions) (*users.Page, error)
+}
+
+// Create implements users.API
+func (m *Users) Create(ctx context.Context, user *users.User) (*users.User, error) {
+	if m.CreateFunc == nil {
+		return nil, notStubbed("Users.Create")
+	}
+	return m.CreateFunc(ctx, user)
+}
+
+// Get implements users.API
+func (m *Users) Get(ctx context.Context, userID string) (*users.User, error) {
+	if m.GetFunc == nil {
+		return nil, notStubbed("Users.Get")
+	}
+	return m.GetFunc(ctx, userID)
+}
+
+// GetByName implements users.API
+func (m *Users) GetByName(ctx context.Context, name string) (*users.User, error) {
+	if m.GetByNameFunc == nil {
+		return nil, notStubbed("Users.GetByName")
+	}
+	return m.GetByNameFunc(ctx, name)
+}
+
+// Update implements users.API
+func (m *Users) Update(ctx context.Context, userID string, user *users.User) (*users.User, error) {
+	if m.UpdateFunc == nil {
+		return nil, notStubbed("Users.Update")
+	}
+	return m.UpdateFunc(ctx, userID, user)
+}
+
+// Delete implements users.API
+func (m *Users) Delete(ctx context.Context, userID string) error {
+	if m.DeleteFunc == nil {
+		return notStubbed("Users.Delete")
+	}
+	return m.DeleteFunc(ctx, userID)
+}
+
+// List implements users.API
+func (m *Users) List(ctx context.Context, opts *users.ListOptions) (*users.Page, error) {
+	if m.ListFunc == nil {
+		return nil, notStubbed("Users.List")
+	}
+	return m.ListFunc(ctx, opts)
+}
@@ -0,0 +1,41 @@
+package vonagemock
+
+import (
+	"context"
+
+	"github.com/vonatrigger/poc/pkg/vonage/verify"
+)
+
+var _ verify.API = (*Verify)(nil)
+
+// Verify is a stub verify.API. Each method calls the matching Func field,
+// or returns ErrNotStubbed if it is nil.
+type Verify struct {
+	StartVerificationFunc  func(ctx context.Context, number string, opts *verify.StartOptions) (*verify.StartResult, error)
+	CheckVerificationFunc  func(ctx context.Context, requestID, code string) (*verify.CheckResult, error)
+	CancelVerificationFunc func(ctx context.Context, requestID string) error
+}
+
+// StartVerification implements verify.API
+func (m *Verify) StartVerification(ctx context.Context, number string, opts *verify.StartOptions) (*verify.StartResult, error) {
+	if m.StartVerificationFunc == nil {
+		return nil, notStubbed("Verify.StartVerification")
+	}
+	return m.StartVerificationFunc(ctx, number, opts)
+}
+
+// CheckVerification implements verify.API
+func (m *Verify) CheckVerification(ctx context.Context, requestID, code string) (*verify.CheckResult, error) {
+	if m.CheckVerificationFunc == nil {
+		return nil, notStubbed("Verify.CheckVerification")
+	}
+	return m.CheckVerificationFunc(ctx, requestID, code)
+}
+
+// CancelVerification implements verify.API
+func (m *Verify) CancelVerification(ctx context.Context, requestID string) error {
+	if m.CancelVerificationFunc == nil {
+		return notStubbed("Verify.CancelVerification")
+	}
+	return m.CancelVerificationFunc(ctx, requestID)
+}
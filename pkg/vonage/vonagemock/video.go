@@ -0,0 +1,349 @@
+package vonagemock
+
+import (
+	"context"
+	"io"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/video"
+)
+
+var _ video.API = (*Video)(nil)
+
+// Video is a stub video.API. Each method calls the matching Func field,
+// or returns ErrNotStubbed if it is nil.
+type Video struct {
+	CreateSessionFunc        func(ctx context.Context, opts *video.CreateSessionOptions) (*video.Session, error)
+	CreateSessionForSpotFunc func(ctx context.Context, spotID string, opts *video.CreateSessionOptions) (*video.Session, error)
+	GetSessionFunc           func(ctx context.Context, sessionID string) (*video.Session, error)
+	GetOrCreateSessionFunc   func(ctx context.Context, spotID string, opts *video.CreateSessionOptions) (*video.Session, error)
+	FindSessionsByTagFunc    func(ctx context.Context, key, value string) ([]*video.Session, error)
+	StartArchiveFunc         func(ctx context.Context, sessionID string, opts *video.ArchiveOptions) (*video.Archive, error)
+	StopArchiveFunc          func(ctx context.Context, archiveID string) (*video.Archive, error)
+	GetArchiveFunc           func(ctx context.Context, archiveID string) (*video.Archive, error)
+	ListArchivesFunc         func(ctx context.Context, opts *video.ListOptions) (*video.ArchiveList, error)
+	DeleteArchiveFunc        func(ctx context.Context, archiveID string) error
+	SetArchiveLayoutFunc     func(ctx context.Context, archiveID string, layout video.Layout) error
+	DownloadArchiveFunc      func(ctx context.Context, archive *video.Archive, w io.Writer, opts ...vonage.DownloadOption) (int64, error)
+	StartBroadcastFunc       func(ctx context.Context, sessionID string, opts *video.BroadcastOptions) (*video.Broadcast, error)
+	StopBroadcastFunc        func(ctx context.Context, broadcastID string) (*video.Broadcast, error)
+	GetBroadcastFunc         func(ctx context.Context, broadcastID string) (*video.Broadcast, error)
+	ListBroadcastsFunc       func(ctx context.Context, opts *video.ListOptions) (*video.BroadcastList, error)
+	SetBroadcastLayoutFunc   func(ctx context.Context, broadcastID string, layout video.Layout) error
+	ForceDisconnectFunc      func(ctx context.Context, sessionID, connectionID string) error
+	MuteStreamFunc           func(ctx context.Context, sessionID, streamID string) error
+	MuteAllStreamsFunc       func(ctx context.Context, sessionID string, excludedStreamIDs ...string) error
+	DisableForceMuteFunc     func(ctx context.Context, sessionID string) error
+	DialFunc                 func(ctx context.Context, sessionID, token, sipURI string, opts *video.SIPOptions) (*video.SIPCall, error)
+	PlayDTMFFunc             func(ctx context.Context, sessionID, connectionID, digits string) error
+	PlayDTMFToSessionFunc    func(ctx context.Context, sessionID, digits string) error
+	HangupSIPCallFunc        func(ctx context.Context, sessionID string, call *video.SIPCall) error
+	EnableCaptionsFunc       func(ctx context.Context, sessionID, token string, opts *video.CaptionsOptions) (string, error)
+	DisableCaptionsFunc      func(ctx context.Context, captionsID string) error
+	GetCaptionsStatusFunc    func(ctx context.Context, captionsID string) (*video.CaptionsStatus, error)
+	StartRenderFunc          func(ctx context.Context, sessionID, token, pageURL string, opts *video.RenderOptions) (*video.Render, error)
+	StopRenderFunc           func(ctx context.Context, renderID string) error
+	GetRenderFunc            func(ctx context.Context, renderID string) (*video.Render, error)
+	ListRendersFunc          func(ctx context.Context, opts *video.ListOptions) (*video.RenderList, error)
+	SetArchiveStorageFunc    func(ctx context.Context, storage video.ArchiveStorage) error
+	DeleteArchiveStorageFunc func(ctx context.Context) error
+	RegisterCallbackFunc     func(ctx context.Context, group video.CallbackGroup, event video.CallbackEvent, callbackURL string) (*video.Callback, error)
+	ListCallbacksFunc        func(ctx context.Context) ([]video.Callback, error)
+	DeleteCallbackFunc       func(ctx context.Context, callbackID string) error
+}
+
+// CreateSession implements video.API
+func (m *Video) CreateSession(ctx context.Context, opts *video.CreateSessionOptions) (*video.Session, error) {
+	if m.CreateSessionFunc == nil {
+		return nil, notStubbed("Video.CreateSession")
+	}
+	return m.CreateSessionFunc(ctx, opts)
+}
+
+// CreateSessionForSpot implements video.API
+func (m *Video) CreateSessionForSpot(ctx context.Context, spotID string, opts *video.CreateSessionOptions) (*video.Session, error) {
+	if m.CreateSessionForSpotFunc == nil {
+		return nil, notStubbed("Video.CreateSessionForSpot")
+	}
+	return m.CreateSessionForSpotFunc(ctx, spotID, opts)
+}
+
+// GetSession implements video.API
+func (m *Video) GetSession(ctx context.Context, sessionID string) (*video.Session, error) {
+	if m.GetSessionFunc == nil {
+		return nil, notStubbed("Video.GetSession")
+	}
+	return m.GetSessionFunc(ctx, sessionID)
+}
+
+// GetOrCreateSession implements video.API
+func (m *Video) GetOrCreateSession(ctx context.Context, spotID string, opts *video.CreateSessionOptions) (*video.Session, error) {
+	if m.GetOrCreateSessionFunc == nil {
+		return nil, notStubbed("Video.GetOrCreateSession")
+	}
+	return m.GetOrCreateSessionFunc(ctx, spotID, opts)
+}
+
+// FindSessionsByTag implements video.API
+func (m *Video) FindSessionsByTag(ctx context.Context, key, value string) ([]*video.Session, error) {
+	if m.FindSessionsByTagFunc == nil {
+		return nil, notStubbed("Video.FindSessionsByTag")
+	}
+	return m.FindSessionsByTagFunc(ctx, key, value)
+}
+
+// StartArchive implements video.API
+func (m *Video) StartArchive(ctx context.Context, sessionID string, opts *video.ArchiveOptions) (*video.Archive, error) {
+	if m.StartArchiveFunc == nil {
+		return nil, notStubbed("Video.StartArchive")
+	}
+	return m.StartArchiveFunc(ctx, sessionID, opts)
+}
+
+// StopArchive implements video.API
+func (m *Video) StopArchive(ctx context.Context, archiveID string) (*video.Archive, error) {
+	if m.StopArchiveFunc == nil {
+		return nil, notStubbed("Video.StopArchive")
+	}
+	return m.StopArchiveFunc(ctx, archiveID)
+}
+
+// GetArchive implements video.API
+func (m *Video) GetArchive(ctx context.Context, archiveID string) (*video.Archive, error) {
+	if m.GetArchiveFunc == nil {
+		return nil, notStubbed("Video.GetArchive")
+	}
+	return m.GetArchiveFunc(ctx, archiveID)
+}
+
+// ListArchives implements video.API
+func (m *Video) ListArchives(ctx context.Context, opts *video.ListOptions) (*video.ArchiveList, error) {
+	if m.ListArchivesFunc == nil {
+		return nil, notStubbed("Video.ListArchives")
+	}
+	return m.ListArchivesFunc(ctx, opts)
+}
+
+// DeleteArchive implements video.API
+func (m *Video) DeleteArchive(ctx context.Context, archiveID string) error {
+	if m.DeleteArchiveFunc == nil {
+		return notStubbed("Video.DeleteArchive")
+	}
+	return m.DeleteArchiveFunc(ctx, archiveID)
+}
+
+// SetArchiveLayout implements video.API
+func (m *Video) SetArchiveLayout(ctx context.Context, archiveID string, layout video.Layout) error {
+	if m.SetArchiveLayoutFunc == nil {
+		return notStubbed("Video.SetArchiveLayout")
+	}
+	return m.SetArchiveLayoutFunc(ctx, archiveID, layout)
+}
+
+// DownloadArchive implements video.API
+func (m *Video) DownloadArchive(ctx context.Context, archive *video.Archive, w io.Writer, opts ...vonage.DownloadOption) (int64, error) {
+	if m.DownloadArchiveFunc == nil {
+		return 0, notStubbed("Video.DownloadArchive")
+	}
+	return m.DownloadArchiveFunc(ctx, archive, w, opts...)
+}
+
+// StartBroadcast implements video.API
+func (m *Video) StartBroadcast(ctx context.Context, sessionID string, opts *video.BroadcastOptions) (*video.Broadcast, error) {
+	if m.StartBroadcastFunc == nil {
+		return nil, notStubbed("Video.StartBroadcast")
+	}
+	return m.StartBroadcastFunc(ctx, sessionID, opts)
+}
+
+// StopBroadcast implements video.API
+func (m *Video) StopBroadcast(ctx context.Context, broadcastID string) (*video.Broadcast, error) {
+	if m.StopBroadcastFunc == nil {
+		return nil, notStubbed("Video.StopBroadcast")
+	}
+	return m.StopBroadcastFunc(ctx, broadcastID)
+}
+
+// GetBroadcast implements video.API
+func (m *Video) GetBroadcast(ctx context.Context, broadcastID string) (*video.Broadcast, error) {
+	if m.GetBroadcastFunc == nil {
+		return nil, notStubbed("Video.GetBroadcast")
+	}
+	return m.GetBroadcastFunc(ctx, broadcastID)
+}
+
+// ListBroadcasts implements video.API
+func (m *Video) ListBroadcasts(ctx context.Context, opts *video.ListOptions) (*video.BroadcastList, error) {
+	if m.ListBroadcastsFunc == nil {
+		return nil, notStubbed("Video.ListBroadcasts")
+	}
+	return m.ListBroadcastsFunc(ctx, opts)
+}
+
+// SetBroadcastLayout implements video.API
+func (m *Video) SetBroadcastLayout(ctx context.Context, broadcastID string, layout video.Layout) error {
+	if m.SetBroadcastLayoutFunc == nil {
+		return notStubbed("Video.SetBroadcastLayout")
+	}
+	return m.SetBroadcastLayoutFunc(ctx, broadcastID, layout)
+}
+
+// ForceDisconnect implements video.API
+func (m *Video) ForceDisconnect(ctx context.Context, sessionID, connectionID string) error {
+	if m.ForceDisconnectFunc == nil {
+		return notStubbed("Video.ForceDisconnect")
+	}
+	return m.ForceDisconnectFunc(ctx, sessionID, connectionID)
+}
+
+// MuteStream implements video.API
+func (m *Video) MuteStream(ctx context.Context, sessionID, streamID string) error {
+	if m.MuteStreamFunc == nil {
+		return notStubbed("Video.MuteStream")
+	}
+	return m.MuteStreamFunc(ctx, sessionID, streamID)
+}
+
+// MuteAllStreams implements video.API
+func (m *Video) MuteAllStreams(ctx context.Context, sessionID string, excludedStreamIDs ...string) error {
+	if m.MuteAllStreamsFunc == nil {
+		return notStubbed("Video.MuteAllStreams")
+	}
+	return m.MuteAllStreamsFunc(ctx, sessionID, excludedStreamIDs...)
+}
+
+// DisableForceMute implements video.API
+func (m *Video) DisableForceMute(ctx context.Context, sessionID string) error {
+	if m.DisableForceMuteFunc == nil {
+		return notStubbed("Video.DisableForceMute")
+	}
+	return m.DisableForceMuteFunc(ctx, sessionID)
+}
+
+// Dial implements video.API
+func (m *Video) Dial(ctx context.Context, sessionID, token, sipURI string, opts *video.SIPOptions) (*video.SIPCall, error) {
+	if m.DialFunc == nil {
+		return nil, notStubbed("Video.Dial")
+	}
+	return m.DialFunc(ctx, sessionID, token, sipURI, opts)
+}
+
+// PlayDTMF implements video.API
+func (m *Video) PlayDTMF(ctx context.Context, sessionID, connectionID, digits string) error {
+	if m.PlayDTMFFunc == nil {
+		return notStubbed("Video.PlayDTMF")
+	}
+	return m.PlayDTMFFunc(ctx, sessionID, connectionID, digits)
+}
+
+// PlayDTMFToSession implements video.API
+func (m *Video) PlayDTMFToSession(ctx context.Context, sessionID, digits string) error {
+	if m.PlayDTMFToSessionFunc == nil {
+		return notStubbed("Video.PlayDTMFToSession")
+	}
+	return m.PlayDTMFToSessionFunc(ctx, sessionID, digits)
+}
+
+// HangupSIPCall implements video.API
+func (m *Video) HangupSIPCall(ctx context.Context, sessionID string, call *video.SIPCall) error {
+	if m.HangupSIPCallFunc == nil {
+		return notStubbed("Video.HangupSIPCall")
+	}
+	return m.HangupSIPCallFunc(ctx, sessionID, call)
+}
+
+// EnableCaptions implements video.API
+func (m *Video) EnableCaptions(ctx context.Context, sessionID, token string, opts *video.CaptionsOptions) (string, error) {
+	if m.EnableCaptionsFunc == nil {
+		return "", notStubbed("Video.EnableCaptions")
+	}
+	return m.EnableCaptionsFunc(ctx, sessionID, token, opts)
+}
+
+// DisableCaptions implements video.API
+func (m *Video) DisableCaptions(ctx context.Context, captionsID string) error {
+	if m.DisableCaptionsFunc == nil {
+		return notStubbed("Video.DisableCaptions")
+	}
+	return m.DisableCaptionsFunc(ctx, captionsID)
+}
+
+// GetCaptionsStatus implements video.API
+func (m *Video) GetCaptionsStatus(ctx context.Context, captionsID string) (*video.CaptionsStatus, error) {
+	if m.GetCaptionsStatusFunc == nil {
+		return nil, notStubbed("Video.GetCaptionsStatus")
+	}
+	return m.GetCaptionsStatusFunc(ctx, captionsID)
+}
+
+// StartRender implements video.API
+func (m *Video) StartRender(ctx context.Context, sessionID, token, pageURL string, opts *video.RenderOptions) (*video.Render, error) {
+	if m.StartRenderFunc == nil {
+		return nil, notStubbed("Video.StartRender")
+	}
+	return m.StartRenderFunc(ctx, sessionID, token, pageURL, opts)
+}
+
+// StopRender implements video.API
+func (m *Video) StopRender(ctx context.Context, renderID string) error {
+	if m.StopRenderFunc == nil {
+		return notStubbed("Video.StopRender")
+	}
+	return m.StopRenderFunc(ctx, renderID)
+}
+
+// GetRender implements video.API
+func (m *Video) GetRender(ctx context.Context, renderID string) (*video.Render, error) {
+	if m.GetRenderFunc == nil {
+		return nil, notStubbed("Video.GetRender")
+	}
+	return m.GetRenderFunc(ctx, renderID)
+}
+
+// ListRenders implements video.API
+func (m *Video) ListRenders(ctx context.Context, opts *video.ListOptions) (*video.RenderList, error) {
+	if m.ListRendersFunc == nil {
+		return nil, notStubbed("Video.ListRenders")
+	}
+	return m.ListRendersFunc(ctx, opts)
+}
+
+// SetArchiveStorage implements video.API
+func (m *Video) SetArchiveStorage(ctx context.Context, storage video.ArchiveStorage) error {
+	if m.SetArchiveStorageFunc == nil {
+		return notStubbed("Video.SetArchiveStorage")
+	}
+	return m.SetArchiveStorageFunc(ctx, storage)
+}
+
+// DeleteArchiveStorage implements video.API
+func (m *Video) DeleteArchiveStorage(ctx context.Context) error {
+	if m.DeleteArchiveStorageFunc == nil {
+		return notStubbed("Video.DeleteArchiveStorage")
+	}
+	return m.DeleteArchiveStorageFunc(ctx)
+}
+
+// RegisterCallback implements video.API
+func (m *Video) RegisterCallback(ctx context.Context, group video.CallbackGroup, event video.CallbackEvent, callbackURL string) (*video.Callback, error) {
+	if m.RegisterCallbackFunc == nil {
+		return nil, notStubbed("Video.RegisterCallback")
+	}
+	return m.RegisterCallbackFunc(ctx, group, event, callbackURL)
+}
+
+// ListCallbacks implements video.API
+func (m *Video) ListCallbacks(ctx context.Context) ([]video.Callback, error) {
+	if m.ListCallbacksFunc == nil {
+		return nil, notStubbed("Video.ListCallbacks")
+	}
+	return m.ListCallbacksFunc(ctx)
+}
+
+// DeleteCallback implements video.API
+func (m *Video) DeleteCallback(ctx context.Context, callbackID string) error {
+	if m.DeleteCallbackFunc == nil {
+		return notStubbed("Video.DeleteCallback")
+	}
+	return m.DeleteCallbackFunc(ctx, callbackID)
+}
@@ -0,0 +1,187 @@
+package vonagemock
+
+import (
+	"context"
+	"io"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/voice"
+)
+
+var _ voice.API = (*Voice)(nil)
+
+// Voice is a stub voice.API. Each method calls the matching Func field,
+// or returns ErrNotStubbed if it is nil.
+type Voice struct {
+	CreateCallFunc             func(ctx context.Context, opts voice.CreateCallOptions) (*voice.CreateCallResponse, error)
+	CreateCallToPhoneFunc      func(ctx context.Context, toNumber, answerURL, eventURL string) (*voice.CreateCallResponse, error)
+	CreateCallWithNCCOFunc     func(ctx context.Context, toNumber string, ncco voice.NCCO, eventURL string) (*voice.CreateCallResponse, error)
+	GetCallInfoFunc            func(ctx context.Context, callUUID string) (*voice.CallInfo, error)
+	ListCallsFunc              func(ctx context.Context, opts *voice.ListCallsOptions) (*voice.CallList, error)
+	GetCallsByConversationFunc func(ctx context.Context, conversationUUID string) ([]voice.CallInfo, error)
+	GetConversationLegsFunc    func(ctx context.Context, conversationUUID string) (*voice.ConversationLegs, error)
+	TransferCallFunc           func(ctx context.Context, callUUID, nccoURL string) error
+	HangupCallFunc             func(ctx context.Context, callUUID string) error
+	MuteCallFunc               func(ctx context.Context, callUUID string) error
+	UnmuteCallFunc             func(ctx context.Context, callUUID string) error
+	EarmuffCallFunc            func(ctx context.Context, callUUID string) error
+	UnearmuffCallFunc          func(ctx context.Context, callUUID string) error
+	SendDTMFFunc               func(ctx context.Context, callUUID, digits string) error
+	TalkIntoCallFunc           func(ctx context.Context, callUUID string, opts voice.TalkIntoCallOptions) error
+	StopTalkFunc               func(ctx context.Context, callUUID string) error
+	StreamIntoCallFunc         func(ctx context.Context, callUUID string, opts voice.StreamIntoCallOptions) error
+	StopStreamFunc             func(ctx context.Context, callUUID string) error
+	DownloadRecordingFunc      func(ctx context.Context, recordingURL string, w io.Writer, opts ...vonage.DownloadOption) (int64, error)
+}
+
+// CreateCall implements voice.API
+func (m *Voice) CreateCall(ctx context.Context, opts voice.CreateCallOptions) (*voice.CreateCallResponse, error) {
+	if m.CreateCallFunc == nil {
+		return nil, notStubbed("Voice.CreateCall")
+	}
+	return m.CreateCallFunc(ctx, opts)
+}
+
+// CreateCallToPhone implements voice.API
+func (m *Voice) CreateCallToPhone(ctx context.Context, toNumber, answerURL, eventURL string) (*voice.CreateCallResponse, error) {
+	if m.CreateCallToPhoneFunc == nil {
+		return nil, notStubbed("Voice.CreateCallToPhone")
+	}
+	return m.CreateCallToPhoneFunc(ctx, toNumber, answerURL, eventURL)
+}
+
+// CreateCallWithNCCO implements voice.API
+func (m *Voice) CreateCallWithNCCO(ctx context.Context, toNumber string, ncco voice.NCCO, eventURL string) (*voice.CreateCallResponse, error) {
+	if m.CreateCallWithNCCOFunc == nil {
+		return nil, notStubbed("Voice.CreateCallWithNCCO")
+	}
+	return m.CreateCallWithNCCOFunc(ctx, toNumber, ncco, eventURL)
+}
+
+// GetCallInfo implements voice.API
+func (m *Voice) GetCallInfo(ctx context.Context, callUUID string) (*voice.CallInfo, error) {
+	if m.GetCallInfoFunc == nil {
+		return nil, notStubbed("Voice.GetCallInfo")
+	}
+	return m.GetCallInfoFunc(ctx, callUUID)
+}
+
+// ListCalls implements voice.API
+func (m *Voice) ListCalls(ctx context.Context, opts *voice.ListCallsOptions) (*voice.CallList, error) {
+	if m.ListCallsFunc == nil {
+		return nil, notStubbed("Voice.ListCalls")
+	}
+	return m.ListCallsFunc(ctx, opts)
+}
+
+// GetCallsByConversation implements voice.API
+func (m *Voice) GetCallsByConversation(ctx context.Context, conversationUUID string) ([]voice.CallInfo, error) {
+	if m.GetCallsByConversationFunc == nil {
+		return nil, notStubbed("Voice.GetCallsByConversation")
+	}
+	return m.GetCallsByConversationFunc(ctx, conversationUUID)
+}
+
+// GetConversationLegs implements voice.API
+func (m *Voice) GetConversationLegs(ctx context.Context, conversationUUID string) (*voice.ConversationLegs, error) {
+	if m.GetConversationLegsFunc == nil {
+		return nil, notStubbed("Voice.GetConversationLegs")
+	}
+	return m.GetConversationLegsFunc(ctx, conversationUUID)
+}
+
+// TransferCall implements voice.API
+func (m *Voice) TransferCall(ctx context.Context, callUUID, nccoURL string) error {
+	if m.TransferCallFunc == nil {
+		return notStubbed("Voice.TransferCall")
+	}
+	return m.TransferCallFunc(ctx, callUUID, nccoURL)
+}
+
+// HangupCall implements voice.API
+func (m *Voice) HangupCall(ctx context.Context, callUUID string) error {
+	if m.HangupCallFunc == nil {
+		return notStubbed("Voice.HangupCall")
+	}
+	return m.HangupCallFunc(ctx, callUUID)
+}
+
+// MuteCall implements voice.API
+func (m *Voice) MuteCall(ctx context.Context, callUUID string) error {
+	if m.MuteCallFunc == nil {
+		return notStubbed("Voice.MuteCall")
+	}
+	return m.MuteCallFunc(ctx, callUUID)
+}
+
+// UnmuteCall implements voice.API
+func (m *Voice) UnmuteCall(ctx context.Context, callUUID string) error {
+	if m.UnmuteCallFunc == nil {
+		return notStubbed("Voice.UnmuteCall")
+	}
+	return m.UnmuteCallFunc(ctx, callUUID)
+}
+
+// EarmuffCall implements voice.API
+func (m *Voice) EarmuffCall(ctx context.Context, callUUID string) error {
+	if m.EarmuffCallFunc == nil {
+		return notStubbed("Voice.EarmuffCall")
+	}
+	return m.EarmuffCallFunc(ctx, callUUID)
+}
+
+// UnearmuffCall implements voice.API
+func (m *Voice) UnearmuffCall(ctx context.Context, callUUID string) error {
+	if m.UnearmuffCallFunc == nil {
+		return notStubbed("Voice.UnearmuffCall")
+	}
+	return m.UnearmuffCallFunc(ctx, callUUID)
+}
+
+// SendDTMF implements voice.API
+func (m *Voice) SendDTMF(ctx context.Context, callUUID, digits string) error {
+	if m.SendDTMFFunc == nil {
+		return notStubbed("Voice.SendDTMF")
+	}
+	return m.SendDTMFFunc(ctx, callUUID, digits)
+}
+
+// TalkIntoCall implements voice.API
+func (m *Voice) TalkIntoCall(ctx context.Context, callUUID string, opts voice.TalkIntoCallOptions) error {
+	if m.TalkIntoCallFunc == nil {
+		return notStubbed("Voice.TalkIntoCall")
+	}
+	return m.TalkIntoCallFunc(ctx, callUUID, opts)
+}
+
+// StopTalk implements voice.API
+func (m *Voice) StopTalk(ctx context.Context, callUUID string) error {
+	if m.StopTalkFunc == nil {
+		return notStubbed("Voice.StopTalk")
+	}
+	return m.StopTalkFunc(ctx, callUUID)
+}
+
+// StreamIntoCall implements voice.API
+func (m *Voice) StreamIntoCall(ctx context.Context, callUUID string, opts voice.StreamIntoCallOptions) error {
+	if m.StreamIntoCallFunc == nil {
+		return notStubbed("Voice.StreamIntoCall")
+	}
+	return m.StreamIntoCallFunc(ctx, callUUID, opts)
+}
+
+// StopStream implements voice.API
+func (m *Voice) StopStream(ctx context.Context, callUUID string) error {
+	if m.StopStreamFunc == nil {
+		return notStubbed("Voice.StopStream")
+	}
+	return m.StopStreamFunc(ctx, callUUID)
+}
+
+// DownloadRecording implements voice.API
+func (m *Voice) DownloadRecording(ctx context.Context, recordingURL string, w io.Writer, opts ...vonage.DownloadOption) (int64, error) {
+	if m.DownloadRecordingFunc == nil {
+		return 0, notStubbed("Voice.DownloadRecording")
+	}
+	return m.DownloadRecordingFunc(ctx, recordingURL, w, opts...)
+}
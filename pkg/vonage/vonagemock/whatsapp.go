@@ -0,0 +1,41 @@
+package vonagemock
+
+import (
+	"context"
+
+	"github.com/vonatrigger/poc/pkg/vonage/whatsapp"
+)
+
+var _ whatsapp.API = (*WhatsApp)(nil)
+
+// WhatsApp is a stub whatsapp.API. Each method calls the matching Func field,
+// or returns ErrNotStubbed if it is nil.
+type WhatsApp struct {
+	ListTemplatesFunc  func(ctx context.Context, wabaID string, opts *whatsapp.ListOptions) (*whatsapp.Page, error)
+	CreateTemplateFunc func(ctx context.Context, wabaID string, t *whatsapp.Template) (*whatsapp.CreateResponse, error)
+	DeleteTemplateFunc func(ctx context.Context, wabaID, name string) error
+}
+
+// ListTemplates implements whatsapp.API
+func (m *WhatsApp) ListTemplates(ctx context.Context, wabaID string, opts *whatsapp.ListOptions) (*whatsapp.Page, error) {
+	if m.ListTemplatesFunc == nil {
+		return nil, notStubbed("WhatsApp.ListTemplates")
+	}
+	return m.ListTemplatesFunc(ctx, wabaID, opts)
+}
+
+// CreateTemplate implements whatsapp.API
+func (m *WhatsApp) CreateTemplate(ctx context.Context, wabaID string, t *whatsapp.Template) (*whatsapp.CreateResponse, error) {
+	if m.CreateTemplateFunc == nil {
+		return nil, notStubbed("WhatsApp.CreateTemplate")
+	}
+	return m.CreateTemplateFunc(ctx, wabaID, t)
+}
+
+// DeleteTemplate implements whatsapp.API
+func (m *WhatsApp) DeleteTemplate(ctx context.Context, wabaID, name string) error {
+	if m.DeleteTemplateFunc == nil {
+		return notStubbed("WhatsApp.DeleteTemplate")
+	}
+	return m.DeleteTemplateFunc(ctx, wabaID, name)
+}
@@ -0,0 +1,122 @@
+package vonagetest_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/vonatrigger/poc/pkg/vonage/messages"
+	"github.com/vonatrigger/poc/pkg/vonage/video"
+	"github.com/vonatrigger/poc/pkg/vonage/voice"
+	"github.com/vonatrigger/poc/pkg/vonage/vonagetest"
+)
+
+func ExampleServer_voice() {
+	srv := vonagetest.NewServer()
+	defer srv.Close()
+
+	// The application under test receives call events here
+	events := make(chan voice.CallEvent, 1)
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event voice.CallEvent
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	defer app.Close()
+
+	client, _ := voice.NewClientFromCredentials(srv.Credentials(), voice.WithBaseURL(srv.URL))
+
+	ctx := context.Background()
+	call, err := client.CreateCallToPhone(ctx, "15551234567", app.URL+"/answer", app.URL+"/event")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	// Drive the call through its lifecycle
+	_ = srv.EmitCallEvent(ctx, call.UUID, voice.CallStatusAnswered)
+	fmt.Println((<-events).Status)
+
+	_ = client.HangupCall(ctx, call.UUID)
+	info, _ := client.GetCallInfo(ctx, call.UUID)
+	fmt.Println(info.Status, srv.Calls()[0].Actions[0].Action)
+	// Output:
+	// answered
+	// completed hangup
+}
+
+func ExampleServer_messages() {
+	statuses := make(chan messages.Status, 1)
+	app := httptest.NewServer(messages.NewWebhookHandler().
+		OnStatus(func(s *messages.MessageStatus) error {
+			statuses <- s.Status
+			return nil
+		}).
+		HandleStatus())
+	defer app.Close()
+
+	// Status webhooks go to the application's status URL
+	srv := vonagetest.NewServer(vonagetest.WithStatusURL(app.URL))
+	defer srv.Close()
+
+	client, _ := messages.NewClientFromCredentials(srv.Credentials(), messages.WithBaseURL(srv.URL))
+
+	ctx := context.Background()
+	resp, _ := client.SendSMS(ctx, "15551234567", "Your table is ready")
+	if err := srv.EmitMessageStatus(ctx, resp.MessageUUID, messages.StatusDelivered); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(srv.Messages()[0].Request.Text, <-statuses)
+	// Output: Your table is ready delivered
+}
+
+func ExampleServer_video() {
+	srv := vonagetest.NewServer()
+	defer srv.Close()
+
+	client, _ := video.NewClientFromCredentials(srv.Credentials(), video.WithBaseURL(srv.URL))
+
+	session, err := client.CreateSessionForSpot(context.Background(), "spot-1", nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(session.IsMock, session.SessionID == srv.Sessions()[0].ID)
+	// Output: false true
+}
+
+func ExampleRecorder() {
+	cassette := filepath.Join(os.TempDir(), "vonagetest-example-calls.json")
+	defer os.Remove(cassette)
+
+	// Record against a real (here: fake) API. In a real suite the mode
+	// comes from vonagetest.ModeFromEnv().
+	srv := vonagetest.NewServer()
+	rec, _ := vonagetest.NewRecorder(cassette, vonagetest.ModeRecord)
+	client, _ := voice.NewClientFromCredentials(srv.Credentials(),
+		voice.WithBaseURL(srv.URL),
+		voice.WithMiddleware(rec.Middleware()),
+	)
+	_, _ = client.CreateCallToPhone(context.Background(), "15551234567", "https://example.com/answer", "")
+	_ = rec.Save()
+	srv.Close()
+
+	// Replay in CI without network access or real credentials
+	rec, _ = vonagetest.NewRecorder(cassette, vonagetest.ModeReplay)
+	client, _ = voice.NewClientFromCredentials(srv.Credentials(),
+		voice.WithMiddleware(rec.Middleware()),
+	)
+	call, err := client.CreateCallToPhone(context.Background(), "15551234567", "https://example.com/answer", "")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(call.Status)
+	// Output: started
+}
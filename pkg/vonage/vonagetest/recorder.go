@@ -0,0 +1,305 @@
+package vonagetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+// ========================================
+// Record / Replay
+// ========================================
+
+// EnvRecord selects ModeRecord in ModeFromEnv when set to a non-empty value
+const EnvRecord = "VONAGE_RECORD"
+
+// ErrNoInteraction is returned in replay mode when no recorded interaction
+// matches a request
+var ErrNoInteraction = errors.New("vonagetest: no recorded interaction matches request")
+
+// Mode selects whether a Recorder captures or replays interactions
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette without network access
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the real API and captures them
+	ModeRecord
+)
+
+// ModeFromEnv returns ModeRecord if VONAGE_RECORD is set, so CI replays
+// by default and a developer re-records with VONAGE_RECORD=1
+func ModeFromEnv() Mode {
+	if os.Getenv(EnvRecord) != "" {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the sanitized request of an interaction. The host is
+// not recorded, so cassettes replay against any base URL.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse is the sanitized response of an interaction
+type RecordedResponse struct {
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+}
+
+// Sanitizer rewrites a request or response body before it is recorded or
+// matched
+type Sanitizer func(body string) string
+
+var (
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	numberPattern = regexp.MustCompile(`"\+?[0-9]{10,15}"`)
+)
+
+// DefaultSanitizer redacts JWTs and phone numbers held in JSON strings
+func DefaultSanitizer(body string) string {
+	body = jwtPattern.ReplaceAllString(body, "REDACTED_JWT")
+	return numberPattern.ReplaceAllString(body, `"REDACTED_NUMBER"`)
+}
+
+// Query and form fields that are always redacted: the API key, the
+// vonage.DefaultSecretFields and the vonage.DefaultNumberFields
+var (
+	recordedSecretFields = fieldSet(append([]string{"api_key"}, vonage.DefaultSecretFields...))
+	recordedNumberFields = fieldSet(vonage.DefaultNumberFields)
+)
+
+func fieldSet(fields []string) map[string]bool {
+	set := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		set[f] = true
+	}
+	return set
+}
+
+// sanitizeValues redacts the secrets and phone numbers in query or form
+// values, which the numbers, account and verify APIs carry there, and
+// encodes them with sorted keys
+func sanitizeValues(values url.Values) string {
+	for key, vals := range values {
+		switch k := strings.ToLower(key); {
+		case recordedSecretFields[k]:
+			for i := range vals {
+				vals[i] = "REDACTED"
+			}
+		case recordedNumberFields[k]:
+			for i := range vals {
+				vals[i] = "REDACTED_NUMBER"
+			}
+		}
+	}
+	return values.Encode()
+}
+
+// isForm reports whether a request body is form encoded
+func isForm(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/x-www-form-urlencoded"
+}
+
+// Recorder is a transport middleware that records API interactions to a
+// JSON cassette file, or replays them from it. Authorization headers are
+// never recorded, and API keys, secrets, signatures and phone numbers in
+// the query string or a form body are always redacted.
+type Recorder struct {
+	path      string
+	mode      Mode
+	sanitizer Sanitizer
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// RecorderOption is a functional option for configuring a Recorder
+type RecorderOption func(*Recorder)
+
+// WithSanitizer replaces DefaultSanitizer. Chain DefaultSanitizer inside
+// it to keep the default redactions.
+func WithSanitizer(s Sanitizer) RecorderOption {
+	return func(r *Recorder) {
+		r.sanitizer = s
+	}
+}
+
+// NewRecorder creates a recorder for the cassette at path. In replay mode
+// the cassette is loaded and must exist.
+func NewRecorder(path string, mode Mode, opts ...RecorderOption) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		sanitizer: DefaultSanitizer,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("vonagetest: failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.interactions); err != nil {
+			return nil, fmt.Errorf("vonagetest: failed to parse cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.interactions))
+	}
+
+	return r, nil
+}
+
+// Mode returns the recorder's mode
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Middleware returns the transport middleware. Add it last so it sees the
+// request as sent and other middleware still runs on replay.
+func (r *Recorder) Middleware() vonage.Middleware {
+	return func(next vonage.RoundTripFunc) vonage.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			recorded, err := r.recordRequest(req)
+			if err != nil {
+				return nil, err
+			}
+
+			if r.mode == ModeReplay {
+				return r.replay(req, recorded)
+			}
+
+			resp, err := next(req)
+			if err != nil {
+				return nil, err
+			}
+			return r.record(recorded, resp)
+		}
+	}
+}
+
+// Save writes the recorded interactions to the cassette (record mode only)
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("vonagetest: failed to encode cassette: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("vonagetest: failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// recordRequest captures and sanitizes req, restoring its body
+func (r *Recorder) recordRequest(req *http.Request) (RecordedRequest, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return RecordedRequest{}, fmt.Errorf("vonagetest: failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	recorded := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  sanitizeValues(req.URL.Query()),
+		Body:   string(body),
+	}
+	if len(body) > 0 && isForm(req.Header.Get("Content-Type")) {
+		if form, err := url.ParseQuery(recorded.Body); err == nil {
+			recorded.Body = sanitizeValues(form)
+		}
+	}
+	recorded.Body = r.sanitizer(recorded.Body)
+	return recorded, nil
+}
+
+// record stores the interaction and returns resp with its body restored
+func (r *Recorder) record(recorded RecordedRequest, resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("vonagetest: failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        r.sanitizer(string(body)),
+		},
+	})
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// replay serves the first unused interaction matching the request
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.used[i] || interaction.Request != recorded {
+			continue
+		}
+		r.used[i] = true
+
+		resp := &http.Response{
+			StatusCode: interaction.Response.StatusCode,
+			Status:     fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+			Request:    req,
+		}
+		if interaction.Response.ContentType != "" {
+			resp.Header.Set("Content-Type", interaction.Response.ContentType)
+		}
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, recorded.Method, recorded.Path)
+}
@@ -0,0 +1,76 @@
+package vonagetest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vonatrigger/poc/pkg/vonage/numbers"
+)
+
+func TestRecorderRedactsQueryAndForm(t *testing.T) {
+	const (
+		apiKey    = "a1b2c3d4"
+		apiSecret = "S3cr3tValue99"
+		msisdn    = "447700900123"
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"error-code":"200","error-code-label":"success"}`))
+			return
+		}
+		w.Write([]byte(`{"count":0,"numbers":[]}`))
+	}))
+	defer srv.Close()
+
+	cassette := filepath.Join(t.TempDir(), "numbers.json")
+	rec, err := NewRecorder(cassette, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := numbers.NewClient(apiKey, apiSecret,
+		numbers.WithBaseURL(srv.URL),
+		numbers.WithMiddleware(rec.Middleware()),
+	)
+	ctx := context.Background()
+	if err := client.Buy(ctx, "GB", msisdn); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.List(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{apiKey, apiSecret, msisdn} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, data)
+		}
+	}
+	if !strings.Contains(string(data), "api_secret=REDACTED") {
+		t.Errorf("cassette does not record the redacted field:\n%s", data)
+	}
+
+	// The redacted cassette still replays the same calls
+	rec, err = NewRecorder(cassette, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = numbers.NewClient(apiKey, apiSecret, numbers.WithMiddleware(rec.Middleware()))
+	if err := client.Buy(ctx, "GB", msisdn); err != nil {
+		t.Errorf("replayed Buy() = %v", err)
+	}
+	if _, err := client.List(ctx, nil); err != nil {
+		t.Errorf("replayed List() = %v", err)
+	}
+}
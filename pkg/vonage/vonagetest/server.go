@@ -0,0 +1,529 @@
+// Package vonagetest provides an in-memory fake of the Vonage APIs for
+// end-to-end tests that run without real credentials. Point the clients'
+// WithBaseURL at Server.URL and use Server.Credentials; the server records
+// every request and can POST webhook events back to the URLs it was given.
+//
+// It replaces the video client's WithMockFallback for tests: sessions are
+// created through the real API code path.
+//
+// Recorder captures interactions with the real APIs to sanitized cassette
+// files and replays them as transport middleware.
+package vonagetest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/messages"
+	"github.com/vonatrigger/poc/pkg/vonage/voice"
+)
+
+// ========================================
+// Server
+// ========================================
+
+// Call is a call created on the fake server
+type Call struct {
+	Request voice.CreateCallRequest
+	Info    voice.CallInfo
+	// Actions are the in-call requests (hangup, talk, stream, dtmf...)
+	Actions []CallAction
+}
+
+// CallAction is an in-call request made against a call
+type CallAction struct {
+	Method string
+	// Action is the path suffix ("talk", "stream", "dtmf"), or the action
+	// field of a PUT on the call itself ("hangup", "mute", "transfer"...)
+	Action string
+	Body   json.RawMessage
+}
+
+// Message is a message sent through the fake server
+type Message struct {
+	UUID    string
+	Request messages.SendRequest
+}
+
+// Session is a video session created on the fake server
+type Session struct {
+	ID      string
+	Options url.Values
+}
+
+// Server is a fake Vonage API server backed by httptest.Server
+type Server struct {
+	*httptest.Server
+
+	statusURL     string
+	webhookClient *http.Client
+	credentials   *vonage.Credentials
+
+	mu       sync.Mutex
+	calls    map[string]*Call
+	order    []string
+	messages []Message
+	sessions []Session
+	failures []failure
+}
+
+// failure is a queued error response for FailNext
+type failure struct {
+	status int
+	body   string
+}
+
+// Option is a functional option for configuring the server
+type Option func(*Server)
+
+// WithStatusURL sets the message status webhook URL used when a send
+// request has no webhook_url, like the application's status URL
+func WithStatusURL(url string) Option {
+	return func(s *Server) {
+		s.statusURL = url
+	}
+}
+
+// WithWebhookClient sets the HTTP client used to deliver webhooks
+func WithWebhookClient(httpClient *http.Client) Option {
+	return func(s *Server) {
+		s.webhookClient = httpClient
+	}
+}
+
+// NewServer starts a fake Vonage server. Close it when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		webhookClient: &http.Client{Timeout: 5 * time.Second},
+		calls:         make(map[string]*Call),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/calls", s.handleCreateCall)
+	mux.HandleFunc("GET /v1/calls", s.handleListCalls)
+	mux.HandleFunc("GET /v1/calls/{uuid}", s.handleGetCall)
+	mux.HandleFunc("PUT /v1/calls/{uuid}", s.handleCallAction)
+	mux.HandleFunc("PUT /v1/calls/{uuid}/{action}", s.handleCallAction)
+	mux.HandleFunc("DELETE /v1/calls/{uuid}/{action}", s.handleCallAction)
+	mux.HandleFunc("POST /v1/messages", s.handleSendMessage)
+	mux.HandleFunc("POST /session/create", s.handleCreateSession)
+
+	s.Server = httptest.NewServer(s.authenticate(mux))
+	return s
+}
+
+// Credentials returns application credentials with a generated key that
+// the server accepts
+func (s *Server) Credentials() *vonage.Credentials {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.credentials == nil {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			panic(fmt.Sprintf("vonagetest: failed to generate key: %v", err))
+		}
+		s.credentials, _ = vonage.NewCredentials(
+			vonage.WithApplication("vonagetest-app", ""),
+			vonage.WithPrivateKey(key),
+			vonage.WithPhoneNumber("15550000000"),
+		)
+	}
+	return s.credentials
+}
+
+// FailNext makes the next API request fail with status and body
+func (s *Server) FailNext(status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{status: status, body: body})
+}
+
+// Calls returns the calls created so far, oldest first
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calls := make([]Call, 0, len(s.order))
+	for _, uuid := range s.order {
+		calls = append(calls, *s.calls[uuid])
+	}
+	return calls
+}
+
+// Messages returns the messages sent so far, oldest first
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// Sessions returns the video sessions created so far, oldest first
+func (s *Server) Sessions() []Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Session(nil), s.sessions...)
+}
+
+// Reset forgets all recorded calls, messages, sessions and queued failures
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = make(map[string]*Call)
+	s.order = nil
+	s.messages = nil
+	s.sessions = nil
+	s.failures = nil
+}
+
+// ========================================
+// Webhook Emission
+// ========================================
+
+// EmitCallEvent moves a call to status and POSTs the event to the call's
+// event URL (no-op if it has none)
+func (s *Server) EmitCallEvent(ctx context.Context, callUUID string, status voice.CallStatus) error {
+	s.mu.Lock()
+	call, ok := s.calls[callUUID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("vonagetest: unknown call %q", callUUID)
+	}
+	call.Info.Status = status
+	if status == voice.CallStatusCompleted {
+		call.Info.EndTime = time.Now().UTC()
+	}
+	event := voice.CallEvent{
+		UUID:             call.Info.UUID,
+		ConversationUUID: call.Info.ConversationUUID,
+		Status:           status,
+		Direction:        string(call.Info.Direction),
+		Timestamp:        time.Now().UTC().Format(time.RFC3339Nano),
+		From:             call.Info.From.Number,
+		To:               call.Info.To.Number,
+	}
+	var eventURL string
+	if len(call.Request.EventURL) > 0 {
+		eventURL = call.Request.EventURL[0]
+	}
+	s.mu.Unlock()
+
+	if eventURL == "" {
+		return nil
+	}
+	return s.PostWebhook(ctx, eventURL, event)
+}
+
+// EmitMessageStatus POSTs a status update for a sent message to its
+// webhook_url, or to the WithStatusURL default
+func (s *Server) EmitMessageStatus(ctx context.Context, messageUUID string, status messages.Status) error {
+	s.mu.Lock()
+	var msg *Message
+	for i := range s.messages {
+		if s.messages[i].UUID == messageUUID {
+			msg = &s.messages[i]
+			break
+		}
+	}
+	if msg == nil {
+		s.mu.Unlock()
+		return fmt.Errorf("vonagetest: unknown message %q", messageUUID)
+	}
+	statusURL := msg.Request.WebhookURL
+	if statusURL == "" {
+		statusURL = s.statusURL
+	}
+	event := messages.MessageStatus{
+		MessageUUID: msg.UUID,
+		To:          msg.Request.To,
+		From:        msg.Request.From,
+		Timestamp:   time.Now().UTC(),
+		Status:      status,
+		Channel:     msg.Request.Channel,
+		ClientRef:   msg.Request.ClientRef,
+	}
+	s.mu.Unlock()
+
+	if statusURL == "" {
+		return fmt.Errorf("vonagetest: no status URL for message %q", messageUUID)
+	}
+	return s.PostWebhook(ctx, statusURL, event)
+}
+
+// PostWebhook POSTs payload as JSON to url, like Vonage delivering a
+// webhook. Any 2xx response is success.
+func (s *Server) PostWebhook(ctx context.Context, url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("vonagetest: failed to marshal webhook: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("vonagetest: failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.webhookClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("vonagetest: webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("vonagetest: webhook %s returned status %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// ========================================
+// Handlers
+// ========================================
+
+// authenticate rejects requests without credentials and serves queued
+// failures
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Bearer ") && !strings.HasPrefix(auth, "Basic ") {
+			writeProblem(w, http.StatusUnauthorized, "Unauthorized", "missing or invalid credentials")
+			return
+		}
+
+		s.mu.Lock()
+		var f *failure
+		if len(s.failures) > 0 {
+			f = &s.failures[0]
+			s.failures = s.failures[1:]
+		}
+		s.mu.Unlock()
+
+		if f != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(f.status)
+			io.WriteString(w, f.body)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleCreateCall(w http.ResponseWriter, r *http.Request) {
+	var req voice.CreateCallRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, http.StatusBadRequest, "Bad Request", err.Error())
+		return
+	}
+	if len(req.To) == 0 {
+		writeProblem(w, http.StatusBadRequest, "Bad Request", "to is required")
+		return
+	}
+	if req.NCCO == nil && len(req.AnswerURL) == 0 {
+		writeProblem(w, http.StatusBadRequest, "Bad Request", "ncco or answer_url is required")
+		return
+	}
+
+	call := &Call{
+		Request: req,
+		Info: voice.CallInfo{
+			UUID:             newUUID(),
+			Status:           voice.CallStatusStarted,
+			Direction:        voice.CallDirectionOutbound,
+			ConversationUUID: "CON-" + newUUID(),
+			StartTime:        time.Now().UTC(),
+			To:               req.To[0],
+			From:             req.From,
+		},
+	}
+
+	s.mu.Lock()
+	s.calls[call.Info.UUID] = call
+	s.order = append(s.order, call.Info.UUID)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, voice.CreateCallResponse{
+		UUID:             call.Info.UUID,
+		Status:           string(call.Info.Status),
+		Direction:        string(call.Info.Direction),
+		ConversationUUID: call.Info.ConversationUUID,
+	})
+}
+
+func (s *Server) handleGetCall(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	call, ok := s.calls[r.PathValue("uuid")]
+	var info voice.CallInfo
+	if ok {
+		info = call.Info
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeProblem(w, http.StatusNotFound, "Not Found", "call not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+func (s *Server) handleListCalls(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	index, _ := strconv.Atoi(q.Get("record_index"))
+	size, _ := strconv.Atoi(q.Get("page_size"))
+	if size <= 0 {
+		size = 10
+	}
+
+	s.mu.Lock()
+	var matched []voice.CallInfo
+	for _, id := range s.order {
+		info := s.calls[id].Info
+		if c := q.Get("conversation_uuid"); c != "" && info.ConversationUUID != c {
+			continue
+		}
+		if st := q.Get("status"); st != "" && string(info.Status) != st {
+			continue
+		}
+		matched = append(matched, info)
+	}
+	s.mu.Unlock()
+
+	page := matched[min(index, len(matched)):min(index+size, len(matched))]
+	resp := map[string]interface{}{
+		"count":        len(matched),
+		"page_size":    size,
+		"record_index": index,
+		"_embedded":    map[string]interface{}{"calls": page},
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleCallAction(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	action := r.PathValue("action")
+	if action == "" {
+		var probe struct {
+			Action string `json:"action"`
+		}
+		json.Unmarshal(body, &probe)
+		action = probe.Action
+	}
+
+	s.mu.Lock()
+	call, ok := s.calls[r.PathValue("uuid")]
+	if ok {
+		call.Actions = append(call.Actions, CallAction{
+			Method: r.Method,
+			Action: action,
+			Body:   json.RawMessage(body),
+		})
+		if action == "hangup" {
+			call.Info.Status = voice.CallStatusCompleted
+			call.Info.EndTime = time.Now().UTC()
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		writeProblem(w, http.StatusNotFound, "Not Found", "call not found")
+		return
+	}
+
+	if r.PathValue("action") == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{
+		"message": action + " " + strings.ToLower(r.Method),
+		"uuid":    call.Info.UUID,
+	})
+}
+
+func (s *Server) handleSendMessage(w http.ResponseWriter, r *http.Request) {
+	var req messages.SendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, http.StatusBadRequest, "Bad Request", err.Error())
+		return
+	}
+	if req.To == "" || req.From == "" || req.Channel == "" {
+		writeProblem(w, http.StatusUnprocessableEntity, "Invalid params", "to, from and channel are required")
+		return
+	}
+
+	msg := Message{UUID: newUUID(), Request: req}
+
+	s.mu.Lock()
+	s.messages = append(s.messages, msg)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusAccepted, messages.SendResponse{MessageUUID: msg.UUID})
+}
+
+func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeProblem(w, http.StatusBadRequest, "Bad Request", err.Error())
+		return
+	}
+
+	session := Session{
+		ID:      "2_MX4" + strings.ReplaceAll(newUUID(), "-", ""),
+		Options: r.PostForm,
+	}
+
+	s.mu.Lock()
+	s.sessions = append(s.sessions, session)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, []map[string]string{{
+		"session_id": session.ID,
+		"project_id": "vonagetest-app",
+	}})
+}
+
+// ========================================
+// Helpers
+// ========================================
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeProblem writes an RFC 7807 error body like the real APIs
+func writeProblem(w http.ResponseWriter, status int, title, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"type":   "https://developer.vonage.com/api-errors",
+		"title":  title,
+		"detail": detail,
+	})
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
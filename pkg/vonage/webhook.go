@@ -0,0 +1,501 @@
+package vonage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ========================================
+// Webhook Verification
+// ========================================
+
+// DefaultWebhookTolerance is a reasonable timestamp tolerance for
+// WithTimestampTolerance, allowing for clock skew and delivery delay
+const DefaultWebhookTolerance = 5 * time.Minute
+
+var (
+	// ErrWebhookExpired is returned when a webhook's timestamp is outside
+	// the verifier's tolerance. It wraps ErrInvalidSignature.
+	ErrWebhookExpired = fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	// ErrWebhookReplayed is returned when a webhook's jti or nonce has been
+	// seen before. It wraps ErrInvalidSignature.
+	ErrWebhookReplayed = fmt.Errorf("%w: webhook replayed", ErrInvalidSignature)
+)
+
+// ReplayCache remembers the IDs (JWT jti or legacy nonce) of verified
+// webhooks. Deployments with several instances need a shared
+// implementation, e.g. Redis SET NX with an expiry.
+type ReplayCache interface {
+	// Seen records id until expiresAt and reports whether it was already
+	// recorded. It must be atomic.
+	Seen(id string, expiresAt time.Time) bool
+}
+
+// MemoryReplayCache is an in-process ReplayCache
+type MemoryReplayCache struct {
+	mu        sync.Mutex
+	ids       map[string]time.Time
+	lastPurge time.Time
+}
+
+// NewMemoryReplayCache creates an empty in-process replay cache
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{ids: make(map[string]time.Time)}
+}
+
+// Seen implements ReplayCache. Expired IDs are purged at most once a
+// minute.
+func (c *MemoryReplayCache) Seen(id string, expiresAt time.Time) bool {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.lastPurge) > time.Minute {
+		for k, exp := range c.ids {
+			if now.After(exp) {
+				delete(c.ids, k)
+			}
+		}
+		c.lastPurge = now
+	}
+
+	if exp, ok := c.ids[id]; ok && now.Before(exp) {
+		return true
+	}
+	c.ids[id] = expiresAt
+	return false
+}
+
+// WebhookVerifier verifies webhook signatures and, optionally, rejects
+// stale or replayed webhooks. Without options it only checks signatures,
+// like VerifyWebhook.
+//
+// Vonage retries webhooks that did not get a 2xx response. A retry may
+// carry the original token, so with replay protection a retried webhook is
+// rejected: acknowledge webhooks quickly and process them asynchronously.
+type WebhookVerifier struct {
+	secret    string
+	method    SignatureMethod
+	tolerance time.Duration
+	replay    ReplayCache
+}
+
+// WebhookVerifierOption is a functional option for configuring a verifier
+type WebhookVerifierOption func(*WebhookVerifier)
+
+// WithTimestampTolerance rejects webhooks whose iat claim (or legacy
+// timestamp field) is more than d from now with ErrWebhookExpired
+func WithTimestampTolerance(d time.Duration) WebhookVerifierOption {
+	return func(v *WebhookVerifier) {
+		v.tolerance = d
+	}
+}
+
+// WithReplayCache rejects webhooks whose jti claim (or legacy nonce field)
+// is already in c with ErrWebhookReplayed. IDs are kept for the timestamp
+// tolerance (or DefaultWebhookTolerance if none is set) from when they are
+// seen, or from their timestamp if it lies in the future.
+func WithReplayCache(c ReplayCache) WebhookVerifierOption {
+	return func(v *WebhookVerifier) {
+		v.replay = c
+	}
+}
+
+// NewWebhookVerifier creates a verifier for the credentials' signature
+// secret
+func NewWebhookVerifier(creds *Credentials, opts ...WebhookVerifierOption) *WebhookVerifier {
+	v := &WebhookVerifier{
+		secret: creds.SignatureSecret,
+		method: creds.SignatureMethod,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// Enabled reports whether v has a signature secret to verify with. A nil
+// verifier is disabled.
+func (v *WebhookVerifier) Enabled() bool {
+	return v != nil && v.secret != ""
+}
+
+// Verify checks a webhook's signature, then its timestamp and ID if
+// configured. authorization is the request's Authorization header.
+func (v *WebhookVerifier) Verify(authorization string, body []byte) error {
+	var (
+		id string
+		ts time.Time
+	)
+
+	if authorization != "" {
+		claims, err := parseWebhookJWT(authorization, body, v.secret)
+		if err != nil {
+			return err
+		}
+		id, _ = claims["jti"].(string)
+		if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+			ts = iat.Time
+		}
+	} else {
+		params, err := webhookParams(body)
+		if err != nil {
+			return err
+		}
+		if err := VerifySignedParams(params, v.secret, v.method); err != nil {
+			return err
+		}
+		id = params["nonce"]
+		if sec, err := strconv.ParseInt(params["timestamp"], 10, 64); err == nil {
+			ts = time.Unix(sec, 0)
+		}
+	}
+
+	if v.tolerance > 0 {
+		if ts.IsZero() {
+			return fmt.Errorf("%w: missing timestamp", ErrWebhookExpired)
+		}
+		if math.Abs(float64(time.Since(ts))) > float64(v.tolerance) {
+			return ErrWebhookExpired
+		}
+	}
+
+	if v.replay != nil {
+		if id == "" {
+			return fmt.Errorf("%w: missing jti or nonce", ErrInvalidSignature)
+		}
+		window := v.tolerance
+		if window <= 0 {
+			window = DefaultWebhookTolerance
+		}
+		// Keep the ID for the window from now, not from the timestamp, so
+		// an old webhook is not recorded already expired
+		expires := time.Now()
+		if ts.After(expires) {
+			expires = ts
+		}
+		if v.replay.Seen(id, expires.Add(window)) {
+			return ErrWebhookReplayed
+		}
+	}
+
+	return nil
+}
+
+// ========================================
+// Webhook Router
+// ========================================
+
+// WebhookPaths are the paths the router mounts each webhook on, relative
+// to its prefix. Configure the same URLs on the Vonage application.
+type WebhookPaths struct {
+	VoiceAnswer     string
+	VoiceEvent      string
+	MessagesInbound string
+	MessagesStatus  string
+	Video           string
+	Verify          string
+	RTC             string
+}
+
+// DefaultWebhookPaths are the paths used for fields left empty in
+// WithWebhookPaths
+var DefaultWebhookPaths = WebhookPaths{
+	VoiceAnswer:     "/voice/answer",
+	VoiceEvent:      "/voice/event",
+	MessagesInbound: "/messages/inbound",
+	MessagesStatus:  "/messages/status",
+	Video:           "/video/events",
+	Verify:          "/verify/events",
+	RTC:             "/rtc/events",
+}
+
+// withDefaults fills empty paths from DefaultWebhookPaths
+func (p WebhookPaths) withDefaults() WebhookPaths {
+	def := DefaultWebhookPaths
+	return WebhookPaths{
+		VoiceAnswer:     orDefault(p.VoiceAnswer, def.VoiceAnswer),
+		VoiceEvent:      orDefault(p.VoiceEvent, def.VoiceEvent),
+		MessagesInbound: orDefault(p.MessagesInbound, def.MessagesInbound),
+		MessagesStatus:  orDefault(p.MessagesStatus, def.MessagesStatus),
+		Video:           orDefault(p.Video, def.Video),
+		Verify:          orDefault(p.Verify, def.Verify),
+		RTC:             orDefault(p.RTC, def.RTC),
+	}
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// DefaultMaxWebhookBytes is the default limit on the size of webhook bodies
+// the router reads. Vonage webhooks are a few kilobytes.
+const DefaultMaxWebhookBytes = 1 << 20
+
+// WebhookRouter hosts all Vonage webhooks under one http.Handler. Mount the
+// handlers from the sub-packages (messages.WebhookHandler, the video
+// webhook handler) or your own, and the router verifies webhook signatures
+// for all of them in one place.
+type WebhookRouter struct {
+	mux     *http.ServeMux
+	paths   WebhookPaths
+	prefix  string
+	logger  Logger
+	maxBody int64
+
+	verifier *WebhookVerifier
+	sink     EventSink
+	metrics  WebhookMetricsHook
+}
+
+// WebhookRouterOption is a functional option for configuring the router
+type WebhookRouterOption func(*WebhookRouter)
+
+// WithWebhookPaths overrides the default paths. Empty fields keep their
+// default.
+func WithWebhookPaths(p WebhookPaths) WebhookRouterOption {
+	return func(r *WebhookRouter) {
+		r.paths = p
+	}
+}
+
+// WithWebhookPrefix mounts every path under prefix, e.g. "/webhooks"
+func WithWebhookPrefix(prefix string) WebhookRouterOption {
+	return func(r *WebhookRouter) {
+		r.prefix = strings.TrimSuffix(prefix, "/")
+	}
+}
+
+// WithWebhookSignatureVerification rejects signed webhooks whose signature
+// does not match the credentials' signature secret with 401. Video
+// callbacks are not signed by Vonage and are not verified.
+func WithWebhookSignatureVerification(creds *Credentials) WebhookRouterOption {
+	return WithWebhookVerifier(NewWebhookVerifier(creds))
+}
+
+// WithWebhookVerifier is like WithWebhookSignatureVerification with a
+// configured verifier, e.g. one with replay protection
+func WithWebhookVerifier(v *WebhookVerifier) WebhookRouterOption {
+	return func(r *WebhookRouter) {
+		r.verifier = v
+	}
+}
+
+// WithMaxWebhookBytes limits how much of a webhook body the router reads
+// to verify or publish it (default DefaultMaxWebhookBytes). Larger bodies
+// are rejected with 413; n <= 0 removes the limit.
+func WithMaxWebhookBytes(n int64) WebhookRouterOption {
+	return func(r *WebhookRouter) {
+		r.maxBody = n
+	}
+}
+
+// WithWebhookLogger sets the logger (default: no logging)
+func WithWebhookLogger(l Logger) WebhookRouterOption {
+	return func(r *WebhookRouter) {
+		r.logger = l
+	}
+}
+
+// WithWebhookEventSink publishes every webhook that passes verification to
+// sink before its handler runs; the event Type is e.g. "voice.event", or
+// the path for webhooks mounted with Handle. Publish failures are logged
+// and the webhook is still handled. Don't also set a sink on typed
+// handlers mounted on the router, or events are published twice.
+func WithWebhookEventSink(sink EventSink) WebhookRouterOption {
+	return func(r *WebhookRouter) {
+		r.sink = sink
+	}
+}
+
+// WithWebhookMetrics reports every webhook that passes verification to
+// hook, e.g. a WebhookCollector, as InstrumentWebhook does. Don't also set
+// metrics on typed handlers mounted on the router, or webhooks are counted
+// twice.
+func WithWebhookMetrics(hook WebhookMetricsHook) WebhookRouterOption {
+	return func(r *WebhookRouter) {
+		r.metrics = hook
+	}
+}
+
+// NewWebhookRouter creates an empty webhook router
+func NewWebhookRouter(opts ...WebhookRouterOption) *WebhookRouter {
+	r := &WebhookRouter{
+		mux:     http.NewServeMux(),
+		logger:  NopLogger(),
+		maxBody: DefaultMaxWebhookBytes,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.paths = r.paths.withDefaults()
+
+	return r
+}
+
+// Paths returns the full paths, including the prefix, that webhooks are
+// mounted on
+func (r *WebhookRouter) Paths() WebhookPaths {
+	p := r.paths
+	for _, path := range []*string{&p.VoiceAnswer, &p.VoiceEvent, &p.MessagesInbound, &p.MessagesStatus, &p.Video, &p.Verify, &p.RTC} {
+		*path = r.prefix + *path
+	}
+	return p
+}
+
+// VoiceAnswer mounts the voice answer webhook, which returns the NCCO
+func (r *WebhookRouter) VoiceAnswer(h http.Handler) *WebhookRouter {
+	return r.handle(EventSourceVoice, "voice.answer", r.paths.VoiceAnswer, h, true)
+}
+
+// VoiceEvent mounts the voice event webhook
+func (r *WebhookRouter) VoiceEvent(h http.Handler) *WebhookRouter {
+	return r.handle(EventSourceVoice, "voice.event", r.paths.VoiceEvent, h, true)
+}
+
+// MessagesInbound mounts the messages inbound webhook
+func (r *WebhookRouter) MessagesInbound(h http.Handler) *WebhookRouter {
+	return r.handle(EventSourceMessages, "messages.inbound", r.paths.MessagesInbound, h, true)
+}
+
+// MessagesStatus mounts the messages status webhook
+func (r *WebhookRouter) MessagesStatus(h http.Handler) *WebhookRouter {
+	return r.handle(EventSourceMessages, "messages.status", r.paths.MessagesStatus, h, true)
+}
+
+// Video mounts the video session and archive callback
+func (r *WebhookRouter) Video(h http.Handler) *WebhookRouter {
+	return r.handle(EventSourceVideo, "video.callback", r.paths.Video, h, false)
+}
+
+// Verify mounts the verify status callback
+func (r *WebhookRouter) Verify(h http.Handler) *WebhookRouter {
+	return r.handle(EventSourceVerify, "verify.event", r.paths.Verify, h, true)
+}
+
+// RTC mounts the RTC (conversation) event webhook
+func (r *WebhookRouter) RTC(h http.Handler) *WebhookRouter {
+	return r.handle(EventSourceRTC, "rtc.event", r.paths.RTC, h, true)
+}
+
+// Handle mounts a handler on any other path under the prefix. signed
+// selects whether its signature is verified.
+func (r *WebhookRouter) Handle(path string, h http.Handler, signed bool) *WebhookRouter {
+	return r.handle("", path, path, h, signed)
+}
+
+// ServeHTTP implements http.Handler
+func (r *WebhookRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mux.ServeHTTP(w, req)
+}
+
+// handle registers h on path, wrapped in signature verification,
+// publishing and metrics as configured
+func (r *WebhookRouter) handle(source, typ, path string, h http.Handler, signed bool) *WebhookRouter {
+	if r.metrics != nil {
+		h = InstrumentWebhook(r.metrics, source, typ, h)
+	}
+	if r.sink != nil {
+		h = r.publish(source, typ, h)
+	}
+	if signed && r.verifier.Enabled() {
+		h = r.verify(h)
+	}
+	r.mux.Handle(r.prefix+path, h)
+	return r
+}
+
+// verify rejects requests whose signature does not match with 401 and
+// passes the others on with their body restored
+func (r *WebhookRouter) verify(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, ok := r.readBody(w, req)
+		if !ok {
+			return
+		}
+
+		if err := r.verifier.Verify(req.Header.Get("Authorization"), body); err != nil {
+			LoggerFromContext(req.Context(), r.logger).Warn("Rejected webhook",
+				"path", req.URL.Path,
+				"error", err,
+			)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, req)
+	})
+}
+
+// readBody reads the request body up to the router's limit. If it fails it
+// writes the response and returns false: 413 for bodies over the limit,
+// and 200 otherwise so that Vonage does not retry.
+func (r *WebhookRouter) readBody(w http.ResponseWriter, req *http.Request) ([]byte, bool) {
+	reader := req.Body
+	if r.maxBody > 0 {
+		reader = http.MaxBytesReader(w, req.Body, r.maxBody)
+	}
+	body, err := io.ReadAll(reader)
+	req.Body.Close()
+	if err == nil {
+		return body, true
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		LoggerFromContext(req.Context(), r.logger).Warn("Rejected webhook",
+			"path", req.URL.Path,
+			"error", err,
+		)
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+	LoggerFromContext(req.Context(), r.logger).Error("Failed to read webhook body",
+		"path", req.URL.Path,
+		"error", err,
+	)
+	w.WriteHeader(http.StatusOK) // Always 200 for webhooks
+	return nil, false
+}
+
+// publish publishes each request to the router's sink and passes it on
+// with its body restored. Query parameters of bodiless requests, such as
+// GET answer webhooks, are published as a JSON object.
+func (r *WebhookRouter) publish(source, typ string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, ok := r.readBody(w, req)
+		if !ok {
+			return
+		}
+
+		data := body
+		if len(data) == 0 && req.URL.RawQuery != "" {
+			params := make(map[string]string)
+			for k, v := range req.URL.Query() {
+				params[k] = v[0]
+			}
+			data, _ = json.Marshal(params)
+		}
+		PublishEvent(req.Context(), r.sink, r.logger, NewEvent(source, typ, data))
+
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, req)
+	})
+}
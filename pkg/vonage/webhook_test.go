@@ -0,0 +1,156 @@
+package vonage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSignatureSecret = "signature-secret"
+
+// signedWebhook returns a Bearer Authorization header for a webhook JWT
+// over body
+func signedWebhook(t *testing.T, jti string, iat time.Time, body []byte) string {
+	t.Helper()
+	sum := sha256.Sum256(body)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"jti":          jti,
+		"iat":          iat.Unix(),
+		"payload_hash": hex.EncodeToString(sum[:]),
+	})
+	signed, err := token.SignedString([]byte(testSignatureSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + signed
+}
+
+func newTestVerifier(opts ...WebhookVerifierOption) *WebhookVerifier {
+	return NewWebhookVerifier(&Credentials{SignatureSecret: testSignatureSecret}, opts...)
+}
+
+func TestWebhookVerifierReplay(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []WebhookVerifierOption
+		iat  time.Duration
+	}{
+		{"fresh token", nil, 0},
+		{"old token without tolerance", nil, -10 * time.Minute},
+		{"old token with long tolerance", []WebhookVerifierOption{WithTimestampTolerance(time.Hour)}, -50 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newTestVerifier(append(tt.opts, WithReplayCache(NewMemoryReplayCache()))...)
+			auth := signedWebhook(t, "jti-1", time.Now().Add(tt.iat), nil)
+
+			if err := v.Verify(auth, nil); err != nil {
+				t.Fatalf("first Verify() = %v, want nil", err)
+			}
+			for i := 0; i < 3; i++ {
+				if err := v.Verify(auth, nil); !errors.Is(err, ErrWebhookReplayed) {
+					t.Fatalf("replay %d: Verify() = %v, want ErrWebhookReplayed", i+1, err)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookVerifierDistinctIDs(t *testing.T) {
+	v := newTestVerifier(WithReplayCache(NewMemoryReplayCache()))
+	now := time.Now()
+
+	for _, jti := range []string{"jti-1", "jti-2", "jti-3"} {
+		if err := v.Verify(signedWebhook(t, jti, now, nil), nil); err != nil {
+			t.Errorf("Verify(%s) = %v, want nil", jti, err)
+		}
+	}
+}
+
+func TestWebhookVerifierTolerance(t *testing.T) {
+	v := newTestVerifier(WithTimestampTolerance(time.Minute))
+
+	if err := v.Verify(signedWebhook(t, "jti-1", time.Now().Add(-2*time.Minute), nil), nil); !errors.Is(err, ErrWebhookExpired) {
+		t.Errorf("Verify() = %v, want ErrWebhookExpired", err)
+	}
+	if err := v.Verify(signedWebhook(t, "jti-2", time.Now(), nil), nil); err != nil {
+		t.Errorf("Verify() = %v, want nil", err)
+	}
+}
+
+func TestWebhookRouterBodyLimit(t *testing.T) {
+	body := `{"uuid":"CALL-1","status":"completed"}`
+
+	tests := []struct {
+		name    string
+		opts    []WebhookRouterOption
+		body    string
+		want    int
+		handled bool
+	}{
+		{"verified within the limit", []WebhookRouterOption{WithWebhookVerifier(newTestVerifier()), WithMaxWebhookBytes(int64(len(body)))}, body, http.StatusOK, true},
+		{"verified over the limit", []WebhookRouterOption{WithWebhookVerifier(newTestVerifier()), WithMaxWebhookBytes(16)}, body, http.StatusRequestEntityTooLarge, false},
+		{"verified over the default limit", []WebhookRouterOption{WithWebhookVerifier(newTestVerifier())}, strings.Repeat(" ", DefaultMaxWebhookBytes+1), http.StatusRequestEntityTooLarge, false},
+		{"published over the limit", []WebhookRouterOption{WithWebhookEventSink(EventSinkFunc(func(context.Context, Event) error { return nil })), WithMaxWebhookBytes(16)}, body, http.StatusRequestEntityTooLarge, false},
+		{"without a limit", []WebhookRouterOption{WithWebhookVerifier(newTestVerifier()), WithMaxWebhookBytes(0)}, strings.Repeat(" ", DefaultMaxWebhookBytes+1), http.StatusOK, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handled bool
+			router := NewWebhookRouter(tt.opts...).VoiceEvent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handled = true
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, router.Paths().VoiceEvent, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", signedWebhook(t, "jti-1", time.Now(), []byte(tt.body)))
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want || handled != tt.handled {
+				t.Errorf("status = %d, handled %v, want %d, handled %v", rec.Code, handled, tt.want, tt.handled)
+			}
+		})
+	}
+}
+
+func TestVerifyWebhookJWTPayloadHash(t *testing.T) {
+	body := []byte(`{"uuid":"CALL-1","status":"completed"}`)
+	unhashed, err := jwt.New(jwt.SigningMethodHS256).SignedString([]byte(testSignatureSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		auth    string
+		body    []byte
+		wantErr bool
+	}{
+		{"matching hash", signedWebhook(t, "jti-1", time.Now(), body), body, false},
+		{"other body", signedWebhook(t, "jti-1", time.Now(), body), []byte(`{"uuid":"CALL-2"}`), true},
+		{"no hash with a body", "Bearer " + unhashed, body, true},
+		{"no hash without a body", "Bearer " + unhashed, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyWebhookJWT(tt.auth, tt.body, testSignatureSecret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyWebhookJWT() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("VerifyWebhookJWT() = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
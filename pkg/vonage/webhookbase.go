@@ -0,0 +1,159 @@
+package vonage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ========================================
+// Webhook Base URL
+// ========================================
+
+// ErrNoWebhookBase is returned when no public base URL is available, e.g.
+// no tunnel is running
+var ErrNoWebhookBase = errors.New("vonage: no webhook base URL available")
+
+// DefaultNgrokAPI is the local ngrok agent API
+const DefaultNgrokAPI = "http://127.0.0.1:4040"
+
+// WebhookBaseResolver returns the public base URL, such as
+// "https://game.example.com", that webhook paths are appended to. Resolve
+// it per call rather than once at startup, so local development with a
+// tunnel whose URL rotates needs no restart.
+type WebhookBaseResolver interface {
+	WebhookBase(ctx context.Context) (string, error)
+}
+
+// WebhookBaseFunc adapts a function, e.g. a tunnel provider callback, to
+// WebhookBaseResolver
+type WebhookBaseFunc func(ctx context.Context) (string, error)
+
+// WebhookBase calls f
+func (f WebhookBaseFunc) WebhookBase(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// StaticWebhookBase always returns baseURL, e.g. from configuration
+func StaticWebhookBase(baseURL string) WebhookBaseResolver {
+	return WebhookBaseFunc(func(context.Context) (string, error) {
+		if baseURL == "" {
+			return "", ErrNoWebhookBase
+		}
+		return strings.TrimSuffix(baseURL, "/"), nil
+	})
+}
+
+// EnvWebhookBase reads the environment variable name on every call
+func EnvWebhookBase(name string) WebhookBaseResolver {
+	return WebhookBaseFunc(func(ctx context.Context) (string, error) {
+		return StaticWebhookBase(os.Getenv(name)).WebhookBase(ctx)
+	})
+}
+
+// FileWebhookBase reads the base URL from a file on every call, e.g. one a
+// tunnel start script rewrites
+func FileWebhookBase(path string) WebhookBaseResolver {
+	return WebhookBaseFunc(func(ctx context.Context) (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrNoWebhookBase, err)
+		}
+		return StaticWebhookBase(strings.TrimSpace(string(data))).WebhookBase(ctx)
+	})
+}
+
+// NgrokWebhookBase asks the local ngrok agent at apiURL (DefaultNgrokAPI
+// if empty) for the public HTTPS URL of its first tunnel. Wrap it in
+// CachedWebhookBase to avoid a lookup per call.
+func NgrokWebhookBase(apiURL string) WebhookBaseResolver {
+	if apiURL == "" {
+		apiURL = DefaultNgrokAPI
+	}
+	httpClient := &http.Client{Timeout: 2 * time.Second}
+
+	return WebhookBaseFunc(func(ctx context.Context) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(apiURL, "/")+"/api/tunnels", nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("%w: ngrok agent: %v", ErrNoWebhookBase, err)
+		}
+		defer resp.Body.Close()
+
+		var body struct {
+			Tunnels []struct {
+				PublicURL string `json:"public_url"`
+			} `json:"tunnels"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return "", fmt.Errorf("%w: ngrok agent: %v", ErrNoWebhookBase, err)
+		}
+		for _, t := range body.Tunnels {
+			if strings.HasPrefix(t.PublicURL, "https://") {
+				return t.PublicURL, nil
+			}
+		}
+		return "", fmt.Errorf("%w: ngrok has no HTTPS tunnel", ErrNoWebhookBase)
+	})
+}
+
+// FirstWebhookBase returns the first base URL resolved by resolvers in
+// order, e.g. a tunnel falling back to configuration
+func FirstWebhookBase(resolvers ...WebhookBaseResolver) WebhookBaseResolver {
+	return WebhookBaseFunc(func(ctx context.Context) (string, error) {
+		var errs []error
+		for _, r := range resolvers {
+			base, err := r.WebhookBase(ctx)
+			if err == nil {
+				return base, nil
+			}
+			errs = append(errs, err)
+		}
+		if len(errs) == 0 {
+			return "", ErrNoWebhookBase
+		}
+		return "", errors.Join(errs...)
+	})
+}
+
+// CachedWebhookBase remembers the base URL resolved by r for ttl. Failed
+// lookups are not cached.
+func CachedWebhookBase(r WebhookBaseResolver, ttl time.Duration) WebhookBaseResolver {
+	var (
+		mu      sync.Mutex
+		base    string
+		expires time.Time
+	)
+	return WebhookBaseFunc(func(ctx context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if base != "" && time.Now().Before(expires) {
+			return base, nil
+		}
+		resolved, err := r.WebhookBase(ctx)
+		if err != nil {
+			return "", err
+		}
+		base, expires = resolved, time.Now().Add(ttl)
+		return base, nil
+	})
+}
+
+// WebhookURL joins the resolved base URL and path
+func WebhookURL(ctx context.Context, r WebhookBaseResolver, path string) (string, error) {
+	base, err := r.WebhookBase(ctx)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.TrimPrefix(path, "/"), nil
+}
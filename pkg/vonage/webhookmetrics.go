@@ -0,0 +1,215 @@
+package vonage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ========================================
+// Webhook Metrics
+// ========================================
+
+// WebhookMetricsHook observes processed webhooks. Implementations must be
+// safe for concurrent use.
+type WebhookMetricsHook interface {
+	OnWebhook(ctx context.Context, obs WebhookObservation)
+}
+
+// WebhookObservation describes one processed webhook
+type WebhookObservation struct {
+	// Source is the API the webhook came from, e.g. EventSourceVoice
+	Source string
+	// Type is the webhook type, e.g. "messages.inbound"
+	Type string
+	// Latency is how long the handler took
+	Latency time.Duration
+	// Err is the error the handler returned, if any
+	Err error
+	// Unknown is set when the body had a format the SDK does not recognize
+	Unknown bool
+}
+
+// DefaultWebhookLatencyBuckets are the histogram upper bounds (seconds)
+// used by WebhookCollector
+var DefaultWebhookLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// WebhookCollector is an in-memory WebhookMetricsHook that exposes webhook
+// counts by type, handler latency, handler errors and unknown formats in
+// the Prometheus text exposition format. Mount it on your metrics endpoint
+// or call WritePrometheus from an existing collector.
+type WebhookCollector struct {
+	buckets []float64
+
+	mu      sync.Mutex
+	events  map[webhookKey]int64
+	errors  map[webhookKey]int64
+	unknown map[string]int64
+	latency map[webhookKey]*latencyHistogram
+}
+
+type webhookKey struct {
+	source string
+	typ    string
+}
+
+type latencyHistogram struct {
+	counts []int64
+	count  int64
+	sum    float64
+}
+
+// NewWebhookCollector creates a collector using DefaultWebhookLatencyBuckets
+func NewWebhookCollector() *WebhookCollector {
+	return &WebhookCollector{
+		buckets: DefaultWebhookLatencyBuckets,
+		events:  make(map[webhookKey]int64),
+		errors:  make(map[webhookKey]int64),
+		unknown: make(map[string]int64),
+		latency: make(map[webhookKey]*latencyHistogram),
+	}
+}
+
+// OnWebhook implements WebhookMetricsHook
+func (c *WebhookCollector) OnWebhook(_ context.Context, obs WebhookObservation) {
+	key := webhookKey{source: obs.Source, typ: obs.Type}
+	seconds := obs.Latency.Seconds()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if obs.Unknown {
+		c.unknown[obs.Source]++
+		return
+	}
+	c.events[key]++
+	if obs.Err != nil {
+		c.errors[key]++
+	}
+
+	h, ok := c.latency[key]
+	if !ok {
+		h = &latencyHistogram{counts: make([]int64, len(c.buckets))}
+		c.latency[key] = h
+	}
+	for i, le := range c.buckets {
+		if seconds <= le {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// WritePrometheus writes all metrics in the Prometheus text exposition format
+func (c *WebhookCollector) WritePrometheus(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+	printf := func(format string, args ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	printf("# HELP vonage_webhook_events_total Webhooks processed by source and type.\n")
+	printf("# TYPE vonage_webhook_events_total counter\n")
+	for _, k := range sortedWebhookKeys(c.events) {
+		printf("vonage_webhook_events_total{source=%q,type=%q} %d\n", k.source, k.typ, c.events[k])
+	}
+
+	printf("# HELP vonage_webhook_handler_errors_total Webhooks whose handler returned an error.\n")
+	printf("# TYPE vonage_webhook_handler_errors_total counter\n")
+	for _, k := range sortedWebhookKeys(c.errors) {
+		printf("vonage_webhook_handler_errors_total{source=%q,type=%q} %d\n", k.source, k.typ, c.errors[k])
+	}
+
+	printf("# HELP vonage_webhook_unknown_total Webhooks with a format the SDK does not recognize.\n")
+	printf("# TYPE vonage_webhook_unknown_total counter\n")
+	sources := make([]string, 0, len(c.unknown))
+	for s := range c.unknown {
+		sources = append(sources, s)
+	}
+	sort.Strings(sources)
+	for _, s := range sources {
+		printf("vonage_webhook_unknown_total{source=%q} %d\n", s, c.unknown[s])
+	}
+
+	printf("# HELP vonage_webhook_handler_duration_seconds Webhook handler latency.\n")
+	printf("# TYPE vonage_webhook_handler_duration_seconds histogram\n")
+	for _, k := range sortedWebhookKeys(c.latency) {
+		h := c.latency[k]
+		for i, le := range c.buckets {
+			printf("vonage_webhook_handler_duration_seconds_bucket{source=%q,type=%q,le=\"%g\"} %d\n", k.source, k.typ, le, h.counts[i])
+		}
+		printf("vonage_webhook_handler_duration_seconds_bucket{source=%q,type=%q,le=\"+Inf\"} %d\n", k.source, k.typ, h.count)
+		printf("vonage_webhook_handler_duration_seconds_sum{source=%q,type=%q} %g\n", k.source, k.typ, h.sum)
+		printf("vonage_webhook_handler_duration_seconds_count{source=%q,type=%q} %d\n", k.source, k.typ, h.count)
+	}
+
+	return err
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format
+func (c *WebhookCollector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_ = c.WritePrometheus(w)
+}
+
+func sortedWebhookKeys[V any](m map[webhookKey]V) []webhookKey {
+	keys := make([]webhookKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].source != keys[j].source {
+			return keys[i].source < keys[j].source
+		}
+		return keys[i].typ < keys[j].typ
+	})
+	return keys
+}
+
+// ObserveWebhook reports a webhook to hook, if not nil
+func ObserveWebhook(ctx context.Context, hook WebhookMetricsHook, obs WebhookObservation) {
+	if hook != nil {
+		hook.OnWebhook(ctx, obs)
+	}
+}
+
+// InstrumentWebhook wraps a webhook handler written without the SDK's typed
+// handlers, e.g. a voice event handler using voice.ParseCallEvent, so each
+// request is reported to hook as a webhook of typ. Responses with a 5xx
+// status count as handler errors and 400 as an unknown format.
+func InstrumentWebhook(hook WebhookMetricsHook, source, typ string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rec, req)
+
+		obs := WebhookObservation{Source: source, Type: typ, Latency: time.Since(start)}
+		switch {
+		case rec.status == http.StatusBadRequest:
+			obs.Unknown = true
+		case rec.status >= 500:
+			obs.Err = fmt.Errorf("vonage: webhook handler returned status %d", rec.status)
+		}
+		hook.OnWebhook(req.Context(), obs)
+	})
+}
+
+// statusRecorder records the status code written to a ResponseWriter
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
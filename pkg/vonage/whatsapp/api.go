@@ -0,0 +1,20 @@
+package whatsapp
+
+import (
+	"context"
+)
+
+// ========================================
+// API Interface
+// ========================================
+
+// API is the WhatsApp template management API implemented by *Client.
+// Application code can depend on it and substitute vonagemock.WhatsApp in
+// tests.
+type API interface {
+	ListTemplates(ctx context.Context, wabaID string, opts *ListOptions) (*Page, error)
+	CreateTemplate(ctx context.Context, wabaID string, t *Template) (*CreateResponse, error)
+	DeleteTemplate(ctx context.Context, wabaID, name string) error
+}
+
+var _ API = (*Client)(nil)
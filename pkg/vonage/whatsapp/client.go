@@ -0,0 +1,221 @@
+// Package whatsapp manages the message templates of WhatsApp Business
+// Accounts (WABAs) through the WhatsApp management endpoints Vonage proxies
+// to Meta, and validates template messages against a local registry
+// before they are sent.
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+)
+
+const (
+	// BaseURL is the Vonage WhatsApp management API base URL
+	BaseURL = vonage.BaseURLREST
+
+	// basePath prefixes every WABA endpoint
+	basePath = "/v2/whatsapp-manager/wabas"
+)
+
+// ErrNotConfigured is returned when the client has no API key and secret
+var ErrNotConfigured = errors.New("whatsapp: API key and secret required")
+
+// Client handles WhatsApp template management. The API authenticates with
+// the account API key and secret.
+type Client struct {
+	provider vonage.CredentialsProvider
+
+	baseURL    string
+	httpClient *http.Client
+	middleware []vonage.Middleware
+	transport  *vonage.Transport
+	logger     vonage.Logger
+	uaSuffix   string
+	mode       vonage.Mode
+}
+
+// ClientOption is a functional option for configuring the client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL overrides the base URL (useful for testing)
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
+// WithLogger sets the logger (default: no logging)
+func WithLogger(l vonage.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithUserAgentSuffix appends an application identifier such as
+// "checkin-service/1.4" to the User-Agent
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(c *Client) {
+		c.uaSuffix = suffix
+	}
+}
+
+// WithMiddleware adds transport middleware (retries, tracing, metrics)
+func WithMiddleware(mw ...vonage.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithMode sets the client's mode (default vonage.ModeLive). In
+// vonage.ModeMock no request is sent, and in vonage.ModeDryRun only
+// reads are.
+func WithMode(m vonage.Mode) ClientOption {
+	return func(c *Client) {
+		c.mode = m
+	}
+}
+
+// WithSettings applies the HTTP client, logger, middleware, User-Agent
+// suffix and mode shared by a root vonage.Client, see
+// vonage.Client.Settings
+func WithSettings(s vonage.Settings) ClientOption {
+	return func(c *Client) {
+		if s.HTTPClient != nil {
+			c.httpClient = s.HTTPClient
+		}
+		if s.Logger != nil {
+			c.logger = s.Logger
+		}
+		c.middleware = append(c.middleware, s.Middleware...)
+		c.uaSuffix = s.UserAgentSuffix
+		c.mode = s.Mode
+	}
+}
+
+// WithCredentialsProvider fetches the API key and secret from p for every
+// request instead of using those passed to NewClient, so rotated secrets
+// are picked up
+func WithCredentialsProvider(p vonage.CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.provider = p
+	}
+}
+
+// NewClient creates a new WhatsApp template management client
+func NewClient(apiKey, apiSecret string, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:    BaseURL,
+		httpClient: &http.Client{Timeout: vonage.DefaultTimeout},
+		logger:     vonage.NopLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	provider := c.provider
+	if provider == nil {
+		provider = &vonage.Credentials{APIKey: apiKey, APISecret: apiSecret}
+	}
+	c.transport = vonage.NewTransport(c.baseURL, vonage.NewAuthChain(provider, vonage.AuthBasic),
+		vonage.WithTransportHTTPClient(c.httpClient),
+		vonage.WithMiddleware(c.middleware...),
+		vonage.WithTransportLogger(c.logger),
+		vonage.WithTransportUserAgentSuffix(c.uaSuffix),
+		vonage.WithTransportMode(c.mode),
+	)
+
+	return c
+}
+
+// NewClientFromCredentials creates a new client from Vonage credentials
+func NewClientFromCredentials(creds *vonage.Credentials, opts ...ClientOption) (*Client, error) {
+	if !creds.HasAPIKey() {
+		return nil, ErrNotConfigured
+	}
+	return NewClient(creds.APIKey, creds.APISecret, append([]ClientOption{WithMode(creds.Mode)}, opts...)...), nil
+}
+
+// ========================================
+// Templates
+// ========================================
+
+// ListTemplates returns one page of the WABA's templates
+func (c *Client) ListTemplates(ctx context.Context, wabaID string, opts *ListOptions) (*Page, error) {
+	var resp listResponse
+	if err := c.transport.Do(ctx, http.MethodGet, templatesPath(wabaID)+opts.query(), nil, &resp); err != nil {
+		return nil, err
+	}
+	page := &Page{Templates: resp.Templates}
+	if resp.Paging.Next != "" {
+		page.After = resp.Paging.Cursors.After
+	}
+	return page, nil
+}
+
+// NewPager returns a pager over the WABA's templates matching opts
+func NewPager(c API, wabaID string, opts *ListOptions, pagerOpts ...vonage.PagerOption) *vonage.Pager[Template] {
+	var base ListOptions
+	if opts != nil {
+		base = *opts
+	}
+	return vonage.NewPager(func(ctx context.Context, token string) ([]Template, string, error) {
+		o := base
+		if token != "" {
+			o.After = token
+		}
+		page, err := c.ListTemplates(ctx, wabaID, &o)
+		if err != nil {
+			return nil, "", err
+		}
+		return page.Templates, page.After, nil
+	}, pagerOpts...)
+}
+
+// CreateTemplate submits a template for Meta's review. It can be sent once
+// its status is APPROVED.
+func (c *Client) CreateTemplate(ctx context.Context, wabaID string, t *Template) (*CreateResponse, error) {
+	var resp CreateResponse
+	if err := c.transport.Do(ctx, http.MethodPost, templatesPath(wabaID), t, &resp); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Submitted WhatsApp template",
+		"wabaID", wabaID,
+		"name", t.Name,
+		"language", t.Language,
+		"status", resp.Status,
+	)
+	return &resp, nil
+}
+
+// DeleteTemplate deletes the WABA's templates named name, in every
+// language
+func (c *Client) DeleteTemplate(ctx context.Context, wabaID, name string) error {
+	path := templatesPath(wabaID) + "?" + url.Values{"name": {name}}.Encode()
+	if err := c.transport.Do(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return err
+	}
+
+	c.logger.Info("Deleted WhatsApp template",
+		"wabaID", wabaID,
+		"name", name,
+	)
+	return nil
+}
+
+// templatesPath returns the path of a WABA's templates
+func templatesPath(wabaID string) string {
+	return basePath + "/" + url.PathEscape(wabaID) + "/templates"
+}
@@ -0,0 +1,91 @@
+package whatsapp_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	vonage "github.com/vonatrigger/poc/pkg/vonage"
+	"github.com/vonatrigger/poc/pkg/vonage/messages"
+	"github.com/vonatrigger/poc/pkg/vonage/vonagemock"
+	"github.com/vonatrigger/poc/pkg/vonage/whatsapp"
+)
+
+func ExampleClient_CreateTemplate() {
+	creds, _ := vonage.NewCredentialsFromEnv()
+	client, _ := whatsapp.NewClientFromCredentials(creds)
+
+	resp, err := client.CreateTemplate(context.Background(), "104996122399160", &whatsapp.Template{
+		Name:     "appointment_reminder",
+		Language: "ja",
+		Category: whatsapp.CategoryUtility,
+		Components: []whatsapp.Component{{
+			Type: whatsapp.ComponentBody,
+			Text: "{{1}}様、{{2}}のご予約をお待ちしております。",
+			Example: &whatsapp.ComponentExample{
+				BodyText: [][]string{{"山田", "10月20日 14:00"}},
+			},
+		}},
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(resp.ID, resp.Status)
+}
+
+func ExampleRegistry() {
+	// Load the approved templates once at startup; the stub stands in for
+	// the template management API
+	api := &vonagemock.WhatsApp{
+		ListTemplatesFunc: func(ctx context.Context, wabaID string, opts *whatsapp.ListOptions) (*whatsapp.Page, error) {
+			return &whatsapp.Page{Templates: []whatsapp.Template{{
+				Name:     "order_shipped",
+				Language: "en_US",
+				Status:   whatsapp.StatusApproved,
+				Components: []whatsapp.Component{{
+					Type: whatsapp.ComponentBody,
+					Text: "Hi {{1}}, order {{2}} has shipped.",
+				}},
+			}}}, nil
+		},
+	}
+	registry := whatsapp.NewRegistry()
+	if err := registry.Load(context.Background(), api, "104996122399160"); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	client := messages.NewClient(nil,
+		messages.WithPhoneNumber("447700900000"),
+		messages.WithMode(vonage.ModeMock),
+		messages.WithTemplateValidator(registry),
+	)
+
+	send := func(params ...string) error {
+		req := &messages.SendRequest{
+			To:          "447700900001",
+			Channel:     messages.ChannelWhatsApp,
+			MessageType: messages.MessageTypeTemplate,
+			WhatsApp: &messages.WhatsAppOptions{
+				Policy: "deterministic",
+				Template: &messages.WhatsAppTemplate{
+					Name: "9b6b4fcb_da19_4a26_8fe8_78074a91b584:order_shipped",
+				},
+			},
+		}
+		for _, p := range params {
+			req.WhatsApp.Template.Parameters = append(req.WhatsApp.Template.Parameters,
+				messages.WhatsAppTemplateParam{Default: p})
+		}
+		_, err := client.Send(context.Background(), req)
+		return err
+	}
+
+	fmt.Println(send("Ana", "A-1042"))
+	err := send("Ana")
+	fmt.Println(errors.Is(err, whatsapp.ErrTemplateParams), err)
+	// Output:
+	// <nil>
+	// true whatsapp: template order_shipped (en_US) takes 2 parameters, got 1
+}
@@ -0,0 +1,159 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vonatrigger/poc/pkg/vonage/messages"
+)
+
+// ========================================
+// Template Registry
+// ========================================
+
+// DefaultLanguage is the language of template messages sent without a
+// locale
+const DefaultLanguage = "en_US"
+
+var (
+	// ErrUnknownTemplate is returned for templates missing from a registry
+	ErrUnknownTemplate = errors.New("whatsapp: unknown template")
+
+	// ErrTemplateParams is matched by *ParamCountError
+	ErrTemplateParams = errors.New("whatsapp: wrong number of template parameters")
+)
+
+// ParamCountError is returned when a template message has a different
+// number of parameters than its template's body
+type ParamCountError struct {
+	Name     string
+	Language string
+	Want     int
+	Got      int
+}
+
+func (e *ParamCountError) Error() string {
+	return fmt.Sprintf("whatsapp: template %s (%s) takes %d parameters, got %d",
+		e.Name, e.Language, e.Want, e.Got)
+}
+
+// Is makes errors.Is(err, ErrTemplateParams) true
+func (e *ParamCountError) Is(target error) bool {
+	return target == ErrTemplateParams
+}
+
+// paramPattern matches the positional parameters of a component text
+var paramPattern = regexp.MustCompile(`\{\{\s*(\d+)\s*\}\}`)
+
+// ParamCount returns the number of parameters of the template's body, the
+// highest {{n}} in its text
+func ParamCount(t *Template) int {
+	body := t.Component(ComponentBody)
+	if body == nil {
+		return 0
+	}
+	n := 0
+	for _, m := range paramPattern.FindAllStringSubmatch(body.Text, -1) {
+		i, _ := strconv.Atoi(m[1])
+		n = max(n, i)
+	}
+	return n
+}
+
+// Registry holds the templates an application sends, by name and
+// language, to validate template messages before they are sent. It
+// implements messages.TemplateValidator and is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	templates map[string]Template
+}
+
+// NewRegistry creates a registry holding templates
+func NewRegistry(templates ...Template) *Registry {
+	r := &Registry{templates: make(map[string]Template, len(templates))}
+	for _, t := range templates {
+		r.Register(t)
+	}
+	return r
+}
+
+// Register adds t, replacing a template with the same name and language
+func (r *Registry) Register(t Template) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates[registryKey(t.Name, t.Language)] = t
+}
+
+// Load registers the WABA's approved templates
+func (r *Registry) Load(ctx context.Context, c API, wabaID string) error {
+	templates, err := NewPager(c, wabaID, &ListOptions{Status: StatusApproved}).All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, t := range templates {
+		r.Register(t)
+	}
+	return nil
+}
+
+// Lookup returns the template with the name and language, DefaultLanguage
+// if empty
+func (r *Registry) Lookup(name, language string) (Template, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.templates[registryKey(name, language)]
+	return t, ok
+}
+
+// Validate returns ErrUnknownTemplate if the template is not registered,
+// or a *ParamCountError unless params matches its parameter count
+func (r *Registry) Validate(name, language string, params int) error {
+	t, ok := r.Lookup(name, language)
+	if !ok {
+		return fmt.Errorf("%w: %s (%s)", ErrUnknownTemplate, name, languageOrDefault(language))
+	}
+	if want := ParamCount(&t); params != want {
+		return &ParamCountError{Name: t.Name, Language: t.Language, Want: want, Got: params}
+	}
+	return nil
+}
+
+// ValidateTemplate validates a WhatsApp template message. Other messages
+// pass. Template names may carry the "namespace:" prefix of the Messages
+// API.
+func (r *Registry) ValidateTemplate(req *messages.SendRequest) error {
+	if req.Channel != messages.ChannelWhatsApp || req.MessageType != messages.MessageTypeTemplate {
+		return nil
+	}
+	if req.WhatsApp == nil || req.WhatsApp.Template == nil {
+		return &messages.ValidationError{Fields: []messages.FieldError{
+			{Field: "whatsapp.template", Reason: "required for template messages"},
+		}}
+	}
+	tmpl := req.WhatsApp.Template
+	name := tmpl.Name
+	if i := strings.LastIndexByte(name, ':'); i >= 0 {
+		name = name[i+1:]
+	}
+	return r.Validate(name, req.WhatsApp.Locale, len(tmpl.Parameters))
+}
+
+var _ messages.TemplateValidator = (*Registry)(nil)
+
+// registryKey returns the registry key of a template
+func registryKey(name, language string) string {
+	return name + "/" + languageOrDefault(language)
+}
+
+// languageOrDefault returns language, or DefaultLanguage if it is empty
+func languageOrDefault(language string) string {
+	if language == "" {
+		return DefaultLanguage
+	}
+	return language
+}
@@ -0,0 +1,159 @@
+package whatsapp
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Category is the category Meta approves a template for
+type Category string
+
+const (
+	CategoryMarketing      Category = "MARKETING"
+	CategoryUtility        Category = "UTILITY"
+	CategoryAuthentication Category = "AUTHENTICATION"
+)
+
+// Status is the review status of a template
+type Status string
+
+const (
+	StatusApproved Status = "APPROVED"
+	StatusPending  Status = "PENDING"
+	StatusRejected Status = "REJECTED"
+	StatusPaused   Status = "PAUSED"
+	StatusDisabled Status = "DISABLED"
+)
+
+// ComponentType is the part of a template message a component fills
+type ComponentType string
+
+const (
+	ComponentHeader  ComponentType = "HEADER"
+	ComponentBody    ComponentType = "BODY"
+	ComponentFooter  ComponentType = "FOOTER"
+	ComponentButtons ComponentType = "BUTTONS"
+)
+
+// Template is a WhatsApp message template of a WhatsApp Business Account
+// (WABA). Templates are identified by name and language; a name usually
+// has one template per language.
+type Template struct {
+	ID         string      `json:"id,omitempty"`
+	Name       string      `json:"name"`
+	Language   string      `json:"language"`
+	Category   Category    `json:"category"`
+	Status     Status      `json:"status,omitempty"`
+	Components []Component `json:"components"`
+}
+
+// Component is a header, body, footer or buttons part of a template.
+// Header and body texts hold positional parameters {{1}}, {{2}}, ...
+type Component struct {
+	Type ComponentType `json:"type"`
+	// Format is the header format: TEXT, IMAGE, VIDEO, DOCUMENT or LOCATION
+	Format  string            `json:"format,omitempty"`
+	Text    string            `json:"text,omitempty"`
+	Buttons []Button          `json:"buttons,omitempty"`
+	Example *ComponentExample `json:"example,omitempty"`
+}
+
+// Button is a template button
+type Button struct {
+	// Type is QUICK_REPLY, URL, PHONE_NUMBER or COPY_CODE
+	Type        string `json:"type"`
+	Text        string `json:"text,omitempty"`
+	URL         string `json:"url,omitempty"`
+	PhoneNumber string `json:"phone_number,omitempty"`
+}
+
+// ComponentExample holds sample parameter values, which Meta requires for
+// review of components with parameters
+type ComponentExample struct {
+	HeaderText []string   `json:"header_text,omitempty"`
+	BodyText   [][]string `json:"body_text,omitempty"`
+}
+
+// Component returns the template's component of type ct, nil if it has
+// none
+func (t *Template) Component(ct ComponentType) *Component {
+	for i := range t.Components {
+		if t.Components[i].Type == ct {
+			return &t.Components[i]
+		}
+	}
+	return nil
+}
+
+// ListOptions filters and pages the template list
+type ListOptions struct {
+	Name     string
+	Status   Status
+	Category Category
+	Language string
+	// Limit is the number of templates per page
+	Limit int
+	// After is the cursor of the page to return, "" for the first page
+	After string
+}
+
+// Page is one page of templates
+type Page struct {
+	Templates []Template
+	// After is the cursor of the next page, "" after the last page
+	After string
+}
+
+// HasNext returns true if there are more pages after this one
+func (p *Page) HasNext() bool {
+	return p.After != ""
+}
+
+// listResponse is the body of the list endpoint
+type listResponse struct {
+	Templates []Template `json:"templates"`
+	Paging    struct {
+		Cursors struct {
+			Before string `json:"before"`
+			After  string `json:"after"`
+		} `json:"cursors"`
+		Next string `json:"next"`
+	} `json:"paging"`
+}
+
+// CreateResponse is the result of submitting a template for review
+type CreateResponse struct {
+	ID       string   `json:"id"`
+	Status   Status   `json:"status"`
+	Category Category `json:"category"`
+}
+
+// query encodes list options
+func (o *ListOptions) query() string {
+	if o == nil {
+		return ""
+	}
+	q := url.Values{}
+	if o.Name != "" {
+		q.Set("name", o.Name)
+	}
+	if o.Status != "" {
+		q.Set("status", string(o.Status))
+	}
+	if o.Category != "" {
+		q.Set("category", string(o.Category))
+	}
+	if o.Language != "" {
+		q.Set("language", o.Language)
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.After != "" {
+		q.Set("after", o.After)
+	}
+	if len(q) == 0 {
+		return ""
+	}
+	return "?" + q.Encode()
+}
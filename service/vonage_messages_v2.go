@@ -28,7 +28,9 @@ func NewVonageMessagesServiceV2(cfg *config.Config, secrets VonageSecrets) (*Von
 		return nil, fmt.Errorf("failed to create credentials: %w", err)
 	}
 
-	client, err := messages.NewClientFromCredentials(creds)
+	client, err := messages.NewClientFromCredentials(creds,
+		messages.WithLogger(vonage.NewZerologLogger(log.Logger)),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create messages client: %w", err)
 	}